@@ -36,6 +36,7 @@ type Config struct {
 	Receivers    []Module     `mapstructure:"receivers"`
 	Processors   []Module     `mapstructure:"processors"`
 	Connectors   []Module     `mapstructure:"connectors"`
+	Converters   []Module     `mapstructure:"converters"`
 	Replaces     []string     `mapstructure:"replaces"`
 	Excludes     []string     `mapstructure:"excludes"`
 }
@@ -54,7 +55,7 @@ type Distribution struct {
 	DebugCompilation     bool   `mapstructure:"debug_compilation"`
 }
 
-// Module represents a receiver, exporter, processor or extension for the distribution
+// Module represents a receiver, exporter, processor, extension or config converter for the distribution
 type Module struct {
 	Name   string `mapstructure:"name"`   // if not specified, this is package part of the go mod (last part of the path)
 	Import string `mapstructure:"import"` // if not specified, this is the path part of the go mods
@@ -92,6 +93,7 @@ func (c *Config) Validate() error {
 		validateModules(c.Exporters),
 		validateModules(c.Processors),
 		validateModules(c.Connectors),
+		validateModules(c.Converters),
 	)
 }
 
@@ -155,6 +157,11 @@ func (c *Config) ParseModules() error {
 		return err
 	}
 
+	c.Converters, err = parseModules(c.Converters)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -122,6 +122,15 @@ func TestInvalidModule(t *testing.T) {
 			},
 			err: ErrInvalidGoMod,
 		},
+		{
+			cfg: Config{
+				Logger: zap.NewNop(),
+				Converters: []Module{{
+					Import: "invalid",
+				}},
+			},
+			err: ErrInvalidGoMod,
+		},
 	}
 
 	for _, test := range configurations {
@@ -121,6 +121,10 @@ type GRPCServerSettings struct {
 	// Server net.Addr config. For transport only "tcp" and "unix" are valid options.
 	NetAddr confignet.NetAddr `mapstructure:",squash"`
 
+	// SocketPermissions are the octal permissions, e.g. "0660", of the socket file when the transport
+	// is "unix". The default is to keep the permissions set by the umask.
+	SocketPermissions string `mapstructure:"socket_permissions"`
+
 	// Configures the protocol to use TLS.
 	// The default value is nil, which will cause the protocol to not use TLS.
 	TLSSetting *configtls.TLSServerSetting `mapstructure:"tls"`
@@ -149,6 +153,11 @@ type GRPCServerSettings struct {
 	// Include propagates the incoming connection's metadata to downstream consumers.
 	// Experimental: *NOTE* this option is subject to change or removal in the future.
 	IncludeMetadata bool `mapstructure:"include_metadata"`
+
+	// IncludeMetadataKeys if not empty, are the case-insensitive keys of the incoming connection's metadata
+	// propagated to the downstream consumers, only them, whether IncludeMetadata is set or not.
+	// Experimental: *NOTE* this option is subject to change or removal in the future.
+	IncludeMetadataKeys []string `mapstructure:"include_metadata_keys"`
 }
 
 // SanitizedEndpoint strips the prefix of either http:// or https:// from configgrpc.GRPCClientSettings.Endpoint.
@@ -270,6 +279,9 @@ func validateBalancerName(balancerName string) bool {
 
 // ToListener returns the net.Listener constructed from the settings.
 func (gss *GRPCServerSettings) ToListener() (net.Listener, error) {
+	if gss.NetAddr.Transport == "unix" {
+		return confignet.ListenUnix(gss.NetAddr.Endpoint, gss.SocketPermissions)
+	}
 	return gss.NetAddr.Listen()
 }
 
@@ -367,8 +379,8 @@ func (gss *GRPCServerSettings) toServerOption(host component.Host, settings comp
 
 	// Enable OpenTelemetry observability plugin.
 
-	uInterceptors = append(uInterceptors, enhanceWithClientInformation(gss.IncludeMetadata))
-	sInterceptors = append(sInterceptors, enhanceStreamWithClientInformation(gss.IncludeMetadata))
+	uInterceptors = append(uInterceptors, enhanceWithClientInformation(gss.IncludeMetadata, gss.IncludeMetadataKeys))
+	sInterceptors = append(sInterceptors, enhanceStreamWithClientInformation(gss.IncludeMetadata, gss.IncludeMetadataKeys))
 
 	opts = append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler(otelOpts...)), grpc.ChainUnaryInterceptor(uInterceptors...), grpc.ChainStreamInterceptor(sInterceptors...))
 
@@ -391,26 +403,37 @@ func getGRPCCompressionName(compressionType configcompression.CompressionType) (
 
 // enhanceWithClientInformation intercepts the incoming RPC, replacing the incoming context with one that includes
 // a client.Info, potentially with the peer's address.
-func enhanceWithClientInformation(includeMetadata bool) func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+func enhanceWithClientInformation(includeMetadata bool, metadataKeys []string) func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		return handler(contextWithClient(ctx, includeMetadata), req)
+		return handler(contextWithClient(ctx, includeMetadata, metadataKeys), req)
 	}
 }
 
-func enhanceStreamWithClientInformation(includeMetadata bool) func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+func enhanceStreamWithClientInformation(includeMetadata bool, metadataKeys []string) func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		return handler(srv, wrapServerStream(contextWithClient(ss.Context(), includeMetadata), ss))
+		return handler(srv, wrapServerStream(contextWithClient(ss.Context(), includeMetadata, metadataKeys), ss))
 	}
 }
 
 // contextWithClient attempts to add the peer address to the client.Info from the context. When no
 // client.Info exists in the context, one is created.
-func contextWithClient(ctx context.Context, includeMetadata bool) context.Context {
+func contextWithClient(ctx context.Context, includeMetadata bool, metadataKeys []string) context.Context {
 	cl := client.FromContext(ctx)
 	if p, ok := peer.FromContext(ctx); ok {
 		cl.Addr = p.Addr
 	}
-	if includeMetadata {
+	if len(metadataKeys) > 0 {
+		md, _ := metadata.FromIncomingContext(ctx)
+		selectedMD := make(map[string][]string, len(metadataKeys))
+		for _, key := range metadataKeys {
+			if vals := md.Get(key); len(vals) > 0 {
+				selectedMD[key] = append([]string(nil), vals...)
+			} else if strings.EqualFold(key, client.MetadataHostName) && len(md[":authority"]) > 0 {
+				selectedMD[key] = append([]string(nil), md[":authority"]...)
+			}
+		}
+		cl.Metadata = client.NewMetadata(selectedMD)
+	} else if includeMetadata {
 		if md, ok := metadata.FromIncomingContext(ctx); ok {
 			copiedMD := md.Copy()
 			if len(md[client.MetadataHostName]) == 0 && len(md[":authority"]) > 0 {
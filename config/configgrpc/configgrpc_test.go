@@ -661,9 +661,13 @@ func TestReceiveOnUnixDomainSocket(t *testing.T) {
 			Endpoint:  socketName,
 			Transport: "unix",
 		},
+		SocketPermissions: "0600",
 	}
 	ln, err := gss.ToListener()
 	assert.NoError(t, err)
+	fi, err := os.Stat(socketName)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
 	srv, err := gss.ToServer(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
 	assert.NoError(t, err)
 	ptraceotlp.RegisterGRPCServer(srv, &grpcTraceServer{})
@@ -691,10 +695,11 @@ func TestReceiveOnUnixDomainSocket(t *testing.T) {
 
 func TestContextWithClient(t *testing.T) {
 	testCases := []struct {
-		desc       string
-		input      context.Context
-		doMetadata bool
-		expected   client.Info
+		desc         string
+		input        context.Context
+		doMetadata   bool
+		metadataKeys []string
+		expected     client.Info
 	}{
 		{
 			desc:     "no peer information, empty client",
@@ -784,10 +789,21 @@ func TestContextWithClient(t *testing.T) {
 				Metadata: client.NewMetadata(map[string][]string{"test-metadata-key": {"test-value"}, ":authority": {"localhost:55443"}, "Host": {"localhost:55443"}}),
 			},
 		},
+		{
+			desc: "existing client with selected metadata keys",
+			input: metadata.NewIncomingContext(
+				client.NewContext(context.Background(), client.Info{}),
+				metadata.Pairs("x-tenant", "acme", "test-metadata-key", "test-value", ":authority", "localhost:55443"),
+			),
+			metadataKeys: []string{"X-Tenant", "Host", "x-missing"},
+			expected: client.Info{
+				Metadata: client.NewMetadata(map[string][]string{"X-Tenant": {"acme"}, "Host": {"localhost:55443"}}),
+			},
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
-			cl := client.FromContext(contextWithClient(tC.input, tC.doMetadata))
+			cl := client.FromContext(contextWithClient(tC.input, tC.doMetadata, tC.metadataKeys))
 			assert.Equal(t, tC.expected, cl)
 		})
 	}
@@ -810,7 +826,7 @@ func TestStreamInterceptorEnhancesClient(t *testing.T) {
 	}
 
 	// test
-	err := enhanceStreamWithClientInformation(false)(nil, stream, nil, handler)
+	err := enhanceStreamWithClientInformation(false, nil)(nil, stream, nil, handler)
 
 	// verify
 	assert.NoError(t, err)
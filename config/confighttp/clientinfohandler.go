@@ -7,6 +7,7 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"strings"
 
 	"go.opentelemetry.io/collector/client"
 )
@@ -19,18 +20,20 @@ type clientInfoHandler struct {
 
 	// include client metadata or not
 	includeMetadata bool
+	// metadataKeys if not empty, are the only headers included in the client metadata
+	metadataKeys []string
 }
 
 // ServeHTTP intercepts incoming HTTP requests, replacing the request's context with one that contains
 // a client.Info containing the client's IP address.
 func (h *clientInfoHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	req = req.WithContext(contextWithClient(req, h.includeMetadata))
+	req = req.WithContext(contextWithClient(req, h.includeMetadata, h.metadataKeys))
 	h.next.ServeHTTP(w, req)
 }
 
 // contextWithClient attempts to add the client IP address to the client.Info from the context. When no
 // client.Info exists in the context, one is created.
-func contextWithClient(req *http.Request, includeMetadata bool) context.Context {
+func contextWithClient(req *http.Request, includeMetadata bool, metadataKeys []string) context.Context {
 	cl := client.FromContext(req.Context())
 
 	ip := parseIP(req.RemoteAddr)
@@ -38,7 +41,17 @@ func contextWithClient(req *http.Request, includeMetadata bool) context.Context
 		cl.Addr = ip
 	}
 
-	if includeMetadata {
+	if len(metadataKeys) > 0 {
+		md := make(map[string][]string, len(metadataKeys))
+		for _, key := range metadataKeys {
+			if vals := req.Header.Values(key); len(vals) > 0 {
+				md[key] = append([]string(nil), vals...)
+			} else if strings.EqualFold(key, client.MetadataHostName) && req.Host != "" {
+				md[key] = []string{req.Host}
+			}
+		}
+		cl.Metadata = client.NewMetadata(md)
+	} else if includeMetadata {
 		md := req.Header.Clone()
 		if len(md.Get(client.MetadataHostName)) == 0 && req.Host != "" {
 			md.Add(client.MetadataHostName, req.Host)
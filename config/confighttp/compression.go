@@ -14,6 +14,7 @@ import (
 	"net/http"
 
 	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
 
 	"go.opentelemetry.io/collector/config/configcompression"
 )
@@ -69,13 +70,15 @@ type decompressor struct {
 	errHandler func(w http.ResponseWriter, r *http.Request, errorMsg string, statusCode int)
 	base       http.Handler
 	decoders   map[string]func(body io.ReadCloser) (io.ReadCloser, error)
+	// maxDecompressedSize is the maximum size of the decompressed bodies, not limited if not positive.
+	maxDecompressedSize int64
 }
 
 // httpContentDecompressor offloads the task of handling compressed HTTP requests
 // by identifying the compression format in the "Content-Encoding" header and re-writing
 // request body so that the handlers further in the chain can work on decompressed data.
-// It supports gzip and deflate/zlib compression.
-func httpContentDecompressor(h http.Handler, eh func(w http.ResponseWriter, r *http.Request, errorMsg string, statusCode int), decoders map[string]func(body io.ReadCloser) (io.ReadCloser, error)) http.Handler {
+// It supports gzip, zstd, lz4 and deflate/zlib compression, restricted to the encodings of the settings if any.
+func httpContentDecompressor(h http.Handler, set *DecompressionSettings, eh func(w http.ResponseWriter, r *http.Request, errorMsg string, statusCode int), decoders map[string]func(body io.ReadCloser) (io.ReadCloser, error)) (http.Handler, error) {
 	errHandler := defaultErrorHandler
 	if eh != nil {
 		errHandler = eh
@@ -110,6 +113,9 @@ func httpContentDecompressor(h http.Handler, eh func(w http.ResponseWriter, r *h
 				}
 				return zr.IOReadCloser(), nil
 			},
+			"lz4": func(body io.ReadCloser) (io.ReadCloser, error) {
+				return io.NopCloser(lz4.NewReader(body)), nil
+			},
 			"zlib": func(body io.ReadCloser) (io.ReadCloser, error) {
 				zr, err := zlib.NewReader(body)
 				if err != nil {
@@ -125,7 +131,22 @@ func httpContentDecompressor(h http.Handler, eh func(w http.ResponseWriter, r *h
 		d.decoders[key] = dec
 	}
 
-	return d
+	if set == nil {
+		return d, nil
+	}
+	d.maxDecompressedSize = set.MaxDecompressedSize
+	if len(set.Encodings) > 0 {
+		accepted := map[string]func(body io.ReadCloser) (io.ReadCloser, error){"": d.decoders[""]}
+		for _, encoding := range set.Encodings {
+			dec, ok := d.decoders[encoding]
+			if !ok {
+				return nil, fmt.Errorf("unsupported %s: %s", headerContentEncoding, encoding)
+			}
+			accepted[encoding] = dec
+		}
+		d.decoders = accepted
+	}
+	return d, nil
 }
 
 func (d *decompressor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -143,6 +164,9 @@ func (d *decompressor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		r.Header.Del("Content-Length")
 		r.ContentLength = -1
 		r.Body = newBody
+		if d.maxDecompressedSize > 0 {
+			r.Body = http.MaxBytesReader(w, newBody, d.maxDecompressedSize)
+		}
 	}
 	d.base.ServeHTTP(w, r)
 }
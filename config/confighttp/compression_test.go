@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"compress/zlib"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/golang/snappy"
 	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -133,7 +135,9 @@ func TestHTTPCustomDecompression(t *testing.T) {
 			return io.NopCloser(strings.NewReader("decompressed body")), nil
 		},
 	}
-	srv := httptest.NewServer(httpContentDecompressor(handler, defaultErrorHandler, decoders))
+	decompressor, err := httpContentDecompressor(handler, nil, defaultErrorHandler, decoders)
+	require.NoError(t, err)
+	srv := httptest.NewServer(decompressor)
 
 	t.Cleanup(srv.Close)
 
@@ -190,6 +194,12 @@ func TestHTTPContentDecompressionHandler(t *testing.T) {
 			reqBody:  compressZstd(t, testBody),
 			respCode: http.StatusOK,
 		},
+		{
+			name:     "ValidLz4",
+			encoding: "lz4",
+			reqBody:  compressLz4(t, testBody),
+			respCode: http.StatusOK,
+		},
 		{
 			name:     "InvalidDeflate",
 			encoding: "deflate",
@@ -218,6 +228,13 @@ func TestHTTPContentDecompressionHandler(t *testing.T) {
 			respCode: http.StatusBadRequest,
 			respBody: "invalid input: magic number mismatch",
 		},
+		{
+			name:     "InvalidLz4",
+			encoding: "lz4",
+			reqBody:  bytes.NewBuffer(testBody),
+			respCode: http.StatusBadRequest,
+			respBody: "lz4: bad magic number",
+		},
 		{
 			name:     "UnsupportedCompression",
 			encoding: "nosuchcompression",
@@ -228,7 +245,7 @@ func TestHTTPContentDecompressionHandler(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			srv := httptest.NewServer(httpContentDecompressor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			decompressor, err := httpContentDecompressor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				body, err := io.ReadAll(r.Body)
 				if err != nil {
 					w.WriteHeader(http.StatusBadRequest)
@@ -239,7 +256,9 @@ func TestHTTPContentDecompressionHandler(t *testing.T) {
 				require.NoError(t, err, "failed to read request body: %v", err)
 				assert.EqualValues(t, testBody, string(body))
 				w.WriteHeader(http.StatusOK)
-			}), defaultErrorHandler, noDecoders))
+			}), nil, defaultErrorHandler, noDecoders)
+			require.NoError(t, err)
+			srv := httptest.NewServer(decompressor)
 			t.Cleanup(srv.Close)
 
 			req, err := http.NewRequest(http.MethodGet, srv.URL, tt.reqBody)
@@ -260,6 +279,107 @@ func TestHTTPContentDecompressionHandler(t *testing.T) {
 	}
 }
 
+func TestHTTPContentDecompressionSettings(t *testing.T) {
+	testBody := bytes.Repeat([]byte("uncompressed_text"), 100)
+	tests := []struct {
+		name     string
+		settings *DecompressionSettings
+		encoding string
+		reqBody  *bytes.Buffer
+		respCode int
+		respBody string
+	}{
+		{
+			name:     "AcceptedEncoding",
+			settings: &DecompressionSettings{Encodings: []string{"gzip"}},
+			encoding: "gzip",
+			reqBody:  compressGzip(t, testBody),
+			respCode: http.StatusOK,
+		},
+		{
+			name:     "NoCompression",
+			settings: &DecompressionSettings{Encodings: []string{"gzip"}},
+			encoding: "",
+			reqBody:  bytes.NewBuffer(testBody),
+			respCode: http.StatusOK,
+		},
+		{
+			name:     "RefusedEncoding",
+			settings: &DecompressionSettings{Encodings: []string{"gzip"}},
+			encoding: "zstd",
+			reqBody:  compressZstd(t, testBody),
+			respCode: http.StatusBadRequest,
+			respBody: "unsupported Content-Encoding: zstd\n",
+		},
+		{
+			name:     "DecompressedSizeWithinLimit",
+			settings: &DecompressionSettings{MaxDecompressedSize: int64(len(testBody))},
+			encoding: "zstd",
+			reqBody:  compressZstd(t, testBody),
+			respCode: http.StatusOK,
+		},
+		{
+			name:     "DecompressedSizeOverLimit",
+			settings: &DecompressionSettings{MaxDecompressedSize: int64(len(testBody)) - 1},
+			encoding: "zstd",
+			reqBody:  compressZstd(t, testBody),
+			respCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:     "Lz4DecompressedSizeWithinLimit",
+			settings: &DecompressionSettings{Encodings: []string{"lz4"}, MaxDecompressedSize: int64(len(testBody))},
+			encoding: "lz4",
+			reqBody:  compressLz4(t, testBody),
+			respCode: http.StatusOK,
+		},
+		{
+			name:     "Lz4DecompressedSizeOverLimit",
+			settings: &DecompressionSettings{Encodings: []string{"lz4"}, MaxDecompressedSize: int64(len(testBody)) - 1},
+			encoding: "lz4",
+			reqBody:  compressLz4(t, testBody),
+			respCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decompressor, err := httpContentDecompressor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+					return
+				}
+				require.NoError(t, err, "failed to read request body: %v", err)
+				assert.EqualValues(t, testBody, body)
+				w.WriteHeader(http.StatusOK)
+			}), tt.settings, defaultErrorHandler, nil)
+			require.NoError(t, err)
+			srv := httptest.NewServer(decompressor)
+			t.Cleanup(srv.Close)
+
+			req, err := http.NewRequest(http.MethodPost, srv.URL, tt.reqBody)
+			require.NoError(t, err, "failed to create request to test handler")
+			req.Header.Set("Content-Encoding", tt.encoding)
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+
+			assert.Equal(t, tt.respCode, res.StatusCode, "test handler returned unexpected status code ")
+			if tt.respBody != "" {
+				assert.Equal(t, tt.respBody, string(body))
+			}
+		})
+	}
+}
+
+func TestHTTPContentDecompressionUnsupportedSetting(t *testing.T) {
+	_, err := httpContentDecompressor(http.NotFoundHandler(), &DecompressionSettings{Encodings: []string{"lzw"}}, defaultErrorHandler, nil)
+	assert.EqualError(t, err, "unsupported Content-Encoding: lzw")
+}
+
 func TestHTTPContentCompressionRequestWithNilBody(t *testing.T) {
 	compressedGzipBody := compressGzip(t, []byte{})
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -370,3 +490,12 @@ func compressZstd(t testing.TB, body []byte) *bytes.Buffer {
 	require.NoError(t, zw.Close())
 	return &buf
 }
+
+func compressLz4(t testing.TB, body []byte) *bytes.Buffer {
+	var buf bytes.Buffer
+	lw := lz4.NewWriter(&buf)
+	_, err := lw.Write(body)
+	require.NoError(t, err)
+	require.NoError(t, lw.Close())
+	return &buf
+}
@@ -6,6 +6,7 @@ package confighttp // import "go.opentelemetry.io/collector/config/confighttp"
 import (
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -19,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/config/internal"
@@ -212,9 +214,17 @@ func (interceptor *headerRoundTripper) RoundTrip(req *http.Request) (*http.Respo
 
 // HTTPServerSettings defines settings for creating an HTTP server.
 type HTTPServerSettings struct {
-	// Endpoint configures the listening address for the server.
+	// Endpoint configures the listening address for the server, or the path of the socket when the
+	// transport is "unix".
 	Endpoint string `mapstructure:"endpoint"`
 
+	// Transport to listen on, "tcp" or "unix". The default is "tcp".
+	Transport string `mapstructure:"transport"`
+
+	// SocketPermissions are the octal permissions, e.g. "0660", of the socket file when the transport
+	// is "unix". The default is to keep the permissions set by the umask.
+	SocketPermissions string `mapstructure:"socket_permissions"`
+
 	// TLSSetting struct exposes TLS client configuration.
 	TLSSetting *configtls.TLSServerSetting `mapstructure:"tls"`
 
@@ -227,10 +237,19 @@ type HTTPServerSettings struct {
 	// MaxRequestBodySize sets the maximum request body size in bytes
 	MaxRequestBodySize int64 `mapstructure:"max_request_body_size"`
 
+	// Decompression configures how the compressed request bodies are decompressed. If nil, all the
+	// supported content encodings are accepted and the decompressed bodies are not limited.
+	Decompression *DecompressionSettings `mapstructure:"decompression"`
+
 	// IncludeMetadata propagates the client metadata from the incoming requests to the downstream consumers
 	// Experimental: *NOTE* this option is subject to change or removal in the future.
 	IncludeMetadata bool `mapstructure:"include_metadata"`
 
+	// IncludeMetadataKeys if not empty, are the case-insensitive keys of the headers propagated as client
+	// metadata to the downstream consumers, only them, whether IncludeMetadata is set or not.
+	// Experimental: *NOTE* this option is subject to change or removal in the future.
+	IncludeMetadataKeys []string `mapstructure:"include_metadata_keys"`
+
 	// Additional headers attached to each HTTP response sent to the client.
 	// Header values are opaque since they may be sensitive.
 	ResponseHeaders map[string]configopaque.String `mapstructure:"response_headers"`
@@ -238,7 +257,16 @@ type HTTPServerSettings struct {
 
 // ToListener creates a net.Listener.
 func (hss *HTTPServerSettings) ToListener() (net.Listener, error) {
-	listener, err := net.Listen("tcp", hss.Endpoint)
+	var listener net.Listener
+	var err error
+	switch hss.Transport {
+	case "", "tcp":
+		listener, err = net.Listen("tcp", hss.Endpoint)
+	case "unix":
+		listener, err = confignet.ListenUnix(hss.Endpoint, hss.SocketPermissions)
+	default:
+		return nil, fmt.Errorf("unsupported transport %q", hss.Transport)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -294,7 +322,10 @@ func (hss *HTTPServerSettings) ToServer(host component.Host, settings component.
 		o(serverOpts)
 	}
 
-	handler = httpContentDecompressor(handler, serverOpts.errHandler, serverOpts.decoders)
+	handler, err := httpContentDecompressor(handler, hss.Decompression, serverOpts.errHandler, serverOpts.decoders)
+	if err != nil {
+		return nil, err
+	}
 
 	if hss.MaxRequestBodySize > 0 {
 		handler = maxRequestBodySizeInterceptor(handler, hss.MaxRequestBodySize)
@@ -341,6 +372,7 @@ func (hss *HTTPServerSettings) ToServer(host component.Host, settings component.
 	handler = &clientInfoHandler{
 		next:            handler,
 		includeMetadata: hss.IncludeMetadata,
+		metadataKeys:    hss.IncludeMetadataKeys,
 	}
 
 	return &http.Server{
@@ -360,6 +392,18 @@ func responseHeadersHandler(handler http.Handler, headers map[string]configopaqu
 	})
 }
 
+// DecompressionSettings configures how the compressed request bodies are decompressed.
+type DecompressionSettings struct {
+	// Encodings if not empty, are the only content encodings accepted, e.g. "gzip" or "zstd". The
+	// requests with another content encoding are refused. The uncompressed requests are always accepted.
+	Encodings []string `mapstructure:"encodings"`
+
+	// MaxDecompressedSize if positive, is the maximum size in bytes of the decompressed request bodies,
+	// so a small compressed request cannot exhaust the memory when decompressed. Reading past it fails
+	// with an *http.MaxBytesError.
+	MaxDecompressedSize int64 `mapstructure:"max_decompressed_size"`
+}
+
 // CORSSettings configures a receiver for HTTP cross-origin resource sharing (CORS).
 // See the underlying https://github.com/rs/cors package for details.
 type CORSSettings struct {
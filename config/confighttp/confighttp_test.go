@@ -12,7 +12,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -430,6 +432,13 @@ func TestHTTPServerSettingsError(t *testing.T) {
 				},
 			},
 		},
+		{
+			err: `^unsupported transport "udp"`,
+			settings: HTTPServerSettings{
+				Endpoint:  "localhost:0",
+				Transport: "udp",
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.err, func(t *testing.T) {
@@ -439,6 +448,50 @@ func TestHTTPServerSettingsError(t *testing.T) {
 	}
 }
 
+func TestHttpReceptionOnUnixDomainSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	hss := &HTTPServerSettings{
+		Endpoint:          socketPath,
+		Transport:         "unix",
+		SocketPermissions: "0660",
+	}
+	ln, err := hss.ToListener()
+	require.NoError(t, err)
+	fi, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0660), fi.Mode().Perm())
+
+	s, err := hss.ToServer(
+		componenttest.NewNopHost(),
+		componenttest.NewNopTelemetrySettings(),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, errWrite := fmt.Fprint(w, "test")
+			assert.NoError(t, errWrite)
+		}))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(ln)
+	}()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	resp, err := client.Get("http://localhost/")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "test", string(body))
+	require.NoError(t, resp.Body.Close())
+	require.NoError(t, s.Close())
+}
+
 func TestHTTPServerWarning(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -991,10 +1044,11 @@ func TestHttpClientHeaders(t *testing.T) {
 
 func TestContextWithClient(t *testing.T) {
 	testCases := []struct {
-		desc       string
-		input      *http.Request
-		doMetadata bool
-		expected   client.Info
+		desc         string
+		input        *http.Request
+		doMetadata   bool
+		metadataKeys []string
+		expected     client.Info
 	}{
 		{
 			desc:     "request without client IP or headers",
@@ -1041,10 +1095,21 @@ func TestContextWithClient(t *testing.T) {
 				Metadata: client.NewMetadata(map[string][]string{"x-test-header": {"test-value"}, "Host": {"localhost:55443"}}),
 			},
 		},
+		{
+			desc: "request with selected client headers",
+			input: &http.Request{
+				Header: map[string][]string{"X-Tenant": {"acme"}, "X-Other": {"other-value"}},
+				Host:   "localhost:55443",
+			},
+			metadataKeys: []string{"x-tenant", "host", "x-missing"},
+			expected: client.Info{
+				Metadata: client.NewMetadata(map[string][]string{"x-tenant": {"acme"}, "host": {"localhost:55443"}}),
+			},
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
-			ctx := contextWithClient(tC.input, tC.doMetadata)
+			ctx := contextWithClient(tC.input, tC.doMetadata, tC.metadataKeys)
 			assert.Equal(t, tC.expected, client.FromContext(ctx))
 		})
 	}
@@ -5,12 +5,14 @@ go 1.20
 require (
 	github.com/golang/snappy v0.0.4
 	github.com/klauspost/compress v1.17.2
+	github.com/pierrec/lz4/v4 v4.1.18
 	github.com/rs/cors v1.10.1
 	github.com/stretchr/testify v1.8.4
 	go.opentelemetry.io/collector v0.88.0
 	go.opentelemetry.io/collector/component v0.88.0
 	go.opentelemetry.io/collector/config/configauth v0.88.0
 	go.opentelemetry.io/collector/config/configcompression v0.88.0
+	go.opentelemetry.io/collector/config/confignet v0.88.0
 	go.opentelemetry.io/collector/config/configopaque v0.88.0
 	go.opentelemetry.io/collector/config/configtelemetry v0.88.0
 	go.opentelemetry.io/collector/config/configtls v0.88.0
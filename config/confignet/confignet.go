@@ -4,7 +4,11 @@
 package confignet // import "go.opentelemetry.io/collector/config/confignet"
 
 import (
+	"fmt"
+	"io/fs"
 	"net"
+	"os"
+	"strconv"
 )
 
 // NetAddr represents a network endpoint address.
@@ -50,3 +54,41 @@ func (na *TCPAddr) Dial() (net.Conn, error) {
 func (na *TCPAddr) Listen() (net.Listener, error) {
 	return net.Listen("tcp", na.Endpoint)
 }
+
+// ListenUnix listens on the Unix domain socket at the given path. The socket left at the path by a
+// process that did not close its listener, if any, is removed first, unless a process still listens
+// on it. The permissions of the socket
+// file are set to the given octal permissions, e.g. "0660", if not empty, otherwise they are set by
+// the umask of the process.
+func ListenUnix(path string, permissions string) (net.Listener, error) {
+	var mode fs.FileMode
+	if permissions != "" {
+		perm, err := strconv.ParseUint(permissions, 8, 32)
+		if err != nil || perm > uint64(fs.ModePerm) {
+			return nil, fmt.Errorf("invalid socket permissions %q", permissions)
+		}
+		mode = fs.FileMode(perm)
+	}
+
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&fs.ModeSocket != 0 {
+		if conn, dialErr := net.Dial("unix", path); dialErr == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("the socket %q is in use", path)
+		}
+		if err = os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove the stale socket: %w", err)
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if permissions != "" {
+		if err = os.Chmod(path, mode); err != nil {
+			_ = ln.Close()
+			return nil, fmt.Errorf("failed to set the socket permissions: %w", err)
+		}
+	}
+	return ln, nil
+}
@@ -4,10 +4,16 @@
 package confignet
 
 import (
+	"fmt"
+	"io/fs"
 	"net"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNetAddr(t *testing.T) {
@@ -77,3 +83,49 @@ func TestTcpAddr(t *testing.T) {
 	<-done
 	assert.NoError(t, ln.Close())
 }
+
+func TestListenUnix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	path := filepath.Join(t.TempDir(), "test.sock")
+
+	ln, err := ListenUnix(path, "0600")
+	require.NoError(t, err)
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0600), fi.Mode().Perm())
+
+	// The socket is not removed while in use.
+	_, err = ListenUnix(path, "")
+	assert.EqualError(t, err, fmt.Sprintf("the socket %q is in use", path))
+
+	// Simulate a socket left by a process that exited without closing its listener.
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, ln.Close())
+	ln, err = ListenUnix(path, "0660")
+	require.NoError(t, err)
+	fi, err = os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0660), fi.Mode().Perm())
+	require.NoError(t, ln.Close())
+}
+
+func TestListenUnixErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	dir := t.TempDir()
+
+	_, err := ListenUnix(filepath.Join(dir, "test.sock"), "rw-rw----")
+	assert.EqualError(t, err, `invalid socket permissions "rw-rw----"`)
+	_, err = ListenUnix(filepath.Join(dir, "test.sock"), "01777")
+	assert.EqualError(t, err, `invalid socket permissions "01777"`)
+
+	// The files that are not sockets are never removed.
+	path := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+	_, err = ListenUnix(path, "")
+	assert.Error(t, err)
+	assert.FileExists(t, path)
+}
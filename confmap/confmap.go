@@ -7,6 +7,7 @@ import (
 	"encoding"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/knadh/koanf/maps"
 	"github.com/knadh/koanf/providers/confmap"
@@ -19,6 +20,9 @@ import (
 const (
 	// KeyDelimiter is used as the default key delimiter in the default koanf instance.
 	KeyDelimiter = "::"
+
+	// RedactedValue replaces the opaque values in the Conf returned by Redacted.
+	RedactedValue = "[REDACTED]"
 )
 
 // New creates a new empty confmap.Conf instance.
@@ -38,6 +42,12 @@ func NewFromStringMap(data map[string]any) *Conf {
 // The confmap.Conf can be unmarshalled into the Collector's config using the "service" package.
 type Conf struct {
 	k *koanf.Koanf
+	// opaqueKeys are the keys of the values expanded from opaque retrieved values, see WithRetrievedOpaque.
+	opaqueKeys map[string]struct{}
+	// sources are the URIs of the configs the values were retrieved from, per key, set by the Resolver.
+	sources map[string]string
+	// strictTypes disallows the implicit conversions between types on Unmarshal, see ResolverSettings.StrictTypes.
+	strictTypes bool
 }
 
 // AllKeys returns all keys holding a value, regardless of where they are set.
@@ -112,25 +122,104 @@ func (l *Conf) IsSet(key string) bool {
 // Merge merges the input given configuration into the existing config.
 // Note that the given map may be modified.
 func (l *Conf) Merge(in *Conf) error {
+	for k := range in.opaqueKeys {
+		if l.opaqueKeys == nil {
+			l.opaqueKeys = make(map[string]struct{}, len(in.opaqueKeys))
+		}
+		l.opaqueKeys[k] = struct{}{}
+	}
+	for k, uri := range in.sources {
+		if l.sources == nil {
+			l.sources = make(map[string]string, len(in.sources))
+		}
+		l.sources[k] = uri
+	}
 	return l.k.Merge(in.k)
 }
 
+// mergeAppend merges the input given configuration into the existing config like Merge, except the lists of
+// the input are appended to the existing lists instead of replacing them.
+func (l *Conf) mergeAppend(in *Conf) error {
+	data := in.k.All()
+	for k, v := range data {
+		inList, ok := v.([]any)
+		if !ok {
+			continue
+		}
+		if list, ok := l.Get(k).([]any); ok {
+			data[k] = append(append(make([]any, 0, len(list)+len(inList)), list...), inList...)
+		}
+	}
+	appended := NewFromStringMap(maps.Unflatten(data, KeyDelimiter))
+	appended.opaqueKeys = in.opaqueKeys
+	appended.sources = in.sources
+	return l.Merge(appended)
+}
+
 // Sub returns new Conf instance representing a sub-config of this instance.
 // It returns an error is the sub-config is not a map[string]any (use Get()), and an empty Map if none exists.
 func (l *Conf) Sub(key string) (*Conf, error) {
 	// Code inspired by the koanf "Cut" func, but returns an error instead of empty map for unsupported sub-config type.
 	data := l.Get(key)
 	if data == nil {
-		return New(), nil
+		sub := New()
+		sub.strictTypes = l.strictTypes
+		return sub, nil
 	}
 
 	if v, ok := data.(map[string]any); ok {
-		return NewFromStringMap(v), nil
+		sub := NewFromStringMap(v)
+		sub.strictTypes = l.strictTypes
+		return sub, nil
 	}
 
 	return nil, fmt.Errorf("unexpected sub-config value kind for key:%s value:%v kind:%v)", key, data, reflect.TypeOf(data).Kind())
 }
 
+// Redacted returns a copy of the Conf with the values expanded from opaque retrieved values, e.g. secrets,
+// replaced by "[REDACTED]", see WithRetrievedOpaque. The values embedding an opaque value are redacted
+// entirely, as are the maps and slices expanded from one.
+func (l *Conf) Redacted() *Conf {
+	data := l.k.All()
+	for k := range data {
+		if l.IsOpaque(k) {
+			data[k] = RedactedValue
+		}
+	}
+	return NewFromStringMap(maps.Unflatten(data, KeyDelimiter))
+}
+
+// IsOpaque returns whether the value of the key, or of any of its parents, was expanded from an opaque value,
+// see WithRetrievedOpaque.
+func (l *Conf) IsOpaque(key string) bool {
+	for k := range l.opaqueKeys {
+		if key == k || strings.HasPrefix(key, k+KeyDelimiter) {
+			return true
+		}
+	}
+	return false
+}
+
+// Source returns the URI of the config the value of the key, or of its closest parent, was retrieved from
+// by the Resolver, or the empty string if none, e.g. for the values set after the configuration is resolved.
+func (l *Conf) Source(key string) string {
+	for ; key != ""; key = parentKey(key) {
+		if uri, ok := l.sources[key]; ok {
+			return uri
+		}
+	}
+	return ""
+}
+
+// parentKey returns the key of the parent of the given key, or the empty string if none.
+func parentKey(key string) string {
+	idx := strings.LastIndex(key, KeyDelimiter)
+	if idx < 0 {
+		return ""
+	}
+	return key[:idx]
+}
+
 // ToStringMap creates a map[string]any from a Parser.
 func (l *Conf) ToStringMap() map[string]any {
 	return maps.Unflatten(l.k.All(), KeyDelimiter)
@@ -143,12 +232,13 @@ func (l *Conf) ToStringMap() map[string]any {
 // uniqueness of component IDs (see mapKeyStringToMapKeyTextUnmarshalerHookFunc).
 // Decodes time.Duration from strings. Allows custom unmarshaling for structs implementing
 // encoding.TextUnmarshaler. Allows custom unmarshaling for structs implementing confmap.Unmarshaler.
+// Converts the values weakly between types, e.g. the string "true" to a bool, unless the Conf has strict types.
 func decodeConfig(m *Conf, result any, errorUnused bool) error {
 	dc := &mapstructure.DecoderConfig{
 		ErrorUnused:      errorUnused,
 		Result:           result,
 		TagName:          "mapstructure",
-		WeaklyTypedInput: true,
+		WeaklyTypedInput: !m.strictTypes,
 		MatchName:        caseSensitiveMatchName,
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
 			expandNilStructPointersHookFunc(),
@@ -156,7 +246,7 @@ func decodeConfig(m *Conf, result any, errorUnused bool) error {
 			mapKeyStringToMapKeyTextUnmarshalerHookFunc(),
 			mapstructure.StringToTimeDurationHookFunc(),
 			mapstructure.TextUnmarshallerHookFunc(),
-			unmarshalerHookFunc(result),
+			unmarshalerHookFunc(result, m.strictTypes),
 			zeroSliceHookFunc(),
 		),
 	}
@@ -262,8 +352,8 @@ func mapKeyStringToMapKeyTextUnmarshalerHookFunc() mapstructure.DecodeHookFuncTy
 }
 
 // Provides a mechanism for individual structs to define their own unmarshal logic,
-// by implementing the Unmarshaler interface.
-func unmarshalerHookFunc(result any) mapstructure.DecodeHookFuncValue {
+// by implementing the Unmarshaler interface. The Conf given to the Unmarshaler keeps the given strict types.
+func unmarshalerHookFunc(result any, strictTypes bool) mapstructure.DecodeHookFuncValue {
 	return func(from reflect.Value, to reflect.Value) (any, error) {
 		if !to.CanAddr() {
 			return from.Interface(), nil
@@ -289,7 +379,9 @@ func unmarshalerHookFunc(result any) mapstructure.DecodeHookFuncValue {
 			unmarshaler = reflect.New(to.Type()).Interface().(Unmarshaler)
 		}
 
-		if err := unmarshaler.Unmarshal(NewFromStringMap(from.Interface().(map[string]any))); err != nil {
+		conf := NewFromStringMap(from.Interface().(map[string]any))
+		conf.strictTypes = strictTypes
+		if err := unmarshaler.Unmarshal(conf); err != nil {
 			return nil, err
 		}
 
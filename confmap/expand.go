@@ -25,55 +25,73 @@ var (
 	errTooManyRecursiveExpansions = errors.New("too many recursive expansions")
 )
 
-func (mr *Resolver) expandValueRecursively(ctx context.Context, value any) (any, error) {
+// expandedValue is the result of the expansion of a value.
+type expandedValue struct {
+	// value is the expanded value.
+	value any
+	// changed is whether any URI was expanded.
+	changed bool
+	// opaque is whether any URI expanded was retrieved as opaque, see WithRetrievedOpaque.
+	opaque bool
+}
+
+// expandValueRecursively returns the value with all the URIs expanded, and whether any of them was
+// retrieved as opaque.
+func (mr *Resolver) expandValueRecursively(ctx context.Context, value any) (any, bool, error) {
+	opaque := false
 	for i := 0; i < 100; i++ {
-		val, changed, err := mr.expandValue(ctx, value)
+		exp, err := mr.expandValue(ctx, value)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
-		if !changed {
-			return val, nil
+		opaque = opaque || exp.opaque
+		if !exp.changed {
+			return exp.value, opaque, nil
 		}
-		value = val
+		value = exp.value
 	}
-	return nil, errTooManyRecursiveExpansions
+	return nil, false, errTooManyRecursiveExpansions
 }
 
-func (mr *Resolver) expandValue(ctx context.Context, value any) (any, bool, error) {
+func (mr *Resolver) expandValue(ctx context.Context, value any) (expandedValue, error) {
 	switch v := value.(type) {
 	case string:
 		if !strings.Contains(v, "${") || !strings.Contains(v, "}") {
 			// No URIs to expand.
-			return value, false, nil
+			return expandedValue{value: value}, nil
 		}
 		// Embedded or nested URIs.
 		return mr.findAndExpandURI(ctx, v)
 	case []any:
 		nslice := make([]any, 0, len(v))
-		nchanged := false
+		nexp := expandedValue{}
 		for _, vint := range v {
-			val, changed, err := mr.expandValue(ctx, vint)
+			exp, err := mr.expandValue(ctx, vint)
 			if err != nil {
-				return nil, false, err
+				return expandedValue{}, err
 			}
-			nslice = append(nslice, val)
-			nchanged = nchanged || changed
+			nslice = append(nslice, exp.value)
+			nexp.changed = nexp.changed || exp.changed
+			nexp.opaque = nexp.opaque || exp.opaque
 		}
-		return nslice, nchanged, nil
+		nexp.value = nslice
+		return nexp, nil
 	case map[string]any:
 		nmap := map[string]any{}
-		nchanged := false
+		nexp := expandedValue{}
 		for mk, mv := range v {
-			val, changed, err := mr.expandValue(ctx, mv)
+			exp, err := mr.expandValue(ctx, mv)
 			if err != nil {
-				return nil, false, err
+				return expandedValue{}, err
 			}
-			nmap[mk] = val
-			nchanged = nchanged || changed
+			nmap[mk] = exp.value
+			nexp.changed = nexp.changed || exp.changed
+			nexp.opaque = nexp.opaque || exp.opaque
 		}
-		return nmap, nchanged, nil
+		nexp.value = nmap
+		return nexp, nil
 	}
-	return value, false, nil
+	return expandedValue{value: value}, nil
 }
 
 // findURI attempts to find the first expandable URI in input. It returns an expandable
@@ -98,26 +116,27 @@ func findURI(input string) string {
 
 // findAndExpandURI attempts to find and expand the first occurrence of an expandable URI in input. If an expandable URI is found it
 // returns the input with the URI expanded, true and nil. Otherwise, it returns the unchanged input, false and the expanding error.
-func (mr *Resolver) findAndExpandURI(ctx context.Context, input string) (any, bool, error) {
+func (mr *Resolver) findAndExpandURI(ctx context.Context, input string) (expandedValue, error) {
 	uri := findURI(input)
 	if uri == "" {
 		// No URI found, return.
-		return input, false, nil
+		return expandedValue{value: input}, nil
 	}
 	if uri == input {
 		// If the value is a single URI, then the return value can be anything.
 		// This is the case `foo: ${file:some_extra_config.yml}`.
 		return mr.expandURI(ctx, input)
 	}
-	expanded, changed, err := mr.expandURI(ctx, uri)
+	exp, err := mr.expandURI(ctx, uri)
 	if err != nil {
-		return input, false, err
+		return expandedValue{value: input}, err
 	}
-	repl, err := toString(uri, expanded)
+	repl, err := toString(uri, exp.value)
 	if err != nil {
-		return input, false, err
+		return expandedValue{value: input}, err
 	}
-	return strings.ReplaceAll(input, uri, repl), changed, err
+	exp.value = strings.ReplaceAll(input, uri, repl)
+	return exp, nil
 }
 
 // toString attempts to convert input to a string.
@@ -138,21 +157,22 @@ func toString(strURI string, input any) (string, error) {
 	}
 }
 
-func (mr *Resolver) expandURI(ctx context.Context, uri string) (any, bool, error) {
+func (mr *Resolver) expandURI(ctx context.Context, uri string) (expandedValue, error) {
 	lURI, err := newLocation(uri[2 : len(uri)-1])
 	if err != nil {
-		return nil, false, err
+		return expandedValue{}, err
 	}
 	if strings.Contains(lURI.opaqueValue, "$") {
-		return nil, false, fmt.Errorf("the uri %q contains unsupported characters ('$')", lURI.asString())
+		return expandedValue{}, fmt.Errorf("the uri %q contains unsupported characters ('$')", lURI.asString())
 	}
 	ret, err := mr.retrieveValue(ctx, lURI)
 	if err != nil {
-		return nil, false, err
+		return expandedValue{}, err
 	}
 	mr.closers = append(mr.closers, ret.Close)
+	mr.applied = append(mr.applied, ret.Applied)
 	val, err := ret.AsRaw()
-	return val, true, err
+	return expandedValue{value: val, changed: true, opaque: ret.IsOpaque()}, err
 }
 
 type location struct {
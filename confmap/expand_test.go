@@ -459,3 +459,46 @@ func TestResolverExpandStringValueInvalidReturnValue(t *testing.T) {
 	_, err = resolver.Resolve(context.Background())
 	assert.EqualError(t, err, `expanding ${test:PORT}, expected convertable to string value type, got ['ӛ']([]interface {})`)
 }
+
+func TestResolverExpandOpaqueValues(t *testing.T) {
+	provider := newFakeProvider("input", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+		return NewRetrieved(map[string]any{
+			"password": "${secret:PASSWORD}",
+			"headers":  map[string]any{"authorization": "Bearer ${secret:TOKEN}", "user": "${test:USER}"},
+			"tls":      "${secret:TLS}",
+			"endpoint": "localhost:4317",
+		})
+	})
+	secretProvider := newFakeProvider("secret", func(_ context.Context, uri string, _ WatcherFunc) (*Retrieved, error) {
+		if uri == "secret:TLS" {
+			return NewRetrieved(map[string]any{"key": "private key", "ca": []any{"ca"}}, WithRetrievedOpaque())
+		}
+		return NewRetrieved("s3cr3t", WithRetrievedOpaque())
+	})
+	testProvider := newFakeProvider("test", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+		return NewRetrieved("admin")
+	})
+
+	resolver, err := NewResolver(ResolverSettings{URIs: []string{"input:"}, Providers: makeMapProvidersMap(provider, secretProvider, testProvider), Converters: nil})
+	require.NoError(t, err)
+
+	cfgMap, err := resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"password": "s3cr3t",
+		"headers":  map[string]any{"authorization": "Bearer s3cr3t", "user": "admin"},
+		"tls":      map[string]any{"key": "private key", "ca": []any{"ca"}},
+		"endpoint": "localhost:4317",
+	}, cfgMap.ToStringMap())
+	assert.Equal(t, map[string]any{
+		"password": "[REDACTED]",
+		"headers":  map[string]any{"authorization": "[REDACTED]", "user": "admin"},
+		"tls":      map[string]any{"key": "[REDACTED]", "ca": "[REDACTED]"},
+		"endpoint": "localhost:4317",
+	}, cfgMap.Redacted().ToStringMap())
+
+	// The opaque values are kept when merged.
+	merged := New()
+	require.NoError(t, merged.Merge(cfgMap))
+	assert.Equal(t, "[REDACTED]", merged.Redacted().Get("password"))
+}
@@ -3,10 +3,12 @@ module go.opentelemetry.io/collector/confmap
 go 1.20
 
 require (
+	github.com/fsnotify/fsnotify v1.7.0
 	github.com/knadh/koanf/maps v0.1.1
 	github.com/knadh/koanf/providers/confmap v0.1.0
 	github.com/knadh/koanf/v2 v2.0.1
 	github.com/mitchellh/mapstructure v1.5.1-0.20220423185008-bf980b35cac4
+	github.com/pelletier/go-toml/v2 v2.1.0
 	github.com/stretchr/testify v1.8.4
 	go.opentelemetry.io/collector/featuregate v1.0.0-rcv0017
 	go.uber.org/multierr v1.11.0
@@ -20,6 +22,7 @@ require (
 	github.com/mitchellh/copystructure v1.2.0 // indirect
 	github.com/mitchellh/reflectwalk v1.0.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.14.0 // indirect
 )
 
 replace go.opentelemetry.io/collector/featuregate => ../featuregate
@@ -38,6 +38,9 @@ type Provider interface {
 	//     in https://tools.ietf.org/id/draft-kerwin-file-scheme-07.html#syntax.
 	//   - For testing, all implementation MUST check that confmaptest.ValidateProviderScheme returns no error.
 	//
+	// The providers retrieving secret material, e.g. from secret stores, MUST mark the returned Retrieved
+	// as opaque, see WithRetrievedOpaque, so the secrets are redacted from the effective configuration.
+	//
 	// `watcher` callback is called when the config changes. watcher may be called from
 	// a different go routine. After watcher is called Retrieved.Get should be called
 	// to get the new config. See description of Retrieved for more details.
@@ -75,12 +78,16 @@ type ChangeEvent struct {
 
 // Retrieved holds the result of a call to the Retrieve method of a Provider object.
 type Retrieved struct {
-	rawConf   any
-	closeFunc CloseFunc
+	rawConf     any
+	opaque      bool
+	closeFunc   CloseFunc
+	appliedFunc AppliedFunc
 }
 
 type retrievedSettings struct {
-	closeFunc CloseFunc
+	opaque      bool
+	closeFunc   CloseFunc
+	appliedFunc AppliedFunc
 }
 
 // RetrievedOption options to customize Retrieved values.
@@ -94,6 +101,23 @@ func WithRetrievedClose(closeFunc CloseFunc) RetrievedOption {
 	}
 }
 
+// WithRetrievedOpaque marks the retrieved value as secret material, e.g. a password fetched from a secret
+// store. The values of the configuration expanded from it are redacted in the Conf returned by Conf.Redacted,
+// so they are not exposed to the config watchers, e.g. to report the effective configuration.
+func WithRetrievedOpaque() RetrievedOption {
+	return func(settings *retrievedSettings) {
+		settings.opaque = true
+	}
+}
+
+// WithRetrievedApplied sets the function notified of the result of applying the configuration the
+// retrieved value is part of, e.g. to report the status of a remote configuration to its source.
+func WithRetrievedApplied(appliedFunc AppliedFunc) RetrievedOption {
+	return func(settings *retrievedSettings) {
+		settings.appliedFunc = appliedFunc
+	}
+}
+
 // NewRetrieved returns a new Retrieved instance that contains the data from the raw deserialized config.
 // The rawConf can be one of the following types:
 //   - Primitives: int, int32, int64, float32, float64, bool, string;
@@ -107,7 +131,7 @@ func NewRetrieved(rawConf any, opts ...RetrievedOption) (*Retrieved, error) {
 	for _, opt := range opts {
 		opt(&set)
 	}
-	return &Retrieved{rawConf: rawConf, closeFunc: set.closeFunc}, nil
+	return &Retrieved{rawConf: rawConf, opaque: set.opaque, closeFunc: set.closeFunc, appliedFunc: set.appliedFunc}, nil
 }
 
 // AsConf returns the retrieved configuration parsed as a Conf.
@@ -130,6 +154,11 @@ func (r *Retrieved) AsRaw() (any, error) {
 	return r.rawConf, nil
 }
 
+// IsOpaque returns whether the retrieved value is secret material, see WithRetrievedOpaque.
+func (r *Retrieved) IsOpaque() bool {
+	return r.opaque
+}
+
 // Close and release any watchers that Provider.Retrieve may have created.
 //
 // Should block until all resources are closed, and guarantee that `onChange` is not
@@ -146,6 +175,19 @@ func (r *Retrieved) Close(ctx context.Context) error {
 // CloseFunc a function equivalent to Retrieved.Close.
 type CloseFunc func(context.Context) error
 
+// Applied notifies the result of applying the configuration the retrieved value is part of: nil if the
+// configuration was applied, e.g. the Collector started with it, or the error preventing it from being applied.
+//
+// Should never be called concurrently with itself.
+func (r *Retrieved) Applied(ctx context.Context, err error) {
+	if r.appliedFunc != nil {
+		r.appliedFunc(ctx, err)
+	}
+}
+
+// AppliedFunc a function equivalent to Retrieved.Applied.
+type AppliedFunc func(ctx context.Context, err error)
+
 func checkRawConfType(rawConf any) error {
 	if rawConf == nil {
 		return nil
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azurekeyvaultprovider // import "go.opentelemetry.io/collector/confmap/provider/azurekeyvaultprovider"
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/provider/internal/secretprovider"
+)
+
+const schemeName = "azurekeyvault"
+
+type provider struct {
+	settings secretprovider.Settings
+	endpoint string
+	// clientOptions are the options of the clients, set by the tests.
+	clientOptions *azsecrets.ClientOptions
+
+	mu sync.Mutex
+	// credential authenticates the requests, the default Azure credential created on the first fetch if
+	// not set by the tests.
+	credential azcore.TokenCredential
+	// clients are the clients of the vaults, by vault URL.
+	clients map[string]*azsecrets.Client
+}
+
+// Option configures the provider.
+type Option func(*provider)
+
+// WithEndpoint sends the requests to the given endpoint, e.g. a private endpoint, rather than to the
+// endpoint of the vault, "https://<vault>.vault.azure.net".
+func WithEndpoint(endpoint string) Option {
+	return func(p *provider) {
+		p.endpoint = endpoint
+	}
+}
+
+// WithCacheTTL caches the values of the secrets for the given duration, 5 minutes by default, the references
+// to the same secret and the reloads of the configuration reusing the cached values. The values are not
+// cached if the duration is not positive.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(p *provider) {
+		p.settings.CacheTTL = ttl
+	}
+}
+
+// WithPollInterval polls the secrets at the given interval, notifying the watcher, which reloads the
+// collector, when they changed, e.g. when a new version is created. The polls use the cached values, so the
+// changes are detected within the cache TTL and the poll interval.
+func WithPollInterval(interval time.Duration) Option {
+	return func(p *provider) {
+		p.settings.PollInterval = interval
+	}
+}
+
+// New returns a new confmap.Provider that retrieves the secrets from Azure Key Vault.
+//
+// This Provider supports "azurekeyvault" scheme, and can be called with a "uri" that follows:
+//
+//	azurekeyvault-uri	= "azurekeyvault:" vault "/" secret [ "/" version ] [ "#" json-key ]
+//
+// The "vault" is the name of the vault, or its DNS name for the sovereign clouds, e.g. "my-vault.vault.azure.cn".
+// The current version of the secret is retrieved by default, as an opaque string, so it is redacted from the
+// effective configuration. With the "json-key", the secret is a JSON object, and the value retrieved is the
+// string of the key.
+//
+// Examples:
+// `${azurekeyvault:my-vault/api-key}`
+// `${azurekeyvault:my-vault/otelcol/0123456789abcdef0123456789abcdef#api_key}`
+//
+// The requests are authenticated with the default credential chain of the Azure SDK: the service principal
+// of the AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET or AZURE_CLIENT_CERTIFICATE_PATH environment
+// variables, or else the workload identity, e.g. on AKS, or else the managed identity of the workload, the
+// user-assigned identity of the AZURE_CLIENT_ID environment variable if set, or else the Azure CLI.
+func New(opts ...Option) confmap.Provider {
+	p := newProvider(opts...)
+	return secretprovider.New(schemeName, p.fetch, p.settings)
+}
+
+func newProvider(opts ...Option) *provider {
+	p := &provider{
+		settings: secretprovider.Settings{CacheTTL: secretprovider.DefaultCacheTTL},
+		clients:  map[string]*azsecrets.Client{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// fetch returns the value of the version of the secret.
+func (p *provider) fetch(ctx context.Context, id string) (string, error) {
+	parts := strings.Split(id, "/")
+	if (len(parts) != 2 && len(parts) != 3) || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("invalid secret %q, must be <vault>/<secret>[/<version>]", id)
+	}
+	vaultURL := p.endpoint
+	if vaultURL == "" {
+		host := parts[0]
+		if !strings.Contains(host, ".") {
+			host += ".vault.azure.net"
+		}
+		vaultURL = "https://" + host
+	}
+	var version string
+	if len(parts) == 3 {
+		version = parts[2]
+	}
+
+	client, err := p.getClient(vaultURL)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.GetSecret(ctx, parts[1], version, nil)
+	if err != nil {
+		return "", err
+	}
+	if resp.Value == nil {
+		return "", nil
+	}
+	return *resp.Value, nil
+}
+
+// getClient returns the client of the vault, created on the first call, with the default Azure credential.
+func (p *provider) getClient(vaultURL string) (*azsecrets.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if client, ok := p.clients[vaultURL]; ok {
+		return client, nil
+	}
+	if p.credential == nil {
+		credential, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the Azure credential: %w", err)
+		}
+		p.credential = credential
+	}
+	client, err := azsecrets.NewClient(vaultURL, p.credential, p.clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the Key Vault client: %w", err)
+	}
+	p.clients[vaultURL] = client
+	return client, nil
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package azurekeyvaultprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/confmap/provider/internal/secretprovider"
+)
+
+// fakeCredential returns the same access token, counting the requests of the tokens.
+type fakeCredential struct {
+	requests atomic.Int64
+}
+
+func (fc *fakeCredential) GetToken(_ context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	fc.requests.Add(1)
+	if len(opts.Scopes) != 1 || opts.Scopes[0] != "https://vault.azure.net/.default" {
+		return azcore.AccessToken{}, assert.AnError
+	}
+	return azcore.AccessToken{Token: "my-token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// handlerTransport sends the requests to the handler, whatever their host.
+type handlerTransport struct {
+	http.Handler
+}
+
+func (ht handlerTransport) Do(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	ht.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// newTestProvider returns a provider sending the requests to a fake Key Vault returning the given secrets,
+// the keys being the URLs of the secrets, if the requests are authenticated with the token of the credential.
+func newTestProvider(t *testing.T, credential azcore.TokenCredential, secrets map[string]string) *provider {
+	vault := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The client authenticates the requests once challenged.
+		if r.Header.Get("Authorization") == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer authorization="https://login.microsoftonline.com/my-tenant", resource="https://vault.azure.net"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "Bearer my-token", r.Header.Get("Authorization"))
+		// The path of the current version of the secret ends with a slash.
+		secret, ok := secrets["https://"+r.URL.Host+strings.TrimSuffix(r.URL.Path, "/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": "SecretNotFound", "message": "A secret with the name was not found."}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"value": secret, "id": "https://" + r.URL.Host + r.URL.Path})
+	})
+
+	p := newProvider()
+	p.credential = credential
+	p.clientOptions = &azsecrets.ClientOptions{ClientOptions: azcore.ClientOptions{Transport: handlerTransport{Handler: vault}}}
+	return p
+}
+
+func TestValidateProviderScheme(t *testing.T) {
+	assert.NoError(t, confmaptest.ValidateProviderScheme(New()))
+}
+
+func TestUnsupportedScheme(t *testing.T) {
+	p := New()
+	_, err := p.Retrieve(context.Background(), "https://", nil)
+	assert.Error(t, err)
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestRetrieve(t *testing.T) {
+	credential := &fakeCredential{}
+	p := newTestProvider(t, credential, map[string]string{
+		"https://my-vault.vault.azure.net/secrets/api-key":                                  "my-api-key",
+		"https://my-vault.vault.azure.net/secrets/otelcol/0123456789abcdef0123456789abcdef": `{"api_key": "my-other-api-key"}`,
+		"https://my-vault.vault.azure.cn/secrets/api-key":                                   "my-sovereign-api-key",
+	})
+	sp := secretprovider.New(schemeName, p.fetch, p.settings)
+
+	ret, err := sp.Retrieve(context.Background(), "azurekeyvault:my-vault/api-key", nil)
+	require.NoError(t, err)
+	value, err := ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "my-api-key", value)
+	assert.True(t, ret.IsOpaque())
+
+	ret, err = sp.Retrieve(context.Background(), "azurekeyvault:my-vault/otelcol/0123456789abcdef0123456789abcdef#api_key", nil)
+	require.NoError(t, err)
+	value, err = ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "my-other-api-key", value)
+
+	_, err = sp.Retrieve(context.Background(), "azurekeyvault:my-vault/unknown", nil)
+	assert.ErrorContains(t, err, "404")
+	assert.ErrorContains(t, err, "SecretNotFound")
+	// The access token is cached by the client of the vault.
+	assert.Equal(t, int64(1), credential.requests.Load())
+
+	p.clientOptions.DisableChallengeResourceVerification = true
+	ret, err = sp.Retrieve(context.Background(), "azurekeyvault:my-vault.vault.azure.cn/api-key", nil)
+	require.NoError(t, err)
+	value, err = ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "my-sovereign-api-key", value)
+	assert.NoError(t, sp.Shutdown(context.Background()))
+}
+
+func TestInvalidSecret(t *testing.T) {
+	for _, id := range []string{"my-vault", "my-vault/", "/api-key", "my-vault/api-key/version/other"} {
+		_, err := New().Retrieve(context.Background(), "azurekeyvault:"+id, nil)
+		assert.ErrorContains(t, err, "invalid secret")
+	}
+}
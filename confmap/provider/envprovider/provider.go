@@ -15,25 +15,75 @@ import (
 
 const schemeName = "env"
 
-type provider struct{}
+type provider struct {
+	// format is the format of the values of the environment variables, YAML if empty.
+	format string
+}
+
+// Option configures the provider.
+type Option func(*provider)
+
+// WithFormat parses the values of the environment variables in the given format, "yaml", "json" or "toml",
+// rather than as YAML. The provider then supports the "env+<format>" scheme, e.g. "env+json:NAME".
+func WithFormat(format string) Option {
+	return func(emp *provider) {
+		emp.format = format
+	}
+}
 
 // New returns a new confmap.Provider that reads the configuration from the given environment variable.
 //
 // This Provider supports "env" scheme, and can be called with a selector:
 // `env:NAME_OF_ENVIRONMENT_VARIABLE`
-func New() confmap.Provider {
-	return &provider{}
+//
+// Like in the shells, the selector can specify the value used when the environment variable is unset
+// or empty, or the error returned in that case:
+// `env:NAME_OF_ENVIRONMENT_VARIABLE:-default value`
+// `env:NAME_OF_ENVIRONMENT_VARIABLE:?error message`
+//
+// The values are parsed as YAML, unless the format is given, see WithFormat.
+func New(opts ...Option) confmap.Provider {
+	emp := &provider{}
+	for _, opt := range opts {
+		opt(emp)
+	}
+	return emp
 }
 
 func (emp *provider) Retrieve(_ context.Context, uri string, _ confmap.WatcherFunc) (*confmap.Retrieved, error) {
-	if !strings.HasPrefix(uri, schemeName+":") {
-		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, schemeName)
+	if !strings.HasPrefix(uri, emp.Scheme()+":") {
+		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, emp.Scheme())
 	}
 
-	return internal.NewRetrievedFromYAML([]byte(os.Getenv(uri[len(schemeName)+1:])))
+	selector := uri[len(emp.Scheme())+1:]
+	name, modifier, hasModifier := strings.Cut(selector, ":")
+	if !hasModifier || (!strings.HasPrefix(modifier, "-") && !strings.HasPrefix(modifier, "?")) {
+		return emp.newRetrieved(os.Getenv(selector))
+	}
+
+	if val := os.Getenv(name); val != "" {
+		return emp.newRetrieved(val)
+	}
+	if modifier[0] == '-' {
+		return emp.newRetrieved(modifier[1:])
+	}
+	if msg := modifier[1:]; msg != "" {
+		return nil, fmt.Errorf("environment variable %q is not set: %s", name, msg)
+	}
+	return nil, fmt.Errorf("environment variable %q is not set", name)
 }
 
-func (*provider) Scheme() string {
+func (emp *provider) newRetrieved(val string) (*confmap.Retrieved, error) {
+	if emp.format == "" {
+		return internal.NewRetrievedFromYAML([]byte(val))
+	}
+	return internal.NewRetrievedFromFormat(emp.format, []byte(val))
+}
+
+func (emp *provider) Scheme() string {
+	if emp.format != "" {
+		return schemeName + "+" + emp.format
+	}
 	return schemeName
 }
 
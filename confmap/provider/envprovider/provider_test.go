@@ -68,3 +68,69 @@ func TestEnv(t *testing.T) {
 
 	assert.NoError(t, env.Shutdown(context.Background()))
 }
+
+func TestEnvWithFormat(t *testing.T) {
+	t.Setenv("json-config", `{"processors": {"batch": null}, "exporters": {"otlp": {"endpoint": "localhost:4317", "timeout": 10}}}`)
+	t.Setenv("toml-config", "[processors.batch]\n[exporters.otlp]\nendpoint = \"localhost:4317\"\ntimeout = 10\n")
+
+	for _, format := range []string{"json", "toml"} {
+		env := New(WithFormat(format))
+		assert.NoError(t, confmaptest.ValidateProviderScheme(env))
+		ret, err := env.Retrieve(context.Background(), "env+"+format+":"+format+"-config", nil)
+		require.NoError(t, err)
+		retMap, err := ret.AsConf()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"endpoint": "localhost:4317", "timeout": 10}, retMap.Get("exporters::otlp"), format)
+		assert.True(t, retMap.IsSet("processors::batch"), format)
+		assert.NoError(t, env.Shutdown(context.Background()))
+	}
+
+	env := New(WithFormat("json"))
+	_, err := env.Retrieve(context.Background(), envSchemePrefix+"json-config", nil)
+	assert.Error(t, err)
+	ret, err := env.Retrieve(context.Background(), "env+json:undefined-config:-{\"key\": \"value\"}", nil)
+	require.NoError(t, err)
+	retMap, err := ret.AsConf()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"key": "value"}, retMap.ToStringMap())
+}
+
+func TestEnvWithDefault(t *testing.T) {
+	t.Setenv("SET", "localhost:4318")
+	t.Setenv("EMPTY", "")
+
+	tests := []struct {
+		name string
+		uri  string
+		want any
+	}{
+		{name: "set", uri: "SET:-localhost:4317", want: "localhost:4318"},
+		{name: "unset", uri: "UNSET:-localhost:4317", want: "localhost:4317"},
+		{name: "empty", uri: "EMPTY:-localhost:4317", want: "localhost:4317"},
+		{name: "empty_default", uri: "UNSET:-", want: nil},
+		{name: "yaml_default", uri: "UNSET:-4317", want: 4317},
+		{name: "required_set", uri: "SET:?the endpoint is required", want: "localhost:4318"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := New()
+			ret, err := env.Retrieve(context.Background(), envSchemePrefix+tt.uri, nil)
+			require.NoError(t, err)
+			raw, err := ret.AsRaw()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, raw)
+			assert.NoError(t, env.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestEnvRequired(t *testing.T) {
+	t.Setenv("EMPTY", "")
+
+	env := New()
+	_, err := env.Retrieve(context.Background(), envSchemePrefix+"UNSET:?the endpoint is required", nil)
+	assert.EqualError(t, err, `environment variable "UNSET" is not set: the endpoint is required`)
+	_, err = env.Retrieve(context.Background(), envSchemePrefix+"EMPTY:?", nil)
+	assert.EqualError(t, err, `environment variable "EMPTY" is not set`)
+	assert.NoError(t, env.Shutdown(context.Background()))
+}
@@ -9,6 +9,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
 
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/provider/internal"
@@ -16,7 +19,34 @@ import (
 
 const schemeName = "file"
 
-type provider struct{}
+type provider struct {
+	watch    bool
+	debounce time.Duration
+	// format is the format of the files, detected from their extensions if empty.
+	format string
+}
+
+// Option configures the provider.
+type Option func(*provider)
+
+// WithWatch watches the files retrieved, notifying the watcher, which reloads the collector, when one of
+// them changed and no other change was detected for the debounce duration, e.g. while an editor writes
+// the file in several steps. The files included in the configuration with the "file" scheme are watched
+// as well, since they are retrieved with the same provider.
+func WithWatch(debounce time.Duration) Option {
+	return func(fmp *provider) {
+		fmp.watch = true
+		fmp.debounce = debounce
+	}
+}
+
+// WithFormat parses the files in the given format, "yaml", "json" or "toml", rather than in the format of their
+// extensions. The provider then supports the "file+<format>" scheme, e.g. "file+json:path/to/config".
+func WithFormat(format string) Option {
+	return func(fmp *provider) {
+		fmp.format = format
+	}
+}
 
 // New returns a new confmap.Provider that reads the configuration from a file.
 //
@@ -33,25 +63,119 @@ type provider struct{}
 // `file:/path/to/file` - absolute path (unix, windows)
 // `file:c:/path/to/file` - absolute path including drive-letter (windows)
 // `file:c:\path\to\file` - absolute path including drive-letter (windows)
-func New() confmap.Provider {
-	return &provider{}
+//
+// The files are parsed as JSON with the ".json" extension, as TOML with the ".toml" extension, and as YAML
+// otherwise, unless the format is given, see WithFormat. The files are not watched by default, see WithWatch.
+func New(opts ...Option) confmap.Provider {
+	fmp := &provider{}
+	for _, opt := range opts {
+		opt(fmp)
+	}
+	return fmp
 }
 
-func (fmp *provider) Retrieve(_ context.Context, uri string, _ confmap.WatcherFunc) (*confmap.Retrieved, error) {
-	if !strings.HasPrefix(uri, schemeName+":") {
-		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, schemeName)
+func (fmp *provider) Retrieve(ctx context.Context, uri string, watcher confmap.WatcherFunc) (*confmap.Retrieved, error) {
+	if !strings.HasPrefix(uri, fmp.Scheme()+":") {
+		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, fmp.Scheme())
 	}
 
 	// Clean the path before using it.
-	content, err := os.ReadFile(filepath.Clean(uri[len(schemeName)+1:]))
+	path := filepath.Clean(uri[len(fmp.Scheme())+1:])
+	content, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read the file %v: %w", uri, err)
 	}
+	format := fmp.format
+	if format == "" {
+		format = internal.FormatFromPath(path)
+	}
+
+	if !fmp.watch || watcher == nil {
+		return internal.NewRetrievedFromFormat(format, content)
+	}
+	closeFunc, err := fmp.watchFile(path, watcher)
+	if err != nil {
+		return nil, fmt.Errorf("unable to watch the file %v: %w", uri, err)
+	}
+	retrieved, err := internal.NewRetrievedFromFormat(format, content, confmap.WithRetrievedClose(closeFunc))
+	if err != nil {
+		_ = closeFunc(ctx)
+		return nil, err
+	}
+	return retrieved, nil
+}
 
-	return internal.NewRetrievedFromYAML(content)
+// watchFile watches the file until the returned close function is called.
+func (fmp *provider) watchFile(path string, watcher confmap.WatcherFunc) (confmap.CloseFunc, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// The directory is watched rather than the file, since the file is often replaced rather than
+	// written, e.g. by the editors renaming a new file over it, or by Kubernetes updating the symbolic
+	// links of the ConfigMaps, which would stop the watch of the file.
+	if err = fsWatcher.Add(filepath.Dir(path)); err != nil {
+		_ = fsWatcher.Close()
+		return nil, err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fmp.handleEvents(fsWatcher, path, watcher)
+	}()
+	return func(context.Context) error {
+		err := fsWatcher.Close()
+		<-done
+		return err
+	}, nil
+}
+
+// handleEvents handles the events of the directory of the file until the watch is closed, and notifies the
+// watcher once when the file changed.
+func (fmp *provider) handleEvents(fsWatcher *fsnotify.Watcher, path string, watcher confmap.WatcherFunc) {
+	realPath, _ := filepath.EvalSymlinks(path)
+	var timer *time.Timer
+	var debounced <-chan time.Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for {
+		select {
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return
+			}
+			// The target of the symbolic link changes when the file is replaced through a link.
+			currentPath, _ := filepath.EvalSymlinks(path)
+			linkChanged := currentPath != realPath
+			realPath = currentPath
+			if !linkChanged && (filepath.Clean(event.Name) != path || event.Op == fsnotify.Chmod) {
+				continue
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.NewTimer(fmp.debounce)
+			debounced = timer.C
+		case _, ok := <-fsWatcher.Errors:
+			// The errors are not reported, since the watcher would stop the collector: the collector keeps
+			// running with the current configuration.
+			if !ok {
+				return
+			}
+		case <-debounced:
+			watcher(&confmap.ChangeEvent{})
+			return
+		}
+	}
 }
 
-func (*provider) Scheme() string {
+func (fmp *provider) Scheme() string {
+	if fmp.format != "" {
+		return schemeName + "+" + fmp.format
+	}
 	return schemeName
 }
 
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -82,6 +83,132 @@ func TestAbsolutePath(t *testing.T) {
 	assert.NoError(t, fp.Shutdown(context.Background()))
 }
 
+func TestFormatFromExtension(t *testing.T) {
+	fp := New()
+	for _, file := range []string{"default-config.json", "default-config.toml"} {
+		ret, err := fp.Retrieve(context.Background(), fileSchemePrefix+filepath.Join("testdata", file), nil)
+		require.NoError(t, err)
+		retMap, err := ret.AsConf()
+		require.NoError(t, err)
+		assert.Equal(t, "localhost:4317", retMap.Get("exporters::otlp::endpoint"), file)
+		assert.True(t, retMap.IsSet("processors::batch"), file)
+	}
+	_, err := fp.Retrieve(context.Background(), fileSchemePrefix+filepath.Join("testdata", "invalid-toml.toml"), nil)
+	assert.ErrorContains(t, err, "toml: line 1: array is incomplete")
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestWithFormat(t *testing.T) {
+	fp := New(WithFormat("toml"))
+	assert.NoError(t, confmaptest.ValidateProviderScheme(fp))
+	assert.Equal(t, "file+toml", fp.Scheme())
+
+	_, err := fp.Retrieve(context.Background(), fileSchemePrefix+filepath.Join("testdata", "default-config.conf"), nil)
+	assert.Error(t, err)
+	ret, err := fp.Retrieve(context.Background(), "file+toml:"+filepath.Join("testdata", "default-config.conf"), nil)
+	require.NoError(t, err)
+	retMap, err := ret.AsConf()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"processors": map[string]any{"batch": map[string]any{}},
+		"exporters":  map[string]any{"otlp": map[string]any{"endpoint": "localhost:4317"}},
+	}, retMap.ToStringMap())
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestWatchFileChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("key: value"), 0600))
+
+	fp := New(WithWatch(50 * time.Millisecond))
+	events := make(chan *confmap.ChangeEvent, 10)
+	ret, err := fp.Retrieve(context.Background(), fileSchemePrefix+path, func(event *confmap.ChangeEvent) {
+		events <- event
+	})
+	require.NoError(t, err)
+
+	// The writes within the debounce duration are notified once.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, os.WriteFile(path, []byte("key: changed"), 0600))
+	}
+	select {
+	case event := <-events:
+		assert.NoError(t, event.Error)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change of the file was not notified")
+	}
+
+	require.NoError(t, ret.Close(context.Background()))
+	assert.Empty(t, events)
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestWatchFileReplaced(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("key: value"), 0600))
+
+	fp := New(WithWatch(0))
+	events := make(chan *confmap.ChangeEvent, 10)
+	ret, err := fp.Retrieve(context.Background(), fileSchemePrefix+path, func(event *confmap.ChangeEvent) {
+		events <- event
+	})
+	require.NoError(t, err)
+
+	newPath := filepath.Join(dir, "config.yaml.new")
+	require.NoError(t, os.WriteFile(newPath, []byte("key: changed"), 0600))
+	require.NoError(t, os.Rename(newPath, path))
+	select {
+	case event := <-events:
+		assert.NoError(t, event.Error)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the replacement of the file was not notified")
+	}
+
+	require.NoError(t, ret.Close(context.Background()))
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestWatchOtherFileChanged(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("key: value"), 0600))
+
+	fp := New(WithWatch(0))
+	events := make(chan *confmap.ChangeEvent, 10)
+	ret, err := fp.Retrieve(context.Background(), fileSchemePrefix+path, func(event *confmap.ChangeEvent) {
+		events <- event
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("key: value"), 0600))
+	select {
+	case <-events:
+		t.Fatal("the change of another file was notified")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.NoError(t, ret.Close(context.Background()))
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestNoWatchByDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("key: value"), 0600))
+
+	fp := New()
+	ret, err := fp.Retrieve(context.Background(), fileSchemePrefix+path, func(*confmap.ChangeEvent) {
+		t.Error("the file is not watched by default")
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("key: changed"), 0600))
+	time.Sleep(100 * time.Millisecond)
+
+	require.NoError(t, ret.Close(context.Background()))
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
 func absolutePath(t *testing.T, relativePath string) string {
 	dir, err := os.Getwd()
 	require.NoError(t, err)
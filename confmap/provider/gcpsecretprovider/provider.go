@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gcpsecretprovider // import "go.opentelemetry.io/collector/confmap/provider/gcpsecretprovider"
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/api/option"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/provider/internal/secretprovider"
+)
+
+const schemeName = "gcpsecret"
+
+type provider struct {
+	settings secretprovider.Settings
+	endpoint string
+	// clientOptions are the additional options of the client, set by the tests.
+	clientOptions []option.ClientOption
+
+	mu     sync.Mutex
+	client *secretmanager.Client
+}
+
+// Option configures the provider.
+type Option func(*provider)
+
+// WithEndpoint sends the requests to the given endpoint, e.g. a Private Service Connect endpoint, rather than
+// to "secretmanager.googleapis.com:443".
+func WithEndpoint(endpoint string) Option {
+	return func(p *provider) {
+		p.endpoint = endpoint
+	}
+}
+
+// WithCacheTTL caches the values of the secrets for the given duration, 5 minutes by default, the references
+// to the same secret and the reloads of the configuration reusing the cached values. The values are not
+// cached if the duration is not positive.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(p *provider) {
+		p.settings.CacheTTL = ttl
+	}
+}
+
+// WithPollInterval polls the secrets at the given interval, notifying the watcher, which reloads the
+// collector, when they changed, e.g. when a new version is added. The polls use the cached values, so the
+// changes are detected within the cache TTL and the poll interval.
+func WithPollInterval(interval time.Duration) Option {
+	return func(p *provider) {
+		p.settings.PollInterval = interval
+	}
+}
+
+// New returns a new confmap.Provider that retrieves the secrets from GCP Secret Manager.
+//
+// This Provider supports "gcpsecret" scheme, and can be called with a "uri" that follows:
+//
+//	gcpsecret-uri	= "gcpsecret:projects/" project "/secrets/" secret [ "/versions/" version ] [ "#" json-key ]
+//
+// The "version" is "latest" by default, the version of the secret being retrieved as an opaque string, so
+// it is redacted from the effective configuration. With the "json-key", the secret is a JSON object, and the
+// value retrieved is the string of the key.
+//
+// Examples:
+// `${gcpsecret:projects/my-project/secrets/api_key}`
+// `${gcpsecret:projects/my-project/secrets/otelcol/versions/3#api_key}`
+//
+// The requests are authenticated with the Application Default Credentials: the credentials file of the
+// GOOGLE_APPLICATION_CREDENTIALS environment variable, e.g. of a service account or of a workload identity
+// federation, or else the credentials of the gcloud CLI, or else the service account of the workload, from
+// the metadata server, e.g. on GCE, GKE with Workload Identity or Cloud Run.
+func New(opts ...Option) confmap.Provider {
+	p := newProvider(opts...)
+	return secretprovider.New(schemeName, p.fetch, p.settings)
+}
+
+func newProvider(opts ...Option) *provider {
+	p := &provider{settings: secretprovider.Settings{CacheTTL: secretprovider.DefaultCacheTTL}}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.settings.Shutdown = p.shutdown
+	return p
+}
+
+// fetch returns the value of the version of the secret.
+func (p *provider) fetch(ctx context.Context, name string) (string, error) {
+	parts := strings.Split(name, "/")
+	switch {
+	case len(parts) == 4 && parts[0] == "projects" && parts[2] == "secrets":
+		name += "/versions/latest"
+	case len(parts) == 6 && parts[0] == "projects" && parts[2] == "secrets" && parts[4] == "versions":
+	default:
+		return "", fmt.Errorf("invalid secret name %q, must be projects/<project>/secrets/<secret>[/versions/<version>]", name)
+	}
+
+	client, err := p.getClient()
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{Name: name})
+	if err != nil {
+		return "", err
+	}
+	return string(resp.GetPayload().GetData()), nil
+}
+
+// getClient returns the client of Secret Manager, created on the first call.
+func (p *provider) getClient() (*secretmanager.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client != nil {
+		return p.client, nil
+	}
+	var opts []option.ClientOption
+	if p.endpoint != "" {
+		opts = append(opts, option.WithEndpoint(p.endpoint))
+	}
+	// The client is not created with the context of the fetch, which the credentials keep to renew the tokens.
+	client, err := secretmanager.NewClient(context.Background(), append(opts, p.clientOptions...)...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the Secret Manager client: %w", err)
+	}
+	p.client = client
+	return client, nil
+}
+
+func (p *provider) shutdown(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client == nil {
+		return nil
+	}
+	err := p.client.Close()
+	p.client = nil
+	return err
+}
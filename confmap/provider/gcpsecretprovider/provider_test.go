@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gcpsecretprovider
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/confmap/provider/internal/secretprovider"
+)
+
+// fakeSecretManager is a Secret Manager server returning the given secrets, by version name.
+type fakeSecretManager struct {
+	secretmanagerpb.UnimplementedSecretManagerServiceServer
+	secrets map[string]string
+}
+
+func (f *fakeSecretManager) AccessSecretVersion(_ context.Context, req *secretmanagerpb.AccessSecretVersionRequest) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	secret, ok := f.secrets[req.Name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "secret version %q not found", req.Name)
+	}
+	return &secretmanagerpb.AccessSecretVersionResponse{Name: req.Name, Payload: &secretmanagerpb.SecretPayload{Data: []byte(secret)}}, nil
+}
+
+// newTestProvider returns a provider sending the requests to a fake Secret Manager server returning the
+// given secrets.
+func newTestProvider(t *testing.T, secrets map[string]string) *provider {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	secretmanagerpb.RegisterSecretManagerServiceServer(srv, &fakeSecretManager{secrets: secrets})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+
+	p := newProvider(WithEndpoint(ln.Addr().String()))
+	p.clientOptions = []option.ClientOption{
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	}
+	return p
+}
+
+func TestValidateProviderScheme(t *testing.T) {
+	assert.NoError(t, confmaptest.ValidateProviderScheme(New()))
+}
+
+func TestUnsupportedScheme(t *testing.T) {
+	p := New()
+	_, err := p.Retrieve(context.Background(), "https://", nil)
+	assert.Error(t, err)
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestRetrieve(t *testing.T) {
+	p := newTestProvider(t, map[string]string{
+		"projects/my-project/secrets/api_key/versions/latest": "my-api-key",
+		"projects/my-project/secrets/otelcol/versions/3":      `{"api_key": "my-other-api-key"}`,
+	})
+	sp := secretprovider.New(schemeName, p.fetch, p.settings)
+
+	ret, err := sp.Retrieve(context.Background(), "gcpsecret:projects/my-project/secrets/api_key", nil)
+	require.NoError(t, err)
+	value, err := ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "my-api-key", value)
+	assert.True(t, ret.IsOpaque())
+
+	ret, err = sp.Retrieve(context.Background(), "gcpsecret:projects/my-project/secrets/otelcol/versions/3#api_key", nil)
+	require.NoError(t, err)
+	value, err = ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "my-other-api-key", value)
+
+	_, err = sp.Retrieve(context.Background(), "gcpsecret:projects/my-project/secrets/unknown", nil)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// The client is closed on shutdown.
+	require.NotNil(t, p.client)
+	assert.NoError(t, sp.Shutdown(context.Background()))
+	assert.Nil(t, p.client)
+}
+
+func TestInvalidSecretName(t *testing.T) {
+	for _, name := range []string{"api_key", "projects/my-project/api_key", "projects/my-project/secrets/api_key/3"} {
+		_, err := New().Retrieve(context.Background(), "gcpsecret:"+name, nil)
+		assert.ErrorContains(t, err, "invalid secret name")
+	}
+}
@@ -4,16 +4,61 @@
 package httpsprovider // import "go.opentelemetry.io/collector/confmap/provider/httpsprovider"
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/provider/internal/configurablehttpprovider"
 )
 
+type settings = configurablehttpprovider.Settings
+
+// Option configures the provider.
+type Option func(*settings)
+
+// WithCAFile verifies the certificate of the server with the CA certificates of the given PEM file, in
+// addition to the system root certificates.
+func WithCAFile(caFile string) Option {
+	return func(set *settings) {
+		set.CAFile = caFile
+	}
+}
+
+// WithClientCertificate authenticates the collector with the client certificate and key of the given
+// PEM files.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(set *settings) {
+		set.CertFile = certFile
+		set.KeyFile = keyFile
+	}
+}
+
+// WithHeaders adds the given headers to the requests, e.g. the "Authorization" header.
+func WithHeaders(headers map[string]string) Option {
+	return func(set *settings) {
+		set.Headers = headers
+	}
+}
+
+// WithPollInterval polls the configuration at the given interval, notifying the watcher, which reloads
+// the collector, when the configuration changed. The requests are conditional on the "ETag" or
+// "Last-Modified" headers returned by the server, if any.
+func WithPollInterval(interval time.Duration) Option {
+	return func(set *settings) {
+		set.PollInterval = interval
+	}
+}
+
 // New returns a new confmap.Provider that reads the configuration from a https server.
 //
 // This Provider supports "https" scheme. One example of an HTTPS URI is: https://localhost:3333/getConfig
 //
-// To add extra CA certificates you need to install certificates in the system pool. This procedure is operating system
-// dependent. E.g.: on Linux please refer to the `update-ca-trust` command.
-func New() confmap.Provider {
-	return configurablehttpprovider.New(configurablehttpprovider.HTTPSScheme)
+// To add extra CA certificates you need to install certificates in the system pool, or use WithCAFile. The
+// procedure to install certificates is operating system dependent. E.g.: on Linux please refer to the
+// `update-ca-trust` command.
+func New(opts ...Option) confmap.Provider {
+	var set settings
+	for _, opt := range opts {
+		opt(&set)
+	}
+	return configurablehttpprovider.NewWithSettings(configurablehttpprovider.HTTPSScheme, set)
 }
@@ -4,12 +4,43 @@
 package httpsprovider
 
 import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSupportedScheme(t *testing.T) {
 	fp := New()
 	assert.Equal(t, "https", fp.Scheme())
 }
+
+func TestRetrieveWithOptions(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("key: value"))
+	}))
+	defer ts.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0600))
+
+	fp := New(WithCAFile(caFile), WithHeaders(map[string]string{"Authorization": "Bearer token"}))
+	ret, err := fp.Retrieve(context.Background(), ts.URL, nil)
+	require.NoError(t, err)
+	raw, err := ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"key": "value"}, raw)
+	assert.NoError(t, fp.Shutdown(context.Background()))
+
+	_, err = New(WithCAFile(caFile)).Retrieve(context.Background(), ts.URL, nil)
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@
 package configurablehttpprovider // import "go.opentelemetry.io/collector/confmap/provider/internal/configurablehttpprovider"
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -13,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/provider/internal"
@@ -25,10 +27,27 @@ const (
 	HTTPSScheme SchemeType = "https"
 )
 
+// Settings are the settings of the provider, all optional.
+type Settings struct {
+	// CAFile is the path of the CA certificates, in PEM format, verifying the certificate of the server in
+	// addition to the system root certificates.
+	CAFile string
+	// CertFile and KeyFile are the paths of the client certificate and key, in PEM format, authenticating
+	// the collector with the server.
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify skips the verification of the certificate of the server.
+	InsecureSkipVerify bool
+	// Headers are the headers added to the requests, e.g. the "Authorization" header.
+	Headers map[string]string
+	// PollInterval if positive, is the interval the configuration is polled at, the watcher being
+	// notified when it changes. The configuration is not polled by default.
+	PollInterval time.Duration
+}
+
 type provider struct {
-	scheme             SchemeType
-	caCertPath         string // Used for tests
-	insecureSkipVerify bool   // Used for tests
+	scheme   SchemeType
+	settings Settings
 }
 
 // New returns a new provider that reads the configuration from http server using the configured transport mechanism
@@ -39,7 +58,19 @@ type provider struct {
 // One example for https-uri: https://localhost:3333/getConfig
 // This is used by the http and https external implementations.
 func New(scheme SchemeType) confmap.Provider {
-	return &provider{scheme: scheme}
+	return NewWithSettings(scheme, Settings{})
+}
+
+// NewWithSettings returns a new provider like New, with the given settings.
+func NewWithSettings(scheme SchemeType, set Settings) confmap.Provider {
+	return &provider{scheme: scheme, settings: set}
+}
+
+// response is the state of the configuration last retrieved, compared with the polled one.
+type response struct {
+	body         []byte
+	etag         string
+	lastModified string
 }
 
 // Create the client based on the type of scheme that was selected.
@@ -54,24 +85,33 @@ func (fmp *provider) createClient() (*http.Client, error) {
 			return nil, fmt.Errorf("unable to create a cert pool: %w", err)
 		}
 
-		if fmp.caCertPath != "" {
-			cert, err := os.ReadFile(filepath.Clean(fmp.caCertPath))
+		if fmp.settings.CAFile != "" {
+			cert, err := os.ReadFile(filepath.Clean(fmp.settings.CAFile))
 
 			if err != nil {
-				return nil, fmt.Errorf("unable to read CA from %q URI: %w", fmp.caCertPath, err)
+				return nil, fmt.Errorf("unable to read CA from %q URI: %w", fmp.settings.CAFile, err)
 			}
 
 			if ok := pool.AppendCertsFromPEM(cert); !ok {
-				return nil, fmt.Errorf("unable to add CA from uri: %s into the cert pool", fmp.caCertPath)
+				return nil, fmt.Errorf("unable to add CA from uri: %s into the cert pool", fmp.settings.CAFile)
 			}
 		}
 
+		tlsCfg := &tls.Config{
+			InsecureSkipVerify: fmp.settings.InsecureSkipVerify,
+			RootCAs:            pool,
+		}
+		if fmp.settings.CertFile != "" || fmp.settings.KeyFile != "" {
+			cert, err := tls.LoadX509KeyPair(filepath.Clean(fmp.settings.CertFile), filepath.Clean(fmp.settings.KeyFile))
+			if err != nil {
+				return nil, fmt.Errorf("unable to load the client certificate: %w", err)
+			}
+			tlsCfg.Certificates = []tls.Certificate{cert}
+		}
+
 		return &http.Client{
 			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: fmp.insecureSkipVerify,
-					RootCAs:            pool,
-				},
+				TLSClientConfig: tlsCfg,
 			},
 		}, nil
 	default:
@@ -79,7 +119,7 @@ func (fmp *provider) createClient() (*http.Client, error) {
 	}
 }
 
-func (fmp *provider) Retrieve(_ context.Context, uri string, _ confmap.WatcherFunc) (*confmap.Retrieved, error) {
+func (fmp *provider) Retrieve(ctx context.Context, uri string, watcher confmap.WatcherFunc) (*confmap.Retrieved, error) {
 
 	if !strings.HasPrefix(uri, string(fmp.scheme)+":") {
 		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, string(fmp.scheme))
@@ -91,14 +131,63 @@ func (fmp *provider) Retrieve(_ context.Context, uri string, _ confmap.WatcherFu
 		return nil, fmt.Errorf("unable to configure http transport layer: %w", err)
 	}
 
+	last, err := fmp.get(ctx, client, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if watcher == nil || fmp.settings.PollInterval <= 0 {
+		return internal.NewRetrievedFromYAML(last.body)
+	}
+	pollCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fmp.poll(pollCtx, client, uri, last, watcher)
+	}()
+	closeFunc := func(context.Context) error {
+		cancel()
+		<-done
+		return nil
+	}
+	retrieved, err := internal.NewRetrievedFromYAML(last.body, confmap.WithRetrievedClose(closeFunc))
+	if err != nil {
+		_ = closeFunc(ctx)
+		return nil, err
+	}
+	return retrieved, nil
+}
+
+// get downloads the configuration, conditionally if the last response is not nil, in which case the
+// returned response is nil if the configuration was not modified.
+func (fmp *provider) get(ctx context.Context, client *http.Client, uri string, last *response) (*response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the HTTP request for uri %q: %w", uri, err)
+	}
+	for k, v := range fmp.settings.Headers {
+		req.Header.Set(k, v)
+	}
+	if last != nil {
+		if last.etag != "" {
+			req.Header.Set("If-None-Match", last.etag)
+		}
+		if last.lastModified != "" {
+			req.Header.Set("If-Modified-Since", last.lastModified)
+		}
+	}
+
 	// send a HTTP GET request
-	resp, err := client.Get(uri)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("unable to download the file via HTTP GET for uri %q: %w ", uri, err)
 	}
 	defer resp.Body.Close()
 
 	// check the HTTP status code
+	if last != nil && resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("failed to load resource from uri %q. status code: %d", uri, resp.StatusCode)
 	}
@@ -109,7 +198,37 @@ func (fmp *provider) Retrieve(_ context.Context, uri string, _ confmap.WatcherFu
 		return nil, fmt.Errorf("fail to read the response body from uri %q: %w", uri, err)
 	}
 
-	return internal.NewRetrievedFromYAML(body)
+	return &response{
+		body:         body,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
+
+// poll polls the configuration until the context is done, and notifies the watcher once when it changed.
+func (fmp *provider) poll(ctx context.Context, client *http.Client, uri string, last *response, watcher confmap.WatcherFunc) {
+	ticker := time.NewTicker(fmp.settings.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// The failures to poll are not reported, since the watcher would stop the collector: the
+		// collector keeps running with the current configuration, which is polled again later.
+		resp, err := fmp.get(ctx, client, uri, last)
+		if err != nil || resp == nil {
+			continue
+		}
+		// The servers not supporting the conditional requests return the configuration unchanged.
+		if bytes.Equal(resp.body, last.body) {
+			last = resp
+			continue
+		}
+		watcher(&confmap.ChangeEvent{})
+		return
+	}
 }
 
 func (fmp *provider) Scheme() string {
@@ -17,12 +17,15 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 )
 
@@ -215,9 +218,9 @@ func TestFunctionalityDownloadFileHTTPS(t *testing.T) {
 			tsURL, err := url.Parse(ts.URL)
 			require.NoError(t, err)
 			if tt.useCertificate {
-				fp.caCertPath = tt.certPath
+				fp.settings.CAFile = tt.certPath
 			}
-			fp.insecureSkipVerify = tt.skipHostnameValidation
+			fp.settings.InsecureSkipVerify = tt.skipHostnameValidation
 			_, err = fp.Retrieve(context.Background(), fmt.Sprintf("https://%s:%s", tt.hostName, tsURL.Port()), nil)
 			if tt.shouldError {
 				assert.Error(t, err)
@@ -302,3 +305,118 @@ func TestInvalidTransport(t *testing.T) {
 	_, err := fp.Retrieve(context.Background(), "foo://..", nil)
 	assert.Error(t, err)
 }
+
+func TestHeaders(t *testing.T) {
+	fp := NewWithSettings(HTTPScheme, Settings{Headers: map[string]string{"Authorization": "Bearer token"}})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		answerGet(w, r)
+	}))
+	defer ts.Close()
+	_, err := fp.Retrieve(context.Background(), ts.URL, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestInvalidClientCertificate(t *testing.T) {
+	fp := NewWithSettings(HTTPSScheme, Settings{CertFile: "no_certificate", KeyFile: "no_key"})
+	_, err := fp.Retrieve(context.Background(), "https://localhost", nil)
+	assert.ErrorContains(t, err, "unable to load the client certificate")
+}
+
+// configServer serves the configuration with an ETag, and answers the conditional requests.
+type configServer struct {
+	mu          sync.Mutex
+	config      string
+	conditional int
+}
+
+func (cs *configServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	etag := fmt.Sprintf("%q", cs.config)
+	if r.Header.Get("If-None-Match") == etag {
+		cs.conditional++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	_, _ = w.Write([]byte(cs.config))
+}
+
+func (cs *configServer) set(config string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.config = config
+}
+
+func (cs *configServer) conditionalRequests() int {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.conditional
+}
+
+func TestPollConfigChanged(t *testing.T) {
+	cs := &configServer{config: "key: value"}
+	ts := httptest.NewServer(cs)
+	defer ts.Close()
+
+	fp := NewWithSettings(HTTPScheme, Settings{PollInterval: time.Millisecond})
+	watched := make(chan *confmap.ChangeEvent, 1)
+	ret, err := fp.Retrieve(context.Background(), ts.URL, func(event *confmap.ChangeEvent) { watched <- event })
+	require.NoError(t, err)
+	raw, err := ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"key": "value"}, raw)
+
+	// The configuration is polled with conditional requests until it changes.
+	assert.Eventually(t, func() bool { return cs.conditionalRequests() >= 2 }, 10*time.Second, time.Millisecond)
+	assert.Empty(t, watched)
+
+	cs.set("key: changed")
+	event := <-watched
+	assert.NoError(t, event.Error)
+	require.NoError(t, ret.Close(context.Background()))
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestPollConfigUnchangedWithoutETag(t *testing.T) {
+	var requests atomic.Int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		answerGet(w, r)
+	}))
+	defer ts.Close()
+
+	fp := NewWithSettings(HTTPScheme, Settings{PollInterval: time.Millisecond})
+	ret, err := fp.Retrieve(context.Background(), ts.URL, func(*confmap.ChangeEvent) {
+		assert.Fail(t, "The configuration did not change")
+	})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return requests.Load() >= 3 }, 10*time.Second, time.Millisecond)
+	require.NoError(t, ret.Close(context.Background()))
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestNoPollWithoutWatcher(t *testing.T) {
+	var requests atomic.Int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		answerGet(w, r)
+	}))
+	defer ts.Close()
+
+	fp := NewWithSettings(HTTPScheme, Settings{PollInterval: time.Millisecond})
+	ret, err := fp.Retrieve(context.Background(), ts.URL, nil)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int64(1), requests.Load())
+	require.NoError(t, ret.Close(context.Background()))
+}
@@ -4,11 +4,26 @@
 package internal // import "go.opentelemetry.io/collector/confmap/provider/internal"
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
 	"gopkg.in/yaml.v3"
 
 	"go.opentelemetry.io/collector/confmap"
 )
 
+// The formats of the configurations.
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
 // NewRetrievedFromYAML returns a new Retrieved instance that contains the deserialized data from the yaml bytes.
 // * yamlBytes the yaml bytes that will be deserialized.
 // * opts specifies options associated with this Retrieved value, such as CloseFunc.
@@ -19,3 +34,85 @@ func NewRetrievedFromYAML(yamlBytes []byte, opts ...confmap.RetrievedOption) (*c
 	}
 	return confmap.NewRetrieved(rawConf, opts...)
 }
+
+// NewRetrievedFromFormat returns a new Retrieved instance that contains the deserialized data from the bytes in the
+// given format, FormatYAML, FormatJSON or FormatTOML, converted to the same values as YAML. The empty bytes
+// are deserialized as an empty configuration in all the formats.
+func NewRetrievedFromFormat(format string, data []byte, opts ...confmap.RetrievedOption) (*confmap.Retrieved, error) {
+	var rawConf any
+	switch format {
+	case FormatYAML:
+		return NewRetrievedFromYAML(data, opts...)
+	case FormatJSON:
+		if len(bytes.TrimSpace(data)) > 0 {
+			var err error
+			if rawConf, err = unmarshalJSON(data); err != nil {
+				return nil, err
+			}
+		}
+	case FormatTOML:
+		conf, err := unmarshalTOML(data)
+		if err != nil {
+			return nil, err
+		}
+		rawConf = conf
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	return confmap.NewRetrieved(rawConf, opts...)
+}
+
+// FormatFromPath returns the format of the file of the given path from its extension, FormatYAML if not
+// ".json" or ".toml".
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatYAML
+	}
+}
+
+// unmarshalJSON deserializes the JSON value, the numbers being converted to int when they are integers, like
+// the YAML numbers, rather than to float64.
+func unmarshalJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("invalid data after the JSON value")
+	}
+	return convertJSONNumbers(value)
+}
+
+func convertJSONNumbers(value any) (any, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i), nil
+		}
+		return v.Float64()
+	case map[string]any:
+		for key, elem := range v {
+			converted, err := convertJSONNumbers(elem)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+	case []any:
+		for i, elem := range v {
+			converted, err := convertJSONNumbers(elem)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+	}
+	return value, nil
+}
@@ -45,3 +45,44 @@ func TestNewRetrievedFromYAMLInvalidAsMap(t *testing.T) {
 	_, err = ret.AsConf()
 	assert.Error(t, err)
 }
+
+func TestNewRetrievedFromFormat(t *testing.T) {
+	tests := []struct {
+		format   string
+		data     string
+		expected map[string]any
+	}{
+		{format: FormatYAML, data: "key:\n  int: 1\n  float: 1.5\n  list: [a, true]\n", expected: map[string]any{"key": map[string]any{"int": 1, "float": 1.5, "list": []any{"a", true}}}},
+		{format: FormatJSON, data: `{"key": {"int": 1, "float": 1.5, "list": ["a", true], "null": null}}`, expected: map[string]any{"key": map[string]any{"int": 1, "float": 1.5, "list": []any{"a", true}, "null": nil}}},
+		{format: FormatTOML, data: "[key]\nint = 1\nfloat = 1.5\nlist = [\"a\", true]\n", expected: map[string]any{"key": map[string]any{"int": 1, "float": 1.5, "list": []any{"a", true}}}},
+		{format: FormatJSON, data: " \n", expected: map[string]any{}},
+		{format: FormatTOML, data: "", expected: map[string]any{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			ret, err := NewRetrievedFromFormat(tt.format, []byte(tt.data))
+			require.NoError(t, err)
+			retMap, err := ret.AsConf()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, retMap.ToStringMap())
+		})
+	}
+}
+
+func TestNewRetrievedFromFormatErrors(t *testing.T) {
+	_, err := NewRetrievedFromFormat("xml", []byte("<key/>"))
+	assert.EqualError(t, err, `unsupported format "xml"`)
+	_, err = NewRetrievedFromFormat(FormatJSON, []byte(`{"key": `))
+	assert.Error(t, err)
+	_, err = NewRetrievedFromFormat(FormatJSON, []byte(`{"key": 1} {}`))
+	assert.EqualError(t, err, "invalid data after the JSON value")
+	_, err = NewRetrievedFromFormat(FormatTOML, []byte("key = "))
+	assert.Error(t, err)
+}
+
+func TestFormatFromPath(t *testing.T) {
+	assert.Equal(t, FormatJSON, FormatFromPath("config.json"))
+	assert.Equal(t, FormatTOML, FormatFromPath("/etc/otelcol/config.TOML"))
+	assert.Equal(t, FormatYAML, FormatFromPath("config.yaml"))
+	assert.Equal(t, FormatYAML, FormatFromPath("config"))
+}
@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package secretprovider // import "go.opentelemetry.io/collector/confmap/provider/internal/secretprovider"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/confmap"
+)
+
+// DefaultCacheTTL is the default CacheTTL of the secret stores providers.
+const DefaultCacheTTL = 5 * time.Minute
+
+// fetchTimeout is the timeout of the fetches of the secrets, including the retrieval of the credentials.
+const fetchTimeout = 10 * time.Second
+
+// FetchFunc returns the current value of the secret of the given ID, from the secret store.
+type FetchFunc func(ctx context.Context, id string) (string, error)
+
+// Settings are the settings of the provider, all optional.
+type Settings struct {
+	// CacheTTL if positive, is the duration the values of the secrets are cached for, the references to the
+	// same secret and the reloads of the configuration reusing the cached values. The values are not cached
+	// by default.
+	CacheTTL time.Duration
+	// PollInterval if positive, is the interval the secrets are polled at, the watcher being notified when
+	// they change, e.g. when they are rotated. The polls use the cached values, so the changes are detected
+	// within the CacheTTL and the PollInterval. The secrets are not polled by default.
+	PollInterval time.Duration
+	// Shutdown if not nil, is called when the provider is shut down, e.g. to close the clients of the
+	// secret store.
+	Shutdown func(ctx context.Context) error
+}
+
+type provider struct {
+	scheme   string
+	fetch    FetchFunc
+	settings Settings
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+type cachedSecret struct {
+	value   string
+	fetched time.Time
+}
+
+// New returns a new provider that retrieves the secrets of the given scheme with the given FetchFunc, the
+// "uri" following:
+//
+//	secret-uri	= scheme ":" secret-id [ "#" json-key ]
+//
+// The value of the secret is retrieved as an opaque string, so it is redacted from the effective
+// configuration, see confmap.WithRetrievedOpaque. With the "json-key", the secret is a JSON object, and the
+// value retrieved is the string of the key, e.g. for the secrets holding several credentials.
+//
+// This is used by the secretsmanager, gcpsecret and azurekeyvault external implementations.
+func New(scheme string, fetch FetchFunc, set Settings) confmap.Provider {
+	return &provider{scheme: scheme, fetch: fetch, settings: set, cache: map[string]cachedSecret{}}
+}
+
+func (sp *provider) Retrieve(ctx context.Context, uri string, watcher confmap.WatcherFunc) (*confmap.Retrieved, error) {
+	if !strings.HasPrefix(uri, sp.scheme+":") {
+		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, sp.scheme)
+	}
+	id, key, _ := strings.Cut(uri[len(sp.scheme)+1:], "#")
+	if id == "" {
+		return nil, fmt.Errorf("invalid uri %q: the secret id is empty", uri)
+	}
+
+	secret, err := sp.get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the secret %q: %w", id, err)
+	}
+	value, err := selectKey(secret, key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret %q: %w", id, err)
+	}
+
+	if watcher == nil || sp.settings.PollInterval <= 0 {
+		return confmap.NewRetrieved(value, confmap.WithRetrievedOpaque())
+	}
+	pollCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sp.poll(pollCtx, id, secret, watcher)
+	}()
+	closeFunc := func(context.Context) error {
+		cancel()
+		<-done
+		return nil
+	}
+	return confmap.NewRetrieved(value, confmap.WithRetrievedOpaque(), confmap.WithRetrievedClose(closeFunc))
+}
+
+// get returns the value of the secret, from the cache if fetched within the CacheTTL.
+func (sp *provider) get(ctx context.Context, id string) (string, error) {
+	sp.mu.Lock()
+	cached, ok := sp.cache[id]
+	sp.mu.Unlock()
+	if ok && time.Since(cached.fetched) < sp.settings.CacheTTL {
+		return cached.value, nil
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	value, err := sp.fetch(fetchCtx, id)
+	if err != nil {
+		return "", err
+	}
+	if sp.settings.CacheTTL > 0 {
+		sp.mu.Lock()
+		sp.cache[id] = cachedSecret{value: value, fetched: time.Now()}
+		sp.mu.Unlock()
+	}
+	return value, nil
+}
+
+// poll polls the secret until the context is done, and notifies the watcher once when it changed.
+func (sp *provider) poll(ctx context.Context, id string, last string, watcher confmap.WatcherFunc) {
+	ticker := time.NewTicker(sp.settings.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// The failures to poll are not reported, since the watcher would stop the collector: the
+		// collector keeps running with the current secret, which is polled again later.
+		value, err := sp.get(ctx, id)
+		if err != nil || value == last {
+			continue
+		}
+		watcher(&confmap.ChangeEvent{})
+		return
+	}
+}
+
+// selectKey returns the string of the key of the JSON object of the secret, or the secret if the key is empty.
+func selectKey(secret string, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+	var values map[string]any
+	if err := json.Unmarshal([]byte(secret), &values); err != nil {
+		// The error is not wrapped, since it could include a part of the secret.
+		return "", fmt.Errorf("the secret is not a JSON object, required by the key %q", key)
+	}
+	value, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("the key %q is not found", key)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("the value of the key %q is not a string", key)
+	}
+	return str, nil
+}
+
+func (sp *provider) Scheme() string {
+	return sp.scheme
+}
+
+func (sp *provider) Shutdown(ctx context.Context) error {
+	if sp.settings.Shutdown == nil {
+		return nil
+	}
+	return sp.settings.Shutdown(ctx)
+}
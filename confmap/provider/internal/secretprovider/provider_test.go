@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package secretprovider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/confmap"
+)
+
+// fakeStore is a secret store counting the fetches of the secrets.
+type fakeStore struct {
+	mu      sync.Mutex
+	secrets map[string]string
+	fetches int
+}
+
+func (fs *fakeStore) fetch(_ context.Context, id string) (string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.fetches++
+	secret, ok := fs.secrets[id]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return secret, nil
+}
+
+func (fs *fakeStore) set(id, secret string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.secrets[id] = secret
+}
+
+func TestRetrieve(t *testing.T) {
+	store := &fakeStore{secrets: map[string]string{
+		"api_key": "my-api-key",
+		"db":      `{"username": "otelcol", "port": 5432}`,
+	}}
+	p := New("fake", store.fetch, Settings{})
+	assert.Equal(t, "fake", p.Scheme())
+
+	ret, err := p.Retrieve(context.Background(), "fake:api_key", nil)
+	require.NoError(t, err)
+	value, err := ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "my-api-key", value)
+	assert.True(t, ret.IsOpaque())
+
+	ret, err = p.Retrieve(context.Background(), "fake:db#username", nil)
+	require.NoError(t, err)
+	value, err = ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "otelcol", value)
+	// The values are not cached by default.
+	assert.Equal(t, 2, store.fetches)
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestRetrieveErrors(t *testing.T) {
+	store := &fakeStore{secrets: map[string]string{
+		"api_key": "my-api-key",
+		"db":      `{"username": "otelcol", "port": 5432}`,
+	}}
+	p := New("fake", store.fetch, Settings{})
+	tests := []struct {
+		uri string
+		err string
+	}{
+		{uri: "other:api_key", err: `"other:api_key" uri is not supported by "fake" provider`},
+		{uri: "fake:", err: `invalid uri "fake:": the secret id is empty`},
+		{uri: "fake:unknown", err: `unable to retrieve the secret "unknown": secret not found`},
+		{uri: "fake:api_key#username", err: `invalid secret "api_key": the secret is not a JSON object, required by the key "username"`},
+		{uri: "fake:db#password", err: `invalid secret "db": the key "password" is not found`},
+		{uri: "fake:db#port", err: `invalid secret "db": the value of the key "port" is not a string`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			_, err := p.Retrieve(context.Background(), tt.uri, nil)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	store := &fakeStore{secrets: map[string]string{"db": `{"username": "otelcol", "password": "my-password"}`}}
+	p := New("fake", store.fetch, Settings{CacheTTL: time.Hour})
+	for _, uri := range []string{"fake:db#username", "fake:db#password", "fake:db#username"} {
+		_, err := p.Retrieve(context.Background(), uri, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, store.fetches)
+}
+
+func TestPoll(t *testing.T) {
+	store := &fakeStore{secrets: map[string]string{"api_key": "my-api-key"}}
+	p := New("fake", store.fetch, Settings{PollInterval: time.Millisecond})
+
+	changed := make(chan struct{})
+	watcher := func(event *confmap.ChangeEvent) {
+		assert.NoError(t, event.Error)
+		close(changed)
+	}
+	ret, err := p.Retrieve(context.Background(), "fake:api_key", watcher)
+	require.NoError(t, err)
+
+	store.set("api_key", "my-rotated-api-key")
+	select {
+	case <-changed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the watcher was not notified of the rotation of the secret")
+	}
+	assert.NoError(t, ret.Close(context.Background()))
+}
+
+func TestPollClose(t *testing.T) {
+	store := &fakeStore{secrets: map[string]string{"api_key": "my-api-key"}}
+	p := New("fake", store.fetch, Settings{PollInterval: time.Millisecond})
+	watcher := func(*confmap.ChangeEvent) {
+		t.Error("the watcher must not be notified if the secret did not change")
+	}
+	ret, err := p.Retrieve(context.Background(), "fake:api_key", watcher)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, ret.Close(context.Background()))
+}
+
+func TestFetchTimeout(t *testing.T) {
+	p := New("fake", func(ctx context.Context, _ string) (string, error) {
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(fetchTimeout), deadline, time.Second)
+		return "my-api-key", nil
+	}, Settings{})
+	_, err := p.Retrieve(context.Background(), "fake:api_key", nil)
+	require.NoError(t, err)
+}
+
+func TestShutdown(t *testing.T) {
+	assert.NoError(t, New("fake", nil, Settings{}).Shutdown(context.Background()))
+
+	var shutdown bool
+	p := New("fake", nil, Settings{Shutdown: func(context.Context) error {
+		shutdown = true
+		return nil
+	}})
+	assert.NoError(t, p.Shutdown(context.Background()))
+	assert.True(t, shutdown)
+}
@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "go.opentelemetry.io/collector/confmap/provider/internal"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// unmarshalTOML parses the TOML document into a map, see https://toml.io/en/v1.0.0. The values are converted
+// to the types of the YAML values: the integers to int, and the dates and times, which have no equivalent,
+// to strings.
+func unmarshalTOML(data []byte) (map[string]any, error) {
+	var conf map[string]any
+	if err := toml.Unmarshal(data, &conf); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, _ := decodeErr.Position()
+			return nil, fmt.Errorf("toml: line %d: %s", row, strings.TrimPrefix(decodeErr.Error(), "toml: "))
+		}
+		return nil, err
+	}
+	if conf == nil {
+		return map[string]any{}, nil
+	}
+	return convertTOMLValue(conf).(map[string]any), nil
+}
+
+// convertTOMLValue converts the TOML value to the type of the equivalent YAML value.
+func convertTOMLValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, val := range v {
+			v[key] = convertTOMLValue(val)
+		}
+		return v
+	case []any:
+		for i, val := range v {
+			v[i] = convertTOMLValue(val)
+		}
+		return v
+	case int64:
+		return int(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case toml.LocalDate, toml.LocalTime, toml.LocalDateTime:
+		return fmt.Sprint(v)
+	default:
+		return v
+	}
+}
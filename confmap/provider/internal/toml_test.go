@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalTOML(t *testing.T) {
+	tests := []struct {
+		name     string
+		toml     string
+		expected map[string]any
+	}{
+		{
+			name: "key values",
+			toml: `# comment
+string = "value" # comment
+literal = 'C:\path'
+int = -1_000
+hex = 0xff
+octal = 0o17
+binary = 0b101
+float = 6.25e-1
+bool = false
+"quoted key" = 1
+'literal key' = 2
+`,
+			expected: map[string]any{
+				"string": "value", "literal": `C:\path`, "int": -1000, "hex": 255, "octal": 15, "binary": 5,
+				"float": 0.625, "bool": false, "quoted key": 1, "literal key": 2,
+			},
+		},
+		{
+			name:     "escapes",
+			toml:     `string = "tab\tquote\" backslash\\ \u00e9\U0001F600"`,
+			expected: map[string]any{"string": "tab\tquote\" backslash\\ é😀"},
+		},
+		{
+			name: "multi-line strings",
+			toml: "basic = \"\"\"\nline 1\nline 2 \\\n    continued\"\"\"\nquotes = \"\"\"\"quoted\"\"\"\"\nliteral = '''\n\\n is not escaped\n'''\n",
+			expected: map[string]any{
+				"basic":   "line 1\nline 2 continued",
+				"quotes":  `"quoted"`,
+				"literal": "\\n is not escaped\n",
+			},
+		},
+		{
+			name: "dates and times",
+			toml: "odt = 1979-05-27T07:32:00Z\nspace = 1979-05-27 07:32:00.999-07:00\nldt = 1979-05-27T07:32:00\ndate = 1979-05-27\ntime = 07:32:00\n",
+			expected: map[string]any{
+				"odt": "1979-05-27T07:32:00Z", "space": "1979-05-27T07:32:00.999-07:00", "ldt": "1979-05-27T07:32:00",
+				"date": "1979-05-27", "time": "07:32:00",
+			},
+		},
+		{
+			name: "arrays",
+			toml: "empty = []\nnested = [[1, 2], [\"a\"]]\nmultiline = [\n  1, # comment\n  2,\n]\n",
+			expected: map[string]any{
+				"empty":     []any{},
+				"nested":    []any{[]any{1, 2}, []any{"a"}},
+				"multiline": []any{1, 2},
+			},
+		},
+		{
+			name: "tables",
+			toml: `[exporters.otlp]
+endpoint = "localhost:4317"
+tls.insecure = true
+headers = { "x-key" = "value", nested.key = 1 }
+
+[exporters]
+debug = {}
+
+[processors.batch]
+`,
+			expected: map[string]any{
+				"exporters": map[string]any{
+					"otlp": map[string]any{
+						"endpoint": "localhost:4317",
+						"tls":      map[string]any{"insecure": true},
+						"headers":  map[string]any{"x-key": "value", "nested": map[string]any{"key": 1}},
+					},
+					"debug": map[string]any{},
+				},
+				"processors": map[string]any{"batch": map[string]any{}},
+			},
+		},
+		{
+			name: "arrays of tables",
+			toml: `[[service.extensions]]
+name = "a"
+[service.extensions.settings]
+key = 1
+
+[[service.extensions]]
+name = "b"
+[service.extensions.settings]
+key = 2
+`,
+			expected: map[string]any{
+				"service": map[string]any{
+					"extensions": []any{
+						map[string]any{"name": "a", "settings": map[string]any{"key": 1}},
+						map[string]any{"name": "b", "settings": map[string]any{"key": 2}},
+					},
+				},
+			},
+		},
+		{
+			name:     "crlf",
+			toml:     "[table]\r\nkey = 1\r\n",
+			expected: map[string]any{"table": map[string]any{"key": 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, err := unmarshalTOML([]byte(tt.toml))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, conf)
+		})
+	}
+}
+
+func TestUnmarshalTOMLSpecialFloats(t *testing.T) {
+	conf, err := unmarshalTOML([]byte("inf = inf\nneg = -inf\nnan = nan\n"))
+	require.NoError(t, err)
+	assert.Equal(t, math.Inf(1), conf["inf"])
+	assert.Equal(t, math.Inf(-1), conf["neg"])
+	assert.True(t, math.IsNaN(conf["nan"].(float64)))
+}
+
+func TestUnmarshalTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		toml        string
+		expectedErr string
+	}{
+		{name: "missing value", toml: "key =\n", expectedErr: "toml: line 1: incomplete number"},
+		{name: "missing equal", toml: "key 1", expectedErr: "toml: line 1: expected character ="},
+		{name: "duplicate key", toml: "key = 1\nkey = 2", expectedErr: "toml: key key is already defined"},
+		{name: "duplicate table", toml: "[a]\n[b]\n[a]", expectedErr: "toml: table a already exists"},
+		{name: "key not a table", toml: "a = 1\n[a.b]", expectedErr: "toml: expected a to be a table, not a value"},
+		{name: "dotted keys table", toml: "a.b = 1\n[a]\nc = 2\n", expectedErr: "toml: table a already exists"},
+		{name: "nested dotted keys table", toml: "[a]\nb.c = 1\n[a.b]\nd = 2\n", expectedErr: "toml: table b already exists"},
+		{name: "inline table reopened by a table", toml: "a = {b = 1}\n[a]\nc = 2\n", expectedErr: "toml: key a should be a table, not a value"},
+		{name: "inline table reopened by a dotted key", toml: "a = {b = 1}\na.c = 2\n", expectedErr: "toml: expected a to be a table, not a value"},
+		{name: "unterminated string", toml: "key = \"value\nother = 1", expectedErr: "toml: line 1: basic strings cannot have new lines"},
+		{name: "unterminated array", toml: "key = [1, 2", expectedErr: "toml: line 1: expected character ] but the document ended here"},
+		{name: "invalid escape", toml: `key = "\x"`, expectedErr: "toml: line 1: invalid escaped character U+0078 'x'"},
+		{name: "leading zeros", toml: "key = 01", expectedErr: "toml: line 1: leading zero not allowed on decimal number"},
+		{name: "invalid underscore", toml: "key = 1__0", expectedErr: "toml: line 1: number must have at least one digit between underscores"},
+		{name: "two values", toml: "key = 1 2", expectedErr: "toml: line 1: expected newline but got U+0032 '2'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := unmarshalTOML([]byte(tt.toml))
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
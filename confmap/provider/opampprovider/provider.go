@@ -0,0 +1,382 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opampprovider // import "go.opentelemetry.io/collector/confmap/provider/opampprovider"
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+
+	"go.opentelemetry.io/collector/confmap"
+)
+
+const (
+	schemeName = "opamp"
+
+	defaultPollInterval = 30 * time.Second
+	defaultTimeout      = 10 * time.Second
+	protobufContentType = "application/x-protobuf"
+)
+
+type provider struct {
+	client       *http.Client
+	headers      map[string]string
+	pollInterval time.Duration
+	instanceUID  []byte
+	attributes   map[string]string
+
+	mu          sync.Mutex
+	sequenceNum uint64
+	// status is the status of the last remote config received, nil if none.
+	status *protobufs.RemoteConfigStatus
+	// applied is the last remote config the collector started with, empty if none.
+	applied map[string]any
+	// hasApplied is set once the collector started with a remote config.
+	hasApplied bool
+	// pending is the remote config received while polling, applied by the next Retrieve.
+	pending *protobufs.AgentRemoteConfig
+	// endpoint is the endpoint of the last Retrieve, notified of the disconnection of the agent on Shutdown.
+	endpoint string
+}
+
+// Option configures the provider.
+type Option func(*provider)
+
+// WithHeaders adds the given headers to the requests, e.g. the "Authorization" header.
+func WithHeaders(headers map[string]string) Option {
+	return func(p *provider) {
+		p.headers = headers
+	}
+}
+
+// WithTimeout sets the timeout of the requests sent to the server, 10s by default.
+func WithTimeout(timeout time.Duration) Option {
+	return func(p *provider) {
+		p.client.Timeout = timeout
+	}
+}
+
+// WithPollInterval sets the interval the server is polled at for remote config changes, 30s by default.
+func WithPollInterval(interval time.Duration) Option {
+	return func(p *provider) {
+		p.pollInterval = interval
+	}
+}
+
+// WithInstanceUID sets the instance UID identifying the collector with the server, 16 random bytes by
+// default. It should be persisted by the collectors restarting, for the server to recognize them.
+func WithInstanceUID(uid []byte) Option {
+	return func(p *provider) {
+		p.instanceUID = uid
+	}
+}
+
+// WithIdentifyingAttributes sets the identifying attributes of the agent description reported to the server,
+// e.g. "service.name", "service.version" or "service.instance.id".
+func WithIdentifyingAttributes(attributes map[string]string) Option {
+	return func(p *provider) {
+		p.attributes = attributes
+	}
+}
+
+// New returns a new confmap.Provider that retrieves the configuration from an OpAMP server, with the
+// plain HTTP transport of the protocol.
+//
+// This Provider supports "opamp" scheme, and can be called with a "uri" that follows:
+//
+//	opamp-uri = "opamp:" http-uri
+//
+// Examples:
+// `opamp:https://opamp.example.com:4320/v1/opamp`
+// `opamp:http://localhost:4320/v1/opamp`
+//
+// The config files of the remote config offered by the server, in YAML, are merged in the order of their
+// names. The server is polled for changes, the watcher being notified, which reloads the collector, when
+// the remote config changed. The status of the remote config is reported to the server: applying once it
+// is parsed, then applied once the collector started with it, or failed with the error. The remote configs
+// failing to be parsed are ignored, the last remote config applied being kept, except when no remote config
+// was applied yet, which fails the Retrieve.
+func New(opts ...Option) confmap.Provider {
+	p := &provider{
+		client:       &http.Client{Timeout: defaultTimeout},
+		pollInterval: defaultPollInterval,
+		applied:      map[string]any{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if len(p.instanceUID) == 0 {
+		p.instanceUID = make([]byte, 16)
+		_, _ = rand.Read(p.instanceUID)
+	}
+	return p
+}
+
+func (p *provider) Retrieve(ctx context.Context, uri string, watcher confmap.WatcherFunc) (*confmap.Retrieved, error) {
+	if !strings.HasPrefix(uri, schemeName+":") {
+		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, schemeName)
+	}
+	endpoint := uri[len(schemeName)+1:]
+
+	p.mu.Lock()
+	p.endpoint = endpoint
+	remoteConfig := p.pending
+	p.pending = nil
+	p.mu.Unlock()
+	if remoteConfig == nil {
+		resp, err := p.send(ctx, endpoint, false)
+		if err != nil {
+			return nil, err
+		}
+		remoteConfig = resp.RemoteConfig
+	}
+	conf, appliedFunc, err := p.apply(ctx, endpoint, remoteConfig)
+	if err != nil {
+		return nil, err
+	}
+	opts := []confmap.RetrievedOption{confmap.WithRetrievedApplied(appliedFunc)}
+
+	if watcher == nil || p.pollInterval <= 0 {
+		return confmap.NewRetrieved(conf, opts...)
+	}
+	pollCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.poll(pollCtx, endpoint, watcher)
+	}()
+	closeFunc := func(context.Context) error {
+		cancel()
+		<-done
+		return nil
+	}
+	retrieved, err := confmap.NewRetrieved(conf, append(opts, confmap.WithRetrievedClose(closeFunc))...)
+	if err != nil {
+		_ = closeFunc(ctx)
+		return nil, err
+	}
+	return retrieved, nil
+}
+
+// apply returns the configuration of the given remote config, reporting it as applying to the server, and the
+// func reporting whether the collector started with it. It returns the last remote config applied if the given
+// one is nil, unchanged, or failed.
+func (p *provider) apply(ctx context.Context, endpoint string, remoteConfig *protobufs.AgentRemoteConfig) (map[string]any, confmap.AppliedFunc, error) {
+	p.mu.Lock()
+	if remoteConfig == nil || (p.status != nil && bytes.Equal(p.status.LastRemoteConfigHash, remoteConfig.ConfigHash) &&
+		p.status.Status != protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING) {
+		defer p.mu.Unlock()
+		if remoteConfig != nil && !p.hasApplied && p.status.Status == protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED {
+			return nil, nil, fmt.Errorf("failed to apply the remote config: %s", p.status.ErrorMessage)
+		}
+		return p.applied, nil, nil
+	}
+	conf, err := parseRemoteConfig(remoteConfig)
+	if err != nil {
+		p.status = &protobufs.RemoteConfigStatus{
+			LastRemoteConfigHash: remoteConfig.ConfigHash,
+			Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED,
+			ErrorMessage:         err.Error(),
+		}
+	} else {
+		p.status = &protobufs.RemoteConfigStatus{
+			LastRemoteConfigHash: remoteConfig.ConfigHash,
+			Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING,
+		}
+	}
+	hasApplied, applied := p.hasApplied, p.applied
+	p.mu.Unlock()
+
+	// The status is reported on a best effort basis, the next requests reporting it again.
+	_, _ = p.send(ctx, endpoint, false)
+	if err != nil {
+		if !hasApplied {
+			return nil, nil, fmt.Errorf("failed to apply the remote config: %w", err)
+		}
+		return applied, nil, nil
+	}
+	return conf, func(ctx context.Context, err error) {
+		p.reportApplied(ctx, endpoint, remoteConfig.ConfigHash, conf, err)
+	}, nil
+}
+
+// reportApplied reports to the server whether the collector started with the remote config of the given hash,
+// unless another remote config was received since.
+func (p *provider) reportApplied(ctx context.Context, endpoint string, hash []byte, conf map[string]any, err error) {
+	p.mu.Lock()
+	if p.status == nil || !bytes.Equal(p.status.LastRemoteConfigHash, hash) ||
+		p.status.Status != protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING {
+		p.mu.Unlock()
+		return
+	}
+	if err != nil {
+		p.status = &protobufs.RemoteConfigStatus{
+			LastRemoteConfigHash: hash,
+			Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED,
+			ErrorMessage:         err.Error(),
+		}
+	} else {
+		p.status = &protobufs.RemoteConfigStatus{
+			LastRemoteConfigHash: hash,
+			Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED,
+		}
+		p.applied = conf
+		p.hasApplied = true
+	}
+	p.mu.Unlock()
+
+	_, _ = p.send(ctx, endpoint, false)
+}
+
+// poll polls the server until the context is done, and notifies the watcher once when the remote config changed.
+func (p *provider) poll(ctx context.Context, endpoint string, watcher confmap.WatcherFunc) {
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// The failures to poll are not reported, since the watcher would stop the collector: the
+		// collector keeps running with the current configuration, and the server is polled again later.
+		resp, err := p.send(ctx, endpoint, false)
+		if err != nil || resp.RemoteConfig == nil {
+			continue
+		}
+		p.mu.Lock()
+		changed := p.status == nil || !bytes.Equal(p.status.LastRemoteConfigHash, resp.RemoteConfig.ConfigHash)
+		if changed {
+			p.pending = resp.RemoteConfig
+		}
+		p.mu.Unlock()
+		if changed {
+			watcher(&confmap.ChangeEvent{})
+			return
+		}
+	}
+}
+
+// send sends the state of the agent to the server, and returns the response of the server.
+func (p *provider) send(ctx context.Context, endpoint string, disconnect bool) (*protobufs.ServerToAgent, error) {
+	p.mu.Lock()
+	msg := &protobufs.AgentToServer{
+		InstanceUid: p.instanceUID,
+		SequenceNum: p.sequenceNum,
+		Capabilities: uint64(protobufs.AgentCapabilities_AgentCapabilities_ReportsStatus |
+			protobufs.AgentCapabilities_AgentCapabilities_AcceptsRemoteConfig |
+			protobufs.AgentCapabilities_AgentCapabilities_ReportsRemoteConfig),
+		RemoteConfigStatus: p.status,
+	}
+	p.sequenceNum++
+	p.mu.Unlock()
+	if len(p.attributes) > 0 {
+		msg.AgentDescription = &protobufs.AgentDescription{}
+		for _, k := range sortedKeys(p.attributes) {
+			msg.AgentDescription.IdentifyingAttributes = append(msg.AgentDescription.IdentifyingAttributes, &protobufs.KeyValue{
+				Key:   k,
+				Value: &protobufs.AnyValue{Value: &protobufs.AnyValue_StringValue{StringValue: p.attributes[k]}},
+			})
+		}
+	}
+	if disconnect {
+		msg.AgentDisconnect = &protobufs.AgentDisconnect{}
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal the OpAMP request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the OpAMP request for %q: %w", endpoint, err)
+	}
+	req.Header.Set("Content-Type", protobufContentType)
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send the OpAMP request to %q: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to send the OpAMP request to %q. status code: %d", endpoint, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read the OpAMP response from %q: %w", endpoint, err)
+	}
+
+	serverMsg := &protobufs.ServerToAgent{}
+	if err = proto.Unmarshal(body, serverMsg); err != nil {
+		return nil, fmt.Errorf("invalid OpAMP response from %q: %w", endpoint, err)
+	}
+	if serverMsg.ErrorResponse != nil {
+		return nil, fmt.Errorf("the OpAMP server %q returned an error: %s", endpoint, serverMsg.ErrorResponse.ErrorMessage)
+	}
+	return serverMsg, nil
+}
+
+// parseRemoteConfig merges the YAML config files of the remote config in the order of their names.
+func parseRemoteConfig(remoteConfig *protobufs.AgentRemoteConfig) (map[string]any, error) {
+	conf := confmap.New()
+	files := remoteConfig.GetConfig().GetConfigMap()
+	for _, name := range sortedKeys(files) {
+		file := files[name]
+		if file.GetContentType() != "" && !strings.Contains(file.GetContentType(), "yaml") {
+			return nil, fmt.Errorf("config file %q: unsupported content type %q", name, file.GetContentType())
+		}
+		var rawConf any
+		if err := yaml.Unmarshal(file.GetBody(), &rawConf); err != nil {
+			return nil, fmt.Errorf("config file %q: %w", name, err)
+		}
+		if rawConf == nil {
+			continue
+		}
+		fileConf, ok := rawConf.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("config file %q: the config must be a map", name)
+		}
+		if err := conf.Merge(confmap.NewFromStringMap(fileConf)); err != nil {
+			return nil, fmt.Errorf("config file %q: %w", name, err)
+		}
+	}
+	return conf.ToStringMap(), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (*provider) Scheme() string {
+	return schemeName
+}
+
+// Shutdown notifies the server of the disconnection of the agent, on a best effort basis.
+func (p *provider) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	endpoint := p.endpoint
+	p.mu.Unlock()
+	if endpoint != "" {
+		_, _ = p.send(ctx, endpoint, true)
+	}
+	return nil
+}
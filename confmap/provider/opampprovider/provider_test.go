@@ -0,0 +1,266 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opampprovider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+)
+
+// fakeServer is an OpAMP server offering the remote config set.
+type fakeServer struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	remoteConfig *protobufs.AgentRemoteConfig
+	errorMessage string
+	messages     []*protobufs.AgentToServer
+	authHeaders  []string
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	fs := &fakeServer{}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, protobufContentType, r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		msg := &protobufs.AgentToServer{}
+		require.NoError(t, proto.Unmarshal(body, msg))
+
+		fs.mu.Lock()
+		defer fs.mu.Unlock()
+		fs.messages = append(fs.messages, msg)
+		fs.authHeaders = append(fs.authHeaders, r.Header.Get("Authorization"))
+		resp := &protobufs.ServerToAgent{InstanceUid: msg.InstanceUid, RemoteConfig: fs.remoteConfig}
+		if fs.errorMessage != "" {
+			resp.ErrorResponse = &protobufs.ServerErrorResponse{ErrorMessage: fs.errorMessage}
+		}
+		data, err := proto.Marshal(resp)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", protobufContentType)
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+func (fs *fakeServer) setRemoteConfig(hash string, files map[string]string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.remoteConfig = &protobufs.AgentRemoteConfig{
+		ConfigHash: []byte(hash),
+		Config:     &protobufs.AgentConfigMap{ConfigMap: map[string]*protobufs.AgentConfigFile{}},
+	}
+	for name, body := range files {
+		fs.remoteConfig.Config.ConfigMap[name] = &protobufs.AgentConfigFile{Body: []byte(body), ContentType: "text/yaml"}
+	}
+}
+
+func (fs *fakeServer) lastMessage() *protobufs.AgentToServer {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.messages[len(fs.messages)-1]
+}
+
+func (fs *fakeServer) lastStatus() (protobufs.RemoteConfigStatuses, string, string) {
+	status := fs.lastMessage().GetRemoteConfigStatus()
+	return status.GetStatus(), string(status.GetLastRemoteConfigHash()), status.GetErrorMessage()
+}
+
+func TestValidateProviderScheme(t *testing.T) {
+	assert.NoError(t, confmaptest.ValidateProviderScheme(New()))
+}
+
+func TestUnsupportedScheme(t *testing.T) {
+	p := New()
+	_, err := p.Retrieve(context.Background(), "https://localhost", nil)
+	assert.Error(t, err)
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestRetrieve(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.setRemoteConfig("hash-1", map[string]string{
+		"a.yaml": "exporters:\n  otlp:\n    endpoint: localhost:4317\n",
+		"b.yaml": "exporters:\n  otlp:\n    endpoint: collector:4317\nreceivers:\n  otlp:\n",
+	})
+
+	p := New(
+		WithInstanceUID([]byte("0123456789abcdef")),
+		WithHeaders(map[string]string{"Authorization": "Bearer token"}),
+		WithIdentifyingAttributes(map[string]string{"service.name": "otelcol"}),
+	)
+	ret, err := p.Retrieve(context.Background(), "opamp:"+fs.URL, nil)
+	require.NoError(t, err)
+	conf, err := ret.AsConf()
+	require.NoError(t, err)
+	// The config files are merged in the order of their names.
+	assert.Equal(t, map[string]any{
+		"exporters": map[string]any{"otlp": map[string]any{"endpoint": "collector:4317"}},
+		"receivers": map[string]any{"otlp": nil},
+	}, conf.ToStringMap())
+
+	msg := fs.lastMessage()
+	assert.Equal(t, []byte("0123456789abcdef"), msg.InstanceUid)
+	assert.Equal(t, uint64(1), msg.SequenceNum)
+	assert.Equal(t, uint64(protobufs.AgentCapabilities_AgentCapabilities_ReportsStatus|
+		protobufs.AgentCapabilities_AgentCapabilities_AcceptsRemoteConfig|
+		protobufs.AgentCapabilities_AgentCapabilities_ReportsRemoteConfig), msg.Capabilities)
+	require.Len(t, msg.GetAgentDescription().GetIdentifyingAttributes(), 1)
+	assert.Equal(t, "service.name", msg.AgentDescription.IdentifyingAttributes[0].Key)
+	assert.Equal(t, "otelcol", msg.AgentDescription.IdentifyingAttributes[0].Value.GetStringValue())
+	assert.Equal(t, []string{"Bearer token", "Bearer token"}, fs.authHeaders)
+	// The remote config is only reported as applied once the collector started with it.
+	status, hash, _ := fs.lastStatus()
+	assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING, status)
+	assert.Equal(t, "hash-1", hash)
+	ret.Applied(context.Background(), nil)
+	status, hash, _ = fs.lastStatus()
+	assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, status)
+	assert.Equal(t, "hash-1", hash)
+
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.NotNil(t, fs.lastMessage().AgentDisconnect)
+}
+
+func TestRetrieveWithoutRemoteConfig(t *testing.T) {
+	fs := newFakeServer(t)
+
+	p := New()
+	ret, err := p.Retrieve(context.Background(), "opamp:"+fs.URL, nil)
+	require.NoError(t, err)
+	conf, err := ret.AsConf()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{}, conf.ToStringMap())
+	assert.Len(t, fs.lastMessage().InstanceUid, 16)
+	assert.Nil(t, fs.lastMessage().RemoteConfigStatus)
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestRetrieveInvalidRemoteConfig(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.setRemoteConfig("hash-1", map[string]string{"config.yaml": "[invalid"})
+
+	p := New()
+	_, err := p.Retrieve(context.Background(), "opamp:"+fs.URL, nil)
+	assert.ErrorContains(t, err, `failed to apply the remote config: config file "config.yaml"`)
+	status, _, errorMessage := fs.lastStatus()
+	assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, status)
+	assert.Contains(t, errorMessage, `config file "config.yaml"`)
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestRetrieveServerError(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.errorMessage = "unknown agent"
+
+	p := New()
+	_, err := p.Retrieve(context.Background(), "opamp:"+fs.URL, nil)
+	assert.ErrorContains(t, err, "returned an error: unknown agent")
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestRetrieveStatusCode(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	p := New()
+	_, err := p.Retrieve(context.Background(), "opamp:"+ts.URL, nil)
+	assert.ErrorContains(t, err, "status code: 404")
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestPollRemoteConfigChanged(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.setRemoteConfig("hash-1", map[string]string{"config.yaml": "key: value"})
+
+	p := New(WithPollInterval(10 * time.Millisecond))
+	events := make(chan *confmap.ChangeEvent, 1)
+	watcher := func(event *confmap.ChangeEvent) { events <- event }
+	ret, err := p.Retrieve(context.Background(), "opamp:"+fs.URL, watcher)
+	require.NoError(t, err)
+	ret.Applied(context.Background(), nil)
+
+	// An invalid remote config is reported as failed, the last one applied being kept without reload.
+	fs.setRemoteConfig("hash-2", map[string]string{"config.yaml": "[invalid"})
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change of the remote config was not notified")
+	}
+	require.NoError(t, ret.Close(context.Background()))
+	ret, err = p.Retrieve(context.Background(), "opamp:"+fs.URL, watcher)
+	require.NoError(t, err)
+	conf, err := ret.AsConf()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"key": "value"}, conf.ToStringMap())
+	status, _, _ := fs.lastStatus()
+	assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, status)
+
+	fs.setRemoteConfig("hash-3", map[string]string{"config.yaml": "key: changed"})
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change of the remote config was not notified")
+	}
+	require.NoError(t, ret.Close(context.Background()))
+	ret, err = p.Retrieve(context.Background(), "opamp:"+fs.URL, nil)
+	require.NoError(t, err)
+	conf, err = ret.AsConf()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"key": "changed"}, conf.ToStringMap())
+	ret.Applied(context.Background(), nil)
+	status, hash, _ := fs.lastStatus()
+	assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, status)
+	assert.Equal(t, "hash-3", hash)
+	assert.Empty(t, events)
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestRetrieveAppliedFailed(t *testing.T) {
+	fs := newFakeServer(t)
+	fs.setRemoteConfig("hash-1", map[string]string{"config.yaml": "key: value"})
+
+	p := New()
+	ret, err := p.Retrieve(context.Background(), "opamp:"+fs.URL, nil)
+	require.NoError(t, err)
+	ret.Applied(context.Background(), errors.New("invalid configuration"))
+	status, hash, errorMessage := fs.lastStatus()
+	assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, status)
+	assert.Equal(t, "hash-1", hash)
+	assert.Equal(t, "invalid configuration", errorMessage)
+
+	// The remote config failed is not retried.
+	_, err = p.Retrieve(context.Background(), "opamp:"+fs.URL, nil)
+	assert.ErrorContains(t, err, "failed to apply the remote config: invalid configuration")
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestRetrieveTimeout(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	p := New(WithTimeout(10 * time.Millisecond))
+	_, err := p.Retrieve(context.Background(), "opamp:"+ts.URL, nil)
+	assert.ErrorContains(t, err, "Client.Timeout exceeded")
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
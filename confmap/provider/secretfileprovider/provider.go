@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package secretfileprovider // import "go.opentelemetry.io/collector/confmap/provider/secretfileprovider"
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/collector/confmap"
+)
+
+const schemeName = "secretfile"
+
+type provider struct{}
+
+// New returns a new confmap.Provider that reads secrets from files, e.g. the secrets mounted by Kubernetes
+// or Docker. The content of the file is retrieved as an opaque string, without its trailing new lines, so
+// it is redacted from the effective configuration, see confmap.WithRetrievedOpaque.
+//
+// This Provider supports "secretfile" scheme, and can be called with a "uri" that follows:
+//
+//	secretfile-uri	= "secretfile:" local-path
+//
+// The "local-path" can be relative or absolute, and it can be any OS supported format, see the "file" scheme.
+//
+// Examples:
+// `${secretfile:/run/secrets/api_key}` - the whole value is the secret
+// `Bearer ${secretfile:/run/secrets/token}` - the secret is embedded in the value
+func New() confmap.Provider {
+	return &provider{}
+}
+
+func (fmp *provider) Retrieve(_ context.Context, uri string, _ confmap.WatcherFunc) (*confmap.Retrieved, error) {
+	if !strings.HasPrefix(uri, schemeName+":") {
+		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, schemeName)
+	}
+
+	// Clean the path before using it.
+	content, err := os.ReadFile(filepath.Clean(uri[len(schemeName)+1:]))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the secret file %v: %w", uri, err)
+	}
+
+	return confmap.NewRetrieved(strings.TrimRight(string(content), "\r\n"), confmap.WithRetrievedOpaque())
+}
+
+func (*provider) Scheme() string {
+	return schemeName
+}
+
+func (*provider) Shutdown(context.Context) error {
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package secretfileprovider
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+)
+
+const secretFileSchemePrefix = schemeName + ":"
+
+func TestValidateProviderScheme(t *testing.T) {
+	assert.NoError(t, confmaptest.ValidateProviderScheme(New()))
+}
+
+func TestUnsupportedScheme(t *testing.T) {
+	fp := New()
+	_, err := fp.Retrieve(context.Background(), "file:"+filepath.Join("testdata", "password"), nil)
+	assert.Error(t, err)
+	assert.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestNonExistent(t *testing.T) {
+	fp := New()
+	_, err := fp.Retrieve(context.Background(), secretFileSchemePrefix+filepath.Join("testdata", "non-existent"), nil)
+	assert.Error(t, err)
+	require.NoError(t, fp.Shutdown(context.Background()))
+}
+
+func TestRetrieveSecret(t *testing.T) {
+	fp := New()
+	ret, err := fp.Retrieve(context.Background(), secretFileSchemePrefix+filepath.Join("testdata", "password"), nil)
+	require.NoError(t, err)
+	assert.True(t, ret.IsOpaque())
+	raw, err := ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", raw)
+	require.NoError(t, fp.Shutdown(context.Background()))
+}
@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package secretsmanagerprovider // import "go.opentelemetry.io/collector/confmap/provider/secretsmanagerprovider"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/provider/internal/secretprovider"
+)
+
+const schemeName = "secretsmanager"
+
+type provider struct {
+	settings secretprovider.Settings
+	region   string
+	endpoint string
+
+	mu sync.Mutex
+	// awsConfig is the configuration of the environment, loaded once, caching the credentials.
+	awsConfig *aws.Config
+}
+
+// Option configures the provider.
+type Option func(*provider)
+
+// WithRegion sets the region of the secrets, by default the region of the ARN of the secrets, or the region
+// of the AWS_REGION or AWS_DEFAULT_REGION environment variables, or else of the profile of the shared config file.
+func WithRegion(region string) Option {
+	return func(p *provider) {
+		p.region = region
+	}
+}
+
+// WithEndpoint sends the requests to the given endpoint, e.g. a VPC endpoint, rather than to the endpoint of
+// the region, "https://secretsmanager.<region>.amazonaws.com".
+func WithEndpoint(endpoint string) Option {
+	return func(p *provider) {
+		p.endpoint = endpoint
+	}
+}
+
+// WithCacheTTL caches the values of the secrets for the given duration, 5 minutes by default, the references
+// to the same secret and the reloads of the configuration reusing the cached values. The values are not
+// cached if the duration is not positive.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(p *provider) {
+		p.settings.CacheTTL = ttl
+	}
+}
+
+// WithPollInterval polls the secrets at the given interval, notifying the watcher, which reloads the
+// collector, when they changed, e.g. when they are rotated. The polls use the cached values, so the changes
+// are detected within the cache TTL and the poll interval.
+func WithPollInterval(interval time.Duration) Option {
+	return func(p *provider) {
+		p.settings.PollInterval = interval
+	}
+}
+
+// New returns a new confmap.Provider that retrieves the secrets from AWS Secrets Manager.
+//
+// This Provider supports "secretsmanager" scheme, and can be called with a "uri" that follows:
+//
+//	secretsmanager-uri	= "secretsmanager:" secret-id [ "#" json-key ]
+//
+// The "secret-id" is the name or the ARN of the secret, the current version of the secret being retrieved
+// as an opaque string, so it is redacted from the effective configuration. With the "json-key", the secret
+// is a JSON object, and the value retrieved is the string of the key.
+//
+// Examples:
+// `${secretsmanager:prod/otelcol/api_key}`
+// `${secretsmanager:arn:aws:secretsmanager:us-east-1:123456789012:secret:prod/otelcol-AbCdEf#api_key}`
+//
+// The requests are authenticated with the default credential chain of the AWS SDK: the credentials of the
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables, or else of the shared
+// credentials and config files, of the AWS_PROFILE environment variable if set, or else of the web identity
+// token file, e.g. with EKS IAM Roles for Service Accounts, or else of the container, e.g. with ECS or EKS
+// Pod Identity, or else of the EC2 instance.
+func New(opts ...Option) confmap.Provider {
+	p := newProvider(opts...)
+	return secretprovider.New(schemeName, p.fetch, p.settings)
+}
+
+func newProvider(opts ...Option) *provider {
+	p := &provider{settings: secretprovider.Settings{CacheTTL: secretprovider.DefaultCacheTTL}}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// fetch returns the value of the current version of the secret.
+func (p *provider) fetch(ctx context.Context, id string) (string, error) {
+	cfg, err := p.loadConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	region := p.region
+	if region == "" {
+		region = regionFromARN(id)
+	}
+	if region == "" {
+		region = cfg.Region
+	}
+	if region == "" {
+		return "", errors.New("the AWS region is not set, see the AWS_REGION environment variable")
+	}
+
+	client := secretsmanager.NewFromConfig(cfg, func(o *secretsmanager.Options) {
+		o.Region = region
+		if p.endpoint != "" {
+			o.BaseEndpoint = aws.String(p.endpoint)
+		}
+	})
+	resp, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(id)})
+	if err != nil {
+		return "", err
+	}
+	if resp.SecretString != nil {
+		return *resp.SecretString, nil
+	}
+	return string(resp.SecretBinary), nil
+}
+
+// loadConfig returns the configuration of the environment, with the region and the credentials, loaded on
+// the first call. The credentials are cached by the configuration until they expire.
+func (p *provider) loadConfig(ctx context.Context) (aws.Config, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.awsConfig != nil {
+		return *p.awsConfig, nil
+	}
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("unable to load the AWS configuration: %w", err)
+	}
+	p.awsConfig = &cfg
+	return cfg, nil
+}
+
+// regionFromARN returns the region of the ARN of the secret, or the empty string if not an ARN.
+func regionFromARN(id string) string {
+	// arn:partition:secretsmanager:region:account-id:secret:name
+	parts := strings.SplitN(id, ":", 5)
+	if len(parts) < 5 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[3]
+}
@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package secretsmanagerprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+)
+
+// newFakeSecretsManager returns a Secrets Manager server returning the given secrets, checking the signature
+// of the requests for the given access key ID and region.
+func newFakeSecretsManager(t *testing.T, accessKeyID, region string, secrets map[string]string) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/"),
+			r.Header.Get("Authorization"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/"+region+"/secretsmanager/aws4_request")
+
+		var req struct {
+			SecretID string `json:"SecretId"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		secret, ok := secrets[req.SecretID]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"Name": req.SecretID, "SecretString": secret})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// setEnv sets the credentials of the environment variables, isolating the tests from the configuration
+// and the credentials of the environment.
+func setEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+	t.Setenv("AWS_ROLE_ARN", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+}
+
+func TestValidateProviderScheme(t *testing.T) {
+	assert.NoError(t, confmaptest.ValidateProviderScheme(New()))
+}
+
+func TestUnsupportedScheme(t *testing.T) {
+	p := New()
+	_, err := p.Retrieve(context.Background(), "https://", nil)
+	assert.Error(t, err)
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestRetrieve(t *testing.T) {
+	setEnv(t)
+	t.Setenv("AWS_REGION", "us-west-2")
+	ts := newFakeSecretsManager(t, "AKIDEXAMPLE", "us-west-2", map[string]string{
+		"prod/api_key": "my-api-key",
+		"prod/db":      `{"username": "otelcol", "password": "my-password"}`,
+	})
+
+	p := New(WithEndpoint(ts.URL))
+	ret, err := p.Retrieve(context.Background(), "secretsmanager:prod/api_key", nil)
+	require.NoError(t, err)
+	value, err := ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "my-api-key", value)
+	assert.True(t, ret.IsOpaque())
+
+	ret, err = p.Retrieve(context.Background(), "secretsmanager:prod/db#password", nil)
+	require.NoError(t, err)
+	value, err = ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "my-password", value)
+
+	_, err = p.Retrieve(context.Background(), "secretsmanager:prod/unknown", nil)
+	assert.ErrorContains(t, err, "StatusCode: 400")
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestRetrieveRegion(t *testing.T) {
+	setEnv(t)
+	arn := "arn:aws:secretsmanager:eu-west-1:123456789012:secret:api_key-AbCdEf"
+	ts := newFakeSecretsManager(t, "AKIDEXAMPLE", "eu-west-1", map[string]string{arn: "my-api-key"})
+
+	p := New(WithEndpoint(ts.URL))
+	_, err := p.Retrieve(context.Background(), "secretsmanager:api_key", nil)
+	assert.ErrorContains(t, err, "the AWS region is not set")
+	ret, err := p.Retrieve(context.Background(), "secretsmanager:"+arn, nil)
+	require.NoError(t, err)
+	value, err := ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "my-api-key", value)
+
+	ts = newFakeSecretsManager(t, "AKIDEXAMPLE", "ap-south-1", map[string]string{"api_key": "my-api-key"})
+	ret, err = New(WithEndpoint(ts.URL), WithRegion("ap-south-1")).Retrieve(context.Background(), "secretsmanager:api_key", nil)
+	require.NoError(t, err)
+	value, err = ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "my-api-key", value)
+}
+
+func TestSharedCredentialsFile(t *testing.T) {
+	setEnv(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_PROFILE", "otelcol")
+	require.NoError(t, os.WriteFile(os.Getenv("AWS_CONFIG_FILE"), []byte("[profile otelcol]\nregion = eu-central-1\n"), 0600))
+	require.NoError(t, os.WriteFile(os.Getenv("AWS_SHARED_CREDENTIALS_FILE"),
+		[]byte("[otelcol]\naws_access_key_id = AKIDPROFILE\naws_secret_access_key = secret\n"), 0600))
+	ts := newFakeSecretsManager(t, "AKIDPROFILE", "eu-central-1", map[string]string{"api_key": "my-api-key"})
+
+	ret, err := New(WithEndpoint(ts.URL)).Retrieve(context.Background(), "secretsmanager:api_key", nil)
+	require.NoError(t, err)
+	value, err := ret.AsRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "my-api-key", value)
+}
+
+func TestWebIdentityCredentials(t *testing.T) {
+	setEnv(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_REGION", "us-east-1")
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("web-identity-token"), 0600))
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", tokenFile)
+	t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/otelcol")
+	var stsRequests int
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stsRequests++
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.Form.Get("Action"))
+		assert.Equal(t, "web-identity-token", r.Form.Get("WebIdentityToken"))
+		assert.Equal(t, "arn:aws:iam::123456789012:role/otelcol", r.Form.Get("RoleArn"))
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIAWEBIDENTITY</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session-token</SessionToken>
+      <Expiration>2100-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`))
+	}))
+	defer sts.Close()
+	t.Setenv("AWS_ENDPOINT_URL_STS", sts.URL)
+	ts := newFakeSecretsManager(t, "ASIAWEBIDENTITY", "us-east-1", map[string]string{"api_key": "my-api-key"})
+
+	p := New(WithEndpoint(ts.URL), WithCacheTTL(0))
+	for i := 0; i < 2; i++ {
+		ret, err := p.Retrieve(context.Background(), "secretsmanager:api_key", nil)
+		require.NoError(t, err)
+		value, err := ret.AsRaw()
+		require.NoError(t, err)
+		assert.Equal(t, "my-api-key", value)
+	}
+	// The credentials are cached until they expire.
+	assert.Equal(t, 1, stsRequests)
+}
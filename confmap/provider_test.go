@@ -31,6 +31,21 @@ func TestNewRetrievedWithOptions(t *testing.T) {
 	assert.Equal(t, want, ret.Close(context.Background()))
 }
 
+func TestNewRetrievedApplied(t *testing.T) {
+	ret, err := NewRetrieved(nil)
+	require.NoError(t, err)
+	// The default Retrieved.Applied function does nothing.
+	ret.Applied(context.Background(), nil)
+
+	var applied []error
+	ret, err = NewRetrieved(nil, WithRetrievedApplied(func(_ context.Context, err error) { applied = append(applied, err) }))
+	require.NoError(t, err)
+	want := errors.New("my error")
+	ret.Applied(context.Background(), nil)
+	ret.Applied(context.Background(), want)
+	assert.Equal(t, []error{nil, want}, applied)
+}
+
 func TestNewRetrievedUnsupportedType(t *testing.T) {
 	_, err := NewRetrieved(errors.New("my error"))
 	require.Error(t, err)
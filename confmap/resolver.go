@@ -25,13 +25,36 @@ var _ = featuregate.GlobalRegistry().MustRegister(
 	featuregate.WithRegisterToVersion("v0.75.0"),
 	featuregate.WithRegisterDescription("controls whether expanding embedded external config providers URIs"))
 
+// strictTypesFeatureGate is the feature gate that controls whether the Confs returned by all the Resolvers are
+// unmarshaled with strict types, like with ResolverSettings.StrictTypes.
+var strictTypesFeatureGate = featuregate.GlobalRegistry().MustRegister(
+	"confmap.strictTypes",
+	featuregate.StageAlpha,
+	featuregate.WithRegisterDescription("controls whether the resolved configurations are unmarshaled without "+
+		"converting the values implicitly between types, e.g. the string \"true\" to a bool"))
+
+// ListMergeStrategy defines how the lists of the configurations retrieved from several URIs are merged.
+// The maps are always merged deeply, the values retrieved last overriding those retrieved first.
+type ListMergeStrategy string
+
+const (
+	// ListMergeReplace replaces the lists retrieved first with those retrieved last. It is the default.
+	ListMergeReplace ListMergeStrategy = "replace"
+	// ListMergeAppend appends the lists retrieved last to those retrieved first.
+	ListMergeAppend ListMergeStrategy = "append"
+)
+
 // Resolver resolves a configuration as a Conf.
 type Resolver struct {
-	uris       []location
-	providers  map[string]Provider
-	converters []Converter
+	uris              []location
+	providers         map[string]Provider
+	converters        []Converter
+	listMergeStrategy ListMergeStrategy
+	strictTypes       bool
 
 	closers []CloseFunc
+	// applied are the Retrieved.Applied functions of the values retrieved by the last Resolve.
+	applied []AppliedFunc
 	watcher chan error
 }
 
@@ -47,12 +70,23 @@ type ResolverSettings struct {
 
 	// MapConverters is a slice of Converter.
 	Converters []Converter
+
+	// ListMergeStrategy is how the lists of the configurations retrieved from the URIs are merged.
+	// The default is ListMergeReplace.
+	ListMergeStrategy ListMergeStrategy
+
+	// StrictTypes disallows the implicit conversions between types when the resolved Conf is unmarshaled,
+	// e.g. from the string "true" to a bool, or from an int to a string, failing the Unmarshal instead, so
+	// that the mistyped values are reported. The default, unless the "confmap.strictTypes" feature gate is
+	// enabled, is to convert the values weakly.
+	StrictTypes bool
 }
 
 // NewResolver returns a new Resolver that resolves configuration from multiple URIs.
 //
 // To resolve a configuration the following steps will happen:
-//  1. Retrieves individual configurations from all given "URIs", and merge them in the retrieve order.
+//  1. Retrieves individual configurations from all given "URIs", and merge them in the retrieve order, the lists
+//     being merged according to the "ListMergeStrategy".
 //  2. Once the Conf is merged, apply the converters in the given order.
 //
 // After the configuration was resolved the `Resolver` can be used as a single point to watch for updates in
@@ -77,6 +111,15 @@ func NewResolver(set ResolverSettings) (*Resolver, error) {
 		return nil, errors.New("invalid map resolver config: no Providers")
 	}
 
+	listMergeStrategy := set.ListMergeStrategy
+	switch listMergeStrategy {
+	case "":
+		listMergeStrategy = ListMergeReplace
+	case ListMergeReplace, ListMergeAppend:
+	default:
+		return nil, fmt.Errorf("invalid map resolver config: unsupported list merge strategy %q", listMergeStrategy)
+	}
+
 	// Safe copy, ensures the slices and maps cannot be changed from the caller.
 	uris := make([]location, len(set.URIs))
 	for i, uri := range set.URIs {
@@ -104,10 +147,12 @@ func NewResolver(set ResolverSettings) (*Resolver, error) {
 	copy(convertersCopy, set.Converters)
 
 	return &Resolver{
-		uris:       uris,
-		providers:  providersCopy,
-		converters: convertersCopy,
-		watcher:    make(chan error, 1),
+		uris:              uris,
+		providers:         providersCopy,
+		converters:        convertersCopy,
+		listMergeStrategy: listMergeStrategy,
+		strictTypes:       set.StrictTypes || strictTypesFeatureGate.IsEnabled(),
+		watcher:           make(chan error, 1),
 	}, nil
 }
 
@@ -120,6 +165,8 @@ func (mr *Resolver) Resolve(ctx context.Context) (*Conf, error) {
 		return nil, fmt.Errorf("cannot close previous watch: %w", err)
 	}
 
+	mr.applied = nil
+
 	// Retrieves individual configurations from all URIs in the given order, and merge them in retMap.
 	retMap := New()
 	for _, uri := range mr.uris {
@@ -128,24 +175,42 @@ func (mr *Resolver) Resolve(ctx context.Context) (*Conf, error) {
 			return nil, fmt.Errorf("cannot retrieve the configuration: %w", err)
 		}
 		mr.closers = append(mr.closers, ret.Close)
+		mr.applied = append(mr.applied, ret.Applied)
 		retCfgMap, err := ret.AsConf()
 		if err != nil {
 			return nil, err
 		}
-		if err = retMap.Merge(retCfgMap); err != nil {
+		retCfgMap.sources = make(map[string]string)
+		for _, k := range retCfgMap.AllKeys() {
+			retCfgMap.sources[k] = uri.asString()
+		}
+		if mr.listMergeStrategy == ListMergeAppend {
+			err = retMap.mergeAppend(retCfgMap)
+		} else {
+			err = retMap.Merge(retCfgMap)
+		}
+		if err != nil {
 			return nil, err
 		}
 	}
 
 	cfgMap := make(map[string]any)
+	opaqueKeys := make(map[string]struct{})
 	for _, k := range retMap.AllKeys() {
-		val, err := mr.expandValueRecursively(ctx, retMap.Get(k))
+		val, opaque, err := mr.expandValueRecursively(ctx, retMap.Get(k))
 		if err != nil {
 			return nil, err
 		}
 		cfgMap[k] = val
+		if opaque {
+			opaqueKeys[k] = struct{}{}
+		}
 	}
+	sources := retMap.sources
 	retMap = NewFromStringMap(cfgMap)
+	retMap.opaqueKeys = opaqueKeys
+	retMap.sources = sources
+	retMap.strictTypes = mr.strictTypes
 
 	// Apply the converters in the given order.
 	for _, confConv := range mr.converters {
@@ -157,6 +222,17 @@ func (mr *Resolver) Resolve(ctx context.Context) (*Conf, error) {
 	return retMap, nil
 }
 
+// NotifyApplied notifies the values retrieved by the last Resolve of the result of applying the configuration:
+// nil if the configuration was applied, e.g. the Collector started with it, or the error preventing it from
+// being applied, see Retrieved.Applied.
+//
+// Should never be called concurrently with Resolve or Shutdown.
+func (mr *Resolver) NotifyApplied(ctx context.Context, err error) {
+	for _, applied := range mr.applied {
+		applied(ctx, err)
+	}
+}
+
 // Watch blocks until any configuration change was detected or an unrecoverable error
 // happened during monitoring the configuration changes.
 //
@@ -6,6 +6,7 @@ package confmap
 import (
 	"context"
 	"errors"
+	"fmt"
 	"path/filepath"
 	"sync"
 	"sync/atomic"
@@ -13,6 +14,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/featuregate"
 )
 
 type mockProvider struct {
@@ -301,6 +304,37 @@ func TestResolver(t *testing.T) {
 	assert.Equal(t, int32(3), numCalls.Load())
 }
 
+func TestResolverNotifyApplied(t *testing.T) {
+	var applied []string
+	appliedFunc := func(uri string) AppliedFunc {
+		return func(_ context.Context, err error) {
+			applied = append(applied, fmt.Sprintf("%s: %v", uri, err))
+		}
+	}
+	provider := newFakeProvider("fake", func(_ context.Context, uri string, _ WatcherFunc) (*Retrieved, error) {
+		if uri == "fake:main" {
+			return NewRetrieved(map[string]any{"key": "${fake:value}"}, WithRetrievedApplied(appliedFunc(uri)))
+		}
+		return NewRetrieved("value", WithRetrievedApplied(appliedFunc(uri)))
+	})
+	resolver, err := NewResolver(ResolverSettings{URIs: []string{"fake:main"}, Providers: makeMapProvidersMap(provider)})
+	require.NoError(t, err)
+
+	_, err = resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	resolver.NotifyApplied(context.Background(), nil)
+	// Both the retrieved configuration and the expanded values are notified.
+	assert.Equal(t, []string{"fake:main: <nil>", "fake:value: <nil>"}, applied)
+
+	applied = nil
+	_, err = resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	resolver.NotifyApplied(context.Background(), errors.New("invalid configuration"))
+	// Only the values retrieved by the last Resolve are notified.
+	assert.Equal(t, []string{"fake:main: invalid configuration", "fake:value: invalid configuration"}, applied)
+	assert.NoError(t, resolver.Shutdown(context.Background()))
+}
+
 func TestResolverNewLinesInOpaqueValue(t *testing.T) {
 	_, err := NewResolver(ResolverSettings{
 		URIs:       []string{"mock:receivers:\n nop:\n"},
@@ -325,6 +359,141 @@ func TestResolverNoProviders(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestResolverInvalidListMergeStrategy(t *testing.T) {
+	_, err := NewResolver(ResolverSettings{
+		URIs:              []string{filepath.Join("testdata", "config.yaml")},
+		Providers:         makeMapProvidersMap(&mockProvider{}),
+		ListMergeStrategy: "prepend"})
+	assert.EqualError(t, err, `invalid map resolver config: unsupported list merge strategy "prepend"`)
+}
+
+func TestResolverListMergeStrategy(t *testing.T) {
+	base := newFakeProvider("base", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+		return NewRetrieved(map[string]any{
+			"exporters": map[string]any{"otlp": map[string]any{"endpoint": "localhost:4317", "headers": map[string]any{"a": "b"}}},
+			"service":   map[string]any{"extensions": []any{"health_check"}, "pipelines": map[string]any{"traces": map[string]any{"exporters": []any{"otlp"}}}},
+		})
+	})
+	overlay := newFakeProvider("overlay", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+		return NewRetrieved(map[string]any{
+			"exporters": map[string]any{"otlp": map[string]any{"endpoint": "collector:4317"}, "debug": nil},
+			"service":   map[string]any{"pipelines": map[string]any{"traces": map[string]any{"exporters": []any{"debug"}}}},
+		})
+	})
+
+	tests := []struct {
+		strategy  ListMergeStrategy
+		exporters []any
+	}{
+		{strategy: "", exporters: []any{"debug"}},
+		{strategy: ListMergeReplace, exporters: []any{"debug"}},
+		{strategy: ListMergeAppend, exporters: []any{"otlp", "debug"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			resolver, err := NewResolver(ResolverSettings{
+				URIs:              []string{"base:", "overlay:"},
+				Providers:         makeMapProvidersMap(base, overlay),
+				ListMergeStrategy: tt.strategy})
+			require.NoError(t, err)
+			conf, err := resolver.Resolve(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{
+				"exporters": map[string]any{"otlp": map[string]any{"endpoint": "collector:4317", "headers": map[string]any{"a": "b"}}, "debug": nil},
+				"service":   map[string]any{"extensions": []any{"health_check"}, "pipelines": map[string]any{"traces": map[string]any{"exporters": tt.exporters}}},
+			}, conf.ToStringMap())
+		})
+	}
+}
+
+func TestResolverSources(t *testing.T) {
+	base := newFakeProvider("base", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+		return NewRetrieved(map[string]any{
+			"exporters": map[string]any{"otlp": map[string]any{"endpoint": "localhost:4317", "headers": map[string]any{"a": "b"}}},
+			"receivers": "${included:}",
+		})
+	})
+	overlay := newFakeProvider("overlay", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+		return NewRetrieved(map[string]any{
+			"exporters": map[string]any{"otlp": map[string]any{"endpoint": "collector:4317"}},
+		})
+	})
+	included := newFakeProvider("included", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+		return NewRetrieved(map[string]any{"otlp": map[string]any{"protocols": nil}})
+	})
+
+	resolver, err := NewResolver(ResolverSettings{
+		URIs:      []string{"base:", "overlay:"},
+		Providers: makeMapProvidersMap(base, overlay, included)})
+	require.NoError(t, err)
+	conf, err := resolver.Resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "overlay:", conf.Source("exporters::otlp::endpoint"))
+	assert.Equal(t, "base:", conf.Source("exporters::otlp::headers::a"))
+	// The values expanded from a reference come from the config of the reference.
+	assert.Equal(t, "base:", conf.Source("receivers::otlp::protocols"))
+	assert.Equal(t, "", conf.Source("processors::batch"))
+
+	// The sources are kept when merged.
+	merged := New()
+	require.NoError(t, merged.Merge(conf))
+	assert.Equal(t, "overlay:", merged.Source("exporters::otlp::endpoint"))
+}
+
+type strictTypesConfig struct {
+	Enabled bool        `mapstructure:"enabled"`
+	Next    *nextConfig `mapstructure:"next"`
+}
+
+func TestResolverStrictTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		strictTypes bool
+		featureGate bool
+		expectErr   bool
+	}{
+		{name: "lenient by default"},
+		{name: "strict types setting", strictTypes: true, expectErr: true},
+		{name: "strict types feature gate", featureGate: true, expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, featuregate.GlobalRegistry().Set(strictTypesFeatureGate.ID(), tt.featureGate))
+			defer func() {
+				require.NoError(t, featuregate.GlobalRegistry().Set(strictTypesFeatureGate.ID(), false))
+			}()
+
+			for _, data := range []map[string]any{
+				{"enabled": "true"},
+				// The mistyped values of the Unmarshalers are reported too.
+				{"enabled": true, "next": map[string]any{"string": 10}},
+			} {
+				provider := newFakeProvider("mock", func(context.Context, string, WatcherFunc) (*Retrieved, error) {
+					return NewRetrieved(map[string]any{"component": data})
+				})
+				resolver, err := NewResolver(ResolverSettings{
+					URIs:        []string{"mock:"},
+					Providers:   makeMapProvidersMap(provider),
+					StrictTypes: tt.strictTypes})
+				require.NoError(t, err)
+				conf, err := resolver.Resolve(context.Background())
+				require.NoError(t, err)
+				sub, err := conf.Sub("component")
+				require.NoError(t, err)
+
+				cfg := &strictTypesConfig{}
+				err = sub.Unmarshal(cfg)
+				if tt.expectErr {
+					assert.Error(t, err)
+					continue
+				}
+				require.NoError(t, err)
+				assert.True(t, cfg.Enabled)
+			}
+		})
+	}
+}
+
 func TestResolverShutdownClosesWatch(t *testing.T) {
 	resolver, err := NewResolver(ResolverSettings{
 		URIs:       []string{filepath.Join("testdata", "config.yaml")},
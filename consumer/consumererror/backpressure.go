@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consumererror // import "go.opentelemetry.io/collector/consumer/consumererror"
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrRateLimited is wrapped by the Backpressure errors of the data refused because it exceeds rate limits,
+// rather than because a consumer is overloaded, for the receivers to tell the clients they send too much
+// data, e.g. with the 429 Too Many Requests HTTP status code.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// Backpressure is an error indicating that the data was refused because a consumer is overloaded,
+// e.g. its queue is full or its memory limit is reached. The data can be sent again once the
+// consumer is not overloaded anymore.
+type Backpressure struct {
+	err        error
+	retryAfter time.Duration
+}
+
+// NewBackpressure wraps an error to indicate that the data was refused because of the backpressure
+// of a consumer, and can be sent again after the given delay, or zero if unknown.
+func NewBackpressure(err error, retryAfter time.Duration) error {
+	return Backpressure{err: err, retryAfter: retryAfter}
+}
+
+func (b Backpressure) Error() string {
+	return b.err.Error()
+}
+
+// Unwrap returns the wrapped error for functions Is and As in standard package errors.
+func (b Backpressure) Unwrap() error {
+	return b.err
+}
+
+// RetryAfter returns the delay after which the data can be sent again, or zero if unknown.
+func (b Backpressure) RetryAfter() time.Duration {
+	return b.retryAfter
+}
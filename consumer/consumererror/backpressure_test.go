@@ -0,0 +1,33 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consumererror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackpressure(t *testing.T) {
+	err := errors.New("some error")
+	backpressureErr := NewBackpressure(err, 5*time.Second)
+	assert.Equal(t, err.Error(), backpressureErr.Error())
+	var target Backpressure
+	assert.False(t, errors.As(nil, &target))
+	assert.False(t, errors.As(err, &target))
+	require.True(t, errors.As(fmt.Errorf("wrapped: %w", backpressureErr), &target))
+	assert.Equal(t, 5*time.Second, target.RetryAfter())
+	assert.ErrorIs(t, backpressureErr, err)
+}
+
+func TestBackpressureRateLimited(t *testing.T) {
+	backpressureErr := NewBackpressure(fmt.Errorf("data refused: %w", ErrRateLimited), time.Second)
+	assert.ErrorIs(t, backpressureErr, ErrRateLimited)
+	assert.EqualError(t, backpressureErr, "data refused: rate limit exceeded")
+	assert.NotErrorIs(t, NewBackpressure(errors.New("queue is full"), time.Second), ErrRateLimited)
+}
@@ -0,0 +1,31 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consumererror // import "go.opentelemetry.io/collector/consumer/consumererror"
+
+// Partial is an error indicating that a part of the received data was rejected and must not be
+// sent again, while the rest of the data was accepted.
+type Partial struct {
+	err      error
+	rejected int
+}
+
+// NewPartial wraps an error to indicate that only the given number of items, spans, metric data
+// points or log records, of the received data were rejected.
+func NewPartial(err error, rejected int) error {
+	return Partial{err: err, rejected: rejected}
+}
+
+func (p Partial) Error() string {
+	return p.err.Error()
+}
+
+// Unwrap returns the wrapped error for functions Is and As in standard package errors.
+func (p Partial) Unwrap() error {
+	return p.err
+}
+
+// Rejected returns the number of rejected items.
+func (p Partial) Rejected() int {
+	return p.rejected
+}
@@ -0,0 +1,25 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consumererror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartial(t *testing.T) {
+	err := errors.New("some error")
+	partialErr := NewPartial(err, 3)
+	assert.Equal(t, err.Error(), partialErr.Error())
+	var target Partial
+	assert.False(t, errors.As(nil, &target))
+	assert.False(t, errors.As(err, &target))
+	require.True(t, errors.As(fmt.Errorf("wrapped: %w", partialErr), &target))
+	assert.Equal(t, 3, target.Rejected())
+	assert.ErrorIs(t, partialErr, err)
+}
@@ -0,0 +1,196 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// errCircuitBreakerOpen is returned when a request is not sent because the circuit breaker is open.
+// It is not a permanent error, so the request can be retried or kept in the queue.
+var errCircuitBreakerOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerSettings defines configuration for stopping sending requests to a destination
+// that keeps failing, to avoid overloading it with retries.
+type CircuitBreakerSettings struct {
+	// Enabled indicates whether the circuit breaker is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// FailureThreshold is the number of consecutive failed attempts after which the circuit opens,
+	// so the following requests fail immediately without being sent.
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// OpenTimeout is the time the circuit stays open before probe requests are sent to the destination.
+	OpenTimeout time.Duration `mapstructure:"open_timeout"`
+	// HalfOpenRequests is the number of probe requests sent after the OpenTimeout. The circuit closes
+	// when all of them succeed, and opens again as soon as one of them fails.
+	HalfOpenRequests int `mapstructure:"half_open_requests"`
+}
+
+// NewDefaultCircuitBreakerSettings returns the default settings for CircuitBreakerSettings.
+func NewDefaultCircuitBreakerSettings() CircuitBreakerSettings {
+	return CircuitBreakerSettings{
+		Enabled:          false,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+// Validate checks if the CircuitBreakerSettings configuration is valid
+func (cfg *CircuitBreakerSettings) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.FailureThreshold <= 0 {
+		return errors.New("failure threshold must be positive")
+	}
+	if cfg.OpenTimeout <= 0 {
+		return errors.New("open timeout must be positive")
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		return errors.New("number of half-open requests must be positive")
+	}
+	return nil
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreakerSender is a requestSender that stops sending requests after a number of consecutive failures.
+type circuitBreakerSender struct {
+	baseRequestSender
+	cfg    CircuitBreakerSettings
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu               sync.Mutex
+	state            circuitState
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight int
+	halfOpenSuccess  int
+	// halfOpenPeriod is incremented every time the circuit becomes half-open, to ignore the probes of the
+	// previous periods.
+	halfOpenPeriod int
+}
+
+// admission tells how a request was allowed by the circuit breaker, so its result only updates the state
+// of the circuit it was admitted by.
+type admission struct {
+	// probe is true if the request was allowed as a probe of the half-open circuit.
+	probe bool
+	// period is the half-open period the probe was allowed in.
+	period int
+}
+
+func newCircuitBreakerSender(config CircuitBreakerSettings, set exporter.CreateSettings) *circuitBreakerSender {
+	return &circuitBreakerSender{
+		cfg:    config,
+		logger: set.Logger,
+		now:    time.Now,
+	}
+}
+
+// send implements the requestSender interface
+func (cbs *circuitBreakerSender) send(req internal.Request) error {
+	adm, ok := cbs.allow()
+	if !ok {
+		return errCircuitBreakerOpen
+	}
+	err := cbs.nextSender.send(req)
+	cbs.record(adm, err)
+	return err
+}
+
+// allow returns true if the request can be sent to the destination, and how it was admitted.
+func (cbs *circuitBreakerSender) allow() (admission, bool) {
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+
+	switch cbs.state {
+	case circuitClosed:
+		return admission{}, true
+	case circuitOpen:
+		if cbs.now().Sub(cbs.openedAt) < cbs.cfg.OpenTimeout {
+			return admission{}, false
+		}
+		cbs.state = circuitHalfOpen
+		cbs.halfOpenInFlight = 0
+		cbs.halfOpenSuccess = 0
+		cbs.halfOpenPeriod++
+		cbs.logger.Info("Circuit breaker is half-open, probing the destination.")
+	}
+
+	if cbs.halfOpenInFlight+cbs.halfOpenSuccess >= cbs.cfg.HalfOpenRequests {
+		return admission{}, false
+	}
+	cbs.halfOpenInFlight++
+	return admission{probe: true, period: cbs.halfOpenPeriod}, true
+}
+
+// record updates the state of the circuit with the result of a sent request.
+// Permanent errors are caused by the data, not by the destination health, so they are counted as successes.
+// The requests allowed while the circuit was closed only update the closed circuit, and the probes only
+// update the half-open circuit they were allowed by.
+func (cbs *circuitBreakerSender) record(adm admission, err error) {
+	failed := err != nil && !consumererror.IsPermanent(err)
+
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+
+	switch cbs.state {
+	case circuitClosed:
+		if adm.probe {
+			// A probe of a half-open period that already closed the circuit, nothing to update.
+			return
+		}
+		if !failed {
+			cbs.failures = 0
+			return
+		}
+		cbs.failures++
+		if cbs.failures >= cbs.cfg.FailureThreshold {
+			cbs.open()
+		}
+	case circuitHalfOpen:
+		if !adm.probe || adm.period != cbs.halfOpenPeriod {
+			// A request sent before the circuit became half-open finished, it does not tell whether
+			// the destination recovered.
+			return
+		}
+		cbs.halfOpenInFlight--
+		if failed {
+			cbs.open()
+			return
+		}
+		cbs.halfOpenSuccess++
+		if cbs.halfOpenSuccess >= cbs.cfg.HalfOpenRequests {
+			cbs.state = circuitClosed
+			cbs.failures = 0
+			cbs.logger.Info("Circuit breaker is closed, the destination is healthy again.")
+		}
+	case circuitOpen:
+		// A request sent before the circuit opened finished, nothing to update.
+	}
+}
+
+func (cbs *circuitBreakerSender) open() {
+	cbs.state = circuitOpen
+	cbs.openedAt = cbs.now()
+	cbs.failures = 0
+	cbs.logger.Warn("Circuit breaker is open, requests are not sent to the destination.",
+		zap.Duration("open_timeout", cbs.cfg.OpenTimeout))
+}
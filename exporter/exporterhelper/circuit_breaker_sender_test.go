@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+)
+
+func TestCircuitBreakerSettings_Validate(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerSettings()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.FailureThreshold = 0
+	assert.EqualError(t, cfg.Validate(), "failure threshold must be positive")
+
+	cfg = NewDefaultCircuitBreakerSettings()
+	cfg.Enabled = true
+	cfg.OpenTimeout = 0
+	assert.EqualError(t, cfg.Validate(), "open timeout must be positive")
+
+	cfg = NewDefaultCircuitBreakerSettings()
+	cfg.Enabled = true
+	cfg.HalfOpenRequests = 0
+	assert.EqualError(t, cfg.Validate(), "number of half-open requests must be positive")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	cfg.Enabled = false
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestCircuitBreakerSender(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerSettings()
+	cfg.Enabled = true
+	cfg.FailureThreshold = 2
+	cfg.OpenTimeout = time.Minute
+	cfg.HalfOpenRequests = 2
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender, WithCircuitBreaker(cfg))
+	require.NoError(t, err)
+	cbs := be.circuitBreakerSender.(*circuitBreakerSender)
+	now := time.Now()
+	cbs.now = func() time.Time { return now }
+
+	sendErr := errors.New("transient error")
+
+	// Permanent errors and successful requests don't open the circuit.
+	assert.Error(t, be.send(newMockRequest(context.Background(), 1, sendErr)))
+	assert.Error(t, be.send(newMockRequest(context.Background(), 1, consumererror.NewPermanent(sendErr))))
+	assert.NoError(t, be.send(newMockRequest(context.Background(), 1, nil)))
+	assert.Error(t, be.send(newMockRequest(context.Background(), 1, sendErr)))
+	assert.Equal(t, circuitClosed, cbs.state)
+
+	// The second consecutive failure opens the circuit.
+	assert.ErrorIs(t, be.send(newMockRequest(context.Background(), 1, sendErr)), sendErr)
+	assert.Equal(t, circuitOpen, cbs.state)
+
+	mockR := newMockRequest(context.Background(), 1, nil)
+	err = be.send(mockR)
+	assert.ErrorIs(t, err, errCircuitBreakerOpen)
+	assert.False(t, consumererror.IsPermanent(err))
+	mockR.checkNumRequests(t, 0)
+
+	// A failed probe opens the circuit again.
+	now = now.Add(time.Minute)
+	assert.ErrorIs(t, be.send(newMockRequest(context.Background(), 1, sendErr)), sendErr)
+	assert.Equal(t, circuitOpen, cbs.state)
+	assert.ErrorIs(t, be.send(newMockRequest(context.Background(), 1, nil)), errCircuitBreakerOpen)
+
+	// All the probes must succeed to close the circuit.
+	now = now.Add(time.Minute)
+	assert.NoError(t, be.send(newMockRequest(context.Background(), 1, nil)))
+	assert.Equal(t, circuitHalfOpen, cbs.state)
+	assert.NoError(t, be.send(newMockRequest(context.Background(), 1, nil)))
+	assert.Equal(t, circuitClosed, cbs.state)
+	assert.NoError(t, be.send(newMockRequest(context.Background(), 1, nil)))
+}
+
+func TestCircuitBreakerSender_HalfOpenLimit(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerSettings()
+	cfg.Enabled = true
+	cfg.FailureThreshold = 1
+	cbs := newCircuitBreakerSender(cfg, exportertest.NewNopCreateSettings())
+	now := time.Now()
+	cbs.now = func() time.Time { return now }
+
+	adm, ok := cbs.allow()
+	require.True(t, ok)
+	cbs.record(adm, errors.New("transient error"))
+	_, ok = cbs.allow()
+	require.False(t, ok)
+
+	now = now.Add(cfg.OpenTimeout)
+	// Only one probe is allowed while it's in flight.
+	adm, ok = cbs.allow()
+	require.True(t, ok)
+	_, ok = cbs.allow()
+	require.False(t, ok)
+	cbs.record(adm, nil)
+	_, ok = cbs.allow()
+	require.True(t, ok)
+}
+
+func TestCircuitBreakerSender_RequestsAllowedBeforeHalfOpen(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerSettings()
+	cfg.Enabled = true
+	cfg.FailureThreshold = 1
+	cfg.HalfOpenRequests = 1
+	cbs := newCircuitBreakerSender(cfg, exportertest.NewNopCreateSettings())
+	now := time.Now()
+	cbs.now = func() time.Time { return now }
+	sendErr := errors.New("transient error")
+
+	// Two requests are allowed while the circuit is closed, the first one opens it.
+	closed1, ok := cbs.allow()
+	require.True(t, ok)
+	closed2, ok := cbs.allow()
+	require.True(t, ok)
+	cbs.record(closed1, sendErr)
+	require.Equal(t, circuitOpen, cbs.state)
+
+	now = now.Add(cfg.OpenTimeout)
+	probe, ok := cbs.allow()
+	require.True(t, ok)
+	// The request allowed while the circuit was closed neither frees a probe slot nor closes the circuit.
+	cbs.record(closed2, nil)
+	assert.Equal(t, circuitHalfOpen, cbs.state)
+	_, ok = cbs.allow()
+	require.False(t, ok)
+
+	// A failed probe opens the circuit again, the probe of the previous period is then ignored.
+	cbs.record(probe, sendErr)
+	require.Equal(t, circuitOpen, cbs.state)
+	now = now.Add(cfg.OpenTimeout)
+	newProbe, ok := cbs.allow()
+	require.True(t, ok)
+	cbs.record(probe, nil)
+	assert.Equal(t, circuitHalfOpen, cbs.state)
+	_, ok = cbs.allow()
+	require.False(t, ok)
+
+	cbs.record(newProbe, nil)
+	assert.Equal(t, circuitClosed, cbs.state)
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+		WithCircuitBreaker(NewDefaultCircuitBreakerSettings()))
+	require.NoError(t, err)
+	require.IsType(t, &baseRequestSender{}, be.circuitBreakerSender)
+}
@@ -5,6 +5,7 @@ package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporte
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -42,12 +43,17 @@ type errorLoggingRequestSender struct {
 	baseRequestSender
 	logger  *zap.Logger
 	message string
+	// onDropped is called for the failed requests if set, when the error means the data is dropped.
+	onDropped func(req internal.Request, err error)
 }
 
 func (l *errorLoggingRequestSender) send(req internal.Request) error {
 	err := l.baseRequestSender.send(req)
 	if err != nil {
 		l.logger.Error(l.message, zap.Int("dropped_items", req.Count()), zap.Error(err))
+		if l.onDropped != nil {
+			l.onDropped(req, err)
+		}
 	}
 	return err
 }
@@ -58,6 +64,7 @@ type obsrepSenderFactory func(obsrep *ObsReport) requestSender
 type baseRequest struct {
 	ctx                        context.Context
 	processingFinishedCallback func()
+	enqueuedAt                 time.Time
 }
 
 func (req *baseRequest) Context() context.Context {
@@ -78,6 +85,14 @@ func (req *baseRequest) OnProcessingFinished() {
 	}
 }
 
+func (req *baseRequest) EnqueuedAt() time.Time {
+	return req.enqueuedAt
+}
+
+func (req *baseRequest) SetEnqueuedAt(enqueuedAt time.Time) {
+	req.enqueuedAt = enqueuedAt
+}
+
 // Option apply changes to baseExporter.
 type Option func(*baseExporter)
 
@@ -116,28 +131,94 @@ func WithRetry(config RetrySettings) Option {
 			}
 			return
 		}
-		o.retrySender = newRetrySender(config, o.set, o.onTemporaryFailure)
+		o.retrySender = newRetrySender(config, o.set, o.obsrep, o.throttle, o.errorClassifier, o.onTemporaryFailure)
+	}
+}
+
+// WithErrorClassifier sets the function classifying the errors returned by the exporter, so the retry settings
+// can be configured per class of errors with RetrySettings.PerErrorClass. The errors classified as an empty string
+// are classified by the default classifier, which recognizes the throttling and the network errors.
+// The classes matching one of the error codes, e.g. ErrorCodeUnauthenticated, are also used to attribute
+// the send failures reported by the exporter_send_failures metric.
+func WithErrorClassifier(classifier ErrorClassifier) Option {
+	return func(o *baseExporter) {
+		o.errorClassifier = classifier
+		o.obsrep.errorClassifier = classifier
+		if rs, ok := o.retrySender.(*retrySender); ok {
+			rs.classifier = classifier
+		}
+	}
+}
+
+// WithCircuitBreaker enables the circuit breaker for an exporter, so requests fail immediately with a retryable error
+// while the destination keeps failing. The default CircuitBreakerSettings is to disable the circuit breaker.
+func WithCircuitBreaker(config CircuitBreakerSettings) Option {
+	return func(o *baseExporter) {
+		if !config.Enabled {
+			return
+		}
+		o.circuitBreakerSender = newCircuitBreakerSender(config, o.set)
+	}
+}
+
+// WithRateLimit limits the rate of items and bytes sent to the destination. Every attempt to send a request,
+// including retries, is accounted. The default RateLimitSettings is to disable rate limiting.
+func WithRateLimit(config RateLimitSettings) Option {
+	return func(o *baseExporter) {
+		if !config.Enabled {
+			return
+		}
+		o.rateLimitSender = newRateLimitSender(config)
+	}
+}
+
+// WithConcurrencyLimit limits the number of requests sent to the destination concurrently, independently of
+// the number of queue consumers. Every attempt to send a request, including retries, takes one of the slots.
+// The default is no limit, a limit lower than or equal to zero is ignored.
+func WithConcurrencyLimit(limit int) Option {
+	return func(o *baseExporter) {
+		if limit <= 0 {
+			return
+		}
+		o.concurrencySender = newConcurrencyLimitSender(limit)
 	}
 }
 
 // WithQueue overrides the default QueueSettings for an exporter.
 // The default QueueSettings is to disable queueing.
-// This option cannot be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
+// The new exporter helpers New[Traces|Metrics|Logs]RequestExporter only support the in-memory queue with this option,
+// WithRequestQueue must be used to enable the persistent queue for them.
 func WithQueue(config QueueSettings) Option {
 	return func(o *baseExporter) {
-		if o.requestExporter {
-			panic("queueing is not available for the new request exporters yet")
+		if o.requestExporter && config.Enabled && config.StorageID != nil {
+			panic("persistent queue for the new request exporters must be configured with WithRequestQueue")
 		}
-		if !config.Enabled {
-			o.queueSender = &errorLoggingRequestSender{
-				logger:  o.set.Logger,
-				message: "Exporting failed. Dropping data. Try enabling sending_queue to survive temporary failures.",
+		o.setQueue(config)
+	}
+}
+
+// WithRequestQueue enables queueing for an exporter created with one of the new exporter helpers
+// New[Traces|Metrics|Logs]RequestExporter. The marshaler and unmarshaler are used to store the requests
+// in the persistent queue, they are not used by the in-memory queue and can be nil if persistence is not enabled.
+// This option cannot be used with NewTracesExporter, NewMetricsExporter and NewLogsExporter, use WithQueue instead.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func WithRequestQueue(config QueueSettings, marshaler RequestMarshaler, unmarshaler RequestUnmarshaler) Option {
+	return func(o *baseExporter) {
+		if !o.requestExporter {
+			panic("this option is not available for the old exporters, use WithQueue instead")
+		}
+		if config.Enabled && config.StorageID != nil && (marshaler == nil || unmarshaler == nil) {
+			panic("persistent queue requires both request marshaler and unmarshaler")
+		}
+		if marshaler != nil && unmarshaler != nil {
+			o.marshaler = newInternalRequestMarshaler(marshaler)
+			o.unmarshaler = newInternalRequestUnmarshaler(unmarshaler)
+			if ds, ok := o.dedupSender.(*dedupSender); ok {
+				ds.marshaler = o.marshaler
 			}
-			return
 		}
-		qs := newQueueSender(config, o.set, o.signal, o.marshaler, o.unmarshaler)
-		o.queueSender = qs
-		o.setOnTemporaryFailure(qs.onTemporaryFailure)
+		o.setQueue(config)
 	}
 }
 
@@ -166,14 +247,45 @@ type baseExporter struct {
 	// Chain of senders that the exporter helper applies before passing the data to the actual exporter.
 	// The data is handled by each sender in the respective order starting from the queueSender.
 	// Most of the senders are optional, and initialized with a no-op path-through sender.
-	queueSender   requestSender
-	obsrepSender  requestSender
-	retrySender   requestSender
-	timeoutSender *timeoutSender // timeoutSender is always initialized.
+	splitSender          requestSender
+	queueSender          requestSender
+	dedupSender          requestSender
+	fallbackSender       requestSender
+	obsrepSender         requestSender
+	retrySender          requestSender
+	circuitBreakerSender requestSender
+	rateLimitSender      requestSender
+	concurrencySender    requestSender
+	middlewareSender     requestSender
+	failoverSender       requestSender
+	replicationSender    requestSender
+	timeoutSender        *timeoutSender // timeoutSender is always initialized.
 
 	// onTemporaryFailure is a function that is called when the retrySender is unable to send data to the next consumer.
 	onTemporaryFailure onRequestHandlingFinishedFunc
 
+	// errorClassifier is used by the retrySender to select the retry settings for an error.
+	errorClassifier ErrorClassifier
+
+	// queueConfig and queueFactory are used to create the custom queue of the queueSender.
+	queueConfig  QueueSettings
+	queueFactory QueueFactory
+
+	// shardKey is used by the queueSender to select the shard of a request.
+	shardKey internal.ShardKeyFunc
+	// isLowPriority is used by the queueSender to select the requests dropped by the load shedding.
+	isLowPriority func(req internal.Request) bool
+
+	// startTimeout limits the time spent starting the exporter, readiness checks the destination on start.
+	startTimeout time.Duration
+	readiness    *readinessProbe
+
+	// throttle is used by the retrySender to pause the queue consumers while the destination is throttling.
+	throttle *throttleGate
+
+	// dropCallback is called when data is permanently dropped, see WithDropCallback.
+	dropCallback DropCallback
+
 	consumerOptions []consumer.Option
 }
 
@@ -192,13 +304,23 @@ func newBaseExporter(set exporter.CreateSettings, signal component.DataType, req
 		unmarshaler:     unmarshaler,
 		signal:          signal,
 
-		queueSender:   &baseRequestSender{},
-		obsrepSender:  osf(obsReport),
-		retrySender:   &baseRequestSender{},
-		timeoutSender: &timeoutSender{cfg: NewDefaultTimeoutSettings()},
-
-		set:    set,
-		obsrep: obsReport,
+		splitSender:          &baseRequestSender{},
+		queueSender:          &baseRequestSender{},
+		dedupSender:          &baseRequestSender{},
+		fallbackSender:       &baseRequestSender{},
+		obsrepSender:         osf(obsReport),
+		retrySender:          &baseRequestSender{},
+		circuitBreakerSender: &baseRequestSender{},
+		rateLimitSender:      &baseRequestSender{},
+		concurrencySender:    &baseRequestSender{},
+		middlewareSender:     &baseRequestSender{},
+		failoverSender:       &baseRequestSender{},
+		replicationSender:    &baseRequestSender{},
+		timeoutSender:        &timeoutSender{cfg: NewDefaultTimeoutSettings()},
+
+		set:      set,
+		obsrep:   obsReport,
+		throttle: &throttleGate{},
 	}
 
 	for _, op := range options {
@@ -211,19 +333,28 @@ func newBaseExporter(set exporter.CreateSettings, signal component.DataType, req
 
 // send sends the request using the first sender in the chain.
 func (be *baseExporter) send(req internal.Request) error {
-	return be.queueSender.send(req)
+	return be.splitSender.send(req)
 }
 
 // connectSenders connects the senders in the predefined order.
 func (be *baseExporter) connectSenders() {
-	be.queueSender.setNextSender(be.obsrepSender)
+	be.splitSender.setNextSender(be.queueSender)
+	be.queueSender.setNextSender(be.dedupSender)
+	be.dedupSender.setNextSender(be.fallbackSender)
+	be.fallbackSender.setNextSender(be.obsrepSender)
 	be.obsrepSender.setNextSender(be.retrySender)
-	be.retrySender.setNextSender(be.timeoutSender)
+	be.retrySender.setNextSender(be.circuitBreakerSender)
+	be.circuitBreakerSender.setNextSender(be.rateLimitSender)
+	be.rateLimitSender.setNextSender(be.concurrencySender)
+	be.concurrencySender.setNextSender(be.middlewareSender)
+	be.middlewareSender.setNextSender(be.failoverSender)
+	be.failoverSender.setNextSender(be.replicationSender)
+	be.replicationSender.setNextSender(be.timeoutSender)
 }
 
 func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
 	// First start the wrapped exporter.
-	if err := be.StartFunc.Start(ctx, host); err != nil {
+	if err := be.startWithTimeout(ctx, host); err != nil {
 		return err
 	}
 
@@ -232,6 +363,9 @@ func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
 }
 
 func (be *baseExporter) Shutdown(ctx context.Context) error {
+	if be.readiness != nil {
+		be.readiness.shutdown()
+	}
 	return multierr.Combine(
 		// First shutdown the retry sender, so it can push any pending requests to back the queue.
 		be.retrySender.Shutdown(ctx),
@@ -241,6 +375,61 @@ func (be *baseExporter) Shutdown(ctx context.Context) error {
 		be.ShutdownFunc.Shutdown(ctx))
 }
 
+// QueueResizer is implemented by the exporters created by this package. It allows changing the queue size and
+// the number of consumers of the sending queue of a running exporter, e.g. when its configuration is reloaded.
+type QueueResizer interface {
+	// ResizeQueue changes the queue size and the number of consumers of the in-memory sending queue without
+	// dropping the queued data. If the queued data exceeds the new queue size, no new data is accepted until
+	// enough of it is sent. Returns an error if the sending queue is disabled or persistent.
+	ResizeQueue(queueSize int, numConsumers int) error
+}
+
+// ResizeQueue implements QueueResizer.
+func (be *baseExporter) ResizeQueue(queueSize int, numConsumers int) error {
+	qs, ok := be.queueSender.(*queueSender)
+	if !ok {
+		return errQueueNotEnabled
+	}
+	return qs.resize(queueSize, numConsumers)
+}
+
+func (be *baseExporter) setQueue(config QueueSettings) {
+	if !config.Enabled {
+		be.queueSender = &errorLoggingRequestSender{
+			logger:    be.set.Logger,
+			message:   "Exporting failed. Dropping data. Try enabling sending_queue to survive temporary failures.",
+			onDropped: be.onDropped,
+		}
+		return
+	}
+	qs := newQueueSender(config, be.set, be.signal, be.marshaler, be.unmarshaler, be.throttle)
+	qs.shardKey = be.shardKey
+	qs.isLowPriority = be.isLowPriority
+	qs.onDropped = be.onDropped
+	be.queueConfig = config
+	if be.queueFactory != nil {
+		be.useQueueFactory(qs)
+	}
+	be.queueSender = qs
+	be.setOnTemporaryFailure(qs.onTemporaryFailure)
+}
+
+// useQueueFactory replaces the queue of the queueSender with the queue created by the queueFactory.
+func (be *baseExporter) useQueueFactory(qs *queueSender) {
+	queue, err := newCustomQueue(be.queueFactory, QueueCreateSettings{
+		CreateSettings: be.set,
+		DataType:       be.signal,
+		Config:         be.queueConfig,
+	}, be.marshaler, be.unmarshaler)
+	if err != nil {
+		qs.initErr = err
+		return
+	}
+	qs.queue = queue
+	qs.requeuingEnabled = queue.IsPersistent()
+	qs.resizable, _ = queue.(internal.ResizableQueue)
+}
+
 func (be *baseExporter) setOnTemporaryFailure(onTemporaryFailure onRequestHandlingFinishedFunc) {
 	be.onTemporaryFailure = onTemporaryFailure
 	if rs, ok := be.retrySender.(*retrySender); ok {
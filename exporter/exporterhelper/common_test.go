@@ -76,10 +76,32 @@ func TestQueueRetryOptionsWithRequestExporter(t *testing.T) {
 		WithRetry(NewDefaultRetrySettings()))
 	require.Nil(t, err)
 	require.True(t, bs.requestExporter)
+
+	bs, err = newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
+		WithRetry(NewDefaultRetrySettings()), WithQueue(NewDefaultQueueSettings()))
+	require.NoError(t, err)
+	require.IsType(t, &queueSender{}, bs.queueSender)
+
+	qCfg := NewDefaultQueueSettings()
+	storageID := component.NewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID
+	require.Panics(t, func() {
+		_, _ = newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
+			WithRetry(NewDefaultRetrySettings()), WithQueue(qCfg))
+	})
 	require.Panics(t, func() {
 		_, _ = newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
-			WithRetry(NewDefaultRetrySettings()), WithQueue(NewDefaultQueueSettings()))
+			WithRetry(NewDefaultRetrySettings()), WithRequestQueue(qCfg, nil, nil))
+	})
+	require.Panics(t, func() {
+		_, _ = newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+			WithRetry(NewDefaultRetrySettings()), WithRequestQueue(NewDefaultQueueSettings(), nil, nil))
 	})
+
+	bs, err = newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
+		WithRetry(NewDefaultRetrySettings()), WithRequestQueue(qCfg, fakeRequestMarshaler, fakeRequestUnmarshaler))
+	require.NoError(t, err)
+	require.IsType(t, &queueSender{}, bs.queueSender)
 }
 
 func TestBaseExporterLogging(t *testing.T) {
@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// concurrencyLimitSender is a requestSender that limits the number of requests sent to the destination concurrently.
+// Requests exceeding the limit wait until one of the in-flight requests finishes.
+type concurrencyLimitSender struct {
+	baseRequestSender
+	sem chan struct{}
+}
+
+func newConcurrencyLimitSender(limit int) *concurrencyLimitSender {
+	return &concurrencyLimitSender{sem: make(chan struct{}, limit)}
+}
+
+// send implements the requestSender interface
+func (cls *concurrencyLimitSender) send(req internal.Request) error {
+	select {
+	case cls.sem <- struct{}{}:
+	case <-req.Context().Done():
+		return fmt.Errorf("request is cancelled or timed out while waiting for the concurrency limit %w", req.Context().Err())
+	}
+	defer func() { <-cls.sem }()
+	return cls.nextSender.send(req)
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+)
+
+// blockingRequest blocks the export until the release channel is closed and tracks the number of concurrent exports.
+type blockingRequest struct {
+	inFlight    *atomic.Int64
+	maxInFlight *atomic.Int64
+	release     chan struct{}
+}
+
+func (r *blockingRequest) Export(context.Context) error {
+	current := r.inFlight.Add(1)
+	defer r.inFlight.Add(-1)
+	for {
+		prev := r.maxInFlight.Load()
+		if current <= prev || r.maxInFlight.CompareAndSwap(prev, current) {
+			break
+		}
+	}
+	<-r.release
+	return nil
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 10
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
+		WithQueue(qCfg), WithConcurrencyLimit(3))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	inFlight := &atomic.Int64{}
+	maxInFlight := &atomic.Int64{}
+	release := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		require.NoError(t, be.send(newRequest(context.Background(),
+			&blockingRequest{inFlight: inFlight, maxInFlight: maxInFlight, release: release})))
+	}
+
+	assert.Eventually(t, func() bool {
+		return inFlight.Load() == 3
+	}, time.Second, 10*time.Millisecond)
+	// Give the other consumers a chance to exceed the limit.
+	time.Sleep(50 * time.Millisecond)
+	assert.EqualValues(t, 3, inFlight.Load())
+
+	close(release)
+	require.NoError(t, be.Shutdown(context.Background()))
+	assert.EqualValues(t, 3, maxInFlight.Load())
+	assert.EqualValues(t, 0, inFlight.Load())
+}
+
+func TestConcurrencyLimit_CancelledWhileWaiting(t *testing.T) {
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
+		WithConcurrencyLimit(1))
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	req := &blockingRequest{inFlight: &atomic.Int64{}, maxInFlight: &atomic.Int64{}, release: release}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, be.send(newRequest(context.Background(), req)))
+	}()
+	assert.Eventually(t, func() bool {
+		return req.inFlight.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, be.send(newRequest(ctx, req)), context.Canceled)
+
+	close(release)
+	wg.Wait()
+}
+
+func TestConcurrencyLimit_Disabled(t *testing.T) {
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
+		WithConcurrencyLimit(0))
+	require.NoError(t, err)
+	require.IsType(t, &baseRequestSender{}, be.concurrencySender)
+}
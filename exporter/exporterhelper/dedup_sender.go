@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// DeduplicationSettings defines configuration for dropping the requests identical to a request
+// successfully sent within a time window, e.g. sent again by an upstream retry.
+type DeduplicationSettings struct {
+	// Enabled indicates whether the deduplication is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Window is the time during which the requests identical to a sent request are dropped.
+	Window time.Duration `mapstructure:"window"`
+	// MaxEntries is the maximum number of fingerprints of the sent requests kept in memory.
+	// The oldest fingerprints are forgotten first when the limit is reached.
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// NewDefaultDeduplicationSettings returns the default settings for DeduplicationSettings.
+func NewDefaultDeduplicationSettings() DeduplicationSettings {
+	return DeduplicationSettings{
+		Enabled:    false,
+		Window:     5 * time.Minute,
+		MaxEntries: 10000,
+	}
+}
+
+// Validate checks if the DeduplicationSettings configuration is valid
+func (cfg *DeduplicationSettings) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Window <= 0 {
+		return errors.New("deduplication window must be positive")
+	}
+	if cfg.MaxEntries <= 0 {
+		return errors.New("max entries must be positive")
+	}
+	return nil
+}
+
+// WithDeduplication drops the requests identical to a request successfully sent within the configured window.
+// The requests are identified by the hash of their serialized form, so the requests of the exporters created with
+// New[Traces|Metrics|Logs]RequestExporter are only deduplicated if a marshaler is set with WithRequestQueue.
+// The default DeduplicationSettings is to disable the deduplication.
+func WithDeduplication(config DeduplicationSettings) Option {
+	return func(o *baseExporter) {
+		if !config.Enabled {
+			return
+		}
+		o.dedupSender = newDedupSender(config, o.set, o.marshaler)
+	}
+}
+
+type fingerprint [sha256.Size]byte
+
+type dedupEntry struct {
+	fp     fingerprint
+	sentAt time.Time
+}
+
+// dedupSender is a requestSender that drops the requests identical to a recently sent one.
+type dedupSender struct {
+	baseRequestSender
+	cfg       DeduplicationSettings
+	logger    *zap.Logger
+	marshaler internal.RequestMarshaler
+	now       func() time.Time
+
+	mu sync.Mutex
+	// sent maps the fingerprints of the sent requests to their entries in order, the oldest one first.
+	sent  map[fingerprint]*list.Element
+	order *list.List
+}
+
+func newDedupSender(config DeduplicationSettings, set exporter.CreateSettings, marshaler internal.RequestMarshaler) *dedupSender {
+	return &dedupSender{
+		cfg:       config,
+		logger:    set.Logger,
+		marshaler: marshaler,
+		now:       time.Now,
+		sent:      make(map[fingerprint]*list.Element),
+		order:     list.New(),
+	}
+}
+
+// send implements the requestSender interface
+func (ds *dedupSender) send(req internal.Request) error {
+	if ds.marshaler == nil {
+		return ds.nextSender.send(req)
+	}
+	buf, err := ds.marshaler(req)
+	if err != nil {
+		ds.logger.Debug("Failed to compute the request fingerprint, sending it without deduplication.", zap.Error(err))
+		return ds.nextSender.send(req)
+	}
+	fp := fingerprint(sha256.Sum256(buf))
+	if ds.isDuplicate(fp) {
+		ds.logger.Debug("Dropping the request identical to a recently sent one.", zap.Int("dropped_items", req.Count()))
+		return nil
+	}
+	if err = ds.nextSender.send(req); err != nil {
+		return err
+	}
+	ds.remember(fp)
+	return nil
+}
+
+// isDuplicate returns whether a request with the given fingerprint was sent within the window.
+func (ds *dedupSender) isDuplicate(fp fingerprint) bool {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.expire()
+	_, ok := ds.sent[fp]
+	return ok
+}
+
+// remember records the fingerprint of a sent request.
+func (ds *dedupSender) remember(fp fingerprint) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if el, ok := ds.sent[fp]; ok {
+		ds.order.Remove(el)
+	}
+	ds.sent[fp] = ds.order.PushBack(dedupEntry{fp: fp, sentAt: ds.now()})
+	for ds.order.Len() > ds.cfg.MaxEntries {
+		ds.forget(ds.order.Front())
+	}
+}
+
+// expire forgets the fingerprints of the requests sent before the window. Must be called with the lock held.
+func (ds *dedupSender) expire() {
+	deadline := ds.now().Add(-ds.cfg.Window)
+	for el := ds.order.Front(); el != nil && !el.Value.(dedupEntry).sentAt.After(deadline); el = ds.order.Front() {
+		ds.forget(el)
+	}
+}
+
+func (ds *dedupSender) forget(el *list.Element) {
+	ds.order.Remove(el)
+	delete(ds.sent, el.Value.(dedupEntry).fp)
+}
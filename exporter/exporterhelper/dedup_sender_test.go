@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestDeduplicationSettings_Validate(t *testing.T) {
+	cfg := NewDefaultDeduplicationSettings()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Window = 0
+	assert.EqualError(t, cfg.Validate(), "deduplication window must be positive")
+
+	cfg = NewDefaultDeduplicationSettings()
+	cfg.Enabled = true
+	cfg.MaxEntries = 0
+	assert.EqualError(t, cfg.Validate(), "max entries must be positive")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	cfg.Enabled = false
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestDedupSender(t *testing.T) {
+	cfg := NewDefaultDeduplicationSettings()
+	cfg.Enabled = true
+	cfg.Window = time.Minute
+	endpoint := &countingEndpoint{}
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, tracesRequestMarshaler, nil,
+		newNoopObsrepSender, WithDeduplication(cfg))
+	require.NoError(t, err)
+	ds := be.dedupSender.(*dedupSender)
+	now := time.Now()
+	ds.now = func() time.Time { return now }
+
+	send := func(td ptrace.Traces) error {
+		return be.send(newTracesRequest(context.Background(), td, endpoint.push))
+	}
+
+	// The failed requests are not remembered, so they can be sent again.
+	endpoint.err = errors.New("transient error")
+	assert.Error(t, send(testdata.GenerateTraces(2)))
+	endpoint.err = nil
+	assert.NoError(t, send(testdata.GenerateTraces(2)))
+	assert.Equal(t, 2, endpoint.calls)
+
+	// The identical requests are dropped within the window, the different ones are sent.
+	assert.NoError(t, send(testdata.GenerateTraces(2)))
+	assert.Equal(t, 2, endpoint.calls)
+	assert.NoError(t, send(testdata.GenerateTraces(3)))
+	assert.Equal(t, 3, endpoint.calls)
+
+	// The identical requests are sent again once the window passes.
+	now = now.Add(time.Minute)
+	assert.NoError(t, send(testdata.GenerateTraces(2)))
+	assert.Equal(t, 4, endpoint.calls)
+}
+
+func TestDedupSender_MaxEntries(t *testing.T) {
+	cfg := NewDefaultDeduplicationSettings()
+	cfg.Enabled = true
+	cfg.MaxEntries = 2
+	endpoint := &countingEndpoint{}
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, tracesRequestMarshaler, nil,
+		newNoopObsrepSender, WithDeduplication(cfg))
+	require.NoError(t, err)
+
+	send := func(spans int) error {
+		return be.send(newTracesRequest(context.Background(), testdata.GenerateTraces(spans), endpoint.push))
+	}
+	for spans := 1; spans <= 3; spans++ {
+		require.NoError(t, send(spans))
+	}
+	assert.Equal(t, 3, endpoint.calls)
+
+	// The oldest fingerprint is forgotten.
+	assert.NoError(t, send(1))
+	assert.Equal(t, 4, endpoint.calls)
+	assert.NoError(t, send(3))
+	assert.Equal(t, 4, endpoint.calls)
+}
+
+func TestDedupSender_NoMarshaler(t *testing.T) {
+	cfg := NewDefaultDeduplicationSettings()
+	cfg.Enabled = true
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
+		WithDeduplication(cfg))
+	require.NoError(t, err)
+
+	// The requests cannot be identified without a marshaler, so all of them are sent.
+	mockR := newMockRequest(context.Background(), 1, nil)
+	require.NoError(t, be.send(mockR))
+	require.NoError(t, be.send(mockR))
+	mockR.checkNumRequests(t, 2)
+}
+
+func TestDedupSender_RequestQueueMarshaler(t *testing.T) {
+	cfg := NewDefaultDeduplicationSettings()
+	cfg.Enabled = true
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
+		WithDeduplication(cfg), WithRequestQueue(qCfg, func(Request) ([]byte, error) { return []byte("request"), nil },
+			func([]byte) (Request, error) { return nil, nil }))
+	require.NoError(t, err)
+
+	mockR := newMockRequest(context.Background(), 1, nil)
+	require.NoError(t, be.send(newRequest(context.Background(), mockR)))
+	require.NoError(t, be.send(newRequest(context.Background(), mockR)))
+	mockR.checkNumRequests(t, 1)
+}
@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// DropCallback is called with the number of spans, metric data points or log records of the signal
+// permanently dropped by the exporter and the error explaining why they were dropped.
+// The context is the context of the dropped request. The callback must not block.
+type DropCallback func(ctx context.Context, signal component.DataType, count int, err error)
+
+// WithDropCallback sets the callback called every time the exporter permanently drops data, e.g. because
+// the sending queue is full, no more retries are left or the data is not sent before the shutdown timeout.
+// It allows emitting audit events for the lost data instead of relying on the logs.
+func WithDropCallback(callback DropCallback) Option {
+	return func(o *baseExporter) {
+		o.dropCallback = callback
+	}
+}
+
+// onDropped calls the drop callback, if any, for the dropped request.
+func (be *baseExporter) onDropped(req internal.Request, err error) {
+	if be.dropCallback != nil {
+		be.dropCallback(req.Context(), be.signal, req.Count(), err)
+	}
+}
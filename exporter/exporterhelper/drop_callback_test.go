@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+// droppedData records the calls of the drop callback.
+type droppedData struct {
+	mu      sync.Mutex
+	signals []component.DataType
+	counts  []int
+	errs    []error
+}
+
+func (d *droppedData) callback(_ context.Context, signal component.DataType, count int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.signals = append(d.signals, signal)
+	d.counts = append(d.counts, count)
+	d.errs = append(d.errs, err)
+}
+
+func (d *droppedData) calls() ([]int, []error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]int{}, d.counts...), append([]error{}, d.errs...)
+}
+
+func TestDropCallback_QueueFull(t *testing.T) {
+	dropped := &droppedData{}
+	qCfg := NewDefaultQueueSettings()
+	qCfg.QueueSize = 0
+	qCfg.NumConsumers = 0
+	be, err := newBaseExporter(defaultSettings, component.DataTypeTraces, false, nil, nil, newNoopObsrepSender,
+		WithDropCallback(dropped.callback), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	require.Error(t, be.send(newMockRequest(context.Background(), 2, nil)))
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	counts, errs := dropped.calls()
+	assert.Equal(t, []component.DataType{component.DataTypeTraces}, dropped.signals)
+	assert.Equal(t, []int{2}, counts)
+	assert.Equal(t, []error{errSendingQueueIsFull}, errs)
+}
+
+func TestDropCallback_NoRetriesLeft(t *testing.T) {
+	dropped := &droppedData{}
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	rCfg := NewDefaultRetrySettings()
+	rCfg.Enabled = false
+	be, err := newBaseExporter(defaultSettings, component.DataTypeTraces, false, nil, nil, newNoopObsrepSender,
+		WithQueue(qCfg), WithRetry(rCfg), WithDropCallback(dropped.callback))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, be.send(newErrorRequest(context.Background())))
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	counts, errs := dropped.calls()
+	assert.Equal(t, []int{7}, counts)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "transient error")
+}
+
+func TestDropCallback_ShutdownTimeout(t *testing.T) {
+	dropped := &droppedData{}
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.ShutdownTimeout = 10 * time.Millisecond
+	be, err := newBaseExporter(defaultSettings, component.DataTypeTraces, false, nil, nil, newNoopObsrepSender,
+		WithQueue(qCfg), WithDropCallback(dropped.callback))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	exported := &atomic.Int64{}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, be.send(&slowRequest{baseRequest: baseRequest{ctx: context.Background()},
+			delay: 50 * time.Millisecond, exported: exported}))
+	}
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	counts, errs := dropped.calls()
+	assert.NotEmpty(t, counts)
+	assert.Equal(t, int64(3), exported.Load()+int64(len(counts)))
+	for _, err := range errs {
+		assert.ErrorIs(t, err, errDrainExpired)
+	}
+}
+
+func TestDropCallback_QueueDisabled(t *testing.T) {
+	dropped := &droppedData{}
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	be, err := newBaseExporter(defaultSettings, component.DataTypeTraces, false, nil, nil, newNoopObsrepSender,
+		WithDropCallback(dropped.callback), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	sendErr := errors.New("some error")
+	require.ErrorIs(t, be.send(newMockRequest(context.Background(), 3, sendErr)), sendErr)
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	counts, errs := dropped.calls()
+	assert.Equal(t, []int{3}, counts)
+	assert.Equal(t, []error{sendErr}, errs)
+}
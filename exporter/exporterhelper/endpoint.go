@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// errEndpointUnsupportedRequest is returned when the request cannot be exported to the endpoint. The error is
+// permanent, retrying the request does not change its type.
+var errEndpointUnsupportedRequest = consumererror.NewPermanent(errors.New("endpoint does not support the request type"))
+
+// endpoint exports a request to one of the destination endpoints.
+type endpoint func(ctx context.Context, req internal.Request) error
+
+// primaryEndpoint exports the request to the destination of the exporter.
+func primaryEndpoint(ctx context.Context, req internal.Request) error {
+	return req.Export(ctx)
+}
+
+func newTracesEndpoints(consumers []consumer.ConsumeTracesFunc) []endpoint {
+	endpoints := make([]endpoint, 0, len(consumers))
+	for _, consume := range consumers {
+		consume := consume
+		endpoints = append(endpoints, func(ctx context.Context, req internal.Request) error {
+			tr, ok := dataRequest(req).(*tracesRequest)
+			if !ok {
+				return errEndpointUnsupportedRequest
+			}
+			return consume(ctx, tr.td)
+		})
+	}
+	return endpoints
+}
+
+func newMetricsEndpoints(consumers []consumer.ConsumeMetricsFunc) []endpoint {
+	endpoints := make([]endpoint, 0, len(consumers))
+	for _, consume := range consumers {
+		consume := consume
+		endpoints = append(endpoints, func(ctx context.Context, req internal.Request) error {
+			mr, ok := dataRequest(req).(*metricsRequest)
+			if !ok {
+				return errEndpointUnsupportedRequest
+			}
+			return consume(ctx, mr.md)
+		})
+	}
+	return endpoints
+}
+
+func newLogsEndpoints(consumers []consumer.ConsumeLogsFunc) []endpoint {
+	endpoints := make([]endpoint, 0, len(consumers))
+	for _, consume := range consumers {
+		consume := consume
+		endpoints = append(endpoints, func(ctx context.Context, req internal.Request) error {
+			lr, ok := dataRequest(req).(*logsRequest)
+			if !ok {
+				return errEndpointUnsupportedRequest
+			}
+			return consume(ctx, lr.ld)
+		})
+	}
+	return endpoints
+}
+
+func newRequestEndpoints(consumers []func(ctx context.Context, req Request) error) []endpoint {
+	endpoints := make([]endpoint, 0, len(consumers))
+	for _, consume := range consumers {
+		consume := consume
+		endpoints = append(endpoints, func(ctx context.Context, req internal.Request) error {
+			r, ok := dataRequest(req).(*request)
+			if !ok {
+				return errEndpointUnsupportedRequest
+			}
+			return consume(ctx, r.Request)
+		})
+	}
+	return endpoints
+}
+
+// dataRequest returns the request wrapped by the endpointRequests, e.g. by both the failoverSender and the
+// replicationSender, for the endpoints to export its data.
+func dataRequest(req internal.Request) internal.Request {
+	for {
+		er, ok := req.(*endpointRequest)
+		if !ok {
+			return req
+		}
+		req = er.Request
+	}
+}
+
+// endpointRequest is a request exported to one of the destination endpoints.
+type endpointRequest struct {
+	internal.Request
+	endpoint endpoint
+}
+
+func (r *endpointRequest) Export(ctx context.Context) error {
+	return r.endpoint(ctx, r.Request)
+}
+
+// BytesCount returns the size of the wrapped request in bytes if known, otherwise 0.
+func (r *endpointRequest) BytesCount() int {
+	return bytesCount(r.Request)
+}
@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+const (
+	// ErrorCodeTimeout is the error code of the failures caused by a timeout.
+	ErrorCodeTimeout = "timeout"
+	// ErrorCodeThrottled is the error code of the failures caused by the destination asking the exporter to slow down.
+	ErrorCodeThrottled = ErrorClassThrottled
+	// ErrorCodeUnauthenticated is the error code of the failures caused by missing or invalid credentials.
+	ErrorCodeUnauthenticated = "unauthenticated"
+	// ErrorCodeNetwork is the error code of the failures caused by a network failure.
+	ErrorCodeNetwork = ErrorClassNetwork
+	// ErrorCodePermanent is the error code of the other failures that cannot be retried, e.g. caused by invalid data.
+	ErrorCodePermanent = "permanent"
+	// ErrorCodeUnknown is the error code of the failures not matching any other code.
+	ErrorCodeUnknown = "unknown"
+)
+
+// errorCode returns the coarse code of a send failure reported by the ObsReport. The class returned
+// by the classifier set with WithErrorClassifier is used if it is one of the error codes.
+func errorCode(err error, classifier ErrorClassifier) string {
+	if classifier != nil {
+		switch class := classifier(err); class {
+		case ErrorCodeTimeout, ErrorCodeThrottled, ErrorCodeUnauthenticated, ErrorCodeNetwork, ErrorCodePermanent:
+			return class
+		}
+	}
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.Unauthenticated, codes.PermissionDenied:
+			return ErrorCodeUnauthenticated
+		case codes.DeadlineExceeded:
+			return ErrorCodeTimeout
+		case codes.ResourceExhausted:
+			return ErrorCodeThrottled
+		case codes.Unavailable:
+			return ErrorCodeNetwork
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCodeTimeout
+	}
+	if errors.As(err, &throttleRetry{}) {
+		return ErrorCodeThrottled
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrorCodeTimeout
+		}
+		return ErrorCodeNetwork
+	}
+	if consumererror.IsPermanent(err) {
+		return ErrorCodePermanent
+	}
+	return ErrorCodeUnknown
+}
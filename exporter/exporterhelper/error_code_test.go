@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "deadline_exceeded",
+			err:  fmt.Errorf("export failed: %w", context.DeadlineExceeded),
+			want: ErrorCodeTimeout,
+		},
+		{
+			name: "network_timeout",
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded},
+			want: ErrorCodeTimeout,
+		},
+		{
+			name: "grpc_deadline_exceeded",
+			err:  status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+			want: ErrorCodeTimeout,
+		},
+		{
+			name: "throttled",
+			err:  NewThrottleRetry(errors.New("slow down"), time.Second),
+			want: ErrorCodeThrottled,
+		},
+		{
+			name: "grpc_resource_exhausted",
+			err:  status.Error(codes.ResourceExhausted, "resource exhausted"),
+			want: ErrorCodeThrottled,
+		},
+		{
+			name: "grpc_unauthenticated",
+			err:  consumererror.NewPermanent(status.Error(codes.Unauthenticated, "invalid token")),
+			want: ErrorCodeUnauthenticated,
+		},
+		{
+			name: "grpc_permission_denied",
+			err:  status.Error(codes.PermissionDenied, "permission denied"),
+			want: ErrorCodeUnauthenticated,
+		},
+		{
+			name: "network",
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+			want: ErrorCodeNetwork,
+		},
+		{
+			name: "grpc_unavailable",
+			err:  status.Error(codes.Unavailable, "unavailable"),
+			want: ErrorCodeNetwork,
+		},
+		{
+			name: "permanent",
+			err:  consumererror.NewPermanent(errors.New("bad data")),
+			want: ErrorCodePermanent,
+		},
+		{
+			name: "unknown",
+			err:  errors.New("unknown error"),
+			want: ErrorCodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errorCode(tt.err, nil))
+		})
+	}
+}
+
+func TestErrorCode_Classifier(t *testing.T) {
+	unauthenticated := errors.New("HTTP 401")
+	classifier := func(err error) string {
+		switch {
+		case errors.Is(err, unauthenticated):
+			return ErrorCodeUnauthenticated
+		default:
+			return ErrorClassServer
+		}
+	}
+	assert.Equal(t, ErrorCodeUnauthenticated, errorCode(consumererror.NewPermanent(unauthenticated), classifier))
+	// The classes not matching any error code are ignored.
+	assert.Equal(t, ErrorCodeUnknown, errorCode(errors.New("HTTP 500"), classifier))
+}
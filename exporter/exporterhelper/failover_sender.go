@@ -0,0 +1,185 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// FailoverSettings defines configuration for moving to the next destination endpoint when the current one
+// keeps failing, and moving back to the primary endpoint once it is healthy again.
+type FailoverSettings struct {
+	// Enabled indicates whether the failover is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// FailureThreshold is the number of consecutive failed attempts after which the requests are sent
+	// to the next endpoint.
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// ProbeInterval is the time between the probes of the primary endpoint while the requests are sent
+	// to another one. A probe is a request sent to the primary endpoint, if it succeeds the exporter
+	// moves back to the primary endpoint, otherwise the request is sent to the current one.
+	ProbeInterval time.Duration `mapstructure:"probe_interval"`
+}
+
+// NewDefaultFailoverSettings returns the default settings for FailoverSettings.
+func NewDefaultFailoverSettings() FailoverSettings {
+	return FailoverSettings{
+		Enabled:          false,
+		FailureThreshold: 3,
+		ProbeInterval:    30 * time.Second,
+	}
+}
+
+// Validate checks if the FailoverSettings configuration is valid
+func (cfg *FailoverSettings) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.FailureThreshold <= 0 {
+		return errors.New("failure threshold must be positive")
+	}
+	if cfg.ProbeInterval <= 0 {
+		return errors.New("probe interval must be positive")
+	}
+	return nil
+}
+
+// WithTracesFailover enables the failover to the given secondary endpoints, tried in the given order
+// when the destination of the exporter keeps failing. This option can only be used with NewTracesExporter.
+func WithTracesFailover(config FailoverSettings, endpoints ...consumer.ConsumeTracesFunc) Option {
+	return withFailover(config, newTracesEndpoints(endpoints))
+}
+
+// WithMetricsFailover enables the failover to the given secondary endpoints, tried in the given order
+// when the destination of the exporter keeps failing. This option can only be used with NewMetricsExporter.
+func WithMetricsFailover(config FailoverSettings, endpoints ...consumer.ConsumeMetricsFunc) Option {
+	return withFailover(config, newMetricsEndpoints(endpoints))
+}
+
+// WithLogsFailover enables the failover to the given secondary endpoints, tried in the given order
+// when the destination of the exporter keeps failing. This option can only be used with NewLogsExporter.
+func WithLogsFailover(config FailoverSettings, endpoints ...consumer.ConsumeLogsFunc) Option {
+	return withFailover(config, newLogsEndpoints(endpoints))
+}
+
+// WithRequestFailover enables the failover to the given secondary endpoints, tried in the given order
+// when the destination of the exporter keeps failing. This option can only be used with the new exporter
+// helpers New[Traces|Metrics|Logs]RequestExporter.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func WithRequestFailover(config FailoverSettings, endpoints ...func(ctx context.Context, req Request) error) Option {
+	return withFailover(config, newRequestEndpoints(endpoints))
+}
+
+func withFailover(config FailoverSettings, endpoints []endpoint) Option {
+	return func(o *baseExporter) {
+		if !config.Enabled || len(endpoints) == 0 {
+			return
+		}
+		o.failoverSender = newFailoverSender(config, o.set.Logger, endpoints)
+	}
+}
+
+// failoverSender is a requestSender that sends the requests to the next endpoint after a number
+// of consecutive failures, and probes the primary endpoint to move back to it.
+type failoverSender struct {
+	baseRequestSender
+	cfg    FailoverSettings
+	logger *zap.Logger
+	now    func() time.Time
+	// endpoints are the destination endpoints, the first one is the primary endpoint of the exporter.
+	endpoints []endpoint
+
+	mu        sync.Mutex
+	active    int
+	failures  int
+	lastProbe time.Time
+}
+
+func newFailoverSender(config FailoverSettings, logger *zap.Logger, secondary []endpoint) *failoverSender {
+	return &failoverSender{
+		cfg:       config,
+		logger:    logger,
+		now:       time.Now,
+		endpoints: append([]endpoint{primaryEndpoint}, secondary...),
+	}
+}
+
+// send implements the requestSender interface
+func (fs *failoverSender) send(req internal.Request) error {
+	active, probe := fs.pick()
+	if probe {
+		if err := fs.sendTo(0, req); err == nil {
+			fs.failBack()
+			return nil
+		}
+	}
+	err := fs.sendTo(active, req)
+	fs.record(active, err)
+	return err
+}
+
+// sendTo sends the request to the endpoint with the given index through the next senders.
+func (fs *failoverSender) sendTo(endpoint int, req internal.Request) error {
+	return fs.nextSender.send(&endpointRequest{Request: req, endpoint: fs.endpoints[endpoint]})
+}
+
+// pick returns the index of the endpoint the request must be sent to, and whether the primary endpoint
+// must be probed first.
+func (fs *failoverSender) pick() (int, bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.active == 0 || fs.now().Sub(fs.lastProbe) < fs.cfg.ProbeInterval {
+		return fs.active, false
+	}
+	fs.lastProbe = fs.now()
+	return fs.active, true
+}
+
+// record updates the state of the active endpoint with the result of a sent request.
+// Permanent errors are caused by the data, not by the endpoint health, so they are counted as successes.
+func (fs *failoverSender) record(endpoint int, err error) {
+	failed := err != nil && !consumererror.IsPermanent(err)
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if endpoint != fs.active {
+		// A request sent before the exporter moved to another endpoint finished, nothing to update.
+		return
+	}
+	if !failed {
+		fs.failures = 0
+		return
+	}
+	fs.failures++
+	if fs.failures < fs.cfg.FailureThreshold {
+		return
+	}
+	fs.active = (fs.active + 1) % len(fs.endpoints)
+	fs.failures = 0
+	fs.lastProbe = fs.now()
+	fs.logger.Warn("The destination endpoint keeps failing, moving to the next endpoint.",
+		zap.Int("endpoint", fs.active),
+		zap.Error(err))
+}
+
+// failBack moves back to the primary endpoint after a successful probe.
+func (fs *failoverSender) failBack() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.active == 0 {
+		return
+	}
+	fs.active = 0
+	fs.failures = 0
+	fs.logger.Info("The primary destination endpoint is healthy again, moving back to it.")
+}
@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestFailoverSettings_Validate(t *testing.T) {
+	cfg := NewDefaultFailoverSettings()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.FailureThreshold = 0
+	assert.EqualError(t, cfg.Validate(), "failure threshold must be positive")
+
+	cfg = NewDefaultFailoverSettings()
+	cfg.Enabled = true
+	cfg.ProbeInterval = 0
+	assert.EqualError(t, cfg.Validate(), "probe interval must be positive")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	cfg.Enabled = false
+	assert.NoError(t, cfg.Validate())
+}
+
+// countingEndpoint counts the traces exported to it, and fails with the err if set.
+type countingEndpoint struct {
+	calls int
+	err   error
+}
+
+func (e *countingEndpoint) push(context.Context, ptrace.Traces) error {
+	e.calls++
+	return e.err
+}
+
+func TestFailoverSender(t *testing.T) {
+	cfg := NewDefaultFailoverSettings()
+	cfg.Enabled = true
+	cfg.FailureThreshold = 2
+	cfg.ProbeInterval = time.Minute
+	primary := &countingEndpoint{err: errors.New("primary is down")}
+	secondary := &countingEndpoint{}
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+		WithTracesFailover(cfg, secondary.push))
+	require.NoError(t, err)
+	fs := be.failoverSender.(*failoverSender)
+	now := time.Now()
+	fs.now = func() time.Time { return now }
+
+	send := func() error {
+		return be.send(newTracesRequest(context.Background(), testdata.GenerateTraces(1), primary.push))
+	}
+
+	// Permanent errors don't move the exporter to the next endpoint.
+	primary.err = consumererror.NewPermanent(errors.New("bad data"))
+	for i := 0; i < 3; i++ {
+		assert.Error(t, send())
+	}
+	assert.Zero(t, secondary.calls)
+
+	// The exporter moves to the secondary endpoint after the consecutive failures.
+	primary.err = errors.New("primary is down")
+	assert.Error(t, send())
+	assert.Error(t, send())
+	assert.NoError(t, send())
+	assert.Equal(t, 5, primary.calls)
+	assert.Equal(t, 1, secondary.calls)
+
+	// The primary endpoint is probed after the probe interval, the request is sent to the secondary one if it fails.
+	now = now.Add(time.Minute)
+	assert.NoError(t, send())
+	assert.Equal(t, 6, primary.calls)
+	assert.Equal(t, 2, secondary.calls)
+
+	// The primary endpoint is not probed again until the next probe interval.
+	assert.NoError(t, send())
+	assert.Equal(t, 6, primary.calls)
+	assert.Equal(t, 3, secondary.calls)
+
+	// The exporter moves back to the primary endpoint once the probe succeeds.
+	primary.err = nil
+	now = now.Add(time.Minute)
+	assert.NoError(t, send())
+	assert.NoError(t, send())
+	assert.Equal(t, 8, primary.calls)
+	assert.Equal(t, 3, secondary.calls)
+}
+
+func TestFailoverSender_WrapsAround(t *testing.T) {
+	cfg := NewDefaultFailoverSettings()
+	cfg.Enabled = true
+	cfg.FailureThreshold = 1
+	primary := &countingEndpoint{err: errors.New("primary is down")}
+	secondary := &countingEndpoint{err: errors.New("secondary is down")}
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+		WithTracesFailover(cfg, secondary.push))
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		assert.Error(t, be.send(newTracesRequest(context.Background(), testdata.GenerateTraces(1), primary.push)))
+	}
+	// After the last endpoint fails, the exporter tries the primary one again.
+	assert.Equal(t, 2, primary.calls)
+	assert.Equal(t, 1, secondary.calls)
+}
+
+func TestFailoverSender_Disabled(t *testing.T) {
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+		WithTracesFailover(NewDefaultFailoverSettings(), (&countingEndpoint{}).push))
+	require.NoError(t, err)
+	assert.IsType(t, &baseRequestSender{}, be.failoverSender)
+}
+
+func TestFailoverSender_UnsupportedRequest(t *testing.T) {
+	cfg := NewDefaultFailoverSettings()
+	cfg.Enabled = true
+	cfg.FailureThreshold = 1
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+		WithTracesFailover(cfg, (&countingEndpoint{}).push))
+	require.NoError(t, err)
+
+	assert.Error(t, be.send(newMockRequest(context.Background(), 1, errors.New("transient error"))))
+	assert.ErrorIs(t, be.send(newMockRequest(context.Background(), 1, nil)), errEndpointUnsupportedRequest)
+}
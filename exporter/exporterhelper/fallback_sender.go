@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+var errFallbackUnsupportedRequest = errors.New("fallback does not support the request type")
+
+// requeuedError marks an error for a request that was put back to the queue, so it must not be handed
+// to the fallback.
+type requeuedError struct {
+	err error
+}
+
+func (e requeuedError) Error() string {
+	return e.err.Error()
+}
+
+func (e requeuedError) Unwrap() error {
+	return e.err
+}
+
+// fallbackFunc hands a request that failed to be exported to the fallback.
+type fallbackFunc func(req internal.Request) error
+
+// WithTracesFallback sets the consumer that receives the traces the exporter failed to export, either because
+// of a permanent error or because no more retries are left, e.g. an exporter writing the data to a file
+// so it can be recovered later. This option can only be used with NewTracesExporter.
+func WithTracesFallback(fallback consumer.Traces) Option {
+	return withFallback(func(req internal.Request) error {
+		tr, ok := req.(*tracesRequest)
+		if !ok {
+			return errFallbackUnsupportedRequest
+		}
+		return fallback.ConsumeTraces(tr.Context(), tr.td)
+	})
+}
+
+// WithMetricsFallback sets the consumer that receives the metrics the exporter failed to export, either because
+// of a permanent error or because no more retries are left, e.g. an exporter writing the data to a file
+// so it can be recovered later. This option can only be used with NewMetricsExporter.
+func WithMetricsFallback(fallback consumer.Metrics) Option {
+	return withFallback(func(req internal.Request) error {
+		mr, ok := req.(*metricsRequest)
+		if !ok {
+			return errFallbackUnsupportedRequest
+		}
+		return fallback.ConsumeMetrics(mr.Context(), mr.md)
+	})
+}
+
+// WithLogsFallback sets the consumer that receives the logs the exporter failed to export, either because
+// of a permanent error or because no more retries are left, e.g. an exporter writing the data to a file
+// so it can be recovered later. This option can only be used with NewLogsExporter.
+func WithLogsFallback(fallback consumer.Logs) Option {
+	return withFallback(func(req internal.Request) error {
+		lr, ok := req.(*logsRequest)
+		if !ok {
+			return errFallbackUnsupportedRequest
+		}
+		return fallback.ConsumeLogs(lr.Context(), lr.ld)
+	})
+}
+
+// WithRequestFallback sets the function that receives the requests the exporter failed to export, either because
+// of a permanent error or because no more retries are left. This option can only be used with the new exporter
+// helpers New[Traces|Metrics|Logs]RequestExporter.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func WithRequestFallback(fallback func(ctx context.Context, req Request) error) Option {
+	return withFallback(func(req internal.Request) error {
+		r, ok := req.(*request)
+		if !ok {
+			return errFallbackUnsupportedRequest
+		}
+		return fallback(r.Context(), r.Request)
+	})
+}
+
+func withFallback(fallback fallbackFunc) Option {
+	return func(o *baseExporter) {
+		o.fallbackSender = &fallbackSender{
+			logger:   o.set.Logger,
+			fallback: fallback,
+		}
+	}
+}
+
+// fallbackSender is a requestSender that hands the requests the exporter gave up on to a fallback.
+type fallbackSender struct {
+	baseRequestSender
+	logger   *zap.Logger
+	fallback fallbackFunc
+}
+
+// send implements the requestSender interface
+func (fs *fallbackSender) send(req internal.Request) error {
+	err := fs.nextSender.send(req)
+	if err == nil || errors.As(err, &requeuedError{}) {
+		return err
+	}
+
+	// Only the part of the request that failed is handed to the fallback.
+	failed := req.OnError(err)
+	if fallbackErr := fs.fallback(failed); fallbackErr != nil {
+		fs.logger.Error(
+			"Exporting failed. The fallback did not accept the data. Dropping data.",
+			zap.Error(multierr.Append(err, fallbackErr)),
+			zap.Int("dropped_items", failed.Count()),
+		)
+		return err
+	}
+	fs.logger.Warn(
+		"Exporting failed. The data was handed to the fallback.",
+		zap.Error(err),
+		zap.Int("fallback_items", failed.Count()),
+	)
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestTracesExporter_WithFallback_PermanentError(t *testing.T) {
+	sink := &consumertest.TracesSink{}
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeTracesExporterConfig,
+		newTraceDataPusher(consumererror.NewPermanent(errors.New("bad data"))), WithTracesFallback(sink))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, te.Shutdown(context.Background())) })
+
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+	assert.Eventually(t, func() bool {
+		return sink.SpanCount() == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTracesExporter_WithFallback_PartialError(t *testing.T) {
+	sink := &consumertest.TracesSink{}
+	failed := testdata.GenerateTraces(1)
+	pushErr := consumererror.NewPermanent(consumererror.NewTraces(errors.New("bad data"), failed))
+	rCfg := NewDefaultRetrySettings()
+	rCfg.Enabled = false
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeTracesExporterConfig,
+		newTraceDataPusher(pushErr), WithRetry(rCfg), WithQueue(qCfg), WithTracesFallback(sink))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, te.Shutdown(context.Background())) })
+
+	// Only the failed part is handed to the fallback, and the data is not reported as lost.
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(3)))
+	assert.Equal(t, 1, sink.SpanCount())
+}
+
+func TestMetricsExporter_WithFallback_MaxElapsedTime(t *testing.T) {
+	sink := &consumertest.MetricsSink{}
+	rCfg := NewDefaultRetrySettings()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = 10 * time.Millisecond
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	me, err := NewMetricsExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeMetricsExporterConfig,
+		newPushMetricsData(errors.New("transient error")), WithRetry(rCfg), WithQueue(qCfg), WithMetricsFallback(sink))
+	require.NoError(t, err)
+	require.NoError(t, me.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, me.Shutdown(context.Background())) })
+
+	md := testdata.GenerateMetrics(2)
+	require.NoError(t, me.ConsumeMetrics(context.Background(), md))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, md, sink.AllMetrics()[0])
+}
+
+func TestLogsExporter_WithFallback_Error(t *testing.T) {
+	fallbackErr := errors.New("fallback error")
+	rCfg := NewDefaultRetrySettings()
+	rCfg.Enabled = false
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	pushErr := consumererror.NewPermanent(errors.New("bad data"))
+	le, err := NewLogsExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeLogsExporterConfig,
+		newPushLogsData(pushErr), WithRetry(rCfg), WithQueue(qCfg), WithLogsFallback(consumertest.NewErr(fallbackErr)))
+	require.NoError(t, err)
+	require.NoError(t, le.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, le.Shutdown(context.Background())) })
+
+	// The original error is returned if the fallback does not accept the data.
+	assert.Equal(t, pushErr, le.ConsumeLogs(context.Background(), testdata.GenerateLogs(2)))
+}
+
+func TestTracesRequestExporter_WithFallback(t *testing.T) {
+	fallbackItems := &atomic.Int64{}
+	rCfg := NewDefaultRetrySettings()
+	rCfg.Enabled = false
+	te, err := NewTracesRequestExporter(context.Background(), exportertest.NewNopCreateSettings(),
+		&fakeRequestConverter{requestError: consumererror.NewPermanent(errors.New("bad data"))}, WithRetry(rCfg),
+		WithRequestFallback(func(_ context.Context, req Request) error {
+			fallbackItems.Add(int64(req.(fakeRequest).items))
+			return nil
+		}))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, te.Shutdown(context.Background())) })
+
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+	assert.Eventually(t, func() bool {
+		return fallbackItems.Load() == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestFallback_UnsupportedRequest(t *testing.T) {
+	sink := &consumertest.LogsSink{}
+	rCfg := NewDefaultRetrySettings()
+	rCfg.Enabled = false
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	pushErr := consumererror.NewPermanent(errors.New("bad data"))
+	// The logs fallback cannot be used for traces.
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeTracesExporterConfig,
+		newTraceDataPusher(pushErr), WithRetry(rCfg), WithQueue(qCfg), WithLogsFallback(sink))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, te.Shutdown(context.Background())) })
+
+	assert.Equal(t, pushErr, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+	assert.Equal(t, 0, sink.LogRecordCount())
+}
+
+func TestFallback_NotCalledForRequeuedRequests(t *testing.T) {
+	fallbackCalls := &atomic.Int64{}
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+		withFallback(func(internal.Request) error {
+			fallbackCalls.Add(1)
+			return nil
+		}))
+	require.NoError(t, err)
+	be.fallbackSender.setNextSender(&errorSender{err: requeuedError{err: errors.New("transient error")}})
+
+	assert.Error(t, be.fallbackSender.send(newMockRequest(context.Background(), 1, nil)))
+	assert.Equal(t, int64(0), fallbackCalls.Load())
+}
+
+// errorSender is a requestSender that always fails with the given error.
+type errorSender struct {
+	baseRequestSender
+	err error
+}
+
+func (es *errorSender) send(internal.Request) error {
+	return es.err
+}
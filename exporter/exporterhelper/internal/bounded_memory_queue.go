@@ -7,79 +7,297 @@ package internal // import "go.opentelemetry.io/collector/exporter/exporterhelpe
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 )
 
 // boundedMemoryQueue implements a producer-consumer exchange similar to a ring buffer queue,
 // where the queue is bounded and if it fills up due to slow consumers, the new items written by
-// the producer are dropped.
+// the producer are handled according to the overflow policy.
 type boundedMemoryQueue struct {
-	stopWG       sync.WaitGroup
+	stopWG sync.WaitGroup
+	// mu protects the items channel from being closed or replaced while the producers write to it.
+	mu           sync.RWMutex
 	stopped      *atomic.Bool
 	items        chan Request
 	numConsumers int
+	sizer        Sizer
+	capacity     *atomic.Int64
+	size         *atomic.Int64
+	overflow     OverflowSettings
+	dropCallback func(item Request)
+
+	// consumersMu protects the consumers, every consumer stops once its channel is closed.
+	consumersMu sync.Mutex
+	consumers   []chan struct{}
+	callback    func(item Request)
+
+	// freed is closed and replaced every time an item is taken from the queue
+	// to wake up the producers waiting for the free space.
+	freedMu sync.Mutex
+	freed   chan struct{}
 }
 
 // NewBoundedMemoryQueue constructs the new queue of specified capacity. Capacity cannot be 0.
-func NewBoundedMemoryQueue(capacity int, numConsumers int) Queue {
-	return &boundedMemoryQueue{
+// The capacity is measured in the units defined by the given Sizer.
+func NewBoundedMemoryQueue(capacity int, numConsumers int, sizer Sizer, overflow OverflowSettings) Queue {
+	q := &boundedMemoryQueue{
+		// Requests usually hold at least one item, so the channel does not need to be larger than the capacity in any unit.
 		items:        make(chan Request, capacity),
 		stopped:      &atomic.Bool{},
 		numConsumers: numConsumers,
+		sizer:        sizer,
+		capacity:     &atomic.Int64{},
+		size:         &atomic.Int64{},
+		overflow:     overflow,
+		freed:        make(chan struct{}),
 	}
+	q.capacity.Store(int64(capacity))
+	return q
 }
 
 // Start starts a given number of goroutines consuming items from the queue
 // and passing them into the consumer callback.
 func (q *boundedMemoryQueue) Start(_ context.Context, _ component.Host, set QueueSettings) error {
+	q.dropCallback = set.DropCallback
+	q.callback = set.Callback
+	q.consumersMu.Lock()
+	defer q.consumersMu.Unlock()
+	q.startConsumers(q.numConsumers)
+	return nil
+}
+
+// startConsumers starts the given number of consumers. Must be called with consumersMu held.
+func (q *boundedMemoryQueue) startConsumers(n int) {
 	var startWG sync.WaitGroup
-	for i := 0; i < q.numConsumers; i++ {
+	for i := 0; i < n; i++ {
+		quit := make(chan struct{})
+		q.consumers = append(q.consumers, quit)
 		q.stopWG.Add(1)
 		startWG.Add(1)
 		go func() {
 			startWG.Done()
 			defer q.stopWG.Done()
-			for item := range q.items {
-				set.Callback(item)
-			}
+			q.consume(quit)
 		}()
 	}
 	startWG.Wait()
+}
+
+// consume passes the items to the callback until the queue is shut down or the quit channel is closed.
+func (q *boundedMemoryQueue) consume(quit chan struct{}) {
+	for {
+		select {
+		case <-quit:
+			return
+		case item, ok := <-q.itemsChan():
+			if !ok {
+				if q.stopped.Load() {
+					return
+				}
+				// The channel was replaced by Resize.
+				continue
+			}
+			q.onTaken(item)
+			q.callback(item)
+		}
+	}
+}
+
+// Resize changes the capacity of the queue and the number of consumers while the queue is running.
+// The items in the queue are kept, if they exceed the new capacity no new items are accepted until
+// enough of them are consumed. The consumers stopped by the resize finish processing their current item.
+func (q *boundedMemoryQueue) Resize(capacity int, numConsumers int) error {
+	if capacity <= 0 {
+		return errors.New("queue capacity must be positive")
+	}
+	if numConsumers <= 0 {
+		return errors.New("number of queue consumers must be positive")
+	}
+
+	// The lock is held until the consumers are updated, so the queue cannot be shut down in the meantime.
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped.Load() {
+		return errors.New("queue is stopped")
+	}
+	if int(q.capacity.Load()) != capacity {
+		old := q.items
+		// The channel must hold the items already in the queue, they are never dropped by the resize.
+		q.items = make(chan Request, maxInt(capacity, len(old)))
+		close(old)
+		for item := range old {
+			q.items <- item
+		}
+		q.capacity.Store(int64(capacity))
+	}
+	if q.overflow.Policy == OverflowBlock {
+		// Wake up the blocked producers, the new capacity may have room for their items.
+		q.notifyFreed()
+	}
+
+	q.consumersMu.Lock()
+	defer q.consumersMu.Unlock()
+	q.numConsumers = numConsumers
+	if q.callback == nil {
+		// Not started yet, the consumers are started by Start.
+		return nil
+	}
+	if n := numConsumers - len(q.consumers); n > 0 {
+		q.startConsumers(n)
+	}
+	for len(q.consumers) > numConsumers {
+		close(q.consumers[len(q.consumers)-1])
+		q.consumers = q.consumers[:len(q.consumers)-1]
+	}
 	return nil
 }
 
+func (q *boundedMemoryQueue) itemsChan() chan Request {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.items
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 // Produce is used by the producer to submit new item to the queue. Returns false in case of queue overflow.
 func (q *boundedMemoryQueue) Produce(item Request) bool {
+	switch q.overflow.Policy {
+	case OverflowDropOldest:
+		return q.produceDropOldest(item)
+	case OverflowBlock:
+		return q.produceBlocking(item)
+	default:
+		return q.tryProduce(item)
+	}
+}
+
+// tryProduce adds the item to the queue if there is enough free space.
+func (q *boundedMemoryQueue) tryProduce(item Request) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
 	if q.stopped.Load() {
 		return false
 	}
 
+	size := int64(q.sizer.SizeOf(item))
+	capacity := q.capacity.Load()
+	// The number of requests is already bounded by the channel capacity, unless the channel is kept larger by Resize.
+	_, isRequestsSizer := q.sizer.(RequestsSizer)
+	if q.size.Add(size) > capacity && (!isRequestsSizer || int64(cap(q.items)) > capacity) {
+		q.size.Add(-size)
+		return false
+	}
+
 	select {
 	case q.items <- item:
 		return true
 	default:
+		q.size.Add(-size)
+		return false
+	}
+}
+
+// produceDropOldest evicts the oldest items until the new item fits in the queue.
+func (q *boundedMemoryQueue) produceDropOldest(item Request) bool {
+	if _, isRequestsSizer := q.sizer.(RequestsSizer); !isRequestsSizer && int64(q.sizer.SizeOf(item)) > q.capacity.Load() {
+		// The item would not fit even in the empty queue.
 		return false
 	}
+	for !q.tryProduce(item) {
+		if q.stopped.Load() {
+			return false
+		}
+		select {
+		case oldest, ok := <-q.itemsChan():
+			if !ok {
+				// The queue is stopped or the channel was replaced by Resize.
+				continue
+			}
+			q.onTaken(oldest)
+			if q.dropCallback != nil {
+				q.dropCallback(oldest)
+			}
+			oldest.OnProcessingFinished()
+		default:
+			// The queue is empty, e.g. its capacity is zero and no consumer is waiting.
+			return false
+		}
+	}
+	return true
+}
+
+// produceBlocking waits for the free space in the queue up to the block timeout.
+func (q *boundedMemoryQueue) produceBlocking(item Request) bool {
+	timer := time.NewTimer(q.overflow.BlockTimeout)
+	defer timer.Stop()
+	for {
+		// Take the channel before trying, so the space freed in between is not missed.
+		freed := q.freedChan()
+		if q.tryProduce(item) {
+			return true
+		}
+		if q.stopped.Load() {
+			return false
+		}
+		select {
+		case <-freed:
+		case <-timer.C:
+			return false
+		}
+	}
+}
+
+// onTaken updates the queue size after the item is taken from the queue.
+func (q *boundedMemoryQueue) onTaken(item Request) {
+	q.size.Add(-int64(q.sizer.SizeOf(item)))
+	if q.overflow.Policy == OverflowBlock {
+		q.notifyFreed()
+	}
+}
+
+func (q *boundedMemoryQueue) freedChan() chan struct{} {
+	q.freedMu.Lock()
+	defer q.freedMu.Unlock()
+	return q.freed
+}
+
+func (q *boundedMemoryQueue) notifyFreed() {
+	q.freedMu.Lock()
+	defer q.freedMu.Unlock()
+	close(q.freed)
+	q.freed = make(chan struct{})
 }
 
 // Shutdown stops accepting items, and stops all consumers. It blocks until all consumers have stopped.
 func (q *boundedMemoryQueue) Shutdown(context.Context) error {
+	q.mu.Lock()
 	q.stopped.Store(true) // disable producer
 	close(q.items)
+	q.mu.Unlock()
+	// Wake up the blocked producers.
+	q.notifyFreed()
 	q.stopWG.Wait()
 	return nil
 }
 
 // Size returns the current size of the queue
 func (q *boundedMemoryQueue) Size() int {
-	return len(q.items)
+	return int(q.size.Load())
 }
 
 func (q *boundedMemoryQueue) Capacity() int {
-	return cap(q.items)
+	return int(q.capacity.Load())
 }
 
 func (q *boundedMemoryQueue) IsPersistent() bool {
@@ -37,11 +37,13 @@ func newStringRequest(str string) Request {
 	return stringRequest{str: str}
 }
 
+func (stringRequest) OnProcessingFinished() {}
+
 // In this test we run a queue with capacity 1 and a single consumer.
 // We want to test the overflow behavior, so we block the consumer
 // by holding a startLock before submitting items to the queue.
 func TestBoundedQueue(t *testing.T) {
-	q := NewBoundedMemoryQueue(1, 1)
+	q := NewBoundedMemoryQueue(1, 1, RequestsSizer{}, OverflowSettings{})
 
 	var startLock sync.Mutex
 
@@ -105,7 +107,7 @@ func TestBoundedQueue(t *testing.T) {
 // only after Stop will mean the consumers are still locked while
 // trying to perform the final consumptions.
 func TestShutdownWhileNotEmpty(t *testing.T) {
-	q := NewBoundedMemoryQueue(10, 1)
+	q := NewBoundedMemoryQueue(10, 1, RequestsSizer{}, OverflowSettings{})
 
 	consumerState := newConsumerState(t)
 
@@ -188,7 +190,7 @@ func Benchmark_QueueUsage_10000_10_250000(b *testing.B) {
 func queueUsage(b *testing.B, capacity int, numConsumers int, numberOfItems int) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
-		q := NewBoundedMemoryQueue(capacity, numConsumers)
+		q := NewBoundedMemoryQueue(capacity, numConsumers, RequestsSizer{}, OverflowSettings{})
 		err := q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(item Request) {
 			time.Sleep(1 * time.Millisecond)
 		}))
@@ -245,8 +247,35 @@ func (s *consumerState) assertConsumed(expected map[string]bool) {
 	assert.Equal(s.t, expected, s.snapshot())
 }
 
+func TestBoundedQueueWithItemsSizer(t *testing.T) {
+	q := NewBoundedMemoryQueue(120, 1, ItemsSizer{}, OverflowSettings{})
+
+	consumed := make(chan struct{})
+	assert.NoError(t, q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(item Request) {
+		<-consumed
+	})))
+
+	// Every request has 50 spans, the first one is picked by the blocked consumer.
+	req := newFakeTracesRequest(newTraces(5, 10))
+	assert.True(t, q.Produce(req))
+	assert.Eventually(t, func() bool {
+		return q.Size() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.True(t, q.Produce(req))
+	assert.True(t, q.Produce(req))
+	assert.Equal(t, 100, q.Size())
+	assert.False(t, q.Produce(req))
+	assert.Equal(t, 100, q.Size())
+	assert.Equal(t, 120, q.Capacity())
+
+	close(consumed)
+	assert.NoError(t, q.Shutdown(context.Background()))
+	assert.Equal(t, 0, q.Size())
+}
+
 func TestZeroSizeWithConsumers(t *testing.T) {
-	q := NewBoundedMemoryQueue(0, 1)
+	q := NewBoundedMemoryQueue(0, 1, RequestsSizer{}, OverflowSettings{})
 
 	err := q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(item Request) {}))
 	assert.NoError(t, err)
@@ -257,7 +286,7 @@ func TestZeroSizeWithConsumers(t *testing.T) {
 }
 
 func TestZeroSizeNoConsumers(t *testing.T) {
-	q := NewBoundedMemoryQueue(0, 0)
+	q := NewBoundedMemoryQueue(0, 0, RequestsSizer{}, OverflowSettings{})
 
 	err := q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(item Request) {}))
 	assert.NoError(t, err)
@@ -266,3 +295,194 @@ func TestZeroSizeNoConsumers(t *testing.T) {
 
 	assert.NoError(t, q.Shutdown(context.Background()))
 }
+
+func TestBoundedQueueDropOldest(t *testing.T) {
+	q := NewBoundedMemoryQueue(2, 1, RequestsSizer{}, OverflowSettings{Policy: OverflowDropOldest})
+
+	consumerState := newConsumerState(t)
+	release := make(chan struct{})
+	var dropped []string
+	var droppedMu sync.Mutex
+	set := newNopQueueSettings(func(item Request) {
+		consumerState.record(item.(stringRequest).str)
+		<-release
+	})
+	set.DropCallback = func(item Request) {
+		droppedMu.Lock()
+		defer droppedMu.Unlock()
+		dropped = append(dropped, item.(stringRequest).str)
+	}
+	assert.NoError(t, q.Start(context.Background(), componenttest.NewNopHost(), set))
+
+	assert.True(t, q.Produce(newStringRequest("a")))
+	consumerState.waitToConsumeOnce()
+
+	// "b" and "c" fill the queue, "d" and "e" evict them.
+	for _, item := range []string{"b", "c", "d", "e"} {
+		assert.True(t, q.Produce(newStringRequest(item)))
+	}
+	assert.Equal(t, 2, q.Size())
+	droppedMu.Lock()
+	assert.Equal(t, []string{"b", "c"}, dropped)
+	droppedMu.Unlock()
+
+	close(release)
+	consumerState.assertConsumed(map[string]bool{
+		"a": true,
+		"d": true,
+		"e": true,
+	})
+	assert.NoError(t, q.Shutdown(context.Background()))
+}
+
+func TestBoundedQueueDropOldestWithItemsSizer(t *testing.T) {
+	q := NewBoundedMemoryQueue(60, 0, ItemsSizer{}, OverflowSettings{Policy: OverflowDropOldest})
+	assert.NoError(t, q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(item Request) {})))
+
+	// Every request has 20 spans.
+	req := newFakeTracesRequest(newTraces(2, 10))
+	for i := 0; i < 5; i++ {
+		assert.True(t, q.Produce(req))
+	}
+	assert.Equal(t, 60, q.Size())
+
+	// The request larger than the queue capacity is rejected without dropping the queued data.
+	assert.False(t, q.Produce(newFakeTracesRequest(newTraces(7, 10))))
+	assert.Equal(t, 60, q.Size())
+	assert.NoError(t, q.Shutdown(context.Background()))
+}
+
+func TestBoundedQueueBlock(t *testing.T) {
+	q := NewBoundedMemoryQueue(1, 1, RequestsSizer{}, OverflowSettings{Policy: OverflowBlock, BlockTimeout: 50 * time.Millisecond})
+
+	consumerState := newConsumerState(t)
+	release := make(chan struct{})
+	assert.NoError(t, q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(item Request) {
+		consumerState.record(item.(stringRequest).str)
+		<-release
+	})))
+
+	assert.True(t, q.Produce(newStringRequest("a")))
+	consumerState.waitToConsumeOnce()
+	assert.True(t, q.Produce(newStringRequest("b")))
+
+	// The queue is full and the consumer is blocked, so the producer gives up after the timeout.
+	start := time.Now()
+	assert.False(t, q.Produce(newStringRequest("c")))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// The producer is unblocked as soon as the consumer takes an item.
+	q.(*boundedMemoryQueue).overflow.BlockTimeout = 5 * time.Second
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	assert.True(t, q.Produce(newStringRequest("d")))
+	consumerState.assertConsumed(map[string]bool{
+		"a": true,
+		"b": true,
+		"d": true,
+	})
+	assert.NoError(t, q.Shutdown(context.Background()))
+}
+
+func TestBoundedQueueBlock_Shutdown(t *testing.T) {
+	q := NewBoundedMemoryQueue(1, 0, RequestsSizer{}, OverflowSettings{Policy: OverflowBlock, BlockTimeout: 5 * time.Second})
+	assert.NoError(t, q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(item Request) {})))
+	assert.True(t, q.Produce(newStringRequest("a")))
+
+	produced := make(chan bool)
+	go func() {
+		produced <- q.Produce(newStringRequest("b"))
+	}()
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, q.Shutdown(context.Background()))
+	// The blocked producer is released by the shutdown.
+	assert.False(t, <-produced)
+}
+
+func TestBoundedQueueResize(t *testing.T) {
+	q := NewBoundedMemoryQueue(2, 1, RequestsSizer{}, OverflowSettings{}).(ResizableQueue)
+	blockCh := make(chan struct{})
+	consumerState := newConsumerState(t)
+	require.NoError(t, q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(item Request) {
+		consumerState.record(item.(stringRequest).str)
+		<-blockCh
+	})))
+
+	assert.True(t, q.Produce(newStringRequest("a")))
+	consumerState.waitToConsumeOnce()
+	assert.True(t, q.Produce(newStringRequest("b")))
+	assert.True(t, q.Produce(newStringRequest("c")))
+	assert.False(t, q.Produce(newStringRequest("d")))
+
+	// Growing the queue makes room for the new items.
+	require.NoError(t, q.Resize(4, 1))
+	assert.Equal(t, 4, q.Capacity())
+	assert.Equal(t, 2, q.Size())
+	assert.True(t, q.Produce(newStringRequest("d")))
+	assert.True(t, q.Produce(newStringRequest("e")))
+	assert.False(t, q.Produce(newStringRequest("f")))
+
+	// Shrinking the queue keeps the queued items, but no new items are accepted until they are consumed.
+	require.NoError(t, q.Resize(1, 1))
+	assert.Equal(t, 1, q.Capacity())
+	assert.Equal(t, 4, q.Size())
+	assert.False(t, q.Produce(newStringRequest("f")))
+
+	close(blockCh)
+	consumerState.assertConsumed(map[string]bool{
+		"a": true,
+		"b": true,
+		"c": true,
+		"d": true,
+		"e": true,
+	})
+	assert.True(t, q.Produce(newStringRequest("f")))
+	require.NoError(t, q.Shutdown(context.Background()))
+	assert.Error(t, q.Resize(2, 1))
+}
+
+func TestBoundedQueueResize_ItemsSizer(t *testing.T) {
+	q := NewBoundedMemoryQueue(10, 1, ItemsSizer{}, OverflowSettings{}).(ResizableQueue)
+	// Every request has 8 spans, the queue is not started so nothing is consumed.
+	req := newFakeTracesRequest(newTraces(1, 8))
+	assert.True(t, q.Produce(req))
+	assert.False(t, q.Produce(req))
+	require.NoError(t, q.Resize(20, 1))
+	assert.True(t, q.Produce(req))
+	require.NoError(t, q.Resize(5, 1))
+	assert.Equal(t, 16, q.Size())
+	assert.False(t, q.Produce(newFakeTracesRequest(newTraces(1, 1))))
+	require.NoError(t, q.Shutdown(context.Background()))
+}
+
+func TestBoundedQueueResize_Consumers(t *testing.T) {
+	q := NewBoundedMemoryQueue(10, 1, RequestsSizer{}, OverflowSettings{}).(ResizableQueue)
+	blockCh := make(chan struct{})
+	inFlight := &atomic.Int64{}
+	require.NoError(t, q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(Request) {
+		inFlight.Add(1)
+		<-blockCh
+		inFlight.Add(-1)
+	})))
+
+	require.NoError(t, q.Resize(10, 3))
+	for i := 0; i < 5; i++ {
+		assert.True(t, q.Produce(newStringRequest(fmt.Sprintf("%d", i))))
+	}
+	assert.Eventually(t, func() bool { return inFlight.Load() == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, 2, q.Size())
+
+	// The stopped consumers finish their current items.
+	require.NoError(t, q.Resize(10, 1))
+	close(blockCh)
+	assert.Eventually(t, func() bool { return q.Size() == 0 && inFlight.Load() == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, q.Shutdown(context.Background()))
+}
+
+func TestBoundedQueueResize_Invalid(t *testing.T) {
+	q := NewBoundedMemoryQueue(10, 1, RequestsSizer{}, OverflowSettings{}).(ResizableQueue)
+	assert.EqualError(t, q.Resize(0, 1), "queue capacity must be positive")
+	assert.EqualError(t, q.Resize(10, 0), "number of queue consumers must be positive")
+}
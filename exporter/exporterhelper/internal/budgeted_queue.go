@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+
+import (
+	"go.opentelemetry.io/collector/extension/experimental/memorybudget"
+)
+
+// budgetedQueue admits the items to the wrapped queue only if their estimated size in bytes fits
+// in the memory budget shared with the other components.
+type budgetedQueue struct {
+	Queue
+	budget memorybudget.Budget
+	sizeOf func(item Request) int64
+}
+
+// NewBudgetedQueue wraps the queue to acquire the estimated size of every item from the memory budget until the
+// processing of the item is finished, i.e. until it is exported, or dropped. The wrapped queue must not be persistent,
+// since the budgeted queue sets the processing finished callback of the items.
+func NewBudgetedQueue(queue Queue, budget memorybudget.Budget, sizeOf func(item Request) int64) Queue {
+	return &budgetedQueue{
+		Queue:  queue,
+		budget: budget,
+		sizeOf: sizeOf,
+	}
+}
+
+// Produce adds the item to the wrapped queue if its size fits in the budget. Returns false if the item wasn't added
+// to the queue due to the budget or the queue overflow.
+func (q *budgetedQueue) Produce(item Request) bool {
+	size := q.sizeOf(item)
+	if !q.budget.TryAcquire(size) {
+		return false
+	}
+	// The callback is set before the item is added, since it can be consumed right away. An item put back to the queue
+	// is added again before its previous processing is finished, which then releases the size acquired the first time.
+	item.SetOnProcessingFinished(func() {
+		q.budget.Release(size)
+	})
+	if !q.Queue.Produce(item) {
+		q.budget.Release(size)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/experimental/memorybudget"
+)
+
+// budgetedRequest is a request whose size in bytes is the length of its string.
+type budgetedRequest struct {
+	Request
+	str        string
+	onFinished func()
+}
+
+func newBudgetedRequest(str string) *budgetedRequest {
+	return &budgetedRequest{str: str}
+}
+
+func (r *budgetedRequest) SetOnProcessingFinished(callback func()) {
+	r.onFinished = callback
+}
+
+func (r *budgetedRequest) OnProcessingFinished() {
+	if r.onFinished != nil {
+		r.onFinished()
+	}
+}
+
+func budgetedRequestSize(item Request) int64 {
+	return int64(len(item.(*budgetedRequest).str))
+}
+
+func TestBudgetedQueue(t *testing.T) {
+	budget := memorybudget.NewBudget(10)
+	// Both queues share the same budget.
+	q1 := NewBudgetedQueue(NewBoundedMemoryQueue(10, 1, RequestsSizer{}, OverflowSettings{}), budget, budgetedRequestSize)
+	q2 := NewBudgetedQueue(NewBoundedMemoryQueue(10, 1, RequestsSizer{}, OverflowSettings{}), budget, budgetedRequestSize)
+
+	assert.True(t, q1.Produce(newBudgetedRequest("aaaaaa")))
+	assert.False(t, q2.Produce(newBudgetedRequest("bbbbb")))
+	assert.True(t, q2.Produce(newBudgetedRequest("bbbb")))
+	assert.False(t, q1.Produce(newBudgetedRequest("a")))
+
+	consumed := make(chan Request, 1)
+	require.NoError(t, q1.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(item Request) {
+		consumed <- item
+	})))
+	item := <-consumed
+
+	// The consumed item is not released to the budget until its processing is finished, e.g. once it is exported.
+	assert.False(t, q2.Produce(newBudgetedRequest("bbbbbb")))
+	item.OnProcessingFinished()
+	assert.True(t, q2.Produce(newBudgetedRequest("bbbbbb")))
+	assert.NoError(t, q1.Shutdown(context.Background()))
+}
+
+func TestBudgetedQueue_QueueFull(t *testing.T) {
+	budget := memorybudget.NewBudget(10)
+	q := NewBudgetedQueue(NewBoundedMemoryQueue(1, 1, RequestsSizer{}, OverflowSettings{}), budget, budgetedRequestSize)
+
+	assert.True(t, q.Produce(newBudgetedRequest("a")))
+	// The item is rejected by the wrapped queue, so its size is released to the budget.
+	assert.False(t, q.Produce(newBudgetedRequest("b")))
+	assert.True(t, budget.TryAcquire(9))
+}
+
+func TestBudgetedQueue_DropOldest(t *testing.T) {
+	budget := memorybudget.NewBudget(10)
+	q := NewBudgetedQueue(NewBoundedMemoryQueue(1, 0, RequestsSizer{}, OverflowSettings{Policy: OverflowDropOldest}),
+		budget, budgetedRequestSize)
+	require.NoError(t, q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(Request) {})))
+
+	assert.True(t, q.Produce(newBudgetedRequest("aaaaa")))
+	assert.True(t, q.Produce(newBudgetedRequest("bbbbb")))
+	// The evicted item is released to the budget.
+	assert.True(t, budget.TryAcquire(5))
+	assert.NoError(t, q.Shutdown(context.Background()))
+}
+
+func TestBudgetedQueue_Requeued(t *testing.T) {
+	budget := memorybudget.NewBudget(10)
+	q := NewBudgetedQueue(NewBoundedMemoryQueue(10, 1, RequestsSizer{}, OverflowSettings{}), budget, budgetedRequestSize)
+	consumed := make(chan Request, 1)
+	require.NoError(t, q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(item Request) {
+		consumed <- item
+	})))
+
+	assert.True(t, q.Produce(newBudgetedRequest("aaaaa")))
+	item := <-consumed
+	// The item is put back to the queue, then its processing is finished.
+	assert.True(t, q.Produce(item))
+	item.OnProcessingFinished()
+	assert.False(t, budget.TryAcquire(6))
+
+	<-consumed
+	item.OnProcessingFinished()
+	assert.True(t, budget.TryAcquire(10))
+	assert.NoError(t, q.Shutdown(context.Background()))
+}
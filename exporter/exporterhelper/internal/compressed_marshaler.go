@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec is the compression codec of the requests stored in the persistent queue.
+// It is recorded in front of every stored request, so the requests stored with a different codec
+// can still be read after the codec is changed.
+type Codec byte
+
+const (
+	// CodecNone stores the requests uncompressed.
+	CodecNone Codec = iota
+	// CodecZstd compresses the requests with zstd.
+	CodecZstd
+	// CodecSnappy compresses the requests with snappy.
+	CodecSnappy
+)
+
+var errCompressedDataEmpty = errors.New("compressed data is empty")
+
+var (
+	// The zstd encoder and decoder are safe for concurrent use with EncodeAll and DecodeAll.
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// NewCompressedMarshalers wraps the given marshaler and unmarshaler to compress the serialized requests
+// with the given codec. The unmarshaler decompresses the requests stored with any codec.
+func NewCompressedMarshalers(codec Codec, marshaler RequestMarshaler, unmarshaler RequestUnmarshaler) (RequestMarshaler, RequestUnmarshaler) {
+	compressedMarshaler := func(req Request) ([]byte, error) {
+		buf, err := marshaler(req)
+		if err != nil {
+			return nil, err
+		}
+		switch codec {
+		case CodecZstd:
+			return zstdEncoder.EncodeAll(buf, []byte{byte(codec)}), nil
+		case CodecSnappy:
+			return append([]byte{byte(codec)}, snappy.Encode(nil, buf)...), nil
+		default:
+			return append([]byte{byte(CodecNone)}, buf...), nil
+		}
+	}
+
+	compressedUnmarshaler := func(data []byte) (Request, error) {
+		if len(data) == 0 {
+			return nil, errCompressedDataEmpty
+		}
+		var buf []byte
+		var err error
+		switch Codec(data[0]) {
+		case CodecNone:
+			buf = data[1:]
+		case CodecZstd:
+			buf, err = zstdDecoder.DecodeAll(data[1:], nil)
+		case CodecSnappy:
+			buf, err = snappy.Decode(nil, data[1:])
+		default:
+			return nil, fmt.Errorf("unknown compression codec %d", data[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress the request: %w", err)
+		}
+		return unmarshaler(buf)
+	}
+
+	return compressedMarshaler, compressedUnmarshaler
+}
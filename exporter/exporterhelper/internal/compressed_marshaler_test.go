@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCompressedMarshalers(t *testing.T) {
+	req := newFakeTracesRequest(newTraces(5, 10))
+	plain, err := newFakeTracesRequestMarshalerFunc()(req)
+	require.NoError(t, err)
+
+	for _, codec := range []Codec{CodecNone, CodecZstd, CodecSnappy} {
+		marshaler, unmarshaler := NewCompressedMarshalers(codec, newFakeTracesRequestMarshalerFunc(),
+			newFakeTracesRequestUnmarshalerFunc())
+
+		compressed, err := marshaler(req)
+		require.NoError(t, err)
+		assert.Equal(t, byte(codec), compressed[0])
+		if codec != CodecNone {
+			assert.Less(t, len(compressed), len(plain))
+		}
+
+		decompressed, err := unmarshaler(compressed)
+		require.NoError(t, err)
+		assert.Equal(t, req.td, decompressed.(*fakeTracesRequest).td)
+
+		// The requests stored with any codec can be read after the codec is changed.
+		_, zstdUnmarshaler := NewCompressedMarshalers(CodecZstd, newFakeTracesRequestMarshalerFunc(),
+			newFakeTracesRequestUnmarshalerFunc())
+		decompressed, err = zstdUnmarshaler(compressed)
+		require.NoError(t, err)
+		assert.Equal(t, req.td, decompressed.(*fakeTracesRequest).td)
+	}
+}
+
+func TestCompressedMarshalers_InvalidData(t *testing.T) {
+	_, unmarshaler := NewCompressedMarshalers(CodecSnappy, newFakeTracesRequestMarshalerFunc(),
+		newFakeTracesRequestUnmarshalerFunc())
+
+	_, err := unmarshaler(nil)
+	assert.ErrorIs(t, err, errCompressedDataEmpty)
+
+	_, err = unmarshaler([]byte{42, 1, 2, 3})
+	assert.EqualError(t, err, "unknown compression codec 42")
+
+	_, err = unmarshaler([]byte{byte(CodecZstd), 1, 2, 3})
+	assert.ErrorContains(t, err, "failed to decompress the request")
+
+	_, err = unmarshaler([]byte{byte(CodecSnappy), 0xff, 0xff, 0xff})
+	assert.ErrorContains(t, err, "failed to decompress the request")
+}
+
+func TestPersistentStorage_Compressed(t *testing.T) {
+	req := newFakeTracesRequest(newTraces(5, 10))
+	marshaler, unmarshaler := NewCompressedMarshalers(CodecZstd, newFakeTracesRequestMarshalerFunc(),
+		newFakeTracesRequestUnmarshalerFunc())
+
+	client := createTestClient(t, NewMockStorageExtension(nil))
+	ps := newPersistentContiguousStorage(context.Background(), "foo", client, zap.NewNop(), 1000, 0, RequestsSizer{},
+		CompactionSettings{}, marshaler, unmarshaler)
+	require.NoError(t, ps.put(req))
+
+	// The codec is recorded in front of the stored request.
+	stored, err := client.Get(context.Background(), getItemKey(0))
+	require.NoError(t, err)
+	require.NotEmpty(t, stored)
+	assert.Equal(t, byte(CodecZstd), stored[0])
+
+	r := <-ps.get()
+	assert.Equal(t, req.td, r.(*fakeTracesRequest).td)
+	r.OnProcessingFinished()
+	require.Eventually(t, func() bool {
+		return ps.size() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, ps.stop(context.Background()))
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var errEncryptedDataTooShort = errors.New("encrypted data too short")
+
+// NewEncryptedMarshalers wraps the given marshaler and unmarshaler to encrypt the serialized requests
+// using AES-GCM with the given key. The key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+// Every request is encrypted with a random nonce, which is stored in front of the encrypted data.
+func NewEncryptedMarshalers(key []byte, marshaler RequestMarshaler, unmarshaler RequestUnmarshaler) (RequestMarshaler, RequestUnmarshaler, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create the encryption cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create the encryption cipher: %w", err)
+	}
+
+	encryptedMarshaler := func(req Request) ([]byte, error) {
+		buf, err := marshaler(req)
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(buf)+aead.Overhead())
+		if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+			return nil, fmt.Errorf("failed to generate the encryption nonce: %w", err)
+		}
+		return aead.Seal(nonce, nonce, buf, nil), nil
+	}
+
+	encryptedUnmarshaler := func(data []byte) (Request, error) {
+		if len(data) < aead.NonceSize() {
+			return nil, errEncryptedDataTooShort
+		}
+		nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+		buf, err := aead.Open(nil, nonce, ciphertext, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt the request: %w", err)
+		}
+		return unmarshaler(buf)
+	}
+
+	return encryptedMarshaler, encryptedUnmarshaler, nil
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestEncryptedMarshalers(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	marshaler, unmarshaler, err := NewEncryptedMarshalers(key, newFakeTracesRequestMarshalerFunc(), newFakeTracesRequestUnmarshalerFunc())
+	require.NoError(t, err)
+
+	req := newFakeTracesRequest(newTraces(5, 10))
+	plain, err := newFakeTracesRequestMarshalerFunc()(req)
+	require.NoError(t, err)
+
+	encrypted, err := marshaler(req)
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(encrypted, plain))
+
+	// Every request is encrypted with a different nonce.
+	encryptedAgain, err := marshaler(req)
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, encryptedAgain)
+
+	decrypted, err := unmarshaler(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, req.td, decrypted.(*fakeTracesRequest).td)
+
+	// The data encrypted with a different key or modified cannot be decrypted.
+	_, otherUnmarshaler, err := NewEncryptedMarshalers(bytes.Repeat([]byte{2}, 32), newFakeTracesRequestMarshalerFunc(),
+		newFakeTracesRequestUnmarshalerFunc())
+	require.NoError(t, err)
+	_, err = otherUnmarshaler(encrypted)
+	assert.Error(t, err)
+
+	encrypted[len(encrypted)-1] ^= 0xff
+	_, err = unmarshaler(encrypted)
+	assert.Error(t, err)
+
+	_, err = unmarshaler([]byte{1, 2, 3})
+	assert.ErrorIs(t, err, errEncryptedDataTooShort)
+}
+
+func TestEncryptedMarshalers_InvalidKey(t *testing.T) {
+	_, _, err := NewEncryptedMarshalers([]byte("short"), newFakeTracesRequestMarshalerFunc(), newFakeTracesRequestUnmarshalerFunc())
+	assert.Error(t, err)
+}
+
+func TestPersistentStorage_Encrypted(t *testing.T) {
+	req := newFakeTracesRequest(newTraces(5, 10))
+	plain, err := newFakeTracesRequestMarshalerFunc()(req)
+	require.NoError(t, err)
+
+	marshaler, unmarshaler, err := NewEncryptedMarshalers(bytes.Repeat([]byte{1}, 16), newFakeTracesRequestMarshalerFunc(),
+		newFakeTracesRequestUnmarshalerFunc())
+	require.NoError(t, err)
+
+	client := createTestClient(t, NewMockStorageExtension(nil))
+	ps := newPersistentContiguousStorage(context.Background(), "foo", client, zap.NewNop(), 1000, 0, RequestsSizer{},
+		CompactionSettings{}, marshaler, unmarshaler)
+	require.NoError(t, ps.put(req))
+
+	// The request is not stored in plain text.
+	stored, err := client.Get(context.Background(), getItemKey(0))
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.False(t, bytes.Contains(stored, plain))
+
+	r := <-ps.get()
+	assert.Equal(t, req.td, r.(*fakeTracesRequest).td)
+	r.OnProcessingFinished()
+	require.Eventually(t, func() bool {
+		return ps.size() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, ps.stop(context.Background()))
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter"
@@ -26,6 +27,15 @@ var (
 	errWrongExtensionType = errors.New("requested extension is not a storage extension")
 )
 
+// CompactionSettings defines when the storage of the persistent queue is compacted.
+type CompactionSettings struct {
+	// Interval is the time between the periodic compactions. Zero disables the periodic compaction.
+	Interval time.Duration
+	// Threshold is the number of bytes deleted from the queue since the last compaction after which
+	// the storage is compacted. Zero disables the threshold-based compaction.
+	Threshold uint64
+}
+
 // persistentQueue holds the queue backed by file storage
 type persistentQueue struct {
 	stopWG       sync.WaitGroup
@@ -34,6 +44,9 @@ type persistentQueue struct {
 	storageID    component.ID
 	storage      *persistentContiguousStorage
 	capacity     uint64
+	maxBytes     uint64
+	sizer        Sizer
+	compaction   CompactionSettings
 	numConsumers int
 	marshaler    RequestMarshaler
 	unmarshaler  RequestUnmarshaler
@@ -45,11 +58,17 @@ func buildPersistentStorageName(name string, signal component.DataType) string {
 	return fmt.Sprintf("%s-%s", name, signal)
 }
 
-// NewPersistentQueue creates a new queue backed by file storage; name and signal must be a unique combination that identifies the queue storage
-func NewPersistentQueue(capacity int, numConsumers int, storageID component.ID, marshaler RequestMarshaler,
-	unmarshaler RequestUnmarshaler, set exporter.CreateSettings) Queue {
+// NewPersistentQueue creates a new queue backed by file storage; name and signal must be a unique combination that identifies the queue storage.
+// The capacity is measured in the units defined by the given Sizer. If maxBytes is greater than zero, requests are rejected
+// once the serialized size of the queued requests would exceed it. The storage is compacted according to the compaction settings
+// if the storage client supports it.
+func NewPersistentQueue(capacity int, maxBytes int64, sizer Sizer, compaction CompactionSettings, numConsumers int, storageID component.ID,
+	marshaler RequestMarshaler, unmarshaler RequestUnmarshaler, set exporter.CreateSettings) Queue {
 	return &persistentQueue{
 		capacity:     uint64(capacity),
+		maxBytes:     uint64(maxBytes),
+		sizer:        sizer,
+		compaction:   compaction,
 		numConsumers: numConsumers,
 		set:          set,
 		storageID:    storageID,
@@ -66,7 +85,8 @@ func (pq *persistentQueue) Start(ctx context.Context, host component.Host, set Q
 		return err
 	}
 	storageName := buildPersistentStorageName(pq.set.ID.Name(), set.DataType)
-	pq.storage = newPersistentContiguousStorage(ctx, storageName, storageClient, pq.set.Logger, pq.capacity, pq.marshaler, pq.unmarshaler)
+	pq.storage = newPersistentContiguousStorage(ctx, storageName, storageClient, pq.set.Logger, pq.capacity, pq.maxBytes, pq.sizer,
+		pq.compaction, pq.marshaler, pq.unmarshaler)
 	for i := 0; i < pq.numConsumers; i++ {
 		pq.stopWG.Add(1)
 		go func() {
@@ -99,7 +119,7 @@ func (pq *persistentQueue) Shutdown(ctx context.Context) error {
 
 // Size returns the current depth of the queue, excluding the item already in the storage channel (if any)
 func (pq *persistentQueue) Size() int {
-	return int(pq.storage.size())
+	return int(pq.storage.usedCapacity())
 }
 
 func (pq *persistentQueue) Capacity() int {
@@ -110,6 +130,22 @@ func (pq *persistentQueue) IsPersistent() bool {
 	return true
 }
 
+// ReclaimedBytes returns the total number of bytes reclaimed by compacting the storage
+func (pq *persistentQueue) ReclaimedBytes() int64 {
+	if pq.storage == nil {
+		return 0
+	}
+	return pq.storage.reclaimedBytesTotal()
+}
+
+// OldestEnqueuedAt returns the enqueue time of the oldest request in the queue, or the zero time if unknown
+func (pq *persistentQueue) OldestEnqueuedAt() time.Time {
+	if pq.storage == nil {
+		return time.Time{}
+	}
+	return pq.storage.oldestEnqueuedAt()
+}
+
 func toStorageClient(ctx context.Context, storageID component.ID, host component.Host, ownerID component.ID, signal component.DataType) (storage.Client, error) {
 	extension, err := getStorageExtension(host.GetExtensions(), storageID)
 	if err != nil {
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -49,6 +50,8 @@ type persistentContiguousStorage struct {
 	putChan  chan struct{}
 	stopChan chan struct{}
 	capacity uint64
+	maxBytes uint64
+	sizer    Sizer
 
 	reqChan chan Request
 
@@ -56,42 +59,74 @@ type persistentContiguousStorage struct {
 	readIndex                itemIndex
 	writeIndex               itemIndex
 	currentlyDispatchedItems []itemIndex
+	// enqueuedAt caches the enqueue times of the items which were not picked by consumers yet, by index.
+	// The times of the items stored before a restart are read from the storage when needed.
+	enqueuedAt map[itemIndex]time.Time
 
 	itemsCount *atomic.Uint64
+	// bytesCount is the size of the serialized items stored in the storage, including the currently dispatched ones.
+	bytesCount *atomic.Uint64
+	// queuedSize is the size of the items which were not picked by consumers yet, measured by the sizer.
+	queuedSize *atomic.Uint64
+
+	compaction  CompactionSettings
+	compactChan chan struct{}
+	compactWG   sync.WaitGroup
+	// removedBytes is the size of the items deleted from the storage since the last compaction, guarded by mu.
+	removedBytes   uint64
+	reclaimedBytes *atomic.Int64
 }
 
 type itemIndex uint64
 
 const (
-	zapKey           = "key"
-	zapQueueNameKey  = "queueName"
-	zapErrorCount    = "errorCount"
-	zapNumberOfItems = "numberOfItems"
+	zapKey            = "key"
+	zapQueueNameKey   = "queueName"
+	zapErrorCount     = "errorCount"
+	zapNumberOfItems  = "numberOfItems"
+	zapRequestBytes   = "requestBytes"
+	zapReclaimedBytes = "reclaimedBytes"
 
 	readIndexKey                = "ri"
 	writeIndexKey               = "wi"
 	currentlyDispatchedItemsKey = "di"
+	queuedBytesKey              = "qb"
+	queuedSizeKey               = "qs"
 )
 
 var (
 	errMaxCapacityReached = errors.New("max capacity reached")
+	errMaxBytesReached    = errors.New("max bytes reached")
 	errValueNotSet        = errors.New("value not set")
 )
 
 // newPersistentContiguousStorage creates a new file-storage extension backed queue;
 // queueName parameter must be a unique value that identifies the queue.
+// The capacity is measured in the units defined by the sizer.
+// If maxBytes is greater than zero, it limits the total size of the serialized requests stored in the queue,
+// the requests being dispatched being counted until they are deleted from the storage.
+// The storage is compacted according to the compaction settings if the client implements storage.Compactor.
 func newPersistentContiguousStorage(ctx context.Context, queueName string, client storage.Client,
-	logger *zap.Logger, capacity uint64, marshaler RequestMarshaler, unmarshaler RequestUnmarshaler) *persistentContiguousStorage {
+	logger *zap.Logger, capacity uint64, maxBytes uint64, sizer Sizer, compaction CompactionSettings,
+	marshaler RequestMarshaler, unmarshaler RequestUnmarshaler) *persistentContiguousStorage {
 	pcs := &persistentContiguousStorage{
-		logger:      logger.With(zap.String(zapQueueNameKey, queueName)),
-		client:      client,
-		unmarshaler: unmarshaler,
-		marshaler:   marshaler,
-		capacity:    capacity,
-		putChan:     make(chan struct{}, capacity),
-		reqChan:     make(chan Request),
-		stopChan:    make(chan struct{}),
-		itemsCount:  &atomic.Uint64{},
+		logger:         logger.With(zap.String(zapQueueNameKey, queueName)),
+		client:         client,
+		unmarshaler:    unmarshaler,
+		marshaler:      marshaler,
+		capacity:       capacity,
+		maxBytes:       maxBytes,
+		sizer:          sizer,
+		putChan:        make(chan struct{}, capacity),
+		reqChan:        make(chan Request),
+		stopChan:       make(chan struct{}),
+		itemsCount:     &atomic.Uint64{},
+		bytesCount:     &atomic.Uint64{},
+		queuedSize:     &atomic.Uint64{},
+		compaction:     compaction,
+		compactChan:    make(chan struct{}, 1),
+		reclaimedBytes: &atomic.Int64{},
+		enqueuedAt:     map[itemIndex]time.Time{},
 	}
 
 	pcs.initPersistentContiguousStorage(ctx)
@@ -109,14 +144,25 @@ func newPersistentContiguousStorage(ctx context.Context, queueName string, clien
 	// start the loop which moves items from storage to the outbound channel
 	go pcs.loop()
 
+	if compaction.Interval > 0 || compaction.Threshold > 0 {
+		if compactor, ok := client.(storage.Compactor); ok {
+			pcs.compactWG.Add(1)
+			go pcs.compactionLoop(compactor)
+		} else {
+			pcs.logger.Warn("Compaction is configured, but the storage client does not support it")
+		}
+	}
+
 	return pcs
 }
 
 func (pcs *persistentContiguousStorage) initPersistentContiguousStorage(ctx context.Context) {
 	riOp := storage.GetOperation(readIndexKey)
 	wiOp := storage.GetOperation(writeIndexKey)
+	qbOp := storage.GetOperation(queuedBytesKey)
+	qsOp := storage.GetOperation(queuedSizeKey)
 
-	err := pcs.client.Batch(ctx, riOp, wiOp)
+	err := pcs.client.Batch(ctx, riOp, wiOp, qbOp, qsOp)
 	if err == nil {
 		pcs.readIndex, err = bytesToItemIndex(riOp.Value)
 	}
@@ -125,6 +171,23 @@ func (pcs *persistentContiguousStorage) initPersistentContiguousStorage(ctx cont
 		pcs.writeIndex, err = bytesToItemIndex(wiOp.Value)
 	}
 
+	if err == nil {
+		// The queued bytes and size keys may be missing if the queue was created by an older version.
+		// In that case start counting bytes from zero, and assume that every queued request has a size of one.
+		// The bytes of the items left for dispatch are counted until they are moved back to the queue.
+		if queuedBytes, qbErr := bytesToItemIndex(qbOp.Value); qbErr == nil {
+			pcs.bytesCount.Store(uint64(queuedBytes))
+		}
+	}
+
+	if err == nil && pcs.writeIndex != pcs.readIndex {
+		if queuedSize, qsErr := bytesToItemIndex(qsOp.Value); qsErr == nil {
+			pcs.queuedSize.Store(uint64(queuedSize))
+		} else {
+			pcs.queuedSize.Store(uint64(pcs.writeIndex - pcs.readIndex))
+		}
+	}
+
 	if err != nil {
 		if errors.Is(err, errValueNotSet) {
 			pcs.logger.Info("Initializing new persistent queue")
@@ -133,6 +196,8 @@ func (pcs *persistentContiguousStorage) initPersistentContiguousStorage(ctx cont
 		}
 		pcs.readIndex = 0
 		pcs.writeIndex = 0
+		pcs.bytesCount.Store(0)
+		pcs.queuedSize.Store(0)
 	}
 
 	pcs.itemsCount.Store(uint64(pcs.writeIndex - pcs.readIndex))
@@ -142,7 +207,7 @@ func (pcs *persistentContiguousStorage) enqueueNotDispatchedReqs(reqs []Request)
 	if len(reqs) > 0 {
 		errCount := 0
 		for _, req := range reqs {
-			if req == nil || pcs.put(req) != nil {
+			if req == nil || pcs.putItem(req, false) != nil {
 				errCount++
 			}
 		}
@@ -173,6 +238,88 @@ func (pcs *persistentContiguousStorage) loop() {
 	}
 }
 
+// compactionLoop compacts the storage periodically and every time the compaction threshold is reached
+func (pcs *persistentContiguousStorage) compactionLoop(compactor storage.Compactor) {
+	defer pcs.compactWG.Done()
+	var tickerChan <-chan time.Time
+	if pcs.compaction.Interval > 0 {
+		ticker := time.NewTicker(pcs.compaction.Interval)
+		defer ticker.Stop()
+		tickerChan = ticker.C
+	}
+	for {
+		select {
+		case <-pcs.stopChan:
+			return
+		case <-tickerChan:
+		case <-pcs.compactChan:
+		}
+		pcs.compact(context.Background(), compactor)
+	}
+}
+
+// compact reclaims the space left by the items removed from the queue since the last compaction.
+// The queue is locked while compacting, as the client must not be used concurrently.
+func (pcs *persistentContiguousStorage) compact(ctx context.Context, compactor storage.Compactor) {
+	pcs.mu.Lock()
+	defer pcs.mu.Unlock()
+
+	// Nothing was removed, so there is nothing to reclaim.
+	if pcs.removedBytes == 0 {
+		return
+	}
+	pcs.removedBytes = 0
+
+	reclaimed, err := compactor.Compact(ctx)
+	if err != nil {
+		pcs.logger.Warn("Failed compacting the persistent queue storage", zap.Error(err))
+		return
+	}
+	pcs.reclaimedBytes.Add(reclaimed)
+	pcs.logger.Debug("Compacted the persistent queue storage", zap.Int64(zapReclaimedBytes, reclaimed))
+}
+
+// onRemoved accounts the bytes of an item deleted from the storage, releasing them from the stored bytes,
+// and triggers the compaction once the threshold is reached.
+func (pcs *persistentContiguousStorage) onRemoved(bytes uint64) {
+	pcs.releaseStored(bytes)
+	if err := pcs.client.Set(context.Background(), queuedBytesKey, itemIndexToBytes(itemIndex(pcs.bytesSize()))); err != nil {
+		pcs.logger.Debug("Failed updating stored bytes", zap.Error(err))
+	}
+
+	pcs.removedBytes += bytes
+	if pcs.compaction.Threshold == 0 || pcs.removedBytes < pcs.compaction.Threshold {
+		return
+	}
+	select {
+	case pcs.compactChan <- struct{}{}:
+	default:
+		// The compaction is already pending.
+	}
+}
+
+// oldestEnqueuedAt returns the enqueue time of the next item to be picked by consumers, which is the oldest one
+// as the items are picked in order, or the zero time if the queue is empty or the time is unknown.
+func (pcs *persistentContiguousStorage) oldestEnqueuedAt() time.Time {
+	pcs.mu.Lock()
+	defer pcs.mu.Unlock()
+	if pcs.readIndex == pcs.writeIndex {
+		return time.Time{}
+	}
+	if enqueuedAt, ok := pcs.enqueuedAt[pcs.readIndex]; ok {
+		return enqueuedAt
+	}
+	// The item was stored before a restart: read its enqueue time once.
+	buf, err := pcs.client.Get(context.Background(), getEnqueuedAtKey(pcs.readIndex))
+	if err != nil {
+		pcs.logger.Debug("Failed reading the enqueue time of the oldest item", zap.Error(err))
+		return time.Time{}
+	}
+	enqueuedAt := bytesToTime(buf)
+	pcs.enqueuedAt[pcs.readIndex] = enqueuedAt
+	return enqueuedAt
+}
+
 // get returns the request channel that all the requests will be send on
 func (pcs *persistentContiguousStorage) get() <-chan Request {
 	return pcs.reqChan
@@ -183,14 +330,38 @@ func (pcs *persistentContiguousStorage) size() uint64 {
 	return pcs.itemsCount.Load()
 }
 
+// bytesSize returns the total size in bytes of the serialized items stored in the queue, including the items
+// picked by consumers which were not deleted from the storage yet
+func (pcs *persistentContiguousStorage) bytesSize() uint64 {
+	return pcs.bytesCount.Load()
+}
+
+// reclaimedBytesTotal returns the total number of bytes reclaimed by compacting the storage
+func (pcs *persistentContiguousStorage) reclaimedBytesTotal() int64 {
+	return pcs.reclaimedBytes.Load()
+}
+
+// usedCapacity returns the size of the items which were not picked by consumers yet, measured by the sizer
+func (pcs *persistentContiguousStorage) usedCapacity() uint64 {
+	return pcs.queuedSize.Load()
+}
+
 func (pcs *persistentContiguousStorage) stop(ctx context.Context) error {
 	pcs.logger.Debug("Stopping persistentContiguousStorage")
 	close(pcs.stopChan)
+	// Make sure the compaction is not running while the client is being closed
+	pcs.compactWG.Wait()
 	return pcs.client.Close(ctx)
 }
 
 // put marshals the request and puts it into the persistent queue
 func (pcs *persistentContiguousStorage) put(req Request) error {
+	return pcs.putItem(req, true)
+}
+
+// putItem puts the request into the persistent queue. The limits of queued size and bytes are not enforced for the requests
+// that were already accounted before, e.g. when moving the items left for dispatch back to the queue on startup.
+func (pcs *persistentContiguousStorage) putItem(req Request, enforceLimits bool) error {
 	// Nil requests are ignored
 	if req == nil {
 		return nil
@@ -199,23 +370,38 @@ func (pcs *persistentContiguousStorage) put(req Request) error {
 	pcs.mu.Lock()
 	defer pcs.mu.Unlock()
 
-	if pcs.size() >= pcs.capacity {
+	// The number of requests is always bounded by the capacity to make sure the loop is notified about every item.
+	reqSize := pcs.sizer.SizeOf(req)
+	if pcs.size() >= pcs.capacity || (enforceLimits && pcs.usedCapacity()+reqSize > pcs.capacity) {
 		pcs.logger.Warn("Maximum queue capacity reached")
 		return errMaxCapacityReached
 	}
 
-	itemKey := getItemKey(pcs.writeIndex)
-	pcs.writeIndex++
-	pcs.itemsCount.Store(uint64(pcs.writeIndex - pcs.readIndex))
-
 	reqBuf, err := pcs.marshaler(req)
 	if err != nil {
 		return err
 	}
+
+	reqBytes := uint64(len(reqBuf))
+	if enforceLimits && pcs.maxBytes > 0 && pcs.bytesSize()+reqBytes > pcs.maxBytes {
+		pcs.logger.Warn("Maximum queue size in bytes reached", zap.Uint64(zapRequestBytes, reqBytes))
+		return errMaxBytesReached
+	}
+
+	index := pcs.writeIndex
+	pcs.writeIndex++
+	pcs.itemsCount.Store(uint64(pcs.writeIndex - pcs.readIndex))
+	pcs.bytesCount.Add(reqBytes)
+	pcs.queuedSize.Add(reqSize)
+	pcs.enqueuedAt[index] = req.EnqueuedAt()
+
 	ctx := context.Background()
 	err = pcs.client.Batch(ctx,
 		storage.SetOperation(writeIndexKey, itemIndexToBytes(pcs.writeIndex)),
-		storage.SetOperation(itemKey, reqBuf))
+		storage.SetOperation(queuedBytesKey, itemIndexToBytes(itemIndex(pcs.bytesSize()))),
+		storage.SetOperation(queuedSizeKey, itemIndexToBytes(itemIndex(pcs.usedCapacity()))),
+		storage.SetOperation(getItemKey(index), reqBuf),
+		storage.SetOperation(getEnqueuedAtKey(index), timeToBytes(req.EnqueuedAt())))
 
 	// Inform the loop that there's some data to process
 	pcs.putChan <- struct{}{}
@@ -234,20 +420,34 @@ func (pcs *persistentContiguousStorage) getNextItem(ctx context.Context) (Reques
 		pcs.readIndex++
 		pcs.itemsCount.Store(uint64(pcs.writeIndex - pcs.readIndex))
 
-		pcs.updateReadIndex(ctx)
 		pcs.itemDispatchingStart(ctx, index)
+		delete(pcs.enqueuedAt, index)
 
 		var req Request
-		itemKey := getItemKey(index)
-		buf, err := pcs.client.Get(ctx, itemKey)
+		itemOp := storage.GetOperation(getItemKey(index))
+		enqueuedAtOp := storage.GetOperation(getEnqueuedAtKey(index))
+		err := pcs.client.Batch(ctx, itemOp, enqueuedAtOp)
+		buf := itemOp.Value
 		if err == nil {
 			req, err = pcs.unmarshaler(buf)
 		}
+		if err == nil && req != nil {
+			// The enqueue time is missing if the item was stored by an older version.
+			req.SetEnqueuedAt(bytesToTime(enqueuedAtOp.Value))
+		}
+		var reqSize uint64
+		if err == nil && req != nil {
+			reqSize = pcs.sizer.SizeOf(req)
+		}
+		pcs.releaseQueued(reqSize)
+		pcs.updateReadIndex(ctx)
 
 		if err != nil || req == nil {
 			// We need to make sure that currently dispatched items list is cleaned
 			if err := pcs.itemDispatchingFinish(ctx, index); err != nil {
 				pcs.logger.Error("Error deleting item from queue", zap.Error(err))
+			} else {
+				pcs.onRemoved(uint64(len(buf)))
 			}
 
 			return nil, false
@@ -259,6 +459,8 @@ func (pcs *persistentContiguousStorage) getNextItem(ctx context.Context) (Reques
 			defer pcs.mu.Unlock()
 			if err := pcs.itemDispatchingFinish(ctx, index); err != nil {
 				pcs.logger.Error("Error deleting item from queue", zap.Error(err))
+			} else {
+				pcs.onRemoved(uint64(len(buf)))
 			}
 		})
 		return req, true
@@ -295,14 +497,16 @@ func (pcs *persistentContiguousStorage) retrieveNotDispatchedReqs(ctx context.Co
 
 	reqs = make([]Request, len(dispatchedItems))
 	retrieveBatch := make([]storage.Operation, len(dispatchedItems))
-	cleanupBatch := make([]storage.Operation, len(dispatchedItems))
+	enqueuedAtBatch := make([]storage.Operation, len(dispatchedItems))
+	cleanupBatch := make([]storage.Operation, 0, 2*len(dispatchedItems))
 	for i, it := range dispatchedItems {
-		key := getItemKey(it)
-		retrieveBatch[i] = storage.GetOperation(key)
-		cleanupBatch[i] = storage.DeleteOperation(key)
+		retrieveBatch[i] = storage.GetOperation(getItemKey(it))
+		enqueuedAtBatch[i] = storage.GetOperation(getEnqueuedAtKey(it))
+		cleanupBatch = append(cleanupBatch, storage.DeleteOperation(getItemKey(it)),
+			storage.DeleteOperation(getEnqueuedAtKey(it)))
 	}
 
-	retrieveErr := pcs.client.Batch(ctx, retrieveBatch...)
+	retrieveErr := pcs.client.Batch(ctx, append(retrieveBatch, enqueuedAtBatch...)...)
 	cleanupErr := pcs.client.Batch(ctx, cleanupBatch...)
 
 	if retrieveErr != nil {
@@ -317,6 +521,13 @@ func (pcs *persistentContiguousStorage) retrieveNotDispatchedReqs(ctx context.Co
 		return reqs
 	}
 
+	// The bytes of the items left for dispatch are counted again when they are moved back to the queue.
+	if cleanupErr == nil {
+		for _, op := range retrieveBatch {
+			pcs.releaseStored(uint64(len(op.Value)))
+		}
+	}
+
 	for i, op := range retrieveBatch {
 		if op.Value == nil {
 			pcs.logger.Warn("Failed unmarshalling item", zap.String(zapKey, op.Key), zap.Error(errValueNotSet))
@@ -331,6 +542,8 @@ func (pcs *persistentContiguousStorage) retrieveNotDispatchedReqs(ctx context.Co
 		if req == nil {
 			pcs.logger.Debug("Item value could not be retrieved", zap.String(zapKey, op.Key), zap.Error(err))
 		} else {
+			// The item keeps its enqueue time when it is moved back to the queue.
+			req.SetEnqueuedAt(bytesToTime(enqueuedAtBatch[i].Value))
 			reqs[i] = req
 		}
 	}
@@ -360,7 +573,8 @@ func (pcs *persistentContiguousStorage) itemDispatchingFinish(ctx context.Contex
 
 	setOp := storage.SetOperation(currentlyDispatchedItemsKey, itemIndexArrayToBytes(pcs.currentlyDispatchedItems))
 	deleteOp := storage.DeleteOperation(getItemKey(index))
-	if err := pcs.client.Batch(ctx, setOp, deleteOp); err != nil {
+	deleteEnqueuedAtOp := storage.DeleteOperation(getEnqueuedAtKey(index))
+	if err := pcs.client.Batch(ctx, setOp, deleteOp, deleteEnqueuedAtOp); err != nil {
 		// got an error, try to gracefully handle it
 		pcs.logger.Warn("Failed updating currently dispatched items, trying to delete the item first", zap.Error(err))
 	} else {
@@ -368,7 +582,7 @@ func (pcs *persistentContiguousStorage) itemDispatchingFinish(ctx context.Contex
 		return nil
 	}
 
-	if err := pcs.client.Batch(ctx, deleteOp); err != nil {
+	if err := pcs.client.Batch(ctx, deleteOp, deleteEnqueuedAtOp); err != nil {
 		// Return an error here, as this indicates an issue with the underlying storage medium
 		return fmt.Errorf("failed deleting item from queue, got error from storage: %w", err)
 	}
@@ -383,16 +597,61 @@ func (pcs *persistentContiguousStorage) itemDispatchingFinish(ctx context.Contex
 }
 
 func (pcs *persistentContiguousStorage) updateReadIndex(ctx context.Context) {
-	err := pcs.client.Set(ctx, readIndexKey, itemIndexToBytes(pcs.readIndex))
+	err := pcs.client.Batch(ctx,
+		storage.SetOperation(readIndexKey, itemIndexToBytes(pcs.readIndex)),
+		storage.SetOperation(queuedBytesKey, itemIndexToBytes(itemIndex(pcs.bytesSize()))),
+		storage.SetOperation(queuedSizeKey, itemIndexToBytes(itemIndex(pcs.usedCapacity()))))
 	if err != nil {
 		pcs.logger.Debug("Failed updating read index", zap.Error(err))
 	}
 }
 
+// releaseQueued decreases the queued size by the one of an item that was taken out of the queue.
+// The size of an item that cannot be read is unknown, so the counter is reset once the queue is empty
+// to make sure it does not drift over time.
+func (pcs *persistentContiguousStorage) releaseQueued(size uint64) {
+	releaseCounter(pcs.queuedSize, size, pcs.readIndex == pcs.writeIndex)
+}
+
+// releaseStored decreases the stored bytes by the ones of an item that was deleted from the storage.
+// The counter is reset once no item is stored anymore to make sure it does not drift over time.
+func (pcs *persistentContiguousStorage) releaseStored(bytes uint64) {
+	releaseCounter(pcs.bytesCount, bytes, pcs.readIndex == pcs.writeIndex && len(pcs.currentlyDispatchedItems) == 0)
+}
+
+func releaseCounter(counter *atomic.Uint64, value uint64, reset bool) {
+	if reset || value > counter.Load() {
+		counter.Store(0)
+		return
+	}
+	counter.Add(^(value - 1))
+}
+
 func getItemKey(index itemIndex) string {
 	return strconv.FormatUint(uint64(index), 10)
 }
 
+// getEnqueuedAtKey returns the key of the enqueue time of the item with the given index.
+func getEnqueuedAtKey(index itemIndex) string {
+	return "t" + strconv.FormatUint(uint64(index), 10)
+}
+
+// timeToBytes encodes the time in Unix nanoseconds, or as an empty value for the zero time.
+func timeToBytes(t time.Time) []byte {
+	if t.IsZero() {
+		return []byte{}
+	}
+	return binary.LittleEndian.AppendUint64([]byte{}, uint64(t.UnixNano()))
+}
+
+// bytesToTime decodes the time encoded by timeToBytes, it returns the zero time if the value is missing or invalid.
+func bytesToTime(b []byte) time.Time {
+	if len(b) != 8 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(binary.LittleEndian.Uint64(b)))
+}
+
 func itemIndexToBytes(value itemIndex) []byte {
 	return binary.LittleEndian.AppendUint64([]byte{}, uint64(value))
 }
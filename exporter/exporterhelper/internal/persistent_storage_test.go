@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
@@ -29,7 +30,15 @@ func createTestClient(t testing.TB, extension storage.Extension) storage.Client
 }
 
 func createTestPersistentStorageWithCapacity(client storage.Client, capacity uint64) *persistentContiguousStorage {
-	return newPersistentContiguousStorage(context.Background(), "foo", client, zap.NewNop(), capacity,
+	return createTestPersistentStorageWithLimits(client, capacity, 0)
+}
+
+func createTestPersistentStorageWithLimits(client storage.Client, capacity uint64, maxBytes uint64) *persistentContiguousStorage {
+	return createTestPersistentStorageWithSizer(client, capacity, maxBytes, RequestsSizer{})
+}
+
+func createTestPersistentStorageWithSizer(client storage.Client, capacity uint64, maxBytes uint64, sizer Sizer) *persistentContiguousStorage {
+	return newPersistentContiguousStorage(context.Background(), "foo", client, zap.NewNop(), capacity, maxBytes, sizer, CompactionSettings{},
 		newFakeTracesRequestMarshalerFunc(), newFakeTracesRequestUnmarshalerFunc())
 }
 
@@ -40,6 +49,7 @@ func createTestPersistentStorage(client storage.Client) *persistentContiguousSto
 type fakeTracesRequest struct {
 	td                         ptrace.Traces
 	processingFinishedCallback func()
+	enqueuedAt                 time.Time
 	Request
 }
 
@@ -49,6 +59,10 @@ func newFakeTracesRequest(td ptrace.Traces) *fakeTracesRequest {
 	}
 }
 
+func (fd *fakeTracesRequest) Count() int {
+	return fd.td.SpanCount()
+}
+
 func (fd *fakeTracesRequest) OnProcessingFinished() {
 	if fd.processingFinishedCallback != nil {
 		fd.processingFinishedCallback()
@@ -59,6 +73,14 @@ func (fd *fakeTracesRequest) SetOnProcessingFinished(callback func()) {
 	fd.processingFinishedCallback = callback
 }
 
+func (fd *fakeTracesRequest) EnqueuedAt() time.Time {
+	return fd.enqueuedAt
+}
+
+func (fd *fakeTracesRequest) SetEnqueuedAt(enqueuedAt time.Time) {
+	fd.enqueuedAt = enqueuedAt
+}
+
 func newFakeTracesRequestUnmarshalerFunc() RequestUnmarshaler {
 	return func(bytes []byte) (Request, error) {
 		unmarshaler := ptrace.ProtoUnmarshaler{}
@@ -289,6 +311,57 @@ func TestPersistentStorage_StartWithNonDispatched(t *testing.T) {
 	}, 5*time.Second, 10*time.Millisecond)
 }
 
+func TestPersistentStorage_EnqueuedAt(t *testing.T) {
+	ext := NewMockStorageExtension(nil)
+	client := createTestClient(t, ext)
+	ps := createTestPersistentStorage(client)
+	assert.True(t, ps.oldestEnqueuedAt().IsZero())
+
+	times := []time.Time{time.Unix(1, 0), time.Unix(2, 0), time.Unix(3, 0)}
+	for _, enqueuedAt := range times {
+		req := newFakeTracesRequest(newTraces(1, 1))
+		req.SetEnqueuedAt(enqueuedAt)
+		require.NoError(t, ps.put(req))
+	}
+	// The first item is picked by the loop, the next one is the oldest in the queue.
+	require.Eventually(t, func() bool { return ps.size() == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, times[1], ps.oldestEnqueuedAt())
+	assert.NoError(t, ps.stop(context.Background()))
+
+	// Reload, the item left for dispatch is moved back to the queue with its enqueue time, and the second one is picked.
+	newPs := createTestPersistentStorage(client)
+	require.Eventually(t, func() bool { return newPs.size() == 2 }, 5*time.Second, 10*time.Millisecond)
+	// The enqueue time of the item stored before the restart is read from the storage.
+	assert.Equal(t, times[2], newPs.oldestEnqueuedAt())
+
+	for _, want := range []time.Time{times[1], times[2], times[0]} {
+		req := <-newPs.get()
+		assert.Equal(t, want, req.EnqueuedAt())
+		req.OnProcessingFinished()
+	}
+	assert.True(t, newPs.oldestEnqueuedAt().IsZero())
+	assert.NoError(t, newPs.stop(context.Background()))
+}
+
+func TestPersistentStorage_EnqueuedAtOlderVersion(t *testing.T) {
+	ext := NewMockStorageExtension(nil)
+	client := createTestClient(t, ext)
+	ps := createTestPersistentStorage(client)
+
+	req := newFakeTracesRequest(newTraces(1, 1))
+	req.SetEnqueuedAt(time.Unix(1, 0))
+	require.NoError(t, ps.put(req))
+	require.Eventually(t, func() bool { return ps.size() == 0 }, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, ps.stop(context.Background()))
+	// The items stored by an older version have no enqueue time.
+	require.NoError(t, client.Delete(context.Background(), getEnqueuedAtKey(0)))
+
+	newPs := createTestPersistentStorage(client)
+	readReq := <-newPs.get()
+	assert.True(t, readReq.EnqueuedAt().IsZero())
+	assert.NoError(t, newPs.stop(context.Background()))
+}
+
 func TestPersistentStorage_PutCloseReadClose(t *testing.T) {
 	req := newFakeTracesRequest(newTraces(5, 10))
 	ext := NewMockStorageExtension(nil)
@@ -478,6 +551,84 @@ func TestPersistentStorage_StorageFull(t *testing.T) {
 	require.NoError(t, ps.put(req))
 }
 
+func TestPersistentStorage_MaxBytes(t *testing.T) {
+	req := newFakeTracesRequest(newTraces(5, 10))
+	marshaled, err := newFakeTracesRequestMarshalerFunc()(req)
+	require.NoError(t, err)
+	reqSize := uint64(len(marshaled))
+
+	ext := NewMockStorageExtension(nil)
+	client := createTestClient(t, ext)
+	ps := createTestPersistentStorageWithLimits(client, 1000, 3*reqSize)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, ps.put(req))
+	}
+	assert.ErrorIs(t, ps.put(req), errMaxBytesReached)
+
+	// The loop takes one item out of the queue and waits for a consumer, the item still counts until it is
+	// deleted from the storage.
+	require.Eventually(t, func() bool {
+		return ps.size() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 3*reqSize, ps.bytesSize())
+	assert.ErrorIs(t, ps.put(req), errMaxBytesReached)
+
+	// Once the item is processed, one more request fits.
+	r := <-ps.get()
+	r.OnProcessingFinished()
+	require.NoError(t, ps.put(req))
+	assert.Equal(t, 3*reqSize, ps.bytesSize())
+	assert.ErrorIs(t, ps.put(req), errMaxBytesReached)
+
+	// The stored bytes must survive a restart, the item left for dispatch being counted once when it is moved
+	// back to the queue.
+	require.NoError(t, ps.stop(context.Background()))
+	ps = createTestPersistentStorageWithLimits(createTestClient(t, ext), 1000, 3*reqSize)
+	require.Eventually(t, func() bool {
+		return ps.size() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 3*reqSize, ps.bytesSize())
+	assert.ErrorIs(t, ps.put(req), errMaxBytesReached)
+
+	// Draining the queue releases the bytes.
+	for i := uint64(0); i < 3; i++ {
+		r := <-ps.get()
+		r.OnProcessingFinished()
+	}
+	assert.Equal(t, uint64(0), ps.bytesSize())
+	require.NoError(t, ps.put(req))
+}
+
+func TestPersistentStorage_ItemsSizer(t *testing.T) {
+	// Every request has 50 spans.
+	req := newFakeTracesRequest(newTraces(5, 10))
+
+	ext := NewMockStorageExtension(nil)
+	ps := createTestPersistentStorageWithSizer(createTestClient(t, ext), 120, 0, ItemsSizer{})
+
+	require.NoError(t, ps.put(req))
+	require.NoError(t, ps.put(req))
+	assert.ErrorIs(t, ps.put(req), errMaxCapacityReached)
+
+	// The loop takes one request out of the queue and waits for a consumer.
+	require.Eventually(t, func() bool {
+		return ps.usedCapacity() == 50
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, ps.put(req))
+	assert.Equal(t, uint64(100), ps.usedCapacity())
+
+	// The used capacity must survive a restart, the item left for dispatch is moved back to the queue
+	// even though it exceeds the capacity.
+	require.NoError(t, ps.stop(context.Background()))
+	ps = createTestPersistentStorageWithSizer(createTestClient(t, ext), 120, 0, ItemsSizer{})
+	require.Eventually(t, func() bool {
+		return ps.size() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint64(100), ps.usedCapacity())
+	assert.ErrorIs(t, ps.put(req), errMaxCapacityReached)
+}
+
 func TestPersistentStorage_ItemDispatchingFinish_ErrorHandling(t *testing.T) {
 	errDeletingItem := fmt.Errorf("error deleting item")
 	errUpdatingDispatched := fmt.Errorf("error updating dispatched items")
@@ -530,6 +681,85 @@ func TestPersistentStorage_ItemDispatchingFinish_ErrorHandling(t *testing.T) {
 	}
 }
 
+func TestPersistentStorage_CompactionThreshold(t *testing.T) {
+	req := newFakeTracesRequest(newTraces(5, 10))
+	marshaled, err := newFakeTracesRequestMarshalerFunc()(req)
+	require.NoError(t, err)
+	reqSize := uint64(len(marshaled))
+
+	client := newFakeCompactingStorageClient(createTestClient(t, NewMockStorageExtension(nil)), 100)
+	ps := newPersistentContiguousStorage(context.Background(), "foo", client, zap.NewNop(), 1000, 0, RequestsSizer{},
+		CompactionSettings{Threshold: 2 * reqSize}, newFakeTracesRequestMarshalerFunc(), newFakeTracesRequestUnmarshalerFunc())
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, ps.put(req))
+	}
+
+	// Taking a single item out does not reach the threshold.
+	r := <-ps.get()
+	r.OnProcessingFinished()
+	assert.Never(t, func() bool {
+		return client.getCompactCount() > 0
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	// The second item reaches the threshold.
+	r = <-ps.get()
+	r.OnProcessingFinished()
+	require.Eventually(t, func() bool {
+		return client.getCompactCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(100), ps.reclaimedBytesTotal())
+
+	require.NoError(t, ps.stop(context.Background()))
+}
+
+func TestPersistentStorage_CompactionInterval(t *testing.T) {
+	req := newFakeTracesRequest(newTraces(5, 10))
+
+	client := newFakeCompactingStorageClient(createTestClient(t, NewMockStorageExtension(nil)), 100)
+	ps := newPersistentContiguousStorage(context.Background(), "foo", client, zap.NewNop(), 1000, 0, RequestsSizer{},
+		CompactionSettings{Interval: 10 * time.Millisecond}, newFakeTracesRequestMarshalerFunc(), newFakeTracesRequestUnmarshalerFunc())
+
+	// Nothing is compacted while no items are removed from the queue.
+	assert.Never(t, func() bool {
+		return client.getCompactCount() > 0
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	require.NoError(t, ps.put(req))
+	r := <-ps.get()
+	r.OnProcessingFinished()
+	require.Eventually(t, func() bool {
+		return client.getCompactCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(100), ps.reclaimedBytesTotal())
+
+	// The compaction errors are not fatal.
+	client.compactErr = errors.New("compaction failed")
+	require.NoError(t, ps.put(req))
+	r = <-ps.get()
+	r.OnProcessingFinished()
+	require.Eventually(t, func() bool {
+		return client.getCompactCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(100), ps.reclaimedBytesTotal())
+
+	require.NoError(t, ps.stop(context.Background()))
+}
+
+func TestPersistentStorage_CompactionNotSupported(t *testing.T) {
+	req := newFakeTracesRequest(newTraces(5, 10))
+
+	ps := newPersistentContiguousStorage(context.Background(), "foo", createTestClient(t, NewMockStorageExtension(nil)), zap.NewNop(),
+		1000, 0, RequestsSizer{}, CompactionSettings{Interval: 10 * time.Millisecond, Threshold: 1},
+		newFakeTracesRequestMarshalerFunc(), newFakeTracesRequestUnmarshalerFunc())
+
+	require.NoError(t, ps.put(req))
+	r := <-ps.get()
+	r.OnProcessingFinished()
+	assert.Equal(t, int64(0), ps.reclaimedBytesTotal())
+	require.NoError(t, ps.stop(context.Background()))
+}
+
 func requireCurrentlyDispatchedItemsEqual(t *testing.T, pcs *persistentContiguousStorage, compare []itemIndex) {
 	require.Eventually(t, func() bool {
 		pcs.mu.Lock()
@@ -695,3 +925,30 @@ func (m *fakeStorageClientWithErrors) Reset() {
 	defer m.mux.Unlock()
 	m.nextErrorIndex = 0
 }
+
+func newFakeCompactingStorageClient(client storage.Client, reclaimedBytes int64) *fakeCompactingStorageClient {
+	return &fakeCompactingStorageClient{
+		Client:         client,
+		reclaimedBytes: reclaimedBytes,
+	}
+}
+
+// this storage client counts the compactions and reports a fixed number of reclaimed bytes for every one of them
+type fakeCompactingStorageClient struct {
+	storage.Client
+	reclaimedBytes int64
+	compactErr     error
+	compactCount   atomic.Int64
+}
+
+func (m *fakeCompactingStorageClient) Compact(_ context.Context) (int64, error) {
+	m.compactCount.Add(1)
+	if m.compactErr != nil {
+		return 0, m.compactErr
+	}
+	return m.reclaimedBytes, nil
+}
+
+func (m *fakeCompactingStorageClient) getCompactCount() int64 {
+	return m.compactCount.Load()
+}
@@ -7,6 +7,7 @@ package internal // import "go.opentelemetry.io/collector/exporter/exporterhelpe
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 )
@@ -14,6 +15,27 @@ import (
 type QueueSettings struct {
 	DataType component.DataType
 	Callback func(item Request)
+	// DropCallback, if set, is called for the items evicted from the queue to admit new ones.
+	DropCallback func(item Request)
+}
+
+// OverflowPolicy defines what the queue does with a new item when it is full.
+type OverflowPolicy int
+
+const (
+	// OverflowReject rejects the new item.
+	OverflowReject OverflowPolicy = iota
+	// OverflowDropOldest evicts the oldest items to admit the new one.
+	OverflowDropOldest
+	// OverflowBlock waits for the free space up to the block timeout, then rejects the new item.
+	OverflowBlock
+)
+
+// OverflowSettings defines the behavior of the queue when it is full.
+type OverflowSettings struct {
+	Policy OverflowPolicy
+	// BlockTimeout is the maximum time to wait for the free space with the OverflowBlock policy.
+	BlockTimeout time.Duration
 }
 
 // Queue defines a producer-consumer exchange which can be backed by e.g. the memory-based ring buffer queue
@@ -35,3 +57,32 @@ type Queue interface {
 	// TODO: Do not expose this method if the interface moves to a public package.
 	IsPersistent() bool
 }
+
+// Sizer determines the size of a request in the units the queue capacity is measured in.
+type Sizer interface {
+	// SizeOf returns the size of the request.
+	SizeOf(req Request) uint64
+}
+
+// RequestsSizer measures the queue capacity in number of requests.
+type RequestsSizer struct{}
+
+// SizeOf returns 1 for every request.
+func (RequestsSizer) SizeOf(Request) uint64 {
+	return 1
+}
+
+// ItemsSizer measures the queue capacity in number of items (spans, metric data points or log records).
+type ItemsSizer struct{}
+
+// SizeOf returns the number of items in the request.
+func (ItemsSizer) SizeOf(req Request) uint64 {
+	return uint64(req.Count())
+}
+
+// ResizableQueue is a Queue whose capacity and number of consumers can be changed while it is running.
+type ResizableQueue interface {
+	Queue
+	// Resize changes the capacity of the queue and the number of consumers without dropping the items in the queue.
+	Resize(capacity int, numConsumers int) error
+}
@@ -3,7 +3,10 @@
 
 package internal // import "go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Request defines capabilities required for persistent storage of a request
 type Request interface {
@@ -27,6 +30,12 @@ type Request interface {
 
 	// SetOnProcessingFinished allows to set an optional callback function to do the cleanup (e.g. remove the item from persistent queue)
 	SetOnProcessingFinished(callback func())
+
+	// EnqueuedAt returns the time the request was added to the queue, or the zero time if unknown.
+	EnqueuedAt() time.Time
+
+	// SetEnqueuedAt records the time the request is added to the queue. The persistent queue stores it with the request.
+	SetEnqueuedAt(time.Time)
 }
 
 // RequestUnmarshaler defines a function which takes a byte slice and unmarshals it into a relevant request
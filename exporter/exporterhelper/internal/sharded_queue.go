@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// ShardKeyFunc returns the key of the shard the request belongs to, e.g. the tenant the data was received from.
+type ShardKeyFunc func(item Request) string
+
+// shardedQueue limits the share of the wrapped queue a single shard can take, so the items of one shard
+// filling the queue cannot prevent the items of the other shards from being added to it.
+// The items of all the shards are still consumed from the wrapped queue in the order they were added.
+type shardedQueue struct {
+	Queue
+	sizer         Sizer
+	shardCapacity uint64
+	shardKey      ShardKeyFunc
+
+	// mu protects the sizes of the shards, the shards are removed once they are empty.
+	mu     sync.Mutex
+	shards map[string]uint64
+}
+
+// NewShardedQueue wraps the queue to limit the size of every shard to the given capacity. The capacity is measured
+// in the units defined by the given Sizer. The shard keys of the items must not change while they are in the queue.
+func NewShardedQueue(queue Queue, shardCapacity int, sizer Sizer, shardKey ShardKeyFunc) Queue {
+	return &shardedQueue{
+		Queue:         queue,
+		sizer:         sizer,
+		shardCapacity: uint64(shardCapacity),
+		shardKey:      shardKey,
+		shards:        make(map[string]uint64),
+	}
+}
+
+// Start starts the wrapped queue, the items are released from their shards before they are passed
+// to the callbacks.
+func (q *shardedQueue) Start(ctx context.Context, host component.Host, set QueueSettings) error {
+	callback := set.Callback
+	set.Callback = func(item Request) {
+		q.release(item)
+		callback(item)
+	}
+	if dropCallback := set.DropCallback; dropCallback != nil {
+		set.DropCallback = func(item Request) {
+			q.release(item)
+			dropCallback(item)
+		}
+	} else {
+		set.DropCallback = q.release
+	}
+	return q.Queue.Start(ctx, host, set)
+}
+
+// Produce adds the item to the wrapped queue if its shard is not full. Returns false if the item wasn't added
+// to the queue due to the shard or the queue overflow.
+func (q *shardedQueue) Produce(item Request) bool {
+	if !q.reserve(item) {
+		return false
+	}
+	if !q.Queue.Produce(item) {
+		q.release(item)
+		return false
+	}
+	return true
+}
+
+// reserve accounts the item in its shard if there is enough free space.
+func (q *shardedQueue) reserve(item Request) bool {
+	key, size := q.shardKey(item), q.sizer.SizeOf(item)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shards[key]+size > q.shardCapacity {
+		return false
+	}
+	q.shards[key] += size
+	return true
+}
+
+// release frees the space taken by the item in its shard.
+func (q *shardedQueue) release(item Request) {
+	key, size := q.shardKey(item), q.sizer.SizeOf(item)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shards[key] <= size {
+		delete(q.shards, key)
+		return
+	}
+	q.shards[key] -= size
+}
+
+// shardSize returns the current size of the shard.
+func (q *shardedQueue) shardSize(key string) uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.shards[key]
+}
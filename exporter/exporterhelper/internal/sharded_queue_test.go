@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+// firstLetterShardKey uses the first letter of the stringRequest as the shard key.
+func firstLetterShardKey(item Request) string {
+	return item.(stringRequest).str[:1]
+}
+
+func TestShardedQueue(t *testing.T) {
+	q := NewShardedQueue(NewBoundedMemoryQueue(10, 1, RequestsSizer{}, OverflowSettings{}), 2, RequestsSizer{}, firstLetterShardKey)
+	sq := q.(*shardedQueue)
+
+	assert.True(t, q.Produce(newStringRequest("a1")))
+	assert.True(t, q.Produce(newStringRequest("a2")))
+	// The shard "a" is full, but the other shards can still use the queue.
+	assert.False(t, q.Produce(newStringRequest("a3")))
+	assert.True(t, q.Produce(newStringRequest("b1")))
+	assert.Equal(t, uint64(2), sq.shardSize("a"))
+	assert.Equal(t, uint64(1), sq.shardSize("b"))
+	assert.Equal(t, 3, q.Size())
+
+	var mu sync.Mutex
+	var consumed []string
+	require.NoError(t, q.Start(context.Background(), componenttest.NewNopHost(), newNopQueueSettings(func(item Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		consumed = append(consumed, item.(stringRequest).str)
+	})))
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(consumed) == 3
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"a1", "a2", "b1"}, consumed)
+
+	// The consumed items are released from their shards, and the empty shards are removed.
+	assert.Zero(t, sq.shardSize("a"))
+	assert.Empty(t, sq.shards)
+	assert.True(t, q.Produce(newStringRequest("a3")))
+	assert.NoError(t, q.Shutdown(context.Background()))
+}
+
+func TestShardedQueue_QueueFull(t *testing.T) {
+	q := NewShardedQueue(NewBoundedMemoryQueue(2, 1, RequestsSizer{}, OverflowSettings{}), 2, RequestsSizer{}, firstLetterShardKey)
+
+	assert.True(t, q.Produce(newStringRequest("a1")))
+	assert.True(t, q.Produce(newStringRequest("b1")))
+	// The item is rejected by the wrapped queue, so it is not accounted in its shard.
+	assert.False(t, q.Produce(newStringRequest("b2")))
+	assert.Equal(t, uint64(1), q.(*shardedQueue).shardSize("b"))
+}
+
+func TestShardedQueue_DropOldest(t *testing.T) {
+	q := NewShardedQueue(NewBoundedMemoryQueue(2, 0, RequestsSizer{}, OverflowSettings{Policy: OverflowDropOldest}), 2,
+		RequestsSizer{}, firstLetterShardKey)
+	var dropped []string
+	require.NoError(t, q.Start(context.Background(), componenttest.NewNopHost(), QueueSettings{
+		Callback: func(Request) {},
+		DropCallback: func(item Request) {
+			dropped = append(dropped, item.(stringRequest).str)
+		},
+	}))
+
+	assert.True(t, q.Produce(newStringRequest("a1")))
+	assert.True(t, q.Produce(newStringRequest("a2")))
+	// The oldest item is evicted from the queue and released from its shard.
+	assert.True(t, q.Produce(newStringRequest("b1")))
+	assert.Equal(t, []string{"a1"}, dropped)
+	assert.Equal(t, uint64(1), q.(*shardedQueue).shardSize("a"))
+	assert.True(t, q.Produce(newStringRequest("a3")))
+	assert.NoError(t, q.Shutdown(context.Background()))
+}
+
+func TestShardedQueue_ItemsSizer(t *testing.T) {
+	q := NewShardedQueue(NewBoundedMemoryQueue(100, 1, ItemsSizer{}, OverflowSettings{}), 10, ItemsSizer{},
+		func(Request) string { return "tenant" })
+
+	assert.True(t, q.Produce(newFakeTracesRequest(newTraces(1, 6))))
+	assert.False(t, q.Produce(newFakeTracesRequest(newTraces(1, 6))))
+	assert.True(t, q.Produce(newFakeTracesRequest(newTraces(1, 4))))
+	assert.Equal(t, uint64(10), q.(*shardedQueue).shardSize("tenant"))
+}
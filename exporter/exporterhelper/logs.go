@@ -64,6 +64,10 @@ func (req *logsRequest) Count() int {
 	return req.ld.LogRecordCount()
 }
 
+func (req *logsRequest) BytesCount() int {
+	return logsMarshaler.LogsSize(req.ld)
+}
+
 type logsExporter struct {
 	*baseExporter
 	consumer.Logs
@@ -64,6 +64,10 @@ func (req *metricsRequest) Count() int {
 	return req.md.DataPointCount()
 }
 
+func (req *metricsRequest) BytesCount() int {
+	return metricsMarshaler.MetricsSize(req.md)
+}
+
 type metricsExporter struct {
 	*baseExporter
 	consumer.Metrics
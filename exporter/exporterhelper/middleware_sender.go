@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// RequestSender is a stage of the exporter helper that sends requests to the next stage.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type RequestSender interface {
+	// Send sends the request to the next stage. The context is used for sending the request,
+	// e.g. it can carry the tenant information or a deadline.
+	Send(ctx context.Context, req Request) error
+}
+
+// RequestSenderFunc is a helper function that is similar to RequestSender.Send.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type RequestSenderFunc func(ctx context.Context, req Request) error
+
+// Send calls f(ctx, req).
+func (f RequestSenderFunc) Send(ctx context.Context, req Request) error {
+	return f(ctx, req)
+}
+
+// WithSenderMiddleware adds a custom stage to the exporter helper, e.g. for tenant routing, signing or auditing.
+// The middleware receives the next stage and returns the stage that wraps it. It is called for every attempt
+// to send a request, after the queue, retry and concurrency stages, and before the timeout is applied.
+// The middlewares are called in the order they are added. A middleware should pass the request it received
+// to the next stage, otherwise the passed request is exported as is.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func WithSenderMiddleware(middleware func(next RequestSender) RequestSender) Option {
+	return func(o *baseExporter) {
+		ms, ok := o.middlewareSender.(*middlewareSender)
+		if !ok {
+			ms = &middlewareSender{}
+			o.middlewareSender = ms
+		}
+		ms.middlewares = append(ms.middlewares, middleware)
+	}
+}
+
+// middlewareSender is a requestSender that passes the requests through the custom middlewares.
+type middlewareSender struct {
+	baseRequestSender
+	middlewares []func(next RequestSender) RequestSender
+	sender      RequestSender
+}
+
+func (ms *middlewareSender) setNextSender(nextSender requestSender) {
+	ms.baseRequestSender.setNextSender(nextSender)
+	var sender RequestSender = RequestSenderFunc(func(ctx context.Context, req Request) error {
+		r, ok := req.(internal.Request)
+		if !ok {
+			r = newRequest(ctx, req)
+		}
+		r.SetContext(ctx)
+		return nextSender.send(r)
+	})
+	for i := len(ms.middlewares) - 1; i >= 0; i-- {
+		sender = ms.middlewares[i](sender)
+	}
+	ms.sender = sender
+}
+
+// send implements the requestSender interface
+func (ms *middlewareSender) send(req internal.Request) error {
+	return ms.sender.Send(req.Context(), req)
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type tenantKey struct{}
+
+func TestWithSenderMiddleware(t *testing.T) {
+	var calls []string
+	middleware := func(name string) func(next RequestSender) RequestSender {
+		return func(next RequestSender) RequestSender {
+			return RequestSenderFunc(func(ctx context.Context, req Request) error {
+				calls = append(calls, name)
+				return next.Send(context.WithValue(ctx, tenantKey{}, name), req)
+			})
+		}
+	}
+
+	var tenant any
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeTracesExporterConfig,
+		func(ctx context.Context, td ptrace.Traces) error {
+			tenant = ctx.Value(tenantKey{})
+			return nil
+		}, WithQueue(qCfg), WithSenderMiddleware(middleware("first")), WithSenderMiddleware(middleware("second")))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, te.Shutdown(context.Background())) })
+
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+	assert.Equal(t, []string{"first", "second"}, calls)
+	// The context set by the last middleware is used to export the data.
+	assert.Equal(t, "second", tenant)
+}
+
+func TestWithSenderMiddleware_Error(t *testing.T) {
+	wantErr := errors.New("rejected by middleware")
+	rCfg := NewDefaultRetrySettings()
+	rCfg.Enabled = false
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
+		WithRetry(rCfg), WithSenderMiddleware(func(RequestSender) RequestSender {
+			return RequestSenderFunc(func(context.Context, Request) error {
+				return wantErr
+			})
+		}))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, be.Shutdown(context.Background())) })
+
+	assert.ErrorIs(t, be.send(newRequest(context.Background(), fakeRequest{items: 1})), wantErr)
+}
+
+func TestWithSenderMiddleware_ReplacedRequest(t *testing.T) {
+	var sink []int
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
+		WithSenderMiddleware(func(next RequestSender) RequestSender {
+			return RequestSenderFunc(func(ctx context.Context, _ Request) error {
+				return next.Send(ctx, fakeSplittableRequest{fakeRequest: fakeRequest{items: 3}, sink: &sink})
+			})
+		}))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, be.Shutdown(context.Background())) })
+
+	require.NoError(t, be.send(newRequest(context.Background(), fakeRequest{items: 1})))
+	// The request replaced by the middleware is exported.
+	assert.Equal(t, []int{3}, sink)
+}
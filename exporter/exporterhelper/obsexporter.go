@@ -5,6 +5,8 @@ package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporte
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
@@ -17,6 +19,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/internal/obsreportconfig"
 	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
@@ -33,6 +36,8 @@ type ObsReport struct {
 	mutators       []tag.Mutator
 	tracer         trace.Tracer
 	logger         *zap.Logger
+	// errorClassifier is used to attribute the send failures to the error codes.
+	errorClassifier ErrorClassifier
 
 	useOtelForMetrics           bool
 	otelAttrs                   []attribute.KeyValue
@@ -45,6 +50,10 @@ type ObsReport struct {
 	sentLogRecords              metric.Int64Counter
 	failedToSendLogRecords      metric.Int64Counter
 	failedToEnqueueLogRecords   metric.Int64Counter
+	retryAttempts               metric.Int64Histogram
+	retryGiveUps                metric.Int64Counter
+	retryBackoffDelay           metric.Int64Counter
+	sendFailures                metric.Int64Counter
 }
 
 // ObsReportSettings are settings for creating an ObsReport.
@@ -141,6 +150,30 @@ func (or *ObsReport) createOtelMetrics(cfg ObsReportSettings) error {
 		metric.WithUnit("1"))
 	errors = multierr.Append(errors, err)
 
+	or.retryAttempts, err = meter.Int64Histogram(
+		obsmetrics.ExporterPrefix+obsmetrics.RetryAttemptsKey,
+		metric.WithDescription("Number of attempts made to send a request to destination."),
+		metric.WithUnit("1"))
+	errors = multierr.Append(errors, err)
+
+	or.retryGiveUps, err = meter.Int64Counter(
+		obsmetrics.ExporterPrefix+obsmetrics.RetryGiveUpsKey,
+		metric.WithDescription("Number of requests dropped because no more retries were left."),
+		metric.WithUnit("1"))
+	errors = multierr.Append(errors, err)
+
+	or.retryBackoffDelay, err = meter.Int64Counter(
+		obsmetrics.ExporterPrefix+obsmetrics.RetryBackoffDelayKey,
+		metric.WithDescription("Time spent waiting between the attempts to send requests to destination."),
+		metric.WithUnit("ms"))
+	errors = multierr.Append(errors, err)
+
+	or.sendFailures, err = meter.Int64Counter(
+		obsmetrics.ExporterPrefix+obsmetrics.SendFailuresKey,
+		metric.WithDescription("Number of requests that failed to be sent to destination, by error code."),
+		metric.WithUnit("1"))
+	errors = multierr.Append(errors, err)
+
 	return errors
 }
 
@@ -155,6 +188,7 @@ func (or *ObsReport) StartTracesOp(ctx context.Context) context.Context {
 func (or *ObsReport) EndTracesOp(ctx context.Context, numSpans int, err error) {
 	numSent, numFailedToSend := toNumItems(numSpans, err)
 	or.recordMetrics(ctx, component.DataTypeTraces, numSent, numFailedToSend)
+	or.recordSendFailure(ctx, err)
 	endSpan(ctx, err, numSent, numFailedToSend, obsmetrics.SentSpansKey, obsmetrics.FailedToSendSpansKey)
 }
 
@@ -170,6 +204,7 @@ func (or *ObsReport) StartMetricsOp(ctx context.Context) context.Context {
 func (or *ObsReport) EndMetricsOp(ctx context.Context, numMetricPoints int, err error) {
 	numSent, numFailedToSend := toNumItems(numMetricPoints, err)
 	or.recordMetrics(ctx, component.DataTypeMetrics, numSent, numFailedToSend)
+	or.recordSendFailure(ctx, err)
 	endSpan(ctx, err, numSent, numFailedToSend, obsmetrics.SentMetricPointsKey, obsmetrics.FailedToSendMetricPointsKey)
 }
 
@@ -184,6 +219,7 @@ func (or *ObsReport) StartLogsOp(ctx context.Context) context.Context {
 func (or *ObsReport) EndLogsOp(ctx context.Context, numLogRecords int, err error) {
 	numSent, numFailedToSend := toNumItems(numLogRecords, err)
 	or.recordMetrics(ctx, component.DataTypeLogs, numSent, numFailedToSend)
+	or.recordSendFailure(ctx, err)
 	endSpan(ctx, err, numSent, numFailedToSend, obsmetrics.SentLogRecordsKey, obsmetrics.FailedToSendLogRecordsKey)
 }
 
@@ -267,11 +303,33 @@ func endSpan(ctx context.Context, err error, numSent, numFailedToSend int64, sen
 	span.End()
 }
 
+// toNumItems returns the number of sent and failed items. If the error carries the data that failed to be sent,
+// only its items are failed and the rest of the items are sent.
 func toNumItems(numExportedItems int, err error) (int64, int64) {
-	if err != nil {
-		return 0, int64(numExportedItems)
+	if err == nil {
+		return int64(numExportedItems), 0
+	}
+	if numFailed, ok := failedItems(err); ok && numFailed < numExportedItems {
+		return int64(numExportedItems - numFailed), int64(numFailed)
 	}
-	return int64(numExportedItems), 0
+	return 0, int64(numExportedItems)
+}
+
+// failedItems returns the number of items of the data carried by the error, if any.
+func failedItems(err error) (int, bool) {
+	var tracesErr consumererror.Traces
+	if errors.As(err, &tracesErr) {
+		return tracesErr.Data().SpanCount(), true
+	}
+	var metricsErr consumererror.Metrics
+	if errors.As(err, &metricsErr) {
+		return metricsErr.Data().DataPointCount(), true
+	}
+	var logsErr consumererror.Logs
+	if errors.As(err, &logsErr) {
+		return logsErr.Data().LogRecordCount(), true
+	}
+	return 0, false
 }
 
 func (or *ObsReport) recordEnqueueFailure(ctx context.Context, dataType component.DataType, failed int64) {
@@ -313,3 +371,52 @@ func (or *ObsReport) recordEnqueueFailureWithOtel(ctx context.Context, dataType
 
 	enqueueFailedMeasure.Add(ctx, failed, metric.WithAttributes(or.otelAttrs...))
 }
+
+// recordRetries records the number of attempts made to send a single request, the time spent waiting
+// between them and whether the request was dropped because no more retries were left.
+func (or *ObsReport) recordRetries(ctx context.Context, attempts int64, backoffDelay time.Duration, gaveUp bool) {
+	if or.level == configtelemetry.LevelNone {
+		return
+	}
+	var giveUps int64
+	if gaveUp {
+		giveUps = 1
+	}
+	if or.useOtelForMetrics {
+		or.recordRetriesWithOtel(ctx, attempts, backoffDelay.Milliseconds(), giveUps)
+	} else {
+		or.recordRetriesWithOC(ctx, attempts, backoffDelay.Milliseconds(), giveUps)
+	}
+}
+
+func (or *ObsReport) recordRetriesWithOtel(ctx context.Context, attempts int64, backoffDelay int64, giveUps int64) {
+	or.retryAttempts.Record(ctx, attempts, metric.WithAttributes(or.otelAttrs...))
+	or.retryGiveUps.Add(ctx, giveUps, metric.WithAttributes(or.otelAttrs...))
+	or.retryBackoffDelay.Add(ctx, backoffDelay, metric.WithAttributes(or.otelAttrs...))
+}
+
+func (or *ObsReport) recordRetriesWithOC(ctx context.Context, attempts int64, backoffDelay int64, giveUps int64) {
+	_ = stats.RecordWithTags(
+		ctx,
+		or.mutators,
+		obsmetrics.ExporterRetryAttempts.M(attempts),
+		obsmetrics.ExporterRetryGiveUps.M(giveUps),
+		obsmetrics.ExporterRetryBackoffDelay.M(backoffDelay))
+}
+
+// recordSendFailure records a request that failed to be sent, attributed to the coarse code of the error.
+func (or *ObsReport) recordSendFailure(ctx context.Context, err error) {
+	if err == nil || or.level == configtelemetry.LevelNone {
+		return
+	}
+	code := errorCode(err, or.errorClassifier)
+	if or.useOtelForMetrics {
+		or.sendFailures.Add(ctx, 1, metric.WithAttributes(
+			append(or.otelAttrs, attribute.String(obsmetrics.ErrorCodeKey, code))...))
+		return
+	}
+	_ = stats.RecordWithTags(
+		ctx,
+		append(or.mutators, tag.Upsert(obsmetrics.TagKeyErrorCode, code, tag.WithTTL(tag.TTLNoPropagation))),
+		obsmetrics.ExporterSendFailures.M(1))
+}
@@ -14,10 +14,12 @@ import (
 	"go.opentelemetry.io/otel/codes"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/featuregate"
 	"go.opentelemetry.io/collector/internal/obsreportconfig"
 	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 )
 
@@ -173,6 +175,56 @@ func TestExportLogsOp(t *testing.T) {
 	})
 }
 
+func TestExportSendFailures(t *testing.T) {
+	testTelemetry(t, exporterID, func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool) {
+		obsrep, err := newExporter(ObsReportSettings{
+			ExporterID:             exporterID,
+			ExporterCreateSettings: exporter.CreateSettings{ID: exporterID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+		}, useOtel)
+		require.NoError(t, err)
+
+		for _, err := range []error{nil, context.DeadlineExceeded, context.DeadlineExceeded, consumererror.NewPermanent(errFake)} {
+			obsrep.EndTracesOp(obsrep.StartTracesOp(context.Background()), 1, err)
+		}
+		obsrep.EndLogsOp(obsrep.StartLogsOp(context.Background()), 1, errFake)
+
+		require.NoError(t, tt.CheckExporterSendFailures(ErrorCodeTimeout, 2))
+		require.NoError(t, tt.CheckExporterSendFailures(ErrorCodePermanent, 1))
+		require.NoError(t, tt.CheckExporterSendFailures(ErrorCodeUnknown, 1))
+	})
+}
+
+func TestExportPartialFailures(t *testing.T) {
+	testTelemetry(t, exporterID, func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool) {
+		obsrep, err := newExporter(ObsReportSettings{
+			ExporterID:             exporterID,
+			ExporterCreateSettings: exporter.CreateSettings{ID: exporterID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+		}, useOtel)
+		require.NoError(t, err)
+
+		// Only the spans carried by the error failed, the other ones were accepted.
+		ctx := obsrep.StartTracesOp(context.Background())
+		obsrep.EndTracesOp(ctx, 10, consumererror.NewTraces(errFake, testdata.GenerateTraces(3)))
+		// The error carrying more items than the request fails the whole request.
+		ctx = obsrep.StartTracesOp(context.Background())
+		obsrep.EndTracesOp(ctx, 2, consumererror.NewPermanent(consumererror.NewTraces(errFake, testdata.GenerateTraces(3))))
+		require.NoError(t, tt.CheckExporterTraces(7, 5))
+
+		ctx = obsrep.StartMetricsOp(context.Background())
+		obsrep.EndMetricsOp(ctx, 10, consumererror.NewMetrics(errFake, testdata.GenerateMetrics(2)))
+		require.NoError(t, tt.CheckExporterMetrics(6, 4))
+
+		ctx = obsrep.StartLogsOp(context.Background())
+		obsrep.EndLogsOp(ctx, 10, consumererror.NewLogs(errFake, testdata.GenerateLogs(1)))
+		require.NoError(t, tt.CheckExporterLogs(9, 1))
+
+		spans := tt.SpanRecorder.Ended()
+		require.Len(t, spans, 4)
+		require.Contains(t, spans[0].Attributes(), attribute.KeyValue{Key: obsmetrics.SentSpansKey, Value: attribute.Int64Value(7)})
+		require.Contains(t, spans[0].Attributes(), attribute.KeyValue{Key: obsmetrics.FailedToSendSpansKey, Value: attribute.Int64Value(3)})
+	})
+}
+
 type testParams struct {
 	items int
 	err   error
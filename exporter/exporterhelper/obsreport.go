@@ -24,9 +24,13 @@ func init() {
 }
 
 type instruments struct {
-	registry      *metric.Registry
-	queueSize     *metric.Int64DerivedGauge
-	queueCapacity *metric.Int64DerivedGauge
+	registry            *metric.Registry
+	queueSize           *metric.Int64DerivedGauge
+	queueCapacity       *metric.Int64DerivedGauge
+	queueOldestItemAge  *metric.Int64DerivedGauge
+	queueEnqueued       *metric.Int64DerivedCumulative
+	queueDequeued       *metric.Int64DerivedCumulative
+	queueReclaimedBytes *metric.Int64DerivedCumulative
 }
 
 func newInstruments(registry *metric.Registry) *instruments {
@@ -35,14 +39,38 @@ func newInstruments(registry *metric.Registry) *instruments {
 	}
 	insts.queueSize, _ = registry.AddInt64DerivedGauge(
 		obsmetrics.ExporterKey+"/queue_size",
-		metric.WithDescription("Current size of the retry queue (in batches)"),
+		metric.WithDescription("Current size of the retry queue (in batches, or in items with queue_size_unit: items)"),
 		metric.WithLabelKeys(obsmetrics.ExporterKey),
 		metric.WithUnit(metricdata.UnitDimensionless))
 
 	insts.queueCapacity, _ = registry.AddInt64DerivedGauge(
 		obsmetrics.ExporterKey+"/queue_capacity",
-		metric.WithDescription("Fixed capacity of the retry queue (in batches)"),
+		metric.WithDescription("Fixed capacity of the retry queue (in batches, or in items with queue_size_unit: items)"),
 		metric.WithLabelKeys(obsmetrics.ExporterKey),
 		metric.WithUnit(metricdata.UnitDimensionless))
+
+	insts.queueOldestItemAge, _ = registry.AddInt64DerivedGauge(
+		obsmetrics.ExporterKey+"/queue_oldest_item_age",
+		metric.WithDescription("Age of the oldest request in the retry queue"),
+		metric.WithLabelKeys(obsmetrics.ExporterKey),
+		metric.WithUnit(metricdata.UnitMilliseconds))
+
+	insts.queueEnqueued, _ = registry.AddInt64DerivedCumulative(
+		obsmetrics.ExporterKey+"/queue_enqueued_requests",
+		metric.WithDescription("Number of requests added to the retry queue"),
+		metric.WithLabelKeys(obsmetrics.ExporterKey),
+		metric.WithUnit(metricdata.UnitDimensionless))
+
+	insts.queueDequeued, _ = registry.AddInt64DerivedCumulative(
+		obsmetrics.ExporterKey+"/queue_dequeued_requests",
+		metric.WithDescription("Number of requests taken from the retry queue"),
+		metric.WithLabelKeys(obsmetrics.ExporterKey),
+		metric.WithUnit(metricdata.UnitDimensionless))
+
+	insts.queueReclaimedBytes, _ = registry.AddInt64DerivedCumulative(
+		obsmetrics.ExporterKey+"/queue_reclaimed_bytes",
+		metric.WithDescription("Number of bytes reclaimed by compacting the persistent queue storage"),
+		metric.WithLabelKeys(obsmetrics.ExporterKey),
+		metric.WithUnit(metricdata.UnitBytes))
 	return insts
 }
@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// Queue defines a producer-consumer exchange for the requests waiting to be sent, that can be plugged into
+// the sending queue of an exporter with the WithQueueFactory option, e.g. a queue backed by an external storage.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type Queue interface {
+	// Start starts the queue with the consumer function, which is called for every request taken from the queue.
+	// The processing of the request is finished once the consumer function returns, so the queue can remove it.
+	Start(ctx context.Context, host component.Host, consume func(req Request)) error
+	// Produce is used by the producer to submit new request to the queue. Returns false if the request wasn't added
+	// to the queue, e.g. due to queue overflow.
+	Produce(req Request) bool
+	// Size returns the current size of the queue.
+	Size() int
+	// Capacity returns the capacity of the queue.
+	Capacity() int
+	// Shutdown stops accepting requests, and stops all consumers. It blocks until all consumers have stopped.
+	Shutdown(ctx context.Context) error
+}
+
+// QueueCreateSettings are the settings for creating a Queue with a QueueFactory.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type QueueCreateSettings struct {
+	exporter.CreateSettings
+	// DataType is the type of the data exported by the exporter.
+	DataType component.DataType
+	// Config is the sending queue configuration of the exporter.
+	Config QueueSettings
+	// Marshaler and Unmarshaler serialize the requests, e.g. to store them outside of the collector.
+	// They are nil if the exporter does not support serializing the requests.
+	Marshaler   RequestMarshaler
+	Unmarshaler RequestUnmarshaler
+}
+
+// QueueFactory creates the Queue used as the sending queue of an exporter.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type QueueFactory func(set QueueCreateSettings) (Queue, error)
+
+// WithQueueFactory replaces the in-memory and the persistent queues used as the sending queue of an exporter
+// with the queue created by the given factory. The sending queue must be enabled with WithQueue or
+// WithRequestQueue, its configuration is passed to the factory.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func WithQueueFactory(factory QueueFactory) Option {
+	return func(o *baseExporter) {
+		o.queueFactory = factory
+		if qs, ok := o.queueSender.(*queueSender); ok {
+			o.useQueueFactory(qs)
+		}
+	}
+}
+
+// customQueue adapts the Queue created by a QueueFactory to the queue used by the queueSender.
+// The requests are not removed from the custom queues by the queueSender, so they are not requeued.
+type customQueue struct {
+	queue Queue
+}
+
+func newCustomQueue(factory QueueFactory, set QueueCreateSettings, marshaler internal.RequestMarshaler,
+	unmarshaler internal.RequestUnmarshaler) (internal.Queue, error) {
+	if marshaler != nil && unmarshaler != nil {
+		set.Marshaler = func(req Request) ([]byte, error) {
+			return marshaler(toInternalRequest(req))
+		}
+		set.Unmarshaler = func(data []byte) (Request, error) {
+			return unmarshaler(data)
+		}
+	}
+	queue, err := factory(set)
+	if err != nil {
+		return nil, err
+	}
+	return &customQueue{queue: queue}, nil
+}
+
+func (q *customQueue) Start(ctx context.Context, host component.Host, set internal.QueueSettings) error {
+	return q.queue.Start(ctx, host, func(req Request) {
+		set.Callback(toInternalRequest(req))
+	})
+}
+
+func (q *customQueue) Produce(item internal.Request) bool {
+	return q.queue.Produce(item)
+}
+
+func (q *customQueue) Size() int {
+	return q.queue.Size()
+}
+
+func (q *customQueue) Shutdown(ctx context.Context) error {
+	return q.queue.Shutdown(ctx)
+}
+
+func (q *customQueue) Capacity() int {
+	return q.queue.Capacity()
+}
+
+func (q *customQueue) IsPersistent() bool {
+	return false
+}
+
+// toInternalRequest returns the request produced to the custom queue, or wraps the request restored by it.
+func toInternalRequest(req Request) internal.Request {
+	if ir, ok := req.(internal.Request); ok {
+		return ir
+	}
+	return newRequest(context.Background(), req)
+}
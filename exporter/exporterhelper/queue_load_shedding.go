@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// errLoadShed is returned for the low priority requests dropped because the queue utilization is above
+// the load shedding threshold. It is reported as an enqueue failure, as the request was not queued.
+var errLoadShed = fmt.Errorf("%w: low priority data is shed", errSendingQueueIsFull)
+
+// LoadSheddingSettings defines configuration for dropping the low priority batches once the queue is nearly full,
+// so the queue keeps room for the other batches instead of dropping all of them on overflow.
+type LoadSheddingSettings struct {
+	// Enabled indicates whether the load shedding is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Threshold is the queue utilization, between 0 and 1, above which the low priority batches are dropped.
+	// The probability to drop a low priority batch grows linearly from 0 at the threshold to 1 when the queue is full.
+	Threshold float64 `mapstructure:"threshold"`
+}
+
+// Validate checks if the LoadSheddingSettings configuration is valid
+func (cfg *LoadSheddingSettings) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Threshold <= 0 || cfg.Threshold >= 1 {
+		return errors.New("load shedding threshold must be between 0 and 1")
+	}
+	return nil
+}
+
+// WithTracesLowPriority sets the function telling if the traces are low priority, so they can be dropped by
+// the load shedding of the sending queue. If no function is set, all the data can be dropped.
+// This option can only be used with NewTracesExporter.
+func WithTracesLowPriority(isLowPriority func(ctx context.Context, td ptrace.Traces) bool) Option {
+	return withLowPriority(func(req internal.Request) bool {
+		tr, ok := req.(*tracesRequest)
+		if !ok {
+			return true
+		}
+		return isLowPriority(tr.Context(), tr.td)
+	})
+}
+
+// WithMetricsLowPriority sets the function telling if the metrics are low priority, so they can be dropped by
+// the load shedding of the sending queue. If no function is set, all the data can be dropped.
+// This option can only be used with NewMetricsExporter.
+func WithMetricsLowPriority(isLowPriority func(ctx context.Context, md pmetric.Metrics) bool) Option {
+	return withLowPriority(func(req internal.Request) bool {
+		mr, ok := req.(*metricsRequest)
+		if !ok {
+			return true
+		}
+		return isLowPriority(mr.Context(), mr.md)
+	})
+}
+
+// WithLogsLowPriority sets the function telling if the logs are low priority, so they can be dropped by
+// the load shedding of the sending queue. If no function is set, all the data can be dropped.
+// This option can only be used with NewLogsExporter.
+func WithLogsLowPriority(isLowPriority func(ctx context.Context, ld plog.Logs) bool) Option {
+	return withLowPriority(func(req internal.Request) bool {
+		lr, ok := req.(*logsRequest)
+		if !ok {
+			return true
+		}
+		return isLowPriority(lr.Context(), lr.ld)
+	})
+}
+
+// WithRequestLowPriority sets the function telling if the request is low priority, so it can be dropped by
+// the load shedding of the sending queue. If no function is set, all the requests can be dropped.
+// This option can only be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func WithRequestLowPriority(isLowPriority func(ctx context.Context, req Request) bool) Option {
+	return withLowPriority(func(req internal.Request) bool {
+		r, ok := req.(*request)
+		if !ok {
+			return true
+		}
+		return isLowPriority(r.Context(), r.Request)
+	})
+}
+
+func withLowPriority(isLowPriority func(req internal.Request) bool) Option {
+	return func(o *baseExporter) {
+		o.isLowPriority = isLowPriority
+		if qs, ok := o.queueSender.(*queueSender); ok {
+			qs.isLowPriority = isLowPriority
+		}
+	}
+}
+
+// shed returns true if the request must be dropped by the load shedding.
+func (qs *queueSender) shed(req internal.Request) bool {
+	if !qs.loadShedding.Enabled {
+		return false
+	}
+	capacity := qs.queue.Capacity()
+	if capacity <= 0 {
+		return false
+	}
+	utilization := float64(qs.queue.Size()) / float64(capacity)
+	if utilization < qs.loadShedding.Threshold {
+		return false
+	}
+	if qs.isLowPriority != nil && !qs.isLowPriority(req) {
+		return false
+	}
+	probability := (utilization - qs.loadShedding.Threshold) / (1 - qs.loadShedding.Threshold)
+	return qs.random() < probability
+}
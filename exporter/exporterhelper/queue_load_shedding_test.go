@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestLoadSheddingSettings_Validate(t *testing.T) {
+	cfg := LoadSheddingSettings{Enabled: true, Threshold: 0.8}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Threshold = 0
+	assert.EqualError(t, cfg.Validate(), "load shedding threshold must be between 0 and 1")
+
+	cfg.Threshold = 1
+	assert.EqualError(t, cfg.Validate(), "load shedding threshold must be between 0 and 1")
+
+	qCfg := NewDefaultQueueSettings()
+	qCfg.LoadShedding = cfg
+	assert.EqualError(t, qCfg.Validate(), "load shedding threshold must be between 0 and 1")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	cfg.Enabled = false
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestQueuedRetry_LoadShedding(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.QueueSize = 10
+	// No consumers, so the requests stay in the queue.
+	qCfg.NumConsumers = 0
+	qCfg.LoadShedding = LoadSheddingSettings{Enabled: true, Threshold: 0.4}
+	lowPriority := map[*mockRequest]bool{}
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg),
+		withLowPriority(func(req internal.Request) bool {
+			return lowPriority[req.(*mockRequest)]
+		}))
+	require.NoError(t, err)
+	qs := be.queueSender.(*queueSender)
+	qs.random = func() float64 { return 0.5 }
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	send := func(low bool) error {
+		req := newMockRequest(context.Background(), 1, nil)
+		lowPriority[req] = low
+		return be.send(req)
+	}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, send(false))
+	}
+	// The low priority requests are dropped with the probability 1/6 at 50% of utilization.
+	require.NoError(t, send(true))
+	require.NoError(t, send(false))
+	require.NoError(t, send(false))
+	// At 80% of utilization the probability is 2/3.
+	assert.ErrorIs(t, send(true), errLoadShed)
+	assert.ErrorIs(t, send(true), errSendingQueueIsFull)
+	require.NoError(t, send(false))
+	require.NoError(t, send(false))
+	assert.Equal(t, 10, qs.queue.Size())
+}
+
+func TestTracesExporter_WithLowPriority(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.LoadShedding = LoadSheddingSettings{Enabled: true, Threshold: 0.5}
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeTracesExporterConfig,
+		newTraceDataPusher(nil), WithQueue(qCfg), WithTracesLowPriority(func(_ context.Context, td ptrace.Traces) bool {
+			return td.SpanCount() > 1
+		}))
+	require.NoError(t, err)
+	qs := te.(*traceExporter).queueSender.(*queueSender)
+	require.NotNil(t, qs.isLowPriority)
+	assert.True(t, qs.isLowPriority(newTracesRequest(context.Background(), testdata.GenerateTraces(2), nil)))
+	assert.False(t, qs.isLowPriority(newTracesRequest(context.Background(), testdata.GenerateTraces(1), nil)))
+}
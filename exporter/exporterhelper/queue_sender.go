@@ -4,29 +4,74 @@
 package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
 
 import (
+	"container/list"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	otelmetric "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/extension/experimental/memorybudget"
 	"go.opentelemetry.io/collector/internal/obsreportconfig"
 	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
 )
 
 const defaultQueueSize = 1000
 
+const (
+	// QueueSizeUnitRequests measures the queue size in number of requests (batches).
+	QueueSizeUnitRequests = "requests"
+	// QueueSizeUnitItems measures the queue size in number of spans, metric data points or log records.
+	QueueSizeUnitItems = "items"
+)
+
+const (
+	// OverflowPolicyReject rejects new batches when the queue is full.
+	OverflowPolicyReject = "reject"
+	// OverflowPolicyDropOldest drops the oldest batches from the queue to admit the new ones.
+	OverflowPolicyDropOldest = "drop_oldest"
+	// OverflowPolicyBlock makes the caller wait for the free space in the queue up to the BlockTimeout.
+	OverflowPolicyBlock = "block"
+)
+
+const (
+	// QueueCompressionZstd compresses the batches stored in the persistent queue with zstd.
+	QueueCompressionZstd = "zstd"
+	// QueueCompressionSnappy compresses the batches stored in the persistent queue with snappy.
+	QueueCompressionSnappy = "snappy"
+)
+
+// queuedRequestSpanSuffix is appended to the exporter span name prefix to name the spans tracing the queued requests.
+const queuedRequestSpanSuffix = "/queued_request"
+
+// minRetryAfter is the minimum delay after which the data refused because the queue is full can be sent again.
+const minRetryAfter = time.Second
+
 var (
 	errSendingQueueIsFull = errors.New("sending_queue is full")
+	errRequestTooLarge    = errors.New("request is larger than the sending_queue capacity")
+	errDrainExpired       = errors.New("sending_queue shutdown timeout expired")
+	errMaxLatencyExceeded = errors.New("sending_queue max latency exceeded")
+	errQueueNotEnabled    = errors.New("sending_queue is not enabled")
+	errQueueNotResizable  = errors.New("sending_queue cannot be resized, only the in-memory queue can be resized")
 	scopeName             = "go.opentelemetry.io/collector/exporterhelper"
 )
 
@@ -37,10 +82,69 @@ type QueueSettings struct {
 	// NumConsumers is the number of consumers from the queue.
 	NumConsumers int `mapstructure:"num_consumers"`
 	// QueueSize is the maximum number of batches allowed in queue at a given time.
+	// If QueueSizeUnit is set to QueueSizeUnitItems, it's the maximum number of items instead.
 	QueueSize int `mapstructure:"queue_size"`
+	// QueueSizeUnit is the unit QueueSize is measured in. It can be either QueueSizeUnitRequests
+	// or QueueSizeUnitItems. Empty value means QueueSizeUnitRequests.
+	QueueSizeUnit string `mapstructure:"queue_size_unit"`
 	// StorageID if not empty, enables the persistent storage and uses the component specified
 	// as a storage extension for the persistent queue
 	StorageID *component.ID `mapstructure:"storage"`
+	// MaxBytes is the maximum total size in bytes of the serialized batches stored in the persistent queue,
+	// including the batches being sent, which are stored until they are processed. Zero means no limit.
+	// It can only be used when the persistent queue is enabled.
+	MaxBytes int64 `mapstructure:"max_bytes"`
+	// ShutdownTimeout is the maximum time to spend sending the requests left in the in-memory queue on shutdown.
+	// The requests not sent before the timeout are dropped. Zero means no limit.
+	// The persistent queue keeps the requests in the storage instead, so this setting does not apply to it.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	// OverflowPolicy defines what happens to a new batch when the queue is full. It can be OverflowPolicyReject,
+	// OverflowPolicyDropOldest or OverflowPolicyBlock. Empty value means OverflowPolicyReject.
+	// Only OverflowPolicyReject is supported by the persistent queue.
+	OverflowPolicy string `mapstructure:"overflow_policy"`
+	// BlockTimeout is the maximum time the caller waits for the free space with OverflowPolicyBlock.
+	BlockTimeout time.Duration `mapstructure:"block_timeout"`
+	// Compaction defines when the storage of the persistent queue is compacted to reclaim the space
+	// left by the sent batches. It can only be used when the persistent queue is enabled.
+	Compaction CompactionSettings `mapstructure:"compaction"`
+	// Encryption defines the encryption of the batches stored in the persistent queue.
+	// It can only be used when the persistent queue is enabled.
+	Encryption EncryptionSettings `mapstructure:"encryption"`
+	// StorageCompression is the codec used to compress the batches stored in the persistent queue. It can be
+	// QueueCompressionZstd or QueueCompressionSnappy. Empty value disables the compression.
+	// It can only be used when the persistent queue is enabled.
+	StorageCompression string `mapstructure:"storage_compression"`
+	// ShardCapacity is the maximum size of the batches of a single shard allowed in the queue, measured
+	// in the same unit as QueueSize, so one shard filling the queue cannot prevent the others from being queued.
+	// The shard of a batch is provided by the key set with one of the With*ShardKey options, e.g. a tenant.
+	// Zero disables sharding. It cannot be used with the persistent queue.
+	ShardCapacity int `mapstructure:"shard_capacity"`
+	// MemoryBudgetID if not empty, admits the batches to the queue only if their estimated size in bytes fits
+	// in the memory budget shared by all the queues using the component specified as a memory budget extension.
+	// It cannot be used with the persistent queue.
+	MemoryBudgetID *component.ID `mapstructure:"memory_budget"`
+	// MaxLatency is the maximum time between a batch is added to the queue and it is either sent or failed,
+	// including the time spent in the queue and all the attempts to send it. The batches not sent within
+	// the MaxLatency are dropped. Zero means no limit. The persistent queue stores the time the batches are
+	// added to it, so the batches restored after a restart are limited from that time too.
+	MaxLatency time.Duration `mapstructure:"max_latency"`
+	// LoadShedding defines the dropping of the low priority batches once the queue is nearly full.
+	// The low priority batches are identified by one of the With*LowPriority options.
+	LoadShedding LoadSheddingSettings `mapstructure:"load_shedding"`
+	// MaxPendingAcks is the maximum number of batches waiting for their asynchronous acknowledgment, see NewPendingAck.
+	// The queue consumers send the next batches while the acknowledgments are pending, until the maximum is reached.
+	// Zero means the consumers wait for the acknowledgment of every batch they send.
+	MaxPendingAcks int `mapstructure:"max_pending_acks"`
+}
+
+// CompactionSettings defines configuration for compacting the storage of the persistent queue.
+// The compaction is only done if the storage extension supports it.
+type CompactionSettings struct {
+	// Interval is the time between the periodic compactions. Zero disables the periodic compaction.
+	Interval time.Duration `mapstructure:"interval"`
+	// Threshold is the number of bytes deleted from the queue since the last compaction after which
+	// the storage is compacted. Zero disables the threshold-based compaction.
+	Threshold int64 `mapstructure:"threshold"`
 }
 
 // NewDefaultQueueSettings returns the default settings for QueueSettings.
@@ -51,7 +155,30 @@ func NewDefaultQueueSettings() QueueSettings {
 		// By default, batches are 8192 spans, for a total of up to 8 million spans in the queue
 		// This can be estimated at 1-4 GB worth of maximum memory usage
 		// This default is probably still too high, and may be adjusted further down in a future release
-		QueueSize: defaultQueueSize,
+		QueueSize:      defaultQueueSize,
+		MaxPendingAcks: defaultQueueSize,
+	}
+}
+
+// EncryptionSettings defines configuration for encrypting the batches stored in the persistent queue with AES-GCM.
+type EncryptionSettings struct {
+	// Key is the base64 encoded encryption key. It must be 16, 24 or 32 bytes long to select AES-128, AES-192
+	// or AES-256. Empty value disables the encryption. The key is usually sourced from the environment or
+	// a secret provider, e.g. ${env:QUEUE_ENCRYPTION_KEY}.
+	Key configopaque.String `mapstructure:"key"`
+}
+
+// key decodes the encryption key.
+func (eCfg *EncryptionSettings) key() ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(string(eCfg.Key))
+	if err != nil {
+		return nil, fmt.Errorf("encryption key must be base64 encoded: %w", err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("encryption key must be 16, 24 or 32 bytes long, got %d bytes", len(key))
 	}
 }
 
@@ -69,7 +196,111 @@ func (qCfg *QueueSettings) Validate() error {
 		return errors.New("number of queue consumers must be positive")
 	}
 
-	return nil
+	switch qCfg.QueueSizeUnit {
+	case "", QueueSizeUnitRequests, QueueSizeUnitItems:
+	default:
+		return fmt.Errorf("unsupported queue size unit %q", qCfg.QueueSizeUnit)
+	}
+
+	if qCfg.MaxBytes < 0 {
+		return errors.New("max bytes must not be negative")
+	}
+
+	if qCfg.MaxBytes > 0 && qCfg.StorageID == nil {
+		return errors.New("max bytes can only be set when the persistent queue is enabled")
+	}
+
+	if qCfg.ShutdownTimeout < 0 {
+		return errors.New("shutdown timeout must not be negative")
+	}
+
+	if qCfg.MaxLatency < 0 {
+		return errors.New("max latency must not be negative")
+	}
+
+	if qCfg.MaxPendingAcks < 0 {
+		return errors.New("max pending acks must not be negative")
+	}
+
+	switch qCfg.OverflowPolicy {
+	case "", OverflowPolicyReject:
+	case OverflowPolicyDropOldest, OverflowPolicyBlock:
+		if qCfg.StorageID != nil {
+			return fmt.Errorf("overflow policy %q is not supported by the persistent queue", qCfg.OverflowPolicy)
+		}
+	default:
+		return fmt.Errorf("unsupported overflow policy %q", qCfg.OverflowPolicy)
+	}
+
+	if qCfg.OverflowPolicy == OverflowPolicyBlock && qCfg.BlockTimeout <= 0 {
+		return errors.New("block timeout must be positive when the overflow policy is block")
+	}
+
+	if qCfg.Compaction.Interval < 0 || qCfg.Compaction.Threshold < 0 {
+		return errors.New("compaction interval and threshold must not be negative")
+	}
+
+	if (qCfg.Compaction.Interval > 0 || qCfg.Compaction.Threshold > 0) && qCfg.StorageID == nil {
+		return errors.New("compaction can only be set when the persistent queue is enabled")
+	}
+
+	if qCfg.ShardCapacity < 0 {
+		return errors.New("shard capacity must not be negative")
+	}
+
+	if qCfg.ShardCapacity > 0 && qCfg.StorageID != nil {
+		return errors.New("shard capacity is not supported by the persistent queue")
+	}
+
+	if qCfg.MemoryBudgetID != nil && qCfg.StorageID != nil {
+		return errors.New("memory budget is not supported by the persistent queue")
+	}
+
+	switch qCfg.StorageCompression {
+	case "":
+	case QueueCompressionZstd, QueueCompressionSnappy:
+		if qCfg.StorageID == nil {
+			return errors.New("storage compression can only be set when the persistent queue is enabled")
+		}
+	default:
+		return fmt.Errorf("unsupported storage compression %q", qCfg.StorageCompression)
+	}
+
+	if qCfg.Encryption.Key != "" {
+		if qCfg.StorageID == nil {
+			return errors.New("encryption can only be set when the persistent queue is enabled")
+		}
+		if _, err := qCfg.Encryption.key(); err != nil {
+			return err
+		}
+	}
+
+	return qCfg.LoadShedding.Validate()
+}
+
+// interruptedError marks an error for a request which sending was interrupted by the shutdown,
+// so it must be handed back to the persistent queue by the queue consumer.
+type interruptedError struct {
+	err error
+	req internal.Request
+}
+
+func (e interruptedError) Error() string {
+	return e.err.Error()
+}
+
+func (e interruptedError) Unwrap() error {
+	return e.err
+}
+
+// agingQueue is implemented by the queues that keep the enqueue times of their requests, e.g. in the persistent storage.
+type agingQueue interface {
+	OldestEnqueuedAt() time.Time
+}
+
+// reclaimingQueue is implemented by the queues that compact their storage.
+type reclaimingQueue interface {
+	ReclaimedBytes() int64
 }
 
 type queueSender struct {
@@ -78,34 +309,145 @@ type queueSender struct {
 	signal           component.DataType
 	queue            internal.Queue
 	traceAttribute   attribute.KeyValue
+	tracer           trace.Tracer
+	spanName         string
 	logger           *zap.Logger
 	meter            otelmetric.Meter
 	requeuingEnabled bool
+	throttle         *throttleGate
+	sizer            internal.Sizer
+	sizeUnit         string
+	shardCapacity    int
+	shardKey         internal.ShardKeyFunc
+	memoryBudgetID   *component.ID
+	stopCh           chan struct{}
+	shutdownTimeout  time.Duration
+	maxLatency       time.Duration
+	loadShedding     LoadSheddingSettings
+	// isLowPriority tells if a request can be dropped by the load shedding, all the requests can if it is nil.
+	isLowPriority func(req internal.Request) bool
+	// random returns a random number in [0, 1) used to decide which low priority requests are dropped.
+	random func() float64
+	// onDropped is called for the requests permanently dropped by the queue sender.
+	onDropped func(req internal.Request, err error)
+	// resizable is the queue changed by ResizeQueue, nil if the queue cannot be resized.
+	resizable internal.ResizableQueue
+	// initErr is the error that occurred while setting up the queue, it is reported on Start.
+	initErr error
+
+	// ackSlots limits the number of requests waiting for their acknowledgment after the consumer is released,
+	// nil if the consumers wait for the acknowledgments.
+	ackSlots chan struct{}
+	// detachMu protects the start of the detached sends against the shutdown, which waits for them with detached.
+	detachMu sync.RWMutex
+	detached sync.WaitGroup
+
+	// shuttingDown is set when the queue starts draining, drainExpired when the shutdown timeout passes.
+	shuttingDown atomic.Bool
+	drainExpired atomic.Bool
+	// flushedItems and droppedItems count the items left in the queue on shutdown.
+	flushedItems atomic.Int64
+	droppedItems atomic.Int64
 
-	metricCapacity otelmetric.Int64ObservableGauge
-	metricSize     otelmetric.Int64ObservableGauge
+	// now is the clock of the enqueue times, overridable by tests.
+	now func() time.Time
+	// pending tracks the requests waiting in the in-memory queue to report the age of the oldest one.
+	pending *pendingRequests
+	// enqueued and dequeued count the requests added to and taken from the queue.
+	enqueued atomic.Int64
+	dequeued atomic.Int64
+
+	metricCapacity      otelmetric.Int64ObservableGauge
+	metricSize          otelmetric.Int64ObservableGauge
+	metricOldestItemAge otelmetric.Int64ObservableGauge
+	metricEnqueued      otelmetric.Int64ObservableCounter
+	metricDequeued      otelmetric.Int64ObservableCounter
+	metricReclaimed     otelmetric.Int64ObservableCounter
+	metricLatency       otelmetric.Int64Histogram
+	mutators            []tag.Mutator
 }
 
 func newQueueSender(config QueueSettings, set exporter.CreateSettings, signal component.DataType,
-	marshaler internal.RequestMarshaler, unmarshaler internal.RequestUnmarshaler) *queueSender {
+	marshaler internal.RequestMarshaler, unmarshaler internal.RequestUnmarshaler, throttle *throttleGate) *queueSender {
+	var sizer internal.Sizer = internal.RequestsSizer{}
+	sizeUnit := "batches"
+	if config.QueueSizeUnit == QueueSizeUnitItems {
+		sizer = internal.ItemsSizer{}
+		sizeUnit = "items"
+	}
 	var queue internal.Queue
+	var initErr error
 	if config.StorageID == nil {
-		queue = internal.NewBoundedMemoryQueue(config.QueueSize, config.NumConsumers)
+		overflow := internal.OverflowSettings{BlockTimeout: config.BlockTimeout}
+		switch config.OverflowPolicy {
+		case OverflowPolicyDropOldest:
+			overflow.Policy = internal.OverflowDropOldest
+		case OverflowPolicyBlock:
+			overflow.Policy = internal.OverflowBlock
+		}
+		queue = internal.NewBoundedMemoryQueue(config.QueueSize, config.NumConsumers, sizer, overflow)
 	} else {
-		queue = internal.NewPersistentQueue(config.QueueSize, config.NumConsumers, *config.StorageID, marshaler, unmarshaler, set)
+		// The batches are compressed before they are encrypted, the encrypted data cannot be compressed.
+		switch config.StorageCompression {
+		case QueueCompressionZstd:
+			marshaler, unmarshaler = internal.NewCompressedMarshalers(internal.CodecZstd, marshaler, unmarshaler)
+		case QueueCompressionSnappy:
+			marshaler, unmarshaler = internal.NewCompressedMarshalers(internal.CodecSnappy, marshaler, unmarshaler)
+		}
+		if config.Encryption.Key != "" {
+			marshaler, unmarshaler, initErr = newEncryptedMarshalers(config.Encryption, marshaler, unmarshaler)
+		}
+		compaction := internal.CompactionSettings{
+			Interval:  config.Compaction.Interval,
+			Threshold: uint64(config.Compaction.Threshold),
+		}
+		queue = internal.NewPersistentQueue(config.QueueSize, config.MaxBytes, sizer, compaction, config.NumConsumers,
+			*config.StorageID, marshaler, unmarshaler, set)
+	}
+	resizable, _ := queue.(internal.ResizableQueue)
+	var ackSlots chan struct{}
+	if config.MaxPendingAcks > 0 {
+		ackSlots = make(chan struct{}, config.MaxPendingAcks)
 	}
 	return &queueSender{
 		fullName:       set.ID.String(),
 		signal:         signal,
 		queue:          queue,
 		traceAttribute: attribute.String(obsmetrics.ExporterKey, set.ID.String()),
+		tracer:         set.TelemetrySettings.TracerProvider.Tracer(set.ID.String()),
+		spanName:       obsmetrics.ExporterPrefix + set.ID.String() + queuedRequestSpanSuffix,
 		logger:         set.TelemetrySettings.Logger,
 		meter:          set.TelemetrySettings.MeterProvider.Meter(scopeName),
 		// TODO: this can be further exposed as a config param rather than relying on a type of queue
 		requeuingEnabled: queue.IsPersistent(),
+		throttle:         throttle,
+		sizer:            sizer,
+		sizeUnit:         sizeUnit,
+		shardCapacity:    config.ShardCapacity,
+		memoryBudgetID:   config.MemoryBudgetID,
+		stopCh:           make(chan struct{}),
+		shutdownTimeout:  config.ShutdownTimeout,
+		maxLatency:       config.MaxLatency,
+		loadShedding:     config.LoadShedding,
+		ackSlots:         ackSlots,
+		random:           rand.Float64,
+		resizable:        resizable,
+		initErr:          initErr,
+		now:              time.Now,
+		pending:          newPendingRequests(),
+		mutators:         []tag.Mutator{tag.Upsert(obsmetrics.TagKeyExporter, set.ID.String(), tag.WithTTL(tag.TTLNoPropagation))},
 	}
 }
 
+func newEncryptedMarshalers(config EncryptionSettings, marshaler internal.RequestMarshaler,
+	unmarshaler internal.RequestUnmarshaler) (internal.RequestMarshaler, internal.RequestUnmarshaler, error) {
+	key, err := config.key()
+	if err != nil {
+		return nil, nil, err
+	}
+	return internal.NewEncryptedMarshalers(key, marshaler, unmarshaler)
+}
+
 func (qs *queueSender) onTemporaryFailure(logger *zap.Logger, req internal.Request, err error) error {
 	if !qs.requeuingEnabled {
 		logger.Error(
@@ -116,12 +458,26 @@ func (qs *queueSender) onTemporaryFailure(logger *zap.Logger, req internal.Reque
 		return err
 	}
 
+	// The dequeued request is kept in the persistent storage until its processing is finished,
+	// so the queue consumer decides how to hand it back.
+	if qs.queue.IsPersistent() && errors.Is(err, errInterruptedByShutdown) {
+		return interruptedError{err: requeuedError{err: err}, req: req}
+	}
+	return qs.requeue(logger, req, err)
+}
+
+// requeue puts the request back to the end of the queue.
+func (qs *queueSender) requeue(logger *zap.Logger, req internal.Request, err error) error {
+	qs.onEnqueue(req)
 	if qs.queue.Produce(req) {
+		qs.enqueued.Add(1)
 		logger.Error(
 			"Exporting failed. Putting back to the end of the queue.",
 			zap.Error(err),
 		)
+		return requeuedError{err: err}
 	} else {
+		qs.pending.remove(req)
 		logger.Error(
 			"Exporting failed. Queue did not accept requeuing request. Dropping data.",
 			zap.Error(err),
@@ -133,11 +489,82 @@ func (qs *queueSender) onTemporaryFailure(logger *zap.Logger, req internal.Reque
 
 // Start is invoked during service startup.
 func (qs *queueSender) Start(ctx context.Context, host component.Host) error {
+	if qs.initErr != nil {
+		return qs.initErr
+	}
+	if qs.shardCapacity > 0 {
+		if qs.shardKey != nil {
+			qs.queue = internal.NewShardedQueue(qs.queue, qs.shardCapacity, qs.sizer, qs.shardKey)
+		} else {
+			qs.logger.Warn("The sending_queue shard_capacity is ignored, the exporter does not provide the shard keys.")
+		}
+	}
+	if qs.memoryBudgetID != nil {
+		budget, err := getMemoryBudget(host, *qs.memoryBudgetID)
+		if err != nil {
+			return err
+		}
+		qs.queue = internal.NewBudgetedQueue(qs.queue, budget, func(item internal.Request) int64 {
+			return int64(bytesCount(item))
+		})
+	}
 	err := qs.queue.Start(ctx, host, internal.QueueSettings{
 		DataType: qs.signal,
 		Callback: func(item internal.Request) {
-			_ = qs.nextSender.send(item)
-			item.OnProcessingFinished()
+			// The senders may replace the context of the request, so the span is taken before sending.
+			span := trace.SpanFromContext(item.Context())
+			latency := qs.onDequeued(item)
+			cancel := context.CancelFunc(func() {})
+			if qs.maxLatency > 0 {
+				if latency >= qs.maxLatency {
+					qs.logger.Error(
+						"Dropping data because it was not sent within the sending_queue max_latency.",
+						zap.Int("dropped_items", item.Count()),
+					)
+					qs.endRequestSpan(span, errMaxLatencyExceeded)
+					qs.dropped(item, errMaxLatencyExceeded)
+					item.OnProcessingFinished()
+					return
+				}
+				// The deadline interrupts the retries and the attempt in progress once the max latency passes.
+				var ctx context.Context
+				ctx, cancel = context.WithTimeout(item.Context(), qs.maxLatency-latency)
+				item.SetContext(ctx)
+			}
+			if qs.drainExpired.Load() {
+				cancel()
+				qs.droppedItems.Add(int64(item.Count()))
+				qs.endRequestSpan(span, errDrainExpired)
+				qs.dropped(item, errDrainExpired)
+				item.OnProcessingFinished()
+				return
+			}
+			// Do not send new requests while the destination is throttling the exporter.
+			qs.throttle.wait(qs.stopCh)
+			release, ok := qs.detach(item)
+			if !ok {
+				defer cancel()
+				qs.finish(item, span, qs.nextSender.send(item))
+				return
+			}
+			go func() {
+				defer qs.detached.Done()
+				defer cancel()
+				err := qs.nextSender.send(item)
+				release.onSent()
+				qs.finish(item, span, err)
+			}()
+			<-release.released
+		},
+		DropCallback: func(item internal.Request) {
+			// The dropped request is not sent, but it is no longer waiting in the queue.
+			qs.pending.remove(item)
+			qs.logger.Error(
+				"Dropping the oldest data because sending_queue is full. Try increasing queue_size.",
+				zap.Int("dropped_items", item.Count()),
+			)
+			qs.endRequestSpan(trace.SpanFromContext(item.Context()), errSendingQueueIsFull)
+			qs.dropped(item, errSendingQueueIsFull)
 		},
 	})
 	if err != nil {
@@ -157,7 +584,7 @@ func (qs *queueSender) recordWithOtel() error {
 
 	qs.metricSize, err = qs.meter.Int64ObservableGauge(
 		obsmetrics.ExporterKey+"/queue_size",
-		otelmetric.WithDescription("Current size of the retry queue (in batches)"),
+		otelmetric.WithDescription("Current size of the retry queue (in "+qs.sizeUnit+")"),
 		otelmetric.WithUnit("1"),
 		otelmetric.WithInt64Callback(func(_ context.Context, o otelmetric.Int64Observer) error {
 			o.Observe(int64(qs.queue.Size()), attrs)
@@ -168,7 +595,7 @@ func (qs *queueSender) recordWithOtel() error {
 
 	qs.metricCapacity, err = qs.meter.Int64ObservableGauge(
 		obsmetrics.ExporterKey+"/queue_capacity",
-		otelmetric.WithDescription("Fixed capacity of the retry queue (in batches)"),
+		otelmetric.WithDescription("Fixed capacity of the retry queue (in "+qs.sizeUnit+")"),
 		otelmetric.WithUnit("1"),
 		otelmetric.WithInt64Callback(func(_ context.Context, o otelmetric.Int64Observer) error {
 			o.Observe(int64(qs.queue.Capacity()), attrs)
@@ -176,6 +603,54 @@ func (qs *queueSender) recordWithOtel() error {
 		}))
 
 	errs = multierr.Append(errs, err)
+
+	qs.metricOldestItemAge, err = qs.meter.Int64ObservableGauge(
+		obsmetrics.ExporterKey+"/queue_oldest_item_age",
+		otelmetric.WithDescription("Age of the oldest request in the retry queue"),
+		otelmetric.WithUnit("ms"),
+		otelmetric.WithInt64Callback(func(_ context.Context, o otelmetric.Int64Observer) error {
+			o.Observe(qs.oldestAge().Milliseconds(), attrs)
+			return nil
+		}))
+	errs = multierr.Append(errs, err)
+
+	qs.metricEnqueued, err = qs.meter.Int64ObservableCounter(
+		obsmetrics.ExporterKey+"/queue_enqueued_requests",
+		otelmetric.WithDescription("Number of requests added to the retry queue"),
+		otelmetric.WithUnit("1"),
+		otelmetric.WithInt64Callback(func(_ context.Context, o otelmetric.Int64Observer) error {
+			o.Observe(qs.enqueued.Load(), attrs)
+			return nil
+		}))
+	errs = multierr.Append(errs, err)
+
+	qs.metricDequeued, err = qs.meter.Int64ObservableCounter(
+		obsmetrics.ExporterKey+"/queue_dequeued_requests",
+		otelmetric.WithDescription("Number of requests taken from the retry queue"),
+		otelmetric.WithUnit("1"),
+		otelmetric.WithInt64Callback(func(_ context.Context, o otelmetric.Int64Observer) error {
+			o.Observe(qs.dequeued.Load(), attrs)
+			return nil
+		}))
+	errs = multierr.Append(errs, err)
+
+	if rq, ok := qs.queue.(reclaimingQueue); ok {
+		qs.metricReclaimed, err = qs.meter.Int64ObservableCounter(
+			obsmetrics.ExporterKey+"/queue_reclaimed_bytes",
+			otelmetric.WithDescription("Number of bytes reclaimed by compacting the persistent queue storage"),
+			otelmetric.WithUnit("By"),
+			otelmetric.WithInt64Callback(func(_ context.Context, o otelmetric.Int64Observer) error {
+				o.Observe(rq.ReclaimedBytes(), attrs)
+				return nil
+			}))
+		errs = multierr.Append(errs, err)
+	}
+
+	qs.metricLatency, err = qs.meter.Int64Histogram(
+		obsmetrics.ExporterKey+"/queue_latency",
+		otelmetric.WithDescription("Time spent by requests in the retry queue"),
+		otelmetric.WithUnit("ms"))
+	errs = multierr.Append(errs, err)
 	return errs
 }
 
@@ -193,42 +668,375 @@ func (qs *queueSender) recordWithOC() error {
 	if err != nil {
 		return fmt.Errorf("failed to create retry queue capacity metric: %w", err)
 	}
+	err = globalInstruments.queueOldestItemAge.UpsertEntry(func() int64 {
+		return qs.oldestAge().Milliseconds()
+	}, metricdata.NewLabelValue(qs.fullName))
+	if err != nil {
+		return fmt.Errorf("failed to create retry queue oldest item age metric: %w", err)
+	}
+	err = globalInstruments.queueEnqueued.UpsertEntry(qs.enqueued.Load, metricdata.NewLabelValue(qs.fullName))
+	if err != nil {
+		return fmt.Errorf("failed to create retry queue enqueued requests metric: %w", err)
+	}
+	err = globalInstruments.queueDequeued.UpsertEntry(qs.dequeued.Load, metricdata.NewLabelValue(qs.fullName))
+	if err != nil {
+		return fmt.Errorf("failed to create retry queue dequeued requests metric: %w", err)
+	}
+	if rq, ok := qs.queue.(reclaimingQueue); ok {
+		err = globalInstruments.queueReclaimedBytes.UpsertEntry(rq.ReclaimedBytes, metricdata.NewLabelValue(qs.fullName))
+		if err != nil {
+			return fmt.Errorf("failed to create retry queue reclaimed bytes metric: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// getMemoryBudget returns the memory budget of the extension with the given ID.
+func getMemoryBudget(host component.Host, budgetID component.ID) (memorybudget.Budget, error) {
+	ext, found := host.GetExtensions()[budgetID]
+	if !found {
+		return nil, fmt.Errorf("memory budget extension %q not found", budgetID)
+	}
+	budgetExt, ok := ext.(memorybudget.Extension)
+	if !ok {
+		return nil, fmt.Errorf("extension %q is not a memory budget extension", budgetID)
+	}
+	return budgetExt.GetBudget(), nil
+}
+
+// handBack hands the request, which sending was interrupted by the shutdown, back to the persistent queue.
+// The dequeued request is left in the storage without finishing its processing, so it is moved back to the queue
+// and sent again after the restart. If only a part of the request failed, the part is put back to the queue instead.
+func (qs *queueSender) handBack(item internal.Request, ie interruptedError) {
+	if ie.req == item {
+		qs.logger.Info(
+			"Exporting interrupted due to shutdown. Keeping the data in the queue to send it after the restart.",
+			zap.Error(ie.err),
+			zap.Int("kept_items", item.Count()),
+		)
+		return
+	}
+	if err := qs.requeue(qs.logger, ie.req, ie.err); !errors.As(err, &requeuedError{}) {
+		qs.dropped(ie.req, err)
+	}
+	item.OnProcessingFinished()
+}
+
+// finish handles the result of sending the dequeued request: the request is removed from the queue, unless its
+// sending was interrupted by the shutdown.
+func (qs *queueSender) finish(item internal.Request, span trace.Span, err error) {
+	if ie := (interruptedError{}); errors.As(err, &ie) {
+		qs.handBack(item, ie)
+		return
+	}
+	if qs.shuttingDown.Load() {
+		if err != nil {
+			qs.droppedItems.Add(int64(item.Count()))
+		} else {
+			qs.flushedItems.Add(int64(item.Count()))
+		}
+	}
+	qs.endRequestSpan(span, err)
+	if err != nil && !errors.As(err, &requeuedError{}) {
+		qs.dropped(item, err)
+	}
+	item.OnProcessingFinished()
+}
+
+// detach prepares the dequeued request to be sent by another goroutine than the queue consumer, so the consumer can
+// send the next requests once the exporter returns a pending acknowledgment for it, see NewPendingAck. The request is
+// then finished once the acknowledgment arrives. It returns false if the consumer must send the request itself,
+// because the consumers wait for the acknowledgments or the queue is shutting down.
+func (qs *queueSender) detach(item internal.Request) (*consumerRelease, bool) {
+	if qs.ackSlots == nil {
+		return nil, false
+	}
+	qs.detachMu.RLock()
+	defer qs.detachMu.RUnlock()
+	if qs.shuttingDown.Load() {
+		return nil, false
+	}
+	qs.detached.Add(1)
+	release := &consumerRelease{released: make(chan struct{}), slots: qs.ackSlots}
+	item.SetContext(context.WithValue(item.Context(), releaseConsumerKey{}, release.onPendingAck))
+	return release, true
+}
+
+// consumerRelease releases the queue consumer waiting for a detached request once its acknowledgment is pending,
+// or once it is sent.
+type consumerRelease struct {
+	once     sync.Once
+	released chan struct{}
+	// slots are the free slots of the requests waiting for their acknowledgment, one is taken while pending.
+	slots   chan struct{}
+	pending bool
+}
+
+// onPendingAck releases the consumer as soon as a slot is free for the request waiting for its acknowledgment.
+func (r *consumerRelease) onPendingAck() {
+	r.once.Do(func() {
+		r.slots <- struct{}{}
+		r.pending = true
+		close(r.released)
+	})
+}
 
+// onSent releases the consumer if it is still waiting, and the slot of the request if it took one.
+func (r *consumerRelease) onSent() {
+	r.once.Do(func() {
+		close(r.released)
+	})
+	if r.pending {
+		<-r.slots
+	}
+}
+
+// dropped reports the request permanently dropped by the queue sender.
+func (qs *queueSender) dropped(req internal.Request, err error) {
+	if qs.onDropped != nil {
+		qs.onDropped(req, err)
+	}
+}
+
+// resize changes the size and the number of consumers of the queue.
+func (qs *queueSender) resize(queueSize int, numConsumers int) error {
+	if qs.resizable == nil {
+		return errQueueNotResizable
+	}
+	if err := qs.resizable.Resize(queueSize, numConsumers); err != nil {
+		return fmt.Errorf("failed to resize the sending_queue: %w", err)
+	}
+	qs.logger.Info("The sending_queue is resized.",
+		zap.Int("queue_size", queueSize),
+		zap.Int("num_consumers", numConsumers))
 	return nil
 }
 
+// onEnqueue records the current time as the time the request is added to the queue.
+func (qs *queueSender) onEnqueue(req internal.Request) {
+	req.SetEnqueuedAt(qs.now())
+	// The persistent queue returns new requests read from the storage, and keeps their enqueue times itself.
+	if !qs.queue.IsPersistent() {
+		qs.pending.add(req)
+	}
+}
+
+// onDequeued records the time the request spent in the queue and returns it, or zero if it is not known, e.g. for
+// the requests stored by an older version of the persistent queue.
+func (qs *queueSender) onDequeued(req internal.Request) time.Duration {
+	qs.dequeued.Add(1)
+	qs.pending.remove(req)
+	if req.EnqueuedAt().IsZero() {
+		return 0
+	}
+	latency := qs.now().Sub(req.EnqueuedAt())
+	trace.SpanFromContext(req.Context()).AddEvent("Dequeued item.", trace.WithAttributes(qs.traceAttribute,
+		attribute.Int64(obsmetrics.QueueLatencyKey, latency.Milliseconds())))
+	if obsreportconfig.UseOtelForInternalMetricsfeatureGate.IsEnabled() {
+		qs.metricLatency.Record(req.Context(), latency.Milliseconds(),
+			otelmetric.WithAttributes(attribute.String(obsmetrics.ExporterKey, qs.fullName)))
+		return latency
+	}
+	_ = stats.RecordWithTags(req.Context(), qs.mutators, obsmetrics.ExporterQueueLatency.M(latency.Milliseconds()))
+	return latency
+}
+
+// endRequestSpan ends the span tracing the request in the in-memory queue.
+func (qs *queueSender) endRequestSpan(span trace.Span, err error) {
+	if qs.queue.IsPersistent() {
+		return
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
 // Shutdown is invoked during service shutdown.
 func (qs *queueSender) Shutdown(ctx context.Context) error {
 	// Cleanup queue metrics reporting
 	_ = globalInstruments.queueSize.UpsertEntry(func() int64 {
 		return int64(0)
 	}, metricdata.NewLabelValue(qs.fullName))
+	_ = globalInstruments.queueOldestItemAge.UpsertEntry(func() int64 {
+		return int64(0)
+	}, metricdata.NewLabelValue(qs.fullName))
 
-	// Stop the queued sender, this will drain the queue and will call the retry (which is stopped) that will only
-	// try once every request.
-	return qs.queue.Shutdown(ctx)
+	// Stop waiting for the throttling to end, then stop the queued sender, this will drain the queue and will call
+	// the retry (which is stopped) that will only try once every request.
+	qs.detachMu.Lock()
+	qs.shuttingDown.Store(true)
+	qs.detachMu.Unlock()
+	close(qs.stopCh)
+	if qs.queue.IsPersistent() {
+		// The requests waiting for their acknowledgment are finished before the storage is closed.
+		qs.detached.Wait()
+		return qs.queue.Shutdown(ctx)
+	}
+
+	if qs.shutdownTimeout > 0 {
+		timer := time.AfterFunc(qs.shutdownTimeout, func() {
+			qs.drainExpired.Store(true)
+		})
+		defer timer.Stop()
+	}
+	err := qs.queue.Shutdown(ctx)
+	qs.detached.Wait()
+	if flushed, dropped := qs.flushedItems.Load(), qs.droppedItems.Load(); flushed > 0 || dropped > 0 {
+		qs.logger.Info("Sending queue drained on shutdown.",
+			zap.Int64("flushed_items", flushed),
+			zap.Int64("dropped_items", dropped),
+		)
+	}
+	return err
 }
 
 // send implements the requestSender interface
 func (qs *queueSender) send(req internal.Request) error {
 	// Prevent cancellation and deadline to propagate to the context stored in the queue.
 	// The grpc/http based receivers will cancel the request context after this function returns.
-	req.SetContext(noCancellationContext{Context: req.Context()})
+	var ctx context.Context = noCancellationContext{Context: req.Context()}
+	var span trace.Span
+	if qs.queue.IsPersistent() {
+		// The context is not stored in the persistent queue, so the request cannot be traced once it is dequeued.
+		span = trace.SpanFromContext(ctx)
+	} else {
+		// The span covers the time the request waits in the queue, all the attempts to send it and the final result.
+		ctx, span = qs.tracer.Start(ctx, qs.spanName, trace.WithAttributes(qs.traceAttribute))
+	}
+	req.SetContext(ctx)
 
-	span := trace.SpanFromContext(req.Context())
+	if qs.shed(req) {
+		qs.logger.Warn(
+			"Dropping low priority data because sending_queue is nearly full.",
+			zap.Int("dropped_items", req.Count()),
+		)
+		span.AddEvent("Dropped low priority item, sending_queue is nearly full.", trace.WithAttributes(qs.traceAttribute))
+		qs.endRequestSpan(span, errLoadShed)
+		qs.dropped(req, errLoadShed)
+		return consumererror.NewBackpressure(errLoadShed, qs.retryAfter())
+	}
+
+	if err := qs.checkRequestSize(req); err != nil {
+		qs.logger.Error(
+			"Dropping data because the request is larger than the sending_queue capacity. "+
+				"Try increasing queue_size or splitting the requests, e.g. with the batch processor send_batch_max_size.",
+			zap.Int("dropped_items", req.Count()),
+		)
+		span.AddEvent("Dropped item, larger than the sending_queue capacity.", trace.WithAttributes(qs.traceAttribute))
+		qs.endRequestSpan(span, err)
+		qs.dropped(req, err)
+		return consumererror.NewPermanent(err)
+	}
+
+	qs.onEnqueue(req)
 	if !qs.queue.Produce(req) {
+		qs.pending.remove(req)
 		qs.logger.Error(
 			"Dropping data because sending_queue is full. Try increasing queue_size.",
 			zap.Int("dropped_items", req.Count()),
 		)
 		span.AddEvent("Dropped item, sending_queue is full.", trace.WithAttributes(qs.traceAttribute))
-		return errSendingQueueIsFull
+		qs.endRequestSpan(span, errSendingQueueIsFull)
+		qs.dropped(req, errSendingQueueIsFull)
+		return consumererror.NewBackpressure(errSendingQueueIsFull, qs.retryAfter())
 	}
 
+	qs.enqueued.Add(1)
 	span.AddEvent("Enqueued item.", trace.WithAttributes(qs.traceAttribute))
 	return nil
 }
 
+// checkRequestSize returns an error if the request can never fit in the queue, even when it is empty. The
+// requests sizer always fits one request, so only the requests larger than the capacity in items are refused.
+func (qs *queueSender) checkRequestSize(req internal.Request) error {
+	if _, isRequestsSizer := qs.sizer.(internal.RequestsSizer); isRequestsSizer {
+		return nil
+	}
+	size := qs.sizer.SizeOf(req)
+	capacity := uint64(qs.queue.Capacity())
+	if qs.shardCapacity > 0 && uint64(qs.shardCapacity) < capacity {
+		capacity = uint64(qs.shardCapacity)
+	}
+	if size > capacity {
+		return fmt.Errorf("%w: %d %s, capacity: %d %s", errRequestTooLarge, size, qs.sizeUnit, capacity, qs.sizeUnit)
+	}
+	return nil
+}
+
+// retryAfter estimates the delay after which the data refused because the queue is full can be sent
+// again: the time the oldest request has been waiting in the queue, which is about the time the queue
+// takes to drain, or the time the destination throttles the exporter if longer.
+func (qs *queueSender) retryAfter() time.Duration {
+	delay := qs.oldestAge()
+	if throttled := qs.throttle.remaining(); throttled > delay {
+		delay = throttled
+	}
+	if delay < minRetryAfter {
+		delay = minRetryAfter
+	}
+	return delay
+}
+
+// oldestAge returns the age of the oldest request in the queue, or zero if the queue is empty or the age is unknown.
+func (qs *queueSender) oldestAge() time.Duration {
+	var oldest time.Time
+	if aq, ok := qs.queue.(agingQueue); ok {
+		oldest = aq.OldestEnqueuedAt()
+	} else {
+		oldest = qs.pending.oldest()
+	}
+	if oldest.IsZero() {
+		return 0
+	}
+	return qs.now().Sub(oldest)
+}
+
+// pendingRequests keeps the requests waiting in the in-memory queue, in the order they were added to it. They are tracked
+// by identity rather than by position, so the requests taken out of order, e.g. by the sharded queue, or dropped by the
+// queue, are forgotten without affecting the other requests.
+type pendingRequests struct {
+	mu    sync.Mutex
+	list  *list.List
+	elems map[internal.Request]*list.Element
+}
+
+func newPendingRequests() *pendingRequests {
+	return &pendingRequests{
+		list:  list.New(),
+		elems: map[internal.Request]*list.Element{},
+	}
+}
+
+// add starts tracking a request added to the queue.
+func (pr *pendingRequests) add(req internal.Request) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.elems[req] = pr.list.PushBack(req)
+}
+
+// remove stops tracking a request taken from the queue, or not accepted by it. Unknown requests are ignored.
+func (pr *pendingRequests) remove(req internal.Request) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if elem, ok := pr.elems[req]; ok {
+		pr.list.Remove(elem)
+		delete(pr.elems, req)
+	}
+}
+
+// oldest returns the enqueue time of the oldest request in the queue, or the zero time if the queue is empty.
+func (pr *pendingRequests) oldest() time.Time {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	front := pr.list.Front()
+	if front == nil {
+		return time.Time{}
+	}
+	return front.Value.(internal.Request).EnqueuedAt()
+}
+
 type noCancellationContext struct {
 	context.Context
 }
@@ -5,6 +5,7 @@ package exporterhelper
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"sync/atomic"
 	"testing"
@@ -15,14 +16,18 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/extension/experimental/memorybudget"
 	"go.opentelemetry.io/collector/featuregate"
 	"go.opentelemetry.io/collector/internal/obsreportconfig"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
 func TestQueuedRetry_StopWhileWaiting(t *testing.T) {
@@ -94,7 +99,48 @@ func TestQueuedRetry_DropOnFull(t *testing.T) {
 	t.Cleanup(func() {
 		assert.NoError(t, be.Shutdown(context.Background()))
 	})
-	require.Error(t, be.send(newMockRequest(context.Background(), 2, nil)))
+	err = be.send(newMockRequest(context.Background(), 2, nil))
+	require.ErrorIs(t, err, errSendingQueueIsFull)
+	// The refused data is retryable once the queue is drained.
+	var bp consumererror.Backpressure
+	require.ErrorAs(t, err, &bp)
+	assert.GreaterOrEqual(t, bp.RetryAfter(), minRetryAfter)
+}
+
+func TestQueuedRetry_DropOnFullItems(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.QueueSize = 3
+	qCfg.QueueSizeUnit = QueueSizeUnitItems
+	qCfg.NumConsumers = 0
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+	require.NoError(t, be.send(newMockRequest(context.Background(), 2, nil)))
+	require.ErrorIs(t, be.send(newMockRequest(context.Background(), 2, nil)), errSendingQueueIsFull)
+	require.NoError(t, be.send(newMockRequest(context.Background(), 1, nil)))
+	assert.Equal(t, 3, be.queueSender.(*queueSender).queue.Size())
+}
+
+func TestQueuedRetry_RequestLargerThanCapacity(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.QueueSize = 3
+	qCfg.QueueSizeUnit = QueueSizeUnitItems
+	qCfg.NumConsumers = 0
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+	// The request would not fit even in the empty queue, so it is refused permanently.
+	err = be.send(newMockRequest(context.Background(), 4, nil))
+	require.ErrorIs(t, err, errRequestTooLarge)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.EqualError(t, err, "Permanent error: request is larger than the sending_queue capacity: 4 items, capacity: 3 items")
+	assert.Equal(t, 0, be.queueSender.(*queueSender).queue.Size())
 }
 
 func TestQueuedRetryHappyPath(t *testing.T) {
@@ -161,9 +207,40 @@ func TestQueuedRetry_QueueMetricsReported(t *testing.T) {
 		require.NoError(t, be.send(newErrorRequest(context.Background())))
 	}
 	checkValueForGlobalManager(t, defaultExporterTags, int64(7), "exporter/queue_size")
+	checkValueForGlobalManager(t, defaultExporterTags, int64(7), "exporter/queue_enqueued_requests")
+	checkValueForGlobalManager(t, defaultExporterTags, int64(0), "exporter/queue_dequeued_requests")
 
 	assert.NoError(t, be.Shutdown(context.Background()))
 	checkValueForGlobalManager(t, defaultExporterTags, int64(0), "exporter/queue_size")
+	checkValueForGlobalManager(t, defaultExporterTags, int64(0), "exporter/queue_oldest_item_age")
+}
+
+func TestPendingRequests(t *testing.T) {
+	now := time.Unix(0, 0)
+	newRequest := func() internal.Request {
+		req := newMockRequest(context.Background(), 1, nil)
+		req.SetEnqueuedAt(now)
+		now = now.Add(time.Second)
+		return req
+	}
+	pr := newPendingRequests()
+	assert.True(t, pr.oldest().IsZero())
+
+	first := newRequest()
+	rejected := newRequest()
+	last := newRequest()
+	pr.add(first)
+	pr.add(rejected)
+	pr.add(last)
+	pr.remove(rejected)
+	assert.Equal(t, first.EnqueuedAt(), pr.oldest())
+
+	// The requests are forgotten by identity, whatever the order they are taken from the queue.
+	pr.remove(last)
+	assert.Equal(t, first.EnqueuedAt(), pr.oldest())
+	pr.remove(newRequest())
+	pr.remove(first)
+	assert.True(t, pr.oldest().IsZero())
 }
 
 func TestQueuedRetry_QueueMetricsReportedUsingOTel(t *testing.T) {
@@ -219,11 +296,413 @@ func TestQueueSettings_Validate(t *testing.T) {
 
 	assert.EqualError(t, qCfg.Validate(), "number of queue consumers must be positive")
 
+	qCfg = NewDefaultQueueSettings()
+	qCfg.QueueSizeUnit = "bytes"
+	assert.EqualError(t, qCfg.Validate(), `unsupported queue size unit "bytes"`)
+
+	qCfg.QueueSizeUnit = QueueSizeUnitItems
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg = NewDefaultQueueSettings()
+	qCfg.MaxBytes = -1
+	assert.EqualError(t, qCfg.Validate(), "max bytes must not be negative")
+
+	qCfg.MaxBytes = 1024
+	assert.EqualError(t, qCfg.Validate(), "max bytes can only be set when the persistent queue is enabled")
+
+	storageID := component.NewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.ShutdownTimeout = -time.Second
+	assert.EqualError(t, qCfg.Validate(), "shutdown timeout must not be negative")
+
+	qCfg.ShutdownTimeout = time.Second
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.MaxLatency = -time.Second
+	assert.EqualError(t, qCfg.Validate(), "max latency must not be negative")
+
+	qCfg.MaxLatency = time.Second
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.MaxPendingAcks = -1
+	assert.EqualError(t, qCfg.Validate(), "max pending acks must not be negative")
+
+	qCfg.MaxPendingAcks = 0
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.OverflowPolicy = "invalid"
+	assert.EqualError(t, qCfg.Validate(), `unsupported overflow policy "invalid"`)
+
+	qCfg.OverflowPolicy = OverflowPolicyDropOldest
+	assert.EqualError(t, qCfg.Validate(), `overflow policy "drop_oldest" is not supported by the persistent queue`)
+
+	qCfg.StorageID = nil
+	qCfg.MaxBytes = 0
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.OverflowPolicy = OverflowPolicyBlock
+	assert.EqualError(t, qCfg.Validate(), "block timeout must be positive when the overflow policy is block")
+
+	qCfg.BlockTimeout = time.Second
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg = NewDefaultQueueSettings()
+	qCfg.Compaction.Threshold = -1
+	assert.EqualError(t, qCfg.Validate(), "compaction interval and threshold must not be negative")
+
+	qCfg.Compaction.Threshold = 0
+	qCfg.Compaction.Interval = time.Minute
+	assert.EqualError(t, qCfg.Validate(), "compaction can only be set when the persistent queue is enabled")
+
+	qCfg.StorageID = &storageID
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg = NewDefaultQueueSettings()
+	qCfg.Encryption.Key = configopaque.String(base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	assert.EqualError(t, qCfg.Validate(), "encryption can only be set when the persistent queue is enabled")
+
+	qCfg.StorageID = &storageID
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.Encryption.Key = "not base64"
+	assert.ErrorContains(t, qCfg.Validate(), "encryption key must be base64 encoded")
+
+	qCfg.Encryption.Key = configopaque.String(base64.StdEncoding.EncodeToString(make([]byte, 10)))
+	assert.EqualError(t, qCfg.Validate(), "encryption key must be 16, 24 or 32 bytes long, got 10 bytes")
+
+	qCfg = NewDefaultQueueSettings()
+	qCfg.StorageCompression = "gzip"
+	assert.EqualError(t, qCfg.Validate(), `unsupported storage compression "gzip"`)
+
+	qCfg.StorageCompression = QueueCompressionZstd
+	assert.EqualError(t, qCfg.Validate(), "storage compression can only be set when the persistent queue is enabled")
+
+	qCfg.StorageID = &storageID
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.StorageCompression = QueueCompressionSnappy
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg = NewDefaultQueueSettings()
+	qCfg.ShardCapacity = -1
+	assert.EqualError(t, qCfg.Validate(), "shard capacity must not be negative")
+
+	qCfg.ShardCapacity = 100
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.StorageID = &storageID
+	assert.EqualError(t, qCfg.Validate(), "shard capacity is not supported by the persistent queue")
+
+	qCfg = NewDefaultQueueSettings()
+	budgetID := component.NewID("memory_budget")
+	qCfg.MemoryBudgetID = &budgetID
+	assert.NoError(t, qCfg.Validate())
+
+	qCfg.StorageID = &storageID
+	assert.EqualError(t, qCfg.Validate(), "memory budget is not supported by the persistent queue")
+
 	// Confirm Validate doesn't return error with invalid config when feature is disabled
 	qCfg.Enabled = false
 	assert.NoError(t, qCfg.Validate())
 }
 
+func TestQueuedRetry_OverflowDropOldest(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 0
+	qCfg.QueueSize = 2
+	qCfg.OverflowPolicy = OverflowPolicyDropOldest
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, be.send(newMockRequest(context.Background(), 2, nil)))
+	}
+	assert.Equal(t, 2, be.queueSender.(*queueSender).queue.Size())
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestQueuedRetry_ShardCapacity(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 0
+	qCfg.QueueSize = 10
+	qCfg.ShardCapacity = 2
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeTracesExporterConfig,
+		newTraceDataPusher(nil), WithQueue(qCfg), WithTracesShardKey(func(_ context.Context, td ptrace.Traces) string {
+			tenant, _ := td.ResourceSpans().At(0).Resource().Attributes().Get("tenant")
+			return tenant.Str()
+		}))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, te.Shutdown(context.Background()))
+	})
+
+	tenantTraces := func(tenant string) ptrace.Traces {
+		td := testdata.GenerateTraces(1)
+		td.ResourceSpans().At(0).Resource().Attributes().PutStr("tenant", tenant)
+		return td
+	}
+	require.NoError(t, te.ConsumeTraces(context.Background(), tenantTraces("a")))
+	require.NoError(t, te.ConsumeTraces(context.Background(), tenantTraces("a")))
+	// The shard of the tenant "a" is full, but the other tenants can still use the queue.
+	assert.ErrorIs(t, te.ConsumeTraces(context.Background(), tenantTraces("a")), errSendingQueueIsFull)
+	require.NoError(t, te.ConsumeTraces(context.Background(), tenantTraces("b")))
+}
+
+type fakeMemoryBudgetExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	budget memorybudget.Budget
+}
+
+func (e *fakeMemoryBudgetExtension) GetBudget() memorybudget.Budget {
+	return e.budget
+}
+
+func TestQueuedRetry_MemoryBudget(t *testing.T) {
+	td := testdata.GenerateTraces(1)
+	size := int64(tracesMarshaler.TracesSize(td))
+	budgetID := component.NewID("memory_budget")
+	host := &mockHost{ext: map[component.ID]component.Component{
+		budgetID: &fakeMemoryBudgetExtension{budget: memorybudget.NewBudget(3 * size)},
+	}}
+
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 0
+	qCfg.MemoryBudgetID = &budgetID
+	// The queues of both exporters share the same budget.
+	var exporters []exporter.Traces
+	for i := 0; i < 2; i++ {
+		te, err := NewTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeTracesExporterConfig,
+			newTraceDataPusher(nil), WithQueue(qCfg))
+		require.NoError(t, err)
+		require.NoError(t, te.Start(context.Background(), host))
+		t.Cleanup(func() {
+			assert.NoError(t, te.Shutdown(context.Background()))
+		})
+		exporters = append(exporters, te)
+	}
+
+	require.NoError(t, exporters[0].ConsumeTraces(context.Background(), td))
+	require.NoError(t, exporters[0].ConsumeTraces(context.Background(), td))
+	require.NoError(t, exporters[1].ConsumeTraces(context.Background(), td))
+	assert.ErrorIs(t, exporters[1].ConsumeTraces(context.Background(), td), errSendingQueueIsFull)
+}
+
+func TestQueuedRetry_MemoryBudgetNotFound(t *testing.T) {
+	budgetID := component.NewID("memory_budget")
+	qCfg := NewDefaultQueueSettings()
+	qCfg.MemoryBudgetID = &budgetID
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	assert.EqualError(t, be.Start(context.Background(), &mockHost{}), `memory budget extension "memory_budget" not found`)
+
+	host := &mockHost{ext: map[component.ID]component.Component{
+		budgetID: internal.NewMockStorageExtension(nil),
+	}}
+	be, err = newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	assert.EqualError(t, be.Start(context.Background(), host), `extension "memory_budget" is not a memory budget extension`)
+}
+
+// slowRequest takes the given time to be exported.
+type slowRequest struct {
+	baseRequest
+	delay    time.Duration
+	exported *atomic.Int64
+}
+
+func (r *slowRequest) Export(context.Context) error {
+	time.Sleep(r.delay)
+	r.exported.Add(1)
+	return nil
+}
+
+func (r *slowRequest) OnError(error) internal.Request {
+	return r
+}
+
+func (r *slowRequest) Count() int {
+	return 1
+}
+
+func TestQueuedRetry_ShutdownTimeout(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.ShutdownTimeout = 150 * time.Millisecond
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	exported := &atomic.Int64{}
+	for i := 0; i < 10; i++ {
+		require.NoError(t, be.send(&slowRequest{baseRequest: baseRequest{ctx: context.Background()},
+			delay: 100 * time.Millisecond, exported: exported}))
+	}
+
+	start := time.Now()
+	require.NoError(t, be.Shutdown(context.Background()))
+	// The request being sent when the timeout passes is still finished.
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	qs := be.queueSender.(*queueSender)
+	assert.Less(t, exported.Load(), int64(10))
+	assert.Equal(t, int64(10), exported.Load()+qs.droppedItems.Load())
+	assert.LessOrEqual(t, qs.flushedItems.Load(), exported.Load())
+}
+
+func TestQueuedRetry_ShutdownWithoutTimeout(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	exported := &atomic.Int64{}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, be.send(&slowRequest{baseRequest: baseRequest{ctx: context.Background()},
+			delay: 10 * time.Millisecond, exported: exported}))
+	}
+
+	require.NoError(t, be.Shutdown(context.Background()))
+	assert.Equal(t, int64(5), exported.Load())
+	assert.Equal(t, int64(0), be.queueSender.(*queueSender).droppedItems.Load())
+}
+
+func TestQueuedRetry_MaxLatencyStopsRetries(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.MaxLatency = 100 * time.Millisecond
+	rCfg := NewDefaultRetrySettings()
+	rCfg.InitialInterval = 10 * time.Millisecond
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newObservabilityConsumerSender, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	ocs := be.obsrepSender.(*observabilityConsumerSender)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	start := time.Now()
+	ocs.run(func() {
+		require.NoError(t, be.send(newErrorRequest(context.Background())))
+	})
+	ocs.awaitAsyncProcessing()
+
+	// The request is failed once the max latency passes instead of being retried for the max elapsed time.
+	assert.Less(t, time.Since(start), time.Second)
+	ocs.checkDroppedItemsCount(t, 7)
+}
+
+func TestQueuedRetry_MaxLatencyDropsStaleRequests(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.MaxLatency = 50 * time.Millisecond
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	exported := &atomic.Int64{}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, be.send(&slowRequest{baseRequest: baseRequest{ctx: context.Background()},
+			delay: 100 * time.Millisecond, exported: exported}))
+	}
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	// The first request is sent right away, the other ones waited in the queue for longer than the max latency.
+	assert.Equal(t, int64(1), exported.Load())
+}
+
+func TestQueuedRetry_MaxLatencyPersistentQueueRestored(t *testing.T) {
+	storageID := component.NewIDWithName("file_storage", "storage")
+	host := &mockHost{ext: map[component.ID]component.Component{
+		storageID: internal.NewMockStorageExtension(nil),
+	}}
+	qCfg := NewDefaultQueueSettings()
+	qCfg.StorageID = &storageID
+	qCfg.MaxLatency = time.Minute
+	sink := new(consumertest.TracesSink)
+	newExporter := func(numConsumers int, now time.Time) exporter.Traces {
+		qCfg.NumConsumers = numConsumers
+		te, err := NewTracesExporter(context.Background(), defaultSettings, &fakeTracesExporterConfig, sink.ConsumeTraces, WithQueue(qCfg))
+		require.NoError(t, err)
+		te.(*traceExporter).queueSender.(*queueSender).now = func() time.Time { return now }
+		require.NoError(t, te.Start(context.Background(), host))
+		return te
+	}
+
+	// The request is stored without being sent, then the collector restarts after the max latency.
+	now := time.Now()
+	te := newExporter(0, now)
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+	qs := te.(*traceExporter).queueSender.(*queueSender)
+	assert.Eventually(t, func() bool { return qs.queue.Size() == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, te.Shutdown(context.Background()))
+
+	te = newExporter(1, now.Add(2*time.Minute))
+	t.Cleanup(func() { require.NoError(t, te.Shutdown(context.Background())) })
+	qs = te.(*traceExporter).queueSender.(*queueSender)
+	assert.Eventually(t, func() bool { return qs.dequeued.Load() == 1 }, time.Second, time.Millisecond)
+
+	// The restored request is dropped as stale, based on the time it was first added to the queue, while a new
+	// request is sent.
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+	assert.Eventually(t, func() bool { return sink.SpanCount() == 2 }, time.Second, time.Millisecond)
+	assert.Len(t, sink.AllTraces(), 1)
+}
+
+func TestQueuedRetry_ResizeQueue(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.QueueSize = 1
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	exported := &atomic.Int64{}
+	qs := be.queueSender.(*queueSender)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, be.send(&slowRequest{baseRequest: baseRequest{ctx: context.Background()},
+			delay: 50 * time.Millisecond, exported: exported}))
+		if i == 0 {
+			// Wait for the first request to be taken by the consumer.
+			assert.Eventually(t, func() bool { return qs.queue.Size() == 0 }, time.Second, time.Millisecond)
+		}
+	}
+	assert.Error(t, be.send(&slowRequest{baseRequest: baseRequest{ctx: context.Background()}, exported: exported}))
+
+	var resizer QueueResizer = be
+	require.NoError(t, resizer.ResizeQueue(10, 5))
+	assert.Equal(t, 10, qs.queue.Capacity())
+	for i := 0; i < 8; i++ {
+		require.NoError(t, be.send(&slowRequest{baseRequest: baseRequest{ctx: context.Background()},
+			delay: 50 * time.Millisecond, exported: exported}))
+	}
+	assert.EqualError(t, resizer.ResizeQueue(0, 5), "failed to resize the sending_queue: queue capacity must be positive")
+
+	require.NoError(t, be.Shutdown(context.Background()))
+	// The requests queued before the resize are not dropped.
+	assert.Equal(t, int64(10), exported.Load())
+}
+
+func TestQueuedRetry_ResizeQueueNotSupported(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	assert.ErrorIs(t, be.ResizeQueue(10, 1), errQueueNotEnabled)
+
+	qCfg = NewDefaultQueueSettings()
+	storageID := component.NewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID
+	be, err = newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	assert.ErrorIs(t, be.ResizeQueue(10, 1), errQueueNotResizable)
+}
+
 // if requeueing is enabled, we eventually retry even if we failed at first
 func TestQueuedRetry_RequeuingEnabled(t *testing.T) {
 	qCfg := NewDefaultQueueSettings()
@@ -318,9 +797,58 @@ func TestQueuedRetryPersistenceEnabled(t *testing.T) {
 
 	// we start correctly with a file storage extension
 	require.NoError(t, be.Start(context.Background(), host))
+	// the persistent queue reports the bytes reclaimed by the compaction
+	checkValueForGlobalManager(t, defaultExporterTags, int64(0), "exporter/queue_reclaimed_bytes")
 	require.NoError(t, be.Shutdown(context.Background()))
 }
 
+func TestQueuedRetryPersistenceEnabledEncryption(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	storageID := component.NewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID // enable persistence
+	qCfg.Encryption.Key = configopaque.String(base64.StdEncoding.EncodeToString(make([]byte, 16)))
+	be, err := newBaseExporter(defaultSettings, "", false, mockRequestMarshaler, mockRequestUnmarshaler(&mockRequest{}),
+		newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+
+	host := &mockHost{ext: map[component.ID]component.Component{
+		storageID: internal.NewMockStorageExtension(nil),
+	}}
+	require.NoError(t, be.Start(context.Background(), host))
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	// an invalid key fails the start
+	qCfg.Encryption.Key = "invalid"
+	be, err = newBaseExporter(defaultSettings, "", false, mockRequestMarshaler, mockRequestUnmarshaler(&mockRequest{}),
+		newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+	assert.ErrorContains(t, be.Start(context.Background(), host), "encryption key must be base64 encoded")
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestQueuedRetryPersistenceEnabledCompression(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	storageID := component.NewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID // enable persistence
+	qCfg.StorageCompression = QueueCompressionZstd
+	qCfg.Encryption.Key = configopaque.String(base64.StdEncoding.EncodeToString(make([]byte, 16)))
+	mockR := newMockRequest(context.Background(), 2, nil)
+	be, err := newBaseExporter(defaultSettings, "", false, mockRequestMarshaler, mockRequestUnmarshaler(mockR),
+		newNoopObsrepSender, WithQueue(qCfg))
+	require.NoError(t, err)
+
+	host := &mockHost{ext: map[component.ID]component.Component{
+		storageID: internal.NewMockStorageExtension(nil),
+	}}
+	require.NoError(t, be.Start(context.Background(), host))
+	t.Cleanup(func() {
+		require.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	require.NoError(t, be.send(mockR))
+	mockR.checkNumRequests(t, 1)
+}
+
 func TestQueuedRetryPersistenceEnabledStorageError(t *testing.T) {
 	storageError := errors.New("could not get storage client")
 	tt, err := obsreporttest.SetupTelemetry(defaultID)
@@ -381,6 +909,57 @@ func TestQueuedRetryPersistentEnabled_shutdown_dataIsRequeued(t *testing.T) {
 	}, time.Second, 1*time.Millisecond)
 }
 
+func TestQueuedRetryPersistentEnabled_shutdown_dataIsKept(t *testing.T) {
+	produceCounter := &atomic.Uint32{}
+	storageID := component.NewIDWithName("file_storage", "storage")
+	host := &mockHost{ext: map[component.ID]component.Component{
+		storageID: internal.NewMockStorageExtension(nil),
+	}}
+
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.StorageID = &storageID // enable persistence
+	rCfg := NewDefaultRetrySettings()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = 0 // retry infinitely so shutdown can be triggered
+
+	// the requests left for dispatch must not be empty to be restored
+	marshaler := func(internal.Request) ([]byte, error) {
+		return []byte("request"), nil
+	}
+	errReq := newErrorRequest(context.Background())
+	unmarshaler := func([]byte) (internal.Request, error) {
+		return errReq, nil
+	}
+	be, err := newBaseExporter(defaultSettings, "", false, marshaler, unmarshaler, newNoopObsrepSender, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), host))
+
+	// wraps original queue so we can count operations
+	be.queueSender.(*queueSender).queue = &producerConsumerQueueWithCounter{
+		Queue:          be.queueSender.(*queueSender).queue,
+		produceCounter: produceCounter,
+	}
+
+	require.NoError(t, be.send(errReq))
+	assert.Eventually(t, func() bool {
+		return be.queueSender.(*queueSender).queue.Size() == 0
+	}, time.Second, 1*time.Millisecond)
+
+	// the request being retried is not put back to the queue, it is kept in the storage instead
+	require.NoError(t, be.Shutdown(context.Background()))
+	assert.Equal(t, uint32(1), produceCounter.Load())
+
+	// the kept request is sent after the restart
+	mockR := newMockRequest(context.Background(), 2, nil)
+	be, err = newBaseExporter(defaultSettings, "", false, marshaler, mockRequestUnmarshaler(mockR), newNoopObsrepSender,
+		WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), host))
+	mockR.checkNumRequests(t, 1)
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
 type mockHost struct {
 	component.Host
 	ext map[component.ID]component.Component
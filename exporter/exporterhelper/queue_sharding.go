@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// WithTracesShardKey sets the function returning the key of the sending queue shard the traces belong to,
+// e.g. the value of a resource attribute identifying the tenant. The size of every shard in the queue is limited
+// by QueueSettings.ShardCapacity. This option can only be used with NewTracesExporter.
+func WithTracesShardKey(shardKey func(ctx context.Context, td ptrace.Traces) string) Option {
+	return withShardKey(func(req internal.Request) string {
+		tr, ok := req.(*tracesRequest)
+		if !ok {
+			return ""
+		}
+		return shardKey(tr.Context(), tr.td)
+	})
+}
+
+// WithMetricsShardKey sets the function returning the key of the sending queue shard the metrics belong to,
+// e.g. the value of a resource attribute identifying the tenant. The size of every shard in the queue is limited
+// by QueueSettings.ShardCapacity. This option can only be used with NewMetricsExporter.
+func WithMetricsShardKey(shardKey func(ctx context.Context, md pmetric.Metrics) string) Option {
+	return withShardKey(func(req internal.Request) string {
+		mr, ok := req.(*metricsRequest)
+		if !ok {
+			return ""
+		}
+		return shardKey(mr.Context(), mr.md)
+	})
+}
+
+// WithLogsShardKey sets the function returning the key of the sending queue shard the logs belong to,
+// e.g. the value of a resource attribute identifying the tenant. The size of every shard in the queue is limited
+// by QueueSettings.ShardCapacity. This option can only be used with NewLogsExporter.
+func WithLogsShardKey(shardKey func(ctx context.Context, ld plog.Logs) string) Option {
+	return withShardKey(func(req internal.Request) string {
+		lr, ok := req.(*logsRequest)
+		if !ok {
+			return ""
+		}
+		return shardKey(lr.Context(), lr.ld)
+	})
+}
+
+// WithRequestShardKey sets the function returning the key of the sending queue shard the request belongs to.
+// The size of every shard in the queue is limited by QueueSettings.ShardCapacity. This option can only be used
+// with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func WithRequestShardKey(shardKey func(ctx context.Context, req Request) string) Option {
+	return withShardKey(func(req internal.Request) string {
+		r, ok := req.(*request)
+		if !ok {
+			return ""
+		}
+		return shardKey(r.Context(), r.Request)
+	})
+}
+
+func withShardKey(shardKey internal.ShardKeyFunc) Option {
+	return func(o *baseExporter) {
+		o.shardKey = shardKey
+		if qs, ok := o.queueSender.(*queueSender); ok {
+			qs.shardKey = shardKey
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+// serializingQueue is a Queue that stores the serialized requests, like a queue backed by an external storage.
+type serializingQueue struct {
+	set   QueueCreateSettings
+	items chan []byte
+	wg    sync.WaitGroup
+}
+
+func newSerializingQueue(set QueueCreateSettings) (Queue, error) {
+	return &serializingQueue{set: set, items: make(chan []byte, set.Config.QueueSize)}, nil
+}
+
+func (q *serializingQueue) Start(_ context.Context, _ component.Host, consume func(req Request)) error {
+	for i := 0; i < q.set.Config.NumConsumers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for data := range q.items {
+				req, err := q.set.Unmarshaler(data)
+				if err != nil {
+					continue
+				}
+				consume(req)
+			}
+		}()
+	}
+	return nil
+}
+
+func (q *serializingQueue) Produce(req Request) bool {
+	data, err := q.set.Marshaler(req)
+	if err != nil {
+		return false
+	}
+	select {
+	case q.items <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+func (q *serializingQueue) Size() int {
+	return len(q.items)
+}
+
+func (q *serializingQueue) Capacity() int {
+	return cap(q.items)
+}
+
+func (q *serializingQueue) Shutdown(context.Context) error {
+	close(q.items)
+	q.wg.Wait()
+	return nil
+}
+
+func TestWithQueueFactory(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 2
+	qCfg.QueueSize = 5
+	sink := new(consumertest.TracesSink)
+	var created QueueCreateSettings
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeTracesExporterConfig,
+		sink.ConsumeTraces, WithQueueFactory(func(set QueueCreateSettings) (Queue, error) {
+			created = set
+			return newSerializingQueue(set)
+		}), WithQueue(qCfg))
+	require.NoError(t, err)
+	assert.Equal(t, component.DataTypeTraces, created.DataType)
+	assert.Equal(t, qCfg, created.Config)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+	}
+	assert.Eventually(t, func() bool {
+		return sink.SpanCount() == 6
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, te.Shutdown(context.Background()))
+}
+
+func TestWithQueueFactory_RequestExporter(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	exported := make(chan Request, 1)
+	req := &fakeRequest{items: 3}
+	be, err := newBaseExporter(defaultSettings, "", true, nil, nil, newNoopObsrepSender,
+		WithRequestQueue(qCfg, func(Request) ([]byte, error) { return []byte("request"), nil },
+			func([]byte) (Request, error) { return &exportedRequest{Request: req, exported: exported}, nil }),
+		WithQueueFactory(newSerializingQueue))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, be.send(newRequest(context.Background(), req)))
+	select {
+	case r := <-exported:
+		assert.Equal(t, req, r)
+	case <-time.After(time.Second):
+		assert.Fail(t, "the request restored from the queue is not exported")
+	}
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+// exportedRequest sends the wrapped request to the exported channel when it is exported.
+type exportedRequest struct {
+	Request
+	exported chan Request
+}
+
+func (r *exportedRequest) Export(context.Context) error {
+	r.exported <- r.Request
+	return nil
+}
+
+func TestWithQueueFactory_Error(t *testing.T) {
+	factoryErr := errors.New("cannot create the queue")
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender,
+		WithQueue(NewDefaultQueueSettings()), WithQueueFactory(func(QueueCreateSettings) (Queue, error) {
+			return nil, factoryErr
+		}))
+	require.NoError(t, err)
+	assert.ErrorIs(t, be.Start(context.Background(), componenttest.NewNopHost()), factoryErr)
+	assert.NoError(t, be.Shutdown(context.Background()))
+}
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// RateLimitSettings defines configuration for limiting the rate of data sent to the destination.
+// Requests exceeding the rate wait until enough capacity is available.
+type RateLimitSettings struct {
+	// Enabled indicates whether the rate limiting is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// ItemsPerSecond is the maximum rate of exported spans, metric data points or log records. Zero means no limit.
+	ItemsPerSecond float64 `mapstructure:"items_per_second"`
+	// ItemsBurst is the maximum number of items that can be exported at once above the rate.
+	// Zero means the burst is equal to ItemsPerSecond.
+	ItemsBurst int `mapstructure:"items_burst"`
+	// BytesPerSecond is the maximum rate of exported bytes. Zero means no limit.
+	// Only the requests that provide their size in bytes are accounted.
+	BytesPerSecond float64 `mapstructure:"bytes_per_second"`
+	// BytesBurst is the maximum number of bytes that can be exported at once above the rate.
+	// Zero means the burst is equal to BytesPerSecond.
+	BytesBurst int `mapstructure:"bytes_burst"`
+}
+
+// NewDefaultRateLimitSettings returns the default settings for RateLimitSettings.
+func NewDefaultRateLimitSettings() RateLimitSettings {
+	return RateLimitSettings{
+		Enabled: false,
+	}
+}
+
+// Validate checks if the RateLimitSettings configuration is valid
+func (cfg *RateLimitSettings) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.ItemsPerSecond < 0 || cfg.BytesPerSecond < 0 {
+		return errors.New("rate must not be negative")
+	}
+	if cfg.ItemsBurst < 0 || cfg.BytesBurst < 0 {
+		return errors.New("burst must not be negative")
+	}
+	if cfg.ItemsPerSecond == 0 && cfg.BytesPerSecond == 0 {
+		return errors.New("at least one of items or bytes per second must be set")
+	}
+	return nil
+}
+
+// tokenBucket implements the token bucket algorithm. Tokens are reserved in advance, so the bucket can go
+// below zero, and the callers wait for the time needed to refill it.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now func() time.Time) *tokenBucket {
+	b := float64(burst)
+	if b == 0 {
+		b = math.Ceil(rate)
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  b,
+		now:    now,
+		tokens: b,
+		last:   now(),
+	}
+}
+
+// reserve takes n tokens out of the bucket and returns the time to wait until they are available.
+// Requests larger than the burst take the whole burst.
+func (tb *tokenBucket) reserve(n int) time.Duration {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	now := tb.now()
+	tb.tokens = math.Min(tb.burst, tb.tokens+now.Sub(tb.last).Seconds()*tb.rate)
+	tb.last = now
+
+	tb.tokens -= math.Min(float64(n), tb.burst)
+	if tb.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+}
+
+// rateLimitSender is a requestSender that limits the rate of the exported items and bytes.
+type rateLimitSender struct {
+	baseRequestSender
+	items *tokenBucket
+	bytes *tokenBucket
+}
+
+func newRateLimitSender(config RateLimitSettings) *rateLimitSender {
+	rls := &rateLimitSender{}
+	if config.ItemsPerSecond > 0 {
+		rls.items = newTokenBucket(config.ItemsPerSecond, config.ItemsBurst, time.Now)
+	}
+	if config.BytesPerSecond > 0 {
+		rls.bytes = newTokenBucket(config.BytesPerSecond, config.BytesBurst, time.Now)
+	}
+	return rls
+}
+
+// send implements the requestSender interface
+func (rls *rateLimitSender) send(req internal.Request) error {
+	var delay time.Duration
+	if rls.items != nil {
+		delay = rls.items.reserve(req.Count())
+	}
+	if rls.bytes != nil {
+		if bytesDelay := rls.bytes.reserve(bytesCount(req)); bytesDelay > delay {
+			delay = bytesDelay
+		}
+	}
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			return fmt.Errorf("request is cancelled or timed out while waiting for the rate limit %w", req.Context().Err())
+		}
+	}
+	return rls.nextSender.send(req)
+}
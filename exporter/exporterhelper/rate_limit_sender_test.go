@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestRateLimitSettings_Validate(t *testing.T) {
+	cfg := NewDefaultRateLimitSettings()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Enabled = true
+	assert.EqualError(t, cfg.Validate(), "at least one of items or bytes per second must be set")
+
+	cfg.ItemsPerSecond = -1
+	assert.EqualError(t, cfg.Validate(), "rate must not be negative")
+
+	cfg.ItemsPerSecond = 100
+	cfg.BytesBurst = -1
+	assert.EqualError(t, cfg.Validate(), "burst must not be negative")
+
+	cfg.BytesBurst = 0
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	tb := newTokenBucket(10, 20, func() time.Time { return now })
+
+	// The burst is available right away.
+	assert.Equal(t, time.Duration(0), tb.reserve(15))
+	assert.Equal(t, time.Duration(0), tb.reserve(5))
+	// The bucket is empty, 5 tokens take half a second to refill.
+	assert.Equal(t, 500*time.Millisecond, tb.reserve(5))
+
+	// After 2 seconds the debt is paid and 15 tokens are available.
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, time.Duration(0), tb.reserve(15))
+
+	// Requests larger than the burst take the whole burst.
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, time.Duration(0), tb.reserve(100))
+	assert.Equal(t, 100*time.Millisecond, tb.reserve(1))
+}
+
+func TestTokenBucket_DefaultBurst(t *testing.T) {
+	now := time.Unix(0, 0)
+	tb := newTokenBucket(2.5, 0, func() time.Time { return now })
+	assert.Equal(t, time.Duration(0), tb.reserve(3))
+	assert.Equal(t, 400*time.Millisecond, tb.reserve(1))
+}
+
+func TestRateLimitSender(t *testing.T) {
+	cfg := NewDefaultRateLimitSettings()
+	cfg.Enabled = true
+	cfg.ItemsPerSecond = 100
+	rCfg := NewDefaultRetrySettings()
+	rCfg.Enabled = false
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+		WithRetry(rCfg), WithRateLimit(cfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The first request uses the whole burst, the second one has to wait for the bucket to refill.
+	start := time.Now()
+	mockR := newMockRequest(context.Background(), 100, nil)
+	require.NoError(t, be.send(mockR))
+	require.NoError(t, be.send(mockR))
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+	mockR.checkNumRequests(t, 2)
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestRateLimitSender_ContextCancelled(t *testing.T) {
+	cfg := NewDefaultRateLimitSettings()
+	cfg.Enabled = true
+	cfg.ItemsPerSecond = 1
+	rCfg := NewDefaultRetrySettings()
+	rCfg.Enabled = false
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+		WithRetry(rCfg), WithRateLimit(cfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, be.send(newMockRequest(context.Background(), 1, nil)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	mockR := newMockRequest(ctx, 100, nil)
+	assert.ErrorIs(t, be.send(mockR), context.DeadlineExceeded)
+	mockR.checkNumRequests(t, 0)
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestRateLimitSender_Bytes(t *testing.T) {
+	cfg := NewDefaultRateLimitSettings()
+	cfg.Enabled = true
+	cfg.BytesPerSecond = 1
+	rls := newRateLimitSender(cfg)
+	assert.Nil(t, rls.items)
+	require.NotNil(t, rls.bytes)
+
+	// Traces requests report their size in bytes.
+	req := newTracesRequest(context.Background(), testdata.GenerateTraces(2), nil)
+	assert.Positive(t, bytesCount(req))
+	// Requests not reporting the size are not limited by bytes.
+	assert.Equal(t, 0, bytesCount(newMockRequest(context.Background(), 1, nil)))
+}
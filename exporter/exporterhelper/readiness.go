@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// errStartTimeout is returned by Start when the exporter is not started within the start timeout.
+var errStartTimeout = errors.New("exporter start timed out")
+
+// WithStartTimeout limits the time spent starting the exporter, including the readiness probe
+// set with WithReadinessProbe, so Start fails instead of hanging when the destination is unreachable.
+// The default is no limit, a timeout lower than or equal to zero is ignored.
+func WithStartTimeout(timeout time.Duration) Option {
+	return func(o *baseExporter) {
+		o.startTimeout = timeout
+	}
+}
+
+// WithReadinessProbe sets the probe checking that the destination of the exporter is reachable on start.
+// If the retryInterval is zero, Start fails when the probe fails. Otherwise, Start succeeds and the probe is
+// retried in the background every retryInterval, while the queue consumers don't send the requests
+// until it succeeds.
+func WithReadinessProbe(probe func(ctx context.Context) error, retryInterval time.Duration) Option {
+	return func(o *baseExporter) {
+		o.readiness = &readinessProbe{
+			probe:         probe,
+			retryInterval: retryInterval,
+			logger:        o.set.Logger,
+			throttle:      o.throttle,
+		}
+	}
+}
+
+// startWithTimeout starts the wrapped exporter and checks the readiness of the destination within the start timeout.
+func (be *baseExporter) startWithTimeout(ctx context.Context, host component.Host) error {
+	if be.startTimeout <= 0 {
+		return be.startAndProbe(ctx, host)
+	}
+	ctx, cancel := context.WithTimeout(ctx, be.startTimeout)
+	defer cancel()
+	// Start runs in a separate goroutine, so the timeout applies even if it doesn't respect the context.
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- be.startAndProbe(ctx, host)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w after %v: %w", errStartTimeout, be.startTimeout, ctx.Err())
+	}
+}
+
+func (be *baseExporter) startAndProbe(ctx context.Context, host component.Host) error {
+	if err := be.StartFunc.Start(ctx, host); err != nil {
+		return err
+	}
+	if be.readiness == nil {
+		return nil
+	}
+	return be.readiness.check(ctx)
+}
+
+// readinessProbe checks the readiness of the destination of an exporter.
+type readinessProbe struct {
+	probe         func(ctx context.Context) error
+	retryInterval time.Duration
+	logger        *zap.Logger
+	throttle      *throttleGate
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// check probes the destination. If it is not ready and the retries are enabled, the queue consumers are paused
+// and the probe is retried in the background.
+func (rp *readinessProbe) check(ctx context.Context) error {
+	err := rp.probe(ctx)
+	if err == nil {
+		return nil
+	}
+	if rp.retryInterval <= 0 {
+		return fmt.Errorf("destination is not ready: %w", err)
+	}
+	rp.logger.Warn("The destination is not ready, retrying the readiness probe in the background.",
+		zap.Duration("interval", rp.retryInterval),
+		zap.Error(err))
+	rp.throttle.pause()
+	rp.stopCh = make(chan struct{})
+	rp.wg.Add(1)
+	go rp.retry()
+	return nil
+}
+
+// retry probes the destination every retryInterval until it is ready or the exporter is shut down.
+func (rp *readinessProbe) retry() {
+	defer rp.wg.Done()
+	defer rp.throttle.resume()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-rp.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(rp.retryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-rp.stopCh:
+			return
+		}
+		if err := rp.probe(ctx); err != nil {
+			rp.logger.Debug("The destination is still not ready.", zap.Error(err))
+			continue
+		}
+		rp.logger.Info("The destination is ready.")
+		return
+	}
+}
+
+// shutdown stops the background probe, if any.
+func (rp *readinessProbe) shutdown() {
+	if rp.stopCh == nil {
+		return
+	}
+	rp.stopOnce.Do(func() {
+		close(rp.stopCh)
+	})
+	rp.wg.Wait()
+}
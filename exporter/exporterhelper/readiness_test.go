@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func TestStartTimeout(t *testing.T) {
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender,
+		WithStartTimeout(10*time.Millisecond),
+		WithStart(func(context.Context, component.Host) error {
+			// Start doesn't respect the context.
+			time.Sleep(time.Second)
+			return nil
+		}))
+	require.NoError(t, err)
+	err = be.Start(context.Background(), componenttest.NewNopHost())
+	assert.ErrorIs(t, err, errStartTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestStartTimeout_ReadinessProbe(t *testing.T) {
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender,
+		WithStartTimeout(10*time.Millisecond),
+		WithReadinessProbe(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, 0))
+	require.NoError(t, err)
+	assert.Error(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestReadinessProbe(t *testing.T) {
+	probeErr := errors.New("destination unreachable")
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender,
+		WithReadinessProbe(func(context.Context) error { return probeErr }, 0))
+	require.NoError(t, err)
+	assert.ErrorIs(t, be.Start(context.Background(), componenttest.NewNopHost()), probeErr)
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	be, err = newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender,
+		WithReadinessProbe(func(context.Context) error { return nil }, 0))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestReadinessProbe_RetryInBackground(t *testing.T) {
+	var attempts atomic.Int64
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithQueue(qCfg),
+		WithReadinessProbe(func(context.Context) error {
+			if attempts.Add(1) < 3 {
+				return errors.New("destination unreachable")
+			}
+			return nil
+		}, 50*time.Millisecond))
+	require.NoError(t, err)
+	start := time.Now()
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The queue consumers don't send the requests until the destination is ready.
+	mockR := newMockRequest(context.Background(), 1, nil)
+	require.NoError(t, be.send(mockR))
+	mockR.checkNumRequests(t, 1)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, int64(3), attempts.Load())
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestReadinessProbe_ShutdownStopsRetries(t *testing.T) {
+	var attempts atomic.Int64
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender,
+		WithReadinessProbe(func(context.Context) error {
+			attempts.Add(1)
+			return errors.New("destination unreachable")
+		}, time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		return attempts.Load() > 2
+	}, time.Second, time.Millisecond)
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	stopped := attempts.Load()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, stopped, attempts.Load())
+}
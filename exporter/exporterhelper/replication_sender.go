@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/multierr"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// ReplicationSettings defines configuration for sending every request to several destination endpoints.
+type ReplicationSettings struct {
+	// Enabled indicates whether the replication is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Quorum is the number of destination endpoints, including the primary one, that must accept a request
+	// for it to be successfully sent. Zero, or a number greater than the number of the endpoints, means all of them.
+	Quorum int `mapstructure:"quorum"`
+}
+
+// NewDefaultReplicationSettings returns the default settings for ReplicationSettings.
+func NewDefaultReplicationSettings() ReplicationSettings {
+	return ReplicationSettings{
+		Enabled: false,
+		Quorum:  0,
+	}
+}
+
+// Validate checks if the ReplicationSettings configuration is valid
+func (cfg *ReplicationSettings) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Quorum < 0 {
+		return errors.New("quorum must not be negative")
+	}
+	return nil
+}
+
+// WithTracesReplication enables sending every request to the given replica endpoints in addition to the destination
+// of the exporter. This option can only be used with NewTracesExporter.
+func WithTracesReplication(config ReplicationSettings, replicas ...consumer.ConsumeTracesFunc) Option {
+	return withReplication(config, newTracesEndpoints(replicas))
+}
+
+// WithMetricsReplication enables sending every request to the given replica endpoints in addition to the destination
+// of the exporter. This option can only be used with NewMetricsExporter.
+func WithMetricsReplication(config ReplicationSettings, replicas ...consumer.ConsumeMetricsFunc) Option {
+	return withReplication(config, newMetricsEndpoints(replicas))
+}
+
+// WithLogsReplication enables sending every request to the given replica endpoints in addition to the destination
+// of the exporter. This option can only be used with NewLogsExporter.
+func WithLogsReplication(config ReplicationSettings, replicas ...consumer.ConsumeLogsFunc) Option {
+	return withReplication(config, newLogsEndpoints(replicas))
+}
+
+// WithRequestReplication enables sending every request to the given replica endpoints in addition to the destination
+// of the exporter. This option can only be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func WithRequestReplication(config ReplicationSettings, replicas ...func(ctx context.Context, req Request) error) Option {
+	return withReplication(config, newRequestEndpoints(replicas))
+}
+
+func withReplication(config ReplicationSettings, replicas []endpoint) Option {
+	return func(o *baseExporter) {
+		if !config.Enabled || len(replicas) == 0 {
+			return
+		}
+		o.replicationSender = newReplicationSender(config, replicas)
+	}
+}
+
+// replicationSender is a requestSender that sends every request to all the destination endpoints concurrently,
+// and succeeds once the quorum of them accepted it.
+type replicationSender struct {
+	baseRequestSender
+	quorum int
+	// endpoints are the destination endpoints, the first one is the primary endpoint of the exporter.
+	endpoints []endpoint
+}
+
+func newReplicationSender(config ReplicationSettings, replicas []endpoint) *replicationSender {
+	endpoints := append([]endpoint{primaryEndpoint}, replicas...)
+	quorum := config.Quorum
+	if quorum == 0 || quorum > len(endpoints) {
+		quorum = len(endpoints)
+	}
+	return &replicationSender{
+		quorum:    quorum,
+		endpoints: endpoints,
+	}
+}
+
+// replicationStateKey is the context key of the replicationState of a request, for a replicationSender.
+type replicationStateKey struct {
+	rs *replicationSender
+}
+
+// replicationState records the endpoints that already accepted a request, or rejected it permanently,
+// so the retries of the request are only sent to the endpoints that failed transiently.
+type replicationState struct {
+	req       internal.Request
+	accepted  []bool
+	permanent []error
+}
+
+// state returns the replicationState of the request, stored in its context by the previous attempts.
+func (rs *replicationSender) state(req internal.Request) *replicationState {
+	// The failoverSender wraps the request in a new endpointRequest at every attempt.
+	data := dataRequest(req)
+	key := replicationStateKey{rs: rs}
+	// The state is not reused for the requests with the failed part of a request, see Request.OnError.
+	if st, ok := data.Context().Value(key).(*replicationState); ok && st.req == data {
+		return st
+	}
+	st := &replicationState{
+		req:       data,
+		accepted:  make([]bool, len(rs.endpoints)),
+		permanent: make([]error, len(rs.endpoints)),
+	}
+	data.SetContext(context.WithValue(data.Context(), key, st))
+	return st
+}
+
+// send implements the requestSender interface
+func (rs *replicationSender) send(req internal.Request) error {
+	st := rs.state(req)
+	errs := make([]error, len(rs.endpoints))
+	var wg sync.WaitGroup
+	for i := range rs.endpoints {
+		if st.accepted[i] || st.permanent[i] != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = rs.nextSender.send(&endpointRequest{Request: req, endpoint: rs.endpoints[i]})
+		}(i)
+	}
+	wg.Wait()
+
+	accepted, permanent := 0, 0
+	var permanentErr, transientErr error
+	for i, err := range errs {
+		switch {
+		case st.accepted[i]:
+			accepted++
+		case st.permanent[i] != nil:
+			permanent++
+			permanentErr = multierr.Append(permanentErr, fmt.Errorf("endpoint %d: %w", i, st.permanent[i]))
+		case err == nil:
+			st.accepted[i] = true
+			accepted++
+		case consumererror.IsPermanent(err):
+			st.permanent[i] = err
+			permanent++
+			permanentErr = multierr.Append(permanentErr, fmt.Errorf("endpoint %d: %w", i, err))
+		default:
+			transientErr = multierr.Append(transientErr, fmt.Errorf("endpoint %d: %w", i, err))
+		}
+	}
+	if accepted >= rs.quorum {
+		return nil
+	}
+	// The quorum cannot be reached by retrying the request if too many endpoints rejected it permanently.
+	if len(rs.endpoints)-permanent < rs.quorum {
+		return consumererror.NewPermanent(multierr.Append(permanentErr, transientErr))
+	}
+	return fmt.Errorf("replication quorum not reached, %d endpoints accepted the request, %d required: %w",
+		accepted, rs.quorum, transientErr)
+}
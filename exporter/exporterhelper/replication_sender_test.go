@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestReplicationSettings_Validate(t *testing.T) {
+	cfg := NewDefaultReplicationSettings()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Quorum = -1
+	assert.EqualError(t, cfg.Validate(), "quorum must not be negative")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	cfg.Enabled = false
+	assert.NoError(t, cfg.Validate())
+}
+
+// replicaEndpoint counts the traces exported to it concurrently with the other replicas,
+// and fails with the err if set, or for the first failures calls.
+type replicaEndpoint struct {
+	calls    atomic.Int64
+	err      error
+	failures int64
+}
+
+func (e *replicaEndpoint) push(context.Context, ptrace.Traces) error {
+	if e.calls.Add(1) <= e.failures {
+		return errors.New("replica is down")
+	}
+	return e.err
+}
+
+func TestReplicationSender(t *testing.T) {
+	transientErr := errors.New("transient error")
+	permanentErr := consumererror.NewPermanent(errors.New("bad data"))
+	tests := []struct {
+		name          string
+		quorum        int
+		errs          []error
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{
+			name: "all_accepted",
+			errs: []error{nil, nil, nil},
+		},
+		{
+			name:    "all_required",
+			errs:    []error{nil, nil, transientErr},
+			wantErr: true,
+		},
+		{
+			name:   "quorum_reached",
+			quorum: 2,
+			errs:   []error{transientErr, nil, nil},
+		},
+		{
+			name:    "quorum_not_reached",
+			quorum:  2,
+			errs:    []error{nil, permanentErr, transientErr},
+			wantErr: true,
+		},
+		{
+			name:          "quorum_unreachable",
+			quorum:        2,
+			errs:          []error{permanentErr, permanentErr, transientErr},
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name:   "quorum_greater_than_endpoints",
+			quorum: 5,
+			errs:   []error{nil, nil, nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultReplicationSettings()
+			cfg.Enabled = true
+			cfg.Quorum = tt.quorum
+			primary := &replicaEndpoint{err: tt.errs[0]}
+			replica1 := &replicaEndpoint{err: tt.errs[1]}
+			replica2 := &replicaEndpoint{err: tt.errs[2]}
+			be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+				WithTracesReplication(cfg, replica1.push, replica2.push))
+			require.NoError(t, err)
+
+			err = be.send(newTracesRequest(context.Background(), testdata.GenerateTraces(1), primary.push))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantPermanent, consumererror.IsPermanent(err))
+			// Every request is sent to all the endpoints.
+			for _, e := range []*replicaEndpoint{primary, replica1, replica2} {
+				assert.Equal(t, int64(1), e.calls.Load())
+			}
+		})
+	}
+}
+
+func TestReplicationSender_RetriesFailedEndpoints(t *testing.T) {
+	cfg := NewDefaultReplicationSettings()
+	cfg.Enabled = true
+	rCfg := NewDefaultRetrySettings()
+	rCfg.InitialInterval = time.Millisecond
+	primary := &replicaEndpoint{}
+	replica1 := &replicaEndpoint{failures: 2}
+	replica2 := &replicaEndpoint{}
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+		WithRetry(rCfg), WithTracesReplication(cfg, replica1.push, replica2.push))
+	require.NoError(t, err)
+
+	require.NoError(t, be.send(newTracesRequest(context.Background(), testdata.GenerateTraces(1), primary.push)))
+	// The retries are only sent to the endpoint that failed.
+	assert.Equal(t, int64(1), primary.calls.Load())
+	assert.Equal(t, int64(3), replica1.calls.Load())
+	assert.Equal(t, int64(1), replica2.calls.Load())
+
+	// The next requests are sent to all the endpoints again.
+	require.NoError(t, be.send(newTracesRequest(context.Background(), testdata.GenerateTraces(1), primary.push)))
+	assert.Equal(t, int64(2), primary.calls.Load())
+	assert.Equal(t, int64(4), replica1.calls.Load())
+	assert.Equal(t, int64(2), replica2.calls.Load())
+}
+
+func TestReplicationSender_Failover(t *testing.T) {
+	rCfg := NewDefaultReplicationSettings()
+	rCfg.Enabled = true
+	fCfg := NewDefaultFailoverSettings()
+	fCfg.Enabled = true
+	fCfg.FailureThreshold = 1
+	primary := &replicaEndpoint{err: errors.New("primary is down")}
+	secondary := &replicaEndpoint{}
+	replica := &replicaEndpoint{}
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+		WithTracesFailover(fCfg, secondary.push), WithTracesReplication(rCfg, replica.push))
+	require.NoError(t, err)
+
+	// The replicas receive the request sent to the active endpoint of the failover.
+	assert.Error(t, be.send(newTracesRequest(context.Background(), testdata.GenerateTraces(1), primary.push)))
+	assert.NoError(t, be.send(newTracesRequest(context.Background(), testdata.GenerateTraces(1), primary.push)))
+	assert.Equal(t, int64(1), primary.calls.Load())
+	assert.Equal(t, int64(1), secondary.calls.Load())
+	assert.Equal(t, int64(2), replica.calls.Load())
+}
+
+func TestReplicationSender_Disabled(t *testing.T) {
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", false, nil, nil, newNoopObsrepSender,
+		WithTracesReplication(NewDefaultReplicationSettings(), (&replicaEndpoint{}).push))
+	require.NoError(t, err)
+	assert.IsType(t, &baseRequestSender{}, be.replicationSender)
+}
@@ -5,6 +5,7 @@ package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporte
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 )
@@ -30,6 +31,62 @@ type RequestItemsCounter interface {
 	ItemsCount() int
 }
 
+// RequestBytesCounter is an optional interface that can be implemented by Request to provide the size of the request
+// in bytes as sent to the destination. It is used for limiting the rate of exported bytes.
+// If not implemented, the size of the request is considered to be 0.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type RequestBytesCounter interface {
+	// BytesCount returns the size of the request in bytes.
+	BytesCount() int
+}
+
+// RequestSplitter is an optional interface that can be implemented by Request to allow splitting it into smaller
+// requests when its size exceeds the limit set by WithMaxRequestSize. It requires RequestItemsCounter and
+// RequestBytesCounter to be implemented as well.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type RequestSplitter interface {
+	// Split splits the request into requests with at most maxItems items each.
+	Split(maxItems int) []Request
+}
+
+// RequestMarshaler is a function that can marshal a Request into bytes.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type RequestMarshaler func(req Request) ([]byte, error)
+
+// RequestUnmarshaler is a function that can unmarshal bytes into a Request.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type RequestUnmarshaler func(data []byte) (Request, error)
+
+// partialRequestError is an error that carries the part of a Request that failed to be exported.
+type partialRequestError struct {
+	err    error
+	failed Request
+}
+
+// NewPartialRequestError creates an error to be returned by Request.Export if only a part of the request
+// was accepted by the destination. The failed Request must contain only the data that was not accepted,
+// so only that part is retried or put back to the queue by the exporter helper.
+// This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func NewPartialRequestError(err error, failed Request) error {
+	return partialRequestError{
+		err:    err,
+		failed: failed,
+	}
+}
+
+func (e partialRequestError) Error() string {
+	return e.err.Error()
+}
+
+func (e partialRequestError) Unwrap() error {
+	return e.err
+}
+
 type request struct {
 	Request
 	baseRequest
@@ -44,10 +101,15 @@ func newRequest(ctx context.Context, req Request) *request {
 	}
 }
 
-func (req *request) OnError(_ error) internal.Request {
-	// Potentially we could introduce a new RequestError type that would represent partially succeeded request.
-	// In that case we should consider returning them back to the pipeline converted back to pdata in case if
+// OnError returns a request with the failed part of the original request if the error was created with
+// NewPartialRequestError, otherwise the original request is returned.
+func (req *request) OnError(err error) internal.Request {
+	// TODO: Consider returning the failed part back to the pipeline converted back to pdata in case if
 	// sending queue is disabled. We leave it as a future improvement if decided that it's needed.
+	var partialErr partialRequestError
+	if errors.As(err, &partialErr) && partialErr.failed != nil {
+		return newRequest(req.ctx, partialErr.failed)
+	}
 	return req
 }
 
@@ -59,3 +121,55 @@ func (req *request) Count() int {
 	}
 	return 0
 }
+
+func newInternalRequestMarshaler(marshaler RequestMarshaler) internal.RequestMarshaler {
+	return func(req internal.Request) ([]byte, error) {
+		return marshaler(req.(*request).Request)
+	}
+}
+
+func newInternalRequestUnmarshaler(unmarshaler RequestUnmarshaler) internal.RequestUnmarshaler {
+	return func(data []byte) (internal.Request, error) {
+		req, err := unmarshaler(data)
+		if err != nil {
+			return nil, err
+		}
+		return newRequest(context.Background(), req), nil
+	}
+}
+
+// BytesCount returns the size of the request in bytes. If the request does not implement RequestBytesCounter
+// then 0 is returned.
+func (req *request) BytesCount() int {
+	if counter, ok := req.Request.(RequestBytesCounter); ok {
+		return counter.BytesCount()
+	}
+	return 0
+}
+
+// split splits the request if it implements RequestSplitter, otherwise nil is returned.
+func (req *request) split(maxItems int) []internal.Request {
+	splitter, ok := req.Request.(RequestSplitter)
+	if !ok {
+		return nil
+	}
+	parts := splitter.Split(maxItems)
+	reqs := make([]internal.Request, 0, len(parts))
+	for _, part := range parts {
+		reqs = append(reqs, newRequest(req.ctx, part))
+	}
+	return reqs
+}
+
+// partialError returns the error as is, the failed parts cannot be converted back to the data of the request.
+func (req *request) partialError(err error, _ []internal.Request) error {
+	return err
+}
+
+// bytesCount returns the size of the request in bytes if known, otherwise 0.
+func bytesCount(req internal.Request) int {
+	if counter, ok := req.(RequestBytesCounter); ok {
+		return counter.BytesCount()
+	}
+	return 0
+}
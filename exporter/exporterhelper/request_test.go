@@ -5,6 +5,14 @@ package exporterhelper
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
 
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -42,3 +50,30 @@ func (c fakeRequestConverter) RequestFromTraces(_ context.Context, td ptrace.Tra
 func (c fakeRequestConverter) RequestFromLogs(_ context.Context, ld plog.Logs) (Request, error) {
 	return fakeRequest{items: ld.LogRecordCount(), err: c.requestError}, c.logsError
 }
+
+func fakeRequestMarshaler(req Request) ([]byte, error) {
+	return binary.LittleEndian.AppendUint64(nil, uint64(req.(fakeRequest).items)), nil
+}
+
+func fakeRequestUnmarshaler(data []byte) (Request, error) {
+	if len(data) != 8 {
+		return nil, errors.New("invalid fake request")
+	}
+	return fakeRequest{items: int(binary.LittleEndian.Uint64(data))}, nil
+}
+
+func TestRequest_OnError(t *testing.T) {
+	req := newRequest(context.Background(), fakeRequest{items: 5})
+	assert.Same(t, req, req.OnError(errors.New("transient error")))
+
+	err := NewPartialRequestError(errors.New("partial error"), fakeRequest{items: 2})
+	assert.EqualError(t, err, "partial error")
+	failed := req.OnError(err)
+	assert.Equal(t, 2, failed.Count())
+	assert.Equal(t, req.Context(), failed.Context())
+
+	// The error can be wrapped, e.g. as a permanent error.
+	permanentErr := consumererror.NewPermanent(NewPartialRequestError(errors.New("partial error"), fakeRequest{items: 3}))
+	require.True(t, consumererror.IsPermanent(permanentErr))
+	assert.Equal(t, 3, req.OnError(permanentErr).Count())
+}
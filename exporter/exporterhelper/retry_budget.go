@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// errRetryBudgetExhausted is returned for the requests not retried because the retry budget is exhausted.
+var errRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// retryBudgetBuckets is the number of buckets the window of the retry budget is divided into.
+const retryBudgetBuckets = 10
+
+// RetryBudgetSettings defines configuration for limiting the retries to a share of the requests sent for the first
+// time, so during a prolonged outage of the destination the retries do not multiply the load and crowd out new data.
+type RetryBudgetSettings struct {
+	// Enabled indicates whether the retry budget is enabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Ratio is the maximum number of retries per request sent for the first time, e.g. 0.2 allows
+	// retries to add 20% to the traffic.
+	Ratio float64 `mapstructure:"ratio"`
+	// Window is the rolling window the retries and the requests are counted in.
+	Window time.Duration `mapstructure:"window"`
+	// MinRetries is the number of retries always allowed within the window, so the requests can be retried
+	// when the traffic is low.
+	MinRetries int `mapstructure:"min_retries"`
+}
+
+// NewDefaultRetryBudgetSettings returns the default settings for RetryBudgetSettings.
+func NewDefaultRetryBudgetSettings() RetryBudgetSettings {
+	return RetryBudgetSettings{
+		Enabled:    false,
+		Ratio:      0.2,
+		Window:     10 * time.Second,
+		MinRetries: 10,
+	}
+}
+
+// Validate checks if the RetryBudgetSettings configuration is valid
+func (cfg *RetryBudgetSettings) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Ratio <= 0 {
+		return errors.New("retry budget ratio must be positive")
+	}
+	if cfg.Window <= 0 {
+		return errors.New("retry budget window must be positive")
+	}
+	if cfg.MinRetries < 0 {
+		return errors.New("retry budget min retries must not be negative")
+	}
+	return nil
+}
+
+// retryBudgetBucket counts the requests and the retries within a part of the window.
+type retryBudgetBucket struct {
+	requests int64
+	retries  int64
+}
+
+// retryBudget counts the requests and the retries in a rolling window divided into buckets.
+type retryBudget struct {
+	mu             sync.Mutex
+	ratio          float64
+	minRetries     int64
+	bucketDuration time.Duration
+	buckets        [retryBudgetBuckets]retryBudgetBucket
+	// current is the index of the bucket started at currentStart.
+	current      int
+	currentStart time.Time
+	now          func() time.Time
+}
+
+func newRetryBudget(cfg RetryBudgetSettings) *retryBudget {
+	bucketDuration := cfg.Window / retryBudgetBuckets
+	if bucketDuration <= 0 {
+		bucketDuration = 1
+	}
+	return &retryBudget{
+		ratio:          cfg.Ratio,
+		minRetries:     int64(cfg.MinRetries),
+		bucketDuration: bucketDuration,
+		currentStart:   time.Now(),
+		now:            time.Now,
+	}
+}
+
+// onRequest counts a request sent for the first time.
+func (b *retryBudget) onRequest() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	b.buckets[b.current].requests++
+}
+
+// tryRetry counts a retry and returns true if it fits in the budget.
+func (b *retryBudget) tryRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	var requests, retries int64
+	for _, bucket := range b.buckets {
+		requests += bucket.requests
+		retries += bucket.retries
+	}
+	if retries >= b.minRetries && float64(retries+1) > b.ratio*float64(requests) {
+		return false
+	}
+	b.buckets[b.current].retries++
+	return true
+}
+
+// advance moves to the bucket of the current time, clearing the buckets that left the window.
+func (b *retryBudget) advance() {
+	elapsed := b.now().Sub(b.currentStart)
+	if elapsed < b.bucketDuration {
+		return
+	}
+	n := int(elapsed / b.bucketDuration)
+	if n > retryBudgetBuckets {
+		n = retryBudgetBuckets
+	}
+	for i := 0; i < n; i++ {
+		b.current = (b.current + 1) % retryBudgetBuckets
+		b.buckets[b.current] = retryBudgetBucket{}
+	}
+	b.currentStart = b.currentStart.Add(elapsed / b.bucketDuration * b.bucketDuration)
+}
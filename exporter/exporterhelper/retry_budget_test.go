@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBudgetSettings_Validate(t *testing.T) {
+	cfg := NewDefaultRetryBudgetSettings()
+	cfg.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Ratio = 0
+	assert.EqualError(t, cfg.Validate(), "retry budget ratio must be positive")
+	cfg.Ratio = 0.1
+
+	cfg.Window = 0
+	assert.EqualError(t, cfg.Validate(), "retry budget window must be positive")
+	cfg.Window = time.Second
+
+	cfg.MinRetries = -1
+	assert.EqualError(t, cfg.Validate(), "retry budget min retries must not be negative")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	cfg.Enabled = false
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestRetryBudget(t *testing.T) {
+	now := time.Now()
+	b := newRetryBudget(RetryBudgetSettings{Ratio: 0.2, Window: 10 * time.Second, MinRetries: 2})
+	b.now = func() time.Time { return now }
+	b.currentStart = now
+
+	// The min retries are allowed without any requests.
+	assert.True(t, b.tryRetry())
+	assert.True(t, b.tryRetry())
+	assert.False(t, b.tryRetry())
+
+	// Every 5 requests allow one more retry.
+	for i := 0; i < 15; i++ {
+		b.onRequest()
+	}
+	assert.True(t, b.tryRetry())
+	assert.False(t, b.tryRetry())
+
+	// The retries and the requests are forgotten once they leave the window.
+	now = now.Add(5 * time.Second)
+	for i := 0; i < 5; i++ {
+		b.onRequest()
+	}
+	assert.True(t, b.tryRetry())
+	assert.False(t, b.tryRetry())
+	// Only the last 5 requests and the last retry are in the window, the min retries allow one more retry.
+	now = now.Add(6 * time.Second)
+	assert.True(t, b.tryRetry())
+	assert.False(t, b.tryRetry())
+	now = now.Add(time.Minute)
+	assert.True(t, b.tryRetry())
+}
+
+func TestRetrySender_RetryBudget(t *testing.T) {
+	errSend := errors.New("send error")
+	rCfg := NewDefaultRetrySettings()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = 0
+	rCfg.Budget.Enabled = true
+	rCfg.Budget.Ratio = 1
+	rCfg.Budget.MinRetries = 0
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithRetry(rCfg))
+	require.NoError(t, err)
+	rs := be.retrySender.(*retrySender)
+	rs.setNextSender(&errorSender{err: errSend})
+	t.Cleanup(func() {
+		assert.NoError(t, rs.Shutdown(context.Background()))
+	})
+
+	// The request is retried once, then the budget is exhausted.
+	err = rs.send(newMockRequest(context.Background(), 2, nil))
+	require.ErrorIs(t, err, errSend)
+	require.ErrorIs(t, err, errRetryBudgetExhausted)
+}
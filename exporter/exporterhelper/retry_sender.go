@@ -7,6 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -38,6 +41,49 @@ type RetrySettings struct {
 	// MaxElapsedTime is the maximum amount of time (including retries) spent trying to send a request/batch.
 	// Once this value is reached, the data is discarded.
 	MaxElapsedTime time.Duration `mapstructure:"max_elapsed_time"`
+	// PerErrorClass overrides the backoff settings for the errors of a class, e.g. ErrorClassThrottled.
+	// The class of an error is provided by the classifier set with WithErrorClassifier, the throttling
+	// and the network errors are classified by default.
+	PerErrorClass map[string]RetryClassSettings `mapstructure:"per_error_class"`
+	// Budget limits the retries to a share of the requests sent for the first time. The requests not retried
+	// because the budget is exhausted are handled as if the MaxElapsedTime expired.
+	Budget RetryBudgetSettings `mapstructure:"budget"`
+}
+
+// RetryClassSettings defines the backoff settings overridden for a class of errors.
+// Zero values mean the respective values of RetrySettings are used.
+type RetryClassSettings struct {
+	// Multiplier is the value multiplied by the backoff interval bounds
+	Multiplier float64 `mapstructure:"multiplier"`
+	// MaxInterval is the upper bound on backoff interval.
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+	// MaxElapsedTime is the maximum amount of time (including retries) spent trying to send a request/batch.
+	MaxElapsedTime time.Duration `mapstructure:"max_elapsed_time"`
+}
+
+const (
+	// ErrorClassNetwork is the class of the errors caused by a network failure, e.g. a refused or reset connection.
+	ErrorClassNetwork = "network"
+	// ErrorClassServer is the class of the errors caused by a failure of the destination, e.g. HTTP 5xx responses.
+	ErrorClassServer = "server"
+	// ErrorClassThrottled is the class of the errors returned when the destination asks the exporter to slow down.
+	ErrorClassThrottled = "throttled"
+)
+
+// ErrorClassifier returns the class of an error used to select the retry settings, e.g. ErrorClassServer.
+// An empty string means the error is classified by the default classifier.
+type ErrorClassifier func(err error) string
+
+// defaultErrorClassifier classifies the throttling and the network errors.
+func defaultErrorClassifier(err error) string {
+	if errors.As(err, &throttleRetry{}) {
+		return ErrorClassThrottled
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrorClassNetwork
+	}
+	return ""
 }
 
 // NewDefaultRetrySettings returns the default settings for RetrySettings.
@@ -49,9 +95,13 @@ func NewDefaultRetrySettings() RetrySettings {
 		Multiplier:          backoff.DefaultMultiplier,
 		MaxInterval:         30 * time.Second,
 		MaxElapsedTime:      5 * time.Minute,
+		Budget:              NewDefaultRetryBudgetSettings(),
 	}
 }
 
+// errInterruptedByShutdown is returned for the requests which retrying was interrupted by the shutdown.
+var errInterruptedByShutdown = errors.New("interrupted due to shutdown")
+
 // TODO: Clean this by forcing all exporters to return an internal error type that always include the information about retries.
 type throttleRetry struct {
 	err   error
@@ -74,6 +124,80 @@ func NewThrottleRetry(err error, delay time.Duration) error {
 	}
 }
 
+// throttleGate is shared between the senders of an exporter to pause sending new requests
+// while the destination is throttling the exporter or is not ready.
+type throttleGate struct {
+	// until is the time in unix nanoseconds until which sending is paused.
+	until atomic.Int64
+
+	mu sync.Mutex
+	// resumeCh is closed when sending is resumed, it is nil if sending is not paused with pause.
+	resumeCh chan struct{}
+}
+
+// throttle pauses sending for the given delay, unless it is already paused for longer.
+func (g *throttleGate) throttle(delay time.Duration) {
+	until := time.Now().Add(delay).UnixNano()
+	for {
+		current := g.until.Load()
+		if current >= until || g.until.CompareAndSwap(current, until) {
+			return
+		}
+	}
+}
+
+// remaining returns the time until sending is not paused anymore by throttle, zero if it is not.
+func (g *throttleGate) remaining() time.Duration {
+	if delay := time.Until(time.Unix(0, g.until.Load())); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// pause pauses sending until resume is called.
+func (g *throttleGate) pause() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.resumeCh == nil {
+		g.resumeCh = make(chan struct{})
+	}
+}
+
+// resume resumes sending paused with pause.
+func (g *throttleGate) resume() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.resumeCh != nil {
+		close(g.resumeCh)
+		g.resumeCh = nil
+	}
+}
+
+// wait blocks until sending is not paused anymore or the stopCh is closed.
+func (g *throttleGate) wait(stopCh <-chan struct{}) {
+	g.mu.Lock()
+	resumeCh := g.resumeCh
+	g.mu.Unlock()
+	if resumeCh != nil {
+		select {
+		case <-resumeCh:
+		case <-stopCh:
+			return
+		}
+	}
+
+	delay := time.Until(time.Unix(0, g.until.Load()))
+	if delay <= 0 {
+		return
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-stopCh:
+	}
+}
+
 type onRequestHandlingFinishedFunc func(*zap.Logger, internal.Request, error) error
 
 type retrySender struct {
@@ -82,21 +206,35 @@ type retrySender struct {
 	cfg                RetrySettings
 	stopCh             chan struct{}
 	logger             *zap.Logger
+	obsrep             *ObsReport
+	throttle           *throttleGate
+	classifier         ErrorClassifier
 	onTemporaryFailure onRequestHandlingFinishedFunc
+	// budget limits the number of retries, nil if the retry budget is disabled.
+	budget *retryBudget
 }
 
-func newRetrySender(config RetrySettings, set exporter.CreateSettings, onTemporaryFailure onRequestHandlingFinishedFunc) *retrySender {
+func newRetrySender(config RetrySettings, set exporter.CreateSettings, obsrep *ObsReport, throttle *throttleGate,
+	classifier ErrorClassifier, onTemporaryFailure onRequestHandlingFinishedFunc) *retrySender {
 	if onTemporaryFailure == nil {
 		onTemporaryFailure = func(logger *zap.Logger, req internal.Request, err error) error {
 			return err
 		}
 	}
+	var budget *retryBudget
+	if config.Budget.Enabled {
+		budget = newRetryBudget(config.Budget)
+	}
 	return &retrySender{
 		traceAttribute:     attribute.String(obsmetrics.ExporterKey, set.ID.String()),
 		cfg:                config,
 		stopCh:             make(chan struct{}),
 		logger:             set.Logger,
+		obsrep:             obsrep,
+		throttle:           throttle,
+		classifier:         classifier,
 		onTemporaryFailure: onTemporaryFailure,
+		budget:             budget,
 	}
 }
 
@@ -107,26 +245,32 @@ func (rs *retrySender) Shutdown(context.Context) error {
 
 // send implements the requestSender interface
 func (rs *retrySender) send(req internal.Request) error {
-	// Do not use NewExponentialBackOff since it calls Reset and the code here must
-	// call Reset after changing the InitialInterval (this saves an unnecessary call to Now).
-	expBackoff := backoff.ExponentialBackOff{
-		InitialInterval:     rs.cfg.InitialInterval,
-		RandomizationFactor: rs.cfg.RandomizationFactor,
-		Multiplier:          rs.cfg.Multiplier,
-		MaxInterval:         rs.cfg.MaxInterval,
-		MaxElapsedTime:      rs.cfg.MaxElapsedTime,
-		Stop:                backoff.Stop,
-		Clock:               backoff.SystemClock,
+	// Every class of errors has its own backoff, all of them are started at the same time,
+	// so the max elapsed time is measured from the first attempt.
+	expBackoffs := make(map[string]*backoff.ExponentialBackOff, len(rs.cfg.PerErrorClass)+1)
+	expBackoffs[""] = rs.newExpBackOff(RetryClassSettings{})
+	for class, classCfg := range rs.cfg.PerErrorClass {
+		expBackoffs[class] = rs.newExpBackOff(classCfg)
 	}
-	expBackoff.Reset()
 	span := trace.SpanFromContext(req.Context())
 	retryNum := int64(0)
+	// The retries of a request are recorded once it is sent or dropped.
+	attempts := int64(0)
+	var totalBackoffDelay time.Duration
+	gaveUp := false
+	defer func(ctx context.Context) {
+		rs.obsrep.recordRetries(ctx, attempts, totalBackoffDelay, gaveUp)
+	}(req.Context())
+	if rs.budget != nil {
+		rs.budget.onRequest()
+	}
 	for {
 		span.AddEvent(
 			"Sending request.",
 			trace.WithAttributes(rs.traceAttribute, attribute.Int64("retry_num", retryNum)))
 
 		err := rs.nextSender.send(req)
+		attempts++
 		if err == nil {
 			return nil
 		}
@@ -145,17 +289,29 @@ func (rs *retrySender) send(req internal.Request) error {
 		// failed to process.
 		req = req.OnError(err)
 
+		expBackoff, ok := expBackoffs[rs.classify(err)]
+		if !ok {
+			expBackoff = expBackoffs[""]
+		}
 		backoffDelay := expBackoff.NextBackOff()
 		if backoffDelay == backoff.Stop {
 			// throw away the batch
+			gaveUp = true
 			err = fmt.Errorf("max elapsed time expired %w", err)
 			return rs.onTemporaryFailure(rs.logger, req, err)
 		}
+		if rs.budget != nil && !rs.budget.tryRetry() {
+			gaveUp = true
+			err = fmt.Errorf("%w %w", errRetryBudgetExhausted, err)
+			return rs.onTemporaryFailure(rs.logger, req, err)
+		}
 
+		// The destination knows best when it is ready to accept data again, so the delay provided by it
+		// replaces the exponential backoff, and the other requests are paused for the same amount of time.
 		throttleErr := throttleRetry{}
-		isThrottle := errors.As(err, &throttleErr)
-		if isThrottle {
-			backoffDelay = max(backoffDelay, throttleErr.delay)
+		if errors.As(err, &throttleErr) && throttleErr.delay > 0 {
+			backoffDelay = throttleErr.delay
+			rs.throttle.throttle(backoffDelay)
 		}
 
 		backoffDelayStr := backoffDelay.String()
@@ -177,16 +333,45 @@ func (rs *retrySender) send(req internal.Request) error {
 		case <-req.Context().Done():
 			return fmt.Errorf("request is cancelled or timed out %w", err)
 		case <-rs.stopCh:
-			return rs.onTemporaryFailure(rs.logger, req, fmt.Errorf("interrupted due to shutdown %w", err))
+			return rs.onTemporaryFailure(rs.logger, req, fmt.Errorf("%w %w", errInterruptedByShutdown, err))
 		case <-time.After(backoffDelay):
+			totalBackoffDelay += backoffDelay
 		}
 	}
 }
 
-// max returns the larger of x or y.
-func max(x, y time.Duration) time.Duration {
-	if x < y {
-		return y
+// newExpBackOff creates the backoff using the retry settings overridden by the given class settings.
+func (rs *retrySender) newExpBackOff(classCfg RetryClassSettings) *backoff.ExponentialBackOff {
+	// Do not use NewExponentialBackOff since it calls Reset and the code here must
+	// call Reset after changing the InitialInterval (this saves an unnecessary call to Now).
+	expBackoff := &backoff.ExponentialBackOff{
+		InitialInterval:     rs.cfg.InitialInterval,
+		RandomizationFactor: rs.cfg.RandomizationFactor,
+		Multiplier:          rs.cfg.Multiplier,
+		MaxInterval:         rs.cfg.MaxInterval,
+		MaxElapsedTime:      rs.cfg.MaxElapsedTime,
+		Stop:                backoff.Stop,
+		Clock:               backoff.SystemClock,
+	}
+	if classCfg.Multiplier != 0 {
+		expBackoff.Multiplier = classCfg.Multiplier
+	}
+	if classCfg.MaxInterval != 0 {
+		expBackoff.MaxInterval = classCfg.MaxInterval
+	}
+	if classCfg.MaxElapsedTime != 0 {
+		expBackoff.MaxElapsedTime = classCfg.MaxElapsedTime
+	}
+	expBackoff.Reset()
+	return expBackoff
+}
+
+// classify returns the class of the error, using the classifier of the exporter first.
+func (rs *retrySender) classify(err error) string {
+	if rs.classifier != nil {
+		if class := rs.classifier(err); class != "" {
+			return class
+		}
 	}
-	return x
+	return defaultErrorClassifier(err)
 }
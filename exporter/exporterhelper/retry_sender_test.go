@@ -7,8 +7,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
@@ -21,9 +23,11 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 )
 
 func mockRequestUnmarshaler(mr *mockRequest) internal.RequestUnmarshaler {
@@ -112,6 +116,36 @@ func TestQueuedRetry_OnError(t *testing.T) {
 	ocs.checkDroppedItemsCount(t, 0)
 }
 
+func TestQueuedRetry_OnPartialRequestError(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	rCfg := NewDefaultRetrySettings()
+	rCfg.InitialInterval = 0
+	be, err := newBaseExporter(defaultSettings, "", true, nil, nil, newObservabilityConsumerSender, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	exported := &atomic.Int64{}
+	failed := &partialFailingRequest{items: 1, exported: exported}
+	req := &partialFailingRequest{items: 3, exported: exported, failed: failed}
+	ocs := be.obsrepSender.(*observabilityConsumerSender)
+	ocs.run(func() {
+		// This is asynchronous so it should just enqueue, no errors expected.
+		require.NoError(t, be.send(newRequest(context.Background(), req)))
+	})
+	ocs.awaitAsyncProcessing()
+
+	// Only the failed part of the request is sent again.
+	assert.EqualValues(t, 1, req.calls.Load())
+	assert.EqualValues(t, 1, failed.calls.Load())
+	assert.EqualValues(t, 3, exported.Load())
+	ocs.checkSendItemsCount(t, 3)
+	ocs.checkDroppedItemsCount(t, 0)
+}
+
 func TestQueuedRetry_MaxElapsedTime(t *testing.T) {
 	qCfg := NewDefaultQueueSettings()
 	qCfg.NumConsumers = 1
@@ -190,6 +224,152 @@ func TestQueuedRetry_ThrottleError(t *testing.T) {
 	require.Zero(t, be.queueSender.(*queueSender).queue.Size())
 }
 
+func TestQueuedRetry_ThrottleErrorReplacesBackoff(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 2
+	rCfg := NewDefaultRetrySettings()
+	rCfg.InitialInterval = time.Minute
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newObservabilityConsumerSender, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	ocs := be.obsrepSender.(*observabilityConsumerSender)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	retry := NewThrottleRetry(errors.New("throttle error"), 100*time.Millisecond)
+	mockR := newMockRequest(context.Background(), 2, retry)
+	start := time.Now()
+	ocs.run(func() {
+		// This is asynchronous so it should just enqueue, no errors expected.
+		require.NoError(t, be.send(mockR))
+	})
+	mockR.checkNumRequests(t, 1)
+
+	// The second request must wait for the throttling to end before being sent by the other consumer.
+	secondR := newMockRequest(context.Background(), 3, nil)
+	ocs.run(func() {
+		require.NoError(t, be.send(secondR))
+	})
+	ocs.awaitAsyncProcessing()
+
+	// The initial backoff is 1 minute, but the throttle delay is used instead.
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	mockR.checkNumRequests(t, 2)
+	secondR.checkNumRequests(t, 1)
+	ocs.checkSendItemsCount(t, 5)
+	ocs.checkDroppedItemsCount(t, 0)
+}
+
+func TestThrottleGate(t *testing.T) {
+	gate := &throttleGate{}
+	start := time.Now()
+	gate.wait(nil)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	gate.throttle(100 * time.Millisecond)
+	// A shorter delay must not shorten the pause.
+	gate.throttle(time.Millisecond)
+	gate.wait(nil)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// Closing the stop channel interrupts the pause.
+	gate.throttle(time.Minute)
+	stopCh := make(chan struct{})
+	close(stopCh)
+	gate.wait(stopCh)
+	assert.Less(t, time.Since(start), time.Minute)
+}
+
+func TestRetrySender_PerErrorClass(t *testing.T) {
+	errServer := errors.New("server error")
+	rCfg := NewDefaultRetrySettings()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = 0 // retry infinitely unless overridden
+	rCfg.PerErrorClass = map[string]RetryClassSettings{
+		ErrorClassServer: {MaxInterval: time.Millisecond, MaxElapsedTime: 50 * time.Millisecond},
+	}
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender, WithRetry(rCfg),
+		WithErrorClassifier(func(err error) string {
+			if errors.Is(err, errServer) {
+				return ErrorClassServer
+			}
+			return ""
+		}))
+	require.NoError(t, err)
+	rs := be.retrySender.(*retrySender)
+	rs.setNextSender(&errorSender{err: errServer})
+	t.Cleanup(func() {
+		assert.NoError(t, rs.Shutdown(context.Background()))
+	})
+
+	// The server errors give up after the overridden max elapsed time.
+	start := time.Now()
+	err = rs.send(newMockRequest(context.Background(), 2, nil))
+	require.ErrorIs(t, err, errServer)
+	assert.ErrorContains(t, err, "max elapsed time expired")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestWithErrorClassifier(t *testing.T) {
+	classifier := func(error) string { return ErrorClassServer }
+
+	// The classifier is set regardless of the order of the options.
+	be, err := newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender,
+		WithErrorClassifier(classifier), WithRetry(NewDefaultRetrySettings()))
+	require.NoError(t, err)
+	assert.Equal(t, ErrorClassServer, be.retrySender.(*retrySender).classify(errors.New("some error")))
+
+	be, err = newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender,
+		WithRetry(NewDefaultRetrySettings()), WithErrorClassifier(classifier))
+	require.NoError(t, err)
+	assert.Equal(t, ErrorClassServer, be.retrySender.(*retrySender).classify(errors.New("some error")))
+
+	// The errors not classified by the exporter are classified by the default classifier.
+	be, err = newBaseExporter(defaultSettings, "", false, nil, nil, newNoopObsrepSender,
+		WithRetry(NewDefaultRetrySettings()), WithErrorClassifier(func(error) string { return "" }))
+	require.NoError(t, err)
+	assert.Equal(t, ErrorClassThrottled, be.retrySender.(*retrySender).classify(NewThrottleRetry(errors.New("slow down"), time.Second)))
+}
+
+func TestDefaultErrorClassifier(t *testing.T) {
+	assert.Equal(t, ErrorClassThrottled, defaultErrorClassifier(NewThrottleRetry(errors.New("slow down"), time.Second)))
+	assert.Equal(t, ErrorClassNetwork, defaultErrorClassifier(fmt.Errorf("failed: %w",
+		&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})))
+	assert.Equal(t, "", defaultErrorClassifier(errors.New("unknown error")))
+}
+
+func TestRetrySender_RecordsRetries(t *testing.T) {
+	testTelemetry(t, defaultID, func(t *testing.T, tt obsreporttest.TestTelemetry, _ bool) {
+		set := exporter.CreateSettings{ID: defaultID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()}
+		rCfg := NewDefaultRetrySettings()
+		rCfg.InitialInterval = 0
+		be, err := newBaseExporter(set, "", false, nil, nil, newNoopObsrepSender, WithRetry(rCfg))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			assert.NoError(t, be.Shutdown(context.Background()))
+		})
+
+		// The request succeeds on the second attempt.
+		mockR := newMockRequest(context.Background(), 2, errors.New("transient error"))
+		require.NoError(t, be.send(mockR))
+		mockR.checkNumRequests(t, 2)
+
+		// The max elapsed time is shorter than the first backoff, so the request is dropped after the first attempt.
+		rCfg.InitialInterval = time.Millisecond
+		rCfg.MaxElapsedTime = time.Nanosecond
+		giveUpBe, err := newBaseExporter(set, "", false, nil, nil, newNoopObsrepSender, WithRetry(rCfg))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			assert.NoError(t, giveUpBe.Shutdown(context.Background()))
+		})
+		assert.ErrorContains(t, giveUpBe.send(newErrorRequest(context.Background())), "max elapsed time expired")
+
+		require.NoError(t, tt.CheckExporterRetries(2, 3, 1))
+	})
+}
+
 func TestQueuedRetry_RetryOnError(t *testing.T) {
 	qCfg := NewDefaultQueueSettings()
 	qCfg.NumConsumers = 1
@@ -277,6 +457,28 @@ func newErrorRequest(ctx context.Context) internal.Request {
 	}
 }
 
+// partialFailingRequest accepts all the items except the ones in the failed request.
+type partialFailingRequest struct {
+	items    int
+	failed   *partialFailingRequest
+	calls    atomic.Int64
+	exported *atomic.Int64
+}
+
+func (r *partialFailingRequest) Export(_ context.Context) error {
+	r.calls.Add(1)
+	if r.failed == nil {
+		r.exported.Add(int64(r.items))
+		return nil
+	}
+	r.exported.Add(int64(r.items - r.failed.items))
+	return NewPartialRequestError(errors.New("some items were rejected"), r.failed)
+}
+
+func (r *partialFailingRequest) ItemsCount() int {
+	return r.items
+}
+
 type mockRequest struct {
 	baseRequest
 	cnt          int
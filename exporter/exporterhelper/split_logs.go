@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// splitLogs removes logrecords from the input data and returns a new data of the specified size.
+func splitLogs(size int, src plog.Logs) plog.Logs {
+	if src.LogRecordCount() <= size {
+		return src
+	}
+	totalCopiedLogRecords := 0
+	dest := plog.NewLogs()
+
+	src.ResourceLogs().RemoveIf(func(srcRl plog.ResourceLogs) bool {
+		// If we are done skip everything else.
+		if totalCopiedLogRecords == size {
+			return false
+		}
+
+		// If it fully fits
+		srcRlLRC := resourceLRC(srcRl)
+		if (totalCopiedLogRecords + srcRlLRC) <= size {
+			totalCopiedLogRecords += srcRlLRC
+			srcRl.MoveTo(dest.ResourceLogs().AppendEmpty())
+			return true
+		}
+
+		destRl := dest.ResourceLogs().AppendEmpty()
+		srcRl.Resource().CopyTo(destRl.Resource())
+		srcRl.ScopeLogs().RemoveIf(func(srcIll plog.ScopeLogs) bool {
+			// If we are done skip everything else.
+			if totalCopiedLogRecords == size {
+				return false
+			}
+
+			// If possible to move all metrics do that.
+			srcIllLRC := srcIll.LogRecords().Len()
+			if size >= srcIllLRC+totalCopiedLogRecords {
+				totalCopiedLogRecords += srcIllLRC
+				srcIll.MoveTo(destRl.ScopeLogs().AppendEmpty())
+				return true
+			}
+
+			destIll := destRl.ScopeLogs().AppendEmpty()
+			srcIll.Scope().CopyTo(destIll.Scope())
+			srcIll.LogRecords().RemoveIf(func(srcMetric plog.LogRecord) bool {
+				// If we are done skip everything else.
+				if totalCopiedLogRecords == size {
+					return false
+				}
+				srcMetric.MoveTo(destIll.LogRecords().AppendEmpty())
+				totalCopiedLogRecords++
+				return true
+			})
+			return false
+		})
+		return srcRl.ScopeLogs().Len() == 0
+	})
+
+	return dest
+}
+
+// resourceLRC calculates the total number of log records in the plog.ResourceLogs.
+func resourceLRC(rs plog.ResourceLogs) (count int) {
+	for k := 0; k < rs.ScopeLogs().Len(); k++ {
+		count += rs.ScopeLogs().At(k).LogRecords().Len()
+	}
+	return
+}
+
+// split splits a copy of the request data into requests with at most maxItems log records each.
+// The request data is not modified, since it can be shared with other consumers.
+func (req *logsRequest) split(maxItems int) []internal.Request {
+	ld := plog.NewLogs()
+	req.ld.CopyTo(ld)
+	var reqs []internal.Request
+	for ld.LogRecordCount() > maxItems {
+		reqs = append(reqs, newLogsRequest(req.ctx, splitLogs(maxItems, ld), req.pusher))
+	}
+	return append(reqs, newLogsRequest(req.ctx, ld, req.pusher))
+}
+
+// partialError returns the error with a copy of the log records of the failed parts of the request.
+func (req *logsRequest) partialError(err error, failed []internal.Request) error {
+	ld := plog.NewLogs()
+	for _, part := range failed {
+		rls := part.(*logsRequest).ld.ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			rls.At(i).CopyTo(ld.ResourceLogs().AppendEmpty())
+		}
+	}
+	return consumererror.NewLogs(err, ld)
+}
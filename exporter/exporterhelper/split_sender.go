@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// splittableRequest is implemented by the requests that can be split into smaller ones.
+type splittableRequest interface {
+	// split splits the request into requests with at most maxItems items each.
+	// Returns nil if the request cannot be split.
+	split(maxItems int) []internal.Request
+	// partialError returns the error of the failed parts of the request, carrying only their data if possible.
+	partialError(err error, failed []internal.Request) error
+}
+
+// WithMaxRequestSize splits the requests larger than maxBytes into smaller ones before they are queued and sent,
+// so they are not rejected by a destination that limits the size of the requests. The size of a request is
+// estimated by its size in the OTLP protobuf encoding, or by RequestBytesCounter for the new exporter helpers,
+// which also have to implement RequestSplitter. A request with a single item larger than maxBytes is sent as is.
+// If only some of the parts fail, the exporters created with NewTracesExporter, NewMetricsExporter and
+// NewLogsExporter return a consumererror.Traces, Metrics or Logs error with only the data of the failed parts,
+// so the data already sent is not sent again by the callers retrying the failed data.
+// Zero or negative maxBytes means no limit.
+func WithMaxRequestSize(maxBytes int) Option {
+	return func(o *baseExporter) {
+		if maxBytes <= 0 {
+			return
+		}
+		o.splitSender = &splitSender{
+			maxBytes: maxBytes,
+			logger:   o.set.Logger,
+		}
+	}
+}
+
+// splitSender is a requestSender that splits the requests exceeding the maximum size.
+type splitSender struct {
+	baseRequestSender
+	maxBytes int
+	logger   *zap.Logger
+}
+
+// send implements the requestSender interface
+func (ss *splitSender) send(req internal.Request) error {
+	parts := ss.split(req)
+	if parts == nil {
+		return ss.nextSender.send(req)
+	}
+
+	ss.logger.Debug("Splitting the request exceeding the maximum size.",
+		zap.Int("items", req.Count()),
+		zap.Int("parts", len(parts)),
+	)
+	var errs error
+	var failed []internal.Request
+	for _, part := range parts {
+		// The parts are split again if they are still too large.
+		if err := ss.send(part); err != nil {
+			errs = multierr.Append(errs, err)
+			failed = append(failed, part.OnError(err))
+		}
+	}
+	if len(failed) == 0 || len(failed) == len(parts) {
+		return errs
+	}
+	return req.(splittableRequest).partialError(errs, failed)
+}
+
+// split returns the parts of the request if it exceeds the maximum size and can be split, nil otherwise.
+func (ss *splitSender) split(req internal.Request) []internal.Request {
+	size := bytesCount(req)
+	count := req.Count()
+	if size <= ss.maxBytes || count <= 1 {
+		return nil
+	}
+	splittable, ok := req.(splittableRequest)
+	if !ok {
+		return nil
+	}
+	// Assume the items are of similar size.
+	numParts := (size + ss.maxBytes - 1) / ss.maxBytes
+	parts := splittable.split((count + numParts - 1) / numParts)
+	if len(parts) <= 1 {
+		return nil
+	}
+	return parts
+}
@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestTracesExporter_WithMaxRequestSize(t *testing.T) {
+	td := testdata.GenerateTraces(20)
+	maxBytes := tracesMarshaler.TracesSize(td) / 3
+
+	var mu sync.Mutex
+	var sizes []int
+	spans := 0
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeTracesExporterConfig,
+		func(_ context.Context, td ptrace.Traces) error {
+			mu.Lock()
+			defer mu.Unlock()
+			sizes = append(sizes, tracesMarshaler.TracesSize(td))
+			spans += td.SpanCount()
+			return nil
+		}, WithQueue(qCfg), WithMaxRequestSize(maxBytes))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, te.Shutdown(context.Background())) })
+
+	require.NoError(t, te.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, 20, spans)
+	assert.GreaterOrEqual(t, len(sizes), 3)
+	for _, size := range sizes {
+		assert.LessOrEqual(t, size, maxBytes)
+	}
+	// The original data is not modified.
+	assert.Equal(t, testdata.GenerateTraces(20), td)
+}
+
+func TestTracesExporter_WithMaxRequestSizePartialError(t *testing.T) {
+	td := testdata.GenerateTraces(20)
+	maxBytes := tracesMarshaler.TracesSize(td) / 3
+
+	exportErr := errors.New("export failed")
+	var failed []ptrace.Traces
+	calls := 0
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	te, err := NewTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeTracesExporterConfig,
+		func(_ context.Context, td ptrace.Traces) error {
+			calls++
+			if calls%2 == 0 {
+				failed = append(failed, td)
+				return exportErr
+			}
+			return nil
+		}, WithQueue(qCfg), WithMaxRequestSize(maxBytes))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, te.Shutdown(context.Background())) })
+
+	// Only the data of the failed parts is returned, to be retried by the caller.
+	err = te.ConsumeTraces(context.Background(), td)
+	assert.ErrorIs(t, err, exportErr)
+	var tracesErr consumererror.Traces
+	require.ErrorAs(t, err, &tracesErr)
+	require.NotEmpty(t, failed)
+	wantSpans := 0
+	for _, part := range failed {
+		wantSpans += part.SpanCount()
+	}
+	assert.Equal(t, wantSpans, tracesErr.Data().SpanCount())
+	assert.Less(t, wantSpans, td.SpanCount())
+
+	// If all the parts fail, the error is returned as is.
+	te, err = NewTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeTracesExporterConfig,
+		func(context.Context, ptrace.Traces) error {
+			return exportErr
+		}, WithQueue(qCfg), WithMaxRequestSize(maxBytes))
+	require.NoError(t, err)
+	err = te.ConsumeTraces(context.Background(), td)
+	assert.ErrorIs(t, err, exportErr)
+	assert.False(t, errors.As(err, &tracesErr))
+}
+
+func TestMetricsExporter_WithMaxRequestSize(t *testing.T) {
+	md := testdata.GenerateMetrics(20)
+	maxBytes := metricsMarshaler.MetricsSize(md) / 4
+
+	var sizes []int
+	points := 0
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	me, err := NewMetricsExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeMetricsExporterConfig,
+		func(_ context.Context, md pmetric.Metrics) error {
+			sizes = append(sizes, metricsMarshaler.MetricsSize(md))
+			points += md.DataPointCount()
+			return nil
+		}, WithQueue(qCfg), WithMaxRequestSize(maxBytes))
+	require.NoError(t, err)
+	require.NoError(t, me.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, me.Shutdown(context.Background())) })
+
+	require.NoError(t, me.ConsumeMetrics(context.Background(), md))
+	assert.Equal(t, md.DataPointCount(), points)
+	assert.GreaterOrEqual(t, len(sizes), 4)
+	for _, size := range sizes {
+		assert.LessOrEqual(t, size, maxBytes)
+	}
+}
+
+func TestSplittableRequest_PartialError(t *testing.T) {
+	exportErr := errors.New("export failed")
+
+	mr := newMetricsRequest(context.Background(), testdata.GenerateMetrics(10), nil).(*metricsRequest)
+	mParts := mr.split(4)
+	var metricsErr consumererror.Metrics
+	require.ErrorAs(t, mr.partialError(exportErr, mParts[1:]), &metricsErr)
+	assert.Equal(t, mr.md.DataPointCount()-mParts[0].(*metricsRequest).md.DataPointCount(), metricsErr.Data().DataPointCount())
+
+	lr := newLogsRequest(context.Background(), testdata.GenerateLogs(10), nil).(*logsRequest)
+	lParts := lr.split(4)
+	var logsErr consumererror.Logs
+	require.ErrorAs(t, lr.partialError(exportErr, lParts[1:]), &logsErr)
+	assert.Equal(t, 6, logsErr.Data().LogRecordCount())
+}
+
+func TestLogsExporter_WithMaxRequestSize_SingleItem(t *testing.T) {
+	ld := testdata.GenerateLogs(1)
+
+	calls := 0
+	qCfg := NewDefaultQueueSettings()
+	qCfg.Enabled = false
+	le, err := NewLogsExporter(context.Background(), exportertest.NewNopCreateSettings(), &fakeLogsExporterConfig,
+		func(_ context.Context, ld plog.Logs) error {
+			calls++
+			return nil
+		}, WithQueue(qCfg), WithMaxRequestSize(1))
+	require.NoError(t, err)
+	require.NoError(t, le.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, le.Shutdown(context.Background())) })
+
+	// A single log record cannot be split, so it is sent as is.
+	require.NoError(t, le.ConsumeLogs(context.Background(), ld))
+	assert.Equal(t, 1, calls)
+}
+
+// fakeSplittableRequest is a fakeRequest with the size of 10 bytes per item that can be split.
+type fakeSplittableRequest struct {
+	fakeRequest
+	sink *[]int
+}
+
+func (r fakeSplittableRequest) Export(context.Context) error {
+	*r.sink = append(*r.sink, r.items)
+	return nil
+}
+
+func (r fakeSplittableRequest) BytesCount() int {
+	return r.items * 10
+}
+
+func (r fakeSplittableRequest) Split(maxItems int) []Request {
+	var parts []Request
+	for items := r.items; items > 0; items -= maxItems {
+		part := r
+		part.items = maxItems
+		if items < maxItems {
+			part.items = items
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+func TestRequestExporter_WithMaxRequestSize(t *testing.T) {
+	var sink []int
+	be, err := newBaseExporter(exportertest.NewNopCreateSettings(), "", true, nil, nil, newNoopObsrepSender,
+		WithMaxRequestSize(45))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, be.Shutdown(context.Background())) })
+
+	require.NoError(t, be.send(newRequest(context.Background(),
+		fakeSplittableRequest{fakeRequest: fakeRequest{items: 10}, sink: &sink})))
+	assert.Equal(t, []int{4, 4, 2}, sink)
+
+	// The requests not implementing RequestSplitter are sent as is.
+	require.NoError(t, be.send(newRequest(context.Background(), fakeRequest{items: 10})))
+}
+
+func getTestSpanName(requestNum, index int) string {
+	return fmt.Sprintf("test-span-%d-%d", requestNum, index)
+}
+
+func getTestMetricName(requestNum, index int) string {
+	return fmt.Sprintf("test-metric-int-%d-%d", requestNum, index)
+}
+
+func getTestLogSeverityText(requestNum, index int) string {
+	return fmt.Sprintf("test-log-int-%d-%d", requestNum, index)
+}
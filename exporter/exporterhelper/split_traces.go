@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// splitTraces removes spans from the input trace and returns a new trace of the specified size.
+func splitTraces(size int, src ptrace.Traces) ptrace.Traces {
+	if src.SpanCount() <= size {
+		return src
+	}
+	totalCopiedSpans := 0
+	dest := ptrace.NewTraces()
+
+	src.ResourceSpans().RemoveIf(func(srcRs ptrace.ResourceSpans) bool {
+		// If we are done skip everything else.
+		if totalCopiedSpans == size {
+			return false
+		}
+
+		// If it fully fits
+		srcRsSC := resourceSC(srcRs)
+		if (totalCopiedSpans + srcRsSC) <= size {
+			totalCopiedSpans += srcRsSC
+			srcRs.MoveTo(dest.ResourceSpans().AppendEmpty())
+			return true
+		}
+
+		destRs := dest.ResourceSpans().AppendEmpty()
+		srcRs.Resource().CopyTo(destRs.Resource())
+		srcRs.ScopeSpans().RemoveIf(func(srcIls ptrace.ScopeSpans) bool {
+			// If we are done skip everything else.
+			if totalCopiedSpans == size {
+				return false
+			}
+
+			// If possible to move all metrics do that.
+			srcIlsSC := srcIls.Spans().Len()
+			if size-totalCopiedSpans >= srcIlsSC {
+				totalCopiedSpans += srcIlsSC
+				srcIls.MoveTo(destRs.ScopeSpans().AppendEmpty())
+				return true
+			}
+
+			destIls := destRs.ScopeSpans().AppendEmpty()
+			srcIls.Scope().CopyTo(destIls.Scope())
+			srcIls.Spans().RemoveIf(func(srcSpan ptrace.Span) bool {
+				// If we are done skip everything else.
+				if totalCopiedSpans == size {
+					return false
+				}
+				srcSpan.MoveTo(destIls.Spans().AppendEmpty())
+				totalCopiedSpans++
+				return true
+			})
+			return false
+		})
+		return srcRs.ScopeSpans().Len() == 0
+	})
+
+	return dest
+}
+
+// resourceSC calculates the total number of spans in the ptrace.ResourceSpans.
+func resourceSC(rs ptrace.ResourceSpans) (count int) {
+	for k := 0; k < rs.ScopeSpans().Len(); k++ {
+		count += rs.ScopeSpans().At(k).Spans().Len()
+	}
+	return
+}
+
+// split splits a copy of the request data into requests with at most maxItems spans each.
+// The request data is not modified, since it can be shared with other consumers.
+func (req *tracesRequest) split(maxItems int) []internal.Request {
+	td := ptrace.NewTraces()
+	req.td.CopyTo(td)
+	var reqs []internal.Request
+	for td.SpanCount() > maxItems {
+		reqs = append(reqs, newTracesRequest(req.ctx, splitTraces(maxItems, td), req.pusher))
+	}
+	return append(reqs, newTracesRequest(req.ctx, td, req.pusher))
+}
+
+// partialError returns the error with a copy of the spans of the failed parts of the request.
+func (req *tracesRequest) partialError(err error, failed []internal.Request) error {
+	td := ptrace.NewTraces()
+	for _, part := range failed {
+		rss := part.(*tracesRequest).td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			rss.At(i).CopyTo(td.ResourceSpans().AppendEmpty())
+		}
+	}
+	return consumererror.NewTraces(err, td)
+}
@@ -5,6 +5,9 @@ package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporte
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
@@ -14,6 +17,14 @@ import (
 type TimeoutSettings struct {
 	// Timeout is the timeout for every attempt to send data to the backend.
 	Timeout time.Duration `mapstructure:"timeout"`
+	// TimeoutPerItem is added to the Timeout for every span, metric data point or log record in the request,
+	// so the large requests are given more time than the small ones.
+	TimeoutPerItem time.Duration `mapstructure:"timeout_per_item"`
+	// TimeoutPerByte is added to the Timeout for every byte of the request, if the size of the request is known.
+	TimeoutPerByte time.Duration `mapstructure:"timeout_per_byte"`
+	// AckTimeout is the maximum time to wait for the acknowledgment of a request the destination acknowledges
+	// asynchronously, see NewPendingAck. Zero means the acknowledgment is awaited until the Timeout expires.
+	AckTimeout time.Duration `mapstructure:"ack_timeout"`
 }
 
 // NewDefaultTimeoutSettings returns the default settings for TimeoutSettings.
@@ -23,6 +34,29 @@ func NewDefaultTimeoutSettings() TimeoutSettings {
 	}
 }
 
+// Validate checks if the TimeoutSettings configuration is valid
+func (tCfg *TimeoutSettings) Validate() error {
+	if tCfg.TimeoutPerItem < 0 || tCfg.TimeoutPerByte < 0 {
+		return errors.New("timeout increments must not be negative")
+	}
+	if tCfg.AckTimeout < 0 {
+		return errors.New("ack timeout must not be negative")
+	}
+	return nil
+}
+
+// timeoutFor returns the timeout for the given request, zero means no timeout.
+func (tCfg *TimeoutSettings) timeoutFor(req internal.Request) time.Duration {
+	if tCfg.Timeout <= 0 {
+		return 0
+	}
+	timeout := tCfg.Timeout + time.Duration(req.Count())*tCfg.TimeoutPerItem
+	if tCfg.TimeoutPerByte > 0 {
+		timeout += time.Duration(bytesCount(req)) * tCfg.TimeoutPerByte
+	}
+	return timeout
+}
+
 // timeoutSender is a requestSender that adds a `timeout` to every request that passes this sender.
 type timeoutSender struct {
 	baseRequestSender
@@ -33,10 +67,74 @@ func (ts *timeoutSender) send(req internal.Request) error {
 	// Intentionally don't overwrite the context inside the request, because in case of retries deadline will not be
 	// updated because this deadline most likely is before the next one.
 	ctx := req.Context()
-	if ts.cfg.Timeout > 0 {
+	if timeout := ts.cfg.timeoutFor(req); timeout > 0 {
 		var cancelFunc func()
-		ctx, cancelFunc = context.WithTimeout(req.Context(), ts.cfg.Timeout)
+		ctx, cancelFunc = context.WithTimeout(req.Context(), timeout)
 		defer cancelFunc()
 	}
-	return req.Export(ctx)
+	err := req.Export(ctx)
+	if pa := (pendingAck{}); errors.As(err, &pa) {
+		// The queue consumer sending the request can send the next ones while the acknowledgment is pending.
+		if release, ok := req.Context().Value(releaseConsumerKey{}).(func()); ok {
+			release()
+		}
+		return ts.waitAck(ctx, pa)
+	}
+	return err
+}
+
+// waitAck waits for the asynchronous acknowledgment of the request, which result is the result of the attempt.
+func (ts *timeoutSender) waitAck(ctx context.Context, pa pendingAck) error {
+	var timeoutCh <-chan time.Time
+	if ts.cfg.AckTimeout > 0 {
+		timer := time.NewTimer(ts.cfg.AckTimeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+	select {
+	case err := <-pa.ack:
+		return err
+	case <-timeoutCh:
+		return errAckTimeout
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", errAckTimeout, ctx.Err())
+	}
+}
+
+// errAckTimeout is returned when the acknowledgment of a request does not arrive in time.
+// It is not a permanent error, so the request can be retried.
+var errAckTimeout = errors.New("acknowledgment timed out")
+
+// releaseConsumerKey is the context key of the function releasing the queue consumer sending the request,
+// see queueSender.detach.
+type releaseConsumerKey struct{}
+
+// pendingAck is returned by the exporters sending the data which is acknowledged by the destination asynchronously.
+type pendingAck struct {
+	ack <-chan error
+}
+
+func (pa pendingAck) Error() string {
+	return "acknowledgment pending"
+}
+
+// AckFunc reports the result of an export acknowledged asynchronously by the destination, nil or an error.
+// Only the first result is taken into account. It never blocks, so it can be called even after the exporter helper
+// stopped waiting for the acknowledgment, e.g. once the TimeoutSettings.AckTimeout expired.
+type AckFunc func(err error)
+
+// NewPendingAck creates an error to be returned by the exporters when the destination acknowledges the data
+// asynchronously, e.g. with a callback, and the AckFunc to call with the result of the export once it is known.
+// The exporter helper waits for the result up to the TimeoutSettings.AckTimeout, and handles it as if it was
+// returned by the exporter, so the data is retried or removed from the queue only once it is known. The queue
+// consumer sending the data is not blocked meanwhile, up to the QueueSettings.MaxPendingAcks.
+func NewPendingAck() (AckFunc, error) {
+	ack := make(chan error, 1)
+	var once sync.Once
+	ackFunc := func(err error) {
+		once.Do(func() {
+			ack <- err
+		})
+	}
+	return ackFunc, pendingAck{ack: ack}
 }
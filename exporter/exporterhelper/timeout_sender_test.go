@@ -0,0 +1,196 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// deadlineRequest is a fakeRequest with the size of 10 bytes per item that records the time left until the deadline.
+type deadlineRequest struct {
+	fakeRequest
+	timeLeft *time.Duration
+}
+
+func (r deadlineRequest) Export(ctx context.Context) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		*r.timeLeft = time.Until(deadline)
+	}
+	return nil
+}
+
+func (r deadlineRequest) BytesCount() int {
+	return r.items * 10
+}
+
+func TestTimeoutSettings_Validate(t *testing.T) {
+	tCfg := NewDefaultTimeoutSettings()
+	assert.NoError(t, tCfg.Validate())
+
+	tCfg.TimeoutPerItem = -time.Millisecond
+	assert.EqualError(t, tCfg.Validate(), "timeout increments must not be negative")
+
+	tCfg.TimeoutPerItem = time.Millisecond
+	tCfg.TimeoutPerByte = -time.Microsecond
+	assert.EqualError(t, tCfg.Validate(), "timeout increments must not be negative")
+
+	tCfg.TimeoutPerByte = time.Microsecond
+	assert.NoError(t, tCfg.Validate())
+
+	tCfg.AckTimeout = -time.Second
+	assert.EqualError(t, tCfg.Validate(), "ack timeout must not be negative")
+}
+
+func TestTimeoutSettings_TimeoutFor(t *testing.T) {
+	req := newRequest(context.Background(), deadlineRequest{fakeRequest: fakeRequest{items: 100}})
+
+	tCfg := TimeoutSettings{Timeout: 5 * time.Second}
+	assert.Equal(t, 5*time.Second, tCfg.timeoutFor(req))
+
+	tCfg.TimeoutPerItem = time.Millisecond
+	assert.Equal(t, 5*time.Second+100*time.Millisecond, tCfg.timeoutFor(req))
+
+	tCfg.TimeoutPerByte = time.Millisecond
+	assert.Equal(t, 5*time.Second+1100*time.Millisecond, tCfg.timeoutFor(req))
+
+	// The size in bytes is not known for every request.
+	assert.Equal(t, 5*time.Second+100*time.Millisecond, tCfg.timeoutFor(newRequest(context.Background(), fakeRequest{items: 100})))
+
+	// The increments do not apply without the base timeout.
+	tCfg.Timeout = 0
+	assert.Zero(t, tCfg.timeoutFor(req))
+}
+
+func TestTimeoutSender_TimeoutPerItem(t *testing.T) {
+	var timeLeft time.Duration
+	ts := &timeoutSender{cfg: TimeoutSettings{Timeout: time.Second, TimeoutPerItem: time.Second}}
+	require.NoError(t, ts.send(newRequest(context.Background(), deadlineRequest{fakeRequest: fakeRequest{items: 10}, timeLeft: &timeLeft})))
+	assert.Greater(t, timeLeft, 10*time.Second)
+	assert.LessOrEqual(t, timeLeft, 11*time.Second)
+}
+
+// ackRequest is a fakeRequest acknowledged asynchronously, its AckFunc is sent to the acks channel.
+type ackRequest struct {
+	fakeRequest
+	acks chan AckFunc
+}
+
+func (r ackRequest) Export(context.Context) error {
+	ack, err := NewPendingAck()
+	r.acks <- ack
+	return err
+}
+
+func TestNewPendingAck(t *testing.T) {
+	ack, err := NewPendingAck()
+	assert.ErrorAs(t, err, &pendingAck{})
+
+	// Only the first result is kept, the next ones do not block.
+	ackErr := errors.New("not acknowledged")
+	ack(ackErr)
+	ack(nil)
+	assert.Equal(t, ackErr, <-err.(pendingAck).ack)
+}
+
+func TestTimeoutSender_PendingAck(t *testing.T) {
+	ts := &timeoutSender{cfg: TimeoutSettings{Timeout: time.Minute, AckTimeout: time.Minute}}
+
+	acks := make(chan AckFunc, 1)
+	go func() {
+		(<-acks)(nil)
+	}()
+	assert.NoError(t, ts.send(newRequest(context.Background(), ackRequest{acks: acks})))
+
+	ackErr := errors.New("not acknowledged")
+	go func() {
+		ack := <-acks
+		time.Sleep(10 * time.Millisecond)
+		ack(ackErr)
+	}()
+	assert.ErrorIs(t, ts.send(newRequest(context.Background(), ackRequest{acks: acks})), ackErr)
+}
+
+func TestTimeoutSender_PendingAckTimeout(t *testing.T) {
+	ts := &timeoutSender{cfg: TimeoutSettings{Timeout: time.Minute, AckTimeout: 10 * time.Millisecond}}
+	acks := make(chan AckFunc, 1)
+	err := ts.send(newRequest(context.Background(), ackRequest{acks: acks}))
+	assert.ErrorIs(t, err, errAckTimeout)
+	assert.False(t, consumererror.IsPermanent(err))
+	// The late acknowledgment does not block the exporter.
+	(<-acks)(nil)
+
+	// Without the ack timeout, the acknowledgment is awaited until the timeout of the attempt.
+	ts = &timeoutSender{cfg: TimeoutSettings{Timeout: 10 * time.Millisecond}}
+	err = ts.send(newRequest(context.Background(), ackRequest{acks: acks}))
+	assert.ErrorIs(t, err, errAckTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	(<-acks)(nil)
+}
+
+func TestPendingAck_Retried(t *testing.T) {
+	rCfg := NewDefaultRetrySettings()
+	rCfg.InitialInterval = 0
+	tCfg := NewDefaultTimeoutSettings()
+	tCfg.AckTimeout = time.Minute
+	be, err := newBaseExporter(defaultSettings, "", true, nil, nil, newNoopObsrepSender, WithRetry(rCfg), WithTimeout(tCfg))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	// The failed acknowledgment is retried as any other failure.
+	acks := make(chan AckFunc, 2)
+	go func() {
+		(<-acks)(errors.New("not acknowledged"))
+		(<-acks)(nil)
+	}()
+	require.NoError(t, be.send(newRequest(context.Background(), ackRequest{acks: acks})))
+}
+
+func TestPendingAck_QueueConsumerReleased(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.MaxPendingAcks = 2
+	tCfg := NewDefaultTimeoutSettings()
+	tCfg.AckTimeout = time.Minute
+	be, err := newBaseExporter(defaultSettings, "", true, nil, nil, newNoopObsrepSender, WithQueue(qCfg), WithTimeout(tCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	qs := be.queueSender.(*queueSender)
+	var dropped atomic.Int64
+	qs.onDropped = func(internal.Request, error) {
+		dropped.Add(1)
+	}
+
+	acks := make(chan AckFunc, 3)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, be.send(newRequest(context.Background(), ackRequest{acks: acks})))
+	}
+
+	// The single consumer sends the next requests while the acknowledgments are pending, up to the maximum.
+	first, second := <-acks, <-acks
+	assert.Eventually(t, func() bool { return qs.queue.Size() == 0 }, time.Second, time.Millisecond)
+	third := <-acks
+	assert.Len(t, qs.ackSlots, 2)
+
+	// The requests are finished from the acknowledgments, the slot is freed for the third request.
+	first(nil)
+	second(consumererror.NewPermanent(errors.New("not acknowledged")))
+	assert.Eventually(t, func() bool { return dropped.Load() == 1 }, time.Second, time.Millisecond)
+	third(nil)
+	require.NoError(t, be.Shutdown(context.Background()))
+	assert.Empty(t, qs.ackSlots)
+	assert.Equal(t, int64(1), dropped.Load())
+}
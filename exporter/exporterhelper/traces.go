@@ -64,6 +64,10 @@ func (req *tracesRequest) Count() int {
 	return req.td.SpanCount()
 }
 
+func (req *tracesRequest) BytesCount() int {
+	return tracesMarshaler.TracesSize(req.td)
+}
+
 type traceExporter struct {
 	*baseExporter
 	consumer.Traces
@@ -174,6 +174,32 @@ func TestTracesExporter_WithPersistentQueue(t *testing.T) {
 	}, 500*time.Millisecond, 10*time.Millisecond)
 }
 
+func TestTracesRequestExporter_WithPersistentQueue(t *testing.T) {
+	qCfg := NewDefaultQueueSettings()
+	storageID := component.NewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID
+	id := component.NewIDWithName("test_traces", "with_persistent_queue")
+	tt, err := obsreporttest.SetupTelemetry(id)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	set := exporter.CreateSettings{ID: id, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()}
+	te, err := NewTracesRequestExporter(context.Background(), set, &fakeRequestConverter{},
+		WithRequestQueue(qCfg, fakeRequestMarshaler, fakeRequestUnmarshaler))
+	require.NoError(t, err)
+
+	host := &mockHost{ext: map[component.ID]component.Component{
+		storageID: internal.NewMockStorageExtension(nil),
+	}}
+	require.NoError(t, te.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, te.Shutdown(context.Background())) })
+
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+	require.Eventually(t, func() bool {
+		return tt.CheckExporterTraces(2, 0) == nil
+	}, 500*time.Millisecond, 10*time.Millisecond)
+}
+
 func TestTracesExporter_WithRecordMetrics(t *testing.T) {
 	tt, err := obsreporttest.SetupTelemetry(fakeTracesExporterName)
 	require.NoError(t, err)
@@ -307,6 +333,44 @@ func TestTracesRequestExporter_WithSpan_ExportError(t *testing.T) {
 	checkWrapSpanForTracesExporter(t, sr, set.TracerProvider.Tracer("test"), te, want, 1)
 }
 
+func TestTracesExporter_WithQueue_WithSpan(t *testing.T) {
+	set := exportertest.NewNopCreateSettings()
+	sr := new(tracetest.SpanRecorder)
+	set.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	want := errors.New("my_error")
+	te, err := NewTracesExporter(context.Background(), set, &fakeTracesExporterConfig, newTraceDataPusher(want),
+		WithQueue(NewDefaultQueueSettings()), WithRetry(RetrySettings{Enabled: false}))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+
+	ctx, span := set.TracerProvider.Tracer("test").Start(context.Background(), fakeTraceParentSpanName)
+	require.NoError(t, te.ConsumeTraces(ctx, testdata.GenerateTraces(2)))
+	span.End()
+	require.NoError(t, te.Shutdown(context.Background()))
+
+	// The exporter span is a child of the queued request span, which is a child of the span of the caller.
+	gotSpanData := map[string]sdktrace.ReadOnlySpan{}
+	for _, sd := range sr.Ended() {
+		gotSpanData[sd.Name()] = sd
+	}
+	require.Len(t, gotSpanData, 3)
+	parentSpan := gotSpanData[fakeTraceParentSpanName]
+	queuedSpan := gotSpanData["exporter/"+set.ID.String()+"/queued_request"]
+	exportSpan := gotSpanData["exporter/"+set.ID.String()+"/traces"]
+	require.NotNil(t, queuedSpan)
+	require.NotNil(t, exportSpan)
+	require.Equal(t, parentSpan.SpanContext(), queuedSpan.Parent())
+	require.Equal(t, queuedSpan.SpanContext(), exportSpan.Parent())
+	checkStatus(t, queuedSpan, want)
+
+	var eventNames []string
+	for _, event := range queuedSpan.Events() {
+		eventNames = append(eventNames, event.Name)
+	}
+	assert.Equal(t, []string{"Enqueued item.", "Dequeued item."}, eventNames)
+}
+
 func TestTracesExporter_WithShutdown(t *testing.T) {
 	shutdownCalled := false
 	shutdown := func(context.Context) error { shutdownCalled = true; return nil }
@@ -4,10 +4,13 @@ go 1.20
 
 require (
 	github.com/cenkalti/backoff/v4 v4.2.1
+	github.com/golang/snappy v0.0.4
+	github.com/klauspost/compress v1.17.2
 	github.com/stretchr/testify v1.8.4
 	go.opencensus.io v0.24.0
 	go.opentelemetry.io/collector v0.88.0
 	go.opentelemetry.io/collector/component v0.88.0
+	go.opentelemetry.io/collector/config/configopaque v0.88.0
 	go.opentelemetry.io/collector/config/configtelemetry v0.88.0
 	go.opentelemetry.io/collector/consumer v0.88.0
 	go.opentelemetry.io/collector/extension v0.88.0
@@ -94,4 +97,6 @@ replace go.opentelemetry.io/collector/config/confignet => ../config/confignet
 
 replace go.opentelemetry.io/collector/config/configtelemetry => ../config/configtelemetry
 
+replace go.opentelemetry.io/collector/config/configopaque => ../config/configopaque
+
 replace go.opentelemetry.io/collector/service => ../service
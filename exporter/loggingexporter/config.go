@@ -27,7 +27,7 @@ var (
 type Config struct {
 	// LogLevel defines log level of the logging exporter; options are debug, info, warn, error.
 	// Deprecated: Use `Verbosity` instead.
-	LogLevel zapcore.Level `mapstructure:"loglevel,omitempty"`
+	LogLevel zapcore.Level `mapstructure:"loglevel,omitempty" deprecated:"use verbosity instead"`
 
 	// Verbosity defines the logging exporter verbosity.
 	Verbosity configtelemetry.Level `mapstructure:"verbosity,omitempty"`
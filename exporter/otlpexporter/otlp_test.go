@@ -27,6 +27,7 @@ import (
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/internal/testdata"
@@ -310,7 +311,10 @@ func TestSendTraces(t *testing.T) {
 	td = testdata.GenerateTraces(2)
 
 	err = exp.ConsumeTraces(context.Background(), td)
-	assert.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+	var partialErr consumererror.Partial
+	require.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, 1, partialErr.Rejected())
 }
 
 func TestSendTracesWhenEndpointHasHttpScheme(t *testing.T) {
@@ -484,7 +488,11 @@ func TestSendMetrics(t *testing.T) {
 
 	// Send two metrics.
 	md = testdata.GenerateMetrics(2)
-	assert.Error(t, exp.ConsumeMetrics(context.Background(), md))
+	err = exp.ConsumeMetrics(context.Background(), md)
+	assert.True(t, consumererror.IsPermanent(err))
+	var partialErr consumererror.Partial
+	require.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, 1, partialErr.Rejected())
 }
 
 func TestSendTraceDataServerDownAndUp(t *testing.T) {
@@ -770,5 +778,8 @@ func TestSendLogData(t *testing.T) {
 	ld = testdata.GenerateLogs(2)
 
 	err = exp.ConsumeLogs(context.Background(), ld)
-	assert.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+	var partialErr consumererror.Partial
+	require.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, 1, partialErr.Rejected())
 }
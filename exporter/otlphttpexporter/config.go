@@ -25,6 +25,10 @@ type Config struct {
 
 	// The URL to send logs to. If omitted the Endpoint + "/v1/logs" will be used.
 	LogsEndpoint string `mapstructure:"logs_endpoint"`
+
+	// Encoding if not empty, is the ID of the encoding extension encoding the requests, sent with its
+	// content type, instead of the OTLP protobuf encoding.
+	Encoding *component.ID `mapstructure:"encoding"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -44,11 +44,13 @@ func TestUnmarshalConfig(t *testing.T) {
 				Multiplier:          1.3,
 				MaxInterval:         1 * time.Minute,
 				MaxElapsedTime:      10 * time.Minute,
+				Budget:              exporterhelper.NewDefaultRetryBudgetSettings(),
 			},
 			QueueSettings: exporterhelper.QueueSettings{
-				Enabled:      true,
-				NumConsumers: 2,
-				QueueSize:    10,
+				Enabled:        true,
+				NumConsumers:   2,
+				QueueSize:      10,
+				MaxPendingAcks: 1000,
 			},
 			HTTPClientSettings: confighttp.HTTPClientSettings{
 				Headers: map[string]configopaque.String{
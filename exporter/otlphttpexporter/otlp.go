@@ -23,6 +23,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/extension/experimental/encoding"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -42,6 +43,12 @@ type baseExporter struct {
 	settings   component.TelemetrySettings
 	// Default user-agent header.
 	userAgent string
+
+	// The marshalers of the encoding extension, set when the exporter is started.
+	tracesMarshaler  ptrace.Marshaler
+	metricsMarshaler pmetric.Marshaler
+	logsMarshaler    plog.Marshaler
+	contentType      string
 }
 
 const (
@@ -67,10 +74,11 @@ func newExporter(cfg component.Config, set exporter.CreateSettings) (*baseExport
 
 	// client construction is deferred to start
 	return &baseExporter{
-		config:    oCfg,
-		logger:    set.Logger,
-		userAgent: userAgent,
-		settings:  set.TelemetrySettings,
+		config:      oCfg,
+		logger:      set.Logger,
+		userAgent:   userAgent,
+		settings:    set.TelemetrySettings,
+		contentType: protobufContentType,
 	}, nil
 }
 
@@ -82,12 +90,48 @@ func (e *baseExporter) start(_ context.Context, host component.Host) error {
 		return err
 	}
 	e.client = client
+	if e.config.Encoding != nil {
+		return e.startEncoding(host, *e.config.Encoding)
+	}
+	return nil
+}
+
+// startEncoding gets the marshaler of the exported signal from the encoding extension with the given ID.
+func (e *baseExporter) startEncoding(host component.Host, encodingID component.ID) error {
+	ext, found := host.GetExtensions()[encodingID]
+	if !found {
+		return fmt.Errorf("encoding extension %q not found", encodingID)
+	}
+	encodingExt, ok := ext.(encoding.Extension)
+	if !ok {
+		return fmt.Errorf("extension %q is not an encoding extension", encodingID)
+	}
+	switch {
+	case e.tracesURL != "":
+		if e.tracesMarshaler, ok = encodingExt.(encoding.TracesMarshalerExtension); !ok {
+			return fmt.Errorf("encoding extension %q does not encode traces", encodingID)
+		}
+	case e.metricsURL != "":
+		if e.metricsMarshaler, ok = encodingExt.(encoding.MetricsMarshalerExtension); !ok {
+			return fmt.Errorf("encoding extension %q does not encode metrics", encodingID)
+		}
+	case e.logsURL != "":
+		if e.logsMarshaler, ok = encodingExt.(encoding.LogsMarshalerExtension); !ok {
+			return fmt.Errorf("encoding extension %q does not encode logs", encodingID)
+		}
+	}
+	e.contentType = encodingExt.ContentType()
 	return nil
 }
 
 func (e *baseExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
-	tr := ptraceotlp.NewExportRequestFromTraces(td)
-	request, err := tr.MarshalProto()
+	var request []byte
+	var err error
+	if e.tracesMarshaler != nil {
+		request, err = e.tracesMarshaler.MarshalTraces(td)
+	} else {
+		request, err = ptraceotlp.NewExportRequestFromTraces(td).MarshalProto()
+	}
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
@@ -96,8 +140,13 @@ func (e *baseExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
 }
 
 func (e *baseExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) error {
-	tr := pmetricotlp.NewExportRequestFromMetrics(md)
-	request, err := tr.MarshalProto()
+	var request []byte
+	var err error
+	if e.metricsMarshaler != nil {
+		request, err = e.metricsMarshaler.MarshalMetrics(md)
+	} else {
+		request, err = pmetricotlp.NewExportRequestFromMetrics(md).MarshalProto()
+	}
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
@@ -105,8 +154,13 @@ func (e *baseExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) erro
 }
 
 func (e *baseExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
-	tr := plogotlp.NewExportRequestFromLogs(ld)
-	request, err := tr.MarshalProto()
+	var request []byte
+	var err error
+	if e.logsMarshaler != nil {
+		request, err = e.logsMarshaler.MarshalLogs(ld)
+	} else {
+		request, err = plogotlp.NewExportRequestFromLogs(ld).MarshalProto()
+	}
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
@@ -120,7 +174,7 @@ func (e *baseExporter) export(ctx context.Context, url string, request []byte, p
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
-	req.Header.Set("Content-Type", protobufContentType)
+	req.Header.Set("Content-Type", e.contentType)
 	req.Header.Set("User-Agent", e.userAgent)
 
 	resp, err := e.client.Do(req)
@@ -270,7 +324,7 @@ func tracesPartialSuccessHandler(protoBytes []byte, contentType string) error {
 	}
 	partialSuccess := exportResponse.PartialSuccess()
 	if !(partialSuccess.ErrorMessage() == "" && partialSuccess.RejectedSpans() == 0) {
-		return consumererror.NewPermanent(fmt.Errorf("OTLP partial success: %s (%d rejected)", partialSuccess.ErrorMessage(), partialSuccess.RejectedSpans()))
+		return partialSuccessError(fmt.Errorf("OTLP partial success: %s (%d rejected)", partialSuccess.ErrorMessage(), partialSuccess.RejectedSpans()), partialSuccess.RejectedSpans())
 	}
 	return nil
 }
@@ -286,7 +340,7 @@ func metricsPartialSuccessHandler(protoBytes []byte, contentType string) error {
 	}
 	partialSuccess := exportResponse.PartialSuccess()
 	if !(partialSuccess.ErrorMessage() == "" && partialSuccess.RejectedDataPoints() == 0) {
-		return consumererror.NewPermanent(fmt.Errorf("OTLP partial success: %s (%d rejected)", partialSuccess.ErrorMessage(), partialSuccess.RejectedDataPoints()))
+		return partialSuccessError(fmt.Errorf("OTLP partial success: %s (%d rejected)", partialSuccess.ErrorMessage(), partialSuccess.RejectedDataPoints()), partialSuccess.RejectedDataPoints())
 	}
 	return nil
 }
@@ -302,7 +356,16 @@ func logsPartialSuccessHandler(protoBytes []byte, contentType string) error {
 	}
 	partialSuccess := exportResponse.PartialSuccess()
 	if !(partialSuccess.ErrorMessage() == "" && partialSuccess.RejectedLogRecords() == 0) {
-		return consumererror.NewPermanent(fmt.Errorf("OTLP partial success: %s (%d rejected)", partialSuccess.ErrorMessage(), partialSuccess.RejectedLogRecords()))
+		return partialSuccessError(fmt.Errorf("OTLP partial success: %s (%d rejected)", partialSuccess.ErrorMessage(), partialSuccess.RejectedLogRecords()), partialSuccess.RejectedLogRecords())
 	}
 	return nil
 }
+
+// partialSuccessError returns the permanent error of an export partially rejected by the server. If some items
+// were rejected, it is a consumererror.Partial, so the OTLP receivers report the same partial success.
+func partialSuccessError(err error, rejected int64) error {
+	if rejected > 0 {
+		err = consumererror.NewPartial(err, int(rejected))
+	}
+	return consumererror.NewPermanent(err)
+}
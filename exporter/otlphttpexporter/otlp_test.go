@@ -296,6 +296,102 @@ func TestIssue_4221(t *testing.T) {
 	assert.NoError(t, exp.ConsumeTraces(context.Background(), md))
 }
 
+// tracesEncodingExtension encodes the traces with the JSON encoding, under its own content type.
+type tracesEncodingExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	ptrace.JSONMarshaler
+	ptrace.JSONUnmarshaler
+}
+
+func (tracesEncodingExtension) ContentType() string {
+	return "application/x-test"
+}
+
+type encodingHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *encodingHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func TestTraceRoundTripEncoding(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	encodingID := component.NewID("test_encoding")
+	host := &encodingHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{encodingID: &tracesEncodingExtension{}},
+	}
+
+	var contentType string
+	sink := new(consumertest.TracesSink)
+	rcvFactory := otlpreceiver.NewFactory()
+	rcvCfg := createReceiverConfig(addr, rcvFactory.CreateDefaultConfig())
+	rcvCfg.HTTP.Encodings = []component.ID{encodingID}
+	recv, err := rcvFactory.CreateTracesReceiver(context.Background(), receivertest.NewNopCreateSettings(), rcvCfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, recv.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, recv.Shutdown(context.Background())) })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		r.URL.Host = addr
+		r.URL.Scheme = "http"
+		r.RequestURI = ""
+		resp, errDo := http.DefaultClient.Do(r)
+		if !assert.NoError(t, errDo) {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	defer srv.Close()
+
+	factory := NewFactory()
+	cfg := createExporterConfig(srv.URL, factory.CreateDefaultConfig())
+	cfg.Encoding = &encodingID
+	exp, err := factory.CreateTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, exp.Shutdown(context.Background())) })
+
+	td := testdata.GenerateTraces(1)
+	require.NoError(t, exp.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, "application/x-test", contentType)
+	require.Len(t, sink.AllTraces(), 1)
+	assert.EqualValues(t, td, sink.AllTraces()[0])
+}
+
+func TestStartEncodingErrors(t *testing.T) {
+	encodingID := component.NewID("test_encoding")
+	factory := NewFactory()
+	cfg := createExporterConfig("http://localhost:4318", factory.CreateDefaultConfig())
+	cfg.Encoding = &encodingID
+
+	exp, err := factory.CreateTracesExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	assert.EqualError(t, exp.Start(context.Background(), componenttest.NewNopHost()), `encoding extension "test_encoding" not found`)
+
+	host := &encodingHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{encodingID: &struct {
+			component.StartFunc
+			component.ShutdownFunc
+		}{}},
+	}
+	assert.EqualError(t, exp.Start(context.Background(), host), `extension "test_encoding" is not an encoding extension`)
+
+	host.extensions[encodingID] = &tracesEncodingExtension{}
+	metricsExp, err := factory.CreateMetricsExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	assert.EqualError(t, metricsExp.Start(context.Background(), host), `encoding extension "test_encoding" does not encode metrics`)
+}
+
 func startTracesExporter(t *testing.T, baseURL string, overrideURL string) exporter.Traces {
 	factory := NewFactory()
 	cfg := createExporterConfig(baseURL, factory.CreateDefaultConfig())
@@ -711,6 +807,9 @@ func TestPartialSuccess_traces(t *testing.T) {
 	traces := ptrace.NewTraces()
 	err = exp.ConsumeTraces(context.Background(), traces)
 	require.Error(t, err)
+	var partialErr consumererror.Partial
+	require.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, 1, partialErr.Rejected())
 }
 
 func TestPartialSuccess_metrics(t *testing.T) {
@@ -745,6 +844,9 @@ func TestPartialSuccess_metrics(t *testing.T) {
 	metrics := pmetric.NewMetrics()
 	err = exp.ConsumeMetrics(context.Background(), metrics)
 	require.Error(t, err)
+	var partialErr consumererror.Partial
+	require.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, 1, partialErr.Rejected())
 }
 
 func TestPartialSuccess_logs(t *testing.T) {
@@ -779,6 +881,9 @@ func TestPartialSuccess_logs(t *testing.T) {
 	logs := plog.NewLogs()
 	err = exp.ConsumeLogs(context.Background(), logs)
 	require.Error(t, err)
+	var partialErr consumererror.Partial
+	require.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, 1, partialErr.Rejected())
 }
 
 func TestPartialResponse_missingHeaderButHasBody(t *testing.T) {
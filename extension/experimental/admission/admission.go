@@ -0,0 +1,18 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package admission // import "go.opentelemetry.io/collector/extension/experimental/admission"
+
+import (
+	"go.opentelemetry.io/collector/extension"
+)
+
+// Extension is the interface that admission extensions must implement
+type Extension interface {
+	extension.Extension
+
+	// MustRefuse returns whether the incoming data must be refused, e.g. because the memory
+	// usage of the collector is too high. Receivers call it before reading and decoding
+	// the payloads, so the refused data does not use any memory.
+	MustRefuse() bool
+}
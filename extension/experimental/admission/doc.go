@@ -0,0 +1,6 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package admission implements an extension that receivers can
+// consult to refuse the incoming data before decoding it.
+package admission // import "go.opentelemetry.io/collector/extension/experimental/admission"
@@ -0,0 +1,6 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package encoding implements extensions that the components can use to
+// encode and decode the payloads with alternative encodings.
+package encoding // import "go.opentelemetry.io/collector/extension/experimental/encoding"
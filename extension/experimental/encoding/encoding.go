@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package encoding // import "go.opentelemetry.io/collector/extension/experimental/encoding"
+
+import (
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Extension is the interface that encoding extensions must implement
+type Extension interface {
+	extension.Extension
+
+	// ContentType returns the media type of the encoded payloads, e.g. "application/x-custom-protobuf".
+	// The components negotiate the encoding with it, e.g. with the Content-Type HTTP header.
+	ContentType() string
+}
+
+// TracesMarshalerExtension is an encoding extension able to encode traces.
+type TracesMarshalerExtension interface {
+	Extension
+	ptrace.Marshaler
+}
+
+// TracesUnmarshalerExtension is an encoding extension able to decode traces.
+type TracesUnmarshalerExtension interface {
+	Extension
+	ptrace.Unmarshaler
+}
+
+// MetricsMarshalerExtension is an encoding extension able to encode metrics.
+type MetricsMarshalerExtension interface {
+	Extension
+	pmetric.Marshaler
+}
+
+// MetricsUnmarshalerExtension is an encoding extension able to decode metrics.
+type MetricsUnmarshalerExtension interface {
+	Extension
+	pmetric.Unmarshaler
+}
+
+// LogsMarshalerExtension is an encoding extension able to encode logs.
+type LogsMarshalerExtension interface {
+	Extension
+	plog.Marshaler
+}
+
+// LogsUnmarshalerExtension is an encoding extension able to decode logs.
+type LogsUnmarshalerExtension interface {
+	Extension
+	plog.Unmarshaler
+}
@@ -0,0 +1,6 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package memorybudget implements an extension that can
+// share a memory budget between the components of the collector.
+package memorybudget // import "go.opentelemetry.io/collector/extension/experimental/memorybudget"
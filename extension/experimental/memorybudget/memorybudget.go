@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package memorybudget // import "go.opentelemetry.io/collector/extension/experimental/memorybudget"
+
+import (
+	"sync/atomic"
+
+	"go.opentelemetry.io/collector/extension"
+)
+
+// Extension is the interface that memory budget extensions must implement
+type Extension interface {
+	extension.Extension
+
+	// GetBudget returns the memory budget shared by all the components using the extension.
+	GetBudget() Budget
+}
+
+// Budget is the interface that memory budgets must implement.
+// The components acquire the estimated size of the data they hold in memory, e.g. in a queue,
+// and release it once the data is no longer held.
+type Budget interface {
+	// TryAcquire reserves the given number of bytes. It returns false, and reserves nothing,
+	// if the budget does not have enough bytes left.
+	TryAcquire(bytes int64) bool
+	// Release returns the given number of bytes, previously reserved with TryAcquire, to the budget.
+	Release(bytes int64)
+}
+
+type budget struct {
+	limit int64
+	used  atomic.Int64
+}
+
+// NewBudget returns a budget of the given number of bytes, safe for concurrent use.
+func NewBudget(limit int64) Budget {
+	return &budget{limit: limit}
+}
+
+// TryAcquire reserves the given number of bytes if the budget has enough bytes left.
+func (b *budget) TryAcquire(bytes int64) bool {
+	for {
+		used := b.used.Load()
+		if used+bytes > b.limit {
+			return false
+		}
+		if b.used.CompareAndSwap(used, used+bytes) {
+			return true
+		}
+	}
+}
+
+// Release returns the given number of bytes to the budget.
+func (b *budget) Release(bytes int64) {
+	b.used.Add(-bytes)
+}
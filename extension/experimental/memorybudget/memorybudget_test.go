@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package memorybudget
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	b := NewBudget(100)
+	assert.True(t, b.TryAcquire(60))
+	assert.False(t, b.TryAcquire(50))
+	assert.True(t, b.TryAcquire(40))
+	assert.False(t, b.TryAcquire(1))
+
+	b.Release(60)
+	assert.True(t, b.TryAcquire(50))
+	assert.False(t, b.TryAcquire(11))
+}
+
+func TestBudget_Concurrent(t *testing.T) {
+	b := NewBudget(1000)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if b.TryAcquire(10) {
+					b.Release(10)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.True(t, b.TryAcquire(1000))
+}
@@ -54,6 +54,14 @@ type Client interface {
 	Close(ctx context.Context) error
 }
 
+// Compactor is an optional interface that storage clients can implement
+// to reclaim the space left by the deleted data, e.g. by rewriting the underlying file.
+type Compactor interface {
+	// Compact reclaims the unused space and returns the number of bytes reclaimed.
+	// It should not be called concurrently with the other operations on the client.
+	Compact(ctx context.Context) (int64, error)
+}
+
 type opType int
 
 const (
@@ -45,6 +45,7 @@ type PipelineWatcher interface {
 // wishes to be notified of the Collector's effective configuration.
 type ConfigWatcher interface {
 	// NotifyConfig notifies the extension of the Collector's current effective configuration.
+	// The values retrieved as secrets by the config providers are redacted, see confmap.WithRetrievedOpaque.
 	NotifyConfig(ctx context.Context, conf *confmap.Conf) error
 }
 
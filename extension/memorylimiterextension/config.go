@@ -0,0 +1,13 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package memorylimiterextension provides an extension that receivers can consult
+// to refuse the incoming data according to the current state of memory usage.
+package memorylimiterextension // import "go.opentelemetry.io/collector/extension/memorylimiterextension"
+
+import (
+	"go.opentelemetry.io/collector/internal/memorylimiter"
+)
+
+// Config defines configuration for the memory limiter extension.
+type Config = memorylimiter.Config
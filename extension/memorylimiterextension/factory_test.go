@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package memorylimiterextension
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+)
+
+func TestFactory_CreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.Equal(t, &Config{}, cfg)
+
+	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
+}
+
+func TestFactory_CreateExtension(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+
+	// This extension can't be created with the default config.
+	ext, err := createExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	assert.Error(t, err)
+	assert.Nil(t, ext)
+
+	cfg.CheckInterval = 100 * time.Millisecond
+	cfg.MemoryLimitMiB = 1024
+	cfg.MemorySpikeLimitMiB = 512
+	ext, err = createExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+
+	assert.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, ext.Shutdown(context.Background()))
+}
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package memorylimiterextension // import "go.opentelemetry.io/collector/extension/memorylimiterextension"
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/admission"
+	"go.opentelemetry.io/collector/extension/experimental/memorybudget"
+	"go.opentelemetry.io/collector/internal/memorylimiter"
+)
+
+type memoryLimiterExtension struct {
+	memLimiter *memorylimiter.MemoryLimiter
+	budget     *memoryLimiterBudget
+}
+
+var _ admission.Extension = (*memoryLimiterExtension)(nil)
+var _ memorybudget.Extension = (*memoryLimiterExtension)(nil)
+
+// newMemoryLimiter returns a new memorylimiter extension.
+func newMemoryLimiter(cfg *Config, logger *zap.Logger) (*memoryLimiterExtension, error) {
+	ml, err := memorylimiter.NewMemoryLimiter(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &memoryLimiterExtension{
+		memLimiter: ml,
+		budget: &memoryLimiterBudget{
+			Budget:     memorybudget.NewBudget(int64(ml.MemoryLimit())),
+			memLimiter: ml,
+		},
+	}, nil
+}
+
+func (ml *memoryLimiterExtension) Start(ctx context.Context, host component.Host) error {
+	return ml.memLimiter.Start(ctx, host)
+}
+
+func (ml *memoryLimiterExtension) Shutdown(ctx context.Context) error {
+	return ml.memLimiter.Shutdown(ctx)
+}
+
+// MustRefuse returns whether the memory usage is above the limits, in which case the
+// receivers must refuse the incoming data.
+func (ml *memoryLimiterExtension) MustRefuse() bool {
+	return ml.memLimiter.MustRefuse()
+}
+
+// GetBudget returns the memory budget shared by the components using the extension, e.g. the sending queues
+// of the exporters.
+func (ml *memoryLimiterExtension) GetBudget() memorybudget.Budget {
+	return ml.budget
+}
+
+// memoryLimiterBudget bounds the bytes held by the components to the hard limit, and refuses to acquire
+// more while the memory usage is above the soft limit.
+type memoryLimiterBudget struct {
+	memorybudget.Budget
+	memLimiter *memorylimiter.MemoryLimiter
+}
+
+// TryAcquire reserves the given number of bytes unless the memory usage is above the soft limit, or the
+// bytes held by the components would exceed the hard limit.
+func (b *memoryLimiterBudget) TryAcquire(bytes int64) bool {
+	if b.memLimiter.MustRefuse() {
+		return false
+	}
+	return b.Budget.TryAcquire(bytes)
+}
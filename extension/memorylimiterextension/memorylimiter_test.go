@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package memorylimiterextension
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/internal/memorylimiter"
+)
+
+// TestMemoryPressureResponse manipulates results from querying memory and
+// check expected side effects.
+func TestMemoryPressureResponse(t *testing.T) {
+	var currentMemAlloc uint64
+	memorylimiter.ReadMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = currentMemAlloc
+	}
+	t.Cleanup(func() {
+		memorylimiter.ReadMemStatsFn = runtime.ReadMemStats
+	})
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.CheckInterval = time.Hour
+	cfg.MemoryLimitMiB = 1
+	ml, err := newMemoryLimiter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, ml.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, ml.Shutdown(context.Background())) }()
+
+	// Below memAllocLimit.
+	currentMemAlloc = 800 * 1024
+	ml.memLimiter.CheckMemLimits()
+	assert.False(t, ml.MustRefuse())
+
+	// Above memAllocLimit.
+	currentMemAlloc = 1800 * 1024
+	ml.memLimiter.CheckMemLimits()
+	assert.True(t, ml.MustRefuse())
+
+	// Back below memAllocLimit.
+	currentMemAlloc = 500 * 1024
+	ml.memLimiter.CheckMemLimits()
+	assert.False(t, ml.MustRefuse())
+}
+
+func TestShutdownNotStarted(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.CheckInterval = time.Second
+	cfg.MemoryLimitMiB = 1024
+	ml, err := newMemoryLimiter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	assert.ErrorIs(t, ml.Shutdown(context.Background()), memorylimiter.ErrShutdownNotStarted)
+}
+
+func TestMemoryBudget(t *testing.T) {
+	var currentMemAlloc uint64
+	memorylimiter.ReadMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = currentMemAlloc
+	}
+	t.Cleanup(func() {
+		memorylimiter.ReadMemStatsFn = runtime.ReadMemStats
+	})
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.CheckInterval = time.Hour
+	cfg.MemoryLimitMiB = 1
+	ml, err := newMemoryLimiter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, ml.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, ml.Shutdown(context.Background())) }()
+
+	budget := ml.GetBudget()
+	// The bytes held are bounded by the hard limit.
+	assert.True(t, budget.TryAcquire(800*1024))
+	assert.False(t, budget.TryAcquire(300*1024))
+	budget.Release(800 * 1024)
+
+	// Nothing is acquired while the memory usage is above the soft limit.
+	currentMemAlloc = 1800 * 1024
+	ml.memLimiter.CheckMemLimits()
+	assert.False(t, budget.TryAcquire(1))
+
+	currentMemAlloc = 500 * 1024
+	ml.memLimiter.CheckMemLimits()
+	assert.True(t, budget.TryAcquire(1024*1024))
+}
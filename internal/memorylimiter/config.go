@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package memorylimiter implements the memory limiting logic shared by the memory
+// limiter processor and extension.
+package memorylimiter // import "go.opentelemetry.io/collector/internal/memorylimiter"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Config defines configuration for the memory limiter.
+type Config struct {
+	// CheckInterval is the time between measurements of memory usage for the
+	// purposes of avoiding going over the limits. Defaults to zero, so no
+	// checks will be performed.
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+
+	// MemoryLimitMiB is the maximum amount of memory, in MiB, targeted to be
+	// allocated by the process.
+	MemoryLimitMiB uint32 `mapstructure:"limit_mib"`
+
+	// MemorySpikeLimitMiB is the maximum, in MiB, spike expected between the
+	// measurements of memory usage.
+	MemorySpikeLimitMiB uint32 `mapstructure:"spike_limit_mib"`
+
+	// MemoryLimitPercentage is the maximum amount of memory, in %, targeted to be
+	// allocated by the process. The fixed memory settings MemoryLimitMiB has a higher precedence.
+	// If neither is set, the hard limit is 80% of the total memory available to the process,
+	// detected from the container limits, cgroups v1 or v2, or else from the physical memory.
+	MemoryLimitPercentage uint32 `mapstructure:"limit_percentage"`
+
+	// MemorySpikePercentage is the maximum, in percents against the total memory,
+	// spike expected between the measurements of memory usage.
+	MemorySpikePercentage uint32 `mapstructure:"spike_limit_percentage"`
+
+	// UseGoMemLimit enables the coordination of the memory limiter with the Go runtime
+	// memory limit (GOMEMLIMIT). When enabled the hard limit is set as the Go runtime soft
+	// memory limit, unless GOMEMLIMIT is already set in the environment, and memory usage
+	// is read using runtime metrics as accounted by the Go runtime. If neither MemoryLimitMiB
+	// nor MemoryLimitPercentage is set, the hard limit is derived from GOMEMLIMIT, if set, or
+	// from the total memory available to the process, detected from the container limits.
+	UseGoMemLimit bool `mapstructure:"use_gomemlimit"`
+
+	// Budgets are the shares, in % of the soft limit, of the memory usage allotted to the
+	// traces, metrics and logs. When the soft limit is exceeded only the signals using more
+	// than their budget are refused, the memory usage of a signal being estimated from its
+	// share of the incoming data, in bytes. The signals without a budget are refused as soon
+	// as the soft limit is exceeded, and all the signals once the hard limit is exceeded.
+	Budgets map[component.DataType]uint32 `mapstructure:"budgets"`
+
+	// SpikeDetectionWindow enables the detection of the memory usage spikes from the growth
+	// rate of the memory usage over this window, in place of the fixed spike limit. The spike
+	// expected before the next check is estimated from the fastest growth between two checks
+	// in the window, and the data is refused when it would bring the memory usage above the
+	// hard limit. It must be greater than or equal to CheckInterval. MemorySpikeLimitMiB and
+	// MemorySpikePercentage must not be set when enabled.
+	SpikeDetectionWindow time.Duration `mapstructure:"spike_detection_window"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+var (
+	errBudgetsOutOfRange = errors.New("the sum of the budgets must be less than or equal to hundred")
+
+	errSpikeDetectionWindowOutOfRange = errors.New("spike_detection_window must be greater than or equal to check_interval")
+
+	errSpikeLimitWithDetection = errors.New("spike_limit_mib and spike_limit_percentage must not be set with spike_detection_window")
+)
+
+// Validate checks if the memory limiter configuration is valid
+func (cfg *Config) Validate() error {
+	var total uint32
+	for dataType, budget := range cfg.Budgets {
+		switch dataType {
+		case component.DataTypeTraces, component.DataTypeMetrics, component.DataTypeLogs:
+		default:
+			return fmt.Errorf("unknown data type %q in budgets", dataType)
+		}
+		if budget == 0 || budget > 100 {
+			return fmt.Errorf("the budget of %s must be greater than zero and less than or equal to hundred", dataType)
+		}
+		total += budget
+	}
+	if total > 100 {
+		return errBudgetsOutOfRange
+	}
+	if cfg.SpikeDetectionWindow < 0 || (cfg.SpikeDetectionWindow > 0 && cfg.SpikeDetectionWindow < cfg.CheckInterval) {
+		return errSpikeDetectionWindowOutOfRange
+	}
+	if cfg.SpikeDetectionWindow > 0 && (cfg.MemorySpikeLimitMiB != 0 || cfg.MemorySpikePercentage != 0) {
+		return errSpikeLimitWithDetection
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package memorylimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr string
+	}{
+		{
+			name: "no_budgets",
+			cfg:  &Config{},
+		},
+		{
+			name: "budgets",
+			cfg: &Config{Budgets: map[component.DataType]uint32{
+				component.DataTypeTraces:  50,
+				component.DataTypeMetrics: 20,
+				component.DataTypeLogs:    30,
+			}},
+		},
+		{
+			name:    "unknown_data_type",
+			cfg:     &Config{Budgets: map[component.DataType]uint32{"profiles": 50}},
+			wantErr: `unknown data type "profiles" in budgets`,
+		},
+		{
+			name:    "zero_budget",
+			cfg:     &Config{Budgets: map[component.DataType]uint32{component.DataTypeTraces: 0}},
+			wantErr: "the budget of traces must be greater than zero and less than or equal to hundred",
+		},
+		{
+			name: "budgets_above_hundred",
+			cfg: &Config{Budgets: map[component.DataType]uint32{
+				component.DataTypeTraces: 60,
+				component.DataTypeLogs:   60,
+			}},
+			wantErr: errBudgetsOutOfRange.Error(),
+		},
+		{
+			name: "spike_detection_window",
+			cfg:  &Config{CheckInterval: time.Second, SpikeDetectionWindow: 10 * time.Second},
+		},
+		{
+			name:    "spike_detection_window_below_check_interval",
+			cfg:     &Config{CheckInterval: time.Second, SpikeDetectionWindow: 500 * time.Millisecond},
+			wantErr: errSpikeDetectionWindowOutOfRange.Error(),
+		},
+		{
+			name:    "negative_spike_detection_window",
+			cfg:     &Config{CheckInterval: time.Second, SpikeDetectionWindow: -time.Second},
+			wantErr: errSpikeDetectionWindowOutOfRange.Error(),
+		},
+		{
+			name:    "spike_limit_with_spike_detection",
+			cfg:     &Config{CheckInterval: time.Second, MemorySpikeLimitMiB: 100, SpikeDetectionWindow: 10 * time.Second},
+			wantErr: errSpikeLimitWithDetection.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
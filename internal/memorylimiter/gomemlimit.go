@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package memorylimiter // import "go.opentelemetry.io/collector/internal/memorylimiter"
+
+import (
+	"math"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+)
+
+// make it overridable by tests
+var setMemoryLimitFn = debug.SetMemoryLimit
+
+// currentGoMemLimit returns the Go runtime memory limit if it is set, typically with the
+// GOMEMLIMIT environment variable.
+func currentGoMemLimit() (uint64, bool) {
+	// A negative input does not adjust the limit, it only returns the current one.
+	limit := setMemoryLimitFn(-1)
+	if limit <= 0 || limit == math.MaxInt64 {
+		return 0, false
+	}
+	return uint64(limit), true
+}
+
+// goMemLimitSamples are the runtime metrics used to compute the memory accounted by the
+// Go runtime memory limit, see https://pkg.go.dev/runtime/debug#SetMemoryLimit.
+var goMemLimitSamples = []string{
+	"/memory/classes/total:bytes",
+	"/memory/classes/heap/released:bytes",
+}
+
+// readRuntimeMemStats sets ms.Alloc to the memory accounted by the Go runtime memory limit.
+// Unlike runtime.ReadMemStats, reading runtime metrics does not stop the world, so it is
+// cheap enough to be done at every check interval.
+func readRuntimeMemStats(ms *runtime.MemStats) {
+	samples := make([]metrics.Sample, len(goMemLimitSamples))
+	for i, name := range goMemLimitSamples {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+	total := samples[0].Value.Uint64()
+	released := samples[1].Value.Uint64()
+	if released > total {
+		released = total
+	}
+	ms.Alloc = total - released
+}
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package memorylimiter
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/internal/iruntime"
+)
+
+// fakeGoMemLimit replaces the Go runtime memory limit with a fake one for the duration of the test.
+func fakeGoMemLimit(t *testing.T, initial int64) *int64 {
+	limit := initial
+	setMemoryLimitFn = func(l int64) int64 {
+		prev := limit
+		if l >= 0 {
+			limit = l
+		}
+		return prev
+	}
+	t.Cleanup(func() {
+		setMemoryLimitFn = debug.SetMemoryLimit
+	})
+	return &limit
+}
+
+func TestReadRuntimeMemStats(t *testing.T) {
+	ms := &runtime.MemStats{}
+	readRuntimeMemStats(ms)
+	assert.Greater(t, ms.Alloc, uint64(0))
+}
+
+func TestGoMemLimitUsageChecker(t *testing.T) {
+	t.Cleanup(func() {
+		GetMemoryFn = iruntime.TotalMemory
+	})
+	GetMemoryFn = func() (uint64, error) {
+		return 100 * mibBytes, nil
+	}
+
+	t.Run("total_memory", func(t *testing.T) {
+		fakeGoMemLimit(t, math.MaxInt64)
+		d, err := getMemUsageChecker(&Config{UseGoMemLimit: true}, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, &memUsageChecker{
+			memAllocLimit: 80 * mibBytes,
+			memSpikeLimit: 16 * mibBytes,
+		}, d)
+	})
+	t.Run("total_memory_spike_percentage", func(t *testing.T) {
+		fakeGoMemLimit(t, math.MaxInt64)
+		d, err := getMemUsageChecker(&Config{UseGoMemLimit: true, MemorySpikePercentage: 50}, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, &memUsageChecker{
+			memAllocLimit: 80 * mibBytes,
+			memSpikeLimit: 40 * mibBytes,
+		}, d)
+	})
+	t.Run("gomemlimit_env", func(t *testing.T) {
+		fakeGoMemLimit(t, 50*mibBytes)
+		d, err := getMemUsageChecker(&Config{UseGoMemLimit: true}, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, &memUsageChecker{
+			memAllocLimit: 50 * mibBytes,
+			memSpikeLimit: 10 * mibBytes,
+		}, d)
+	})
+	t.Run("fixed_limit_precedence", func(t *testing.T) {
+		fakeGoMemLimit(t, 50*mibBytes)
+		d, err := getMemUsageChecker(&Config{UseGoMemLimit: true, MemoryLimitMiB: 40}, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, &memUsageChecker{
+			memAllocLimit: 40 * mibBytes,
+			memSpikeLimit: 8 * mibBytes,
+		}, d)
+	})
+}
+
+func TestSetGoMemLimit(t *testing.T) {
+	limit := fakeGoMemLimit(t, math.MaxInt64)
+
+	cfg := &Config{}
+	cfg.CheckInterval = 10 * time.Second
+	cfg.MemoryLimitMiB = 1024
+	cfg.UseGoMemLimit = true
+	ml, err := NewMemoryLimiter(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, ml.Start(context.Background(), &host{ballastSize: 10 * mibBytes}))
+	assert.Equal(t, int64(1034*mibBytes), *limit)
+
+	// A second start, for another pipeline, keeps the limit.
+	require.NoError(t, ml.Start(context.Background(), &host{ballastSize: 10 * mibBytes}))
+	require.NoError(t, ml.Shutdown(context.Background()))
+	assert.Equal(t, int64(1034*mibBytes), *limit)
+
+	require.NoError(t, ml.Shutdown(context.Background()))
+	assert.Equal(t, int64(math.MaxInt64), *limit)
+}
+
+func TestSetGoMemLimitKeepsEnv(t *testing.T) {
+	limit := fakeGoMemLimit(t, 512*mibBytes)
+
+	cfg := &Config{}
+	cfg.CheckInterval = 10 * time.Second
+	cfg.MemoryLimitMiB = 1024
+	cfg.UseGoMemLimit = true
+	ml, err := NewMemoryLimiter(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, ml.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, int64(512*mibBytes), *limit)
+	require.NoError(t, ml.Shutdown(context.Background()))
+	assert.Equal(t, int64(512*mibBytes), *limit)
+}
+
+func TestGoMemLimitSoftLimitNoGC(t *testing.T) {
+	fakeGoMemLimit(t, math.MaxInt64)
+
+	cfg := &Config{}
+	cfg.CheckInterval = 10 * time.Second
+	cfg.MemoryLimitMiB = 100
+	cfg.MemorySpikeLimitMiB = 20
+	cfg.UseGoMemLimit = true
+	ml, err := NewMemoryLimiter(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	var currentMemAlloc uint64
+	ml.readMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = currentMemAlloc
+	}
+
+	// Above the soft limit the data is refused without forcing a GC.
+	currentMemAlloc = 90 * mibBytes
+	ml.CheckMemLimits()
+	assert.True(t, ml.mustRefuse.Load())
+	assert.True(t, ml.lastGCDone.IsZero())
+
+	// Above the hard limit a GC is forced.
+	currentMemAlloc = 110 * mibBytes
+	ml.CheckMemLimits()
+	assert.True(t, ml.mustRefuse.Load())
+	assert.False(t, ml.lastGCDone.IsZero())
+
+	currentMemAlloc = 50 * mibBytes
+	ml.CheckMemLimits()
+	assert.False(t, ml.mustRefuse.Load())
+}
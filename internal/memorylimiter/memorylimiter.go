@@ -0,0 +1,475 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package memorylimiter // import "go.opentelemetry.io/collector/internal/memorylimiter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/internal/iruntime"
+)
+
+const (
+	mibBytes = 1024 * 1024
+)
+
+var (
+	// ErrDataRefused will be returned to callers of ConsumeTraceData to indicate
+	// that data is being refused due to high memory usage.
+	ErrDataRefused = errors.New("data refused due to high memory usage")
+
+	// Construction errors
+
+	errCheckIntervalOutOfRange = errors.New(
+		"checkInterval must be greater than zero")
+
+	errLimitOutOfRange = errors.New(
+		"memAllocLimit must be greater than zero when memSpikeLimit is set")
+
+	errMemSpikeLimitOutOfRange = errors.New(
+		"memSpikeLimit must be smaller than memAllocLimit")
+
+	errPercentageLimitOutOfRange = errors.New(
+		"memoryLimitPercentage and memorySpikePercentage must be greater than zero and less than or equal to hundred",
+	)
+
+	// ErrShutdownNotStarted is returned when the memory limiter is shut down more times than started.
+	ErrShutdownNotStarted = errors.New("no existing monitoring routine is running")
+)
+
+var (
+	// GetMemoryFn and ReadMemStatsFn make it overridable by tests
+	GetMemoryFn    = iruntime.TotalMemory
+	ReadMemStatsFn = runtime.ReadMemStats
+)
+
+// MemoryLimiter checks the memory usage periodically and indicates when the data
+// must be refused. It is shared by the memory limiter processor and extension.
+type MemoryLimiter struct {
+	usageChecker memUsageChecker
+
+	memCheckWait time.Duration
+	ballastSize  uint64
+
+	// mustRefuse is used to indicate when data should be refused.
+	mustRefuse *atomic.Bool
+
+	ticker *time.Ticker
+
+	lastGCDone time.Time
+
+	// The function to read the mem values is set as a reference to help with
+	// testing different values.
+	readMemStatsFn func(m *runtime.MemStats)
+
+	// Fields used for logging.
+	logger                 *zap.Logger
+	configMismatchedLogged bool
+
+	// useGoMemLimit indicates that the memory limiter sets and coordinates with the
+	// Go runtime memory limit.
+	useGoMemLimit bool
+	// prevGoMemLimit is the Go runtime memory limit before it was set by the memory
+	// limiter, restored on shutdown. Negative if the limit was not set.
+	prevGoMemLimit int64
+
+	// budgets are the memory budgets of the signals, set once when the memory limiter is created.
+	budgets map[component.DataType]*budget
+
+	// spikeDetector estimates the spike limit at each check, nil if the spike limit is fixed.
+	spikeDetector *spikeDetector
+
+	refCounterLock sync.Mutex
+	refCounter     int
+}
+
+// budget is the share of the soft limit allotted to a signal.
+type budget struct {
+	percentage uint64
+	// bytes is the size of the incoming data of the signal since the last check, refused or not.
+	bytes atomic.Int64
+	// mustRefuse is used to indicate when the data of the signal should be refused.
+	mustRefuse atomic.Bool
+}
+
+// defaultMemoryLimitPercentage is the percentage of the total memory used as the hard limit
+// when no limit is configured. The rest is left for the memory not accounted by the heap.
+const defaultMemoryLimitPercentage = 80
+
+// Minimum interval between forced GC when in soft limited mode. We don't want to
+// do GCs too frequently since it is a CPU-heavy operation.
+const minGCIntervalWhenSoftLimited = 10 * time.Second
+
+// NewMemoryLimiter returns a new memory limiter.
+func NewMemoryLimiter(cfg *Config, logger *zap.Logger) (*MemoryLimiter, error) {
+	if cfg.CheckInterval <= 0 {
+		return nil, errCheckIntervalOutOfRange
+	}
+	if cfg.MemoryLimitMiB == 0 && cfg.MemorySpikeLimitMiB != 0 {
+		// The fixed spike limit has no meaning with a limit derived from the total memory.
+		return nil, errLimitOutOfRange
+	}
+
+	usageChecker, err := getMemUsageChecker(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SpikeDetectionWindow > 0 {
+		// The spike limit is estimated at each check.
+		usageChecker.memSpikeLimit = 0
+	}
+
+	logger.Info("Memory limiter configured",
+		zap.Uint64("limit_mib", usageChecker.memAllocLimit/mibBytes),
+		zap.Uint64("spike_limit_mib", usageChecker.memSpikeLimit/mibBytes),
+		zap.Duration("check_interval", cfg.CheckInterval),
+		zap.Bool("use_gomemlimit", cfg.UseGoMemLimit),
+		zap.Duration("spike_detection_window", cfg.SpikeDetectionWindow))
+
+	ml := &MemoryLimiter{
+		usageChecker:   *usageChecker,
+		memCheckWait:   cfg.CheckInterval,
+		ticker:         time.NewTicker(cfg.CheckInterval),
+		readMemStatsFn: ReadMemStatsFn,
+		logger:         logger,
+		mustRefuse:     &atomic.Bool{},
+		useGoMemLimit:  cfg.UseGoMemLimit,
+		prevGoMemLimit: -1,
+	}
+	if cfg.UseGoMemLimit {
+		ml.readMemStatsFn = readRuntimeMemStats
+	}
+	if cfg.SpikeDetectionWindow > 0 {
+		ml.spikeDetector = newSpikeDetector(cfg.SpikeDetectionWindow, cfg.CheckInterval)
+	}
+	if len(cfg.Budgets) > 0 {
+		ml.budgets = make(map[component.DataType]*budget, len(cfg.Budgets))
+		for dataType, percentage := range cfg.Budgets {
+			ml.budgets[dataType] = &budget{percentage: uint64(percentage)}
+		}
+	}
+
+	return ml, nil
+}
+
+func getMemUsageChecker(cfg *Config, logger *zap.Logger) (*memUsageChecker, error) {
+	memAllocLimit := uint64(cfg.MemoryLimitMiB) * mibBytes
+	memSpikeLimit := uint64(cfg.MemorySpikeLimitMiB) * mibBytes
+	if cfg.MemoryLimitMiB != 0 {
+		return newFixedMemUsageChecker(memAllocLimit, memSpikeLimit)
+	}
+	if cfg.UseGoMemLimit && cfg.MemoryLimitPercentage == 0 {
+		return getGoMemLimitUsageChecker(cfg, logger)
+	}
+	if cfg.MemoryLimitPercentage == 0 {
+		return getDetectedMemUsageChecker(cfg, logger)
+	}
+	totalMemory, err := GetMemoryFn()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total memory, use fixed memory settings (limit_mib): %w", err)
+	}
+	logger.Info("Using percentage memory limiter",
+		zap.Uint64("total_memory_mib", totalMemory/mibBytes),
+		zap.Uint32("limit_percentage", cfg.MemoryLimitPercentage),
+		zap.Uint32("spike_limit_percentage", cfg.MemorySpikePercentage))
+	if cfg.SpikeDetectionWindow > 0 {
+		// No spike percentage is needed, the spike limit is estimated.
+		if cfg.MemoryLimitPercentage > 100 {
+			return nil, errPercentageLimitOutOfRange
+		}
+		return newFixedMemUsageChecker(uint64(cfg.MemoryLimitPercentage)*totalMemory/100, 0)
+	}
+	return newPercentageMemUsageChecker(totalMemory, uint64(cfg.MemoryLimitPercentage), uint64(cfg.MemorySpikePercentage))
+}
+
+// getGoMemLimitUsageChecker derives the limits from GOMEMLIMIT, if set, or from the total
+// memory detected from the container limits.
+func getGoMemLimitUsageChecker(cfg *Config, logger *zap.Logger) (*memUsageChecker, error) {
+	if limit, ok := currentGoMemLimit(); ok {
+		logger.Info("Using GOMEMLIMIT as the memory limit",
+			zap.Uint64("gomemlimit_mib", limit/mibBytes))
+		return newFixedMemUsageChecker(limit, uint64(cfg.MemorySpikePercentage)*limit/100)
+	}
+	totalMemory, err := GetMemoryFn()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total memory, use fixed memory settings (limit_mib): %w", err)
+	}
+	logger.Info("Using memory limit derived from the total memory",
+		zap.Uint64("total_memory_mib", totalMemory/mibBytes),
+		zap.Uint32("limit_percentage", defaultMemoryLimitPercentage),
+		zap.Uint32("spike_limit_percentage", cfg.MemorySpikePercentage))
+	limit := defaultMemoryLimitPercentage * totalMemory / 100
+	return newFixedMemUsageChecker(limit, uint64(cfg.MemorySpikePercentage)*limit/100)
+}
+
+// getDetectedMemUsageChecker derives the limits from the total memory detected from the
+// container limits, cgroups v1 or v2, or from the physical memory, when no limit is configured.
+// The spike limit is spike_limit_percentage of the total memory, or 20% of the hard limit if not set.
+func getDetectedMemUsageChecker(cfg *Config, logger *zap.Logger) (*memUsageChecker, error) {
+	totalMemory, err := GetMemoryFn()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect the total memory, use fixed memory settings (limit_mib): %w", err)
+	}
+	logger.Info("Using memory limit derived from the detected total memory",
+		zap.Uint64("total_memory_mib", totalMemory/mibBytes),
+		zap.Uint32("limit_percentage", defaultMemoryLimitPercentage),
+		zap.Uint32("spike_limit_percentage", cfg.MemorySpikePercentage))
+	if cfg.MemorySpikePercentage > 100 {
+		return nil, errPercentageLimitOutOfRange
+	}
+	return newFixedMemUsageChecker(defaultMemoryLimitPercentage*totalMemory/100, uint64(cfg.MemorySpikePercentage)*totalMemory/100)
+}
+
+// Start starts the monitoring of the memory usage, it can be called once for each
+// component sharing the memory limiter.
+func (ml *MemoryLimiter) Start(_ context.Context, host component.Host) error {
+	extensions := host.GetExtensions()
+	for _, extension := range extensions {
+		if ext, ok := extension.(interface{ GetBallastSize() uint64 }); ok {
+			ml.ballastSize = ext.GetBallastSize()
+			break
+		}
+	}
+	ml.startMonitoring()
+	return nil
+}
+
+// Shutdown stops the monitoring of the memory usage once all the components sharing
+// the memory limiter are shut down.
+func (ml *MemoryLimiter) Shutdown(context.Context) error {
+	ml.refCounterLock.Lock()
+	defer ml.refCounterLock.Unlock()
+
+	if ml.refCounter == 0 {
+		return ErrShutdownNotStarted
+	} else if ml.refCounter == 1 {
+		ml.ticker.Stop()
+		if ml.prevGoMemLimit >= 0 {
+			setMemoryLimitFn(ml.prevGoMemLimit)
+			ml.prevGoMemLimit = -1
+		}
+	}
+	ml.refCounter--
+	return nil
+}
+
+// MustRefuse returns whether the data must be refused due to high memory usage.
+func (ml *MemoryLimiter) MustRefuse() bool {
+	return ml.mustRefuse.Load()
+}
+
+// MemoryLimit returns the hard limit, in bytes, of the memory allocated by the process, the ballast excluded.
+func (ml *MemoryLimiter) MemoryLimit() uint64 {
+	return ml.usageChecker.memAllocLimit
+}
+
+// HasBudget returns whether the signal has a memory budget. If so the size of its incoming
+// data must be given to MustRefuseData.
+func (ml *MemoryLimiter) HasBudget(dataType component.DataType) bool {
+	_, ok := ml.budgets[dataType]
+	return ok
+}
+
+// MustRefuseData records the size in bytes of the incoming data of the signal and returns whether
+// it must be refused due to high memory usage. The size is ignored if the signal has no budget.
+func (ml *MemoryLimiter) MustRefuseData(dataType component.DataType, size int) bool {
+	b, ok := ml.budgets[dataType]
+	if !ok {
+		return ml.mustRefuse.Load()
+	}
+	b.bytes.Add(int64(size))
+	return b.mustRefuse.Load()
+}
+
+func (ml *MemoryLimiter) readMemStats() *runtime.MemStats {
+	ms := &runtime.MemStats{}
+	ml.readMemStatsFn(ms)
+	// If proper configured ms.Alloc should be at least ml.ballastSize but since
+	// a misconfiguration is possible check for that here.
+	if ms.Alloc >= ml.ballastSize {
+		ms.Alloc -= ml.ballastSize
+	} else if !ml.configMismatchedLogged {
+		// This indicates misconfiguration. Log it once.
+		ml.configMismatchedLogged = true
+		ml.logger.Warn(`"size_mib" in ballast extension is likely incorrectly configured.`)
+	}
+
+	return ms
+}
+
+// startMonitoring starts a single ticker'd goroutine per instance
+// that will check memory usage every checkInterval period.
+func (ml *MemoryLimiter) startMonitoring() {
+	ml.refCounterLock.Lock()
+	defer ml.refCounterLock.Unlock()
+
+	ml.refCounter++
+	if ml.refCounter == 1 {
+		if ml.useGoMemLimit {
+			ml.setGoMemLimit()
+		}
+		go func() {
+			for range ml.ticker.C {
+				ml.CheckMemLimits()
+			}
+		}()
+	}
+}
+
+// setGoMemLimit sets the hard limit as the Go runtime memory limit, so the garbage collector
+// works to keep the memory usage below it, unless it was already set in the environment.
+func (ml *MemoryLimiter) setGoMemLimit() {
+	// The ballast is accounted by the Go runtime but excluded from the memory usage.
+	limit := ml.usageChecker.memAllocLimit + ml.ballastSize
+	if current, ok := currentGoMemLimit(); ok {
+		if current < limit {
+			ml.logger.Warn("GOMEMLIMIT is lower than the memory limiter hard limit, the memory limiter may never be triggered.",
+				zap.Uint64("gomemlimit_mib", current/mibBytes),
+				zap.Uint64("limit_mib", limit/mibBytes))
+		}
+		return
+	}
+	if limit > math.MaxInt64 {
+		limit = math.MaxInt64
+	}
+	ml.prevGoMemLimit = setMemoryLimitFn(int64(limit))
+	ml.logger.Info("Set GOMEMLIMIT", zap.Uint64("gomemlimit_mib", limit/mibBytes))
+}
+
+func memstatToZapField(ms *runtime.MemStats) zap.Field {
+	return zap.Uint64("cur_mem_mib", ms.Alloc/mibBytes)
+}
+
+func (ml *MemoryLimiter) doGCandReadMemStats() *runtime.MemStats {
+	runtime.GC()
+	ml.lastGCDone = time.Now()
+	ms := ml.readMemStats()
+	ml.logger.Info("Memory usage after GC.", memstatToZapField(ms))
+	return ms
+}
+
+// CheckMemLimits checks the memory usage and updates whether the data must be refused.
+// It is called periodically once the memory limiter is started.
+func (ml *MemoryLimiter) CheckMemLimits() {
+	ms := ml.readMemStats()
+
+	ml.logger.Debug("Currently used memory.", memstatToZapField(ms))
+
+	if ml.spikeDetector != nil {
+		ml.usageChecker.memSpikeLimit = ml.spikeDetector.spikeLimit(ms.Alloc, ml.usageChecker.memAllocLimit)
+		ml.logger.Debug("Estimated memory spike.", zap.Uint64("spike_limit_mib", ml.usageChecker.memSpikeLimit/mibBytes))
+	}
+
+	if ml.usageChecker.aboveHardLimit(ms) {
+		ml.logger.Warn("Memory usage is above hard limit. Forcing a GC.", memstatToZapField(ms))
+		ms = ml.doGCandReadMemStats()
+	}
+
+	// Remember current state.
+	wasRefusing := ml.mustRefuse.Load()
+
+	// Check if the memory usage is above the soft limit.
+	mustRefuse := ml.usageChecker.aboveSoftLimit(ms)
+
+	if wasRefusing && !mustRefuse {
+		// Was previously refusing but enough memory is available now, no need to limit.
+		ml.logger.Info("Memory usage back within limits. Resuming normal operation.", memstatToZapField(ms))
+	}
+
+	if !wasRefusing && mustRefuse {
+		// We are above soft limit, do a GC if it wasn't done recently and see if
+		// it brings memory usage below the soft limit. When GOMEMLIMIT is in use the
+		// Go runtime already collects more aggressively, so only refuse the data.
+		if !ml.useGoMemLimit && time.Since(ml.lastGCDone) > minGCIntervalWhenSoftLimited {
+			ml.logger.Info("Memory usage is above soft limit. Forcing a GC.", memstatToZapField(ms))
+			ms = ml.doGCandReadMemStats()
+			// Check the limit again to see if GC helped.
+			mustRefuse = ml.usageChecker.aboveSoftLimit(ms)
+		}
+
+		if mustRefuse {
+			ml.logger.Warn("Memory usage is above soft limit. Refusing data.", memstatToZapField(ms))
+		}
+	}
+
+	ml.mustRefuse.Store(mustRefuse)
+	if len(ml.budgets) > 0 {
+		ml.checkBudgets(ms, mustRefuse)
+	}
+}
+
+// checkBudgets updates whether the data of each signal with a budget must be refused. Above the soft
+// limit the memory usage is split between the signals according to their share of the incoming data.
+func (ml *MemoryLimiter) checkBudgets(ms *runtime.MemStats, aboveSoftLimit bool) {
+	var total uint64
+	sizes := make(map[component.DataType]uint64, len(ml.budgets))
+	for dataType, b := range ml.budgets {
+		size := uint64(b.bytes.Swap(0))
+		sizes[dataType] = size
+		total += size
+	}
+
+	aboveHardLimit := ml.usageChecker.aboveHardLimit(ms)
+	softLimit := ml.usageChecker.memAllocLimit - ml.usageChecker.memSpikeLimit
+	for dataType, b := range ml.budgets {
+		mustRefuse := aboveHardLimit
+		if aboveSoftLimit && !mustRefuse && total > 0 {
+			usage := float64(ms.Alloc) * float64(sizes[dataType]) / float64(total)
+			mustRefuse = usage >= float64(b.percentage*softLimit/100)
+		}
+
+		wasRefusing := b.mustRefuse.Swap(mustRefuse)
+		if wasRefusing && !mustRefuse {
+			ml.logger.Info("Memory usage of the signal back within its budget. Resuming normal operation.",
+				zap.String("data_type", string(dataType)), memstatToZapField(ms))
+		}
+		if !wasRefusing && mustRefuse {
+			ml.logger.Warn("Memory usage of the signal is above its budget. Refusing data.",
+				zap.String("data_type", string(dataType)), memstatToZapField(ms))
+		}
+	}
+}
+
+type memUsageChecker struct {
+	memAllocLimit uint64
+	memSpikeLimit uint64
+}
+
+func (d memUsageChecker) aboveSoftLimit(ms *runtime.MemStats) bool {
+	return ms.Alloc >= d.memAllocLimit-d.memSpikeLimit
+}
+
+func (d memUsageChecker) aboveHardLimit(ms *runtime.MemStats) bool {
+	return ms.Alloc >= d.memAllocLimit
+}
+
+func newFixedMemUsageChecker(memAllocLimit, memSpikeLimit uint64) (*memUsageChecker, error) {
+	if memSpikeLimit >= memAllocLimit {
+		return nil, errMemSpikeLimitOutOfRange
+	}
+	if memSpikeLimit == 0 {
+		// If spike limit is unspecified use 20% of mem limit.
+		memSpikeLimit = memAllocLimit / 5
+	}
+	return &memUsageChecker{
+		memAllocLimit: memAllocLimit,
+		memSpikeLimit: memSpikeLimit,
+	}, nil
+}
+
+func newPercentageMemUsageChecker(totalMemory uint64, percentageLimit, percentageSpike uint64) (*memUsageChecker, error) {
+	if percentageLimit > 100 || percentageLimit <= 0 || percentageSpike > 100 || percentageSpike <= 0 {
+		return nil, errPercentageLimitOutOfRange
+	}
+	return newFixedMemUsageChecker(percentageLimit*totalMemory/100, percentageSpike*totalMemory/100)
+}
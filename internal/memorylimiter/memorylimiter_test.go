@@ -0,0 +1,421 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package memorylimiter
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/internal/iruntime"
+)
+
+func TestNew(t *testing.T) {
+	type args struct {
+		checkInterval       time.Duration
+		memoryLimitMiB      uint32
+		memorySpikeLimitMiB uint32
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "zero_checkInterval",
+			args:    args{},
+			wantErr: errCheckIntervalOutOfRange,
+		},
+		{
+			name: "memSpikeLimit_without_memAllocLimit",
+			args: args{
+				checkInterval:       100 * time.Millisecond,
+				memorySpikeLimitMiB: 1,
+			},
+			wantErr: errLimitOutOfRange,
+		},
+		{
+			name: "memSpikeLimit_gt_memAllocLimit",
+			args: args{
+				checkInterval:       100 * time.Millisecond,
+				memoryLimitMiB:      1,
+				memorySpikeLimitMiB: 2,
+			},
+			wantErr: errMemSpikeLimitOutOfRange,
+		},
+		{
+			name: "success",
+			args: args{
+				checkInterval:  100 * time.Millisecond,
+				memoryLimitMiB: 1024,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.CheckInterval = tt.args.checkInterval
+			cfg.MemoryLimitMiB = tt.args.memoryLimitMiB
+			cfg.MemorySpikeLimitMiB = tt.args.memorySpikeLimitMiB
+			got, err := NewMemoryLimiter(cfg, zap.NewNop())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, got.Start(context.Background(), componenttest.NewNopHost()))
+			assert.NoError(t, got.Shutdown(context.Background()))
+		})
+	}
+}
+
+// TestMemoryPressureResponse manipulates results from querying memory and
+// check expected side effects.
+func TestMemoryPressureResponse(t *testing.T) {
+	var currentMemAlloc uint64
+	ml := &MemoryLimiter{
+		usageChecker: memUsageChecker{
+			memAllocLimit: 1024,
+		},
+		mustRefuse: &atomic.Bool{},
+		readMemStatsFn: func(ms *runtime.MemStats) {
+			ms.Alloc = currentMemAlloc
+		},
+		logger: zap.NewNop(),
+	}
+
+	// Below memAllocLimit.
+	currentMemAlloc = 800
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuse())
+
+	// Above memAllocLimit.
+	currentMemAlloc = 1800
+	ml.CheckMemLimits()
+	assert.True(t, ml.MustRefuse())
+
+	// Check ballast effect
+	ml.ballastSize = 1000
+
+	// Below memAllocLimit accounting for ballast.
+	currentMemAlloc = 800 + ml.ballastSize
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuse())
+
+	// Above memAllocLimit even accountiing for ballast.
+	currentMemAlloc = 1800 + ml.ballastSize
+	ml.CheckMemLimits()
+	assert.True(t, ml.MustRefuse())
+
+	// Restore ballast to default.
+	ml.ballastSize = 0
+
+	// Check spike limit
+	ml.usageChecker.memSpikeLimit = 512
+
+	// Below memSpikeLimit.
+	currentMemAlloc = 500
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuse())
+
+	// Above memSpikeLimit.
+	currentMemAlloc = 550
+	ml.CheckMemLimits()
+	assert.True(t, ml.MustRefuse())
+}
+
+func TestBudgets(t *testing.T) {
+	var currentMemAlloc uint64
+	cfg := &Config{
+		CheckInterval:  time.Second,
+		MemoryLimitMiB: 1,
+		Budgets: map[component.DataType]uint32{
+			component.DataTypeTraces: 60,
+			component.DataTypeLogs:   40,
+		},
+	}
+	ml, err := NewMemoryLimiter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	ml.usageChecker = memUsageChecker{memAllocLimit: 1000, memSpikeLimit: 200}
+	ml.readMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = currentMemAlloc
+	}
+
+	assert.True(t, ml.HasBudget(component.DataTypeTraces))
+	assert.True(t, ml.HasBudget(component.DataTypeLogs))
+	assert.False(t, ml.HasBudget(component.DataTypeMetrics))
+
+	// Below the soft limit.
+	currentMemAlloc = 700
+	assert.False(t, ml.MustRefuseData(component.DataTypeTraces, 100))
+	assert.False(t, ml.MustRefuseData(component.DataTypeLogs, 900))
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuseData(component.DataTypeTraces, 100))
+	assert.False(t, ml.MustRefuseData(component.DataTypeLogs, 900))
+	assert.False(t, ml.MustRefuseData(component.DataTypeMetrics, 100))
+
+	// Above the soft limit, the logs use 90% of the memory, above their budget of 40% of the soft limit.
+	currentMemAlloc = 900
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuseData(component.DataTypeTraces, 0))
+	assert.True(t, ml.MustRefuseData(component.DataTypeLogs, 0))
+	// The signals without a budget are refused above the soft limit.
+	assert.True(t, ml.MustRefuseData(component.DataTypeMetrics, 100))
+	assert.True(t, ml.MustRefuse())
+
+	// Above the soft limit, the traces use 90% of the memory.
+	assert.False(t, ml.MustRefuseData(component.DataTypeTraces, 900))
+	assert.True(t, ml.MustRefuseData(component.DataTypeLogs, 100))
+	ml.CheckMemLimits()
+	assert.True(t, ml.MustRefuseData(component.DataTypeTraces, 0))
+	assert.False(t, ml.MustRefuseData(component.DataTypeLogs, 0))
+
+	// Without incoming data no signal is above its budget.
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuseData(component.DataTypeTraces, 100))
+	assert.False(t, ml.MustRefuseData(component.DataTypeLogs, 100))
+
+	// Above the hard limit all the data is refused.
+	currentMemAlloc = 1100
+	ml.CheckMemLimits()
+	assert.True(t, ml.MustRefuseData(component.DataTypeTraces, 0))
+	assert.True(t, ml.MustRefuseData(component.DataTypeLogs, 0))
+
+	// Back below the soft limit.
+	currentMemAlloc = 700
+	ml.CheckMemLimits()
+	assert.False(t, ml.MustRefuseData(component.DataTypeTraces, 100))
+	assert.False(t, ml.MustRefuseData(component.DataTypeLogs, 100))
+	assert.False(t, ml.MustRefuseData(component.DataTypeMetrics, 100))
+}
+
+func TestSpikeDetection(t *testing.T) {
+	var currentMemAlloc uint64
+	cfg := &Config{
+		CheckInterval:        time.Second,
+		MemoryLimitMiB:       1,
+		SpikeDetectionWindow: 3 * time.Second,
+	}
+	ml, err := NewMemoryLimiter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, ml.spikeDetector)
+	assert.Equal(t, uint64(0), ml.usageChecker.memSpikeLimit)
+	ml.usageChecker.memAllocLimit = 1000
+	ml.readMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = currentMemAlloc
+	}
+	now := time.Now()
+	ml.spikeDetector.now = func() time.Time { return now }
+	check := func(alloc uint64) {
+		now = now.Add(time.Second)
+		currentMemAlloc = alloc
+		ml.CheckMemLimits()
+	}
+
+	// Without growth the data is accepted up to the hard limit.
+	check(900)
+	check(900)
+	check(900)
+	assert.False(t, ml.MustRefuse())
+	assert.Equal(t, uint64(0), ml.usageChecker.memSpikeLimit)
+
+	// The data is refused when the growth would reach the hard limit before the next check.
+	check(400)
+	check(600)
+	assert.False(t, ml.MustRefuse())
+	assert.Equal(t, uint64(200), ml.usageChecker.memSpikeLimit)
+	check(850)
+	assert.True(t, ml.MustRefuse())
+	assert.Equal(t, uint64(250), ml.usageChecker.memSpikeLimit)
+
+	// The drops of memory usage do not hide the growth in the window.
+	check(760)
+	assert.True(t, ml.MustRefuse())
+	assert.Equal(t, uint64(250), ml.usageChecker.memSpikeLimit)
+
+	// The growth is forgotten once out of the window.
+	check(760)
+	check(760)
+	check(800)
+	assert.False(t, ml.MustRefuse())
+	assert.Equal(t, uint64(40), ml.usageChecker.memSpikeLimit)
+}
+
+func TestSpikeDetectorLimit(t *testing.T) {
+	d := newSpikeDetector(time.Minute, 10*time.Second)
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	assert.Equal(t, uint64(0), d.spikeLimit(100, 1000))
+	now = now.Add(5 * time.Second)
+	// 20 bytes per second over a check interval of 10 seconds.
+	assert.Equal(t, uint64(200), d.spikeLimit(200, 1000))
+	now = now.Add(5 * time.Second)
+	assert.Equal(t, uint64(1000), d.spikeLimit(2000, 1000))
+}
+
+func TestGetDecision(t *testing.T) {
+	t.Run("fixed_limit", func(t *testing.T) {
+		d, err := getMemUsageChecker(&Config{MemoryLimitMiB: 100, MemorySpikeLimitMiB: 20}, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, &memUsageChecker{
+			memAllocLimit: 100 * mibBytes,
+			memSpikeLimit: 20 * mibBytes,
+		}, d)
+	})
+	t.Run("fixed_limit_error", func(t *testing.T) {
+		d, err := getMemUsageChecker(&Config{MemoryLimitMiB: 20, MemorySpikeLimitMiB: 100}, zap.NewNop())
+		require.Error(t, err)
+		assert.Nil(t, d)
+	})
+
+	t.Cleanup(func() {
+		GetMemoryFn = iruntime.TotalMemory
+	})
+	GetMemoryFn = func() (uint64, error) {
+		return 100 * mibBytes, nil
+	}
+	t.Run("percentage_limit", func(t *testing.T) {
+		d, err := getMemUsageChecker(&Config{MemoryLimitPercentage: 50, MemorySpikePercentage: 10}, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, &memUsageChecker{
+			memAllocLimit: 50 * mibBytes,
+			memSpikeLimit: 10 * mibBytes,
+		}, d)
+	})
+	t.Run("percentage_limit_error", func(t *testing.T) {
+		d, err := getMemUsageChecker(&Config{MemoryLimitPercentage: 101, MemorySpikePercentage: 10}, zap.NewNop())
+		require.Error(t, err)
+		assert.Nil(t, d)
+		d, err = getMemUsageChecker(&Config{MemoryLimitPercentage: 99, MemorySpikePercentage: 101}, zap.NewNop())
+		require.Error(t, err)
+		assert.Nil(t, d)
+	})
+	t.Run("percentage_limit_spike_detection", func(t *testing.T) {
+		d, err := getMemUsageChecker(&Config{MemoryLimitPercentage: 50, SpikeDetectionWindow: time.Minute}, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(50*mibBytes), d.memAllocLimit)
+	})
+	t.Run("detected_limit", func(t *testing.T) {
+		d, err := getMemUsageChecker(&Config{}, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, &memUsageChecker{
+			memAllocLimit: 80 * mibBytes,
+			memSpikeLimit: 16 * mibBytes,
+		}, d)
+		d, err = getMemUsageChecker(&Config{MemorySpikePercentage: 10}, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, &memUsageChecker{
+			memAllocLimit: 80 * mibBytes,
+			memSpikeLimit: 10 * mibBytes,
+		}, d)
+	})
+	t.Run("detected_limit_error", func(t *testing.T) {
+		d, err := getMemUsageChecker(&Config{MemorySpikePercentage: 80}, zap.NewNop())
+		assert.ErrorIs(t, err, errMemSpikeLimitOutOfRange)
+		assert.Nil(t, d)
+		d, err = getMemUsageChecker(&Config{MemorySpikePercentage: 101}, zap.NewNop())
+		assert.ErrorIs(t, err, errPercentageLimitOutOfRange)
+		assert.Nil(t, d)
+	})
+}
+
+func TestRefuseDecision(t *testing.T) {
+	decison1000Limit30Spike30, err := newPercentageMemUsageChecker(1000, 60, 30)
+	require.NoError(t, err)
+	decison1000Limit60Spike50, err := newPercentageMemUsageChecker(1000, 60, 50)
+	require.NoError(t, err)
+	decison1000Limit40Spike20, err := newPercentageMemUsageChecker(1000, 40, 20)
+	require.NoError(t, err)
+	decison1000Limit40Spike60, err := newPercentageMemUsageChecker(1000, 40, 60)
+	require.Error(t, err)
+	assert.Nil(t, decison1000Limit40Spike60)
+
+	tests := []struct {
+		name         string
+		usageChecker memUsageChecker
+		ms           *runtime.MemStats
+		shouldRefuse bool
+	}{
+		{
+			name:         "should refuse over limit",
+			usageChecker: *decison1000Limit30Spike30,
+			ms:           &runtime.MemStats{Alloc: 600},
+			shouldRefuse: true,
+		},
+		{
+			name:         "should not refuse",
+			usageChecker: *decison1000Limit30Spike30,
+			ms:           &runtime.MemStats{Alloc: 100},
+			shouldRefuse: false,
+		},
+		{
+			name: "should not refuse spike, fixed usageChecker",
+			usageChecker: memUsageChecker{
+				memAllocLimit: 600,
+				memSpikeLimit: 500,
+			},
+			ms:           &runtime.MemStats{Alloc: 300},
+			shouldRefuse: true,
+		},
+		{
+			name:         "should refuse, spike, percentage usageChecker",
+			usageChecker: *decison1000Limit60Spike50,
+			ms:           &runtime.MemStats{Alloc: 300},
+			shouldRefuse: true,
+		},
+		{
+			name:         "should refuse, spike, percentage usageChecker",
+			usageChecker: *decison1000Limit40Spike20,
+			ms:           &runtime.MemStats{Alloc: 250},
+			shouldRefuse: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shouldRefuse := test.usageChecker.aboveSoftLimit(test.ms)
+			assert.Equal(t, test.shouldRefuse, shouldRefuse)
+		})
+	}
+}
+
+func TestBallastSize(t *testing.T) {
+	cfg := &Config{}
+	cfg.CheckInterval = 10 * time.Second
+	cfg.MemoryLimitMiB = 1024
+	got, err := NewMemoryLimiter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, got.Start(context.Background(), &host{ballastSize: 113}))
+	assert.Equal(t, uint64(113), got.ballastSize)
+	require.NoError(t, got.Shutdown(context.Background()))
+}
+
+type host struct {
+	ballastSize uint64
+	component.Host
+}
+
+func (h *host) GetExtensions() map[component.ID]component.Component {
+	ret := make(map[component.ID]component.Component)
+	ret[component.NewID("ballast")] = &ballastExtension{ballastSize: h.ballastSize}
+	return ret
+}
+
+type ballastExtension struct {
+	ballastSize uint64
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+func (be *ballastExtension) GetBallastSize() uint64 {
+	return be.ballastSize
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package memorylimiter // import "go.opentelemetry.io/collector/internal/memorylimiter"
+
+import (
+	"time"
+)
+
+// spikeDetector estimates the spike of memory usage expected before the next check from the
+// growth rate of the memory usage over a window. It is used in place of the fixed spike limit
+// when spike_detection_window is set.
+type spikeDetector struct {
+	window        time.Duration
+	checkInterval time.Duration
+
+	// samples are the memory usages measured in the window, in chronological order.
+	samples []memSample
+
+	// now is overridable by tests.
+	now func() time.Time
+}
+
+type memSample struct {
+	at    time.Time
+	alloc uint64
+}
+
+func newSpikeDetector(window, checkInterval time.Duration) *spikeDetector {
+	return &spikeDetector{
+		window:        window,
+		checkInterval: checkInterval,
+		now:           time.Now,
+	}
+}
+
+// spikeLimit records the current memory usage and returns the spike expected before the next
+// check, at most memAllocLimit. The spike is the fastest growth between two measurements in the
+// window, over one check interval. The drops of memory usage, typically due to the garbage
+// collection, are ignored so they do not hide the growth rate.
+func (d *spikeDetector) spikeLimit(alloc, memAllocLimit uint64) uint64 {
+	now := d.now()
+	d.samples = append(d.samples, memSample{at: now, alloc: alloc})
+	first := 0
+	for first < len(d.samples)-1 && now.Sub(d.samples[first].at) > d.window {
+		first++
+	}
+	d.samples = append(d.samples[:0], d.samples[first:]...)
+
+	var rate float64
+	for i := 1; i < len(d.samples); i++ {
+		prev, cur := d.samples[i-1], d.samples[i]
+		elapsed := cur.at.Sub(prev.at).Seconds()
+		if cur.alloc <= prev.alloc || elapsed <= 0 {
+			continue
+		}
+		if r := float64(cur.alloc-prev.alloc) / elapsed; r > rate {
+			rate = r
+		}
+	}
+
+	spike := rate * d.checkInterval.Seconds()
+	if spike >= float64(memAllocLimit) {
+		return memAllocLimit
+	}
+	return uint64(spike)
+}
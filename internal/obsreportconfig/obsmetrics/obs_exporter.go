@@ -32,10 +32,26 @@ const (
 	FailedToSendLogRecordsKey = "send_failed_log_records"
 	// FailedToEnqueueLogRecordsKey used to track logs that failed to be enqueued by exporters.
 	FailedToEnqueueLogRecordsKey = "enqueue_failed_log_records"
+
+	// QueueLatencyKey used to track the time requests spend in the sending queue.
+	QueueLatencyKey = "queue_latency"
+
+	// RetryAttemptsKey used to track the number of attempts made to send a request.
+	RetryAttemptsKey = "retry_attempts"
+	// RetryGiveUpsKey used to track the requests dropped because no more retries were left.
+	RetryGiveUpsKey = "retry_give_ups"
+	// RetryBackoffDelayKey used to track the time spent waiting between the attempts to send requests.
+	RetryBackoffDelayKey = "retry_backoff_delay"
+
+	// SendFailuresKey used to track the requests that failed to be sent by exporters, by error code.
+	SendFailuresKey = "send_failures"
+	// ErrorCodeKey used to identify the coarse error code of the send failures in metrics.
+	ErrorCodeKey = "error_code"
 )
 
 var (
-	TagKeyExporter, _ = tag.NewKey(ExporterKey)
+	TagKeyExporter, _  = tag.NewKey(ExporterKey)
+	TagKeyErrorCode, _ = tag.NewKey(ErrorCodeKey)
 
 	ExporterPrefix                 = ExporterKey + NameSep
 	ExportTraceDataOperationSuffix = NameSep + "traces"
@@ -83,4 +99,24 @@ var (
 		ExporterPrefix+FailedToEnqueueLogRecordsKey,
 		"Number of log records failed to be added to the sending queue.",
 		stats.UnitDimensionless)
+	ExporterQueueLatency = stats.Int64(
+		ExporterPrefix+QueueLatencyKey,
+		"Time spent by requests in the sending queue.",
+		stats.UnitMilliseconds)
+	ExporterRetryAttempts = stats.Int64(
+		ExporterPrefix+RetryAttemptsKey,
+		"Number of attempts made to send a request to destination.",
+		stats.UnitDimensionless)
+	ExporterRetryGiveUps = stats.Int64(
+		ExporterPrefix+RetryGiveUpsKey,
+		"Number of requests dropped because no more retries were left.",
+		stats.UnitDimensionless)
+	ExporterRetryBackoffDelay = stats.Int64(
+		ExporterPrefix+RetryBackoffDelayKey,
+		"Time spent waiting between the attempts to send requests to destination.",
+		stats.UnitMilliseconds)
+	ExporterSendFailures = stats.Int64(
+		ExporterPrefix+SendFailuresKey,
+		"Number of requests that failed to be sent to destination, by error code.",
+		stats.UnitDimensionless)
 )
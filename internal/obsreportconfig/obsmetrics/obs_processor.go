@@ -20,10 +20,20 @@ const (
 
 	// DroppedLogRecordsKey is the key used to identify log records dropped by the Collector.
 	DroppedLogRecordsKey = "dropped_log_records"
+
+	// SignalKey is the key used to identify the signal of the data in the processor durations.
+	SignalKey = "signal"
+
+	// ProcessingDurationKey is the key used to track the time spent by processors processing the data.
+	ProcessingDurationKey = "processing_duration"
+
+	// AwaitDurationKey is the key used to track the time the data waited to be processed by processors.
+	AwaitDurationKey = "await_duration"
 )
 
 var (
 	TagKeyProcessor, _ = tag.NewKey(ProcessorKey)
+	TagKeySignal, _    = tag.NewKey(SignalKey)
 
 	ProcessorPrefix = ProcessorKey + NameSep
 
@@ -65,4 +75,12 @@ var (
 		ProcessorPrefix+DroppedLogRecordsKey,
 		"Number of log records that were dropped.",
 		stats.UnitDimensionless)
+	ProcessorProcessingDuration = stats.Float64(
+		ProcessorPrefix+ProcessingDurationKey,
+		"Time spent processing a batch of data.",
+		stats.UnitMilliseconds)
+	ProcessorAwaitDuration = stats.Float64(
+		ProcessorPrefix+AwaitDurationKey,
+		"Time a batch of data waited to be processed.",
+		stats.UnitMilliseconds)
 )
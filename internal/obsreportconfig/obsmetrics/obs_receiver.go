@@ -15,6 +15,10 @@ const (
 	TransportKey = "transport"
 	// FormatKey used to identify the format of the data received.
 	FormatKey = "format"
+	// ClientKey used to identify the client that sent the data.
+	ClientKey = "client"
+	// ByClientSuffix used to identify the metrics attributing the data to the clients.
+	ByClientSuffix = "_by_client"
 
 	// AcceptedSpansKey used to identify spans accepted by the Collector.
 	AcceptedSpansKey = "accepted_spans"
@@ -32,11 +36,18 @@ const (
 	// RefusedLogRecordsKey used to identify log records refused (ie.: not ingested) by the
 	// Collector.
 	RefusedLogRecordsKey = "refused_log_records"
+
+	// RefusedRequestsKey used to identify requests refused by the Collector before their data was decoded.
+	RefusedRequestsKey = "refused_requests"
+	// RefusalReasonKey used to identify the reason why a request was refused.
+	RefusalReasonKey = "reason"
 )
 
 var (
-	TagKeyReceiver, _  = tag.NewKey(ReceiverKey)
-	TagKeyTransport, _ = tag.NewKey(TransportKey)
+	TagKeyReceiver, _      = tag.NewKey(ReceiverKey)
+	TagKeyTransport, _     = tag.NewKey(TransportKey)
+	TagKeyClient, _        = tag.NewKey(ClientKey)
+	TagKeyRefusalReason, _ = tag.NewKey(RefusalReasonKey)
 
 	ReceiverPrefix                  = ReceiverKey + NameSep
 	ReceiveTraceDataOperationSuffix = NameSep + "TraceDataReceived"
@@ -72,4 +83,38 @@ var (
 		ReceiverPrefix+RefusedLogRecordsKey,
 		"Number of log records that could not be pushed into the pipeline.",
 		stats.UnitDimensionless)
+
+	// Receiver metrics attributing the data to the clients that sent it, recorded only by the
+	// receivers configured to do so, in addition to the metrics above.
+	ReceiverAcceptedSpansByClient = stats.Int64(
+		ReceiverPrefix+AcceptedSpansKey+ByClientSuffix,
+		"Number of spans successfully pushed into the pipeline, by client.",
+		stats.UnitDimensionless)
+	ReceiverRefusedSpansByClient = stats.Int64(
+		ReceiverPrefix+RefusedSpansKey+ByClientSuffix,
+		"Number of spans that could not be pushed into the pipeline, by client.",
+		stats.UnitDimensionless)
+	ReceiverAcceptedMetricPointsByClient = stats.Int64(
+		ReceiverPrefix+AcceptedMetricPointsKey+ByClientSuffix,
+		"Number of metric points successfully pushed into the pipeline, by client.",
+		stats.UnitDimensionless)
+	ReceiverRefusedMetricPointsByClient = stats.Int64(
+		ReceiverPrefix+RefusedMetricPointsKey+ByClientSuffix,
+		"Number of metric points that could not be pushed into the pipeline, by client.",
+		stats.UnitDimensionless)
+	ReceiverAcceptedLogRecordsByClient = stats.Int64(
+		ReceiverPrefix+AcceptedLogRecordsKey+ByClientSuffix,
+		"Number of log records successfully pushed into the pipeline, by client.",
+		stats.UnitDimensionless)
+	ReceiverRefusedLogRecordsByClient = stats.Int64(
+		ReceiverPrefix+RefusedLogRecordsKey+ByClientSuffix,
+		"Number of log records that could not be pushed into the pipeline, by client.",
+		stats.UnitDimensionless)
+
+	// ReceiverRefusedRequests counts the requests refused before their data was decoded, so the
+	// number of items they contained is unknown, by reason.
+	ReceiverRefusedRequests = stats.Int64(
+		ReceiverPrefix+RefusedRequestsKey,
+		"Number of requests refused before their data was decoded, by reason.",
+		stats.UnitDimensionless)
 )
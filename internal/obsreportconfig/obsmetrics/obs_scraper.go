@@ -18,6 +18,19 @@ const (
 	// ErroredMetricPointsKey used to identify metric points errored (i.e.
 	// unable to be scraped) by the Collector.
 	ErroredMetricPointsKey = "errored_metric_points"
+	// TimedOutScrapesKey used to identify scrapes that exceeded the scrape
+	// timeout.
+	TimedOutScrapesKey = "timed_out_scrapes"
+	// BackoffIntervalKey used to identify the current backoff interval of
+	// scrapers after failed scrapes.
+	BackoffIntervalKey = "backoff_interval"
+
+	// ScrapedMetricKey used to identify the metric that failed to be scraped.
+	ScrapedMetricKey = "metric"
+	// ScrapeErrorReasonKey used to identify the reason why a metric failed to be scraped.
+	ScrapeErrorReasonKey = "reason"
+	// ByMetricSuffix used to identify the metrics attributing the errored metric points to the metrics.
+	ByMetricSuffix = "_by_metric"
 )
 
 const (
@@ -26,7 +39,9 @@ const (
 )
 
 var (
-	TagKeyScraper, _ = tag.NewKey(ScraperKey)
+	TagKeyScraper, _           = tag.NewKey(ScraperKey)
+	TagKeyScrapedMetric, _     = tag.NewKey(ScrapedMetricKey)
+	TagKeyScrapeErrorReason, _ = tag.NewKey(ScrapeErrorReasonKey)
 
 	ScraperScrapedMetricPoints = stats.Int64(
 		ScraperPrefix+ScrapedMetricPointsKey,
@@ -36,4 +51,16 @@ var (
 		ScraperPrefix+ErroredMetricPointsKey,
 		"Number of metric points that were unable to be scraped.",
 		stats.UnitDimensionless)
+	ScraperErroredMetricPointsByMetric = stats.Int64(
+		ScraperPrefix+ErroredMetricPointsKey+ByMetricSuffix,
+		"Number of metric points that were unable to be scraped, by metric and reason.",
+		stats.UnitDimensionless)
+	ScraperTimedOutScrapes = stats.Int64(
+		ScraperPrefix+TimedOutScrapesKey,
+		"Number of scrapes that exceeded the scrape timeout.",
+		stats.UnitDimensionless)
+	ScraperBackoffInterval = stats.Int64(
+		ScraperPrefix+BackoffIntervalKey,
+		"Current backoff interval of the scraper after failed scrapes, 0 when not backing off.",
+		stats.UnitMilliseconds)
 )
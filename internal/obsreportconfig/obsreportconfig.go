@@ -36,6 +36,20 @@ var UseOtelWithSDKConfigurationForInternalTelemetryFeatureGate = featuregate.Glo
 	featuregate.WithRegisterDescription("controls whether the collector supports extended OpenTelemetry"+
 		"configuration for internal telemetry"))
 
+// queueLatencyDistribution is shared by all the views created by AllViews, so the views can be registered again
+// after they were unregistered.
+var queueLatencyDistribution = view.Distribution(0, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000)
+
+// processingDurationDistribution is shared by all the views created by AllViews for the same reason.
+// The processing of a batch usually takes less than a millisecond.
+var processingDurationDistribution = view.Distribution(0, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000)
+
+// retryAttemptsDistribution is shared by all the views created by AllViews for the same reason.
+var retryAttemptsDistribution = view.Distribution(1, 2, 3, 5, 10, 20, 50, 100)
+
+// lastValueAggregation is shared by all the views created by AllViews for the same reason.
+var lastValueAggregation = view.LastValue()
+
 // AllViews returns all the OpenCensus views requires by obsreport package.
 func AllViews(level configtelemetry.Level) []*view.View {
 	if level == configtelemetry.LevelNone {
@@ -63,6 +77,8 @@ func AllViews(level configtelemetry.Level) []*view.View {
 		obsmetrics.ExporterSentLogRecords,
 		obsmetrics.ExporterFailedToSendLogRecords,
 		obsmetrics.ExporterFailedToEnqueueLogRecords,
+		obsmetrics.ExporterRetryGiveUps,
+		obsmetrics.ExporterRetryBackoffDelay,
 	}
 	tagKeys = []tag.Key{obsmetrics.TagKeyExporter}
 	views = append(views, genViews(measures, tagKeys, view.Sum())...)
@@ -75,6 +91,27 @@ func AllViews(level configtelemetry.Level) []*view.View {
 	}
 	views = append(views, errorNumberView)
 
+	queueLatencyView := &view.View{
+		Name:        obsmetrics.ExporterQueueLatency.Name(),
+		Description: obsmetrics.ExporterQueueLatency.Description(),
+		TagKeys:     []tag.Key{obsmetrics.TagKeyExporter},
+		Measure:     obsmetrics.ExporterQueueLatency,
+		Aggregation: queueLatencyDistribution,
+	}
+	views = append(views, queueLatencyView)
+
+	retryAttemptsView := &view.View{
+		Name:        obsmetrics.ExporterRetryAttempts.Name(),
+		Description: obsmetrics.ExporterRetryAttempts.Description(),
+		TagKeys:     []tag.Key{obsmetrics.TagKeyExporter},
+		Measure:     obsmetrics.ExporterRetryAttempts,
+		Aggregation: retryAttemptsDistribution,
+	}
+	views = append(views, retryAttemptsView)
+
+	views = append(views, genViews([]*stats.Int64Measure{obsmetrics.ExporterSendFailures},
+		[]tag.Key{obsmetrics.TagKeyExporter, obsmetrics.TagKeyErrorCode}, view.Sum())...)
+
 	// Processor views.
 	measures = []*stats.Int64Measure{
 		obsmetrics.ProcessorAcceptedSpans,
@@ -90,6 +127,16 @@ func AllViews(level configtelemetry.Level) []*view.View {
 	tagKeys = []tag.Key{obsmetrics.TagKeyProcessor}
 	views = append(views, genViews(measures, tagKeys, view.Sum())...)
 
+	for _, measure := range []*stats.Float64Measure{obsmetrics.ProcessorProcessingDuration, obsmetrics.ProcessorAwaitDuration} {
+		views = append(views, &view.View{
+			Name:        measure.Name(),
+			Description: measure.Description(),
+			TagKeys:     []tag.Key{obsmetrics.TagKeyProcessor, obsmetrics.TagKeySignal},
+			Measure:     measure,
+			Aggregation: processingDurationDistribution,
+		})
+	}
+
 	return views
 }
 
@@ -109,8 +156,22 @@ func receiverViews() []*view.View {
 	tagKeys := []tag.Key{
 		obsmetrics.TagKeyReceiver, obsmetrics.TagKeyTransport,
 	}
+	views := genViews(measures, tagKeys, view.Sum())
+
+	measures = []*stats.Int64Measure{
+		obsmetrics.ReceiverAcceptedSpansByClient,
+		obsmetrics.ReceiverRefusedSpansByClient,
+		obsmetrics.ReceiverAcceptedMetricPointsByClient,
+		obsmetrics.ReceiverRefusedMetricPointsByClient,
+		obsmetrics.ReceiverAcceptedLogRecordsByClient,
+		obsmetrics.ReceiverRefusedLogRecordsByClient,
+	}
+	views = append(views, genViews(measures, append(tagKeys, obsmetrics.TagKeyClient), view.Sum())...)
 
-	return genViews(measures, tagKeys, view.Sum())
+	return append(views, genViews(
+		[]*stats.Int64Measure{obsmetrics.ReceiverRefusedRequests},
+		[]tag.Key{obsmetrics.TagKeyReceiver, obsmetrics.TagKeyTransport, obsmetrics.TagKeyRefusalReason},
+		view.Sum())...)
 }
 
 func scraperViews() []*view.View {
@@ -121,10 +182,16 @@ func scraperViews() []*view.View {
 	measures := []*stats.Int64Measure{
 		obsmetrics.ScraperScrapedMetricPoints,
 		obsmetrics.ScraperErroredMetricPoints,
+		obsmetrics.ScraperTimedOutScrapes,
 	}
 	tagKeys := []tag.Key{obsmetrics.TagKeyReceiver, obsmetrics.TagKeyScraper}
 
-	return genViews(measures, tagKeys, view.Sum())
+	views := genViews(measures, tagKeys, view.Sum())
+	views = append(views, genViews(
+		[]*stats.Int64Measure{obsmetrics.ScraperErroredMetricPointsByMetric},
+		[]tag.Key{obsmetrics.TagKeyReceiver, obsmetrics.TagKeyScraper, obsmetrics.TagKeyScrapedMetric, obsmetrics.TagKeyScrapeErrorReason},
+		view.Sum())...)
+	return append(views, genViews([]*stats.Int64Measure{obsmetrics.ScraperBackoffInterval}, tagKeys, lastValueAggregation)...)
 }
 
 func genViews(
@@ -24,17 +24,17 @@ func TestConfigure(t *testing.T) {
 		{
 			name:         "basic",
 			level:        configtelemetry.LevelBasic,
-			wantViewsLen: 27,
+			wantViewsLen: 44,
 		},
 		{
 			name:         "normal",
 			level:        configtelemetry.LevelNormal,
-			wantViewsLen: 27,
+			wantViewsLen: 44,
 		},
 		{
 			name:         "detailed",
 			level:        configtelemetry.LevelDetailed,
-			wantViewsLen: 27,
+			wantViewsLen: 44,
 		},
 	}
 	for _, tt := range tests {
@@ -71,6 +71,14 @@ type SharedComponent[V component.Component] struct {
 	startOnce  sync.Once
 	stopOnce   sync.Once
 	removeFunc func()
+	// startErr and stopErr are returned to all the instances the shared component represents, so the
+	// failure is reported for each of them and not only for the first started or stopped.
+	startErr error
+	stopErr  error
+
+	mu sync.Mutex
+	// registered is the number of instances set up successfully by Register.
+	registered int
 
 	telemetry    *component.TelemetrySettings
 	seenSettings map[*component.TelemetrySettings]struct{}
@@ -81,38 +89,56 @@ func (r *SharedComponent[V]) Unwrap() V {
 	return r.component
 }
 
-// Start implements component.Component.
+// Register sets up the shared component for another instance it represents, e.g. registering the next
+// consumer of a signal, with the given function. If the function fails for the first instance, the
+// component is removed from the SharedComponents map, so it is created again by the next GetOrAdd
+// instead of being shared half set up. The instances already set up are not affected by the failure,
+// as long as the function leaves the component unchanged when failing.
+func (r *SharedComponent[V]) Register(register func(V) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := register(r.component); err != nil {
+		if r.registered == 0 {
+			r.removeFunc()
+		}
+		return err
+	}
+	r.registered++
+	return nil
+}
+
+// Start implements component.Component. The error starting the component is returned for every
+// instance the shared component represents.
 func (r *SharedComponent[V]) Start(ctx context.Context, host component.Host) error {
-	var err error
 	r.startOnce.Do(func() {
 		// It's important that status for a sharedcomponent is reported through its
 		// telemetrysettings to keep status in sync and avoid race conditions. This logic duplicates
 		// and takes priority over the automated status reporting that happens in graph, making the
 		// status reporting in graph a no-op.
 		_ = r.telemetry.ReportComponentStatus(component.NewStatusEvent(component.StatusStarting))
-		if err = r.component.Start(ctx, host); err != nil {
-			_ = r.telemetry.ReportComponentStatus(component.NewPermanentErrorEvent(err))
+		if r.startErr = r.component.Start(ctx, host); r.startErr != nil {
+			_ = r.telemetry.ReportComponentStatus(component.NewPermanentErrorEvent(r.startErr))
 		}
 	})
-	return err
+	return r.startErr
 }
 
-// Shutdown implements component.Component.
+// Shutdown implements component.Component. The error shutting down the component is returned for
+// every instance the shared component represents.
 func (r *SharedComponent[V]) Shutdown(ctx context.Context) error {
-	var err error
 	r.stopOnce.Do(func() {
 		// It's important that status for a sharedcomponent is reported through its
 		// telemetrysettings to keep status in sync and avoid race conditions. This logic duplicates
 		// and takes priority over the automated status reporting that happens in graph, making the
 		// the status reporting in graph a no-op.
 		_ = r.telemetry.ReportComponentStatus(component.NewStatusEvent(component.StatusStopping))
-		err = r.component.Shutdown(ctx)
-		if err != nil {
-			_ = r.telemetry.ReportComponentStatus(component.NewPermanentErrorEvent(err))
+		r.stopErr = r.component.Shutdown(ctx)
+		if r.stopErr != nil {
+			_ = r.telemetry.ReportComponentStatus(component.NewPermanentErrorEvent(r.stopErr))
 		} else {
 			_ = r.telemetry.ReportComponentStatus(component.NewStatusEvent(component.StatusStopped))
 		}
 		r.removeFunc()
 	})
-	return err
+	return r.stopErr
 }
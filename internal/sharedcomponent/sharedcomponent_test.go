@@ -97,16 +97,42 @@ func TestSharedComponent(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, wantErr, got.Start(context.Background(), componenttest.NewNopHost()))
 	assert.Equal(t, 1, calledStart)
-	// Second time is not called anymore.
-	assert.NoError(t, got.Start(context.Background(), componenttest.NewNopHost()))
+	// Second time is not called anymore, the error is returned for the other instances.
+	assert.Equal(t, wantErr, got.Start(context.Background(), componenttest.NewNopHost()))
 	assert.Equal(t, 1, calledStart)
 	assert.Equal(t, wantErr, got.Shutdown(context.Background()))
 	assert.Equal(t, 1, calledStop)
-	// Second time is not called anymore.
-	assert.NoError(t, got.Shutdown(context.Background()))
+	// Second time is not called anymore, the error is returned for the other instances.
+	assert.Equal(t, wantErr, got.Shutdown(context.Background()))
 	assert.Equal(t, 1, calledStop)
 }
 
+func TestSharedComponentRegister(t *testing.T) {
+	wantErr := errors.New("my error")
+	comps := NewSharedComponents[component.ID, *baseComponent]()
+	create := func() (*baseComponent, error) { return &baseComponent{}, nil }
+
+	// A failure registering the first instance removes the component.
+	got, err := comps.GetOrAdd(id, create, newNopTelemetrySettings())
+	require.NoError(t, err)
+	assert.Equal(t, wantErr, got.Register(func(*baseComponent) error { return wantErr }))
+	assert.Len(t, comps.comps, 0)
+
+	// A failure registering another instance keeps the component for the instances registered.
+	got, err = comps.GetOrAdd(id, create, newNopTelemetrySettings())
+	require.NoError(t, err)
+	var registered []*baseComponent
+	require.NoError(t, got.Register(func(c *baseComponent) error {
+		registered = append(registered, c)
+		return nil
+	}))
+	gotSecond, err := comps.GetOrAdd(id, func() (*baseComponent, error) { panic("should not be called") }, newNopTelemetrySettings())
+	require.NoError(t, err)
+	assert.Equal(t, wantErr, gotSecond.Register(func(*baseComponent) error { return wantErr }))
+	assert.Len(t, comps.comps, 1)
+	assert.Equal(t, []*baseComponent{got.Unwrap()}, registered)
+}
+
 func TestSharedComponentsReportStatus(t *testing.T) {
 	reportedStatuses := make(map[*component.InstanceID][]component.Status)
 	newStatusFunc := func() func(*component.StatusEvent) error {
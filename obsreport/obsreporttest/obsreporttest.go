@@ -33,8 +33,13 @@ const (
 	receiverTag  = "receiver"
 	scraperTag   = "scraper"
 	transportTag = "transport"
+	clientTag    = "client"
+	metricTag    = "metric"
+	reasonTag    = "reason"
 	exporterTag  = "exporter"
 	processorTag = "processor"
+	errorCodeTag = "error_code"
+	signalTag    = "signal"
 )
 
 type TestTelemetry struct {
@@ -78,6 +83,19 @@ func (tts *TestTelemetry) CheckExporterLogs(sentLogRecords, sendFailedLogRecords
 	return tts.prometheusChecker.checkExporterLogs(tts.id, sentLogRecords, sendFailedLogRecords)
 }
 
+// CheckExporterRetries checks that for the current exported values for the exporter retry metrics match given values.
+// When this function is called it is required to also call SetupTelemetry as first thing.
+func (tts *TestTelemetry) CheckExporterRetries(requests, attempts, giveUps int64) error {
+	return tts.prometheusChecker.checkExporterRetries(tts.id, requests, attempts, giveUps)
+}
+
+// CheckExporterSendFailures checks that for the current exported value of the exporter send failures metric
+// with the given error code matches the given value.
+// When this function is called it is required to also call SetupTelemetry as first thing.
+func (tts *TestTelemetry) CheckExporterSendFailures(errorCode string, failures int64) error {
+	return tts.prometheusChecker.checkExporterSendFailures(tts.id, errorCode, failures)
+}
+
 func (tts *TestTelemetry) CheckExporterMetricGauge(metric string, val int64) error {
 	return tts.prometheusChecker.checkExporterMetricGauge(tts.id, metric, val)
 }
@@ -100,6 +118,13 @@ func (tts *TestTelemetry) CheckProcessorLogs(acceptedLogRecords, refusedLogRecor
 	return tts.prometheusChecker.checkProcessorLogs(tts.id, acceptedLogRecords, refusedLogRecords, droppedLogRecords)
 }
 
+// CheckProcessorDurations checks that the number of batches of the given signal recorded in the processing and await
+// duration histograms of the processor match the given values. The await duration is not checked when awaited is 0.
+// When this function is called it is required to also call SetupTelemetry as first thing.
+func (tts *TestTelemetry) CheckProcessorDurations(signal component.DataType, processed, awaited int64) error {
+	return tts.prometheusChecker.checkProcessorDurations(tts.id, signal, processed, awaited)
+}
+
 // CheckReceiverTraces checks that for the current exported values for trace receiver metrics match given values.
 // When this function is called it is required to also call SetupTelemetry as first thing.
 func (tts *TestTelemetry) CheckReceiverTraces(protocol string, acceptedSpans, droppedSpans int64) error {
@@ -118,6 +143,34 @@ func (tts *TestTelemetry) CheckReceiverMetrics(protocol string, acceptedMetricPo
 	return tts.prometheusChecker.checkReceiverMetrics(tts.id, protocol, acceptedMetricPoints, droppedMetricPoints)
 }
 
+// CheckReceiverTracesByClient checks that for the current exported values for trace receiver metrics attributed
+// to the given client match given values.
+// When this function is called it is required to also call SetupTelemetry as first thing.
+func (tts *TestTelemetry) CheckReceiverTracesByClient(protocol, client string, acceptedSpans, droppedSpans int64) error {
+	return tts.prometheusChecker.checkReceiverByClient(tts.id, "spans", protocol, client, acceptedSpans, droppedSpans)
+}
+
+// CheckReceiverLogsByClient checks that for the current exported values for logs receiver metrics attributed
+// to the given client match given values.
+// When this function is called it is required to also call SetupTelemetry as first thing.
+func (tts *TestTelemetry) CheckReceiverLogsByClient(protocol, client string, acceptedLogRecords, droppedLogRecords int64) error {
+	return tts.prometheusChecker.checkReceiverByClient(tts.id, "log_records", protocol, client, acceptedLogRecords, droppedLogRecords)
+}
+
+// CheckReceiverMetricsByClient checks that for the current exported values for metrics receiver metrics attributed
+// to the given client match given values.
+// When this function is called it is required to also call SetupTelemetry as first thing.
+func (tts *TestTelemetry) CheckReceiverMetricsByClient(protocol, client string, acceptedMetricPoints, droppedMetricPoints int64) error {
+	return tts.prometheusChecker.checkReceiverByClient(tts.id, "metric_points", protocol, client, acceptedMetricPoints, droppedMetricPoints)
+}
+
+// CheckReceiverRefusedRequests checks that for the current exported value for the receiver requests refused
+// before their data was decoded, for the given reason, matches the given value.
+// When this function is called it is required to also call SetupTelemetry as first thing.
+func (tts *TestTelemetry) CheckReceiverRefusedRequests(protocol, reason string, refusedRequests int64) error {
+	return tts.prometheusChecker.checkReceiverRefusedRequests(tts.id, protocol, reason, refusedRequests)
+}
+
 // Shutdown unregisters any views and shuts down the SpanRecorder
 func (tts *TestTelemetry) Shutdown(ctx context.Context) error {
 	view.Unregister(tts.views...)
@@ -184,3 +237,24 @@ func SetupTelemetry(id component.ID) (TestTelemetry, error) {
 func CheckScraperMetrics(tts TestTelemetry, receiver component.ID, scraper component.ID, scrapedMetricPoints, erroredMetricPoints int64) error {
 	return tts.prometheusChecker.checkScraperMetrics(receiver, scraper, scrapedMetricPoints, erroredMetricPoints)
 }
+
+// CheckScraperErroredMetricsByMetric checks that for the current exported value for the scraper errored
+// metric points of the given metric, failed for the given reason, matches the given value.
+// When this function is called it is required to also call SetupTelemetry as first thing.
+func CheckScraperErroredMetricsByMetric(tts TestTelemetry, receiver component.ID, scraper component.ID, metric, reason string, erroredMetricPoints int64) error {
+	return tts.prometheusChecker.checkScraperErroredMetricsByMetric(receiver, scraper, metric, reason, erroredMetricPoints)
+}
+
+// CheckScraperTimedOutScrapes checks that for the current exported value for the scraper timed out scrapes
+// metric matches the given value.
+// When this function is called it is required to also call SetupTelemetry as first thing.
+func CheckScraperTimedOutScrapes(tts TestTelemetry, receiver component.ID, scraper component.ID, timedOutScrapes int64) error {
+	return tts.prometheusChecker.checkScraperTimedOutScrapes(receiver, scraper, timedOutScrapes)
+}
+
+// CheckScraperBackoffInterval checks that for the current exported value for the scraper backoff interval
+// metric, in milliseconds, matches the given value.
+// When this function is called it is required to also call SetupTelemetry as first thing.
+func CheckScraperBackoffInterval(tts TestTelemetry, receiver component.ID, scraper component.ID, backoffInterval int64) error {
+	return tts.prometheusChecker.checkScraperBackoffInterval(receiver, scraper, backoffInterval)
+}
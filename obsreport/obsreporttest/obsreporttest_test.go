@@ -6,6 +6,7 @@ package obsreporttest_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -192,6 +193,28 @@ func TestCheckProcessorLogViews(t *testing.T) {
 	assert.Error(t, tt.CheckProcessorLogs(0, 0, 9))
 }
 
+func TestCheckProcessorDurationsViews(t *testing.T) {
+	tt, err := obsreporttest.SetupTelemetry(processorID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	por, err := processorhelper.NewObsReport(processorhelper.ObsReportSettings{
+		ProcessorID:             processorID,
+		ProcessorCreateSettings: processor.CreateSettings{ID: processorID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+	})
+	assert.NoError(t, err)
+
+	por.ProcessingDuration(context.Background(), component.DataTypeLogs, time.Millisecond)
+	por.ProcessingDuration(context.Background(), component.DataTypeLogs, 2*time.Millisecond)
+	por.AwaitDuration(context.Background(), component.DataTypeLogs, time.Millisecond)
+
+	assert.NoError(t, tt.CheckProcessorDurations(component.DataTypeLogs, 2, 1))
+	assert.NoError(t, tt.CheckProcessorDurations(component.DataTypeLogs, 2, 0))
+	assert.Error(t, tt.CheckProcessorDurations(component.DataTypeLogs, 1, 1))
+	assert.Error(t, tt.CheckProcessorDurations(component.DataTypeLogs, 2, 2))
+	assert.Error(t, tt.CheckProcessorDurations(component.DataTypeTraces, 2, 1))
+}
+
 func TestCheckExporterTracesViews(t *testing.T) {
 	tt, err := obsreporttest.SetupTelemetry(exporterID)
 	require.NoError(t, err)
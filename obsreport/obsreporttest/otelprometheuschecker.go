@@ -32,6 +32,19 @@ func (pc *prometheusChecker) checkScraperMetrics(receiver component.ID, scraper
 		pc.checkCounter("scraper_errored_metric_points", erroredMetricPoints, scraperAttrs))
 }
 
+func (pc *prometheusChecker) checkScraperErroredMetricsByMetric(receiver component.ID, scraper component.ID, metric, reason string, erroredMetricPoints int64) error {
+	scraperAttrs := append(attributesForScraperMetrics(receiver, scraper), attribute.String(metricTag, metric), attribute.String(reasonTag, reason))
+	return pc.checkCounter("scraper_errored_metric_points_by_metric", erroredMetricPoints, scraperAttrs)
+}
+
+func (pc *prometheusChecker) checkScraperBackoffInterval(receiver component.ID, scraper component.ID, backoffInterval int64) error {
+	return pc.checkGauge("scraper_backoff_interval", backoffInterval, attributesForScraperMetrics(receiver, scraper))
+}
+
+func (pc *prometheusChecker) checkScraperTimedOutScrapes(receiver component.ID, scraper component.ID, timedOutScrapes int64) error {
+	return pc.checkCounter("scraper_timed_out_scrapes", timedOutScrapes, attributesForScraperMetrics(receiver, scraper))
+}
+
 func (pc *prometheusChecker) checkReceiverTraces(receiver component.ID, protocol string, accepted, dropped int64) error {
 	return pc.checkReceiver(receiver, "spans", protocol, accepted, dropped)
 }
@@ -51,6 +64,18 @@ func (pc *prometheusChecker) checkReceiver(receiver component.ID, datatype, prot
 		pc.checkCounter(fmt.Sprintf("receiver_refused_%s", datatype), droppedMetricPoints, receiverAttrs))
 }
 
+func (pc *prometheusChecker) checkReceiverByClient(receiver component.ID, datatype, protocol, client string, accepted, refused int64) error {
+	receiverAttrs := append(attributesForReceiverMetrics(receiver, protocol), attribute.String(clientTag, client))
+	return multierr.Combine(
+		pc.checkCounter(fmt.Sprintf("receiver_accepted_%s_by_client", datatype), accepted, receiverAttrs),
+		pc.checkCounter(fmt.Sprintf("receiver_refused_%s_by_client", datatype), refused, receiverAttrs))
+}
+
+func (pc *prometheusChecker) checkReceiverRefusedRequests(receiver component.ID, protocol, reason string, refused int64) error {
+	receiverAttrs := append(attributesForReceiverMetrics(receiver, protocol), attribute.String(reasonTag, reason))
+	return pc.checkCounter("receiver_refused_requests", refused, receiverAttrs)
+}
+
 func (pc *prometheusChecker) checkProcessorTraces(processor component.ID, accepted, refused, dropped int64) error {
 	return pc.checkProcessor(processor, "spans", accepted, refused, dropped)
 }
@@ -71,6 +96,15 @@ func (pc *prometheusChecker) checkProcessor(processor component.ID, datatype str
 		pc.checkCounter(fmt.Sprintf("processor_dropped_%s", datatype), dropped, processorAttrs))
 }
 
+func (pc *prometheusChecker) checkProcessorDurations(processor component.ID, signal component.DataType, processed, awaited int64) error {
+	processorAttrs := append(attributesForProcessorMetrics(processor), attribute.String(signalTag, string(signal)))
+	errs := pc.checkHistogramCount("processor_processing_duration", processed, processorAttrs)
+	if awaited > 0 {
+		errs = multierr.Append(errs, pc.checkHistogramCount("processor_await_duration", awaited, processorAttrs))
+	}
+	return errs
+}
+
 func (pc *prometheusChecker) checkExporterTraces(exporter component.ID, sent, sendFailed int64) error {
 	return pc.checkExporter(exporter, "spans", sent, sendFailed)
 }
@@ -101,6 +135,17 @@ func (pc *prometheusChecker) checkExporterEnqueueFailed(exporter component.ID, d
 	return pc.checkCounter(fmt.Sprintf("exporter_enqueue_failed_%s", datatype), enqueueFailed, exporterAttrs)
 }
 
+func (pc *prometheusChecker) checkExporterRetries(exporter component.ID, requests, attempts, giveUps int64) error {
+	exporterAttrs := attributesForExporterMetrics(exporter)
+	errs := pc.checkHistogram("exporter_retry_attempts", requests, attempts, exporterAttrs)
+	return multierr.Append(errs, pc.checkCounter("exporter_retry_give_ups", giveUps, exporterAttrs))
+}
+
+func (pc *prometheusChecker) checkExporterSendFailures(exporter component.ID, errorCode string, failures int64) error {
+	exporterAttrs := append(attributesForExporterMetrics(exporter), attribute.String(errorCodeTag, errorCode))
+	return pc.checkCounter("exporter_send_failures", failures, exporterAttrs)
+}
+
 func (pc *prometheusChecker) checkExporterMetricGauge(exporter component.ID, metric string, val int64) error {
 	exporterAttrs := attributesForExporterMetrics(exporter)
 	// Forces a flush for the opencensus view data.
@@ -119,6 +164,23 @@ func (pc *prometheusChecker) checkExporterMetricGauge(exporter component.ID, met
 	return nil
 }
 
+func (pc *prometheusChecker) checkGauge(expectedMetric string, value int64, attrs []attribute.KeyValue) error {
+	// Forces a flush for the opencensus view data.
+	_, _ = view.RetrieveData(expectedMetric)
+
+	ts, err := pc.getMetric(expectedMetric, io_prometheus_client.MetricType_GAUGE, attrs)
+	if err != nil {
+		return err
+	}
+
+	expected := float64(value)
+	if math.Abs(expected-ts.GetGauge().GetValue()) > 0.0001 {
+		return fmt.Errorf("values for metric '%s' did not match, expected '%f' got '%f'", expectedMetric, expected, ts.GetGauge().GetValue())
+	}
+
+	return nil
+}
+
 func (pc *prometheusChecker) checkCounter(expectedMetric string, value int64, attrs []attribute.KeyValue) error {
 	// Forces a flush for the opencensus view data.
 	_, _ = view.RetrieveData(expectedMetric)
@@ -136,6 +198,42 @@ func (pc *prometheusChecker) checkCounter(expectedMetric string, value int64, at
 	return nil
 }
 
+func (pc *prometheusChecker) checkHistogram(expectedMetric string, count, sum int64, attrs []attribute.KeyValue) error {
+	// Forces a flush for the opencensus view data.
+	_, _ = view.RetrieveData(expectedMetric)
+
+	ts, err := pc.getMetric(expectedMetric, io_prometheus_client.MetricType_HISTOGRAM, attrs)
+	if err != nil {
+		return err
+	}
+
+	if uint64(count) != ts.GetHistogram().GetSampleCount() {
+		return fmt.Errorf("counts for metric '%s' did not match, expected '%d' got '%d'", expectedMetric, count, ts.GetHistogram().GetSampleCount())
+	}
+	expected := float64(sum)
+	if math.Abs(expected-ts.GetHistogram().GetSampleSum()) > 0.0001 {
+		return fmt.Errorf("sums for metric '%s' did not match, expected '%f' got '%f'", expectedMetric, expected, ts.GetHistogram().GetSampleSum())
+	}
+
+	return nil
+}
+
+// checkHistogramCount checks the number of values recorded in a histogram, whatever their sum.
+func (pc *prometheusChecker) checkHistogramCount(expectedMetric string, count int64, attrs []attribute.KeyValue) error {
+	// Forces a flush for the opencensus view data.
+	_, _ = view.RetrieveData(expectedMetric)
+
+	ts, err := pc.getMetric(expectedMetric, io_prometheus_client.MetricType_HISTOGRAM, attrs)
+	if err != nil {
+		return err
+	}
+
+	if uint64(count) != ts.GetHistogram().GetSampleCount() {
+		return fmt.Errorf("counts for metric '%s' did not match, expected '%d' got '%d'", expectedMetric, count, ts.GetHistogram().GetSampleCount())
+	}
+	return nil
+}
+
 // getMetric returns the metric time series that matches the given name, type and set of attributes
 // it fetches data from the prometheus endpoint and parse them, ideally OTel Go should provide a MeterRecorder of some kind.
 func (pc *prometheusChecker) getMetric(expectedName string, expectedType io_prometheus_client.MetricType, expectedAttrs []attribute.KeyValue) (*io_prometheus_client.Metric, error) {
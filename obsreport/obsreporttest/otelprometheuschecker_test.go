@@ -119,4 +119,24 @@ func TestPromChecker(t *testing.T) {
 		pc.checkExporterLogs(exporter, 103, 36),
 		"metrics from Exporter Logs should be valid",
 	)
+
+	assert.NoError(t,
+		pc.checkExporterRetries(exporter, 5, 8, 1),
+		"metrics from Exporter Retries should be valid",
+	)
+
+	assert.Error(t,
+		pc.checkExporterRetries(exporter, 5, 9, 1),
+		"invalid histogram sum should return error",
+	)
+
+	assert.NoError(t,
+		pc.checkExporterSendFailures(exporter, "timeout", 4),
+		"metrics from Exporter Send Failures should be valid",
+	)
+
+	assert.Error(t,
+		pc.checkExporterSendFailures(exporter, "network", 4),
+		"send failures with a different error code should return error",
+	)
 }
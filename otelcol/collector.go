@@ -70,6 +70,10 @@ type CollectorSettings struct {
 	// If the provider watches for configuration change, collector may reload the new configuration upon changes.
 	ConfigProvider ConfigProvider
 
+	// ConfigConverters are applied in the given order to the configuration resolved by the default ConfigProvider,
+	// used when ConfigProvider is not set, after the default converters, e.g. to rewrite legacy keys.
+	ConfigConverters []confmap.Converter
+
 	// LoggingOptions provides a way to change behavior of zap logging.
 	LoggingOptions []zap.Option
 
@@ -94,6 +98,8 @@ type Collector struct {
 
 	service *service.Service
 	state   *atomic.Int32
+	// cfg is the configuration of the running service, nil if the service is not running.
+	cfg *Config
 
 	// shutdownChan is used to terminate the collector.
 	shutdownChan chan struct{}
@@ -161,13 +167,29 @@ func (col *Collector) setupConfigurationComponents(ctx context.Context) error {
 	}
 	cfg, err := col.set.ConfigProvider.Get(ctx, factories)
 	if err != nil {
-		return fmt.Errorf("failed to get config: %w", err)
+		err = fmt.Errorf("failed to get config: %w", err)
+	} else {
+		err = col.startService(ctx, conf, cfg, factories)
+	}
+	if notifier, ok := col.set.ConfigProvider.(ConfigAppliedNotifier); ok {
+		notifier.NotifyApplied(ctx, err)
+	}
+	if err != nil {
+		return err
 	}
+	col.setCollectorState(StateRunning)
 
-	if err = cfg.Validate(); err != nil {
+	return nil
+}
+
+// startService validates the config, and creates and starts the service with it.
+func (col *Collector) startService(ctx context.Context, conf *confmap.Conf, cfg *Config, factories Factories) error {
+	if err := cfg.Validate(); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	col.cfg = nil
+	var err error
 	col.service, err = service.New(ctx, service.Settings{
 		BuildInfo:         col.set.BuildInfo,
 		CollectorConf:     conf,
@@ -190,12 +212,15 @@ func (col *Collector) setupConfigurationComponents(ctx context.Context) error {
 	if err = col.service.Start(ctx); err != nil {
 		return multierr.Combine(err, col.service.Shutdown(ctx))
 	}
-	col.setCollectorState(StateRunning)
-
+	col.cfg = cfg
 	return nil
 }
 
 func (col *Collector) reloadConfiguration(ctx context.Context) error {
+	if col.resizeQueues(ctx) {
+		return nil
+	}
+
 	col.service.Logger().Warn("Config updated, restart service")
 	col.setCollectorState(StateClosing)
 
@@ -210,6 +235,8 @@ func (col *Collector) reloadConfiguration(ctx context.Context) error {
 	return nil
 }
 
+// DryRun validates the configuration of the collector without running it: the configuration of each
+// component is validated, and the components and pipelines are built, without being started.
 func (col *Collector) DryRun(ctx context.Context) error {
 	factories, err := col.set.Factories()
 	if err != nil {
@@ -220,7 +247,19 @@ func (col *Collector) DryRun(ctx context.Context) error {
 		return fmt.Errorf("failed to get config: %w", err)
 	}
 
-	return cfg.Validate()
+	if err = cfg.Validate(); err != nil {
+		return err
+	}
+
+	// Build the components, without starting them, to run the checks requiring the factories.
+	return service.Validate(ctx, service.Settings{
+		BuildInfo:  col.set.BuildInfo,
+		Receivers:  receiver.NewBuilder(cfg.Receivers, factories.Receivers),
+		Processors: processor.NewBuilder(cfg.Processors, factories.Processors),
+		Exporters:  exporter.NewBuilder(cfg.Exporters, factories.Exporters),
+		Connectors: connector.NewBuilder(cfg.Connectors, factories.Connectors),
+		Extensions: extension.NewBuilder(cfg.Extensions, factories.Extensions),
+	}, cfg.Service)
 }
 
 // Run starts the collector according to the given configuration, and waits for it to complete.
@@ -7,6 +7,7 @@ package otelcol
 import (
 	"context"
 	"errors"
+	"fmt"
 	"path/filepath"
 	"sync"
 	"syscall"
@@ -19,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/converter/expandconverter"
+	"go.opentelemetry.io/collector/confmap/provider/fileprovider"
 	"go.opentelemetry.io/collector/extension/extensiontest"
 	"go.opentelemetry.io/collector/processor/processortest"
 )
@@ -439,6 +441,68 @@ func TestPassConfmapToServiceFailure(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestCollectorNotifyConfigApplied(t *testing.T) {
+	tests := []struct {
+		file    string
+		applied string
+	}{
+		{file: "otelcol-nop.yaml", applied: "<nil>"},
+		{file: "otelcol-invalid.yaml", applied: "invalid configuration: service::pipelines::traces: references processor \"invalid\" which is not configured"},
+		{file: "otelcol-invalid-components.yaml", applied: "failed to get config: cannot unmarshal the configuration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			provider := &appliedProvider{Provider: fileprovider.New()}
+			cfgProvider, err := NewConfigProvider(ConfigProviderSettings{
+				ResolverSettings: confmap.ResolverSettings{
+					URIs:      []string{filepath.Join("testdata", tt.file)},
+					Providers: makeMapProvidersMap(provider),
+				},
+			})
+			require.NoError(t, err)
+			col, err := NewCollector(CollectorSettings{
+				BuildInfo:      component.NewDefaultBuildInfo(),
+				Factories:      nopFactories,
+				ConfigProvider: cfgProvider,
+			})
+			require.NoError(t, err)
+
+			if tt.applied != "<nil>" {
+				assert.Error(t, col.Run(context.Background()))
+			} else {
+				wg := startCollector(context.Background(), t, col)
+				assert.Eventually(t, func() bool {
+					return StateRunning == col.GetState()
+				}, 2*time.Second, 200*time.Millisecond)
+				col.Shutdown()
+				wg.Wait()
+			}
+			require.Len(t, provider.applied, 1)
+			assert.Contains(t, fmt.Sprint(provider.applied[0]), tt.applied)
+		})
+	}
+}
+
+// appliedProvider records the results of applying the configurations retrieved by the wrapped provider.
+type appliedProvider struct {
+	confmap.Provider
+	applied []error
+}
+
+func (ap *appliedProvider) Retrieve(ctx context.Context, uri string, watcher confmap.WatcherFunc) (*confmap.Retrieved, error) {
+	ret, err := ap.Provider.Retrieve(ctx, uri, watcher)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := ret.AsRaw()
+	if err != nil {
+		return nil, err
+	}
+	return confmap.NewRetrieved(raw, confmap.WithRetrievedApplied(func(_ context.Context, err error) {
+		ap.applied = append(ap.applied, err)
+	}))
+}
+
 func startCollector(ctx context.Context, t *testing.T, col *Collector) *sync.WaitGroup {
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
@@ -6,12 +6,18 @@ package otelcol // import "go.opentelemetry.io/collector/otelcol"
 import (
 	"errors"
 	"flag"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"go.opentelemetry.io/collector/confmap/provider/fileprovider"
 	"go.opentelemetry.io/collector/featuregate"
 )
 
+// configWatchDebounce is the duration the config files must not change for before the collector is
+// reloaded, when the config files are watched.
+const configWatchDebounce = time.Second
+
 // NewCommand constructs a new cobra.Command using the given CollectorSettings.
 func NewCommand(set CollectorSettings) *cobra.Command {
 	flagSet := flags(featuregate.GlobalRegistry())
@@ -29,22 +35,36 @@ func NewCommand(set CollectorSettings) *cobra.Command {
 	}
 	rootCmd.AddCommand(newComponentsCommand(set))
 	rootCmd.AddCommand(newValidateSubCommand(set, flagSet))
+	rootCmd.AddCommand(newPrintConfigSubCommand(set, flagSet))
 	rootCmd.Flags().AddGoFlagSet(flagSet)
 	return rootCmd
 }
 
 func newCollectorWithFlags(set CollectorSettings, flags *flag.FlagSet) (*Collector, error) {
 	if set.ConfigProvider == nil {
-		configFlags := getConfigFlag(flags)
-		if len(configFlags) == 0 {
-			return nil, errors.New("at least one config flag must be provided")
-		}
-
 		var err error
-		set.ConfigProvider, err = NewConfigProvider(newDefaultConfigProviderSettings(configFlags))
+		set.ConfigProvider, err = newConfigProviderWithFlags(set, flags)
 		if err != nil {
 			return nil, err
 		}
 	}
 	return NewCollector(set)
 }
+
+// newConfigProviderWithFlags returns the default ConfigProvider configured with the given flags, and the
+// converters of the given CollectorSettings.
+func newConfigProviderWithFlags(set CollectorSettings, flags *flag.FlagSet) (ConfigProvider, error) {
+	configFlags := getConfigFlag(flags)
+	if len(configFlags) == 0 {
+		return nil, errors.New("at least one config flag must be provided")
+	}
+
+	cfgSet := newDefaultConfigProviderSettings(configFlags)
+	cfgSet.ResolverSettings.ListMergeStrategy = getConfigMergeListsFlag(flags)
+	cfgSet.ResolverSettings.Converters = append(cfgSet.ResolverSettings.Converters, set.ConfigConverters...)
+	if getConfigWatchFlag(flags) {
+		fp := fileprovider.New(fileprovider.WithWatch(configWatchDebounce))
+		cfgSet.ResolverSettings.Providers[fp.Scheme()] = fp
+	}
+	return NewConfigProvider(cfgSet)
+}
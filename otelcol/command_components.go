@@ -4,17 +4,40 @@
 package otelcol // import "go.opentelemetry.io/collector/otelcol"
 
 import (
+	"encoding"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
 	"gopkg.in/yaml.v3"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
 )
 
 type componentWithStability struct {
 	Name      component.Type
 	Stability map[string]string
+	// DefaultConfig and ConfigSchema are only set with the --config-schema flag. The DefaultConfig is not a
+	// map, for the empty default configs to be output.
+	DefaultConfig any           `yaml:"default_config,omitempty"`
+	ConfigSchema  []configField `yaml:"config_schema,omitempty"`
+}
+
+// configField is the schema of a field of the configuration of a component.
+type configField struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// Default is the default value of the field, nil for the structs whose fields have the default values.
+	Default any `yaml:"default,omitempty"`
+	// Deprecated is the deprecation note of the "deprecated" tag of the field, if any.
+	Deprecated string `yaml:"deprecated,omitempty"`
+	// Fields are the fields of the structs, or of the elements of the lists and maps of structs.
+	Fields []configField `yaml:"fields,omitempty"`
 }
 
 type componentsOutput struct {
@@ -28,12 +51,18 @@ type componentsOutput struct {
 
 // newComponentsCommand constructs a new components command using the given CollectorSettings.
 func newComponentsCommand(set CollectorSettings) *cobra.Command {
-	return &cobra.Command{
+	var configSchema bool
+	var output string
+	componentsCmd := &cobra.Command{
 		Use:   "components",
 		Short: "Outputs available components in this collector distribution",
-		Long:  "Outputs available components in this collector distribution including their stability levels. The output format is not stable and can change between releases.",
-		Args:  cobra.ExactArgs(0),
+		Long: "Outputs available components in this collector distribution including their stability levels, and optionally " +
+			"their default configuration and configuration schema. The output format is not stable and can change between releases.",
+		Args: cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "yaml" && output != "json" {
+				return fmt.Errorf("unsupported output format %q, must be yaml or json", output)
+			}
 
 			factories, err := set.Factories()
 			if err != nil {
@@ -97,13 +126,158 @@ func newComponentsCommand(set CollectorSettings) *cobra.Command {
 					},
 				})
 			}
+			if configSchema {
+				if err = multierr.Combine(
+					setConfigSchemas(components.Receivers, factories.Receivers),
+					setConfigSchemas(components.Processors, factories.Processors),
+					setConfigSchemas(components.Exporters, factories.Exporters),
+					setConfigSchemas(components.Connectors, factories.Connectors),
+					setConfigSchemas(components.Extensions, factories.Extensions),
+				); err != nil {
+					return err
+				}
+			}
 			components.BuildInfo = set.BuildInfo
 			yamlData, err := yaml.Marshal(components)
 			if err != nil {
 				return err
 			}
+			if output == "json" {
+				// The JSON output is converted from the YAML output, to have the same keys.
+				var data any
+				if err = yaml.Unmarshal(yamlData, &data); err != nil {
+					return err
+				}
+				jsonData, err := json.MarshalIndent(data, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(jsonData))
+				return nil
+			}
 			fmt.Fprint(cmd.OutOrStdout(), string(yamlData))
 			return nil
 		},
 	}
+	componentsCmd.Flags().BoolVar(&configSchema, "config-schema", false,
+		"Output the default configuration and the configuration schema of the components.")
+	componentsCmd.Flags().StringVar(&output, "output", "yaml", "Output format, yaml or json.")
+	return componentsCmd
+}
+
+// setConfigSchemas sets the default configurations and the configuration schemas of the given components.
+func setConfigSchemas[F component.Factory](components []componentWithStability, factories map[component.Type]F) error {
+	for i := range components {
+		cfg := factories[components[i].Name].CreateDefaultConfig()
+		conf := confmap.New()
+		if err := conf.Marshal(cfg); err != nil {
+			return fmt.Errorf("failed to marshal the default config of %q: %w", components[i].Name, err)
+		}
+		defaultConfig := conf.ToStringMap()
+		components[i].DefaultConfig = defaultConfig
+		components[i].ConfigSchema = configFields(reflect.TypeOf(cfg), defaultConfig, map[reflect.Type]bool{})
+	}
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// configFields returns the schemas of the fields of the given struct type, following the mapstructure tags
+// like the config unmarshaling, with the default values of the given map. The types being visited are
+// tracked to stop at the recursive types.
+func configFields(t reflect.Type, defaults map[string]any, visiting map[reflect.Type]bool) []configField {
+	t = structType(t)
+	if t == nil || visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var fields []configField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		squash := false
+		for _, opt := range strings.Split(opts, ",") {
+			squash = squash || opt == "squash"
+		}
+		if squash {
+			fields = append(fields, configFields(f.Type, defaults, visiting)...)
+			continue
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		field := configField{
+			Name:       name,
+			Type:       schemaType(f.Type),
+			Deprecated: f.Tag.Get("deprecated"),
+		}
+		if st := structType(f.Type); st != nil {
+			// The defaults of the structs are set on their fields.
+			subDefaults, _ := defaults[name].(map[string]any)
+			field.Fields = configFields(st, subDefaults, visiting)
+		} else {
+			field.Default = defaults[name]
+			if ft := derefType(f.Type); ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array || ft.Kind() == reflect.Map {
+				field.Fields = configFields(ft.Elem(), nil, visiting)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// structType returns the given type without the pointers if it is a struct, or nil otherwise. The structs
+// unmarshaled from text are not considered as structs.
+func structType(t reflect.Type) reflect.Type {
+	t = derefType(t)
+	if t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return nil
+	}
+	return t
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// schemaType returns the name of the type of the values the given type is unmarshaled from.
+func schemaType(t reflect.Type) string {
+	t = derefType(t)
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return "duration"
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "[]" + schemaType(t.Elem())
+	case reflect.Map:
+		return "map[" + schemaType(t.Key()) + "]" + schemaType(t.Elem())
+	case reflect.Struct:
+		return "object"
+	default:
+		return "any"
+	}
 }
@@ -5,15 +5,19 @@ package otelcol
 
 import (
 	"bytes"
+	"encoding/json"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/receiver"
 )
 
 func TestNewBuildSubCommand(t *testing.T) {
@@ -89,3 +93,114 @@ func TestNewBuildSubCommand(t *testing.T) {
 	// line that makes the test fail.
 	assert.Equal(t, strings.Trim(string(ExpectedOutput), "\n"), strings.Trim(b.String(), "\n"))
 }
+
+// SchemaEmbeddedConfig is exported, like the embedded configs which are unmarshaled.
+type SchemaEmbeddedConfig struct {
+	Name string `mapstructure:"name"`
+}
+
+type schemaNestedConfig struct {
+	Count int `mapstructure:"count"`
+}
+
+type schemaConfig struct {
+	SchemaEmbeddedConfig `mapstructure:",squash"`
+	Endpoint             string                        `mapstructure:"endpoint"`
+	OldEndpoint          string                        `mapstructure:"old_endpoint" deprecated:"use endpoint instead"`
+	Timeout              time.Duration                 `mapstructure:"timeout"`
+	Enabled              bool                          `mapstructure:"enabled"`
+	Tags                 []string                      `mapstructure:"tags"`
+	Nested               *schemaNestedConfig           `mapstructure:"nested"`
+	Named                map[string]schemaNestedConfig `mapstructure:"named"`
+	Level                configtelemetry.Level         `mapstructure:"level"`
+	private              int
+}
+
+func schemaFactories() (Factories, error) {
+	factories, err := nopFactories()
+	if err != nil {
+		return Factories{}, err
+	}
+	factories.Receivers["schema"] = receiver.NewFactory("schema", func() component.Config {
+		return &schemaConfig{
+			SchemaEmbeddedConfig: SchemaEmbeddedConfig{Name: "default"},
+			Endpoint:             "localhost:4317",
+			Enabled:              true,
+			Nested:               &schemaNestedConfig{Count: 2},
+			Level:                configtelemetry.LevelBasic,
+		}
+	})
+	return factories, nil
+}
+
+func TestComponentsSubCommandConfigSchema(t *testing.T) {
+	cmd := NewCommand(CollectorSettings{BuildInfo: component.NewDefaultBuildInfo(), Factories: schemaFactories})
+	cmd.SetArgs([]string{"components", "--config-schema"})
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.NoError(t, cmd.Execute())
+
+	var output componentsOutput
+	require.NoError(t, yaml.Unmarshal(b.Bytes(), &output))
+	var schemaReceiver componentWithStability
+	for _, rcv := range output.Receivers {
+		if rcv.Name == "schema" {
+			schemaReceiver = rcv
+		}
+	}
+	assert.Equal(t, map[string]any{
+		"name":         "default",
+		"endpoint":     "localhost:4317",
+		"old_endpoint": "",
+		"timeout":      "0s",
+		"enabled":      true,
+		"tags":         []any{},
+		"nested":       map[string]any{"count": 2},
+		"named":        map[string]any{},
+		"level":        "Basic",
+	}, schemaReceiver.DefaultConfig)
+	assert.Equal(t, []configField{
+		{Name: "name", Type: "string", Default: "default"},
+		{Name: "endpoint", Type: "string", Default: "localhost:4317"},
+		{Name: "old_endpoint", Type: "string", Default: "", Deprecated: "use endpoint instead"},
+		{Name: "timeout", Type: "duration", Default: "0s"},
+		{Name: "enabled", Type: "bool", Default: true},
+		{Name: "tags", Type: "[]string", Default: []any{}},
+		{Name: "nested", Type: "object", Fields: []configField{{Name: "count", Type: "int", Default: 2}}},
+		{Name: "named", Type: "map[string]object", Default: map[string]any{}, Fields: []configField{{Name: "count", Type: "int"}}},
+		{Name: "level", Type: "string", Default: "Basic"},
+	}, schemaReceiver.ConfigSchema)
+
+	// The nop components have an empty config.
+	require.Len(t, output.Exporters, 1)
+	assert.Equal(t, map[string]any{}, output.Exporters[0].DefaultConfig)
+	assert.Empty(t, output.Exporters[0].ConfigSchema)
+}
+
+func TestComponentsSubCommandJSONOutput(t *testing.T) {
+	cmd := NewCommand(CollectorSettings{BuildInfo: component.NewDefaultBuildInfo(), Factories: schemaFactories})
+	cmd.SetArgs([]string{"components", "--config-schema", "--output", "json"})
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.NoError(t, cmd.Execute())
+
+	var output map[string]any
+	require.NoError(t, json.Unmarshal(b.Bytes(), &output))
+	assert.Equal(t, map[string]any{
+		"command":     component.NewDefaultBuildInfo().Command,
+		"description": component.NewDefaultBuildInfo().Description,
+		"version":     component.NewDefaultBuildInfo().Version,
+	}, output["buildinfo"])
+	require.Len(t, output["exporters"], 1)
+	assert.Equal(t, map[string]any{
+		"name":           "nop",
+		"stability":      map[string]any{"logs": "Stable", "metrics": "Stable", "traces": "Stable"},
+		"default_config": map[string]any{},
+	}, output["exporters"].([]any)[0])
+}
+
+func TestComponentsSubCommandInvalidOutput(t *testing.T) {
+	cmd := NewCommand(CollectorSettings{BuildInfo: component.NewDefaultBuildInfo(), Factories: nopFactories})
+	cmd.SetArgs([]string{"components", "--output", "xml"})
+	assert.ErrorContains(t, cmd.Execute(), `unsupported output format "xml"`)
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol // import "go.opentelemetry.io/collector/otelcol"
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"go.opentelemetry.io/collector/confmap"
+)
+
+const (
+	// defaultSource annotates the values not set by any config, e.g. the default values of the components.
+	defaultSource = "default"
+)
+
+// newPrintConfigSubCommand constructs a new print-config sub command using the given CollectorSettings.
+func newPrintConfigSubCommand(set CollectorSettings, flagSet *flag.FlagSet) *cobra.Command {
+	var sources bool
+	printConfigCmd := &cobra.Command{
+		Use:   "print-config",
+		Short: "Prints the effective config without running the collector",
+		Long: "Prints the effective config, once the config providers, the converters and the default values of the components are applied, " +
+			"with the sensitive values redacted. The output format is not stable and can change between releases.",
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if set.ConfigProvider == nil {
+				var err error
+				set.ConfigProvider, err = newConfigProviderWithFlags(set, flagSet)
+				if err != nil {
+					return err
+				}
+			}
+			factories, err := set.Factories()
+			if err != nil {
+				return fmt.Errorf("failed to initialize factories: %w", err)
+			}
+			yamlData, err := printConfig(cmd.Context(), set.ConfigProvider, factories, sources)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(yamlData))
+			return nil
+		},
+	}
+	printConfigCmd.Flags().BoolVar(&sources, "sources", false,
+		"Annotate the values with the config they were set by, or \"default\" when set by none.")
+	printConfigCmd.Flags().AddGoFlagSet(flagSet)
+	return printConfigCmd
+}
+
+// printConfig returns the effective config of the given ConfigProvider as YAML, annotated with the sources
+// of the values if requested.
+func printConfig(ctx context.Context, cfgProvider ConfigProvider, factories Factories, sources bool) (out []byte, err error) {
+	defer func() {
+		if shutdownErr := cfgProvider.Shutdown(ctx); err == nil && shutdownErr != nil {
+			err = fmt.Errorf("failed to shutdown the config provider: %w", shutdownErr)
+		}
+	}()
+
+	cfg, err := cfgProvider.Get(ctx, factories)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	// The config is marshaled from the components configs rather than taken from the resolved config, to
+	// include the default values and redact the configopaque values.
+	conf := confmap.New()
+	if err = conf.Marshal(map[string]any{
+		"receivers":  cfg.Receivers,
+		"processors": cfg.Processors,
+		"exporters":  cfg.Exporters,
+		"connectors": cfg.Connectors,
+		"extensions": cfg.Extensions,
+		"service":    cfg.Service,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	// The resolved config tells the values expanded from opaque values, and the sources of the values.
+	resolved := confmap.New()
+	if cp, ok := cfgProvider.(ConfmapProvider); ok {
+		if resolved, err = cp.GetConfmap(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get config: %w", err)
+		}
+	} else if sources {
+		return nil, errors.New("the config provider does not support the sources of the config")
+	}
+
+	var node yaml.Node
+	if err = node.Encode(conf.ToStringMap()); err != nil {
+		return nil, err
+	}
+	annotateNode(&node, "", resolved, sources)
+	return yaml.Marshal(&node)
+}
+
+// annotateNode redacts the values of the mapping node, at the given key, expanded from opaque values, and
+// annotates them with their sources if requested, recursively.
+func annotateNode(node *yaml.Node, key string, resolved *confmap.Conf, sources bool) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		valueKey := keyNode.Value
+		if key != "" {
+			valueKey = key + confmap.KeyDelimiter + valueKey
+		}
+		if resolved.IsOpaque(valueKey) {
+			*valueNode = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: confmap.RedactedValue}
+		}
+		if valueNode.Kind == yaml.MappingNode && len(valueNode.Content) > 0 {
+			annotateNode(valueNode, valueKey, resolved, sources)
+			continue
+		}
+		if !sources {
+			continue
+		}
+		source := resolved.Source(valueKey)
+		if source == "" {
+			source = defaultSource
+		}
+		// The comments of the keys of the block sequences are emitted on the line of the key, while those of
+		// the keys of the scalars and the empty collections are emitted on the line of the parent key.
+		if valueNode.Kind == yaml.SequenceNode && len(valueNode.Content) > 0 {
+			keyNode.LineComment = source
+		} else {
+			valueNode.LineComment = source
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/featuregate"
+)
+
+func TestPrintConfigSubCommandNoConfig(t *testing.T) {
+	cmd := newPrintConfigSubCommand(CollectorSettings{Factories: nopFactories}, flags(featuregate.GlobalRegistry()))
+	err := cmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "at least one config flag must be provided")
+}
+
+func TestPrintConfigSubCommand(t *testing.T) {
+	var out bytes.Buffer
+	cmd := newPrintConfigSubCommand(CollectorSettings{Factories: nopFactories}, flags(featuregate.NewRegistry()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--config=" + filepath.Join("testdata", "otelcol-printconfig.yaml")})
+	require.NoError(t, cmd.Execute())
+
+	assert.Contains(t, out.String(), "address: localhost:8888\n")
+	// The default values are printed.
+	assert.Contains(t, out.String(), "level: info\n")
+	// The values expanded from secrets are redacted.
+	assert.Contains(t, out.String(), "api_key: '[REDACTED]'\n")
+	assert.NotContains(t, out.String(), "s3cr3t")
+	assert.NotContains(t, out.String(), "#")
+}
+
+func TestPrintConfigSubCommandSources(t *testing.T) {
+	var out bytes.Buffer
+	cmd := newPrintConfigSubCommand(CollectorSettings{Factories: nopFactories}, flags(featuregate.NewRegistry()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{
+		"--config=" + filepath.Join("testdata", "otelcol-printconfig.yaml"),
+		"--set=service.telemetry.logs.level=debug",
+		"--sources",
+	})
+	require.NoError(t, cmd.Execute())
+
+	file := "file:" + filepath.Join("testdata", "otelcol-printconfig.yaml")
+	assert.Contains(t, out.String(), "address: localhost:8888 # "+file+"\n")
+	assert.Contains(t, out.String(), "api_key: '[REDACTED]' # "+file+"\n")
+	assert.Contains(t, out.String(), "exporters: # "+file+"\n")
+	assert.Contains(t, out.String(), "level: debug # yaml:service::telemetry::logs::level: debug\n")
+	assert.Contains(t, out.String(), "encoding: console # default\n")
+}
+
+type converterFunc func(context.Context, *confmap.Conf) error
+
+func (f converterFunc) Convert(ctx context.Context, conf *confmap.Conf) error {
+	return f(ctx, conf)
+}
+
+func TestPrintConfigSubCommandConfigConverters(t *testing.T) {
+	var levels []any
+	set := CollectorSettings{
+		Factories: nopFactories,
+		ConfigConverters: []confmap.Converter{
+			converterFunc(func(_ context.Context, conf *confmap.Conf) error {
+				levels = append(levels, conf.Get("service::telemetry::logs::level"))
+				return conf.Merge(confmap.NewFromStringMap(map[string]any{"service::telemetry::logs::level": "warn"}))
+			}),
+			// The converters are applied in order.
+			converterFunc(func(_ context.Context, conf *confmap.Conf) error {
+				levels = append(levels, conf.Get("service::telemetry::logs::level"))
+				return conf.Merge(confmap.NewFromStringMap(map[string]any{"service::telemetry::logs::level": "debug"}))
+			}),
+		},
+	}
+
+	var out bytes.Buffer
+	cmd := newPrintConfigSubCommand(set, flags(featuregate.NewRegistry()))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--config=" + filepath.Join("testdata", "otelcol-printconfig.yaml")})
+	require.NoError(t, cmd.Execute())
+
+	assert.Contains(t, out.String(), "level: debug\n")
+	assert.Equal(t, []any{nil, "warn"}, levels[:2])
+}
@@ -4,16 +4,23 @@
 package otelcol
 
 import (
+	"context"
+	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/converter/expandconverter"
 	"go.opentelemetry.io/collector/confmap/provider/fileprovider"
+	"go.opentelemetry.io/collector/featuregate"
 )
 
 func TestNewCommandVersion(t *testing.T) {
@@ -40,3 +47,39 @@ func TestNewCommandInvalidComponent(t *testing.T) {
 	cmd := NewCommand(CollectorSettings{Factories: nopFactories, ConfigProvider: cfgProvider})
 	require.Error(t, cmd.Execute())
 }
+
+func TestCollectorWithConfigWatch(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "otelcol-nop.yaml"))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, content, 0600))
+
+	var reloaded atomic.Bool
+	flgs := flags(featuregate.NewRegistry())
+	require.NoError(t, flgs.Parse([]string{"--config=file:" + path, "--config-watch"}))
+	col, err := newCollectorWithFlags(CollectorSettings{
+		BuildInfo: component.NewDefaultBuildInfo(),
+		Factories: nopFactories,
+		LoggingOptions: []zap.Option{zap.Hooks(func(entry zapcore.Entry) error {
+			if entry.Message == "Config updated, restart service" {
+				reloaded.Store(true)
+			}
+			return nil
+		})},
+	}, flgs)
+	require.NoError(t, err)
+
+	wg := startCollector(context.Background(), t, col)
+	assert.Eventually(t, func() bool {
+		return StateRunning == col.GetState()
+	}, 2*time.Second, 200*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, append(content, []byte("# changed\n")...), 0600))
+	assert.Eventually(t, func() bool {
+		return reloaded.Load() && StateRunning == col.GetState()
+	}, 5*time.Second, 200*time.Millisecond)
+
+	col.Shutdown()
+	wg.Wait()
+	assert.Equal(t, StateClosed, col.GetState())
+}
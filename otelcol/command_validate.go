@@ -4,10 +4,12 @@
 package otelcol // import "go.opentelemetry.io/collector/otelcol"
 
 import (
-	"errors"
 	"flag"
+	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
 )
 
 // newValidateSubCommand constructs a new validate sub command using the given CollectorSettings.
@@ -19,13 +21,7 @@ func newValidateSubCommand(set CollectorSettings, flagSet *flag.FlagSet) *cobra.
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if set.ConfigProvider == nil {
 				var err error
-
-				configFlags := getConfigFlag(flagSet)
-				if len(configFlags) == 0 {
-					return errors.New("at least one config flag must be provided")
-				}
-
-				set.ConfigProvider, err = NewConfigProvider(newDefaultConfigProviderSettings(configFlags))
+				set.ConfigProvider, err = newConfigProviderWithFlags(set, flagSet)
 				if err != nil {
 					return err
 				}
@@ -34,9 +30,22 @@ func newValidateSubCommand(set CollectorSettings, flagSet *flag.FlagSet) *cobra.
 			if err != nil {
 				return err
 			}
-			return col.DryRun(cmd.Context())
+			return validationError(col.DryRun(cmd.Context()))
 		},
 	}
 	validateCmd.Flags().AddGoFlagSet(flagSet)
 	return validateCmd
 }
+
+// validationError returns the given error listing all the errors found, one per line, when there are several.
+func validationError(err error) error {
+	errs := multierr.Errors(err)
+	if len(errs) <= 1 {
+		return err
+	}
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return fmt.Errorf("%d errors found in the configuration:\n%s", len(errs), strings.Join(msgs, "\n"))
+}
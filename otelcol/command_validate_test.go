@@ -38,3 +38,25 @@ func TestValidateSubCommandInvalidComponents(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "unknown type: \"nosuchprocessor\"")
 }
+
+func TestValidateSubCommandMultipleErrors(t *testing.T) {
+	cfgProvider, err := NewConfigProvider(newDefaultConfigProviderSettings([]string{filepath.Join("testdata", "otelcol-invalid-multiple.yaml")}))
+	require.NoError(t, err)
+
+	cmd := newValidateSubCommand(CollectorSettings{Factories: nopFactories, ConfigProvider: cfgProvider}, flags(featuregate.GlobalRegistry()))
+	err = cmd.Execute()
+	require.EqualError(t, err, `3 errors found in the configuration:
+service::extensions: references extension "invalid" which is not configured
+service::pipelines::metrics: references receiver "invalid" which is not configured
+service::pipelines::traces: references processor "invalid" which is not configured`)
+}
+
+func TestValidateSubCommandInvalidPipelines(t *testing.T) {
+	cfgProvider, err := NewConfigProvider(newDefaultConfigProviderSettings([]string{filepath.Join("testdata", "otelcol-invalid-pipelines.yaml")}))
+	require.NoError(t, err)
+
+	cmd := newValidateSubCommand(CollectorSettings{Factories: nopFactories, ConfigProvider: cfgProvider}, flags(featuregate.GlobalRegistry()))
+	err = cmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `failed to build pipelines: connector "nop/con" used as exporter in traces pipeline but not used in any supported receiver pipeline`)
+}
@@ -6,6 +6,9 @@ package otelcol // import "go.opentelemetry.io/collector/otelcol"
 import (
 	"errors"
 	"fmt"
+	"sort"
+
+	"go.uber.org/multierr"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/service"
@@ -36,7 +39,7 @@ type Config struct {
 	Service service.Config
 }
 
-// Validate returns an error if the config is invalid.
+// Validate returns an error if the config is invalid, combining all the errors found, see multierr.Errors.
 //
 // This function performs basic validation of configuration. There may be more subtle
 // invalid cases that we currently don't check for but which we may want to add in
@@ -48,72 +51,75 @@ func (cfg *Config) Validate() error {
 		return errMissingReceivers
 	}
 
-	// Validate the receiver configuration.
-	for recvID, recvCfg := range cfg.Receivers {
-		if err := component.ValidateConfig(recvCfg); err != nil {
-			return fmt.Errorf("receivers::%s: %w", recvID, err)
-		}
-	}
-
 	// Currently, there is no default exporter enabled.
 	// The configuration must specify at least one exporter to be valid.
 	if len(cfg.Exporters) == 0 {
 		return errMissingExporters
 	}
 
+	var errs error
+
+	// Validate the receiver configuration.
+	for _, recvID := range sortedIDs(cfg.Receivers) {
+		if err := component.ValidateConfig(cfg.Receivers[recvID]); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("receivers::%s: %w", recvID, err))
+		}
+	}
+
 	// Validate the exporter configuration.
-	for expID, expCfg := range cfg.Exporters {
-		if err := component.ValidateConfig(expCfg); err != nil {
-			return fmt.Errorf("exporters::%s: %w", expID, err)
+	for _, expID := range sortedIDs(cfg.Exporters) {
+		if err := component.ValidateConfig(cfg.Exporters[expID]); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("exporters::%s: %w", expID, err))
 		}
 	}
 
 	// Validate the processor configuration.
-	for procID, procCfg := range cfg.Processors {
-		if err := component.ValidateConfig(procCfg); err != nil {
-			return fmt.Errorf("processors::%s: %w", procID, err)
+	for _, procID := range sortedIDs(cfg.Processors) {
+		if err := component.ValidateConfig(cfg.Processors[procID]); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("processors::%s: %w", procID, err))
 		}
 	}
 
 	// Validate the connector configuration.
-	for connID, connCfg := range cfg.Connectors {
-		if err := component.ValidateConfig(connCfg); err != nil {
-			return fmt.Errorf("connectors::%s: %w", connID, err)
+	for _, connID := range sortedIDs(cfg.Connectors) {
+		if err := component.ValidateConfig(cfg.Connectors[connID]); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("connectors::%s: %w", connID, err))
 		}
 
 		if _, ok := cfg.Exporters[connID]; ok {
-			return fmt.Errorf("connectors::%s: ambiguous ID: Found both %q exporter and %q connector. "+
+			errs = multierr.Append(errs, fmt.Errorf("connectors::%s: ambiguous ID: Found both %q exporter and %q connector. "+
 				"Change one of the components' IDs to eliminate ambiguity (e.g. rename %q connector to %q)",
-				connID, connID, connID, connID, connID.String()+"/connector")
+				connID, connID, connID, connID, connID.String()+"/connector"))
 		}
 		if _, ok := cfg.Receivers[connID]; ok {
-			return fmt.Errorf("connectors::%s: ambiguous ID: Found both %q receiver and %q connector. "+
+			errs = multierr.Append(errs, fmt.Errorf("connectors::%s: ambiguous ID: Found both %q receiver and %q connector. "+
 				"Change one of the components' IDs to eliminate ambiguity (e.g. rename %q connector to %q)",
-				connID, connID, connID, connID, connID.String()+"/connector")
+				connID, connID, connID, connID, connID.String()+"/connector"))
 		}
 	}
 
 	// Validate the extension configuration.
-	for extID, extCfg := range cfg.Extensions {
-		if err := component.ValidateConfig(extCfg); err != nil {
-			return fmt.Errorf("extensions::%s: %w", extID, err)
+	for _, extID := range sortedIDs(cfg.Extensions) {
+		if err := component.ValidateConfig(cfg.Extensions[extID]); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("extensions::%s: %w", extID, err))
 		}
 	}
 
 	if err := cfg.Service.Validate(); err != nil {
-		return err
+		errs = multierr.Append(errs, err)
 	}
 
 	// Check that all enabled extensions in the service are configured.
 	for _, ref := range cfg.Service.Extensions {
 		// Check that the name referenced in the Service extensions exists in the top-level extensions.
 		if cfg.Extensions[ref] == nil {
-			return fmt.Errorf("service::extensions: references extension %q which is not configured", ref)
+			errs = multierr.Append(errs, fmt.Errorf("service::extensions: references extension %q which is not configured", ref))
 		}
 	}
 
 	// Check that all pipelines reference only configured components.
-	for pipelineID, pipeline := range cfg.Service.Pipelines {
+	for _, pipelineID := range sortedIDs(cfg.Service.Pipelines) {
+		pipeline := cfg.Service.Pipelines[pipelineID]
 		// Validate pipeline receiver name references.
 		for _, ref := range pipeline.Receivers {
 			// Check that the name referenced in the pipeline's receivers exists in the top-level receivers.
@@ -124,14 +130,14 @@ func (cfg *Config) Validate() error {
 			if _, ok := cfg.Connectors[ref]; ok {
 				continue
 			}
-			return fmt.Errorf("service::pipelines::%s: references receiver %q which is not configured", pipelineID, ref)
+			errs = multierr.Append(errs, fmt.Errorf("service::pipelines::%s: references receiver %q which is not configured", pipelineID, ref))
 		}
 
 		// Validate pipeline processor name references.
 		for _, ref := range pipeline.Processors {
 			// Check that the name referenced in the pipeline's processors exists in the top-level processors.
 			if cfg.Processors[ref] == nil {
-				return fmt.Errorf("service::pipelines::%s: references processor %q which is not configured", pipelineID, ref)
+				errs = multierr.Append(errs, fmt.Errorf("service::pipelines::%s: references processor %q which is not configured", pipelineID, ref))
 			}
 		}
 
@@ -144,8 +150,18 @@ func (cfg *Config) Validate() error {
 			if _, ok := cfg.Connectors[ref]; ok {
 				continue
 			}
-			return fmt.Errorf("service::pipelines::%s: references exporter %q which is not configured", pipelineID, ref)
+			errs = multierr.Append(errs, fmt.Errorf("service::pipelines::%s: references exporter %q which is not configured", pipelineID, ref))
 		}
 	}
-	return nil
+	return errs
+}
+
+// sortedIDs returns the IDs of the given map sorted, so the errors are reported in a stable order.
+func sortedIDs[V any](m map[component.ID]V) []component.ID {
+	ids := make([]component.ID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
 	"go.uber.org/zap/zapcore"
 
 	"go.opentelemetry.io/collector/component"
@@ -177,7 +178,10 @@ func TestConfigValidate(t *testing.T) {
 				pipe.Exporters = append(pipe.Exporters, component.NewIDWithName("nop", "2"))
 				return cfg
 			},
-			expected: errors.New(`connectors::nop/2: ambiguous ID: Found both "nop/2" receiver and "nop/2" connector. Change one of the components' IDs to eliminate ambiguity (e.g. rename "nop/2" connector to "nop/2/connector")`),
+			expected: multierr.Append(multierr.Append(
+				errors.New(`connectors::nop/2: ambiguous ID: Found both "nop/2" receiver and "nop/2" connector. Change one of the components' IDs to eliminate ambiguity (e.g. rename "nop/2" connector to "nop/2/connector")`),
+				errors.New(`service::pipelines::traces: references receiver "nop/2" which is not configured`)),
+				errors.New(`service::pipelines::traces: references exporter "nop/2" which is not configured`)),
 		},
 		{
 			name: "ambiguous-connector-name-as-exporter",
@@ -190,7 +194,10 @@ func TestConfigValidate(t *testing.T) {
 				pipe.Exporters = append(pipe.Exporters, component.NewIDWithName("nop", "2"))
 				return cfg
 			},
-			expected: errors.New(`connectors::nop/2: ambiguous ID: Found both "nop/2" exporter and "nop/2" connector. Change one of the components' IDs to eliminate ambiguity (e.g. rename "nop/2" connector to "nop/2/connector")`),
+			expected: multierr.Append(multierr.Append(
+				errors.New(`connectors::nop/2: ambiguous ID: Found both "nop/2" exporter and "nop/2" connector. Change one of the components' IDs to eliminate ambiguity (e.g. rename "nop/2" connector to "nop/2/connector")`),
+				errors.New(`service::pipelines::traces: references receiver "nop/2" which is not configured`)),
+				errors.New(`service::pipelines::traces: references exporter "nop/2" which is not configured`)),
 		},
 		{
 			name: "invalid-connector-reference-as-receiver",
@@ -212,6 +219,20 @@ func TestConfigValidate(t *testing.T) {
 			},
 			expected: errors.New(`service::pipelines::traces: references exporter "nop/conn2" which is not configured`),
 		},
+		{
+			name: "multiple-errors",
+			cfgFn: func() *Config {
+				cfg := generateConfig()
+				cfg.Receivers[component.NewID("nop")] = &errConfig{validateErr: errInvalidRecvConfig}
+				cfg.Extensions[component.NewID("nop")] = &errConfig{validateErr: errInvalidExtConfig}
+				cfg.Exporters[component.NewID("nop")] = &errConfig{validateErr: errInvalidExpConfig}
+				return cfg
+			},
+			expected: multierr.Append(multierr.Append(
+				fmt.Errorf(`receivers::nop: %w`, errInvalidRecvConfig),
+				fmt.Errorf(`exporters::nop: %w`, errInvalidExpConfig)),
+				fmt.Errorf(`extensions::nop: %w`, errInvalidExtConfig)),
+		},
 		{
 			name: "invalid-service-config",
 			cfgFn: func() *Config {
@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/confmap/provider/fileprovider"
 	"go.opentelemetry.io/collector/confmap/provider/httpprovider"
 	"go.opentelemetry.io/collector/confmap/provider/httpsprovider"
+	"go.opentelemetry.io/collector/confmap/provider/secretfileprovider"
 	"go.opentelemetry.io/collector/confmap/provider/yamlprovider"
 )
 
@@ -63,12 +64,24 @@ type ConfmapProvider interface {
 	GetConfmap(ctx context.Context) (*confmap.Conf, error)
 }
 
+// ConfigAppliedNotifier is an optional interface to be implemented by ConfigProviders to be notified
+// of the result of applying the configuration returned by Get, e.g. to report it to a remote source of
+// the configuration.
+type ConfigAppliedNotifier interface {
+	// NotifyApplied is called with nil once the Collector started with the configuration returned by the
+	// last Get, or with the error preventing the Collector from starting with it.
+	//
+	// Should never be called concurrently with any ConfigProvider method.
+	NotifyApplied(ctx context.Context, err error)
+}
+
 type configProvider struct {
 	mapResolver *confmap.Resolver
 }
 
 var _ ConfigProvider = &configProvider{}
 var _ ConfmapProvider = &configProvider{}
+var _ ConfigAppliedNotifier = &configProvider{}
 
 // ConfigProviderSettings are the settings to configure the behavior of the ConfigProvider.
 type ConfigProviderSettings struct {
@@ -118,6 +131,10 @@ func (cm *configProvider) Watch() <-chan error {
 	return cm.mapResolver.Watch()
 }
 
+func (cm *configProvider) NotifyApplied(ctx context.Context, err error) {
+	cm.mapResolver.NotifyApplied(ctx, err)
+}
+
 func (cm *configProvider) Shutdown(ctx context.Context) error {
 	return cm.mapResolver.Shutdown(ctx)
 }
@@ -134,8 +151,10 @@ func (cm *configProvider) GetConfmap(ctx context.Context) (*confmap.Conf, error)
 func newDefaultConfigProviderSettings(uris []string) ConfigProviderSettings {
 	return ConfigProviderSettings{
 		ResolverSettings: confmap.ResolverSettings{
-			URIs:       uris,
-			Providers:  makeMapProvidersMap(fileprovider.New(), envprovider.New(), yamlprovider.New(), httpprovider.New(), httpsprovider.New()),
+			URIs: uris,
+			Providers: makeMapProvidersMap(fileprovider.New(), envprovider.New(), envprovider.New(envprovider.WithFormat("json")),
+				envprovider.New(envprovider.WithFormat("toml")), yamlprovider.New(), httpprovider.New(), httpsprovider.New(),
+				secretfileprovider.New()),
 			Converters: []confmap.Converter{expandconverter.New()},
 		},
 	}
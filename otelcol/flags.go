@@ -6,18 +6,24 @@ package otelcol // import "go.opentelemetry.io/collector/otelcol"
 import (
 	"errors"
 	"flag"
+	"fmt"
 	"strings"
 
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/featuregate"
 )
 
 const (
-	configFlag = "config"
+	configFlag           = "config"
+	configMergeListsFlag = "config-merge-lists"
+	configWatchFlag      = "config-watch"
 )
 
 type configFlagValue struct {
 	values []string
 	sets   []string
+	// listMergeStrategy is how the lists of the configs are merged.
+	listMergeStrategy confmap.ListMergeStrategy
 }
 
 func (s *configFlagValue) Set(val string) error {
@@ -36,9 +42,27 @@ func flags(reg *featuregate.Registry) *flag.FlagSet {
 	flagSet.Var(cfgs, configFlag, "Locations to the config file(s), note that only a"+
 		" single location can be set per flag entry e.g. `--config=file:/path/to/first --config=file:path/to/second`.")
 
+	flagSet.Func(configMergeListsFlag,
+		"How the lists of the configs are merged, either \"replace\" (default) to replace the lists of the first configs"+
+			" with those of the last ones, or \"append\" to append them. The maps are always merged deeply."+
+			" Example --config-merge-lists=append",
+		func(s string) error {
+			switch strategy := confmap.ListMergeStrategy(s); strategy {
+			case confmap.ListMergeReplace, confmap.ListMergeAppend:
+				cfgs.listMergeStrategy = strategy
+				return nil
+			}
+			return fmt.Errorf("must be %q or %q", confmap.ListMergeReplace, confmap.ListMergeAppend)
+		})
+
+	flagSet.Bool(configWatchFlag, false,
+		"Watch the config files, and reload the collector when they change. The changes are applied"+
+			" once the files were not changed for a second.")
+
 	flagSet.Func("set",
 		"Set arbitrary component config property. The component has to be defined in the config file and the flag"+
-			" has a higher precedence. Array config properties are overridden and maps are joined. Example --set=processors.batch.timeout=2s",
+			" has a higher precedence. Array config properties are overridden, or appended with --config-merge-lists=append,"+
+			" and maps are joined. Example --set=processors.batch.timeout=2s",
 		func(s string) error {
 			idx := strings.Index(s, "=")
 			if idx == -1 {
@@ -57,3 +81,11 @@ func getConfigFlag(flagSet *flag.FlagSet) []string {
 	cfv := flagSet.Lookup(configFlag).Value.(*configFlagValue)
 	return append(cfv.values, cfv.sets...)
 }
+
+func getConfigMergeListsFlag(flagSet *flag.FlagSet) confmap.ListMergeStrategy {
+	return flagSet.Lookup(configFlag).Value.(*configFlagValue).listMergeStrategy
+}
+
+func getConfigWatchFlag(flagSet *flag.FlagSet) bool {
+	return flagSet.Lookup(configWatchFlag).Value.(flag.Getter).Get().(bool)
+}
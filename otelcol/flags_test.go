@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/featuregate"
 )
 
@@ -69,3 +70,55 @@ func TestSetFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigMergeListsFlag(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		expectedStrategy confmap.ListMergeStrategy
+		expectedErr      string
+	}{
+		{
+			name: "default",
+			args: []string{"--config=file:testdata/otelcol-nop.yaml"},
+		},
+		{
+			name:             "append",
+			args:             []string{"--config=file:testdata/otelcol-nop.yaml", "--config-merge-lists=append"},
+			expectedStrategy: confmap.ListMergeAppend,
+		},
+		{
+			name:             "replace",
+			args:             []string{"--config-merge-lists=replace"},
+			expectedStrategy: confmap.ListMergeReplace,
+		},
+		{
+			name:        "invalid",
+			args:        []string{"--config-merge-lists=prepend"},
+			expectedErr: `invalid value "prepend" for flag -config-merge-lists: must be "replace" or "append"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flgs := flags(featuregate.NewRegistry())
+			err := flgs.Parse(tt.args)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStrategy, getConfigMergeListsFlag(flgs))
+		})
+	}
+}
+
+func TestConfigWatchFlag(t *testing.T) {
+	flgs := flags(featuregate.NewRegistry())
+	require.NoError(t, flgs.Parse([]string{"--config=file:testdata/otelcol-nop.yaml"}))
+	assert.False(t, getConfigWatchFlag(flgs))
+
+	flgs = flags(featuregate.NewRegistry())
+	require.NoError(t, flgs.Parse([]string{"--config=file:testdata/otelcol-nop.yaml", "--config-watch"}))
+	assert.True(t, getConfigWatchFlag(flgs))
+}
@@ -22,6 +22,7 @@ func NewConfigs[F component.Factory](factories map[component.Type]F) *Configs[F]
 }
 
 func (c *Configs[F]) Unmarshal(conf *confmap.Conf) error {
+	// The raw configs are unmarshaled to validate the component IDs, and that they are unique.
 	rawCfgs := make(map[component.ID]map[string]any)
 	if err := conf.Unmarshal(&rawCfgs, confmap.WithErrorUnused()); err != nil {
 		return err
@@ -30,7 +31,11 @@ func (c *Configs[F]) Unmarshal(conf *confmap.Conf) error {
 	// Prepare resulting map.
 	c.cfgs = make(map[component.ID]component.Config)
 	// Iterate over raw configs and create a config for each.
-	for id, value := range rawCfgs {
+	for key := range conf.ToStringMap() {
+		var id component.ID
+		// Cannot return error because the keys were unmarshaled as component IDs above.
+		_ = id.UnmarshalText([]byte(key))
+
 		// Find factory based on component kind and type that we read from config source.
 		factory, ok := c.factories[id.Type()]
 		if !ok {
@@ -40,9 +45,15 @@ func (c *Configs[F]) Unmarshal(conf *confmap.Conf) error {
 		// Create the default config for this component.
 		cfg := factory.CreateDefaultConfig()
 
+		// The sub-config keeps the settings of the Conf, e.g. its strict types.
+		sub, err := conf.Sub(key)
+		if err != nil {
+			return errorUnmarshalError(id, err)
+		}
+
 		// Now that the default config struct is created we can Unmarshal into it,
 		// and it will apply user-defined config on top of the default.
-		if err := component.UnmarshalConfig(confmap.NewFromStringMap(value), cfg); err != nil {
+		if err := component.UnmarshalConfig(sub, cfg); err != nil {
 			return errorUnmarshalError(id, err)
 		}
 
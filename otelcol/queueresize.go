@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol // import "go.opentelemetry.io/collector/otelcol"
+
+import (
+	"context"
+	"reflect"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+var queueSettingsType = reflect.TypeOf(exporterhelper.QueueSettings{})
+
+// resizeQueues applies the reloaded configuration to the running service without restarting it, if the
+// configuration only changes the queue_size or num_consumers of the sending queues of exporters: the sending
+// queues of the running exporters are resized instead. Returns false if the service must be restarted to apply
+// the reloaded configuration.
+func (col *Collector) resizeQueues(ctx context.Context) bool {
+	if col.cfg == nil {
+		return false
+	}
+	factories, err := col.set.Factories()
+	if err != nil {
+		return false
+	}
+	cfg, err := col.set.ConfigProvider.Get(ctx, factories)
+	if err != nil || cfg.Validate() != nil {
+		// Restarting the service reports the error.
+		return false
+	}
+	queues, ok := changedQueues(col.cfg, cfg)
+	if !ok || len(queues) == 0 {
+		return false
+	}
+
+	for _, exps := range col.service.Exporters() {
+		for id, exp := range exps {
+			queueCfg, changed := queues[id]
+			if !changed {
+				continue
+			}
+			resizer, ok := exp.(exporterhelper.QueueResizer)
+			if !ok {
+				return false
+			}
+			if err = resizer.ResizeQueue(queueCfg.QueueSize, queueCfg.NumConsumers); err != nil {
+				col.service.Logger().Warn("Failed to resize the sending queue, restart service",
+					zap.Stringer("exporter", id), zap.Error(err))
+				return false
+			}
+		}
+	}
+
+	col.cfg = cfg
+	col.service.Logger().Info("Config updated, resized the sending queues of the exporters")
+	if notifier, ok := col.set.ConfigProvider.(ConfigAppliedNotifier); ok {
+		notifier.NotifyApplied(ctx, nil)
+	}
+	return true
+}
+
+// changedQueues returns the new sending queue settings of the exporters whose configuration changed between
+// the current and the updated configuration. Returns false if anything else than the queue_size or
+// num_consumers of the sending queues of exporters changed.
+func changedQueues(current, updated *Config) (map[component.ID]exporterhelper.QueueSettings, bool) {
+	if !reflect.DeepEqual(current.Receivers, updated.Receivers) ||
+		!reflect.DeepEqual(current.Processors, updated.Processors) ||
+		!reflect.DeepEqual(current.Connectors, updated.Connectors) ||
+		!reflect.DeepEqual(current.Extensions, updated.Extensions) ||
+		!reflect.DeepEqual(current.Service, updated.Service) ||
+		len(current.Exporters) != len(updated.Exporters) {
+		return nil, false
+	}
+
+	queues := make(map[component.ID]exporterhelper.QueueSettings)
+	for id, cfg := range current.Exporters {
+		updatedCfg, ok := updated.Exporters[id]
+		if !ok {
+			return nil, false
+		}
+		if reflect.DeepEqual(cfg, updatedCfg) {
+			continue
+		}
+		queueCfg, ok := changedQueue(cfg, updatedCfg)
+		if !ok {
+			return nil, false
+		}
+		queues[id] = queueCfg
+	}
+	return queues, true
+}
+
+// changedQueue returns the updated sending queue settings of the exporter configuration, the struct field of type
+// exporterhelper.QueueSettings. Returns false if the configuration has no such field, or if anything else than
+// the queue_size or num_consumers of the sending queue changed.
+func changedQueue(current, updated component.Config) (exporterhelper.QueueSettings, bool) {
+	cur, upd := reflect.ValueOf(current), reflect.ValueOf(updated)
+	if cur.Type() != upd.Type() {
+		return exporterhelper.QueueSettings{}, false
+	}
+	if cur.Kind() == reflect.Pointer {
+		if cur.IsNil() || upd.IsNil() {
+			return exporterhelper.QueueSettings{}, false
+		}
+		cur, upd = cur.Elem(), upd.Elem()
+	}
+	if cur.Kind() != reflect.Struct {
+		return exporterhelper.QueueSettings{}, false
+	}
+
+	for i := 0; i < cur.NumField(); i++ {
+		if cur.Type().Field(i).Type != queueSettingsType || !cur.Type().Field(i).IsExported() {
+			continue
+		}
+		// Compare a copy of the current configuration with the updated queue size and number of consumers.
+		queueCfg := upd.Field(i).Interface().(exporterhelper.QueueSettings)
+		resized := cur.Field(i).Interface().(exporterhelper.QueueSettings)
+		resized.QueueSize = queueCfg.QueueSize
+		resized.NumConsumers = queueCfg.NumConsumers
+		resizedCfg := reflect.New(cur.Type()).Elem()
+		resizedCfg.Set(cur)
+		resizedCfg.Field(i).Set(reflect.ValueOf(resized))
+		if !reflect.DeepEqual(resizedCfg.Interface(), upd.Interface()) {
+			return exporterhelper.QueueSettings{}, false
+		}
+		return queueCfg, true
+	}
+	return exporterhelper.QueueSettings{}, false
+}
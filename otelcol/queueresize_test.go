@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type queuedConfig struct {
+	Endpoint      string                       `mapstructure:"endpoint"`
+	QueueSettings exporterhelper.QueueSettings `mapstructure:"sending_queue"`
+}
+
+func TestChangedQueues(t *testing.T) {
+	id := component.NewID("queued")
+	newConfig := func(update func(cfg *queuedConfig)) *Config {
+		cfg := &queuedConfig{Endpoint: "localhost:4317", QueueSettings: exporterhelper.NewDefaultQueueSettings()}
+		update(cfg)
+		return &Config{Exporters: map[component.ID]component.Config{id: cfg}}
+	}
+	current := newConfig(func(*queuedConfig) {})
+
+	tests := []struct {
+		name    string
+		updated *Config
+		queues  map[component.ID]exporterhelper.QueueSettings
+		ok      bool
+	}{
+		{
+			name:    "unchanged",
+			updated: newConfig(func(*queuedConfig) {}),
+			queues:  map[component.ID]exporterhelper.QueueSettings{},
+			ok:      true,
+		},
+		{
+			name: "queue size and consumers",
+			updated: newConfig(func(cfg *queuedConfig) {
+				cfg.QueueSettings.QueueSize = 20
+				cfg.QueueSettings.NumConsumers = 2
+			}),
+			queues: map[component.ID]exporterhelper.QueueSettings{
+				id: func() exporterhelper.QueueSettings {
+					queueCfg := exporterhelper.NewDefaultQueueSettings()
+					queueCfg.QueueSize = 20
+					queueCfg.NumConsumers = 2
+					return queueCfg
+				}(),
+			},
+			ok: true,
+		},
+		{
+			name: "queue storage",
+			updated: newConfig(func(cfg *queuedConfig) {
+				storageID := component.NewID("file_storage")
+				cfg.QueueSettings.QueueSize = 20
+				cfg.QueueSettings.StorageID = &storageID
+			}),
+		},
+		{
+			name: "exporter setting",
+			updated: newConfig(func(cfg *queuedConfig) {
+				cfg.QueueSettings.QueueSize = 20
+				cfg.Endpoint = "localhost:4318"
+			}),
+		},
+		{
+			name:    "exporter without queue",
+			updated: &Config{Exporters: map[component.ID]component.Config{id: &struct{}{}}},
+		},
+		{
+			name: "new exporter",
+			updated: func() *Config {
+				cfg := newConfig(func(*queuedConfig) {})
+				cfg.Exporters[component.NewIDWithName("queued", "2")] = &queuedConfig{}
+				return cfg
+			}(),
+		},
+		{
+			name: "service",
+			updated: func() *Config {
+				cfg := newConfig(func(cfg *queuedConfig) { cfg.QueueSettings.QueueSize = 20 })
+				cfg.Service.Extensions = []component.ID{component.NewID("nop")}
+				return cfg
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queues, ok := changedQueues(current, tt.updated)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.queues, queues)
+		})
+	}
+}
+
+func TestCollectorResizeQueuesOnConfigChange(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(endpoint string, queueSize int, numConsumers int) {
+		require.NoError(t, os.WriteFile(cfgFile, []byte(fmt.Sprintf(`
+receivers:
+  nop:
+exporters:
+  queued:
+    endpoint: %s
+    sending_queue:
+      queue_size: %d
+      num_consumers: %d
+service:
+  telemetry:
+    metrics:
+      level: none
+  pipelines:
+    traces:
+      receivers: [nop]
+      exporters: [queued]
+`, endpoint, queueSize, numConsumers)), 0600))
+	}
+	writeConfig("localhost:4317", 10, 1)
+
+	recorder := &resizeRecorder{}
+	factories := func() (Factories, error) {
+		factories, err := nopFactories()
+		if err != nil {
+			return Factories{}, err
+		}
+		factories.Exporters, err = exporter.MakeFactoryMap(newQueuedExporterFactory(recorder))
+		return factories, err
+	}
+	provider, err := NewConfigProvider(newDefaultConfigProviderSettings([]string{cfgFile}))
+	require.NoError(t, err)
+	watcher := make(chan error, 1)
+	col, err := NewCollector(CollectorSettings{
+		BuildInfo:      component.NewDefaultBuildInfo(),
+		Factories:      factories,
+		ConfigProvider: &mockCfgProvider{ConfigProvider: provider, watcher: watcher},
+	})
+	require.NoError(t, err)
+
+	wg := startCollector(context.Background(), t, col)
+	assert.Eventually(t, func() bool {
+		return StateRunning == col.GetState()
+	}, 2*time.Second, 200*time.Millisecond)
+	assert.Equal(t, 1, recorder.createdCount())
+
+	// Changing only the sending queue resizes it, without rebuilding the exporter.
+	writeConfig("localhost:4317", 20, 2)
+	watcher <- nil
+	assert.Eventually(t, func() bool {
+		return len(recorder.resizedQueues()) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, [][2]int{{20, 2}}, recorder.resizedQueues())
+	assert.Equal(t, 1, recorder.createdCount())
+	assert.Equal(t, StateRunning, col.GetState())
+
+	// Changing anything else restarts the service.
+	writeConfig("localhost:4318", 30, 2)
+	watcher <- nil
+	assert.Eventually(t, func() bool {
+		return recorder.createdCount() == 2 && StateRunning == col.GetState()
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Len(t, recorder.resizedQueues(), 1)
+
+	col.Shutdown()
+	wg.Wait()
+	assert.Equal(t, StateClosed, col.GetState())
+}
+
+// resizeRecorder records the exporters created by the queued exporter factory, and the resizes of their queues.
+type resizeRecorder struct {
+	mu      sync.Mutex
+	created int
+	resized [][2]int
+}
+
+func (r *resizeRecorder) createdCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.created
+}
+
+func (r *resizeRecorder) resizedQueues() [][2]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][2]int(nil), r.resized...)
+}
+
+// resizeRecordingExporter records the resizes of the queue of the wrapped exporter.
+type resizeRecordingExporter struct {
+	exporter.Traces
+	recorder *resizeRecorder
+}
+
+func (e *resizeRecordingExporter) ResizeQueue(queueSize int, numConsumers int) error {
+	if err := e.Traces.(exporterhelper.QueueResizer).ResizeQueue(queueSize, numConsumers); err != nil {
+		return err
+	}
+	e.recorder.mu.Lock()
+	defer e.recorder.mu.Unlock()
+	e.recorder.resized = append(e.recorder.resized, [2]int{queueSize, numConsumers})
+	return nil
+}
+
+func newQueuedExporterFactory(recorder *resizeRecorder) exporter.Factory {
+	return exporter.NewFactory(
+		"queued",
+		func() component.Config {
+			return &queuedConfig{QueueSettings: exporterhelper.NewDefaultQueueSettings()}
+		},
+		exporter.WithTraces(func(ctx context.Context, set exporter.CreateSettings, cfg component.Config) (exporter.Traces, error) {
+			exp, err := exporterhelper.NewTracesExporter(ctx, set, cfg,
+				func(context.Context, ptrace.Traces) error { return nil },
+				exporterhelper.WithQueue(cfg.(*queuedConfig).QueueSettings),
+				exporterhelper.WithCapabilities(consumer.Capabilities{}))
+			if err != nil {
+				return nil, err
+			}
+			recorder.mu.Lock()
+			defer recorder.mu.Unlock()
+			recorder.created++
+			return &resizeRecordingExporter{Traces: exp, recorder: recorder}, nil
+		}, component.StabilityLevelDevelopment))
+}
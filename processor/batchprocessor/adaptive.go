@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package batchprocessor // import "go.opentelemetry.io/collector/processor/batchprocessor"
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// adaptiveWindow is the number of exports between two adjustments of the batch size.
+	adaptiveWindow = 10
+	// adaptiveStep is the factor by which the batch size grows or shrinks at each adjustment.
+	adaptiveStep = 1.25
+)
+
+// adaptiveSizer adjusts the size of the batches by hill climbing: the size keeps moving in the
+// same direction while the throughput, in items exported per second of export latency, improves,
+// and reverses otherwise. The size is halved when an export fails.
+type adaptiveSizer struct {
+	minSize int
+	maxSize int
+
+	// size is the current size of the batches, read by the shards for each item.
+	size atomic.Int64
+
+	mu sync.Mutex
+	// The exports since the last adjustment.
+	exports int
+	items   int
+	latency time.Duration
+	failed  bool
+	// throughput is the throughput measured before the last adjustment, 0 if unknown.
+	throughput float64
+	growing    bool
+}
+
+func newAdaptiveSizer(cfg *Config) *adaptiveSizer {
+	size := int(cfg.SendBatchSize)
+	minSize := int(cfg.Adaptive.MinSendBatchSize)
+	if minSize == 0 {
+		minSize = size / 8
+	}
+	if minSize == 0 {
+		minSize = 1
+	}
+	maxSize := int(cfg.Adaptive.MaxSendBatchSize)
+	if maxSize == 0 {
+		maxSize = int(cfg.SendBatchMaxSize)
+	}
+	if maxSize == 0 {
+		maxSize = 8 * size
+	}
+
+	s := &adaptiveSizer{
+		minSize: minSize,
+		maxSize: maxSize,
+		growing: true,
+	}
+	s.size.Store(int64(size))
+	return s
+}
+
+// current returns the current size of the batches.
+func (s *adaptiveSizer) current() int {
+	return int(s.size.Load())
+}
+
+// record records the result of the export of a batch that reached the current size.
+func (s *adaptiveSizer) record(items int, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.exports++
+	s.items += items
+	s.latency += latency
+	s.failed = s.failed || err != nil
+	if s.exports < adaptiveWindow {
+		return
+	}
+
+	size := float64(s.size.Load())
+	switch {
+	case s.failed:
+		// Back off and restart the search from the smaller size.
+		size /= 2
+		s.growing = false
+		s.throughput = 0
+	default:
+		throughput := float64(s.items) / s.latency.Seconds()
+		if s.throughput > 0 && throughput < s.throughput {
+			s.growing = !s.growing
+		}
+		s.throughput = throughput
+		if s.growing {
+			// Grow by at least one item, so small sizes can grow too.
+			size = math.Max(size*adaptiveStep, size+1)
+		} else {
+			size /= adaptiveStep
+		}
+	}
+	s.size.Store(int64(s.clamp(int(size))))
+
+	s.exports = 0
+	s.items = 0
+	s.latency = 0
+	s.failed = false
+}
+
+func (s *adaptiveSizer) clamp(size int) int {
+	if size < s.minSize {
+		return s.minSize
+	}
+	if size > s.maxSize {
+		return s.maxSize
+	}
+	return size
+}
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package batchprocessor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordWindow records a window of exports of the current size with the given throughput.
+func recordWindow(s *adaptiveSizer, throughput float64, err error) {
+	size := s.current()
+	for i := 0; i < adaptiveWindow; i++ {
+		s.record(size, time.Duration(float64(size)/throughput*float64(time.Second)), err)
+	}
+}
+
+func TestAdaptiveSizerDefaults(t *testing.T) {
+	s := newAdaptiveSizer(&Config{SendBatchSize: 100})
+	assert.Equal(t, 100, s.current())
+	assert.Equal(t, 12, s.minSize)
+	assert.Equal(t, 800, s.maxSize)
+
+	s = newAdaptiveSizer(&Config{SendBatchSize: 4, SendBatchMaxSize: 200})
+	assert.Equal(t, 1, s.minSize)
+	assert.Equal(t, 200, s.maxSize)
+
+	s = newAdaptiveSizer(&Config{SendBatchSize: 100, SendBatchMaxSize: 200,
+		Adaptive: AdaptiveConfig{MinSendBatchSize: 50, MaxSendBatchSize: 150}})
+	assert.Equal(t, 50, s.minSize)
+	assert.Equal(t, 150, s.maxSize)
+}
+
+func TestAdaptiveSizerHillClimbing(t *testing.T) {
+	s := newAdaptiveSizer(&Config{SendBatchSize: 100})
+
+	// The size only changes once per window.
+	s.record(100, time.Millisecond, nil)
+	assert.Equal(t, 100, s.current())
+
+	// The size grows while the throughput improves.
+	for i := 1; i < adaptiveWindow; i++ {
+		s.record(100, time.Millisecond, nil)
+	}
+	assert.Equal(t, 125, s.current())
+	recordWindow(s, 200_000, nil)
+	assert.Equal(t, 156, s.current())
+
+	// The size shrinks once the throughput decreases, and keeps shrinking while it improves.
+	recordWindow(s, 150_000, nil)
+	assert.Equal(t, 124, s.current())
+	recordWindow(s, 160_000, nil)
+	assert.Equal(t, 99, s.current())
+
+	// The size grows again once the throughput decreases.
+	recordWindow(s, 100_000, nil)
+	assert.Equal(t, 123, s.current())
+}
+
+func TestAdaptiveSizerErrors(t *testing.T) {
+	s := newAdaptiveSizer(&Config{SendBatchSize: 100, Adaptive: AdaptiveConfig{MinSendBatchSize: 30}})
+
+	// A single failed export halves the size.
+	for i := 0; i < adaptiveWindow-1; i++ {
+		s.record(100, time.Millisecond, nil)
+	}
+	s.record(100, time.Millisecond, errors.New("export failed"))
+	assert.Equal(t, 50, s.current())
+
+	// The size does not go below the minimum.
+	recordWindow(s, 100_000, errors.New("export failed"))
+	assert.Equal(t, 30, s.current())
+
+	// The size keeps shrinking after the errors while the throughput improves.
+	recordWindow(s, 100_000, nil)
+	assert.Equal(t, 30, s.current())
+	recordWindow(s, 90_000, nil)
+	assert.Equal(t, 37, s.current())
+}
+
+func TestAdaptiveSizerMaxSize(t *testing.T) {
+	s := newAdaptiveSizer(&Config{SendBatchSize: 100, SendBatchMaxSize: 110})
+	recordWindow(s, 100_000, nil)
+	assert.Equal(t, 110, s.current())
+	recordWindow(s, 200_000, nil)
+	assert.Equal(t, 110, s.current())
+}
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -29,6 +30,11 @@ import (
 // errTooManyBatchers is returned when the MetadataCardinalityLimit has been reached.
 var errTooManyBatchers = consumererror.NewPermanent(errors.New("too many batcher metadata-value combinations"))
 
+// errTooManyBatchesInFlight is returned, wrapped in a consumererror.Backpressure, when the
+// MaxInFlightBatches are being sent. The error is not permanent, so the data is sent again
+// by the preceding component.
+var errTooManyBatchesInFlight = errors.New("too many batches in flight")
+
 // batch_processor is a component that accepts spans and metrics, places them
 // into batches and sends downstream.
 //
@@ -38,10 +44,14 @@ var errTooManyBatchers = consumererror.NewPermanent(errors.New("too many batcher
 // - batch size reaches cfg.SendBatchSize
 // - cfg.Timeout is elapsed since the timestamp when the previous batch was sent out.
 type batchProcessor struct {
-	logger           *zap.Logger
-	timeout          time.Duration
-	sendBatchSize    int
-	sendBatchMaxSize int
+	logger            *zap.Logger
+	timeout           time.Duration
+	sendBatchSize     int
+	sendBatchMaxSize  int
+	sendBatchMaxBytes int
+
+	// adaptive adjusts the size of the batches, nil when the adaptive sizing is disabled.
+	adaptive *adaptiveSizer
 
 	// batchFunc is a factory for new batch objects corresponding
 	// with the appropriate signal.
@@ -56,12 +66,27 @@ type batchProcessor struct {
 	// metadataLimit is the limiting size of the batchers map.
 	metadataLimit int
 
-	shutdownC  chan struct{}
+	shutdownTimeout time.Duration
+
+	// inFlight holds a token for each batch being sent in the background, nil when
+	// MaxInFlightBatches is not set and the batches are sent by the shards.
+	inFlight chan struct{}
+
+	// shutdownCtx bounds the flush of the pending batches. It is set
+	// by Shutdown before closing shutdownC.
+	shutdownCtx context.Context
+	shutdownC   chan struct{}
+	// goroutines counts the shards and the batches in flight.
 	goroutines sync.WaitGroup
 
+	// unsent counts the items of the batches flushed on shutdown that
+	// were not sent yet, or could not be sent.
+	unsent atomic.Int64
+
 	telemetry *batchProcessorTelemetry
 
-	//  batcher will be either *singletonBatcher or *multiBatcher
+	//  batcher will be either *singletonBatcher or *multiBatcher, or
+	//  *partitionedBatcher of those when the traces are partitioned.
 	batcher batcher
 }
 
@@ -95,8 +120,8 @@ type shard struct {
 
 // batch is an interface generalizing the individual signal types.
 type batch interface {
-	// export the current batch
-	export(ctx context.Context, sendBatchMaxSize int, returnBytes bool) (sentBatchSize int, sentBatchBytes int, err error)
+	// split removes the next request from the current batch and returns the function sending it
+	split(sendBatchMaxSize int, sendBatchMaxBytes int, returnBytes bool) (send func(context.Context) error, sentBatchSize int, sentBatchBytes int)
 
 	// itemCount returns the size of the current batch
 	itemCount() int
@@ -110,7 +135,8 @@ var _ consumer.Metrics = (*batchProcessor)(nil)
 var _ consumer.Logs = (*batchProcessor)(nil)
 
 // newBatchProcessor returns a new batch processor component.
-func newBatchProcessor(set processor.CreateSettings, cfg *Config, batchFunc func() batch, useOtel bool) (*batchProcessor, error) {
+// The data is split by trace ID into the given number of partitions when it is greater than 1.
+func newBatchProcessor(set processor.CreateSettings, cfg *Config, batchFunc func() batch, partitions int, useOtel bool) (*batchProcessor, error) {
 	// use lower-case, to be consistent with http/2 headers.
 	mks := make([]string, len(cfg.MetadataKeys))
 	for i, k := range cfg.MetadataKeys {
@@ -120,23 +146,33 @@ func newBatchProcessor(set processor.CreateSettings, cfg *Config, batchFunc func
 	bp := &batchProcessor{
 		logger: set.Logger,
 
-		sendBatchSize:    int(cfg.SendBatchSize),
-		sendBatchMaxSize: int(cfg.SendBatchMaxSize),
-		timeout:          cfg.Timeout,
-		batchFunc:        batchFunc,
-		shutdownC:        make(chan struct{}, 1),
-		metadataKeys:     mks,
-		metadataLimit:    int(cfg.MetadataCardinalityLimit),
+		sendBatchSize:     int(cfg.SendBatchSize),
+		sendBatchMaxSize:  int(cfg.SendBatchMaxSize),
+		sendBatchMaxBytes: int(cfg.SendBatchMaxBytes),
+		timeout:           cfg.Timeout,
+		shutdownTimeout:   cfg.ShutdownTimeout,
+		batchFunc:         batchFunc,
+		shutdownC:         make(chan struct{}, 1),
+		metadataKeys:      mks,
+		metadataLimit:     int(cfg.MetadataCardinalityLimit),
 	}
-	if len(bp.metadataKeys) == 0 {
-		bp.batcher = &singleShardBatcher{batcher: bp.newShard(nil)}
-	} else {
-		bp.batcher = &multiShardBatcher{
-			batchProcessor: bp,
+	if cfg.Adaptive.Enabled {
+		bp.adaptive = newAdaptiveSizer(cfg)
+	}
+	if cfg.MaxInFlightBatches > 0 {
+		bp.inFlight = make(chan struct{}, cfg.MaxInFlightBatches)
+	}
+	if partitions > 1 {
+		pb := &partitionedBatcher{partitions: make([]batcher, partitions)}
+		for i := range pb.partitions {
+			pb.partitions[i] = bp.newBatcher()
 		}
+		bp.batcher = pb
+	} else {
+		bp.batcher = bp.newBatcher()
 	}
 
-	bpt, err := newBatchProcessorTelemetry(set, bp.batcher.currentMetadataCardinality, useOtel)
+	bpt, err := newBatchProcessorTelemetry(set, bp.sendBatchSize, bp.batcher.currentMetadataCardinality, useOtel)
 	if err != nil {
 		return nil, fmt.Errorf("error creating batch processor telemetry: %w", err)
 	}
@@ -145,6 +181,16 @@ func newBatchProcessor(set processor.CreateSettings, cfg *Config, batchFunc func
 	return bp, nil
 }
 
+// newBatcher returns a batcher with a single shard, or a shard per combination of metadata values.
+func (bp *batchProcessor) newBatcher() batcher {
+	if len(bp.metadataKeys) == 0 {
+		return &singleShardBatcher{batcher: bp.newShard(nil)}
+	}
+	return &multiShardBatcher{
+		batchProcessor: bp,
+	}
+}
+
 // newShard gets or creates a batcher corresponding with attrs.
 func (bp *batchProcessor) newShard(md map[string][]string) *shard {
 	exportCtx := client.NewContext(context.Background(), client.Info{
@@ -170,12 +216,33 @@ func (bp *batchProcessor) Start(context.Context, component.Host) error {
 	return nil
 }
 
-// Shutdown is invoked during service shutdown.
-func (bp *batchProcessor) Shutdown(context.Context) error {
+// Shutdown is invoked during service shutdown. It flushes the pending batches until
+// the context is done or the shutdown timeout is elapsed.
+func (bp *batchProcessor) Shutdown(ctx context.Context) error {
+	if bp.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, bp.shutdownTimeout)
+		defer cancel()
+	}
+	bp.shutdownCtx = ctx
 	close(bp.shutdownC)
 
-	// Wait until all goroutines are done.
-	bp.goroutines.Wait()
+	// Wait until all goroutines are done, or the next consumer
+	// ignores the cancellation of the flush for too long.
+	done := make(chan struct{})
+	go func() {
+		bp.goroutines.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to flush the pending batches on shutdown, %d items were not sent: %w", bp.unsent.Load(), ctx.Err())
+	}
+
+	if unsent := bp.unsent.Load(); unsent > 0 {
+		return fmt.Errorf("failed to flush the pending batches on shutdown, %d items were not sent", unsent)
+	}
 	return nil
 }
 
@@ -202,11 +269,7 @@ func (b *shard) start() {
 				}
 			}
 			// This is the close of the channel
-			if b.batch.itemCount() > 0 {
-				// TODO: Set a timeout on sendTraces or
-				// make it cancellable using the context that Shutdown gets as a parameter
-				b.sendItems(triggerTimeout)
-			}
+			b.flush()
 			return
 		case item := <-b.newItem:
 			if item == nil {
@@ -214,8 +277,8 @@ func (b *shard) start() {
 			}
 			b.processItem(item)
 		case <-timerCh:
-			if b.batch.itemCount() > 0 {
-				b.sendItems(triggerTimeout)
+			for b.batch.itemCount() > 0 {
+				b.dispatchItems(triggerTimeout)
 			}
 			b.resetTimer()
 		}
@@ -225,9 +288,9 @@ func (b *shard) start() {
 func (b *shard) processItem(item any) {
 	b.batch.add(item)
 	sent := false
-	for b.batch.itemCount() > 0 && (!b.hasTimer() || b.batch.itemCount() >= b.processor.sendBatchSize) {
+	for b.batch.itemCount() > 0 && (!b.hasTimer() || b.batch.itemCount() >= b.processor.batchSize()) {
 		sent = true
-		b.sendItems(triggerBatchSize)
+		b.dispatchItems(triggerBatchSize)
 	}
 
 	if sent {
@@ -236,6 +299,14 @@ func (b *shard) processItem(item any) {
 	}
 }
 
+// batchSize returns the size of the batches that triggers a send.
+func (bp *batchProcessor) batchSize() int {
+	if bp.adaptive != nil {
+		return bp.adaptive.current()
+	}
+	return bp.sendBatchSize
+}
+
 func (b *shard) hasTimer() bool {
 	return b.timer != nil
 }
@@ -252,13 +323,73 @@ func (b *shard) resetTimer() {
 	}
 }
 
-func (b *shard) sendItems(trigger trigger) {
-	sent, bytes, err := b.batch.export(b.exportCtx, b.processor.sendBatchMaxSize, b.processor.telemetry.detailed)
+// flush sends the pending batch on shutdown, until the shutdown context is done.
+// The items that are not sent are counted as unsent.
+func (b *shard) flush() {
+	ctx := shutdownContext{Context: b.processor.shutdownCtx, values: b.exportCtx}
+	b.processor.unsent.Add(int64(b.batch.itemCount()))
+	// The batch may be split into several requests by send_batch_max_size and send_batch_max_bytes.
+	for b.batch.itemCount() > 0 {
+		if ctx.Err() != nil {
+			// Drop the items that cannot be sent in time.
+			b.batch = b.processor.batchFunc()
+			return
+		}
+		if sent, err := b.sendItems(ctx, triggerTimeout); err == nil {
+			b.processor.unsent.Add(-int64(sent))
+		}
+	}
+}
+
+// sendItems sends the next request of the batch and waits until it is sent.
+func (b *shard) sendItems(ctx context.Context, trigger trigger) (int, error) {
+	send, sent, bytes := b.batch.split(b.processor.sendBatchMaxSize, b.processor.sendBatchMaxBytes,
+		b.processor.telemetry.detailed)
+	return sent, b.processor.send(ctx, trigger, send, sent, bytes)
+}
+
+// dispatchItems sends the next request of the batch. When MaxInFlightBatches is set the request is
+// sent in the background, once fewer than MaxInFlightBatches batches are in flight.
+func (b *shard) dispatchItems(trigger trigger) {
+	bp := b.processor
+	if bp.inFlight == nil {
+		_, _ = b.sendItems(b.exportCtx, trigger)
+		return
+	}
+	send, sent, bytes := b.batch.split(bp.sendBatchMaxSize, bp.sendBatchMaxBytes, bp.telemetry.detailed)
+	bp.inFlight <- struct{}{}
+	bp.goroutines.Add(1)
+	go func() {
+		defer bp.goroutines.Done()
+		_ = bp.send(b.exportCtx, trigger, send, sent, bytes)
+		<-bp.inFlight
+	}()
+}
+
+func (bp *batchProcessor) send(ctx context.Context, trigger trigger, send func(context.Context) error, sent int, bytes int) error {
+	start := time.Now()
+	err := send(ctx)
+	// Only the full batches measure the throughput of the current size.
+	if bp.adaptive != nil && trigger == triggerBatchSize {
+		bp.adaptive.record(sent, time.Since(start), err)
+	}
 	if err != nil {
-		b.processor.logger.Warn("Sender failed", zap.Error(err))
+		bp.logger.Warn("Sender failed", zap.Error(err))
 	} else {
-		b.processor.telemetry.record(trigger, int64(sent), int64(bytes))
+		bp.telemetry.record(trigger, int64(sent), int64(bytes))
 	}
+	return err
+}
+
+// shutdownContext is the context of the flush on shutdown: it has the deadline
+// of the shutdown context and the client metadata of the shard.
+type shutdownContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c shutdownContext) Value(key any) any {
+	return c.values.Value(key)
 }
 
 // singleShardBatcher is used when metadataKeys is empty, to avoid the
@@ -310,16 +441,21 @@ func (mb *multiShardBatcher) consume(ctx context.Context, data any) error {
 	b, ok := mb.batchers.Load(aset)
 	if !ok {
 		mb.lock.Lock()
-		if mb.metadataLimit != 0 && mb.size >= mb.metadataLimit {
-			mb.lock.Unlock()
-			return errTooManyBatchers
-		}
+		// Another caller may have created the shard while we were
+		// waiting for the lock; check again before creating a new
+		// shard, which would otherwise start a goroutine that is
+		// never used.
+		b, ok = mb.batchers.Load(aset)
+		if !ok {
+			if mb.metadataLimit != 0 && mb.size >= mb.metadataLimit {
+				mb.lock.Unlock()
+				return errTooManyBatchers
+			}
 
-		// aset.ToSlice() returns the sorted, deduplicated,
-		// and name-downcased list of attributes.
-		var loaded bool
-		b, loaded = mb.batchers.LoadOrStore(aset, mb.newShard(md))
-		if !loaded {
+			// aset.ToSlice() returns the sorted, deduplicated,
+			// and name-downcased list of attributes.
+			b = mb.newShard(md)
+			mb.batchers.Store(aset, b)
 			mb.size++
 		}
 		mb.lock.Unlock()
@@ -334,34 +470,43 @@ func (mb *multiShardBatcher) currentMetadataCardinality() int {
 	return mb.size
 }
 
+// consume refuses the data while MaxInFlightBatches batches are in flight, instead of
+// buffering it until the next component catches up.
+func (bp *batchProcessor) consume(ctx context.Context, data any) error {
+	if bp.inFlight != nil && len(bp.inFlight) == cap(bp.inFlight) {
+		return consumererror.NewBackpressure(errTooManyBatchesInFlight, 0)
+	}
+	return bp.batcher.consume(ctx, data)
+}
+
 // ConsumeTraces implements TracesProcessor
 func (bp *batchProcessor) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
-	return bp.batcher.consume(ctx, td)
+	return bp.consume(ctx, td)
 }
 
 // ConsumeMetrics implements MetricsProcessor
 func (bp *batchProcessor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
-	return bp.batcher.consume(ctx, md)
+	return bp.consume(ctx, md)
 }
 
 // ConsumeLogs implements LogsProcessor
 func (bp *batchProcessor) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
-	return bp.batcher.consume(ctx, ld)
+	return bp.consume(ctx, ld)
 }
 
 // newBatchTracesProcessor creates a new batch processor that batches traces by size or with timeout
 func newBatchTracesProcessor(set processor.CreateSettings, next consumer.Traces, cfg *Config, useOtel bool) (*batchProcessor, error) {
-	return newBatchProcessor(set, cfg, func() batch { return newBatchTraces(next) }, useOtel)
+	return newBatchProcessor(set, cfg, func() batch { return newBatchTraces(next) }, int(cfg.TraceIDPartitions), useOtel)
 }
 
 // newBatchMetricsProcessor creates a new batch processor that batches metrics by size or with timeout
 func newBatchMetricsProcessor(set processor.CreateSettings, next consumer.Metrics, cfg *Config, useOtel bool) (*batchProcessor, error) {
-	return newBatchProcessor(set, cfg, func() batch { return newBatchMetrics(next) }, useOtel)
+	return newBatchProcessor(set, cfg, func() batch { return newBatchMetrics(next) }, 0, useOtel)
 }
 
 // newBatchLogsProcessor creates a new batch processor that batches logs by size or with timeout
 func newBatchLogsProcessor(set processor.CreateSettings, next consumer.Logs, cfg *Config, useOtel bool) (*batchProcessor, error) {
-	return newBatchProcessor(set, cfg, func() batch { return newBatchLogs(next) }, useOtel)
+	return newBatchProcessor(set, cfg, func() batch { return newBatchLogs(next) }, 0, useOtel)
 }
 
 type batchTraces struct {
@@ -387,7 +532,7 @@ func (bt *batchTraces) add(item any) {
 	td.ResourceSpans().MoveAndAppendTo(bt.traceData.ResourceSpans())
 }
 
-func (bt *batchTraces) export(ctx context.Context, sendBatchMaxSize int, returnBytes bool) (int, int, error) {
+func (bt *batchTraces) split(sendBatchMaxSize int, sendBatchMaxBytes int, returnBytes bool) (func(context.Context) error, int, int) {
 	var req ptrace.Traces
 	var sent int
 	var bytes int
@@ -401,16 +546,41 @@ func (bt *batchTraces) export(ctx context.Context, sendBatchMaxSize int, returnB
 		bt.traceData = ptrace.NewTraces()
 		bt.spanCount = 0
 	}
-	if returnBytes {
+	if sendBatchMaxBytes > 0 {
+		bytes = bt.sizer.TracesSize(req)
+		for bytes > sendBatchMaxBytes && sent > 1 {
+			// The spans left out of the request are put back in front of the batch.
+			n := fittingItems(sent, bytes, sendBatchMaxBytes)
+			head := splitTraces(n, req)
+			bt.traceData.ResourceSpans().MoveAndAppendTo(req.ResourceSpans())
+			bt.traceData = req
+			bt.spanCount += sent - n
+			req, sent = head, n
+			bytes = bt.sizer.TracesSize(req)
+		}
+	} else if returnBytes {
 		bytes = bt.sizer.TracesSize(req)
 	}
-	return sent, bytes, bt.nextConsumer.ConsumeTraces(ctx, req)
+	return func(ctx context.Context) error { return bt.nextConsumer.ConsumeTraces(ctx, req) }, sent, bytes
 }
 
 func (bt *batchTraces) itemCount() int {
 	return bt.spanCount
 }
 
+// fittingItems estimates the number of items of a request fitting in maxBytes, assuming the items have similar
+// sizes. It returns at least 1 and less than count, so every split of the request makes progress.
+func fittingItems(count int, bytes int, maxBytes int) int {
+	n := int(int64(count) * int64(maxBytes) / int64(bytes))
+	if n >= count {
+		n = count - 1
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
 type batchMetrics struct {
 	nextConsumer   consumer.Metrics
 	metricData     pmetric.Metrics
@@ -422,7 +592,7 @@ func newBatchMetrics(nextConsumer consumer.Metrics) *batchMetrics {
 	return &batchMetrics{nextConsumer: nextConsumer, metricData: pmetric.NewMetrics(), sizer: &pmetric.ProtoMarshaler{}}
 }
 
-func (bm *batchMetrics) export(ctx context.Context, sendBatchMaxSize int, returnBytes bool) (int, int, error) {
+func (bm *batchMetrics) split(sendBatchMaxSize int, sendBatchMaxBytes int, returnBytes bool) (func(context.Context) error, int, int) {
 	var req pmetric.Metrics
 	var sent int
 	var bytes int
@@ -436,10 +606,22 @@ func (bm *batchMetrics) export(ctx context.Context, sendBatchMaxSize int, return
 		bm.metricData = pmetric.NewMetrics()
 		bm.dataPointCount = 0
 	}
-	if returnBytes {
+	if sendBatchMaxBytes > 0 {
+		bytes = bm.sizer.MetricsSize(req)
+		for bytes > sendBatchMaxBytes && sent > 1 {
+			// The data points left out of the request are put back in front of the batch.
+			n := fittingItems(sent, bytes, sendBatchMaxBytes)
+			head := splitMetrics(n, req)
+			bm.metricData.ResourceMetrics().MoveAndAppendTo(req.ResourceMetrics())
+			bm.metricData = req
+			bm.dataPointCount += sent - n
+			req, sent = head, n
+			bytes = bm.sizer.MetricsSize(req)
+		}
+	} else if returnBytes {
 		bytes = bm.sizer.MetricsSize(req)
 	}
-	return sent, bytes, bm.nextConsumer.ConsumeMetrics(ctx, req)
+	return func(ctx context.Context) error { return bm.nextConsumer.ConsumeMetrics(ctx, req) }, sent, bytes
 }
 
 func (bm *batchMetrics) itemCount() int {
@@ -468,7 +650,7 @@ func newBatchLogs(nextConsumer consumer.Logs) *batchLogs {
 	return &batchLogs{nextConsumer: nextConsumer, logData: plog.NewLogs(), sizer: &plog.ProtoMarshaler{}}
 }
 
-func (bl *batchLogs) export(ctx context.Context, sendBatchMaxSize int, returnBytes bool) (int, int, error) {
+func (bl *batchLogs) split(sendBatchMaxSize int, sendBatchMaxBytes int, returnBytes bool) (func(context.Context) error, int, int) {
 	var req plog.Logs
 	var sent int
 	var bytes int
@@ -483,10 +665,22 @@ func (bl *batchLogs) export(ctx context.Context, sendBatchMaxSize int, returnByt
 		bl.logData = plog.NewLogs()
 		bl.logCount = 0
 	}
-	if returnBytes {
+	if sendBatchMaxBytes > 0 {
+		bytes = bl.sizer.LogsSize(req)
+		for bytes > sendBatchMaxBytes && sent > 1 {
+			// The log records left out of the request are put back in front of the batch.
+			n := fittingItems(sent, bytes, sendBatchMaxBytes)
+			head := splitLogs(n, req)
+			bl.logData.ResourceLogs().MoveAndAppendTo(req.ResourceLogs())
+			bl.logData = req
+			bl.logCount += sent - n
+			req, sent = head, n
+			bytes = bl.sizer.LogsSize(req)
+		}
+	} else if returnBytes {
 		bytes = bl.sizer.LogsSize(req)
 	}
-	return sent, bytes, bl.nextConsumer.ConsumeLogs(ctx, req)
+	return func(ctx context.Context) error { return bl.nextConsumer.ConsumeLogs(ctx, req) }, sent, bytes
 }
 
 func (bl *batchLogs) itemCount() int {
@@ -5,6 +5,7 @@ package batchprocessor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"sync"
@@ -21,6 +22,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -164,6 +166,103 @@ func TestBatchProcessorSpansDeliveredEnforceBatchSize(t *testing.T) {
 	assert.Equal(t, (requestCount*spansPerRequest)%int(cfg.SendBatchMaxSize), sink.AllTraces()[len(sink.AllTraces())-1].SpanCount())
 }
 
+func TestBatchProcessorSpansDeliveredEnforceBatchMaxBytes(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 100
+	cfg.SendBatchMaxBytes = 2000
+	batcher, err := newBatchTracesProcessor(processortest.NewNopCreateSettings(), sink, cfg, false)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 20
+	spansPerRequest := 10
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		td := testdata.GenerateTraces(spansPerRequest)
+		spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		for spanIndex := 0; spanIndex < spansPerRequest; spanIndex++ {
+			spans.At(spanIndex).SetName(getTestSpanName(requestNum, spanIndex))
+		}
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+	}
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*spansPerRequest, sink.SpanCount())
+	sizer := &ptrace.ProtoMarshaler{}
+	receivedSpans := map[string]bool{}
+	for _, td := range sink.AllTraces() {
+		assert.LessOrEqual(t, sizer.TracesSize(td), int(cfg.SendBatchMaxBytes))
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			spans := td.ResourceSpans().At(i).ScopeSpans().At(0).Spans()
+			for j := 0; j < spans.Len(); j++ {
+				receivedSpans[spans.At(j).Name()] = true
+			}
+		}
+	}
+	assert.Len(t, receivedSpans, requestCount*spansPerRequest)
+}
+
+func TestBatchTraces_MaxBytesKeepsOrder(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	bt := newBatchTraces(sink)
+	td := testdata.GenerateTraces(10)
+	spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).SetName(getTestSpanName(0, i))
+	}
+	oneSpanBytes := (&ptrace.ProtoMarshaler{}).TracesSize(testdata.GenerateTraces(1))
+	bt.add(td)
+
+	// The spans not fitting in the limit are sent first by the next export.
+	sent, bytes, err := export(context.Background(), bt, 0, 3*oneSpanBytes, false)
+	require.NoError(t, err)
+	assert.Less(t, sent, 10)
+	assert.LessOrEqual(t, bytes, 3*oneSpanBytes)
+	assert.Equal(t, 10-sent, bt.itemCount())
+	for bt.itemCount() > 0 {
+		_, _, err = export(context.Background(), bt, 0, 3*oneSpanBytes, false)
+		require.NoError(t, err)
+	}
+	var names []string
+	for _, td := range sink.AllTraces() {
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			spans := td.ResourceSpans().At(i).ScopeSpans().At(0).Spans()
+			for j := 0; j < spans.Len(); j++ {
+				names = append(names, spans.At(j).Name())
+			}
+		}
+	}
+	require.Len(t, names, 10)
+	for i, name := range names {
+		assert.Equal(t, getTestSpanName(0, i), name)
+	}
+}
+
+func TestBatchMetrics_MaxBytes(t *testing.T) {
+	sink := new(metricsSink)
+	bm := newBatchMetrics(sink)
+	bm.add(testdata.GenerateMetrics(50))
+	maxBytes := (&pmetric.ProtoMarshaler{}).MetricsSize(testdata.GenerateMetrics(5))
+	sent, bytes, err := export(context.Background(), bm, 0, maxBytes, false)
+	require.NoError(t, err)
+	assert.Less(t, sent, 100)
+	assert.LessOrEqual(t, bytes, maxBytes)
+	assert.Equal(t, 100-sent, bm.itemCount())
+}
+
+func TestBatchLogs_MaxBytes(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	bl := newBatchLogs(sink)
+	bl.add(testdata.GenerateLogs(50))
+	maxBytes := (&plog.ProtoMarshaler{}).LogsSize(testdata.GenerateLogs(5))
+	sent, bytes, err := export(context.Background(), bl, 0, maxBytes, false)
+	require.NoError(t, err)
+	assert.Less(t, sent, 50)
+	assert.LessOrEqual(t, bytes, maxBytes)
+	assert.Equal(t, 50-sent, bl.itemCount())
+	assert.Equal(t, sent, sink.LogRecordCount())
+}
+
 func TestBatchProcessorSentBySize(t *testing.T) {
 	telemetryTest(t, testBatchProcessorSentBySize)
 }
@@ -216,6 +315,7 @@ func testBatchProcessorSentBySize(t *testing.T, tel testTelemetry, useOtel bool)
 		sendSizeSum:      float64(sink.SpanCount()),
 		sendSizeBytesSum: float64(sizeSum),
 		sizeTrigger:      float64(expectedBatchesNum),
+		fillRatioSum:     float64(sink.SpanCount()) / float64(sendBatchSize),
 	})
 }
 
@@ -264,6 +364,7 @@ func testBatchProcessorSentBySizeWithMaxSize(t *testing.T, tel testTelemetry, us
 		sendSizeSum:    float64(sink.SpanCount()),
 		sizeTrigger:    math.Floor(float64(totalSpans) / float64(sendBatchMaxSize)),
 		timeoutTrigger: 1,
+		fillRatioSum:   float64(totalSpans) / float64(sendBatchSize),
 	})
 }
 
@@ -464,7 +565,7 @@ func TestBatchMetrics_UnevenBatchMaxSize(t *testing.T) {
 
 	batchMetrics.add(md)
 	require.Equal(t, dataPointsPerMetric*metricsCount, batchMetrics.dataPointCount)
-	sent, _, sendErr := batchMetrics.export(ctx, sendBatchMaxSize, false)
+	sent, _, sendErr := export(ctx, batchMetrics, sendBatchMaxSize, 0, false)
 	require.NoError(t, sendErr)
 	require.Equal(t, sendBatchMaxSize, sent)
 	remainingDataPointCount := metricsCount*dataPointsPerMetric - sendBatchMaxSize
@@ -818,6 +919,139 @@ func TestBatchLogsProcessor_Timeout(t *testing.T) {
 	}
 }
 
+// blockingTracesSink blocks the traces until unblocked, or until the context is
+// done if it respects the cancellation.
+type blockingTracesSink struct {
+	consumertest.TracesSink
+	respectCtx bool
+	unblock    chan struct{}
+}
+
+func (bts *blockingTracesSink) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	if bts.respectCtx {
+		select {
+		case <-bts.unblock:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	} else {
+		<-bts.unblock
+	}
+	return bts.TracesSink.ConsumeTraces(ctx, td)
+}
+
+func TestBatchProcessorAdaptiveSize(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 100
+	cfg.Timeout = time.Hour
+	cfg.Adaptive.Enabled = true
+	require.NoError(t, cfg.Validate())
+	bp, err := newBatchTracesProcessor(processortest.NewNopCreateSettings(), consumertest.NewErr(errors.New("export failed")), cfg, true)
+	require.NoError(t, err)
+	require.NoError(t, bp.Start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < adaptiveWindow; i++ {
+		require.NoError(t, bp.ConsumeTraces(context.Background(), testdata.GenerateTraces(100)))
+	}
+	// The size is halved once the window of failed exports is recorded.
+	assert.Eventually(t, func() bool { return bp.batchSize() == 50 }, time.Second, time.Millisecond)
+
+	require.NoError(t, bp.Shutdown(context.Background()))
+}
+
+func TestBatchProcessorShutdownTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		respectCtx bool
+	}{
+		{
+			name:       "next_consumer_respects_cancellation",
+			respectCtx: true,
+		},
+		{
+			name:       "next_consumer_ignores_cancellation",
+			respectCtx: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				Timeout:          time.Hour,
+				SendBatchSize:    1000,
+				SendBatchMaxSize: 10,
+				ShutdownTimeout:  100 * time.Millisecond,
+			}
+			sink := &blockingTracesSink{respectCtx: tt.respectCtx, unblock: make(chan struct{})}
+			defer close(sink.unblock)
+
+			batcher, err := newBatchTracesProcessor(processortest.NewNopCreateSettings(), sink, &cfg, false)
+			require.NoError(t, err)
+			require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+			require.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraces(40)))
+
+			start := time.Now()
+			assert.ErrorContains(t, batcher.Shutdown(context.Background()), "failed to flush the pending batches on shutdown, 40 items were not sent")
+			assert.Less(t, time.Since(start), 5*time.Second)
+			assert.Equal(t, 0, sink.SpanCount())
+		})
+	}
+}
+
+func TestBatchProcessorMaxInFlightBatches(t *testing.T) {
+	cfg := Config{
+		Timeout:            time.Hour,
+		SendBatchSize:      10,
+		MaxInFlightBatches: 2,
+	}
+	sink := &blockingTracesSink{unblock: make(chan struct{})}
+
+	batcher, err := newBatchTracesProcessor(processortest.NewNopCreateSettings(), sink, &cfg, false)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The data is refused once the next component is sending the maximum number of batches.
+	accepted := 0
+	assert.Eventually(t, func() bool {
+		err := batcher.ConsumeTraces(context.Background(), testdata.GenerateTraces(10))
+		if err == nil {
+			accepted++
+			return false
+		}
+		return errors.Is(err, errTooManyBatchesInFlight) && errors.As(err, &consumererror.Backpressure{})
+	}, 5*time.Second, time.Millisecond)
+	assert.GreaterOrEqual(t, accepted, 2)
+	assert.False(t, consumererror.IsPermanent(errTooManyBatchesInFlight))
+	assert.Equal(t, 0, sink.SpanCount())
+
+	// The data is accepted again once the batches are sent.
+	close(sink.unblock)
+	assert.Eventually(t, func() bool {
+		return sink.SpanCount() == 10*accepted
+	}, 5*time.Second, time.Millisecond)
+	require.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraces(10)))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+	assert.Equal(t, 10*(accepted+1), sink.SpanCount())
+}
+
+func TestBatchProcessorShutdownCancelled(t *testing.T) {
+	cfg := Config{
+		Timeout:       time.Hour,
+		SendBatchSize: 1000,
+	}
+	sink := &blockingTracesSink{respectCtx: true, unblock: make(chan struct{})}
+	defer close(sink.unblock)
+
+	batcher, err := newBatchTracesProcessor(processortest.NewNopCreateSettings(), sink, &cfg, false)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraces(5)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.ErrorContains(t, batcher.Shutdown(ctx), "failed to flush the pending batches on shutdown, 5 items were not sent")
+	assert.Equal(t, 0, sink.SpanCount())
+}
+
 func TestBatchLogProcessor_Shutdown(t *testing.T) {
 	cfg := Config{
 		Timeout:       3 * time.Second,
@@ -1032,6 +1266,40 @@ func TestBatchProcessorMetadataCardinalityLimit(t *testing.T) {
 	require.NoError(t, batcher.Shutdown(context.Background()))
 }
 
+func TestBatchProcessorMetadataConcurrentShardCreation(t *testing.T) {
+	const numGoroutines = 20
+
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetadataKeys = []string{"token"}
+	cfg.MetadataCardinalityLimit = 1
+	creationSet := processortest.NewNopCreateSettings()
+	batcher, err := newBatchTracesProcessor(creationSet, sink, cfg, false)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	ctx := client.NewContext(context.Background(), client.Info{
+		Metadata: client.NewMetadata(map[string][]string{
+			"token": {"tenant"},
+		}),
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, batcher.ConsumeTraces(ctx, testdata.GenerateTraces(1)))
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	assert.Equal(t, 1, batcher.batcher.currentMetadataCardinality())
+	assert.Equal(t, numGoroutines, sink.SpanCount())
+}
+
 func TestBatchZeroConfig(t *testing.T) {
 	// This is a no-op configuration. No need for a timer, no
 	// minimum, no mxaimum, just a pass through.
@@ -1105,3 +1373,52 @@ func TestBatchSplitOnly(t *testing.T) {
 		require.Equal(t, maxBatch, ld.LogRecordCount())
 	}
 }
+
+func TestBatchProcessorTraceIDPartitions(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 20
+	cfg.Timeout = time.Hour
+	cfg.TraceIDPartitions = 4
+	sink := new(consumertest.TracesSink)
+	batcher, err := newBatchTracesProcessor(processortest.NewNopCreateSettings(), sink, cfg, false)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	// Each request has one span of each trace.
+	const traceCount = 10
+	for requestNum := 0; requestNum < 10; requestNum++ {
+		td := testdata.GenerateTraces(traceCount)
+		spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		for i := 0; i < spans.Len(); i++ {
+			spans.At(i).SetTraceID([16]byte{byte(i + 1)})
+		}
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+	}
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, traceCount*10, sink.SpanCount())
+	traceIDs := map[pcommon.TraceID]bool{}
+	for _, td := range sink.AllTraces() {
+		partition := -1
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			spans := rss.At(i).ScopeSpans().At(0).Spans()
+			for j := 0; j < spans.Len(); j++ {
+				traceID := spans.At(j).TraceID()
+				traceIDs[traceID] = true
+				if partition == -1 {
+					partition = tracePartition(traceID, 4)
+				}
+				// A batch only has spans of a single partition.
+				assert.Equal(t, partition, tracePartition(traceID, 4))
+			}
+		}
+	}
+	assert.Len(t, traceIDs, traceCount)
+}
+
+// export sends the next request of the batch.
+func export(ctx context.Context, b batch, sendBatchMaxSize int, sendBatchMaxBytes int, returnBytes bool) (int, int, error) {
+	send, sent, bytes := b.split(sendBatchMaxSize, sendBatchMaxBytes, returnBytes)
+	return sent, bytes, send(ctx)
+}
@@ -28,6 +28,12 @@ type Config struct {
 	// Default value is 0, that means no maximum size.
 	SendBatchMaxSize uint32 `mapstructure:"send_batch_max_size"`
 
+	// SendBatchMaxBytes is the maximum size of a batch in bytes, estimated as the size of the batch
+	// serialized with the OTLP protobuf encoding. Larger batches are split into smaller units, a single
+	// span, metric data point or log record larger than the limit is sent alone.
+	// Default value is 0, that means no maximum size.
+	SendBatchMaxBytes uint64 `mapstructure:"send_batch_max_bytes"`
+
 	// MetadataKeys is a list of client.Metadata keys that will be
 	// used to form distinct batchers.  If this setting is empty,
 	// a single batcher instance will be used.  When this setting
@@ -44,6 +50,41 @@ type Config struct {
 	// batcher instances that will be created through a distinct
 	// combination of MetadataKeys.
 	MetadataCardinalityLimit uint32 `mapstructure:"metadata_cardinality_limit"`
+
+	// TraceIDPartitions is the number of partitions the spans are split into by trace ID. Each
+	// partition is batched separately, so all the spans of a trace end up in the same batches.
+	// Only the traces are partitioned. Default value is 0, that means no partitioning.
+	TraceIDPartitions uint32 `mapstructure:"trace_id_partitions"`
+
+	// ShutdownTimeout is the maximum time to flush the pending batches on shutdown. The items
+	// that could not be sent in time are reported in the error returned by the shutdown.
+	// Default value is 0, that means the flush is bounded only by the shutdown context.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+
+	// MaxInFlightBatches is the maximum number of batches being sent to the next component at
+	// once. The batches are sent in the background, and the incoming data is refused with a
+	// retryable error while MaxInFlightBatches batches are in flight. Default value is 0, that
+	// means each batcher sends its batches one at a time and the incoming data waits meanwhile.
+	MaxInFlightBatches uint32 `mapstructure:"max_in_flight_batches"`
+
+	// Adaptive configures the adaptive sizing of the batches.
+	Adaptive AdaptiveConfig `mapstructure:"adaptive"`
+}
+
+// AdaptiveConfig defines the configuration of the adaptive sizing of the batches. When enabled
+// SendBatchSize is the initial size of the batches, which grows or shrinks to maximize the number
+// of items exported per second of export latency. The size shrinks when the exports fail.
+type AdaptiveConfig struct {
+	// Enabled enables the adaptive sizing of the batches.
+	Enabled bool `mapstructure:"enabled"`
+
+	// MinSendBatchSize is the minimum size of the batches.
+	// Default value is 0, that means 1/8 of SendBatchSize.
+	MinSendBatchSize uint32 `mapstructure:"min_send_batch_size"`
+
+	// MaxSendBatchSize is the maximum size of the batches.
+	// Default value is 0, that means SendBatchMaxSize if set, or 8 times SendBatchSize.
+	MaxSendBatchSize uint32 `mapstructure:"max_send_batch_size"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -64,5 +105,30 @@ func (cfg *Config) Validate() error {
 	if cfg.Timeout < 0 {
 		return errors.New("timeout must be greater or equal to 0")
 	}
+	if cfg.ShutdownTimeout < 0 {
+		return errors.New("shutdown_timeout must be greater or equal to 0")
+	}
+	if cfg.Adaptive.Enabled {
+		return cfg.validateAdaptive()
+	}
+	return nil
+}
+
+func (cfg *Config) validateAdaptive() error {
+	if cfg.SendBatchSize == 0 {
+		return errors.New("send_batch_size must be greater than 0 when adaptive is enabled")
+	}
+	if cfg.Timeout == 0 {
+		return errors.New("timeout must be greater than 0 when adaptive is enabled")
+	}
+	if cfg.Adaptive.MinSendBatchSize > cfg.SendBatchSize {
+		return errors.New("adaptive::min_send_batch_size must be less or equal to send_batch_size")
+	}
+	if cfg.Adaptive.MaxSendBatchSize > 0 && cfg.Adaptive.MaxSendBatchSize < cfg.SendBatchSize {
+		return errors.New("adaptive::max_send_batch_size must be greater or equal to send_batch_size")
+	}
+	if cfg.SendBatchMaxSize > 0 && cfg.Adaptive.MaxSendBatchSize > cfg.SendBatchMaxSize {
+		return errors.New("adaptive::max_send_batch_size must be less or equal to send_batch_max_size")
+	}
 	return nil
 }
@@ -35,6 +35,13 @@ func TestUnmarshalConfig(t *testing.T) {
 			SendBatchMaxSize:         uint32(11000),
 			Timeout:                  time.Second * 10,
 			MetadataCardinalityLimit: 1000,
+			ShutdownTimeout:          time.Second * 5,
+			TraceIDPartitions:        uint32(4),
+			MaxInFlightBatches:       uint32(8),
+			Adaptive: AdaptiveConfig{
+				Enabled:          true,
+				MinSendBatchSize: uint32(1000),
+			},
 		}, cfg)
 }
 
@@ -70,6 +77,71 @@ func TestValidateConfig_InvalidTimeout(t *testing.T) {
 	assert.Error(t, cfg.Validate())
 }
 
+func TestValidateConfig_InvalidShutdownTimeout(t *testing.T) {
+	cfg := &Config{
+		ShutdownTimeout: -time.Second,
+	}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestValidateConfig_Adaptive(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "valid",
+			cfg: Config{
+				Timeout:          time.Second,
+				SendBatchSize:    100,
+				SendBatchMaxSize: 1000,
+				Adaptive:         AdaptiveConfig{Enabled: true, MinSendBatchSize: 10, MaxSendBatchSize: 1000},
+			},
+		},
+		{
+			name:    "zero_send_batch_size",
+			cfg:     Config{Timeout: time.Second, Adaptive: AdaptiveConfig{Enabled: true}},
+			wantErr: "send_batch_size must be greater than 0 when adaptive is enabled",
+		},
+		{
+			name:    "zero_timeout",
+			cfg:     Config{SendBatchSize: 100, Adaptive: AdaptiveConfig{Enabled: true}},
+			wantErr: "timeout must be greater than 0 when adaptive is enabled",
+		},
+		{
+			name:    "min_above_send_batch_size",
+			cfg:     Config{Timeout: time.Second, SendBatchSize: 100, Adaptive: AdaptiveConfig{Enabled: true, MinSendBatchSize: 200}},
+			wantErr: "adaptive::min_send_batch_size must be less or equal to send_batch_size",
+		},
+		{
+			name:    "max_below_send_batch_size",
+			cfg:     Config{Timeout: time.Second, SendBatchSize: 100, Adaptive: AdaptiveConfig{Enabled: true, MaxSendBatchSize: 50}},
+			wantErr: "adaptive::max_send_batch_size must be greater or equal to send_batch_size",
+		},
+		{
+			name: "max_above_send_batch_max_size",
+			cfg: Config{Timeout: time.Second, SendBatchSize: 100, SendBatchMaxSize: 200,
+				Adaptive: AdaptiveConfig{Enabled: true, MaxSendBatchSize: 300}},
+			wantErr: "adaptive::max_send_batch_size must be less or equal to send_batch_max_size",
+		},
+		{
+			name: "disabled",
+			cfg:  Config{Adaptive: AdaptiveConfig{MinSendBatchSize: 200}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestValidateConfig_ValidZero(t *testing.T) {
 	cfg := &Config{}
 	assert.NoError(t, cfg.Validate())
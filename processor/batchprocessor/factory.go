@@ -33,7 +33,8 @@ func NewFactory() processor.Factory {
 		createDefaultConfig,
 		processor.WithTraces(createTraces, component.StabilityLevelStable),
 		processor.WithMetrics(createMetrics, component.StabilityLevelStable),
-		processor.WithLogs(createLogs, component.StabilityLevelStable))
+		processor.WithLogs(createLogs, component.StabilityLevelStable),
+		processor.WithOrderingConstraint(processor.OrderingConstraint{Position: processor.PositionLast}))
 }
 
 func createDefaultConfig() component.Config {
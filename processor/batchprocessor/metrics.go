@@ -29,8 +29,13 @@ var (
 	statTimeoutTriggerSend   = stats.Int64("timeout_trigger_send", "Number of times the batch was sent due to a timeout trigger", stats.UnitDimensionless)
 	statBatchSendSize        = stats.Int64("batch_send_size", "Number of units in the batch", stats.UnitDimensionless)
 	statBatchSendSizeBytes   = stats.Int64("batch_send_size_bytes", "Number of bytes in batch that was sent", stats.UnitBytes)
+	statBatchFillRatio       = stats.Float64("batch_fill_ratio", "Number of units in the batch relative to send_batch_size", stats.UnitDimensionless)
 )
 
+// fillRatioBoundaries are the boundaries of the batch_fill_ratio histogram. A ratio above 1 is possible when
+// send_batch_max_size is larger than send_batch_size, or unset.
+var fillRatioBoundaries = []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1, 1.5, 2, 5, 10}
+
 type trigger int
 
 const (
@@ -81,11 +86,20 @@ func metricViews() []*view.View {
 			1000_000, 2000_000, 3000_000, 4000_000, 5000_000, 6000_000, 7000_000, 8000_000, 9000_000),
 	}
 
+	distributionBatchFillRatioView := &view.View{
+		Name:        processorhelper.BuildCustomMetricName(typeStr, statBatchFillRatio.Name()),
+		Measure:     statBatchFillRatio,
+		Description: statBatchFillRatio.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Distribution(fillRatioBoundaries...),
+	}
+
 	return []*view.View{
 		countBatchSizeTriggerSendView,
 		countTimeoutTriggerSendView,
 		distributionBatchSendSizeView,
 		distributionBatchSendSizeBytesView,
+		distributionBatchFillRatioView,
 	}
 }
 
@@ -96,15 +110,19 @@ type batchProcessorTelemetry struct {
 
 	exportCtx context.Context
 
+	// sendBatchSize is the size trigger of the batches, the fill ratio is not recorded when it is 0.
+	sendBatchSize int
+
 	processorAttr            []attribute.KeyValue
 	batchSizeTriggerSend     metric.Int64Counter
 	timeoutTriggerSend       metric.Int64Counter
 	batchSendSize            metric.Int64Histogram
 	batchSendSizeBytes       metric.Int64Histogram
+	batchFillRatio           metric.Float64Histogram
 	batchMetadataCardinality metric.Int64ObservableUpDownCounter
 }
 
-func newBatchProcessorTelemetry(set processor.CreateSettings, currentMetadataCardinality func() int, useOtel bool) (*batchProcessorTelemetry, error) {
+func newBatchProcessorTelemetry(set processor.CreateSettings, sendBatchSize int, currentMetadataCardinality func() int, useOtel bool) (*batchProcessorTelemetry, error) {
 	exportCtx, err := tag.New(context.Background(), tag.Insert(processorTagKey, set.ID.String()))
 	if err != nil {
 		return nil, err
@@ -114,6 +132,7 @@ func newBatchProcessorTelemetry(set processor.CreateSettings, currentMetadataCar
 		useOtel:       useOtel,
 		processorAttr: []attribute.KeyValue{attribute.String(obsmetrics.ProcessorKey, set.ID.String())},
 		exportCtx:     exportCtx,
+		sendBatchSize: sendBatchSize,
 		level:         set.MetricsLevel,
 		detailed:      set.MetricsLevel == configtelemetry.LevelDetailed,
 	}
@@ -161,6 +180,13 @@ func (bpt *batchProcessorTelemetry) createOtelMetrics(mp metric.MeterProvider, c
 	)
 	errors = multierr.Append(errors, err)
 
+	bpt.batchFillRatio, err = meter.Float64Histogram(
+		processorhelper.BuildCustomMetricName(typeStr, "batch_fill_ratio"),
+		metric.WithDescription("Number of units in the batch relative to send_batch_size"),
+		metric.WithUnit("1"),
+	)
+	errors = multierr.Append(errors, err)
+
 	bpt.batchMetadataCardinality, err = meter.Int64ObservableUpDownCounter(
 		processorhelper.BuildCustomMetricName(typeStr, "metadata_cardinality"),
 		metric.WithDescription("Number of distinct metadata value combinations being processed"),
@@ -193,6 +219,9 @@ func (bpt *batchProcessorTelemetry) recordWithOC(trigger trigger, sent, bytes in
 	}
 
 	stats.Record(bpt.exportCtx, triggerMeasure.M(1), statBatchSendSize.M(sent))
+	if bpt.sendBatchSize > 0 {
+		stats.Record(bpt.exportCtx, statBatchFillRatio.M(bpt.fillRatio(sent)))
+	}
 	if bpt.detailed {
 		stats.Record(bpt.exportCtx, statBatchSendSizeBytes.M(bytes))
 	}
@@ -207,7 +236,15 @@ func (bpt *batchProcessorTelemetry) recordWithOtel(trigger trigger, sent, bytes
 	}
 
 	bpt.batchSendSize.Record(bpt.exportCtx, sent, metric.WithAttributes(bpt.processorAttr...))
+	if bpt.sendBatchSize > 0 {
+		bpt.batchFillRatio.Record(bpt.exportCtx, bpt.fillRatio(sent), metric.WithAttributes(bpt.processorAttr...))
+	}
 	if bpt.detailed {
 		bpt.batchSendSizeBytes.Record(bpt.exportCtx, bytes, metric.WithAttributes(bpt.processorAttr...))
 	}
 }
+
+// fillRatio returns the number of sent units relative to the size trigger of the batches.
+func (bpt *batchProcessorTelemetry) fillRatio(sent int64) float64 {
+	return float64(sent) / float64(bpt.sendBatchSize)
+}
@@ -35,6 +35,7 @@ func TestBatchProcessorMetrics(t *testing.T) {
 		"timeout_trigger_send",
 		"batch_send_size",
 		"batch_send_size_bytes",
+		"batch_fill_ratio",
 	}
 	views := metricViews()
 	for i, viewName := range viewNames {
@@ -61,6 +62,8 @@ type expectedMetrics struct {
 	sizeTrigger float64
 	// processor_batch_batch_timeout_trigger_send
 	timeoutTrigger float64
+	// processor_batch_batch_fill_ratio_sum
+	fillRatioSum float64
 }
 
 func telemetryTest(t *testing.T, testFunc func(t *testing.T, tel testTelemetry, useOtel bool)) {
@@ -167,6 +170,20 @@ func (tt *testTelemetry) assertMetrics(t *testing.T, expected expectedMetrics) {
 		)
 	}
 
+	if expected.fillRatioSum > 0 {
+		name := "processor_batch_batch_fill_ratio"
+		metric := tt.getMetric(t, name, io_prometheus_client.MetricType_HISTOGRAM, metrics)
+
+		assertFloat(t, expected.fillRatioSum, metric.GetHistogram().GetSampleSum(), name)
+		assertFloat(t, expected.sendCount, float64(metric.GetHistogram().GetSampleCount()), name)
+
+		tt.assertBoundaries(t,
+			[]float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1, 1.5, 2, 5, 10, math.Inf(1)},
+			metric.GetHistogram(),
+			name,
+		)
+	}
+
 	if expected.sizeTrigger > 0 {
 		name := "processor_batch_batch_size_trigger_send"
 		metric := tt.getMetric(t, name, io_prometheus_client.MetricType_COUNTER, metrics)
@@ -252,5 +269,11 @@ func batchViews() []sdkmetric.View {
 					1000_000, 2000_000, 3000_000, 4000_000, 5000_000, 6000_000, 7000_000, 8000_000, 9000_000},
 			}},
 		),
+		sdkmetric.NewView(
+			sdkmetric.Instrument{Name: processorhelper.BuildCustomMetricName("batch", "batch_fill_ratio")},
+			sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{
+				Boundaries: []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1, 1.5, 2, 5, 10},
+			}},
+		),
 	}
 }
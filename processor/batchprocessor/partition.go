@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package batchprocessor // import "go.opentelemetry.io/collector/processor/batchprocessor"
+
+import (
+	"context"
+	"hash/fnv"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// partitionedBatcher is used when the traces are partitioned by trace ID. Each partition
+// has its own batcher, so the spans of a trace are always batched together, apart from
+// the spans of other partitions.
+type partitionedBatcher struct {
+	partitions []batcher
+}
+
+func (pb *partitionedBatcher) consume(ctx context.Context, data any) error {
+	td, ok := data.(ptrace.Traces)
+	if !ok {
+		// Only the traces are partitioned.
+		return pb.partitions[0].consume(ctx, data)
+	}
+	for i, part := range partitionTraces(td, len(pb.partitions)) {
+		if part == (ptrace.Traces{}) {
+			continue
+		}
+		if err := pb.partitions[i].consume(ctx, part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentMetadataCardinality returns the largest cardinality of the partitions, which counts
+// the combinations of metadata values seen by any of them.
+func (pb *partitionedBatcher) currentMetadataCardinality() int {
+	cardinality := 0
+	for _, b := range pb.partitions {
+		if c := b.currentMetadataCardinality(); c > cardinality {
+			cardinality = c
+		}
+	}
+	return cardinality
+}
+
+// tracePartition returns the partition of the spans with the given trace ID.
+func tracePartition(traceID pcommon.TraceID, partitions int) int {
+	h := fnv.New32a()
+	_, _ = h.Write(traceID[:])
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// partitionTraces splits the traces into the given number of partitions by trace ID. The
+// partitions without spans are left zero. The traces are returned as is when all the spans
+// belong to the same partition, otherwise they are moved to the partitions.
+func partitionTraces(td ptrace.Traces, partitions int) []ptrace.Traces {
+	parts := make([]ptrace.Traces, partitions)
+	first := -1
+	single := true
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len() && single; i++ {
+		ilss := rss.At(i).ScopeSpans()
+		for j := 0; j < ilss.Len() && single; j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				p := tracePartition(spans.At(k).TraceID(), partitions)
+				if first == -1 {
+					first = p
+				} else if p != first {
+					single = false
+					break
+				}
+			}
+		}
+	}
+	if first == -1 {
+		return parts
+	}
+	if single {
+		parts[first] = td
+		return parts
+	}
+
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		// The resource and scope of the spans are copied to each partition they appear in.
+		destRss := make([]ptrace.ResourceSpans, partitions)
+		ilss := rs.ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			destIlss := make([]ptrace.ScopeSpans, partitions)
+			ils.Spans().RemoveIf(func(span ptrace.Span) bool {
+				p := tracePartition(span.TraceID(), partitions)
+				if destIlss[p] == (ptrace.ScopeSpans{}) {
+					if destRss[p] == (ptrace.ResourceSpans{}) {
+						if parts[p] == (ptrace.Traces{}) {
+							parts[p] = ptrace.NewTraces()
+						}
+						destRss[p] = parts[p].ResourceSpans().AppendEmpty()
+						destRss[p].SetSchemaUrl(rs.SchemaUrl())
+						rs.Resource().CopyTo(destRss[p].Resource())
+					}
+					destIlss[p] = destRss[p].ScopeSpans().AppendEmpty()
+					destIlss[p].SetSchemaUrl(ils.SchemaUrl())
+					ils.Scope().CopyTo(destIlss[p].Scope())
+				}
+				span.MoveTo(destIlss[p].Spans().AppendEmpty())
+				return true
+			})
+		}
+	}
+	rss.RemoveIf(func(ptrace.ResourceSpans) bool { return true })
+	return parts
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package batchprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestTracePartition(t *testing.T) {
+	traceID := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	p := tracePartition(traceID, 8)
+	assert.GreaterOrEqual(t, p, 0)
+	assert.Less(t, p, 8)
+	assert.Equal(t, p, tracePartition(traceID, 8))
+	assert.Equal(t, 0, tracePartition(traceID, 1))
+}
+
+func TestPartitionTraces(t *testing.T) {
+	td := testdata.GenerateTraces(20)
+	rs := td.ResourceSpans().At(0)
+	rs.SetSchemaUrl("resource_schema")
+	rs.ScopeSpans().At(0).SetSchemaUrl("scope_schema")
+	spans := rs.ScopeSpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).SetTraceID([16]byte{byte(i % 5)})
+	}
+	// Add a second resource with spans of the same traces.
+	testdata.GenerateTraces(5).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	spans = td.ResourceSpans().At(1).ScopeSpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).SetTraceID([16]byte{byte(i)})
+	}
+
+	parts := partitionTraces(td, 4)
+	require.Len(t, parts, 4)
+	assert.Equal(t, 0, td.SpanCount())
+	count := 0
+	for p, part := range parts {
+		if part == (ptrace.Traces{}) {
+			continue
+		}
+		count += part.SpanCount()
+		rss := part.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			assert.Equal(t, 1, rss.At(i).ScopeSpans().Len())
+			spans := rss.At(i).ScopeSpans().At(0).Spans()
+			assert.Greater(t, spans.Len(), 0)
+			for j := 0; j < spans.Len(); j++ {
+				assert.Equal(t, p, tracePartition(spans.At(j).TraceID(), 4))
+			}
+		}
+		assert.Equal(t, "resource_schema", rss.At(0).SchemaUrl())
+		assert.Equal(t, "scope_schema", rss.At(0).ScopeSpans().At(0).SchemaUrl())
+	}
+	assert.Equal(t, 25, count)
+}
+
+func TestPartitionTracesSinglePartition(t *testing.T) {
+	td := testdata.GenerateTraces(10)
+	spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).SetTraceID([16]byte{1})
+	}
+
+	parts := partitionTraces(td, 4)
+	p := tracePartition([16]byte{1}, 4)
+	// The traces are not copied when all the spans belong to the same partition.
+	assert.Equal(t, td, parts[p])
+	for i, part := range parts {
+		if i != p {
+			assert.Equal(t, ptrace.Traces{}, part)
+		}
+	}
+}
+
+func TestPartitionTracesEmpty(t *testing.T) {
+	assert.Equal(t, make([]ptrace.Traces, 4), partitionTraces(ptrace.NewTraces(), 4))
+}
@@ -9,6 +9,7 @@ require (
 	go.opentelemetry.io/collector/component v0.88.0
 	go.opentelemetry.io/collector/config/configtelemetry v0.88.0
 	go.opentelemetry.io/collector/consumer v0.88.0
+	go.opentelemetry.io/collector/extension v0.88.0
 	go.opentelemetry.io/collector/featuregate v1.0.0-rcv0017
 	go.opentelemetry.io/collector/pdata v1.0.0-rcv0017
 	go.opentelemetry.io/otel v1.20.0
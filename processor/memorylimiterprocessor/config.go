@@ -6,38 +6,8 @@
 package memorylimiterprocessor // import "go.opentelemetry.io/collector/processor/memorylimiterprocessor"
 
 import (
-	"time"
-
-	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/internal/memorylimiter"
 )
 
 // Config defines configuration for memory memoryLimiter processor.
-type Config struct {
-	// CheckInterval is the time between measurements of memory usage for the
-	// purposes of avoiding going over the limits. Defaults to zero, so no
-	// checks will be performed.
-	CheckInterval time.Duration `mapstructure:"check_interval"`
-
-	// MemoryLimitMiB is the maximum amount of memory, in MiB, targeted to be
-	// allocated by the process.
-	MemoryLimitMiB uint32 `mapstructure:"limit_mib"`
-
-	// MemorySpikeLimitMiB is the maximum, in MiB, spike expected between the
-	// measurements of memory usage.
-	MemorySpikeLimitMiB uint32 `mapstructure:"spike_limit_mib"`
-
-	// MemoryLimitPercentage is the maximum amount of memory, in %, targeted to be
-	// allocated by the process. The fixed memory settings MemoryLimitMiB has a higher precedence.
-	MemoryLimitPercentage uint32 `mapstructure:"limit_percentage"`
-
-	// MemorySpikePercentage is the maximum, in percents against the total memory,
-	// spike expected between the measurements of memory usage.
-	MemorySpikePercentage uint32 `mapstructure:"spike_limit_percentage"`
-}
-
-var _ component.Config = (*Config)(nil)
-
-// Validate checks if the processor configuration is valid
-func (cfg *Config) Validate() error {
-	return nil
-}
+type Config = memorylimiter.Config
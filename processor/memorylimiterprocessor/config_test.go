@@ -34,5 +34,9 @@ func TestUnmarshalConfig(t *testing.T) {
 			CheckInterval:       5 * time.Second,
 			MemoryLimitMiB:      4000,
 			MemorySpikeLimitMiB: 500,
+			Budgets: map[component.DataType]uint32{
+				component.DataTypeTraces: 60,
+				component.DataTypeLogs:   40,
+			},
 		}, cfg)
 }
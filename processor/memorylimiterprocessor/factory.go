@@ -23,21 +23,22 @@ var processorCapabilities = consumer.Capabilities{MutatesData: false}
 type factory struct {
 	// memoryLimiters stores memoryLimiter instances with unique configs that multiple processors can reuse.
 	// This avoids running multiple memory checks (ie: GC) for every processor using the same processor config.
-	memoryLimiters map[component.Config]*memoryLimiter
+	memoryLimiters map[component.Config]*memoryLimiterProcessor
 	lock           sync.Mutex
 }
 
 // NewFactory returns a new factory for the Memory Limiter processor.
 func NewFactory() processor.Factory {
 	f := &factory{
-		memoryLimiters: map[component.Config]*memoryLimiter{},
+		memoryLimiters: map[component.Config]*memoryLimiterProcessor{},
 	}
 	return processor.NewFactory(
 		typeStr,
 		createDefaultConfig,
 		processor.WithTraces(f.createTracesProcessor, component.StabilityLevelBeta),
 		processor.WithMetrics(f.createMetricsProcessor, component.StabilityLevelBeta),
-		processor.WithLogs(f.createLogsProcessor, component.StabilityLevelBeta))
+		processor.WithLogs(f.createLogsProcessor, component.StabilityLevelBeta),
+		processor.WithOrderingConstraint(processor.OrderingConstraint{Position: processor.PositionFirst}))
 }
 
 // CreateDefaultConfig creates the default configuration for processor. Notice
@@ -99,7 +100,7 @@ func (f *factory) createLogsProcessor(
 
 // getMemoryLimiter checks if we have a cached memoryLimiter with a specific config,
 // otherwise initialize and add one to the store.
-func (f *factory) getMemoryLimiter(set processor.CreateSettings, cfg component.Config) (*memoryLimiter, error) {
+func (f *factory) getMemoryLimiter(set processor.CreateSettings, cfg component.Config) (*memoryLimiterProcessor, error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
@@ -107,7 +108,7 @@ func (f *factory) getMemoryLimiter(set processor.CreateSettings, cfg component.C
 		return memLimiter, nil
 	}
 
-	memLimiter, err := newMemoryLimiter(set, cfg.(*Config))
+	memLimiter, err := newMemoryLimiterProcessor(set, cfg.(*Config))
 	if err != nil {
 		return nil, err
 	}
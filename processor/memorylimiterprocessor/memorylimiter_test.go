@@ -6,20 +6,18 @@ package memorylimiterprocessor
 import (
 	"context"
 	"runtime"
-	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
-	"go.opentelemetry.io/collector/config/configtelemetry"
-	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
-	"go.opentelemetry.io/collector/internal/iruntime"
+	"go.opentelemetry.io/collector/internal/memorylimiter"
+	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -31,46 +29,40 @@ import (
 
 func TestNew(t *testing.T) {
 	type args struct {
-		nextConsumer        consumer.Traces
 		checkInterval       time.Duration
 		memoryLimitMiB      uint32
 		memorySpikeLimitMiB uint32
 	}
-	sink := new(consumertest.TracesSink)
 	tests := []struct {
 		name    string
 		args    args
-		wantErr error
+		wantErr bool
 	}{
 		{
-			name: "zero_checkInterval",
-			args: args{
-				nextConsumer: sink,
-			},
-			wantErr: errCheckIntervalOutOfRange,
+			name:    "zero_checkInterval",
+			args:    args{},
+			wantErr: true,
 		},
 		{
-			name: "zero_memAllocLimit",
+			name: "memSpikeLimit_without_memAllocLimit",
 			args: args{
-				nextConsumer:  sink,
-				checkInterval: 100 * time.Millisecond,
+				checkInterval:       100 * time.Millisecond,
+				memorySpikeLimitMiB: 1,
 			},
-			wantErr: errLimitOutOfRange,
+			wantErr: true,
 		},
 		{
 			name: "memSpikeLimit_gt_memAllocLimit",
 			args: args{
-				nextConsumer:        sink,
 				checkInterval:       100 * time.Millisecond,
 				memoryLimitMiB:      1,
 				memorySpikeLimitMiB: 2,
 			},
-			wantErr: errMemSpikeLimitOutOfRange,
+			wantErr: true,
 		},
 		{
 			name: "success",
 			args: args{
-				nextConsumer:   sink,
 				checkInterval:  100 * time.Millisecond,
 				memoryLimitMiB: 1024,
 			},
@@ -82,9 +74,9 @@ func TestNew(t *testing.T) {
 			cfg.CheckInterval = tt.args.checkInterval
 			cfg.MemoryLimitMiB = tt.args.memoryLimitMiB
 			cfg.MemorySpikeLimitMiB = tt.args.memorySpikeLimitMiB
-			got, err := newMemoryLimiter(processortest.NewNopCreateSettings(), cfg)
-			if tt.wantErr != nil {
-				assert.ErrorIs(t, err, tt.wantErr)
+			got, err := newMemoryLimiterProcessor(processortest.NewNopCreateSettings(), cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
 				return
 			}
 			assert.NoError(t, err)
@@ -94,21 +86,29 @@ func TestNew(t *testing.T) {
 	}
 }
 
+// newTestMemoryLimiterProcessor returns a memory limiter processor with a 1MiB limit reading
+// the memory usage from currentMemAlloc.
+func newTestMemoryLimiterProcessor(t *testing.T, currentMemAlloc *uint64) *memoryLimiterProcessor {
+	memorylimiter.ReadMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = *currentMemAlloc
+	}
+	t.Cleanup(func() {
+		memorylimiter.ReadMemStatsFn = runtime.ReadMemStats
+	})
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.CheckInterval = time.Hour
+	cfg.MemoryLimitMiB = 1
+	ml, err := newMemoryLimiterProcessor(processortest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	return ml
+}
+
 // TestMetricsMemoryPressureResponse manipulates results from querying memory and
 // check expected side effects.
 func TestMetricsMemoryPressureResponse(t *testing.T) {
 	var currentMemAlloc uint64
-	ml := &memoryLimiter{
-		usageChecker: memUsageChecker{
-			memAllocLimit: 1024,
-		},
-		mustRefuse: &atomic.Bool{},
-		readMemStatsFn: func(ms *runtime.MemStats) {
-			ms.Alloc = currentMemAlloc
-		},
-		obsrep: newObsReport(t),
-		logger: zap.NewNop(),
-	}
+	ml := newTestMemoryLimiterProcessor(t, &currentMemAlloc)
 	mp, err := processorhelper.NewMetricsProcessor(
 		context.Background(),
 		processortest.NewNopCreateSettings(),
@@ -123,61 +123,26 @@ func TestMetricsMemoryPressureResponse(t *testing.T) {
 	md := pmetric.NewMetrics()
 
 	// Below memAllocLimit.
-	currentMemAlloc = 800
-	ml.checkMemLimits()
+	currentMemAlloc = 800 * 1024
+	ml.memlimiter.CheckMemLimits()
 	assert.NoError(t, mp.ConsumeMetrics(ctx, md))
 
 	// Above memAllocLimit.
-	currentMemAlloc = 1800
-	ml.checkMemLimits()
-	assert.Equal(t, errDataRefused, mp.ConsumeMetrics(ctx, md))
-
-	// Check ballast effect
-	ml.ballastSize = 1000
-
-	// Below memAllocLimit accounting for ballast.
-	currentMemAlloc = 800 + ml.ballastSize
-	ml.checkMemLimits()
-	assert.NoError(t, mp.ConsumeMetrics(ctx, md))
-
-	// Above memAllocLimit even accountiing for ballast.
-	currentMemAlloc = 1800 + ml.ballastSize
-	ml.checkMemLimits()
-	assert.Equal(t, errDataRefused, mp.ConsumeMetrics(ctx, md))
-
-	// Restore ballast to default.
-	ml.ballastSize = 0
-
-	// Check spike limit
-	ml.usageChecker.memSpikeLimit = 512
-
-	// Below memSpikeLimit.
-	currentMemAlloc = 500
-	ml.checkMemLimits()
-	assert.NoError(t, mp.ConsumeMetrics(ctx, md))
-
-	// Above memSpikeLimit.
-	currentMemAlloc = 550
-	ml.checkMemLimits()
-	assert.Equal(t, errDataRefused, mp.ConsumeMetrics(ctx, md))
-
+	currentMemAlloc = 1800 * 1024
+	ml.memlimiter.CheckMemLimits()
+	err = mp.ConsumeMetrics(ctx, md)
+	assert.ErrorIs(t, err, memorylimiter.ErrDataRefused)
+	// The refused data can be sent again once the memory usage is checked again.
+	var bp consumererror.Backpressure
+	require.ErrorAs(t, err, &bp)
+	assert.Equal(t, time.Hour, bp.RetryAfter())
 }
 
 // TestTraceMemoryPressureResponse manipulates results from querying memory and
 // check expected side effects.
 func TestTraceMemoryPressureResponse(t *testing.T) {
 	var currentMemAlloc uint64
-	ml := &memoryLimiter{
-		usageChecker: memUsageChecker{
-			memAllocLimit: 1024,
-		},
-		mustRefuse: &atomic.Bool{},
-		readMemStatsFn: func(ms *runtime.MemStats) {
-			ms.Alloc = currentMemAlloc
-		},
-		obsrep: newObsReport(t),
-		logger: zap.NewNop(),
-	}
+	ml := newTestMemoryLimiterProcessor(t, &currentMemAlloc)
 	tp, err := processorhelper.NewTracesProcessor(
 		context.Background(),
 		processortest.NewNopCreateSettings(),
@@ -192,61 +157,21 @@ func TestTraceMemoryPressureResponse(t *testing.T) {
 	td := ptrace.NewTraces()
 
 	// Below memAllocLimit.
-	currentMemAlloc = 800
-	ml.checkMemLimits()
+	currentMemAlloc = 800 * 1024
+	ml.memlimiter.CheckMemLimits()
 	assert.NoError(t, tp.ConsumeTraces(ctx, td))
 
 	// Above memAllocLimit.
-	currentMemAlloc = 1800
-	ml.checkMemLimits()
-	assert.Equal(t, errDataRefused, tp.ConsumeTraces(ctx, td))
-
-	// Check ballast effect
-	ml.ballastSize = 1000
-
-	// Below memAllocLimit accounting for ballast.
-	currentMemAlloc = 800 + ml.ballastSize
-	ml.checkMemLimits()
-	assert.NoError(t, tp.ConsumeTraces(ctx, td))
-
-	// Above memAllocLimit even accountiing for ballast.
-	currentMemAlloc = 1800 + ml.ballastSize
-	ml.checkMemLimits()
-	assert.Equal(t, errDataRefused, tp.ConsumeTraces(ctx, td))
-
-	// Restore ballast to default.
-	ml.ballastSize = 0
-
-	// Check spike limit
-	ml.usageChecker.memSpikeLimit = 512
-
-	// Below memSpikeLimit.
-	currentMemAlloc = 500
-	ml.checkMemLimits()
-	assert.NoError(t, tp.ConsumeTraces(ctx, td))
-
-	// Above memSpikeLimit.
-	currentMemAlloc = 550
-	ml.checkMemLimits()
-	assert.Equal(t, errDataRefused, tp.ConsumeTraces(ctx, td))
-
+	currentMemAlloc = 1800 * 1024
+	ml.memlimiter.CheckMemLimits()
+	assert.ErrorIs(t, tp.ConsumeTraces(ctx, td), memorylimiter.ErrDataRefused)
 }
 
 // TestLogMemoryPressureResponse manipulates results from querying memory and
 // check expected side effects.
 func TestLogMemoryPressureResponse(t *testing.T) {
 	var currentMemAlloc uint64
-	ml := &memoryLimiter{
-		usageChecker: memUsageChecker{
-			memAllocLimit: 1024,
-		},
-		mustRefuse: &atomic.Bool{},
-		readMemStatsFn: func(ms *runtime.MemStats) {
-			ms.Alloc = currentMemAlloc
-		},
-		obsrep: newObsReport(t),
-		logger: zap.NewNop(),
-	}
+	ml := newTestMemoryLimiterProcessor(t, &currentMemAlloc)
 	lp, err := processorhelper.NewLogsProcessor(
 		context.Background(),
 		processortest.NewNopCreateSettings(),
@@ -261,186 +186,64 @@ func TestLogMemoryPressureResponse(t *testing.T) {
 	ld := plog.NewLogs()
 
 	// Below memAllocLimit.
-	currentMemAlloc = 800
-	ml.checkMemLimits()
+	currentMemAlloc = 800 * 1024
+	ml.memlimiter.CheckMemLimits()
 	assert.NoError(t, lp.ConsumeLogs(ctx, ld))
 
 	// Above memAllocLimit.
-	currentMemAlloc = 1800
-	ml.checkMemLimits()
-	assert.Equal(t, errDataRefused, lp.ConsumeLogs(ctx, ld))
-
-	// Check ballast effect
-	ml.ballastSize = 1000
-
-	// Below memAllocLimit accounting for ballast.
-	currentMemAlloc = 800 + ml.ballastSize
-	ml.checkMemLimits()
-	assert.NoError(t, lp.ConsumeLogs(ctx, ld))
-
-	// Above memAllocLimit even accountiing for ballast.
-	currentMemAlloc = 1800 + ml.ballastSize
-	ml.checkMemLimits()
-	assert.Equal(t, errDataRefused, lp.ConsumeLogs(ctx, ld))
-
-	// Restore ballast to default.
-	ml.ballastSize = 0
-
-	// Check spike limit
-	ml.usageChecker.memSpikeLimit = 512
-
-	// Below memSpikeLimit.
-	currentMemAlloc = 500
-	ml.checkMemLimits()
-	assert.NoError(t, lp.ConsumeLogs(ctx, ld))
-
-	// Above memSpikeLimit.
-	currentMemAlloc = 550
-	ml.checkMemLimits()
-	assert.Equal(t, errDataRefused, lp.ConsumeLogs(ctx, ld))
+	currentMemAlloc = 1800 * 1024
+	ml.memlimiter.CheckMemLimits()
+	assert.ErrorIs(t, lp.ConsumeLogs(ctx, ld), memorylimiter.ErrDataRefused)
 }
 
-func TestGetDecision(t *testing.T) {
-	t.Run("fixed_limit", func(t *testing.T) {
-		d, err := getMemUsageChecker(&Config{MemoryLimitMiB: 100, MemorySpikeLimitMiB: 20}, zap.NewNop())
-		require.NoError(t, err)
-		assert.Equal(t, &memUsageChecker{
-			memAllocLimit: 100 * mibBytes,
-			memSpikeLimit: 20 * mibBytes,
-		}, d)
-	})
-	t.Run("fixed_limit_error", func(t *testing.T) {
-		d, err := getMemUsageChecker(&Config{MemoryLimitMiB: 20, MemorySpikeLimitMiB: 100}, zap.NewNop())
-		require.Error(t, err)
-		assert.Nil(t, d)
-	})
-
-	t.Cleanup(func() {
-		getMemoryFn = iruntime.TotalMemory
-	})
-	getMemoryFn = func() (uint64, error) {
-		return 100 * mibBytes, nil
+// TestBudgetsMemoryPressureResponse checks that above the soft limit only the signal using
+// more than its budget is refused.
+func TestBudgetsMemoryPressureResponse(t *testing.T) {
+	var currentMemAlloc uint64
+	memorylimiter.ReadMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = currentMemAlloc
 	}
-	t.Run("percentage_limit", func(t *testing.T) {
-		d, err := getMemUsageChecker(&Config{MemoryLimitPercentage: 50, MemorySpikePercentage: 10}, zap.NewNop())
-		require.NoError(t, err)
-		assert.Equal(t, &memUsageChecker{
-			memAllocLimit: 50 * mibBytes,
-			memSpikeLimit: 10 * mibBytes,
-		}, d)
-	})
-	t.Run("percentage_limit_error", func(t *testing.T) {
-		d, err := getMemUsageChecker(&Config{MemoryLimitPercentage: 101, MemorySpikePercentage: 10}, zap.NewNop())
-		require.Error(t, err)
-		assert.Nil(t, d)
-		d, err = getMemUsageChecker(&Config{MemoryLimitPercentage: 99, MemorySpikePercentage: 101}, zap.NewNop())
-		require.Error(t, err)
-		assert.Nil(t, d)
+	t.Cleanup(func() {
+		memorylimiter.ReadMemStatsFn = runtime.ReadMemStats
 	})
-}
-
-func TestRefuseDecision(t *testing.T) {
-	decison1000Limit30Spike30, err := newPercentageMemUsageChecker(1000, 60, 30)
-	require.NoError(t, err)
-	decison1000Limit60Spike50, err := newPercentageMemUsageChecker(1000, 60, 50)
-	require.NoError(t, err)
-	decison1000Limit40Spike20, err := newPercentageMemUsageChecker(1000, 40, 20)
-	require.NoError(t, err)
-	decison1000Limit40Spike60, err := newPercentageMemUsageChecker(1000, 40, 60)
-	require.Error(t, err)
-	assert.Nil(t, decison1000Limit40Spike60)
-
-	tests := []struct {
-		name         string
-		usageChecker memUsageChecker
-		ms           *runtime.MemStats
-		shouldRefuse bool
-	}{
-		{
-			name:         "should refuse over limit",
-			usageChecker: *decison1000Limit30Spike30,
-			ms:           &runtime.MemStats{Alloc: 600},
-			shouldRefuse: true,
-		},
-		{
-			name:         "should not refuse",
-			usageChecker: *decison1000Limit30Spike30,
-			ms:           &runtime.MemStats{Alloc: 100},
-			shouldRefuse: false,
-		},
-		{
-			name: "should not refuse spike, fixed usageChecker",
-			usageChecker: memUsageChecker{
-				memAllocLimit: 600,
-				memSpikeLimit: 500,
-			},
-			ms:           &runtime.MemStats{Alloc: 300},
-			shouldRefuse: true,
-		},
-		{
-			name:         "should refuse, spike, percentage usageChecker",
-			usageChecker: *decison1000Limit60Spike50,
-			ms:           &runtime.MemStats{Alloc: 300},
-			shouldRefuse: true,
-		},
-		{
-			name:         "should refuse, spike, percentage usageChecker",
-			usageChecker: *decison1000Limit40Spike20,
-			ms:           &runtime.MemStats{Alloc: 250},
-			shouldRefuse: true,
-		},
-	}
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			shouldRefuse := test.usageChecker.aboveSoftLimit(test.ms)
-			assert.Equal(t, test.shouldRefuse, shouldRefuse)
-		})
-	}
-}
 
-func TestBallastSize(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
-	cfg.CheckInterval = 10 * time.Second
-	cfg.MemoryLimitMiB = 1024
-	got, err := newMemoryLimiter(processortest.NewNopCreateSettings(), cfg)
-	require.NoError(t, err)
-	require.NoError(t, got.start(context.Background(), &host{ballastSize: 113}))
-	assert.Equal(t, uint64(113), got.ballastSize)
-	require.NoError(t, got.shutdown(context.Background()))
-}
-
-type host struct {
-	ballastSize uint64
-	component.Host
-}
-
-func (h *host) GetExtensions() map[component.ID]component.Component {
-	ret := make(map[component.ID]component.Component)
-	ret[component.NewID("ballast")] = &ballastExtension{ballastSize: h.ballastSize}
-	return ret
-}
-
-type ballastExtension struct {
-	ballastSize uint64
-	component.StartFunc
-	component.ShutdownFunc
-}
-
-func (be *ballastExtension) GetBallastSize() uint64 {
-	return be.ballastSize
-}
-
-func newObsReport(t *testing.T) *processorhelper.ObsReport {
-	set := processorhelper.ObsReportSettings{
-		ProcessorID:             component.NewID(typeStr),
-		ProcessorCreateSettings: processortest.NewNopCreateSettings(),
+	cfg.CheckInterval = time.Hour
+	cfg.MemoryLimitMiB = 1
+	cfg.Budgets = map[component.DataType]uint32{
+		component.DataTypeTraces: 60,
+		component.DataTypeLogs:   40,
 	}
-	set.ProcessorCreateSettings.MetricsLevel = configtelemetry.LevelNone
-
-	proc, err := processorhelper.NewObsReport(set)
+	require.NoError(t, cfg.Validate())
+	ml, err := newMemoryLimiterProcessor(processortest.NewNopCreateSettings(), cfg)
 	require.NoError(t, err)
 
-	return proc
+	ctx := context.Background()
+	td := testdata.GenerateTraces(1)
+	ld := testdata.GenerateLogs(100)
+
+	// Below the soft limit.
+	currentMemAlloc = 700 * 1024
+	_, err = ml.processTraces(ctx, td)
+	assert.NoError(t, err)
+	_, err = ml.processLogs(ctx, ld)
+	assert.NoError(t, err)
+	ml.memlimiter.CheckMemLimits()
+
+	// Above the soft limit, the logs are most of the incoming data.
+	currentMemAlloc = 900 * 1024
+	_, err = ml.processTraces(ctx, td)
+	assert.NoError(t, err)
+	_, err = ml.processLogs(ctx, ld)
+	assert.NoError(t, err)
+	ml.memlimiter.CheckMemLimits()
+
+	_, err = ml.processTraces(ctx, td)
+	assert.NoError(t, err)
+	_, err = ml.processLogs(ctx, ld)
+	assert.ErrorIs(t, err, memorylimiter.ErrDataRefused)
+	_, err = ml.processMetrics(ctx, testdata.GenerateMetrics(1))
+	assert.ErrorIs(t, err, memorylimiter.ErrDataRefused)
 }
 
 func TestNoDataLoss(t *testing.T) {
@@ -470,7 +273,7 @@ func TestNoDataLoss(t *testing.T) {
 
 	set := processortest.NewNopCreateSettings()
 
-	limiter, err := newMemoryLimiter(set, cfg)
+	limiter, err := newMemoryLimiterProcessor(set, cfg)
 	require.NoError(t, err)
 
 	processor, err := processorhelper.NewLogsProcessor(context.Background(), processor.CreateSettings{}, cfg, exporter,
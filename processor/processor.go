@@ -73,6 +73,9 @@ type Factory interface {
 	// LogsProcessorStability gets the stability level of the LogsProcessor.
 	LogsProcessorStability() component.StabilityLevel
 
+	// OrderingConstraint gets where the processor must, or should, be placed in the pipelines.
+	OrderingConstraint() OrderingConstraint
+
 	unexportedFactoryFunc()
 }
 
@@ -91,6 +94,38 @@ func (f factoryOptionFunc) applyProcessorFactoryOption(o *factory) {
 	f(o)
 }
 
+// Position is a position of a processor in the processors of a pipeline.
+type Position int
+
+const (
+	// PositionAny is any position. This is the default.
+	PositionAny Position = iota
+	// PositionFirst is the first position, right after the receivers.
+	PositionFirst
+	// PositionLast is the last position, right before the exporters.
+	PositionLast
+)
+
+// String returns the string representation of the position.
+func (p Position) String() string {
+	switch p {
+	case PositionFirst:
+		return "first"
+	case PositionLast:
+		return "last"
+	}
+	return "any"
+}
+
+// OrderingConstraint is the position of a processor in the pipelines.
+type OrderingConstraint struct {
+	// Position is where the processor is placed in the processors of the pipelines.
+	Position Position
+
+	// Required makes the pipelines that do not respect the position fail to build. Otherwise a warning is logged.
+	Required bool
+}
+
 // CreateTracesFunc is the equivalent of Factory.CreateTraces().
 type CreateTracesFunc func(context.Context, CreateSettings, component.Config, consumer.Traces) (Traces, error)
 
@@ -147,6 +182,7 @@ type factory struct {
 	metricsStabilityLevel component.StabilityLevel
 	CreateLogsFunc
 	logsStabilityLevel component.StabilityLevel
+	orderingConstraint OrderingConstraint
 }
 
 func (f *factory) Type() component.Type {
@@ -167,6 +203,10 @@ func (f factory) LogsProcessorStability() component.StabilityLevel {
 	return f.logsStabilityLevel
 }
 
+func (f factory) OrderingConstraint() OrderingConstraint {
+	return f.orderingConstraint
+}
+
 // WithTraces overrides the default "error not supported" implementation for CreateTraces and the default "undefined" stability level.
 func WithTraces(createTraces CreateTracesFunc, sl component.StabilityLevel) FactoryOption {
 	return factoryOptionFunc(func(o *factory) {
@@ -191,6 +231,13 @@ func WithLogs(createLogs CreateLogsFunc, sl component.StabilityLevel) FactoryOpt
 	})
 }
 
+// WithOrderingConstraint overrides the default "any position" ordering constraint.
+func WithOrderingConstraint(constraint OrderingConstraint) FactoryOption {
+	return factoryOptionFunc(func(o *factory) {
+		o.orderingConstraint = constraint
+	})
+}
+
 // NewFactory returns a Factory.
 func NewFactory(cfgType component.Type, createDefaultConfig component.CreateDefaultConfigFunc, options ...FactoryOption) Factory {
 	f := &factory{
@@ -30,6 +30,7 @@ func TestNewFactory(t *testing.T) {
 	assert.Error(t, err)
 	_, err = factory.CreateLogsProcessor(context.Background(), CreateSettings{}, &defaultCfg, nil)
 	assert.Error(t, err)
+	assert.Equal(t, OrderingConstraint{Position: PositionAny}, factory.OrderingConstraint())
 }
 
 func TestNewFactoryWithOptions(t *testing.T) {
@@ -40,7 +41,8 @@ func TestNewFactoryWithOptions(t *testing.T) {
 		func() component.Config { return &defaultCfg },
 		WithTraces(createTraces, component.StabilityLevelAlpha),
 		WithMetrics(createMetrics, component.StabilityLevelBeta),
-		WithLogs(createLogs, component.StabilityLevelUnmaintained))
+		WithLogs(createLogs, component.StabilityLevelUnmaintained),
+		WithOrderingConstraint(OrderingConstraint{Position: PositionFirst, Required: true}))
 	assert.EqualValues(t, typeStr, factory.Type())
 	assert.EqualValues(t, &defaultCfg, factory.CreateDefaultConfig())
 
@@ -55,6 +57,14 @@ func TestNewFactoryWithOptions(t *testing.T) {
 	assert.Equal(t, component.StabilityLevelUnmaintained, factory.LogsProcessorStability())
 	_, err = factory.CreateLogsProcessor(context.Background(), CreateSettings{}, &defaultCfg, nil)
 	assert.NoError(t, err)
+
+	assert.Equal(t, OrderingConstraint{Position: PositionFirst, Required: true}, factory.OrderingConstraint())
+}
+
+func TestPositionString(t *testing.T) {
+	assert.Equal(t, "any", PositionAny.String())
+	assert.Equal(t, "first", PositionFirst.String())
+	assert.Equal(t, "last", PositionLast.String())
 }
 
 func TestMakeFactoryMap(t *testing.T) {
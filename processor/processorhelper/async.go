@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper // import "go.opentelemetry.io/collector/processor/processorhelper"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/multierr"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// errProcessorShutdown is returned when an asynchronous processor emits data after it was shut down.
+var errProcessorShutdown = errors.New("processor is shut down")
+
+// flushContextKey marks the context passed to the flush function, so the data it emits is still sent
+// to the next component while the processor is shutting down.
+type flushContextKey struct{}
+
+// asyncProcessor implements the lifecycle shared by the asynchronous processors. It ensures that
+// no data is sent to the next component once Shutdown returns.
+type asyncProcessor struct {
+	component.StartFunc
+	// shutdown is the whole shutdown of the processor, bounded by the shutdown timeout.
+	shutdown     component.ShutdownFunc
+	shutdownFunc component.ShutdownFunc
+	flushFunc    FlushFunc
+	storage      *processorStorage
+
+	obsrep *ObsReport
+
+	// stopping is set once the shutdown starts, only the data emitted by the flush function is sent
+	// afterwards. stopped is set once the data is flushed, or the shutdown is abandoned.
+	stopping atomic.Bool
+	stopped  atomic.Bool
+	// mu is held for reading while data is emitted, so the shutdown waits for the in-flight emits.
+	mu sync.RWMutex
+}
+
+func newAsyncProcessor(set ObsReportSettings, bs *baseSettings, dataType component.DataType) (*asyncProcessor, error) {
+	obsrep, err := NewObsReport(set)
+	if err != nil {
+		return nil, err
+	}
+	ps := newProcessorStorage(set.ProcessorID, bs, dataType)
+	start, _ := ps.lifecycle(bs.StartFunc, nil)
+	ap := &asyncProcessor{
+		StartFunc:    start,
+		shutdownFunc: bs.ShutdownFunc,
+		flushFunc:    bs.flushFunc,
+		storage:      ps,
+		obsrep:       obsrep,
+	}
+	ap.shutdown = shutdownWithTimeout(set.ProcessorCreateSettings.Logger, bs.shutdownTimeout, ap.stop)
+	return ap, nil
+}
+
+// emit calls send unless the processor is shut down, or shutting down and the data is not emitted
+// by the flush function.
+func (ap *asyncProcessor) emit(ctx context.Context, send func() error) error {
+	ap.mu.RLock()
+	defer ap.mu.RUnlock()
+	if ap.stopped.Load() || (ap.stopping.Load() && ctx.Value(flushContextKey{}) == nil) {
+		return errProcessorShutdown
+	}
+	return send()
+}
+
+// Shutdown stops sending the data emitted on the processor's own schedule, calls the shutdown function,
+// so the processor stops emitting data, then the flush function to emit the buffered data, waits for the
+// in-flight emits, and finally persists the state of the processor. The data emitted once Shutdown returns
+// is dropped, even if the shutdown was abandoned because it did not complete in time.
+func (ap *asyncProcessor) Shutdown(ctx context.Context) error {
+	err := ap.shutdown.Shutdown(ctx)
+	ap.stopped.Store(true)
+	return err
+}
+
+func (ap *asyncProcessor) stop(ctx context.Context) error {
+	ap.stopping.Store(true)
+	ap.waitForEmits()
+
+	var errs error
+	if ap.shutdownFunc != nil {
+		errs = ap.shutdownFunc(ctx)
+	}
+	if ap.flushFunc != nil {
+		errs = multierr.Append(errs, ap.flushFunc(context.WithValue(ctx, flushContextKey{}, true)))
+	}
+	ap.stopped.Store(true)
+	ap.waitForEmits()
+	return multierr.Append(errs, ap.storage.persist(ctx))
+}
+
+// waitForEmits waits for the in-flight emits to complete.
+func (ap *asyncProcessor) waitForEmits() {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+}
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper // import "go.opentelemetry.io/collector/processor/processorhelper"
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// OrderingKeyFunc returns the key of a resource. The data of the resources with the same key
+// is always processed by the same worker, in the order it is received.
+type OrderingKeyFunc func(pcommon.Resource) string
+
+// WithConcurrency sets the number of worker goroutines processing the incoming data in parallel.
+// The data is split by resource between the workers and merged again, in the order of the workers,
+// before being sent to the next component. The data of a worker is processed in the order it is
+// received, so use WithOrderingKey to preserve the order of the data of a resource.
+// The default is 1, that means the data is processed on the goroutine of the caller.
+// It is ignored by asynchronous processors.
+func WithConcurrency(n int) Option {
+	return func(o *baseSettings) {
+		o.concurrency = n
+	}
+}
+
+// WithOrderingKey sets the function selecting the worker of each resource when WithConcurrency is used.
+// By default the resources of the incoming data are spread between the workers.
+func WithOrderingKey(keyFunc OrderingKeyFunc) Option {
+	return func(o *baseSettings) {
+		o.orderingKey = keyFunc
+	}
+}
+
+// workerPool runs the processing function of a synchronous processor on a fixed set of workers.
+type workerPool struct {
+	orderingKey OrderingKeyFunc
+	obsrep      *ObsReport
+	dataType    component.DataType
+
+	// mu guards workers, it is held for reading while jobs are running so stop
+	// waits for the in-flight jobs before returning.
+	mu      sync.RWMutex
+	workers []chan func()
+	size    int
+	wg      sync.WaitGroup
+}
+
+// newWorkerPool returns nil if the processing function must run on the goroutine of the caller.
+// The time the jobs wait for their worker is reported as the await duration of the dataType.
+func newWorkerPool(bs *baseSettings, obsrep *ObsReport, dataType component.DataType) *workerPool {
+	if bs.concurrency <= 1 {
+		return nil
+	}
+	return &workerPool{
+		orderingKey: bs.orderingKey,
+		obsrep:      obsrep,
+		dataType:    dataType,
+		size:        bs.concurrency,
+	}
+}
+
+func (wp *workerPool) start() {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	if wp.workers != nil {
+		return
+	}
+	wp.workers = make([]chan func(), wp.size)
+	for i := range wp.workers {
+		jobs := make(chan func())
+		wp.workers[i] = jobs
+		wp.wg.Add(1)
+		go func() {
+			defer wp.wg.Done()
+			for job := range jobs {
+				job()
+			}
+		}()
+	}
+}
+
+func (wp *workerPool) stop() {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	for _, jobs := range wp.workers {
+		close(jobs)
+	}
+	wp.wg.Wait()
+	wp.workers = nil
+}
+
+// workerIndex returns the worker of the i-th resource of the incoming data.
+func (wp *workerPool) workerIndex(i int, res pcommon.Resource) int {
+	if wp.orderingKey == nil {
+		return i % wp.size
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(wp.orderingKey(res)))
+	return int(h.Sum32() % uint32(wp.size))
+}
+
+// run runs the i-th job on the i-th worker, skipping the nil jobs, and waits for all of them.
+// The jobs run on the goroutine of the caller if the pool is not started.
+func (wp *workerPool) run(ctx context.Context, jobs []func()) {
+	wp.mu.RLock()
+	defer wp.mu.RUnlock()
+	if wp.workers == nil {
+		for _, job := range jobs {
+			if job != nil {
+				job()
+			}
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		if job == nil {
+			continue
+		}
+		wg.Add(1)
+		job := job
+		queued := time.Now()
+		wp.workers[i] <- func() {
+			defer wg.Done()
+			wp.obsrep.AwaitDuration(ctx, wp.dataType, time.Since(queued))
+			job()
+		}
+	}
+	wg.Wait()
+}
+
+// lifecycle wraps the start and shutdown functions of a processor to start and stop the worker pool.
+func (wp *workerPool) lifecycle(start component.StartFunc, shutdown component.ShutdownFunc) (component.StartFunc, component.ShutdownFunc) {
+	if wp == nil {
+		return start, shutdown
+	}
+	return func(ctx context.Context, host component.Host) error {
+			if err := start.Start(ctx, host); err != nil {
+				return err
+			}
+			wp.start()
+			return nil
+		}, func(ctx context.Context) error {
+			wp.stop()
+			return shutdown.Shutdown(ctx)
+		}
+}
+
+// assign returns the worker of each of the n resources of the incoming data, and whether
+// the resources are assigned to several workers.
+func (wp *workerPool) assign(n int, resource func(int) pcommon.Resource) ([]int, bool) {
+	workers := make([]int, n)
+	split := false
+	for i := range workers {
+		workers[i] = wp.workerIndex(i, resource(i))
+		split = split || workers[i] != workers[0]
+	}
+	return workers, split
+}
+
+// result returns whether all the jobs skipped their data, and the error of the jobs
+// ignoring the ones that skipped their data.
+func result(jobs []func(), errs []error) (bool, error) {
+	var err error
+	skipped := true
+	for w, job := range jobs {
+		if job == nil {
+			continue
+		}
+		switch {
+		case errs[w] == nil:
+			skipped = false
+		case errors.Is(errs[w], ErrSkipProcessingData):
+		default:
+			skipped = false
+			err = multierr.Append(err, errs[w])
+		}
+	}
+	return skipped, err
+}
@@ -0,0 +1,217 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+// generateTraces returns traces with one span per resource, the resources have the given keys.
+func generateTraces(keys ...string) ptrace.Traces {
+	td := ptrace.NewTraces()
+	for _, key := range keys {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("key", key)
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName(key)
+	}
+	return td
+}
+
+func resourceKey(res pcommon.Resource) string {
+	v, _ := res.Attributes().Get("key")
+	return v.Str()
+}
+
+// barrier returns a function blocking until it is called n times, or failing after a timeout.
+func barrier(n int) func() error {
+	var wg sync.WaitGroup
+	wg.Add(n)
+	return func() error {
+		wg.Done()
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-time.After(5 * time.Second):
+			return errors.New("the data was not processed in parallel")
+		}
+	}
+}
+
+func TestNewTracesProcessor_WithConcurrency(t *testing.T) {
+	wait := barrier(4)
+	sink := new(consumertest.TracesSink)
+	tp, err := NewTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, sink,
+		func(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+			return td, wait()
+		},
+		WithConcurrency(4))
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces("a", "b", "c", "d", "e", "f", "g", "h")))
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, 8, sink.SpanCount())
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	// Once shut down the data is processed on the goroutine of the caller.
+	wait = func() error { return nil }
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces("a", "b")))
+	assert.Equal(t, 10, sink.SpanCount())
+}
+
+func TestNewTracesProcessor_WithConcurrencyDurations(t *testing.T) {
+	tt, err := obsreporttest.SetupTelemetry(processorID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	set := processortest.NewNopCreateSettings()
+	set.ID = processorID
+	set.TelemetrySettings = tt.TelemetrySettings
+	tp, err := NewTracesProcessor(context.Background(), set, &testTracesCfg, consumertest.NewNop(), newTestTProcessor(nil), WithConcurrency(4))
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+
+	// Each worker processes the data of two resources.
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces("a", "b", "c", "d", "e", "f", "g", "h")))
+	require.NoError(t, tp.Shutdown(context.Background()))
+	require.NoError(t, tt.CheckProcessorDurations(component.DataTypeTraces, 4, 4))
+}
+
+func TestNewTracesProcessor_WithOrderingKey(t *testing.T) {
+	var mu sync.Mutex
+	var calls [][]string
+	tp, err := NewTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, consumertest.NewNop(),
+		func(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+			var keys []string
+			for i := 0; i < td.ResourceSpans().Len(); i++ {
+				keys = append(keys, resourceKey(td.ResourceSpans().At(i).Resource()))
+			}
+			mu.Lock()
+			calls = append(calls, keys)
+			mu.Unlock()
+			return td, nil
+		},
+		WithConcurrency(3),
+		WithOrderingKey(resourceKey))
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, tp.Shutdown(context.Background())) }()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces("a", "b", "c", "a", "d", strconv.Itoa(i))))
+	}
+
+	// The resources with the same key are always processed together, in a single call.
+	const key = "a"
+	for _, keys := range calls {
+		count := 0
+		for _, k := range keys {
+			if k == key {
+				count++
+			}
+		}
+		assert.Contains(t, []int{0, 2}, count)
+	}
+}
+
+func TestNewTracesProcessor_WithConcurrencyError(t *testing.T) {
+	want := errors.New("my_error")
+	sink := new(consumertest.TracesSink)
+	tp, err := NewTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, sink,
+		func(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+			for i := 0; i < td.ResourceSpans().Len(); i++ {
+				switch resourceKey(td.ResourceSpans().At(i).Resource()) {
+				case "fail":
+					return td, want
+				case "skip":
+					return td, ErrSkipProcessingData
+				}
+			}
+			return td, nil
+		},
+		WithConcurrency(2))
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, tp.Shutdown(context.Background())) }()
+
+	// The failed data is handed back whole to the error handling.
+	td := generateTraces("a", "fail", "b", "c")
+	assert.ErrorIs(t, tp.ConsumeTraces(context.Background(), td), want)
+	assert.Equal(t, 4, td.SpanCount())
+	assert.Equal(t, 0, sink.SpanCount())
+
+	// Only the skipped part of the data is dropped.
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces("a", "skip", "b", "c")))
+	assert.Equal(t, 2, sink.SpanCount())
+
+	// Nothing is sent if all the data is skipped.
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces("skip", "skip")))
+	assert.Len(t, sink.AllTraces(), 1)
+}
+
+func TestNewMetricsProcessor_WithConcurrency(t *testing.T) {
+	wait := barrier(2)
+	sink := new(consumertest.MetricsSink)
+	mp, err := NewMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testMetricsCfg, sink,
+		func(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+			return md, wait()
+		},
+		WithConcurrency(2))
+	require.NoError(t, err)
+	require.NoError(t, mp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, mp.Shutdown(context.Background())) }()
+
+	md := pmetric.NewMetrics()
+	for i := 0; i < 4; i++ {
+		md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty()
+	}
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), md))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, 4, sink.DataPointCount())
+}
+
+func TestNewLogsProcessor_WithConcurrency(t *testing.T) {
+	wait := barrier(2)
+	sink := new(consumertest.LogsSink)
+	lp, err := NewLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testLogsCfg, sink,
+		func(_ context.Context, ld plog.Logs) (plog.Logs, error) {
+			return ld, wait()
+		},
+		WithConcurrency(2))
+	require.NoError(t, err)
+	require.NoError(t, lp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, lp.Shutdown(context.Background())) }()
+
+	ld := plog.NewLogs()
+	for i := 0; i < 4; i++ {
+		ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	}
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+	require.Len(t, sink.AllLogs(), 1)
+	assert.Equal(t, 4, sink.LogRecordCount())
+}
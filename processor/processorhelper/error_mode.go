@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper // import "go.opentelemetry.io/collector/processor/processorhelper"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+)
+
+// ErrorMode defines how a processor handles the errors returned by its processing function.
+// It can be used in the processor configuration, as it unmarshals from text. ErrorModeRoute is rejected
+// when unmarshaling, since nothing in the configuration references the error consumer.
+type ErrorMode string
+
+const (
+	// ErrorModePropagate returns the error to the preceding component in the pipeline. This is the default.
+	ErrorModePropagate ErrorMode = "propagate"
+
+	// ErrorModeDrop drops the data that failed to be processed, logs the error and records the data
+	// as dropped in the processor telemetry. No error is returned to the preceding component.
+	ErrorModeDrop ErrorMode = "drop"
+
+	// ErrorModeRoute sends the data that failed to be processed to the consumer set with WithErrorTraces,
+	// WithErrorMetrics or WithErrorLogs, for instance the first component of a debug pipeline, instead of
+	// the next component. Only the error returned by that consumer is returned to the preceding component.
+	// It can only be set by the processor itself with WithErrorMode, not in the configuration.
+	ErrorModeRoute ErrorMode = "route"
+)
+
+// errNilErrorConsumer is returned when ErrorModeRoute is used without an error consumer for the signal.
+var errNilErrorConsumer = errors.New("the error consumer must be set to route the data that failed to be processed")
+
+// errRouteNotConfigurable is returned when unmarshaling ErrorModeRoute from the configuration.
+var errRouteNotConfigurable = fmt.Errorf("error mode %q cannot be configured, the error consumer is set by the processor", ErrorModeRoute)
+
+// UnmarshalText unmarshalls text to an ErrorMode.
+func (m *ErrorMode) UnmarshalText(text []byte) error {
+	if m == nil {
+		return errors.New("cannot unmarshal to a nil *ErrorMode")
+	}
+
+	mode := ErrorMode(strings.ToLower(string(text)))
+	switch mode {
+	case ErrorModePropagate, ErrorModeDrop:
+		*m = mode
+		return nil
+	case ErrorModeRoute:
+		return errRouteNotConfigurable
+	}
+	return fmt.Errorf("unknown error mode %q", mode)
+}
+
+// WithErrorMode sets how the errors returned by the processing function are handled.
+// The default is ErrorModePropagate. It is ignored by asynchronous processors.
+func WithErrorMode(mode ErrorMode) Option {
+	return func(o *baseSettings) {
+		o.errorMode = mode
+	}
+}
+
+// WithErrorTraces sets the consumer receiving the traces that failed to be processed when ErrorModeRoute is used.
+func WithErrorTraces(errorConsumer consumer.Traces) Option {
+	return func(o *baseSettings) {
+		o.errorTraces = errorConsumer
+	}
+}
+
+// WithErrorMetrics sets the consumer receiving the metrics that failed to be processed when ErrorModeRoute is used.
+func WithErrorMetrics(errorConsumer consumer.Metrics) Option {
+	return func(o *baseSettings) {
+		o.errorMetrics = errorConsumer
+	}
+}
+
+// WithErrorLogs sets the consumer receiving the logs that failed to be processed when ErrorModeRoute is used.
+func WithErrorLogs(errorConsumer consumer.Logs) Option {
+	return func(o *baseSettings) {
+		o.errorLogs = errorConsumer
+	}
+}
+
+// errorHandler applies the ErrorMode to the errors returned by the processing function.
+type errorHandler struct {
+	mode   ErrorMode
+	logger *zap.Logger
+	obsrep *ObsReport
+
+	traces  consumer.Traces
+	metrics consumer.Metrics
+	logs    consumer.Logs
+}
+
+func newErrorHandler(set processor.CreateSettings, bs *baseSettings, obsrep *ObsReport, hasErrorConsumer bool) (*errorHandler, error) {
+	eh := &errorHandler{
+		mode:    bs.errorMode,
+		logger:  set.Logger,
+		obsrep:  obsrep,
+		traces:  bs.errorTraces,
+		metrics: bs.errorMetrics,
+		logs:    bs.errorLogs,
+	}
+	switch eh.mode {
+	case "", ErrorModePropagate:
+		eh.mode = ErrorModePropagate
+	case ErrorModeDrop:
+	case ErrorModeRoute:
+		if !hasErrorConsumer {
+			return nil, errNilErrorConsumer
+		}
+	default:
+		return nil, fmt.Errorf("unknown error mode %q", eh.mode)
+	}
+	return eh, nil
+}
+
+func (eh *errorHandler) handleTraces(ctx context.Context, td ptrace.Traces, err error) error {
+	switch eh.mode {
+	case ErrorModeDrop:
+		numSpans := td.SpanCount()
+		eh.logger.Warn("Dropping data because it failed to be processed.", zap.Error(err), zap.Int("dropped_spans", numSpans))
+		eh.obsrep.TracesDropped(ctx, numSpans)
+		return nil
+	case ErrorModeRoute:
+		eh.logger.Debug("Routing data to the error consumer because it failed to be processed.", zap.Error(err))
+		return eh.traces.ConsumeTraces(ctx, td)
+	}
+	return err
+}
+
+func (eh *errorHandler) handleMetrics(ctx context.Context, md pmetric.Metrics, err error) error {
+	switch eh.mode {
+	case ErrorModeDrop:
+		numPoints := md.DataPointCount()
+		eh.logger.Warn("Dropping data because it failed to be processed.", zap.Error(err), zap.Int("dropped_data_points", numPoints))
+		eh.obsrep.MetricsDropped(ctx, numPoints)
+		return nil
+	case ErrorModeRoute:
+		eh.logger.Debug("Routing data to the error consumer because it failed to be processed.", zap.Error(err))
+		return eh.metrics.ConsumeMetrics(ctx, md)
+	}
+	return err
+}
+
+func (eh *errorHandler) handleLogs(ctx context.Context, ld plog.Logs, err error) error {
+	switch eh.mode {
+	case ErrorModeDrop:
+		numRecords := ld.LogRecordCount()
+		eh.logger.Warn("Dropping data because it failed to be processed.", zap.Error(err), zap.Int("dropped_log_records", numRecords))
+		eh.obsrep.LogsDropped(ctx, numRecords)
+		return nil
+	case ErrorModeRoute:
+		eh.logger.Debug("Routing data to the error consumer because it failed to be processed.", zap.Error(err))
+		return eh.logs.ConsumeLogs(ctx, ld)
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+func TestErrorModeUnmarshalText(t *testing.T) {
+	tests := []struct {
+		str     string
+		mode    ErrorMode
+		wantErr error
+	}{
+		{str: "propagate", mode: ErrorModePropagate},
+		{str: "DROP", mode: ErrorModeDrop},
+		{str: "Route", wantErr: errRouteNotConfigurable},
+		{str: "ignore", wantErr: errors.New(`unknown error mode "ignore"`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			var mode ErrorMode
+			err := mode.UnmarshalText([]byte(tt.str))
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				assert.Empty(t, mode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.mode, mode)
+		})
+	}
+
+	var nilMode *ErrorMode
+	assert.Error(t, nilMode.UnmarshalText([]byte("drop")))
+}
+
+func TestErrorMode_InvalidSettings(t *testing.T) {
+	_, err := NewTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, consumertest.NewNop(), newTestTProcessor(nil),
+		WithErrorMode(ErrorModeRoute))
+	assert.ErrorIs(t, err, errNilErrorConsumer)
+
+	// The error consumer of another signal does not satisfy the route mode.
+	_, err = NewMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testMetricsCfg, consumertest.NewNop(), newTestMProcessor(nil),
+		WithErrorMode(ErrorModeRoute), WithErrorTraces(consumertest.NewNop()))
+	assert.ErrorIs(t, err, errNilErrorConsumer)
+
+	_, err = NewLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testLogsCfg, consumertest.NewNop(), newTestLProcessor(nil),
+		WithErrorMode("ignore"))
+	assert.Error(t, err)
+}
+
+func TestErrorMode_Propagate(t *testing.T) {
+	want := errors.New("my_error")
+	tp, err := NewTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, consumertest.NewNop(), newTestTProcessor(want),
+		WithErrorMode(ErrorModePropagate))
+	require.NoError(t, err)
+	assert.Equal(t, want, tp.ConsumeTraces(context.Background(), ptrace.NewTraces()))
+}
+
+func TestErrorMode_Drop(t *testing.T) {
+	tt, err := obsreporttest.SetupTelemetry(processorID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	set := processortest.NewNopCreateSettings()
+	set.ID = processorID
+	set.TelemetrySettings = tt.TelemetrySettings
+	want := errors.New("my_error")
+
+	tracesSink := new(consumertest.TracesSink)
+	tp, err := NewTracesProcessor(context.Background(), set, &testTracesCfg, tracesSink, newTestTProcessor(want), WithErrorMode(ErrorModeDrop))
+	require.NoError(t, err)
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), testdata.GenerateTraces(3)))
+	assert.Equal(t, 0, tracesSink.SpanCount())
+	require.NoError(t, tt.CheckProcessorTraces(0, 0, 3))
+
+	metricsSink := new(consumertest.MetricsSink)
+	mp, err := NewMetricsProcessor(context.Background(), set, &testMetricsCfg, metricsSink, newTestMProcessor(want), WithErrorMode(ErrorModeDrop))
+	require.NoError(t, err)
+	assert.NoError(t, mp.ConsumeMetrics(context.Background(), testdata.GenerateMetrics(2)))
+	assert.Equal(t, 0, metricsSink.DataPointCount())
+	require.NoError(t, tt.CheckProcessorMetrics(0, 0, 4))
+
+	logsSink := new(consumertest.LogsSink)
+	lp, err := NewLogsProcessor(context.Background(), set, &testLogsCfg, logsSink, newTestLProcessor(want), WithErrorMode(ErrorModeDrop))
+	require.NoError(t, err)
+	assert.NoError(t, lp.ConsumeLogs(context.Background(), testdata.GenerateLogs(5)))
+	assert.Equal(t, 0, logsSink.LogRecordCount())
+	require.NoError(t, tt.CheckProcessorLogs(0, 0, 5))
+}
+
+func TestErrorMode_Route(t *testing.T) {
+	want := errors.New("my_error")
+
+	tracesSink := new(consumertest.TracesSink)
+	errorTraces := new(consumertest.TracesSink)
+	tp, err := NewTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, tracesSink,
+		func(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) { return ptrace.NewTraces(), want },
+		WithErrorMode(ErrorModeRoute), WithErrorTraces(errorTraces))
+	require.NoError(t, err)
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), testdata.GenerateTraces(3)))
+	assert.Equal(t, 0, tracesSink.SpanCount())
+	assert.Equal(t, 3, errorTraces.SpanCount())
+
+	metricsSink := new(consumertest.MetricsSink)
+	errorMetrics := new(consumertest.MetricsSink)
+	mp, err := NewMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testMetricsCfg, metricsSink, newTestMProcessor(want),
+		WithErrorMode(ErrorModeRoute), WithErrorMetrics(errorMetrics))
+	require.NoError(t, err)
+	assert.NoError(t, mp.ConsumeMetrics(context.Background(), testdata.GenerateMetrics(2)))
+	assert.Equal(t, 0, metricsSink.DataPointCount())
+	assert.Equal(t, 4, errorMetrics.DataPointCount())
+
+	// The error of the error consumer is returned.
+	routeErr := errors.New("route_error")
+	lp, err := NewLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testLogsCfg, consumertest.NewNop(), newTestLProcessor(want),
+		WithErrorMode(ErrorModeRoute), WithErrorLogs(consumertest.NewErr(routeErr)))
+	require.NoError(t, err)
+	assert.Equal(t, routeErr, lp.ConsumeLogs(context.Background(), plog.NewLogs()))
+
+	// Data successfully processed is sent to the next component.
+	mp, err = NewMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testMetricsCfg, metricsSink, newTestMProcessor(nil),
+		WithErrorMode(ErrorModeRoute), WithErrorMetrics(errorMetrics))
+	require.NoError(t, err)
+	assert.NoError(t, mp.ConsumeMetrics(context.Background(), pmetric.NewMetrics()))
+	assert.Len(t, metricsSink.AllMetrics(), 1)
+}
@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper // import "go.opentelemetry.io/collector/processor/processorhelper"
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// MatchType defines how the attribute values of MatchProperties are compared.
+// It can be used in the processor configuration, as it unmarshals from text.
+type MatchType string
+
+const (
+	// MatchTypeStrict matches the attribute values that are equal to the configured value. This is the default.
+	MatchTypeStrict MatchType = "strict"
+
+	// MatchTypeRegexp matches the attribute values that contain a match of the configured regular expression.
+	MatchTypeRegexp MatchType = "regexp"
+)
+
+// UnmarshalText unmarshalls text to a MatchType.
+func (t *MatchType) UnmarshalText(text []byte) error {
+	if t == nil {
+		return errors.New("cannot unmarshal to a nil *MatchType")
+	}
+
+	matchType := MatchType(strings.ToLower(string(text)))
+	switch matchType {
+	case MatchTypeStrict, MatchTypeRegexp:
+		*t = matchType
+		return nil
+	}
+	return fmt.Errorf("unknown match type %q", matchType)
+}
+
+// MatchAttribute is an attribute condition of MatchProperties.
+type MatchAttribute struct {
+	// Key is the key of the attribute.
+	Key string `mapstructure:"key"`
+
+	// Value is compared with the string representation of the attribute value. When empty, the
+	// attribute matches if it is present, whatever its value.
+	Value string `mapstructure:"value"`
+}
+
+// MatchProperties are the conditions that the data must all meet to match.
+type MatchProperties struct {
+	// MatchType is how the attribute values are compared, MatchTypeStrict by default.
+	MatchType MatchType `mapstructure:"match_type"`
+
+	// ResourceAttributes are the conditions on the resource attributes.
+	ResourceAttributes []MatchAttribute `mapstructure:"resource_attributes"`
+
+	// ScopeAttributes are the conditions on the instrumentation scope attributes.
+	ScopeAttributes []MatchAttribute `mapstructure:"scope_attributes"`
+
+	// Attributes are the conditions on the attributes of the spans, metric data points or log records.
+	Attributes []MatchAttribute `mapstructure:"attributes"`
+}
+
+// FilterConfig selects the data a processor applies to. It can be embedded in the processor configuration.
+// The data matches if it meets the Include conditions, when set, and does not meet the Exclude conditions,
+// when set. All the data matches if neither is set.
+type FilterConfig struct {
+	Include *MatchProperties `mapstructure:"include"`
+	Exclude *MatchProperties `mapstructure:"exclude"`
+}
+
+// Validate checks if the filter configuration is valid.
+func (cfg *FilterConfig) Validate() error {
+	_, err := NewMatcher(*cfg)
+	return err
+}
+
+// Matcher is a compiled FilterConfig. It is safe for concurrent use, so it should be created once when
+// the processor is created rather than for each data.
+type Matcher struct {
+	include *propertiesMatcher
+	exclude *propertiesMatcher
+}
+
+// NewMatcher compiles the filter configuration into a Matcher.
+func NewMatcher(cfg FilterConfig) (*Matcher, error) {
+	include, err := newPropertiesMatcher(cfg.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include: %w", err)
+	}
+	exclude, err := newPropertiesMatcher(cfg.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude: %w", err)
+	}
+	return &Matcher{include: include, exclude: exclude}, nil
+}
+
+// Match returns whether the data with the given resource, instrumentation scope and attributes matches.
+func (m *Matcher) Match(resource pcommon.Resource, scope pcommon.InstrumentationScope, attrs pcommon.Map) bool {
+	if m.include != nil && !m.include.match(resource, scope, attrs) {
+		return false
+	}
+	return m.exclude == nil || !m.exclude.match(resource, scope, attrs)
+}
+
+// MatchResource returns whether some data with the given resource may match, whatever its instrumentation
+// scope and attributes. It allows to skip all the data of a resource at once.
+func (m *Matcher) MatchResource(resource pcommon.Resource) bool {
+	if m.include != nil && !m.include.resource.match(resource.Attributes()) {
+		return false
+	}
+	return m.exclude == nil || !m.exclude.onlyResource() || !m.exclude.resource.match(resource.Attributes())
+}
+
+type propertiesMatcher struct {
+	resource   attributesMatcher
+	scope      attributesMatcher
+	attributes attributesMatcher
+}
+
+func newPropertiesMatcher(mp *MatchProperties) (*propertiesMatcher, error) {
+	if mp == nil {
+		return nil, nil
+	}
+	if len(mp.ResourceAttributes) == 0 && len(mp.ScopeAttributes) == 0 && len(mp.Attributes) == 0 {
+		return nil, errors.New("at least one of resource_attributes, scope_attributes or attributes must be set")
+	}
+
+	matchType := mp.MatchType
+	if matchType == "" {
+		matchType = MatchTypeStrict
+	}
+	if matchType != MatchTypeStrict && matchType != MatchTypeRegexp {
+		return nil, fmt.Errorf("unknown match type %q", matchType)
+	}
+
+	pm := &propertiesMatcher{}
+	var err error
+	if pm.resource, err = newAttributesMatcher(matchType, mp.ResourceAttributes); err != nil {
+		return nil, fmt.Errorf("resource_attributes: %w", err)
+	}
+	if pm.scope, err = newAttributesMatcher(matchType, mp.ScopeAttributes); err != nil {
+		return nil, fmt.Errorf("scope_attributes: %w", err)
+	}
+	if pm.attributes, err = newAttributesMatcher(matchType, mp.Attributes); err != nil {
+		return nil, fmt.Errorf("attributes: %w", err)
+	}
+	return pm, nil
+}
+
+func (pm *propertiesMatcher) match(resource pcommon.Resource, scope pcommon.InstrumentationScope, attrs pcommon.Map) bool {
+	return pm.resource.match(resource.Attributes()) && pm.scope.match(scope.Attributes()) && pm.attributes.match(attrs)
+}
+
+// onlyResource returns whether the matcher only has conditions on the resource attributes.
+func (pm *propertiesMatcher) onlyResource() bool {
+	return len(pm.scope) == 0 && len(pm.attributes) == 0
+}
+
+// attributesMatcher matches the attributes meeting all its conditions.
+type attributesMatcher []attributeMatcher
+
+type attributeMatcher struct {
+	key string
+	// value is nil when the attribute only needs to be present.
+	value func(string) bool
+}
+
+func newAttributesMatcher(matchType MatchType, attrs []MatchAttribute) (attributesMatcher, error) {
+	am := make(attributesMatcher, 0, len(attrs))
+	for _, attr := range attrs {
+		if attr.Key == "" {
+			return nil, errors.New("the attribute key must not be empty")
+		}
+		m := attributeMatcher{key: attr.Key}
+		switch {
+		case attr.Value == "":
+		case matchType == MatchTypeRegexp:
+			re, err := regexp.Compile(attr.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regexp for attribute %q: %w", attr.Key, err)
+			}
+			m.value = re.MatchString
+		default:
+			value := attr.Value
+			m.value = func(v string) bool { return v == value }
+		}
+		am = append(am, m)
+	}
+	return am, nil
+}
+
+func (am attributesMatcher) match(attrs pcommon.Map) bool {
+	for _, m := range am {
+		v, ok := attrs.Get(m.key)
+		if !ok {
+			return false
+		}
+		if m.value != nil && !m.value(v.AsString()) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestMatchTypeUnmarshalText(t *testing.T) {
+	var matchType MatchType
+	require.NoError(t, matchType.UnmarshalText([]byte("Regexp")))
+	assert.Equal(t, MatchTypeRegexp, matchType)
+	require.NoError(t, matchType.UnmarshalText([]byte("strict")))
+	assert.Equal(t, MatchTypeStrict, matchType)
+	assert.EqualError(t, matchType.UnmarshalText([]byte("glob")), `unknown match type "glob"`)
+
+	var nilMatchType *MatchType
+	assert.Error(t, nilMatchType.UnmarshalText([]byte("strict")))
+}
+
+func TestFilterConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     FilterConfig
+		wantErr string
+	}{
+		{
+			name: "empty",
+			cfg:  FilterConfig{},
+		},
+		{
+			name: "valid",
+			cfg: FilterConfig{
+				Include: &MatchProperties{MatchType: MatchTypeRegexp, Attributes: []MatchAttribute{{Key: "http.url", Value: "^https://"}}},
+				Exclude: &MatchProperties{ResourceAttributes: []MatchAttribute{{Key: "service.name", Value: "test"}}},
+			},
+		},
+		{
+			name:    "no_conditions",
+			cfg:     FilterConfig{Include: &MatchProperties{}},
+			wantErr: "invalid include: at least one of resource_attributes, scope_attributes or attributes must be set",
+		},
+		{
+			name:    "unknown_match_type",
+			cfg:     FilterConfig{Exclude: &MatchProperties{MatchType: "glob", Attributes: []MatchAttribute{{Key: "key"}}}},
+			wantErr: `invalid exclude: unknown match type "glob"`,
+		},
+		{
+			name:    "empty_key",
+			cfg:     FilterConfig{Include: &MatchProperties{ScopeAttributes: []MatchAttribute{{Value: "value"}}}},
+			wantErr: "invalid include: scope_attributes: the attribute key must not be empty",
+		},
+		{
+			name:    "invalid_regexp",
+			cfg:     FilterConfig{Include: &MatchProperties{MatchType: MatchTypeRegexp, Attributes: []MatchAttribute{{Key: "key", Value: "("}}}},
+			wantErr: "invalid include: attributes: invalid regexp for attribute \"key\": error parsing regexp: missing closing ): `(`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("service.name", "checkout")
+	scope := pcommon.NewInstrumentationScope()
+	scope.Attributes().PutStr("library", "http")
+	attrs := pcommon.NewMap()
+	attrs.PutStr("http.url", "https://example.com/cart")
+	attrs.PutInt("http.status_code", 500)
+
+	tests := []struct {
+		name          string
+		cfg           FilterConfig
+		match         bool
+		matchResource bool
+	}{
+		{
+			name:          "empty",
+			match:         true,
+			matchResource: true,
+		},
+		{
+			name: "include_strict",
+			cfg: FilterConfig{Include: &MatchProperties{
+				ResourceAttributes: []MatchAttribute{{Key: "service.name", Value: "checkout"}},
+				Attributes:         []MatchAttribute{{Key: "http.status_code", Value: "500"}},
+			}},
+			match:         true,
+			matchResource: true,
+		},
+		{
+			name: "include_strict_mismatch",
+			cfg: FilterConfig{Include: &MatchProperties{
+				Attributes: []MatchAttribute{{Key: "http.url", Value: "https://example.com"}},
+			}},
+			match:         false,
+			matchResource: true,
+		},
+		{
+			name: "include_resource_mismatch",
+			cfg: FilterConfig{Include: &MatchProperties{
+				ResourceAttributes: []MatchAttribute{{Key: "service.name", Value: "cart"}},
+			}},
+			match:         false,
+			matchResource: false,
+		},
+		{
+			name: "include_regexp",
+			cfg: FilterConfig{Include: &MatchProperties{
+				MatchType:       MatchTypeRegexp,
+				ScopeAttributes: []MatchAttribute{{Key: "library", Value: "^ht"}},
+				Attributes:      []MatchAttribute{{Key: "http.url", Value: "/cart$"}},
+			}},
+			match:         true,
+			matchResource: true,
+		},
+		{
+			name: "include_present",
+			cfg: FilterConfig{Include: &MatchProperties{
+				Attributes: []MatchAttribute{{Key: "http.url"}},
+			}},
+			match:         true,
+			matchResource: true,
+		},
+		{
+			name: "include_missing",
+			cfg: FilterConfig{Include: &MatchProperties{
+				Attributes: []MatchAttribute{{Key: "db.system"}},
+			}},
+			match:         false,
+			matchResource: true,
+		},
+		{
+			name: "exclude",
+			cfg: FilterConfig{Exclude: &MatchProperties{
+				Attributes: []MatchAttribute{{Key: "http.status_code", Value: "500"}},
+			}},
+			match:         false,
+			matchResource: true,
+		},
+		{
+			name: "exclude_resource",
+			cfg: FilterConfig{Exclude: &MatchProperties{
+				ResourceAttributes: []MatchAttribute{{Key: "service.name", Value: "checkout"}},
+			}},
+			match:         false,
+			matchResource: false,
+		},
+		{
+			name: "include_and_exclude",
+			cfg: FilterConfig{
+				Include: &MatchProperties{ResourceAttributes: []MatchAttribute{{Key: "service.name"}}},
+				Exclude: &MatchProperties{Attributes: []MatchAttribute{{Key: "http.status_code", Value: "200"}}},
+			},
+			match:         true,
+			matchResource: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMatcher(tt.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.match, m.Match(resource, scope, attrs))
+			assert.Equal(t, tt.matchResource, m.MatchResource(resource))
+		})
+	}
+}
@@ -6,11 +6,13 @@ package processorhelper // import "go.opentelemetry.io/collector/processor/proce
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/processor"
 )
@@ -45,27 +47,190 @@ func NewLogsProcessor(
 
 	eventOptions := spanAttributes(set.ID)
 	bs := fromOptions(options)
+	obsrep, err := NewObsReport(ObsReportSettings{ProcessorID: set.ID, ProcessorCreateSettings: set})
+	if err != nil {
+		return nil, err
+	}
+	if detectMutations(bs) {
+		logsFunc = detectLogsMutations(set.Logger, logsFunc)
+	}
+	// Each call of the processing function, on each worker, is reported as the processing of a batch.
+	processFunc := logsFunc
+	logsFunc = func(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+		start := time.Now()
+		defer func() { obsrep.ProcessingDuration(ctx, component.DataTypeLogs, time.Since(start)) }()
+		return processFunc(ctx, ld)
+	}
+	wp := newWorkerPool(bs, obsrep, component.DataTypeLogs)
+	if wp != nil {
+		logsFunc = parallelLogsFunc(wp, logsFunc)
+	}
+	eh, err := newErrorHandler(set, bs, obsrep, bs.errorLogs != nil)
+	if err != nil {
+		return nil, err
+	}
 	logsConsumer, err := consumer.NewLogs(func(ctx context.Context, ld plog.Logs) error {
 		span := trace.SpanFromContext(ctx)
 		span.AddEvent("Start processing.", eventOptions)
-		var err error
-		ld, err = logsFunc(ctx, ld)
+		processed, err := logsFunc(ctx, ld)
 		span.AddEvent("End processing.", eventOptions)
 		if err != nil {
 			if errors.Is(err, ErrSkipProcessingData) {
 				return nil
 			}
-			return err
+			return eh.handleLogs(ctx, ld, err)
 		}
-		return nextConsumer.ConsumeLogs(ctx, ld)
+		return nextConsumer.ConsumeLogs(ctx, processed)
 	}, bs.consumerOptions...)
 	if err != nil {
 		return nil, err
 	}
 
+	start, shutdown := wp.lifecycle(bs.StartFunc, bs.ShutdownFunc)
+	start, shutdown = newProcessorStorage(set.ID, bs, component.DataTypeLogs).lifecycle(start, shutdown)
+	shutdown = shutdownWithTimeout(set.Logger, bs.shutdownTimeout, shutdown)
 	return &logProcessor{
-		StartFunc:    bs.StartFunc,
-		ShutdownFunc: bs.ShutdownFunc,
+		StartFunc:    start,
+		ShutdownFunc: shutdown,
 		Logs:         logsConsumer,
 	}, nil
 }
+
+// parallelLogsFunc splits the logs by resource between the workers of the pool, processes them in parallel
+// and merges the processed logs. If the processing fails the logs are moved back to the input.
+func parallelLogsFunc(wp *workerPool, logsFunc ProcessLogsFunc) ProcessLogsFunc {
+	return func(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+		rls := ld.ResourceLogs()
+		workers, split := wp.assign(rls.Len(), func(i int) pcommon.Resource { return rls.At(i).Resource() })
+		jobs := make([]func(), wp.size)
+		if !split {
+			var processed plog.Logs
+			var err error
+			w := 0
+			if len(workers) > 0 {
+				w = workers[0]
+			}
+			jobs[w] = func() { processed, err = logsFunc(ctx, ld) }
+			wp.run(ctx, jobs)
+			return processed, err
+		}
+
+		inputs := make([]plog.Logs, wp.size)
+		outputs := make([]plog.Logs, wp.size)
+		errs := make([]error, wp.size)
+		for i, w := range workers {
+			if jobs[w] == nil {
+				w := w
+				inputs[w] = plog.NewLogs()
+				jobs[w] = func() { outputs[w], errs[w] = logsFunc(ctx, inputs[w]) }
+			}
+			rls.At(i).MoveTo(inputs[w].ResourceLogs().AppendEmpty())
+		}
+		rls.RemoveIf(func(plog.ResourceLogs) bool { return true })
+		wp.run(ctx, jobs)
+
+		skipped, err := result(jobs, errs)
+		if err != nil {
+			for w, job := range jobs {
+				if job != nil {
+					inputs[w].ResourceLogs().MoveAndAppendTo(rls)
+				}
+			}
+			return ld, err
+		}
+		if skipped {
+			return ld, ErrSkipProcessingData
+		}
+		processed := plog.NewLogs()
+		for w, job := range jobs {
+			if job != nil && errs[w] == nil {
+				outputs[w].ResourceLogs().MoveAndAppendTo(processed.ResourceLogs())
+			}
+		}
+		return processed, nil
+	}
+}
+
+// EmitLogsFunc sends logs to the next component of an asynchronous processor. It can be called
+// from any goroutine until the processor starts shutting down, then only by the flush function.
+type EmitLogsFunc func(context.Context, plog.Logs) error
+
+// ProcessLogsAsyncFunc is a helper function that processes the incoming data asynchronously. It can keep the
+// data and send it to the next component later with the EmitLogsFunc. If error is returned the data is refused.
+type ProcessLogsAsyncFunc func(context.Context, plog.Logs) error
+
+type asyncLogsProcessor struct {
+	*asyncProcessor
+	consumer.Logs
+}
+
+// NewAsyncLogsProcessor creates a processor.Logs that buffers data and emits it on its own schedule.
+// newLogsFunc is called once with the function that sends data to the next component and returns the function
+// that processes the incoming data. Use WithFlush to emit the buffered data when the processor is shut down.
+// The incoming data is recorded as accepted, refused or, if ErrSkipProcessingData is returned, dropped. Emitted
+// data that the next component fails to consume is recorded as dropped.
+func NewAsyncLogsProcessor(
+	_ context.Context,
+	set processor.CreateSettings,
+	_ component.Config,
+	nextConsumer consumer.Logs,
+	newLogsFunc func(EmitLogsFunc) ProcessLogsAsyncFunc,
+	options ...Option,
+) (processor.Logs, error) {
+	if newLogsFunc == nil {
+		return nil, errors.New("nil newLogsFunc")
+	}
+
+	if nextConsumer == nil {
+		return nil, component.ErrNilNextConsumer
+	}
+
+	bs := fromOptions(options)
+	ap, err := newAsyncProcessor(ObsReportSettings{ProcessorID: set.ID, ProcessorCreateSettings: set}, bs, component.DataTypeLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	logsFunc := newLogsFunc(func(ctx context.Context, ld plog.Logs) error {
+		numRecords := ld.LogRecordCount()
+		err := ap.emit(ctx, func() error {
+			return nextConsumer.ConsumeLogs(ctx, ld)
+		})
+		if err != nil {
+			ap.obsrep.LogsDropped(ctx, numRecords)
+		}
+		return err
+	})
+	if logsFunc == nil {
+		return nil, errors.New("nil logsFunc")
+	}
+
+	eventOptions := spanAttributes(set.ID)
+	logsConsumer, err := consumer.NewLogs(func(ctx context.Context, ld plog.Logs) error {
+		span := trace.SpanFromContext(ctx)
+		span.AddEvent("Start processing.", eventOptions)
+		numRecords := ld.LogRecordCount()
+		start := time.Now()
+		err := logsFunc(ctx, ld)
+		ap.obsrep.ProcessingDuration(ctx, component.DataTypeLogs, time.Since(start))
+		span.AddEvent("End processing.", eventOptions)
+		switch {
+		case err == nil:
+			ap.obsrep.LogsAccepted(ctx, numRecords)
+		case errors.Is(err, ErrSkipProcessingData):
+			ap.obsrep.LogsDropped(ctx, numRecords)
+			return nil
+		default:
+			ap.obsrep.LogsRefused(ctx, numRecords)
+		}
+		return err
+	}, bs.consumerOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &asyncLogsProcessor{
+		asyncProcessor: ap,
+		Logs:           logsConsumer,
+	}, nil
+}
@@ -6,15 +6,18 @@ package processorhelper
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/processor/processortest"
 )
@@ -70,3 +73,73 @@ func newTestLProcessor(retError error) ProcessLogsFunc {
 		return ld, retError
 	}
 }
+
+// testAsyncLProcessor buffers the incoming logs and emits them on flush.
+type testAsyncLProcessor struct {
+	retError error
+	emit     EmitLogsFunc
+	mu       sync.Mutex
+	buffered []plog.Logs
+}
+
+func (p *testAsyncLProcessor) newLogsFunc(emit EmitLogsFunc) ProcessLogsAsyncFunc {
+	p.emit = emit
+	return func(_ context.Context, ld plog.Logs) error {
+		if p.retError != nil {
+			return p.retError
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.buffered = append(p.buffered, ld)
+		return nil
+	}
+}
+
+func (p *testAsyncLProcessor) flush(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var errs error
+	for _, ld := range p.buffered {
+		errs = multierr.Append(errs, p.emit(ctx, ld))
+	}
+	p.buffered = nil
+	return errs
+}
+
+func TestNewAsyncLogsProcessor(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	p := &testAsyncLProcessor{}
+	lp, err := NewAsyncLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testLogsCfg, sink, p.newLogsFunc, WithFlush(p.flush))
+	require.NoError(t, err)
+
+	assert.True(t, lp.Capabilities().MutatesData)
+	assert.NoError(t, lp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, lp.ConsumeLogs(context.Background(), testdata.GenerateLogs(2)))
+	assert.Equal(t, 0, sink.LogRecordCount())
+
+	// Data is emitted on the processor schedule.
+	require.NoError(t, p.flush(context.Background()))
+	assert.Equal(t, 2, sink.LogRecordCount())
+
+	// Buffered data is emitted on shutdown.
+	assert.NoError(t, lp.ConsumeLogs(context.Background(), testdata.GenerateLogs(3)))
+	assert.NoError(t, lp.Shutdown(context.Background()))
+	assert.Equal(t, 5, sink.LogRecordCount())
+
+	// Data emitted after shutdown is dropped.
+	assert.ErrorIs(t, p.emit(context.Background(), testdata.GenerateLogs(1)), errProcessorShutdown)
+	assert.Equal(t, 5, sink.LogRecordCount())
+}
+
+func TestNewAsyncLogsProcessor_NilRequiredFields(t *testing.T) {
+	_, err := NewAsyncLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testLogsCfg, consumertest.NewNop(), nil)
+	assert.Error(t, err)
+
+	_, err = NewAsyncLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testLogsCfg, consumertest.NewNop(),
+		func(EmitLogsFunc) ProcessLogsAsyncFunc { return nil })
+	assert.Error(t, err)
+
+	p := &testAsyncLProcessor{}
+	_, err = NewAsyncLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testLogsCfg, nil, p.newLogsFunc)
+	assert.Equal(t, component.ErrNilNextConsumer, err)
+}
@@ -6,11 +6,13 @@ package processorhelper // import "go.opentelemetry.io/collector/processor/proce
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/processor"
 )
@@ -45,27 +47,190 @@ func NewMetricsProcessor(
 
 	eventOptions := spanAttributes(set.ID)
 	bs := fromOptions(options)
+	obsrep, err := NewObsReport(ObsReportSettings{ProcessorID: set.ID, ProcessorCreateSettings: set})
+	if err != nil {
+		return nil, err
+	}
+	if detectMutations(bs) {
+		metricsFunc = detectMetricsMutations(set.Logger, metricsFunc)
+	}
+	// Each call of the processing function, on each worker, is reported as the processing of a batch.
+	processFunc := metricsFunc
+	metricsFunc = func(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+		start := time.Now()
+		defer func() { obsrep.ProcessingDuration(ctx, component.DataTypeMetrics, time.Since(start)) }()
+		return processFunc(ctx, md)
+	}
+	wp := newWorkerPool(bs, obsrep, component.DataTypeMetrics)
+	if wp != nil {
+		metricsFunc = parallelMetricsFunc(wp, metricsFunc)
+	}
+	eh, err := newErrorHandler(set, bs, obsrep, bs.errorMetrics != nil)
+	if err != nil {
+		return nil, err
+	}
 	metricsConsumer, err := consumer.NewMetrics(func(ctx context.Context, md pmetric.Metrics) error {
 		span := trace.SpanFromContext(ctx)
 		span.AddEvent("Start processing.", eventOptions)
-		var err error
-		md, err = metricsFunc(ctx, md)
+		processed, err := metricsFunc(ctx, md)
 		span.AddEvent("End processing.", eventOptions)
 		if err != nil {
 			if errors.Is(err, ErrSkipProcessingData) {
 				return nil
 			}
-			return err
+			return eh.handleMetrics(ctx, md, err)
 		}
-		return nextConsumer.ConsumeMetrics(ctx, md)
+		return nextConsumer.ConsumeMetrics(ctx, processed)
 	}, bs.consumerOptions...)
 	if err != nil {
 		return nil, err
 	}
 
+	start, shutdown := wp.lifecycle(bs.StartFunc, bs.ShutdownFunc)
+	start, shutdown = newProcessorStorage(set.ID, bs, component.DataTypeMetrics).lifecycle(start, shutdown)
+	shutdown = shutdownWithTimeout(set.Logger, bs.shutdownTimeout, shutdown)
 	return &metricsProcessor{
-		StartFunc:    bs.StartFunc,
-		ShutdownFunc: bs.ShutdownFunc,
+		StartFunc:    start,
+		ShutdownFunc: shutdown,
 		Metrics:      metricsConsumer,
 	}, nil
 }
+
+// parallelMetricsFunc splits the metrics by resource between the workers of the pool, processes them in parallel
+// and merges the processed metrics. If the processing fails the metrics are moved back to the input.
+func parallelMetricsFunc(wp *workerPool, metricsFunc ProcessMetricsFunc) ProcessMetricsFunc {
+	return func(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+		rms := md.ResourceMetrics()
+		workers, split := wp.assign(rms.Len(), func(i int) pcommon.Resource { return rms.At(i).Resource() })
+		jobs := make([]func(), wp.size)
+		if !split {
+			var processed pmetric.Metrics
+			var err error
+			w := 0
+			if len(workers) > 0 {
+				w = workers[0]
+			}
+			jobs[w] = func() { processed, err = metricsFunc(ctx, md) }
+			wp.run(ctx, jobs)
+			return processed, err
+		}
+
+		inputs := make([]pmetric.Metrics, wp.size)
+		outputs := make([]pmetric.Metrics, wp.size)
+		errs := make([]error, wp.size)
+		for i, w := range workers {
+			if jobs[w] == nil {
+				w := w
+				inputs[w] = pmetric.NewMetrics()
+				jobs[w] = func() { outputs[w], errs[w] = metricsFunc(ctx, inputs[w]) }
+			}
+			rms.At(i).MoveTo(inputs[w].ResourceMetrics().AppendEmpty())
+		}
+		rms.RemoveIf(func(pmetric.ResourceMetrics) bool { return true })
+		wp.run(ctx, jobs)
+
+		skipped, err := result(jobs, errs)
+		if err != nil {
+			for w, job := range jobs {
+				if job != nil {
+					inputs[w].ResourceMetrics().MoveAndAppendTo(rms)
+				}
+			}
+			return md, err
+		}
+		if skipped {
+			return md, ErrSkipProcessingData
+		}
+		processed := pmetric.NewMetrics()
+		for w, job := range jobs {
+			if job != nil && errs[w] == nil {
+				outputs[w].ResourceMetrics().MoveAndAppendTo(processed.ResourceMetrics())
+			}
+		}
+		return processed, nil
+	}
+}
+
+// EmitMetricsFunc sends metrics to the next component of an asynchronous processor. It can be called
+// from any goroutine until the processor starts shutting down, then only by the flush function.
+type EmitMetricsFunc func(context.Context, pmetric.Metrics) error
+
+// ProcessMetricsAsyncFunc is a helper function that processes the incoming data asynchronously. It can keep the
+// data and send it to the next component later with the EmitMetricsFunc. If error is returned the data is refused.
+type ProcessMetricsAsyncFunc func(context.Context, pmetric.Metrics) error
+
+type asyncMetricsProcessor struct {
+	*asyncProcessor
+	consumer.Metrics
+}
+
+// NewAsyncMetricsProcessor creates a processor.Metrics that buffers data and emits it on its own schedule.
+// newMetricsFunc is called once with the function that sends data to the next component and returns the function
+// that processes the incoming data. Use WithFlush to emit the buffered data when the processor is shut down.
+// The incoming data is recorded as accepted, refused or, if ErrSkipProcessingData is returned, dropped. Emitted
+// data that the next component fails to consume is recorded as dropped.
+func NewAsyncMetricsProcessor(
+	_ context.Context,
+	set processor.CreateSettings,
+	_ component.Config,
+	nextConsumer consumer.Metrics,
+	newMetricsFunc func(EmitMetricsFunc) ProcessMetricsAsyncFunc,
+	options ...Option,
+) (processor.Metrics, error) {
+	if newMetricsFunc == nil {
+		return nil, errors.New("nil newMetricsFunc")
+	}
+
+	if nextConsumer == nil {
+		return nil, component.ErrNilNextConsumer
+	}
+
+	bs := fromOptions(options)
+	ap, err := newAsyncProcessor(ObsReportSettings{ProcessorID: set.ID, ProcessorCreateSettings: set}, bs, component.DataTypeMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	metricsFunc := newMetricsFunc(func(ctx context.Context, md pmetric.Metrics) error {
+		numPoints := md.DataPointCount()
+		err := ap.emit(ctx, func() error {
+			return nextConsumer.ConsumeMetrics(ctx, md)
+		})
+		if err != nil {
+			ap.obsrep.MetricsDropped(ctx, numPoints)
+		}
+		return err
+	})
+	if metricsFunc == nil {
+		return nil, errors.New("nil metricsFunc")
+	}
+
+	eventOptions := spanAttributes(set.ID)
+	metricsConsumer, err := consumer.NewMetrics(func(ctx context.Context, md pmetric.Metrics) error {
+		span := trace.SpanFromContext(ctx)
+		span.AddEvent("Start processing.", eventOptions)
+		numPoints := md.DataPointCount()
+		start := time.Now()
+		err := metricsFunc(ctx, md)
+		ap.obsrep.ProcessingDuration(ctx, component.DataTypeMetrics, time.Since(start))
+		span.AddEvent("End processing.", eventOptions)
+		switch {
+		case err == nil:
+			ap.obsrep.MetricsAccepted(ctx, numPoints)
+		case errors.Is(err, ErrSkipProcessingData):
+			ap.obsrep.MetricsDropped(ctx, numPoints)
+			return nil
+		default:
+			ap.obsrep.MetricsRefused(ctx, numPoints)
+		}
+		return err
+	}, bs.consumerOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &asyncMetricsProcessor{
+		asyncProcessor: ap,
+		Metrics:        metricsConsumer,
+	}, nil
+}
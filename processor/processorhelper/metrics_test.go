@@ -6,15 +6,18 @@ package processorhelper
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/processor/processortest"
 )
@@ -70,3 +73,73 @@ func newTestMProcessor(retError error) ProcessMetricsFunc {
 		return md, retError
 	}
 }
+
+// testAsyncMProcessor buffers the incoming metrics and emits them on flush.
+type testAsyncMProcessor struct {
+	retError error
+	emit     EmitMetricsFunc
+	mu       sync.Mutex
+	buffered []pmetric.Metrics
+}
+
+func (p *testAsyncMProcessor) newMetricsFunc(emit EmitMetricsFunc) ProcessMetricsAsyncFunc {
+	p.emit = emit
+	return func(_ context.Context, md pmetric.Metrics) error {
+		if p.retError != nil {
+			return p.retError
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.buffered = append(p.buffered, md)
+		return nil
+	}
+}
+
+func (p *testAsyncMProcessor) flush(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var errs error
+	for _, md := range p.buffered {
+		errs = multierr.Append(errs, p.emit(ctx, md))
+	}
+	p.buffered = nil
+	return errs
+}
+
+func TestNewAsyncMetricsProcessor(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	p := &testAsyncMProcessor{}
+	mp, err := NewAsyncMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testMetricsCfg, sink, p.newMetricsFunc, WithFlush(p.flush))
+	require.NoError(t, err)
+
+	assert.True(t, mp.Capabilities().MutatesData)
+	assert.NoError(t, mp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, mp.ConsumeMetrics(context.Background(), testdata.GenerateMetrics(2)))
+	assert.Equal(t, 0, sink.DataPointCount())
+
+	// Data is emitted on the processor schedule.
+	require.NoError(t, p.flush(context.Background()))
+	assert.Equal(t, 4, sink.DataPointCount())
+
+	// Buffered data is emitted on shutdown.
+	assert.NoError(t, mp.ConsumeMetrics(context.Background(), testdata.GenerateMetrics(3)))
+	assert.NoError(t, mp.Shutdown(context.Background()))
+	assert.Equal(t, 10, sink.DataPointCount())
+
+	// Data emitted after shutdown is dropped.
+	assert.ErrorIs(t, p.emit(context.Background(), testdata.GenerateMetrics(1)), errProcessorShutdown)
+	assert.Equal(t, 10, sink.DataPointCount())
+}
+
+func TestNewAsyncMetricsProcessor_NilRequiredFields(t *testing.T) {
+	_, err := NewAsyncMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testMetricsCfg, consumertest.NewNop(), nil)
+	assert.Error(t, err)
+
+	_, err = NewAsyncMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testMetricsCfg, consumertest.NewNop(),
+		func(EmitMetricsFunc) ProcessMetricsAsyncFunc { return nil })
+	assert.Error(t, err)
+
+	p := &testAsyncMProcessor{}
+	_, err = NewAsyncMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testMetricsCfg, nil, p.newMetricsFunc)
+	assert.Equal(t, component.ErrNilNextConsumer, err)
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper // import "go.opentelemetry.io/collector/processor/processorhelper"
+
+import (
+	"context"
+	"hash/fnv"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// detectMutationsFeatureGate is the feature gate that controls whether the synchronous processors declaring
+// that they do not mutate the data are checked to not mutate it. The data is hashed before and after each
+// processing, which is expensive, so it is meant to debug the data races caused by illegal mutations.
+var detectMutationsFeatureGate = featuregate.GlobalRegistry().MustRegister(
+	"processorhelper.detectMutations",
+	featuregate.StageAlpha,
+	featuregate.WithRegisterDescription("controls whether the processors declaring that they do not mutate the data "+
+		"are checked to not mutate it, reporting the violations in the logs"))
+
+const mutationMessage = "Processor mutated the data although its capabilities declare that it does not mutate data."
+
+// detectMutations returns whether the processing functions must be checked to not mutate the data.
+func detectMutations(bs *baseSettings) bool {
+	return !bs.capabilities.MutatesData && detectMutationsFeatureGate.IsEnabled()
+}
+
+func hashProto(data []byte, err error) uint64 {
+	if err != nil {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	return h.Sum64()
+}
+
+// detectTracesMutations wraps tracesFunc to log when it mutates the incoming traces.
+func detectTracesMutations(logger *zap.Logger, tracesFunc ProcessTracesFunc) ProcessTracesFunc {
+	marshaler := &ptrace.ProtoMarshaler{}
+	return func(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+		before := hashProto(marshaler.MarshalTraces(td))
+		processed, err := tracesFunc(ctx, td)
+		if hashProto(marshaler.MarshalTraces(td)) != before {
+			logger.Error(mutationMessage, zap.Int("spans", td.SpanCount()))
+		}
+		return processed, err
+	}
+}
+
+// detectMetricsMutations wraps metricsFunc to log when it mutates the incoming metrics.
+func detectMetricsMutations(logger *zap.Logger, metricsFunc ProcessMetricsFunc) ProcessMetricsFunc {
+	marshaler := &pmetric.ProtoMarshaler{}
+	return func(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+		before := hashProto(marshaler.MarshalMetrics(md))
+		processed, err := metricsFunc(ctx, md)
+		if hashProto(marshaler.MarshalMetrics(md)) != before {
+			logger.Error(mutationMessage, zap.Int("data_points", md.DataPointCount()))
+		}
+		return processed, err
+	}
+}
+
+// detectLogsMutations wraps logsFunc to log when it mutates the incoming logs.
+func detectLogsMutations(logger *zap.Logger, logsFunc ProcessLogsFunc) ProcessLogsFunc {
+	marshaler := &plog.ProtoMarshaler{}
+	return func(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+		before := hashProto(marshaler.MarshalLogs(ld))
+		processed, err := logsFunc(ctx, ld)
+		if hashProto(marshaler.MarshalLogs(ld)) != before {
+			logger.Error(mutationMessage, zap.Int("log_records", ld.LogRecordCount()))
+		}
+		return processed, err
+	}
+}
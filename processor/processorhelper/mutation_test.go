@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+func setDetectMutations(t *testing.T, enabled bool) {
+	original := detectMutationsFeatureGate.IsEnabled()
+	require.NoError(t, featuregate.GlobalRegistry().Set(detectMutationsFeatureGate.ID(), enabled))
+	t.Cleanup(func() {
+		require.NoError(t, featuregate.GlobalRegistry().Set(detectMutationsFeatureGate.ID(), original))
+	})
+}
+
+func mutateTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	td.ResourceSpans().At(0).Resource().Attributes().PutStr("mutated", "true")
+	return td, nil
+}
+
+func TestNewTracesProcessor_DetectMutations(t *testing.T) {
+	tests := []struct {
+		name         string
+		enabled      bool
+		mutatesData  bool
+		tracesFunc   ProcessTracesFunc
+		wantMutation bool
+	}{
+		{
+			name:         "mutation",
+			enabled:      true,
+			tracesFunc:   mutateTraces,
+			wantMutation: true,
+		},
+		{
+			name:       "no_mutation",
+			enabled:    true,
+			tracesFunc: newTestTProcessor(nil),
+		},
+		{
+			name:        "mutates_data",
+			enabled:     true,
+			mutatesData: true,
+			tracesFunc:  mutateTraces,
+		},
+		{
+			name:       "disabled",
+			tracesFunc: mutateTraces,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setDetectMutations(t, tt.enabled)
+			core, logs := observer.New(zap.ErrorLevel)
+			set := processortest.NewNopCreateSettings()
+			set.Logger = zap.New(core)
+
+			tp, err := NewTracesProcessor(context.Background(), set, &testTracesCfg, consumertest.NewNop(), tt.tracesFunc,
+				WithCapabilities(consumer.Capabilities{MutatesData: tt.mutatesData}))
+			require.NoError(t, err)
+			assert.NoError(t, tp.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+			if !tt.wantMutation {
+				assert.Equal(t, 0, logs.Len())
+				return
+			}
+			require.Equal(t, 1, logs.Len())
+			assert.Equal(t, mutationMessage, logs.All()[0].Message)
+		})
+	}
+}
+
+func TestNewMetricsProcessor_DetectMutations(t *testing.T) {
+	setDetectMutations(t, true)
+	core, logs := observer.New(zap.ErrorLevel)
+	set := processortest.NewNopCreateSettings()
+	set.Logger = zap.New(core)
+
+	mp, err := NewMetricsProcessor(context.Background(), set, &testMetricsCfg, consumertest.NewNop(),
+		func(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+			md.ResourceMetrics().RemoveIf(func(pmetric.ResourceMetrics) bool { return true })
+			return md, nil
+		},
+		WithCapabilities(consumer.Capabilities{MutatesData: false}))
+	require.NoError(t, err)
+	assert.NoError(t, mp.ConsumeMetrics(context.Background(), testdata.GenerateMetrics(2)))
+	assert.Equal(t, 1, logs.Len())
+}
+
+func TestNewLogsProcessor_DetectMutations(t *testing.T) {
+	setDetectMutations(t, true)
+	core, logs := observer.New(zap.ErrorLevel)
+	set := processortest.NewNopCreateSettings()
+	set.Logger = zap.New(core)
+
+	lp, err := NewLogsProcessor(context.Background(), set, &testLogsCfg, consumertest.NewNop(),
+		func(_ context.Context, ld plog.Logs) (plog.Logs, error) {
+			// The processor returns new data without mutating the incoming one.
+			out := plog.NewLogs()
+			ld.CopyTo(out)
+			out.ResourceLogs().AppendEmpty()
+			return out, nil
+		},
+		WithCapabilities(consumer.Capabilities{MutatesData: false}))
+	require.NoError(t, err)
+	assert.NoError(t, lp.ConsumeLogs(context.Background(), testdata.GenerateLogs(2)))
+	assert.Equal(t, 0, logs.Len())
+}
@@ -6,6 +6,7 @@ package processorhelper // import "go.opentelemetry.io/collector/processor/proce
 import (
 	"context"
 	"strings"
+	"time"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
@@ -58,6 +59,8 @@ type ObsReport struct {
 	acceptedLogRecordsCounter   metric.Int64Counter
 	refusedLogRecordsCounter    metric.Int64Counter
 	droppedLogRecordsCounter    metric.Int64Counter
+	processingDurationHistogram metric.Float64Histogram
+	awaitDurationHistogram      metric.Float64Histogram
 }
 
 // ObsReportSettings are settings for creating an ObsReport.
@@ -159,6 +162,20 @@ func (or *ObsReport) createOtelMetrics(cfg ObsReportSettings) error {
 	)
 	errors = multierr.Append(errors, err)
 
+	or.processingDurationHistogram, err = meter.Float64Histogram(
+		obsmetrics.ProcessorPrefix+obsmetrics.ProcessingDurationKey,
+		metric.WithDescription("Time spent processing a batch of data."),
+		metric.WithUnit("ms"),
+	)
+	errors = multierr.Append(errors, err)
+
+	or.awaitDurationHistogram, err = meter.Float64Histogram(
+		obsmetrics.ProcessorPrefix+obsmetrics.AwaitDurationKey,
+		metric.WithDescription("Time a batch of data waited to be processed."),
+		metric.WithUnit("ms"),
+	)
+	errors = multierr.Append(errors, err)
+
 	return errors
 }
 
@@ -282,3 +299,31 @@ func (or *ObsReport) LogsDropped(ctx context.Context, numRecords int) {
 		or.recordData(ctx, component.DataTypeLogs, int64(0), int64(0), int64(numRecords))
 	}
 }
+
+// ProcessingDuration reports the time spent processing a batch of data of the given signal.
+func (or *ObsReport) ProcessingDuration(ctx context.Context, dataType component.DataType, duration time.Duration) {
+	if or.level != configtelemetry.LevelNone {
+		or.recordDuration(ctx, dataType, or.processingDurationHistogram, obsmetrics.ProcessorProcessingDuration, duration)
+	}
+}
+
+// AwaitDuration reports the time a batch of data of the given signal waited before being processed,
+// for instance for a free worker when the processor processes the data concurrently.
+func (or *ObsReport) AwaitDuration(ctx context.Context, dataType component.DataType, duration time.Duration) {
+	if or.level != configtelemetry.LevelNone {
+		or.recordDuration(ctx, dataType, or.awaitDurationHistogram, obsmetrics.ProcessorAwaitDuration, duration)
+	}
+}
+
+func (or *ObsReport) recordDuration(ctx context.Context, dataType component.DataType, histogram metric.Float64Histogram, measure *stats.Float64Measure, duration time.Duration) {
+	ms := float64(duration) / float64(time.Millisecond)
+	// The full slice expressions ensure the signal is appended to copies, as the processors can record concurrently.
+	if or.useOtelForMetrics {
+		attrs := append(or.otelAttrs[:len(or.otelAttrs):len(or.otelAttrs)], attribute.String(obsmetrics.SignalKey, string(dataType)))
+		histogram.Record(ctx, ms, metric.WithAttributes(attrs...))
+		return
+	}
+	mutators := append(or.mutators[:len(or.mutators):len(or.mutators)], tag.Upsert(obsmetrics.TagKeySignal, string(dataType), tag.WithTTL(tag.TTLNoPropagation)))
+	// ignore the error for now; should not happen
+	_ = stats.RecordWithTags(ctx, mutators, measure.M(ms))
+}
@@ -6,6 +6,7 @@ package processorhelper
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -99,6 +100,23 @@ func TestProcessorLogRecords(t *testing.T) {
 	})
 }
 
+func TestProcessorDurations(t *testing.T) {
+	testTelemetry(t, processorID, func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool) {
+		obsrep, err := newObsReport(ObsReportSettings{
+			ProcessorID:             processorID,
+			ProcessorCreateSettings: processor.CreateSettings{ID: processorID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+		}, useOtel)
+		require.NoError(t, err)
+		obsrep.ProcessingDuration(context.Background(), component.DataTypeMetrics, time.Millisecond)
+		obsrep.ProcessingDuration(context.Background(), component.DataTypeMetrics, 500*time.Microsecond)
+		obsrep.ProcessingDuration(context.Background(), component.DataTypeLogs, time.Millisecond)
+		obsrep.AwaitDuration(context.Background(), component.DataTypeMetrics, time.Millisecond)
+
+		require.NoError(t, tt.CheckProcessorDurations(component.DataTypeMetrics, 2, 1))
+		require.NoError(t, tt.CheckProcessorDurations(component.DataTypeLogs, 1, 0))
+	})
+}
+
 func testTelemetry(t *testing.T, id component.ID, testFunc func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool)) {
 	t.Run("WithOC", func(t *testing.T) {
 		tt, err := obsreporttest.SetupTelemetry(id)
@@ -4,7 +4,9 @@
 package processorhelper // import "go.opentelemetry.io/collector/processor/processorhelper"
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -38,10 +40,24 @@ func WithShutdown(shutdown component.ShutdownFunc) Option {
 	}
 }
 
+// FlushFunc sends to the next component all the data buffered by an asynchronous processor.
+type FlushFunc func(context.Context) error
+
+// WithFlush sets the function called when an asynchronous processor is shut down, after the Shutdown
+// function, to send the buffered data to the next component. Once the shutdown starts, only the data
+// emitted with the context passed to the flush function is sent, the data emitted otherwise is dropped.
+// It is ignored by synchronous processors.
+func WithFlush(flush FlushFunc) Option {
+	return func(o *baseSettings) {
+		o.flushFunc = flush
+	}
+}
+
 // WithCapabilities overrides the default GetCapabilities function for an processor.
 // The default GetCapabilities function returns mutable capabilities.
 func WithCapabilities(capabilities consumer.Capabilities) Option {
 	return func(o *baseSettings) {
+		o.capabilities = capabilities
 		o.consumerOptions = append(o.consumerOptions, consumer.WithCapabilities(capabilities))
 	}
 }
@@ -49,13 +65,30 @@ func WithCapabilities(capabilities consumer.Capabilities) Option {
 type baseSettings struct {
 	component.StartFunc
 	component.ShutdownFunc
+	flushFunc       FlushFunc
+	capabilities    consumer.Capabilities
 	consumerOptions []consumer.Option
+
+	concurrency int
+	orderingKey OrderingKeyFunc
+
+	storageID   *component.ID
+	restoreFunc RestoreFunc
+	persistFunc PersistFunc
+
+	errorMode    ErrorMode
+	errorTraces  consumer.Traces
+	errorMetrics consumer.Metrics
+	errorLogs    consumer.Logs
+
+	shutdownTimeout time.Duration
 }
 
 // fromOptions returns the internal settings starting from the default and applying all options.
 func fromOptions(options []Option) *baseSettings {
 	// Start from the default options:
 	opts := &baseSettings{
+		capabilities:    consumer.Capabilities{MutatesData: true},
 		consumerOptions: []consumer.Option{consumer.WithCapabilities(consumer.Capabilities{MutatesData: true})},
 	}
 
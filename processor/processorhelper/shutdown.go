@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper // import "go.opentelemetry.io/collector/processor/processorhelper"
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// WithShutdownTimeout sets the maximum time the processor takes to shut down, so a processor waiting on an
+// external dependency does not block the shutdown of the collector. The context given to the shutdown is
+// canceled after the timeout, and if the shutdown does not return by then it is abandoned: the timeout is
+// logged and Shutdown returns nil while the shutdown keeps running. The timeout covers the Shutdown function,
+// the flush of asynchronous processors and the persistence of the state. The default 0 means no timeout.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(o *baseSettings) {
+		o.shutdownTimeout = timeout
+	}
+}
+
+// shutdownWithTimeout returns the shutdown function abandoning the given one after the timeout, if set.
+func shutdownWithTimeout(logger *zap.Logger, timeout time.Duration, shutdown component.ShutdownFunc) component.ShutdownFunc {
+	if timeout <= 0 {
+		return shutdown
+	}
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		done := make(chan error, 1)
+		go func() {
+			done <- shutdown.Shutdown(ctx)
+		}()
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			logger.Error("Processor did not shut down in time, abandoning its shutdown.",
+				zap.Duration("shutdown_timeout", timeout), zap.Error(ctx.Err()))
+			return nil
+		}
+	}
+}
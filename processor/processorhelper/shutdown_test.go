@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+func TestNewTracesProcessor_WithShutdownTimeout(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	set := processortest.NewNopCreateSettings()
+	set.Logger = zap.New(core)
+
+	release := make(chan struct{})
+	defer close(release)
+	canceled := make(chan struct{})
+	shutdown := func(ctx context.Context) error {
+		<-ctx.Done()
+		close(canceled)
+		// The shutdown keeps hanging after the cancellation.
+		<-release
+		return nil
+	}
+	tp, err := NewTracesProcessor(context.Background(), set, &testTracesCfg, consumertest.NewNop(), newTestTProcessor(nil),
+		WithShutdown(shutdown), WithShutdownTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	assert.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, tp.Shutdown(context.Background()))
+	<-canceled
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "Processor did not shut down in time, abandoning its shutdown.", logs.All()[0].Message)
+}
+
+func TestNewMetricsProcessor_WithShutdownTimeoutError(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	set := processortest.NewNopCreateSettings()
+	set.Logger = zap.New(core)
+
+	want := errors.New("my_error")
+	mp, err := NewMetricsProcessor(context.Background(), set, &testMetricsCfg, consumertest.NewNop(), newTestMProcessor(nil),
+		WithShutdown(func(context.Context) error { return want }), WithShutdownTimeout(time.Minute))
+	require.NoError(t, err)
+
+	assert.NoError(t, mp.Start(context.Background(), componenttest.NewNopHost()))
+	// The errors of the shutdown completing in time are returned.
+	assert.Equal(t, want, mp.Shutdown(context.Background()))
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestNewAsyncLogsProcessor_WithShutdownTimeout(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	set := processortest.NewNopCreateSettings()
+	set.Logger = zap.New(core)
+
+	release := make(chan struct{})
+	defer close(release)
+	flush := func(context.Context) error {
+		<-release
+		return nil
+	}
+	p := &testAsyncLProcessor{}
+	lp, err := NewAsyncLogsProcessor(context.Background(), set, &testLogsCfg, consumertest.NewNop(), p.newLogsFunc,
+		WithFlush(flush), WithShutdownTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	assert.NoError(t, lp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, lp.Shutdown(context.Background()))
+	assert.Equal(t, 1, logs.Len())
+	// The data emitted once the shutdown is abandoned is dropped.
+	assert.ErrorIs(t, p.emit(context.Background(), testdata.GenerateLogs(1)), errProcessorShutdown)
+}
+
+func TestShutdownWithTimeout_NoTimeout(t *testing.T) {
+	called := false
+	shutdown := component.ShutdownFunc(func(context.Context) error {
+		called = true
+		return nil
+	})
+	assert.NoError(t, shutdownWithTimeout(zap.NewNop(), 0, shutdown).Shutdown(context.Background()))
+	assert.True(t, called)
+	assert.NoError(t, shutdownWithTimeout(zap.NewNop(), 0, nil).Shutdown(context.Background()))
+}
@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper // import "go.opentelemetry.io/collector/processor/processorhelper"
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/multierr"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+var (
+	errNoStorageExtension = errors.New("no storage extension found")
+	errWrongExtensionType = errors.New("requested extension is not a storage extension")
+)
+
+// RestoreFunc restores the state of a processor, for instance aggregation state or deduplication windows,
+// from the storage when the processor is started. If error is returned the processor fails to start.
+type RestoreFunc func(context.Context, storage.Client) error
+
+// PersistFunc persists the state of a processor to the storage when the processor is shut down.
+type PersistFunc func(context.Context, storage.Client) error
+
+// WithStorage sets the storage extension keeping the state of the processor across restarts. The restore
+// function is called when the processor is started, before the Start function, and the persist function
+// when the processor is shut down, after the Shutdown function and the flush of asynchronous processors.
+// Either function can be nil. The storage client is named after the signal of the processor and is closed
+// on shutdown. Nothing is done when storageID is nil, so it can be set from an optional configuration field.
+func WithStorage(storageID *component.ID, restore RestoreFunc, persist PersistFunc) Option {
+	return func(o *baseSettings) {
+		o.storageID = storageID
+		o.restoreFunc = restore
+		o.persistFunc = persist
+	}
+}
+
+// GetStorageClient returns a client of the storage extension with the given ID for the processor.
+// The storageName distinguishes the storages of a processor, for instance one per signal.
+func GetStorageClient(ctx context.Context, host component.Host, storageID component.ID, processorID component.ID, storageName string) (storage.Client, error) {
+	ext, found := host.GetExtensions()[storageID]
+	if !found {
+		return nil, errNoStorageExtension
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, errWrongExtensionType
+	}
+	return storageExt.GetClient(ctx, component.KindProcessor, processorID, storageName)
+}
+
+// processorStorage implements the storage lifecycle of a processor.
+type processorStorage struct {
+	storageID   component.ID
+	processorID component.ID
+	storageName string
+	restoreFunc RestoreFunc
+	persistFunc PersistFunc
+
+	// client is set from the start until the shutdown of the processor.
+	client storage.Client
+}
+
+// newProcessorStorage returns nil if no storage is set.
+func newProcessorStorage(processorID component.ID, bs *baseSettings, dataType component.DataType) *processorStorage {
+	if bs.storageID == nil {
+		return nil
+	}
+	return &processorStorage{
+		storageID:   *bs.storageID,
+		processorID: processorID,
+		storageName: string(dataType),
+		restoreFunc: bs.restoreFunc,
+		persistFunc: bs.persistFunc,
+	}
+}
+
+// restore gets the storage client and restores the state of the processor.
+func (ps *processorStorage) restore(ctx context.Context, host component.Host) error {
+	if ps == nil {
+		return nil
+	}
+	client, err := GetStorageClient(ctx, host, ps.storageID, ps.processorID, ps.storageName)
+	if err != nil {
+		return err
+	}
+	if ps.restoreFunc != nil {
+		if err = ps.restoreFunc(ctx, client); err != nil {
+			return multierr.Append(err, client.Close(ctx))
+		}
+	}
+	ps.client = client
+	return nil
+}
+
+// persist persists the state of the processor and closes the storage client.
+func (ps *processorStorage) persist(ctx context.Context) error {
+	if ps == nil || ps.client == nil {
+		return nil
+	}
+	var err error
+	if ps.persistFunc != nil {
+		err = ps.persistFunc(ctx, ps.client)
+	}
+	err = multierr.Append(err, ps.client.Close(ctx))
+	ps.client = nil
+	return err
+}
+
+// lifecycle returns the start and shutdown functions restoring and persisting the state around the given ones.
+func (ps *processorStorage) lifecycle(start component.StartFunc, shutdown component.ShutdownFunc) (component.StartFunc, component.ShutdownFunc) {
+	if ps == nil {
+		return start, shutdown
+	}
+	return func(ctx context.Context, host component.Host) error {
+			if err := ps.restore(ctx, host); err != nil {
+				return err
+			}
+			return start.Start(ctx, host)
+		}, func(ctx context.Context) error {
+			return multierr.Append(shutdown.Shutdown(ctx), ps.persist(ctx))
+		}
+}
@@ -0,0 +1,249 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package processorhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+var testStorageID = component.NewID("test_storage")
+
+type testStorageExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	data    map[string][]byte
+	clients []*testStorageClient
+}
+
+func newTestStorageExtension() *testStorageExtension {
+	return &testStorageExtension{data: map[string][]byte{}}
+}
+
+func (e *testStorageExtension) GetClient(_ context.Context, kind component.Kind, id component.ID, name string) (storage.Client, error) {
+	c := &testStorageClient{ext: e, kind: kind, id: id, name: name}
+	e.clients = append(e.clients, c)
+	return c, nil
+}
+
+type testStorageClient struct {
+	ext    *testStorageExtension
+	kind   component.Kind
+	id     component.ID
+	name   string
+	closed bool
+}
+
+func (c *testStorageClient) Get(_ context.Context, key string) ([]byte, error) {
+	return c.ext.data[key], nil
+}
+
+func (c *testStorageClient) Set(_ context.Context, key string, value []byte) error {
+	c.ext.data[key] = value
+	return nil
+}
+
+func (c *testStorageClient) Delete(_ context.Context, key string) error {
+	delete(c.ext.data, key)
+	return nil
+}
+
+func (c *testStorageClient) Batch(ctx context.Context, ops ...storage.Operation) error {
+	for _, op := range ops {
+		var err error
+		switch op.Type {
+		case storage.Get:
+			op.Value, err = c.Get(ctx, op.Key)
+		case storage.Set:
+			err = c.Set(ctx, op.Key, op.Value)
+		case storage.Delete:
+			err = c.Delete(ctx, op.Key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *testStorageClient) Close(context.Context) error {
+	c.closed = true
+	return nil
+}
+
+type testStorageHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *testStorageHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func newTestStorageHost(ext component.Component) component.Host {
+	return &testStorageHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{testStorageID: ext},
+	}
+}
+
+func TestNewTracesProcessor_WithStorage(t *testing.T) {
+	ext := newTestStorageExtension()
+	ext.data["state"] = []byte("restored")
+
+	var calls []string
+	var state string
+	restore := func(ctx context.Context, client storage.Client) error {
+		calls = append(calls, "restore")
+		value, err := client.Get(ctx, "state")
+		state = string(value)
+		return err
+	}
+	persist := func(ctx context.Context, client storage.Client) error {
+		calls = append(calls, "persist")
+		return client.Set(ctx, "state", []byte("persisted"))
+	}
+	start := func(context.Context, component.Host) error {
+		calls = append(calls, "start")
+		return nil
+	}
+	shutdown := func(context.Context) error {
+		calls = append(calls, "shutdown")
+		return nil
+	}
+
+	set := processortest.NewNopCreateSettings()
+	tp, err := NewTracesProcessor(context.Background(), set, &testTracesCfg, consumertest.NewNop(), newTestTProcessor(nil),
+		WithStart(start), WithShutdown(shutdown), WithStorage(&testStorageID, restore, persist))
+	require.NoError(t, err)
+
+	require.NoError(t, tp.Start(context.Background(), newTestStorageHost(ext)))
+	assert.Equal(t, "restored", state)
+	require.Len(t, ext.clients, 1)
+	client := ext.clients[0]
+	assert.Equal(t, component.KindProcessor, client.kind)
+	assert.Equal(t, set.ID, client.id)
+	assert.Equal(t, string(component.DataTypeTraces), client.name)
+	assert.False(t, client.closed)
+
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+	require.NoError(t, tp.Shutdown(context.Background()))
+	assert.Equal(t, []string{"restore", "start", "shutdown", "persist"}, calls)
+	assert.Equal(t, []byte("persisted"), ext.data["state"])
+	assert.True(t, client.closed)
+
+	// A second shutdown does not persist again.
+	require.NoError(t, tp.Shutdown(context.Background()))
+	assert.Equal(t, []string{"restore", "start", "shutdown", "persist", "shutdown"}, calls)
+}
+
+func TestNewMetricsProcessor_WithStorage(t *testing.T) {
+	ext := newTestStorageExtension()
+	mp, err := NewMetricsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testMetricsCfg, consumertest.NewNop(), newTestMProcessor(nil),
+		WithStorage(&testStorageID, nil, nil))
+	require.NoError(t, err)
+
+	require.NoError(t, mp.Start(context.Background(), newTestStorageHost(ext)))
+	require.Len(t, ext.clients, 1)
+	assert.Equal(t, string(component.DataTypeMetrics), ext.clients[0].name)
+	require.NoError(t, mp.Shutdown(context.Background()))
+	assert.True(t, ext.clients[0].closed)
+}
+
+func TestNewLogsProcessor_WithStorage(t *testing.T) {
+	ext := newTestStorageExtension()
+	lp, err := NewLogsProcessor(context.Background(), processortest.NewNopCreateSettings(), &testLogsCfg, consumertest.NewNop(), newTestLProcessor(nil),
+		WithStorage(&testStorageID, nil, nil))
+	require.NoError(t, err)
+
+	require.NoError(t, lp.Start(context.Background(), newTestStorageHost(ext)))
+	require.Len(t, ext.clients, 1)
+	assert.Equal(t, string(component.DataTypeLogs), ext.clients[0].name)
+	require.NoError(t, lp.Shutdown(context.Background()))
+	assert.True(t, ext.clients[0].closed)
+}
+
+func TestNewAsyncTracesProcessor_WithStorage(t *testing.T) {
+	ext := newTestStorageExtension()
+	sink := new(consumertest.TracesSink)
+	p := &testAsyncTProcessor{}
+	persist := func(ctx context.Context, client storage.Client) error {
+		// The buffered data is flushed before the state is persisted.
+		assert.Equal(t, 1, sink.SpanCount())
+		return client.Set(ctx, "state", []byte("persisted"))
+	}
+	tp, err := NewAsyncTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, sink, p.newTracesFunc,
+		WithFlush(p.flush), WithStorage(&testStorageID, nil, persist))
+	require.NoError(t, err)
+
+	require.NoError(t, tp.Start(context.Background(), newTestStorageHost(ext)))
+	require.Len(t, ext.clients, 1)
+	assert.Equal(t, string(component.DataTypeTraces), ext.clients[0].name)
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+	require.NoError(t, tp.Shutdown(context.Background()))
+	assert.Equal(t, []byte("persisted"), ext.data["state"])
+	assert.True(t, ext.clients[0].closed)
+}
+
+func TestNewTracesProcessor_WithStorageRestoreError(t *testing.T) {
+	ext := newTestStorageExtension()
+	want := errors.New("my_error")
+	started := false
+	tp, err := NewTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, consumertest.NewNop(), newTestTProcessor(nil),
+		WithStart(func(context.Context, component.Host) error {
+			started = true
+			return nil
+		}),
+		WithStorage(&testStorageID, func(context.Context, storage.Client) error { return want }, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, want, tp.Start(context.Background(), newTestStorageHost(ext)))
+	assert.False(t, started)
+	require.Len(t, ext.clients, 1)
+	assert.True(t, ext.clients[0].closed)
+}
+
+func TestNewTracesProcessor_WithNilStorage(t *testing.T) {
+	called := false
+	restore := func(context.Context, storage.Client) error {
+		called = true
+		return nil
+	}
+	tp, err := NewTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, consumertest.NewNop(), newTestTProcessor(nil),
+		WithStorage(nil, restore, nil))
+	require.NoError(t, err)
+
+	assert.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, tp.Shutdown(context.Background()))
+	assert.False(t, called)
+}
+
+func TestGetStorageClient(t *testing.T) {
+	ext := newTestStorageExtension()
+	processorID := component.NewID("test_processor")
+
+	client, err := GetStorageClient(context.Background(), newTestStorageHost(ext), testStorageID, processorID, "name")
+	require.NoError(t, err)
+	assert.Equal(t, &testStorageClient{ext: ext, kind: component.KindProcessor, id: processorID, name: "name"}, client)
+
+	_, err = GetStorageClient(context.Background(), componenttest.NewNopHost(), testStorageID, processorID, "name")
+	assert.ErrorIs(t, err, errNoStorageExtension)
+
+	_, err = GetStorageClient(context.Background(), newTestStorageHost(&struct {
+		component.StartFunc
+		component.ShutdownFunc
+	}{}), testStorageID, processorID, "name")
+	assert.ErrorIs(t, err, errWrongExtensionType)
+}
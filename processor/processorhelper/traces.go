@@ -6,11 +6,13 @@ package processorhelper // import "go.opentelemetry.io/collector/processor/proce
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
 )
@@ -45,28 +47,191 @@ func NewTracesProcessor(
 
 	eventOptions := spanAttributes(set.ID)
 	bs := fromOptions(options)
+	obsrep, err := NewObsReport(ObsReportSettings{ProcessorID: set.ID, ProcessorCreateSettings: set})
+	if err != nil {
+		return nil, err
+	}
+	if detectMutations(bs) {
+		tracesFunc = detectTracesMutations(set.Logger, tracesFunc)
+	}
+	// Each call of the processing function, on each worker, is reported as the processing of a batch.
+	processFunc := tracesFunc
+	tracesFunc = func(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+		start := time.Now()
+		defer func() { obsrep.ProcessingDuration(ctx, component.DataTypeTraces, time.Since(start)) }()
+		return processFunc(ctx, td)
+	}
+	wp := newWorkerPool(bs, obsrep, component.DataTypeTraces)
+	if wp != nil {
+		tracesFunc = parallelTracesFunc(wp, tracesFunc)
+	}
+	eh, err := newErrorHandler(set, bs, obsrep, bs.errorTraces != nil)
+	if err != nil {
+		return nil, err
+	}
 	traceConsumer, err := consumer.NewTraces(func(ctx context.Context, td ptrace.Traces) error {
 		span := trace.SpanFromContext(ctx)
 		span.AddEvent("Start processing.", eventOptions)
-		var err error
-		td, err = tracesFunc(ctx, td)
+		processed, err := tracesFunc(ctx, td)
 		span.AddEvent("End processing.", eventOptions)
 		if err != nil {
 			if errors.Is(err, ErrSkipProcessingData) {
 				return nil
 			}
-			return err
+			return eh.handleTraces(ctx, td, err)
 		}
-		return nextConsumer.ConsumeTraces(ctx, td)
+		return nextConsumer.ConsumeTraces(ctx, processed)
 	}, bs.consumerOptions...)
 
 	if err != nil {
 		return nil, err
 	}
 
+	start, shutdown := wp.lifecycle(bs.StartFunc, bs.ShutdownFunc)
+	start, shutdown = newProcessorStorage(set.ID, bs, component.DataTypeTraces).lifecycle(start, shutdown)
+	shutdown = shutdownWithTimeout(set.Logger, bs.shutdownTimeout, shutdown)
 	return &tracesProcessor{
-		StartFunc:    bs.StartFunc,
-		ShutdownFunc: bs.ShutdownFunc,
+		StartFunc:    start,
+		ShutdownFunc: shutdown,
 		Traces:       traceConsumer,
 	}, nil
 }
+
+// parallelTracesFunc splits the traces by resource between the workers of the pool, processes them in parallel
+// and merges the processed traces. If the processing fails the traces are moved back to the input.
+func parallelTracesFunc(wp *workerPool, tracesFunc ProcessTracesFunc) ProcessTracesFunc {
+	return func(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+		rss := td.ResourceSpans()
+		workers, split := wp.assign(rss.Len(), func(i int) pcommon.Resource { return rss.At(i).Resource() })
+		jobs := make([]func(), wp.size)
+		if !split {
+			var processed ptrace.Traces
+			var err error
+			w := 0
+			if len(workers) > 0 {
+				w = workers[0]
+			}
+			jobs[w] = func() { processed, err = tracesFunc(ctx, td) }
+			wp.run(ctx, jobs)
+			return processed, err
+		}
+
+		inputs := make([]ptrace.Traces, wp.size)
+		outputs := make([]ptrace.Traces, wp.size)
+		errs := make([]error, wp.size)
+		for i, w := range workers {
+			if jobs[w] == nil {
+				w := w
+				inputs[w] = ptrace.NewTraces()
+				jobs[w] = func() { outputs[w], errs[w] = tracesFunc(ctx, inputs[w]) }
+			}
+			rss.At(i).MoveTo(inputs[w].ResourceSpans().AppendEmpty())
+		}
+		rss.RemoveIf(func(ptrace.ResourceSpans) bool { return true })
+		wp.run(ctx, jobs)
+
+		skipped, err := result(jobs, errs)
+		if err != nil {
+			for w, job := range jobs {
+				if job != nil {
+					inputs[w].ResourceSpans().MoveAndAppendTo(rss)
+				}
+			}
+			return td, err
+		}
+		if skipped {
+			return td, ErrSkipProcessingData
+		}
+		processed := ptrace.NewTraces()
+		for w, job := range jobs {
+			if job != nil && errs[w] == nil {
+				outputs[w].ResourceSpans().MoveAndAppendTo(processed.ResourceSpans())
+			}
+		}
+		return processed, nil
+	}
+}
+
+// EmitTracesFunc sends traces to the next component of an asynchronous processor. It can be called
+// from any goroutine until the processor starts shutting down, then only by the flush function.
+type EmitTracesFunc func(context.Context, ptrace.Traces) error
+
+// ProcessTracesAsyncFunc is a helper function that processes the incoming data asynchronously. It can keep the
+// data and send it to the next component later with the EmitTracesFunc. If error is returned the data is refused.
+type ProcessTracesAsyncFunc func(context.Context, ptrace.Traces) error
+
+type asyncTracesProcessor struct {
+	*asyncProcessor
+	consumer.Traces
+}
+
+// NewAsyncTracesProcessor creates a processor.Traces that buffers data and emits it on its own schedule.
+// newTracesFunc is called once with the function that sends data to the next component and returns the function
+// that processes the incoming data. Use WithFlush to emit the buffered data when the processor is shut down.
+// The incoming data is recorded as accepted, refused or, if ErrSkipProcessingData is returned, dropped. Emitted
+// data that the next component fails to consume is recorded as dropped.
+func NewAsyncTracesProcessor(
+	_ context.Context,
+	set processor.CreateSettings,
+	_ component.Config,
+	nextConsumer consumer.Traces,
+	newTracesFunc func(EmitTracesFunc) ProcessTracesAsyncFunc,
+	options ...Option,
+) (processor.Traces, error) {
+	if newTracesFunc == nil {
+		return nil, errors.New("nil newTracesFunc")
+	}
+
+	if nextConsumer == nil {
+		return nil, component.ErrNilNextConsumer
+	}
+
+	bs := fromOptions(options)
+	ap, err := newAsyncProcessor(ObsReportSettings{ProcessorID: set.ID, ProcessorCreateSettings: set}, bs, component.DataTypeTraces)
+	if err != nil {
+		return nil, err
+	}
+
+	tracesFunc := newTracesFunc(func(ctx context.Context, td ptrace.Traces) error {
+		numSpans := td.SpanCount()
+		err := ap.emit(ctx, func() error {
+			return nextConsumer.ConsumeTraces(ctx, td)
+		})
+		if err != nil {
+			ap.obsrep.TracesDropped(ctx, numSpans)
+		}
+		return err
+	})
+	if tracesFunc == nil {
+		return nil, errors.New("nil tracesFunc")
+	}
+
+	eventOptions := spanAttributes(set.ID)
+	traceConsumer, err := consumer.NewTraces(func(ctx context.Context, td ptrace.Traces) error {
+		span := trace.SpanFromContext(ctx)
+		span.AddEvent("Start processing.", eventOptions)
+		numSpans := td.SpanCount()
+		start := time.Now()
+		err := tracesFunc(ctx, td)
+		ap.obsrep.ProcessingDuration(ctx, component.DataTypeTraces, time.Since(start))
+		span.AddEvent("End processing.", eventOptions)
+		switch {
+		case err == nil:
+			ap.obsrep.TracesAccepted(ctx, numSpans)
+		case errors.Is(err, ErrSkipProcessingData):
+			ap.obsrep.TracesDropped(ctx, numSpans)
+			return nil
+		default:
+			ap.obsrep.TracesRefused(ctx, numSpans)
+		}
+		return err
+	}, bs.consumerOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &asyncTracesProcessor{
+		asyncProcessor: ap,
+		Traces:         traceConsumer,
+	}, nil
+}
@@ -6,15 +6,19 @@ package processorhelper
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor/processortest"
 )
@@ -70,3 +74,176 @@ func newTestTProcessor(retError error) ProcessTracesFunc {
 		return td, retError
 	}
 }
+
+// testAsyncTProcessor buffers the incoming traces and emits them on flush.
+type testAsyncTProcessor struct {
+	retError error
+	emit     EmitTracesFunc
+	mu       sync.Mutex
+	buffered []ptrace.Traces
+}
+
+func (p *testAsyncTProcessor) newTracesFunc(emit EmitTracesFunc) ProcessTracesAsyncFunc {
+	p.emit = emit
+	return func(_ context.Context, td ptrace.Traces) error {
+		if p.retError != nil {
+			return p.retError
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.buffered = append(p.buffered, td)
+		return nil
+	}
+}
+
+func (p *testAsyncTProcessor) flush(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var errs error
+	for _, td := range p.buffered {
+		errs = multierr.Append(errs, p.emit(ctx, td))
+	}
+	p.buffered = nil
+	return errs
+}
+
+func TestNewAsyncTracesProcessor(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	p := &testAsyncTProcessor{}
+	tp, err := NewAsyncTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, sink, p.newTracesFunc, WithFlush(p.flush))
+	require.NoError(t, err)
+
+	assert.True(t, tp.Capabilities().MutatesData)
+	assert.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+	assert.Equal(t, 0, sink.SpanCount())
+
+	// Data is emitted on the processor schedule.
+	require.NoError(t, p.flush(context.Background()))
+	assert.Equal(t, 2, sink.SpanCount())
+
+	// Buffered data is emitted on shutdown.
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), testdata.GenerateTraces(3)))
+	assert.NoError(t, tp.Shutdown(context.Background()))
+	assert.Equal(t, 5, sink.SpanCount())
+
+	// Data emitted after shutdown is dropped.
+	assert.ErrorIs(t, p.emit(context.Background(), testdata.GenerateTraces(1)), errProcessorShutdown)
+	assert.Equal(t, 5, sink.SpanCount())
+}
+
+func TestNewAsyncTracesProcessor_WithOptions(t *testing.T) {
+	want := errors.New("my_error")
+	p := &testAsyncTProcessor{}
+	tp, err := NewAsyncTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, consumertest.NewNop(), p.newTracesFunc,
+		WithStart(func(context.Context, component.Host) error { return want }),
+		WithShutdown(func(context.Context) error { return want }),
+		WithCapabilities(consumer.Capabilities{MutatesData: false}))
+	assert.NoError(t, err)
+
+	assert.Equal(t, want, tp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, want, tp.Shutdown(context.Background()))
+	assert.False(t, tp.Capabilities().MutatesData)
+}
+
+func TestNewAsyncTracesProcessor_NilRequiredFields(t *testing.T) {
+	_, err := NewAsyncTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, consumertest.NewNop(), nil)
+	assert.Error(t, err)
+
+	_, err = NewAsyncTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, consumertest.NewNop(),
+		func(EmitTracesFunc) ProcessTracesAsyncFunc { return nil })
+	assert.Error(t, err)
+
+	p := &testAsyncTProcessor{}
+	_, err = NewAsyncTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, nil, p.newTracesFunc)
+	assert.Equal(t, component.ErrNilNextConsumer, err)
+}
+
+func TestNewAsyncTracesProcessor_ProcessTraceError(t *testing.T) {
+	want := errors.New("my_error")
+	p := &testAsyncTProcessor{retError: want}
+	tp, err := NewAsyncTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, consumertest.NewNop(), p.newTracesFunc)
+	require.NoError(t, err)
+	assert.Equal(t, want, tp.ConsumeTraces(context.Background(), ptrace.NewTraces()))
+}
+
+func TestNewAsyncTracesProcessor_ProcessTracesErrSkipProcessingData(t *testing.T) {
+	p := &testAsyncTProcessor{retError: ErrSkipProcessingData}
+	tp, err := NewAsyncTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, consumertest.NewNop(), p.newTracesFunc)
+	require.NoError(t, err)
+	assert.Equal(t, nil, tp.ConsumeTraces(context.Background(), ptrace.NewTraces()))
+}
+
+func TestNewAsyncTracesProcessor_ObsReport(t *testing.T) {
+	tt, err := obsreporttest.SetupTelemetry(processorID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	set := processortest.NewNopCreateSettings()
+	set.ID = processorID
+	set.TelemetrySettings = tt.TelemetrySettings
+
+	p := &testAsyncTProcessor{}
+	tp, err := NewAsyncTracesProcessor(context.Background(), set, &testTracesCfg, consumertest.NewErr(errors.New("my_error")), p.newTracesFunc, WithFlush(p.flush))
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), testdata.GenerateTraces(3)))
+	p.retError = errors.New("my_error")
+	assert.Error(t, tp.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+	p.retError = ErrSkipProcessingData
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+
+	// The buffered spans fail to be emitted on shutdown and are dropped.
+	assert.Error(t, tp.Shutdown(context.Background()))
+	require.NoError(t, tt.CheckProcessorTraces(3, 2, 4))
+	require.NoError(t, tt.CheckProcessorDurations(component.DataTypeTraces, 3, 0))
+}
+
+func TestNewAsyncTracesProcessor_ShutdownWaitsForEmit(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	p := &testAsyncTProcessor{}
+	tp, err := NewAsyncTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, sink, p.newTracesFunc)
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if err := p.emit(context.Background(), testdata.GenerateTraces(1)); err != nil {
+					return
+				}
+			}
+		}()
+	}
+	require.NoError(t, tp.Shutdown(context.Background()))
+	count := sink.SpanCount()
+	wg.Wait()
+	assert.Equal(t, count, sink.SpanCount())
+}
+
+func TestNewAsyncTracesProcessor_ShutdownDropsEmits(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	p := &testAsyncTProcessor{}
+	var shutdownErr error
+	tp, err := NewAsyncTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), &testTracesCfg, sink, p.newTracesFunc,
+		WithShutdown(func(ctx context.Context) error {
+			// The data emitted on the processor's own schedule once the shutdown started is dropped.
+			shutdownErr = p.emit(ctx, testdata.GenerateTraces(1))
+			return nil
+		}),
+		WithFlush(p.flush))
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, tp.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+	assert.ErrorIs(t, shutdownErr, errProcessorShutdown)
+	// The buffered data is flushed.
+	assert.Equal(t, 2, sink.SpanCount())
+	assert.ErrorIs(t, p.emit(context.Background(), testdata.GenerateTraces(1)), errProcessorShutdown)
+	assert.Equal(t, 2, sink.SpanCount())
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ratelimiterprocessor provides a processor for OpenTelemetry Service pipeline
+// that refuses data on the pipeline when it exceeds the configured rate.
+package ratelimiterprocessor // import "go.opentelemetry.io/collector/processor/ratelimiterprocessor"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Config defines configuration for the rate limiter processor.
+type Config struct {
+	// ItemsPerSecond is the maximum rate of spans, metric data points or log records. Zero means no limit.
+	ItemsPerSecond float64 `mapstructure:"items_per_second"`
+
+	// ItemsBurst is the maximum number of items that can be accepted at once above the rate.
+	// Zero means the burst is equal to ItemsPerSecond.
+	ItemsBurst int `mapstructure:"items_burst"`
+
+	// BytesPerSecond is the maximum rate of bytes, estimated as the size of the data serialized
+	// with the OTLP protobuf encoding. Zero means no limit.
+	BytesPerSecond float64 `mapstructure:"bytes_per_second"`
+
+	// BytesBurst is the maximum number of bytes that can be accepted at once above the rate.
+	// Zero means the burst is equal to BytesPerSecond.
+	BytesBurst int `mapstructure:"bytes_burst"`
+
+	// ResourceAttribute is the resource attribute used to apply the limits separately for each of
+	// its values. When empty, the limits apply to all the data of the pipeline.
+	ResourceAttribute string `mapstructure:"resource_attribute"`
+
+	// ResourceAttributeCardinalityLimit is the maximum number of distinct values of ResourceAttribute
+	// with their own limits. Data with other values shares the limits of the data that does not have
+	// the resource attribute. Zero means no limit.
+	ResourceAttributeCardinalityLimit uint32 `mapstructure:"resource_attribute_cardinality_limit"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.ItemsPerSecond < 0 || cfg.BytesPerSecond < 0 {
+		return errors.New("rate must not be negative")
+	}
+	if cfg.ItemsBurst < 0 || cfg.BytesBurst < 0 {
+		return errors.New("burst must not be negative")
+	}
+	if cfg.ItemsPerSecond == 0 && cfg.BytesPerSecond == 0 {
+		return errors.New("at least one of items_per_second or bytes_per_second must be set")
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimiterprocessor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+)
+
+func TestUnmarshalDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, component.UnmarshalConfig(confmap.New(), cfg))
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg)
+}
+
+func TestUnmarshalConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, component.UnmarshalConfig(cm, cfg))
+	assert.Equal(t,
+		&Config{
+			ItemsPerSecond:                    10000,
+			ItemsBurst:                        20000,
+			BytesPerSecond:                    10485760,
+			ResourceAttribute:                 "tenant.id",
+			ResourceAttributeCardinalityLimit: 100,
+		}, cfg)
+	assert.NoError(t, component.ValidateConfig(cfg))
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{
+			name: "no_limit",
+			cfg:  &Config{},
+		},
+		{
+			name: "negative_rate",
+			cfg:  &Config{ItemsPerSecond: -1, BytesPerSecond: 10},
+		},
+		{
+			name: "negative_burst",
+			cfg:  &Config{ItemsPerSecond: 10, BytesBurst: -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, component.ValidateConfig(tt.cfg))
+		})
+	}
+
+	assert.NoError(t, component.ValidateConfig(&Config{BytesPerSecond: 10}))
+}
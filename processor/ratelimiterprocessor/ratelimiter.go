@@ -0,0 +1,332 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimiterprocessor // import "go.opentelemetry.io/collector/processor/ratelimiterprocessor"
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+// errDataRefused will be returned to callers of ConsumeTraceData, wrapped in a
+// consumererror.Backpressure with the time until enough tokens are available, to
+// indicate that data is being refused because it exceeds the rate limits. The error
+// is not permanent, so the receivers can retry sending the data later.
+var errDataRefused = fmt.Errorf("data refused: %w", consumererror.ErrRateLimited)
+
+// tokenBucket implements the token bucket algorithm. Unlike the exporterhelper one,
+// tokens are never reserved in advance: the data is refused if not enough tokens
+// are available.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	b := float64(burst)
+	if b == 0 {
+		b = math.Ceil(rate)
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  b,
+		tokens: b,
+		last:   now,
+	}
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (tb *tokenBucket) refill(now time.Time) {
+	tb.tokens = math.Min(tb.burst, tb.tokens+now.Sub(tb.last).Seconds()*tb.rate)
+	tb.last = now
+}
+
+func (tb *tokenBucket) take(n float64) {
+	tb.tokens -= math.Min(n, tb.burst)
+}
+
+// delay returns the time until n tokens are available, given the deficit of tokens and the rate,
+// or zero if they are available. Data larger than the burst is allowed when the bucket is full,
+// so it is not refused forever.
+func (tb *tokenBucket) delay(n float64) time.Duration {
+	deficit := math.Min(n, tb.burst) - tb.tokens
+	if deficit <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(deficit / tb.rate * float64(time.Second)))
+}
+
+// limiter holds the token buckets of a value of the resource attribute.
+type limiter struct {
+	items *tokenBucket
+	bytes *tokenBucket
+}
+
+// usage is the amount of items and bytes of a value of the resource attribute in the incoming data.
+type usage struct {
+	items float64
+	bytes float64
+}
+
+type rateLimiter struct {
+	cfg    *Config
+	logger *zap.Logger
+	obsrep *processorhelper.ObsReport
+
+	// now is overridable by tests.
+	now func() time.Time
+
+	tracesSizer  ptrace.Sizer
+	metricsSizer pmetric.Sizer
+	logsSizer    plog.Sizer
+
+	// mu guards limiters and cardinalityLogged. It is held while checking all the
+	// limiters of the incoming data, so the data is either accepted or refused as a whole.
+	mu                sync.Mutex
+	limiters          map[string]*limiter
+	cardinalityLogged bool
+}
+
+// newRateLimiter returns a new ratelimiter processor.
+func newRateLimiter(set processor.CreateSettings, cfg *Config) (*rateLimiter, error) {
+	obsrep, err := processorhelper.NewObsReport(processorhelper.ObsReportSettings{
+		ProcessorID:             set.ID,
+		ProcessorCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	set.Logger.Info("Rate limiter configured",
+		zap.Float64("items_per_second", cfg.ItemsPerSecond),
+		zap.Float64("bytes_per_second", cfg.BytesPerSecond),
+		zap.String("resource_attribute", cfg.ResourceAttribute))
+
+	return &rateLimiter{
+		cfg:          cfg,
+		logger:       set.Logger,
+		obsrep:       obsrep,
+		now:          time.Now,
+		tracesSizer:  &ptrace.ProtoMarshaler{},
+		metricsSizer: &pmetric.ProtoMarshaler{},
+		logsSizer:    &plog.ProtoMarshaler{},
+		limiters:     map[string]*limiter{},
+	}, nil
+}
+
+// resourceKey returns the value of the resource attribute used to select the limiter.
+func (rl *rateLimiter) resourceKey(res pcommon.Resource) string {
+	if rl.cfg.ResourceAttribute == "" {
+		return ""
+	}
+	if v, ok := res.Attributes().Get(rl.cfg.ResourceAttribute); ok {
+		return v.AsString()
+	}
+	return ""
+}
+
+// addUsage adds the items of a resource to the usages, the bytes are computed later.
+func addUsage(usages map[string]*usage, key string, items int) {
+	u, ok := usages[key]
+	if !ok {
+		u = &usage{}
+		usages[key] = u
+	}
+	u.items += float64(items)
+}
+
+// getLimiter returns the limiter for the key, creating it if needed. It must be called with mu held.
+func (rl *rateLimiter) getLimiter(key string, now time.Time) *limiter {
+	if l, ok := rl.limiters[key]; ok {
+		return l
+	}
+	numKeys := len(rl.limiters)
+	if _, ok := rl.limiters[""]; ok {
+		numKeys--
+	}
+	if key != "" && rl.cfg.ResourceAttributeCardinalityLimit != 0 &&
+		uint32(numKeys) >= rl.cfg.ResourceAttributeCardinalityLimit {
+		if !rl.cardinalityLogged {
+			rl.cardinalityLogged = true
+			rl.logger.Warn("Too many distinct values of the resource attribute, the data with new values shares the limits of the data without the resource attribute.",
+				zap.String("resource_attribute", rl.cfg.ResourceAttribute),
+				zap.Uint32("resource_attribute_cardinality_limit", rl.cfg.ResourceAttributeCardinalityLimit))
+		}
+		return rl.getLimiter("", now)
+	}
+	l := &limiter{}
+	if rl.cfg.ItemsPerSecond > 0 {
+		l.items = newTokenBucket(rl.cfg.ItemsPerSecond, rl.cfg.ItemsBurst, now)
+	}
+	if rl.cfg.BytesPerSecond > 0 {
+		l.bytes = newTokenBucket(rl.cfg.BytesPerSecond, rl.cfg.BytesBurst, now)
+	}
+	rl.limiters[key] = l
+	return l
+}
+
+// allow returns whether the data with the given usages is within the limits, taking the tokens if so.
+// Otherwise, it returns the time until enough tokens are available for all the limiters of the data.
+// The bytes of the data are split between the values of the resource attribute proportionally to
+// their items.
+func (rl *rateLimiter) allow(usages map[string]*usage, totalItems int, totalBytes int) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	limiters := make(map[*limiter]*usage, len(usages))
+	for key, u := range usages {
+		if totalItems > 0 {
+			u.bytes = float64(totalBytes) * u.items / float64(totalItems)
+		}
+		l := rl.getLimiter(key, now)
+		// Keys over the cardinality limit share the same limiter.
+		if lu, ok := limiters[l]; ok {
+			lu.items += u.items
+			lu.bytes += u.bytes
+			continue
+		}
+		limiters[l] = &usage{items: u.items, bytes: u.bytes}
+	}
+
+	var retryAfter time.Duration
+	for l, u := range limiters {
+		if l.items != nil {
+			l.items.refill(now)
+			if d := l.items.delay(u.items); d > retryAfter {
+				retryAfter = d
+			}
+		}
+		if l.bytes != nil {
+			l.bytes.refill(now)
+			if d := l.bytes.delay(u.bytes); d > retryAfter {
+				retryAfter = d
+			}
+		}
+	}
+	if retryAfter > 0 {
+		return false, retryAfter
+	}
+	for l, u := range limiters {
+		if l.items != nil {
+			l.items.take(u.items)
+		}
+		if l.bytes != nil {
+			l.bytes.take(u.bytes)
+		}
+	}
+	return true, 0
+}
+
+func (rl *rateLimiter) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	numSpans := td.SpanCount()
+	usages := map[string]*usage{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		items := 0
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			items += rs.ScopeSpans().At(j).Spans().Len()
+		}
+		addUsage(usages, rl.resourceKey(rs.Resource()), items)
+	}
+	var numBytes int
+	if rl.cfg.BytesPerSecond > 0 {
+		numBytes = rl.tracesSizer.TracesSize(td)
+	}
+
+	if ok, retryAfter := rl.allow(usages, numSpans, numBytes); !ok {
+		rl.obsrep.TracesRefused(ctx, numSpans)
+		return td, consumererror.NewBackpressure(errDataRefused, retryAfter)
+	}
+
+	rl.obsrep.TracesAccepted(ctx, numSpans)
+	return td, nil
+}
+
+func (rl *rateLimiter) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	numDataPoints := md.DataPointCount()
+	usages := map[string]*usage{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		items := 0
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			ms := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				items += dataPointCount(ms.At(k))
+			}
+		}
+		addUsage(usages, rl.resourceKey(rm.Resource()), items)
+	}
+	var numBytes int
+	if rl.cfg.BytesPerSecond > 0 {
+		numBytes = rl.metricsSizer.MetricsSize(md)
+	}
+
+	if ok, retryAfter := rl.allow(usages, numDataPoints, numBytes); !ok {
+		rl.obsrep.MetricsRefused(ctx, numDataPoints)
+		return md, consumererror.NewBackpressure(errDataRefused, retryAfter)
+	}
+
+	rl.obsrep.MetricsAccepted(ctx, numDataPoints)
+	return md, nil
+}
+
+func (rl *rateLimiter) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	numRecords := ld.LogRecordCount()
+	usages := map[string]*usage{}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		resLogs := rls.At(i)
+		items := 0
+		for j := 0; j < resLogs.ScopeLogs().Len(); j++ {
+			items += resLogs.ScopeLogs().At(j).LogRecords().Len()
+		}
+		addUsage(usages, rl.resourceKey(resLogs.Resource()), items)
+	}
+	var numBytes int
+	if rl.cfg.BytesPerSecond > 0 {
+		numBytes = rl.logsSizer.LogsSize(ld)
+	}
+
+	if ok, retryAfter := rl.allow(usages, numRecords, numBytes); !ok {
+		rl.obsrep.LogsRefused(ctx, numRecords)
+		return ld, consumererror.NewBackpressure(errDataRefused, retryAfter)
+	}
+
+	rl.obsrep.LogsAccepted(ctx, numRecords)
+	return ld, nil
+}
+
+// dataPointCount returns the number of data points of the metric.
+func dataPointCount(m pmetric.Metric) int {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		return m.Gauge().DataPoints().Len()
+	case pmetric.MetricTypeSum:
+		return m.Sum().DataPoints().Len()
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().DataPoints().Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		return m.ExponentialHistogram().DataPoints().Len()
+	case pmetric.MetricTypeSummary:
+		return m.Summary().DataPoints().Len()
+	}
+	return 0
+}
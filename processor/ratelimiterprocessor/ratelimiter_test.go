@@ -0,0 +1,216 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimiterprocessor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+// fakeClock is a clock advanced manually by the tests.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestRateLimiter(t *testing.T, cfg *Config) (*rateLimiter, *fakeClock) {
+	rl, err := newRateLimiter(processortest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	clock := &fakeClock{now: time.Now()}
+	rl.now = clock.Now
+	return rl, clock
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	tb := newTokenBucket(10, 0, now)
+	assert.Zero(t, tb.delay(10))
+	tb.take(10)
+	// The delay is the time until the missing tokens are refilled at the rate.
+	assert.Equal(t, 100*time.Millisecond, tb.delay(1))
+
+	tb.refill(now.Add(500 * time.Millisecond))
+	assert.Zero(t, tb.delay(5))
+	assert.Equal(t, 100*time.Millisecond, tb.delay(6))
+
+	// The bucket does not fill above the burst.
+	tb.refill(now.Add(time.Hour))
+	assert.Equal(t, float64(10), tb.tokens)
+
+	// Data larger than the burst is allowed when the bucket is full, so it is not refused forever.
+	assert.Zero(t, tb.delay(100))
+	tb.take(100)
+	assert.Equal(t, float64(0), tb.tokens)
+	assert.Equal(t, time.Second, tb.delay(100))
+}
+
+func TestTracesRateLimit(t *testing.T) {
+	rl, clock := newTestRateLimiter(t, &Config{ItemsPerSecond: 10})
+	ctx := context.Background()
+
+	_, err := rl.processTraces(ctx, testdata.GenerateTraces(8))
+	assert.NoError(t, err)
+	_, err = rl.processTraces(ctx, testdata.GenerateTraces(3))
+	assert.ErrorIs(t, err, errDataRefused)
+	assert.ErrorIs(t, err, consumererror.ErrRateLimited)
+	var bpErr consumererror.Backpressure
+	require.ErrorAs(t, err, &bpErr)
+	// 1 span is missing, refilled at 10 spans per second.
+	assert.Equal(t, 100*time.Millisecond, bpErr.RetryAfter())
+	assert.False(t, consumererror.IsPermanent(err))
+
+	clock.now = clock.now.Add(100 * time.Millisecond)
+	_, err = rl.processTraces(ctx, testdata.GenerateTraces(3))
+	assert.NoError(t, err)
+}
+
+func TestMetricsRateLimit(t *testing.T) {
+	rl, clock := newTestRateLimiter(t, &Config{ItemsPerSecond: 10})
+	ctx := context.Background()
+
+	// Each generated metric has 2 data points.
+	_, err := rl.processMetrics(ctx, testdata.GenerateMetrics(4))
+	assert.NoError(t, err)
+	_, err = rl.processMetrics(ctx, testdata.GenerateMetrics(2))
+	assert.ErrorIs(t, err, errDataRefused)
+
+	clock.now = clock.now.Add(200 * time.Millisecond)
+	_, err = rl.processMetrics(ctx, testdata.GenerateMetrics(2))
+	assert.NoError(t, err)
+}
+
+func TestLogsRateLimit(t *testing.T) {
+	rl, clock := newTestRateLimiter(t, &Config{ItemsPerSecond: 10})
+	ctx := context.Background()
+
+	_, err := rl.processLogs(ctx, testdata.GenerateLogs(10))
+	assert.NoError(t, err)
+	_, err = rl.processLogs(ctx, testdata.GenerateLogs(1))
+	assert.ErrorIs(t, err, errDataRefused)
+
+	clock.now = clock.now.Add(time.Second)
+	_, err = rl.processLogs(ctx, testdata.GenerateLogs(10))
+	assert.NoError(t, err)
+}
+
+func TestBytesRateLimit(t *testing.T) {
+	td := testdata.GenerateTraces(5)
+	size := (&ptrace.ProtoMarshaler{}).TracesSize(td)
+	rl, clock := newTestRateLimiter(t, &Config{ItemsPerSecond: 1000, BytesPerSecond: float64(2 * size)})
+	ctx := context.Background()
+
+	_, err := rl.processTraces(ctx, testdata.GenerateTraces(5))
+	assert.NoError(t, err)
+	_, err = rl.processTraces(ctx, testdata.GenerateTraces(5))
+	assert.NoError(t, err)
+	_, err = rl.processTraces(ctx, testdata.GenerateTraces(5))
+	assert.ErrorIs(t, err, errDataRefused)
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	_, err = rl.processTraces(ctx, testdata.GenerateTraces(5))
+	assert.NoError(t, err)
+
+	// The bytes limit is checked for metrics and logs too.
+	md := testdata.GenerateMetrics(5)
+	rl, _ = newTestRateLimiter(t, &Config{BytesPerSecond: float64((&pmetric.ProtoMarshaler{}).MetricsSize(md))})
+	_, err = rl.processMetrics(ctx, md)
+	assert.NoError(t, err)
+	_, err = rl.processMetrics(ctx, testdata.GenerateMetrics(5))
+	assert.ErrorIs(t, err, errDataRefused)
+
+	ld := testdata.GenerateLogs(5)
+	rl, _ = newTestRateLimiter(t, &Config{BytesPerSecond: float64((&plog.ProtoMarshaler{}).LogsSize(ld))})
+	_, err = rl.processLogs(ctx, ld)
+	assert.NoError(t, err)
+	_, err = rl.processLogs(ctx, testdata.GenerateLogs(5))
+	assert.ErrorIs(t, err, errDataRefused)
+}
+
+func generateTenantTraces(tenants ...string) ptrace.Traces {
+	td := ptrace.NewTraces()
+	for _, tenant := range tenants {
+		rs := td.ResourceSpans().AppendEmpty()
+		if tenant != "" {
+			rs.Resource().Attributes().PutStr("tenant.id", tenant)
+		}
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+	}
+	return td
+}
+
+func TestResourceAttributeRateLimit(t *testing.T) {
+	rl, _ := newTestRateLimiter(t, &Config{ItemsPerSecond: 2, ResourceAttribute: "tenant.id", ResourceAttributeCardinalityLimit: 10})
+	ctx := context.Background()
+
+	_, err := rl.processTraces(ctx, generateTenantTraces("a", "a"))
+	assert.NoError(t, err)
+	_, err = rl.processTraces(ctx, generateTenantTraces("a"))
+	assert.ErrorIs(t, err, errDataRefused)
+
+	// Other tenants have their own limits.
+	_, err = rl.processTraces(ctx, generateTenantTraces("b", "", ""))
+	assert.NoError(t, err)
+
+	// The data is refused as a whole if any tenant exceeds its limit, without taking the tokens of the others.
+	_, err = rl.processTraces(ctx, generateTenantTraces("b", "a"))
+	assert.ErrorIs(t, err, errDataRefused)
+	_, err = rl.processTraces(ctx, generateTenantTraces("b"))
+	assert.NoError(t, err)
+}
+
+func TestResourceAttributeCardinalityLimit(t *testing.T) {
+	const cardLimit = 3
+	rl, _ := newTestRateLimiter(t, &Config{ItemsPerSecond: 1, ResourceAttribute: "tenant.id", ResourceAttributeCardinalityLimit: cardLimit})
+	ctx := context.Background()
+
+	for i := 0; i < cardLimit; i++ {
+		_, err := rl.processTraces(ctx, generateTenantTraces(fmt.Sprint(i)))
+		assert.NoError(t, err)
+	}
+
+	// New tenants over the limit share the limits of the data without the resource attribute.
+	_, err := rl.processTraces(ctx, generateTenantTraces("over_1"))
+	assert.NoError(t, err)
+	_, err = rl.processTraces(ctx, generateTenantTraces("over_2"))
+	assert.ErrorIs(t, err, errDataRefused)
+	_, err = rl.processTraces(ctx, generateTenantTraces(""))
+	assert.ErrorIs(t, err, errDataRefused)
+	assert.Len(t, rl.limiters, cardLimit+1)
+}
+
+func TestRateLimiterObsReport(t *testing.T) {
+	id := component.NewID(typeStr)
+	tt, err := obsreporttest.SetupTelemetry(id)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	set := processortest.NewNopCreateSettings()
+	set.ID = id
+	set.TelemetrySettings = tt.TelemetrySettings
+	rl, err := newRateLimiter(set, &Config{ItemsPerSecond: 10})
+	require.NoError(t, err)
+
+	_, err = rl.processTraces(context.Background(), testdata.GenerateTraces(7))
+	assert.NoError(t, err)
+	_, err = rl.processTraces(context.Background(), testdata.GenerateTraces(5))
+	assert.Error(t, err)
+	require.NoError(t, tt.CheckProcessorTraces(7, 5, 0))
+}
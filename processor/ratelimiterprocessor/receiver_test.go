@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimiterprocessor
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/internal/testutil"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/collector/processor/processortest"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+// startRateLimitedReceiver starts an OTLP receiver sending the traces to the rate limiter, which
+// accepts 10 spans per second.
+func startRateLimitedReceiver(t *testing.T, grpcAddr string, httpAddr string) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ItemsPerSecond = 10
+	tp, err := factory.CreateTracesProcessor(context.Background(), processortest.NewNopCreateSettings(), cfg, new(consumertest.TracesSink))
+	require.NoError(t, err)
+
+	rcvFactory := otlpreceiver.NewFactory()
+	rcvCfg := rcvFactory.CreateDefaultConfig().(*otlpreceiver.Config)
+	rcvCfg.GRPC.NetAddr.Endpoint = grpcAddr
+	rcvCfg.HTTP.Endpoint = httpAddr
+	recv, err := rcvFactory.CreateTracesReceiver(context.Background(), receivertest.NewNopCreateSettings(), rcvCfg, tp)
+	require.NoError(t, err)
+
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, recv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, recv.Shutdown(context.Background()))
+		require.NoError(t, tp.Shutdown(context.Background()))
+	})
+}
+
+func TestReceiverHTTPRateLimited(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	startRateLimitedReceiver(t, testutil.GetAvailableLocalAddress(t), addr)
+
+	send := func(numSpans int) *http.Response {
+		pbBytes, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(testdata.GenerateTraces(numSpans))
+		require.NoError(t, err)
+		resp, err := http.Post("http://"+addr+"/v1/traces", "application/x-protobuf", bytes.NewReader(pbBytes))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	assert.Equal(t, http.StatusOK, send(10).StatusCode)
+	resp := send(10)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	// 10 spans are missing, refilled at 10 spans per second.
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+}
+
+func TestReceiverGRPCRateLimited(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	startRateLimitedReceiver(t, addr, testutil.GetAvailableLocalAddress(t))
+
+	cc, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+	client := ptraceotlp.NewGRPCClient(cc)
+
+	_, err = client.Export(context.Background(), ptraceotlp.NewExportRequestFromTraces(testdata.GenerateTraces(10)))
+	require.NoError(t, err)
+	_, err = client.Export(context.Background(), ptraceotlp.NewExportRequestFromTraces(testdata.GenerateTraces(10)))
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.InDelta(t, time.Second, retryInfo.RetryDelay.AsDuration(), float64(100*time.Millisecond))
+}
@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package samplingprocessor provides a processor for OpenTelemetry Service pipeline
+// that samples the data with the consistent probability sampling scheme.
+package samplingprocessor // import "go.opentelemetry.io/collector/processor/samplingprocessor"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Config defines configuration for the sampling processor.
+type Config struct {
+	// SamplingPercentage is the percentage of the traces, log records and, if SampleMetrics is
+	// set, metric time series kept by the processor.
+	SamplingPercentage float64 `mapstructure:"sampling_percentage"`
+
+	// SampleMetrics enables the sampling of the metrics. The metric data points are sampled by
+	// time series, so all the data points of a kept time series are kept. When not set the
+	// metrics are left unchanged.
+	SampleMetrics bool `mapstructure:"sample_metrics"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if !(cfg.SamplingPercentage >= 0 && cfg.SamplingPercentage <= 100) {
+		return errors.New("sampling_percentage must be between 0 and 100")
+	}
+	return nil
+}
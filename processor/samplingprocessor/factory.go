@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package samplingprocessor // import "go.opentelemetry.io/collector/processor/samplingprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// The value of "type" Attribute Key in configuration.
+	typeStr = "sampling"
+
+	defaultSamplingPercentage = 100
+)
+
+var processorCapabilities = consumer.Capabilities{MutatesData: true}
+
+// NewFactory returns a new factory for the Sampling processor.
+func NewFactory() processor.Factory {
+	return processor.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processor.WithTraces(createTracesProcessor, component.StabilityLevelDevelopment),
+		processor.WithMetrics(createMetricsProcessor, component.StabilityLevelDevelopment),
+		processor.WithLogs(createLogsProcessor, component.StabilityLevelDevelopment))
+}
+
+// createDefaultConfig creates the default configuration for processor. The default
+// configuration keeps all the data.
+func createDefaultConfig() component.Config {
+	return &Config{
+		SamplingPercentage: defaultSamplingPercentage,
+	}
+}
+
+func createTracesProcessor(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Traces,
+) (processor.Traces, error) {
+	sp := newSamplingProcessor(cfg.(*Config))
+	return processorhelper.NewTracesProcessor(ctx, set, cfg, nextConsumer,
+		sp.processTraces,
+		processorhelper.WithCapabilities(processorCapabilities))
+}
+
+func createMetricsProcessor(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (processor.Metrics, error) {
+	oCfg := cfg.(*Config)
+	if !oCfg.SampleMetrics {
+		// The metrics are left unchanged.
+		return processorhelper.NewMetricsProcessor(ctx, set, cfg, nextConsumer,
+			func(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) { return md, nil },
+			processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}))
+	}
+	sp := newSamplingProcessor(oCfg)
+	return processorhelper.NewMetricsProcessor(ctx, set, cfg, nextConsumer,
+		sp.processMetrics,
+		processorhelper.WithCapabilities(processorCapabilities))
+}
+
+func createLogsProcessor(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Logs,
+) (processor.Logs, error) {
+	sp := newSamplingProcessor(cfg.(*Config))
+	return processorhelper.NewLogsProcessor(ctx, set, cfg, nextConsumer,
+		sp.processLogs,
+		processorhelper.WithCapabilities(processorCapabilities))
+}
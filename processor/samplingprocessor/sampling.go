@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package samplingprocessor // import "go.opentelemetry.io/collector/processor/samplingprocessor"
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// The consistent probability sampling decisions compare a 56 bits randomness value to a rejection
+// threshold, see https://opentelemetry.io/docs/specs/otel/trace/tracestate-probability-sampling/.
+const (
+	// randomnessBits is the number of bits of the randomness values.
+	randomnessBits = 56
+	// maxThreshold rejects all the randomness values.
+	maxThreshold   threshold = 1 << randomnessBits
+	randomnessMask           = uint64(maxThreshold) - 1
+	// hexDigits is the number of hexadecimal digits of the randomness values and of the thresholds.
+	hexDigits = randomnessBits / 4
+)
+
+var errInvalidHex = errors.New("invalid hexadecimal value")
+
+// threshold is the rejection threshold: the data with a randomness value less than the threshold is dropped.
+type threshold uint64
+
+// newThreshold returns the threshold keeping the given percentage of the randomness values.
+func newThreshold(percentage float64) threshold {
+	if percentage >= 100 {
+		return 0
+	}
+	if percentage <= 0 {
+		return maxThreshold
+	}
+	t := threshold(math.Round((1 - percentage/100) * float64(maxThreshold)))
+	if t >= maxThreshold {
+		// Keep the smallest percentage of the randomness values rather than none.
+		t = maxThreshold - 1
+	}
+	return t
+}
+
+// String returns the encoding of the threshold in the tracestate: the 14 hexadecimal digits
+// without the trailing zeros.
+func (t threshold) String() string {
+	if t == 0 {
+		return "0"
+	}
+	s := strconv.FormatUint(uint64(t), 16)
+	s = strings.Repeat("0", hexDigits-len(s)) + s
+	return strings.TrimRight(s, "0")
+}
+
+// parseThreshold parses a threshold encoded in the tracestate, with up to 14 hexadecimal digits.
+func parseThreshold(s string) (threshold, error) {
+	if len(s) == 0 || len(s) > hexDigits {
+		return 0, errInvalidHex
+	}
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, errInvalidHex
+	}
+	return threshold(v << (4 * (hexDigits - len(s)))), nil
+}
+
+// parseRandomness parses an explicit randomness value, with exactly 14 hexadecimal digits.
+func parseRandomness(s string) (uint64, error) {
+	if len(s) != hexDigits {
+		return 0, errInvalidHex
+	}
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, errInvalidHex
+	}
+	return v, nil
+}
+
+// traceIDRandomness returns the randomness value of a trace ID, its 56 least significant bits,
+// which are random with the W3C Trace Context Level 2.
+func traceIDRandomness(id pcommon.TraceID) uint64 {
+	return binary.BigEndian.Uint64(id[8:]) & randomnessMask
+}
+
+// traceState is a W3C tracestate with its OpenTelemetry entry "ot" split into its fields,
+// see https://opentelemetry.io/docs/specs/otel/trace/tracestate-handling/.
+type traceState struct {
+	// ot are the fields of the OpenTelemetry entry, as key:value.
+	ot []string
+	// others are the entries of the other vendors.
+	others []string
+}
+
+func parseTraceState(raw string) traceState {
+	var ts traceState
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+		case strings.HasPrefix(entry, "ot="):
+			for _, field := range strings.Split(entry[len("ot="):], ";") {
+				if field != "" {
+					ts.ot = append(ts.ot, field)
+				}
+			}
+		default:
+			ts.others = append(ts.others, entry)
+		}
+	}
+	return ts
+}
+
+// get returns the value of a field of the OpenTelemetry entry.
+func (ts *traceState) get(key string) (string, bool) {
+	for _, field := range ts.ot {
+		if k, v, ok := strings.Cut(field, ":"); ok && k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// set sets the value of a field of the OpenTelemetry entry.
+func (ts *traceState) set(key string, value string) {
+	for i, field := range ts.ot {
+		if k, _, ok := strings.Cut(field, ":"); ok && k == key {
+			ts.ot[i] = key + ":" + value
+			return
+		}
+	}
+	ts.ot = append(ts.ot, key+":"+value)
+}
+
+// String returns the W3C tracestate. The OpenTelemetry entry comes first, as it is the last modified.
+func (ts *traceState) String() string {
+	entries := ts.others
+	if len(ts.ot) > 0 {
+		entries = append([]string{"ot=" + strings.Join(ts.ot, ";")}, ts.others...)
+	}
+	return strings.Join(entries, ",")
+}
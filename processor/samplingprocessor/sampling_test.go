@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package samplingprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestThreshold(t *testing.T) {
+	tests := []struct {
+		percentage float64
+		threshold  threshold
+		encoded    string
+	}{
+		{percentage: 100, threshold: 0, encoded: "0"},
+		{percentage: 50, threshold: 1 << 55, encoded: "8"},
+		{percentage: 25, threshold: 0xc0000000000000, encoded: "c"},
+		{percentage: 12.5, threshold: 0xe0000000000000, encoded: "e"},
+		{percentage: 75, threshold: 0x40000000000000, encoded: "4"},
+		{percentage: 100.0 / 4096, threshold: 0xfff00000000000, encoded: "fff"},
+	}
+	for _, tt := range tests {
+		th := newThreshold(tt.percentage)
+		assert.Equal(t, tt.threshold, th, tt.percentage)
+		assert.Equal(t, tt.encoded, th.String(), tt.percentage)
+		parsed, err := parseThreshold(tt.encoded)
+		require.NoError(t, err)
+		assert.Equal(t, th, parsed)
+	}
+
+	assert.Equal(t, maxThreshold, newThreshold(0))
+	assert.Equal(t, maxThreshold-1, newThreshold(1e-20))
+
+	for _, s := range []string{"", "g", "123456789abcdef"} {
+		_, err := parseThreshold(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParseRandomness(t *testing.T) {
+	r, err := parseRandomness("0123456789abcd")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0x0123456789abcd), r)
+	for _, s := range []string{"", "0123456789abc", "0123456789abcg"} {
+		_, err = parseRandomness(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestTraceIDRandomness(t *testing.T) {
+	id := pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	assert.Equal(t, uint64(0x0a0b0c0d0e0f10), traceIDRandomness(id))
+}
+
+func TestTraceState(t *testing.T) {
+	ts := parseTraceState("vendor=value, ot=rv:0123456789abcd;th:8 ,other=1")
+	assert.Equal(t, []string{"rv:0123456789abcd", "th:8"}, ts.ot)
+	assert.Equal(t, []string{"vendor=value", "other=1"}, ts.others)
+	rv, ok := ts.get("rv")
+	assert.True(t, ok)
+	assert.Equal(t, "0123456789abcd", rv)
+	_, ok = ts.get("p")
+	assert.False(t, ok)
+
+	ts.set("th", "c")
+	ts.set("p", "1")
+	assert.Equal(t, "ot=rv:0123456789abcd;th:c;p:1,vendor=value,other=1", ts.String())
+
+	ts = parseTraceState("")
+	assert.Equal(t, "", ts.String())
+	ts.set("th", "0")
+	assert.Equal(t, "ot=th:0", ts.String())
+}
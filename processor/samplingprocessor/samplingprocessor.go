@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package samplingprocessor // import "go.opentelemetry.io/collector/processor/samplingprocessor"
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type samplingProcessor struct {
+	threshold threshold
+
+	// random returns the randomness of the log records without trace ID, overridable by tests.
+	random func() uint64
+}
+
+func newSamplingProcessor(cfg *Config) *samplingProcessor {
+	return &samplingProcessor{
+		threshold: newThreshold(cfg.SamplingPercentage),
+		random:    rand.Uint64,
+	}
+}
+
+// keepSpan returns whether the span is sampled, and records the threshold of the decision in its tracestate.
+// The randomness of the span is the explicit one of its tracestate, if any, or else its trace ID. A threshold
+// already in the tracestate is kept if it is higher, so the spans sampled upstream keep their adjusted count.
+func (sp *samplingProcessor) keepSpan(span ptrace.Span) bool {
+	ts := parseTraceState(span.TraceState().AsRaw())
+	randomness := traceIDRandomness(span.TraceID())
+	if rv, ok := ts.get("rv"); ok {
+		if r, err := parseRandomness(rv); err == nil {
+			randomness = r
+		}
+	}
+	th := sp.threshold
+	existing, hasThreshold := ts.get("th")
+	if hasThreshold {
+		if t, err := parseThreshold(existing); err == nil && t > th {
+			th = t
+		}
+	}
+	if randomness < uint64(th) {
+		return false
+	}
+	if encoded := th.String(); !hasThreshold || existing != encoded {
+		ts.set("th", encoded)
+		span.TraceState().FromRaw(ts.String())
+	}
+	return true
+}
+
+// keepLogRecord returns whether the log record is sampled, according to its trace ID when set, so the log
+// records are sampled consistently with the spans of their trace.
+func (sp *samplingProcessor) keepLogRecord(lr plog.LogRecord) bool {
+	if lr.TraceID().IsEmpty() {
+		return sp.random()&randomnessMask >= uint64(sp.threshold)
+	}
+	return traceIDRandomness(lr.TraceID()) >= uint64(sp.threshold)
+}
+
+func (sp *samplingProcessor) processTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	if sp.threshold == 0 {
+		return td, nil
+	}
+	td.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		rs.ScopeSpans().RemoveIf(func(ils ptrace.ScopeSpans) bool {
+			ils.Spans().RemoveIf(func(span ptrace.Span) bool {
+				return !sp.keepSpan(span)
+			})
+			return ils.Spans().Len() == 0
+		})
+		return rs.ScopeSpans().Len() == 0
+	})
+	if td.ResourceSpans().Len() == 0 {
+		return td, processorhelper.ErrSkipProcessingData
+	}
+	return td, nil
+}
+
+func (sp *samplingProcessor) processLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
+	if sp.threshold == 0 {
+		return ld, nil
+	}
+	ld.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		rl.ScopeLogs().RemoveIf(func(ill plog.ScopeLogs) bool {
+			ill.LogRecords().RemoveIf(func(lr plog.LogRecord) bool {
+				return !sp.keepLogRecord(lr)
+			})
+			return ill.LogRecords().Len() == 0
+		})
+		return rl.ScopeLogs().Len() == 0
+	})
+	if ld.ResourceLogs().Len() == 0 {
+		return ld, processorhelper.ErrSkipProcessingData
+	}
+	return ld, nil
+}
+
+// processMetrics samples the metrics by time series: the randomness of a data point is the hash of
+// its resource, scope, metric name and attributes, so the data points of a time series are all kept
+// or all dropped.
+func (sp *samplingProcessor) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	if sp.threshold == 0 {
+		return md, nil
+	}
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		resource := rm.Resource().Attributes().AsRaw()
+		rm.ScopeMetrics().RemoveIf(func(ilm pmetric.ScopeMetrics) bool {
+			scope := ilm.Scope()
+			ilm.Metrics().RemoveIf(func(metric pmetric.Metric) bool {
+				keep := func(attrs pcommon.Map) bool {
+					return seriesRandomness(resource, scope.Name(), scope.Version(), metric.Name(), attrs) >= uint64(sp.threshold)
+				}
+				return removeDataPoints(metric, keep) == 0
+			})
+			return ilm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	if md.ResourceMetrics().Len() == 0 {
+		return md, processorhelper.ErrSkipProcessingData
+	}
+	return md, nil
+}
+
+// seriesRandomness returns the randomness value of a time series, from the hash of its identity.
+func seriesRandomness(resource map[string]any, scopeName, scopeVersion, metricName string, attrs pcommon.Map) uint64 {
+	h := fnv.New64a()
+	// The keys of the maps are printed in sorted order.
+	_, _ = fmt.Fprint(h, resource, scopeName, scopeVersion, metricName, attrs.AsRaw())
+	return h.Sum64() & randomnessMask
+}
+
+// removeDataPoints removes the data points whose attributes are not kept, and returns the number of data
+// points left.
+func removeDataPoints(metric pmetric.Metric, keep func(pcommon.Map) bool) int {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return !keep(dp.Attributes()) })
+		return dps.Len()
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return !keep(dp.Attributes()) })
+		return dps.Len()
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		dps.RemoveIf(func(dp pmetric.HistogramDataPoint) bool { return !keep(dp.Attributes()) })
+		return dps.Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		dps.RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool { return !keep(dp.Attributes()) })
+		return dps.Len()
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		dps.RemoveIf(func(dp pmetric.SummaryDataPoint) bool { return !keep(dp.Attributes()) })
+		return dps.Len()
+	}
+	// The metrics without data points are left unchanged.
+	return 1
+}
@@ -0,0 +1,233 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package samplingprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+// traceID returns a trace ID with the given randomness value.
+func traceID(randomness uint64) pcommon.TraceID {
+	return pcommon.TraceID([16]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		byte(randomness >> 48), byte(randomness >> 40), byte(randomness >> 32),
+		byte(randomness >> 24), byte(randomness >> 16), byte(randomness >> 8), byte(randomness),
+	})
+}
+
+func newTraces(spans ...func(ptrace.Span)) ptrace.Traces {
+	td := ptrace.NewTraces()
+	ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for _, f := range spans {
+		f(ss.AppendEmpty())
+	}
+	return td
+}
+
+func TestProcessTraces(t *testing.T) {
+	sp := newSamplingProcessor(&Config{SamplingPercentage: 25})
+	td, err := sp.processTraces(context.Background(), newTraces(
+		func(span ptrace.Span) {
+			span.SetName("dropped")
+			span.SetTraceID(traceID(0xbfffffffffffff))
+		},
+		func(span ptrace.Span) {
+			span.SetName("kept")
+			span.SetTraceID(traceID(0xc0000000000000))
+			span.TraceState().FromRaw("vendor=value")
+		},
+		func(span ptrace.Span) {
+			span.SetName("explicit_randomness")
+			span.SetTraceID(traceID(0))
+			span.TraceState().FromRaw("ot=rv:f0000000000000")
+		},
+		func(span ptrace.Span) {
+			span.SetName("higher_threshold")
+			span.SetTraceID(traceID(0xf0000000000000))
+			span.TraceState().FromRaw("ot=th:e")
+		},
+		func(span ptrace.Span) {
+			span.SetName("lower_threshold")
+			span.SetTraceID(traceID(0xf0000000000000))
+			span.TraceState().FromRaw("ot=th:8")
+		},
+		func(span ptrace.Span) {
+			span.SetName("dropped_upstream_threshold")
+			span.SetTraceID(traceID(0xd0000000000000))
+			span.TraceState().FromRaw("ot=th:e")
+		},
+	))
+	require.NoError(t, err)
+
+	spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	traceStates := map[string]string{}
+	for i := 0; i < spans.Len(); i++ {
+		traceStates[spans.At(i).Name()] = spans.At(i).TraceState().AsRaw()
+	}
+	assert.Equal(t, map[string]string{
+		"kept":                "ot=th:c,vendor=value",
+		"explicit_randomness": "ot=rv:f0000000000000;th:c",
+		"higher_threshold":    "ot=th:e",
+		"lower_threshold":     "ot=th:c",
+	}, traceStates)
+}
+
+func TestProcessTracesConsistent(t *testing.T) {
+	// The spans of a trace are sampled consistently, and the spans kept by a lower percentage
+	// are also kept by a higher one.
+	var ids []pcommon.TraceID
+	for i := uint64(0); i < 1000; i++ {
+		ids = append(ids, traceID(i*(randomnessMask/1000)))
+	}
+	kept := func(percentage float64) map[pcommon.TraceID]int {
+		var spans []func(ptrace.Span)
+		for _, id := range ids {
+			id := id
+			for i := 0; i < 2; i++ {
+				spans = append(spans, func(span ptrace.Span) { span.SetTraceID(id) })
+			}
+		}
+		td, _ := newSamplingProcessor(&Config{SamplingPercentage: percentage}).processTraces(context.Background(), newTraces(spans...))
+		counts := map[pcommon.TraceID]int{}
+		if td.ResourceSpans().Len() == 0 {
+			return counts
+		}
+		ss := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		for i := 0; i < ss.Len(); i++ {
+			counts[ss.At(i).TraceID()]++
+		}
+		return counts
+	}
+
+	low := kept(10)
+	high := kept(50)
+	assert.InDelta(t, 100, len(low), 1)
+	assert.InDelta(t, 500, len(high), 1)
+	for id, count := range low {
+		assert.Equal(t, 2, count)
+		assert.Equal(t, 2, high[id])
+	}
+}
+
+func TestProcessTracesAllDropped(t *testing.T) {
+	sp := newSamplingProcessor(&Config{SamplingPercentage: 0})
+	td, err := sp.processTraces(context.Background(), newTraces(func(span ptrace.Span) {
+		span.SetTraceID(traceID(randomnessMask))
+	}))
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+	assert.Equal(t, 0, td.SpanCount())
+}
+
+func TestProcessTracesAllKept(t *testing.T) {
+	sp := newSamplingProcessor(&Config{SamplingPercentage: 100})
+	td, err := sp.processTraces(context.Background(), newTraces(func(span ptrace.Span) {
+		span.SetTraceID(traceID(0))
+	}))
+	require.NoError(t, err)
+	require.Equal(t, 1, td.SpanCount())
+	// The tracestate is left unchanged.
+	assert.Equal(t, "", td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceState().AsRaw())
+}
+
+func TestProcessLogs(t *testing.T) {
+	sp := newSamplingProcessor(&Config{SamplingPercentage: 50})
+	ld := plog.NewLogs()
+	lrs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	lr := lrs.AppendEmpty()
+	lr.Body().SetStr("dropped")
+	lr.SetTraceID(traceID(0x7fffffffffffff))
+	lr = lrs.AppendEmpty()
+	lr.Body().SetStr("kept")
+	lr.SetTraceID(traceID(0x80000000000000))
+	lr = lrs.AppendEmpty()
+	lr.Body().SetStr("random_dropped")
+	lr = lrs.AppendEmpty()
+	lr.Body().SetStr("random_kept")
+
+	randoms := []uint64{0, randomnessMask}
+	sp.random = func() uint64 {
+		r := randoms[0]
+		randoms = randoms[1:]
+		return r
+	}
+	ld, err := sp.processLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	lrs = ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, lrs.Len())
+	assert.Equal(t, "kept", lrs.At(0).Body().Str())
+	assert.Equal(t, "random_kept", lrs.At(1).Body().Str())
+
+	ld = plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().SetTraceID(traceID(0))
+	_, err = sp.processLogs(context.Background(), ld)
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+}
+
+func TestProcessMetrics(t *testing.T) {
+	newMetrics := func() pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", "test")
+		ms := rm.ScopeMetrics().AppendEmpty().Metrics()
+		gauge := ms.AppendEmpty()
+		gauge.SetName("gauge")
+		gauge.SetEmptyGauge()
+		sum := ms.AppendEmpty()
+		sum.SetName("sum")
+		sum.SetEmptySum()
+		hist := ms.AppendEmpty()
+		hist.SetName("histogram")
+		hist.SetEmptyHistogram()
+		for i := 0; i < 100; i++ {
+			for j := 0; j < 2; j++ {
+				dp := gauge.Gauge().DataPoints().AppendEmpty()
+				dp.Attributes().PutInt("series", int64(i))
+				dp.SetIntValue(int64(j))
+				sum.Sum().DataPoints().AppendEmpty().Attributes().PutInt("series", int64(i))
+				hist.Histogram().DataPoints().AppendEmpty().Attributes().PutInt("series", int64(i))
+			}
+		}
+		return md
+	}
+
+	sp := newSamplingProcessor(&Config{SamplingPercentage: 50, SampleMetrics: true})
+	md, err := sp.processMetrics(context.Background(), newMetrics())
+	require.NoError(t, err)
+	assert.Less(t, md.DataPointCount(), 600)
+	assert.Greater(t, md.DataPointCount(), 0)
+
+	// The data points of a time series are all kept or all dropped, and the result is the same
+	// for the same data.
+	again, err := sp.processMetrics(context.Background(), newMetrics())
+	require.NoError(t, err)
+	assert.Equal(t, md, again)
+	ms := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		series := map[int64]int{}
+		removeDataPoints(ms.At(i), func(attrs pcommon.Map) bool {
+			v, _ := attrs.Get("series")
+			series[v.Int()]++
+			return true
+		})
+		for _, count := range series {
+			assert.Equal(t, 2, count)
+		}
+	}
+
+	sp = newSamplingProcessor(&Config{SamplingPercentage: 0, SampleMetrics: true})
+	md, err = sp.processMetrics(context.Background(), newMetrics())
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+	assert.Equal(t, 0, md.DataPointCount())
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schemaprocessor provides a processor for OpenTelemetry Service pipeline
+// that translates the telemetry between the versions of its schema.
+package schemaprocessor // import "go.opentelemetry.io/collector/processor/schemaprocessor"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Config defines configuration for the schema processor.
+type Config struct {
+	// Targets are the schema URLs the telemetry is translated to, for instance
+	// https://opentelemetry.io/schemas/1.21.0. The telemetry with a schema URL of the same
+	// schema family, that is the same URL without the version, is translated to the version
+	// of the target. At most one target can be set per schema family.
+	Targets []string `mapstructure:"targets"`
+
+	// SchemaFiles are the paths of local schema files. The schema files of the targets that
+	// are not provided are downloaded from their URL when the processor is started. A schema
+	// file newer than the target is needed to translate telemetry from the newer versions.
+	SchemaFiles []string `mapstructure:"schema_files"`
+
+	// DownloadTimeout is the maximum time to download a schema file.
+	DownloadTimeout time.Duration `mapstructure:"download_timeout"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	if len(cfg.Targets) == 0 {
+		return errors.New("at least one target must be set")
+	}
+	families := map[string]bool{}
+	for _, target := range cfg.Targets {
+		id, err := parseSchemaURL(target)
+		if err != nil {
+			return fmt.Errorf("invalid target: %w", err)
+		}
+		if families[id.family] {
+			return fmt.Errorf("duplicate target for schema family %q", id.family)
+		}
+		families[id.family] = true
+	}
+	if cfg.DownloadTimeout <= 0 {
+		return errors.New("download_timeout must be greater than 0")
+	}
+	return nil
+}
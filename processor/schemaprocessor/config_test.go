@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaprocessor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+)
+
+func TestUnmarshalDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, component.UnmarshalConfig(confmap.New(), cfg))
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg)
+}
+
+func TestUnmarshalConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, component.UnmarshalConfig(cm, cfg))
+	assert.Equal(t,
+		&Config{
+			Targets:         []string{"https://example.com/schemas/1.1.0"},
+			SchemaFiles:     []string{"testdata/schema.yaml"},
+			DownloadTimeout: 5 * time.Second,
+		}, cfg)
+	assert.NoError(t, component.ValidateConfig(cfg))
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{
+			name: "no_target",
+			cfg:  &Config{DownloadTimeout: time.Second},
+		},
+		{
+			name: "invalid_target",
+			cfg:  &Config{Targets: []string{"https://example.com/schemas/latest"}, DownloadTimeout: time.Second},
+		},
+		{
+			name: "duplicate_family",
+			cfg: &Config{
+				Targets:         []string{"https://example.com/schemas/1.0.0", "https://example.com/schemas/1.1.0"},
+				DownloadTimeout: time.Second,
+			},
+		},
+		{
+			name: "no_download_timeout",
+			cfg:  &Config{Targets: []string{"https://example.com/schemas/1.0.0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, component.ValidateConfig(tt.cfg))
+		})
+	}
+
+	assert.NoError(t, component.ValidateConfig(&Config{
+		Targets:         []string{"https://example.com/schemas/1.0.0", "https://opentelemetry.io/schemas/1.21.0"},
+		DownloadTimeout: time.Second,
+	}))
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaprocessor // import "go.opentelemetry.io/collector/processor/schemaprocessor"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// The value of "type" Attribute Key in configuration.
+	typeStr = "schema"
+
+	defaultDownloadTimeout = 10 * time.Second
+)
+
+var processorCapabilities = consumer.Capabilities{MutatesData: true}
+
+// NewFactory returns a new factory for the Schema processor.
+func NewFactory() processor.Factory {
+	return processor.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processor.WithTraces(createTracesProcessor, component.StabilityLevelDevelopment),
+		processor.WithMetrics(createMetricsProcessor, component.StabilityLevelDevelopment),
+		processor.WithLogs(createLogsProcessor, component.StabilityLevelDevelopment))
+}
+
+// createDefaultConfig creates the default configuration for processor. Notice
+// that the default configuration is expected to fail for this processor.
+func createDefaultConfig() component.Config {
+	return &Config{
+		DownloadTimeout: defaultDownloadTimeout,
+	}
+}
+
+func createTracesProcessor(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Traces,
+) (processor.Traces, error) {
+	sp := newSchemaProcessor(set, cfg.(*Config))
+	return processorhelper.NewTracesProcessor(ctx, set, cfg, nextConsumer,
+		sp.processTraces,
+		processorhelper.WithStart(sp.start),
+		processorhelper.WithCapabilities(processorCapabilities))
+}
+
+func createMetricsProcessor(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (processor.Metrics, error) {
+	sp := newSchemaProcessor(set, cfg.(*Config))
+	return processorhelper.NewMetricsProcessor(ctx, set, cfg, nextConsumer,
+		sp.processMetrics,
+		processorhelper.WithStart(sp.start),
+		processorhelper.WithCapabilities(processorCapabilities))
+}
+
+func createLogsProcessor(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Logs,
+) (processor.Logs, error) {
+	sp := newSchemaProcessor(set, cfg.(*Config))
+	return processorhelper.NewLogsProcessor(ctx, set, cfg, nextConsumer,
+		sp.processLogs,
+		processorhelper.WithStart(sp.start),
+		processorhelper.WithCapabilities(processorCapabilities))
+}
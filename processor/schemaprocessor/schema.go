@@ -0,0 +1,235 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaprocessor // import "go.opentelemetry.io/collector/processor/schemaprocessor"
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// version is a version of a schema, major.minor.patch.
+type version [3]int
+
+func parseVersion(s string) (version, error) {
+	var v version
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid version %q, it must be major.minor.patch", s)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q, it must be major.minor.patch", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+func (v version) less(o version) bool {
+	for i := range v {
+		if v[i] != o[i] {
+			return v[i] < o[i]
+		}
+	}
+	return false
+}
+
+func (v version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// schemaID identifies a version of a schema family by its schema URL.
+type schemaID struct {
+	// family is the schema URL without the version, for instance https://opentelemetry.io/schemas/.
+	family  string
+	version version
+}
+
+// parseSchemaURL splits a schema URL into its family and version, the last segment of its path.
+func parseSchemaURL(url string) (schemaID, error) {
+	i := strings.LastIndex(url, "/")
+	if i < 0 || !strings.Contains(url, "://") {
+		return schemaID{}, fmt.Errorf("invalid schema URL %q", url)
+	}
+	v, err := parseVersion(url[i+1:])
+	if err != nil {
+		return schemaID{}, fmt.Errorf("invalid schema URL %q: %w", url, err)
+	}
+	return schemaID{family: url[:i+1], version: v}, nil
+}
+
+// The file format of the schema files, see https://opentelemetry.io/docs/specs/otel/schemas/file_format_v1.1.0/.
+// Only the renames of attributes, metrics and span events are supported, the other changes are ignored.
+type schemaFile struct {
+	FileFormat string                       `yaml:"file_format"`
+	SchemaURL  string                       `yaml:"schema_url"`
+	Versions   map[string]schemaFileVersion `yaml:"versions"`
+}
+
+type schemaFileVersion struct {
+	All        schemaFileSection `yaml:"all"`
+	Resources  schemaFileSection `yaml:"resources"`
+	Spans      schemaFileSection `yaml:"spans"`
+	SpanEvents schemaFileSection `yaml:"span_events"`
+	Metrics    schemaFileSection `yaml:"metrics"`
+	Logs       schemaFileSection `yaml:"logs"`
+}
+
+type schemaFileSection struct {
+	Changes []schemaFileChange `yaml:"changes"`
+}
+
+type schemaFileChange struct {
+	RenameAttributes *struct {
+		AttributeMap   map[string]string `yaml:"attribute_map"`
+		ApplyToSpans   []string          `yaml:"apply_to_spans"`
+		ApplyToEvents  []string          `yaml:"apply_to_events"`
+		ApplyToMetrics []string          `yaml:"apply_to_metrics"`
+	} `yaml:"rename_attributes"`
+	RenameMetrics map[string]string `yaml:"rename_metrics"`
+	RenameEvents  *struct {
+		NameMap map[string]string `yaml:"name_map"`
+	} `yaml:"rename_events"`
+}
+
+// change is a change of a schema version, from the previous version.
+type change struct {
+	// attributes renames the attributes, from their old to their new key.
+	attributes map[string]string
+	// names renames the metrics or the span events, from their old to their new name.
+	names map[string]string
+	// applyTo restricts the renames of the attributes to the spans or metrics with these names.
+	applyTo map[string]bool
+	// applyToEvents restricts the renames of the span event attributes to the events with these names.
+	applyToEvents map[string]bool
+}
+
+// inverse returns the change translating the data back to the previous version.
+func (c change) inverse() change {
+	return change{
+		attributes:    inverseMap(c.attributes),
+		names:         inverseMap(c.names),
+		applyTo:       c.applyTo,
+		applyToEvents: c.applyToEvents,
+	}
+}
+
+func inverseMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	inv := make(map[string]string, len(m))
+	for k, v := range m {
+		inv[v] = k
+	}
+	return inv
+}
+
+// versionChanges are the changes of a schema version, by kind of telemetry, in the order they apply.
+type versionChanges struct {
+	version    version
+	all        []change
+	resources  []change
+	spans      []change
+	spanEvents []change
+	metrics    []change
+	logs       []change
+}
+
+// inverse returns the changes translating the data back to the previous version, in the order they apply.
+func (vc *versionChanges) inverse() *versionChanges {
+	return &versionChanges{
+		version:    vc.version,
+		all:        inverseChanges(vc.all),
+		resources:  inverseChanges(vc.resources),
+		spans:      inverseChanges(vc.spans),
+		spanEvents: inverseChanges(vc.spanEvents),
+		metrics:    inverseChanges(vc.metrics),
+		logs:       inverseChanges(vc.logs),
+	}
+}
+
+func inverseChanges(changes []change) []change {
+	inv := make([]change, len(changes))
+	for i, c := range changes {
+		inv[len(changes)-1-i] = c.inverse()
+	}
+	return inv
+}
+
+// parseSchemaFile parses a schema file into its ID and its versions, sorted in ascending order.
+func parseSchemaFile(data []byte) (schemaID, []*versionChanges, error) {
+	var sf schemaFile
+	if err := yaml.Unmarshal(data, &sf); err != nil {
+		return schemaID{}, nil, fmt.Errorf("invalid schema file: %w", err)
+	}
+	format, err := parseVersion(sf.FileFormat)
+	if err != nil {
+		return schemaID{}, nil, fmt.Errorf("invalid file_format: %w", err)
+	}
+	if format[0] != 1 {
+		return schemaID{}, nil, fmt.Errorf("unsupported file_format %q", sf.FileFormat)
+	}
+	id, err := parseSchemaURL(sf.SchemaURL)
+	if err != nil {
+		return schemaID{}, nil, err
+	}
+
+	versions := make([]*versionChanges, 0, len(sf.Versions))
+	for s, fv := range sf.Versions {
+		v, err := parseVersion(s)
+		if err != nil {
+			return schemaID{}, nil, err
+		}
+		if id.version.less(v) {
+			return schemaID{}, nil, fmt.Errorf("version %s is newer than the schema URL %q", v, sf.SchemaURL)
+		}
+		versions = append(versions, &versionChanges{
+			version:    v,
+			all:        compileChanges(fv.All.Changes),
+			resources:  compileChanges(fv.Resources.Changes),
+			spans:      compileChanges(fv.Spans.Changes),
+			spanEvents: compileChanges(fv.SpanEvents.Changes),
+			metrics:    compileChanges(fv.Metrics.Changes),
+			logs:       compileChanges(fv.Logs.Changes),
+		})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].version.less(versions[j].version) })
+	return id, versions, nil
+}
+
+func compileChanges(fcs []schemaFileChange) []change {
+	var changes []change
+	for _, fc := range fcs {
+		switch {
+		case fc.RenameAttributes != nil:
+			c := change{attributes: fc.RenameAttributes.AttributeMap}
+			if names := append(fc.RenameAttributes.ApplyToSpans, fc.RenameAttributes.ApplyToMetrics...); len(names) > 0 {
+				c.applyTo = toSet(names)
+			}
+			if len(fc.RenameAttributes.ApplyToEvents) > 0 {
+				c.applyToEvents = toSet(fc.RenameAttributes.ApplyToEvents)
+			}
+			changes = append(changes, c)
+		case fc.RenameMetrics != nil:
+			changes = append(changes, change{names: fc.RenameMetrics})
+		case fc.RenameEvents != nil:
+			changes = append(changes, change{names: fc.RenameEvents.NameMap})
+		}
+	}
+	return changes
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
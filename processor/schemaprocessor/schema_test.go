@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchemaURL(t *testing.T) {
+	id, err := parseSchemaURL("https://opentelemetry.io/schemas/1.21.0")
+	require.NoError(t, err)
+	assert.Equal(t, schemaID{family: "https://opentelemetry.io/schemas/", version: version{1, 21, 0}}, id)
+	assert.Equal(t, "1.21.0", id.version.String())
+
+	for _, url := range []string{"", "1.0.0", "https://example.com/schemas/", "https://example.com/schemas/1.0", "https://example.com/schemas/1.0.-1"} {
+		_, err = parseSchemaURL(url)
+		assert.Error(t, err, url)
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	assert.True(t, version{1, 2, 3}.less(version{1, 10, 0}))
+	assert.True(t, version{1, 2, 3}.less(version{2, 0, 0}))
+	assert.False(t, version{1, 2, 3}.less(version{1, 2, 3}))
+	assert.False(t, version{1, 2, 4}.less(version{1, 2, 3}))
+}
+
+func TestParseSchemaFile(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+	id, versions, err := parseSchemaFile(data)
+	require.NoError(t, err)
+	assert.Equal(t, schemaID{family: "https://example.com/schemas/", version: version{1, 2, 0}}, id)
+	require.Len(t, versions, 3)
+	assert.Equal(t, version{1, 0, 0}, versions[0].version)
+	assert.Equal(t, version{1, 1, 0}, versions[1].version)
+	assert.Equal(t, version{1, 2, 0}, versions[2].version)
+
+	assert.Equal(t, []change{{attributes: map[string]string{"host": "host.name"}}}, versions[1].resources)
+	assert.Equal(t, []change{
+		{names: map[string]string{"http.duration": "http.server.duration"}},
+		{attributes: map[string]string{"status": "http.status_code"}, applyTo: map[string]bool{"http.server.duration": true}},
+	}, versions[2].metrics)
+
+	inv := versions[2].inverse()
+	assert.Equal(t, []change{
+		{attributes: map[string]string{"http.status_code": "status"}, applyTo: map[string]bool{"http.server.duration": true}},
+		{names: map[string]string{"http.server.duration": "http.duration"}},
+	}, inv.metrics)
+}
+
+func TestParseSchemaFileInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "invalid_yaml",
+			data: "file_format: [",
+		},
+		{
+			name: "unsupported_file_format",
+			data: "file_format: 2.0.0\nschema_url: https://example.com/schemas/1.0.0",
+		},
+		{
+			name: "invalid_schema_url",
+			data: "file_format: 1.1.0\nschema_url: https://example.com/schemas/latest",
+		},
+		{
+			name: "invalid_version",
+			data: "file_format: 1.1.0\nschema_url: https://example.com/schemas/1.0.0\nversions:\n  latest:",
+		},
+		{
+			name: "newer_version",
+			data: "file_format: 1.1.0\nschema_url: https://example.com/schemas/1.0.0\nversions:\n  1.1.0:",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseSchemaFile([]byte(tt.data))
+			assert.Error(t, err)
+		})
+	}
+}
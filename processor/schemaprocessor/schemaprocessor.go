@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaprocessor // import "go.opentelemetry.io/collector/processor/schemaprocessor"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+)
+
+type schemaProcessor struct {
+	cfg    *Config
+	logger *zap.Logger
+	client *http.Client
+
+	// translator is set when the processor is started.
+	translator *translator
+}
+
+func newSchemaProcessor(set processor.CreateSettings, cfg *Config) *schemaProcessor {
+	return &schemaProcessor{
+		cfg:    cfg,
+		logger: set.Logger,
+		client: &http.Client{Timeout: cfg.DownloadTimeout},
+	}
+}
+
+// start loads the local schema files, then downloads the schema files of the targets that are missing.
+func (sp *schemaProcessor) start(ctx context.Context, _ component.Host) error {
+	t, err := newTranslator(sp.cfg.Targets)
+	if err != nil {
+		return err
+	}
+	for _, path := range sp.cfg.SchemaFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read the schema file: %w", err)
+		}
+		id, versions, err := parseSchemaFile(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse the schema file %q: %w", path, err)
+		}
+		t.addSchema(id, versions)
+	}
+	for _, target := range t.missing() {
+		data, err := sp.download(ctx, target)
+		if err != nil {
+			return fmt.Errorf("failed to download the schema file %q: %w", target, err)
+		}
+		id, versions, err := parseSchemaFile(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse the schema file %q: %w", target, err)
+		}
+		if id.version != t.families[id.family].target {
+			return fmt.Errorf("the schema file downloaded from %q has the schema URL %q", target, id.family+id.version.String())
+		}
+		sp.logger.Debug("Downloaded the schema file", zap.String("schema_url", target))
+		t.addSchema(id, versions)
+	}
+	sp.translator = t
+	return nil
+}
+
+func (sp *schemaProcessor) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := sp.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// The resources and the scopes are translated according to their own schema URL, and set the target schema
+// URL once translated. The data of the scopes without schema URL is translated according to the schema URL of
+// the resource, which is kept consistent with it.
+
+func (sp *schemaProcessor) processTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceURL := rs.SchemaUrl()
+		if tr := sp.translator.translation(resourceURL); tr != nil {
+			tr.resource(rs.Resource())
+			rs.SetSchemaUrl(tr.targetURL)
+		}
+		ilss := rs.ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			tr := sp.scopeTranslation(resourceURL, ils.SchemaUrl())
+			if tr == nil {
+				continue
+			}
+			if ils.SchemaUrl() != "" {
+				ils.SetSchemaUrl(tr.targetURL)
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				tr.span(spans.At(k))
+			}
+		}
+	}
+	return td, nil
+}
+
+func (sp *schemaProcessor) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resourceURL := rm.SchemaUrl()
+		if tr := sp.translator.translation(resourceURL); tr != nil {
+			tr.resource(rm.Resource())
+			rm.SetSchemaUrl(tr.targetURL)
+		}
+		ilms := rm.ScopeMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			tr := sp.scopeTranslation(resourceURL, ilm.SchemaUrl())
+			if tr == nil {
+				continue
+			}
+			if ilm.SchemaUrl() != "" {
+				ilm.SetSchemaUrl(tr.targetURL)
+			}
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				tr.metric(metrics.At(k))
+			}
+		}
+	}
+	return md, nil
+}
+
+func (sp *schemaProcessor) processLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceURL := rl.SchemaUrl()
+		if tr := sp.translator.translation(resourceURL); tr != nil {
+			tr.resource(rl.Resource())
+			rl.SetSchemaUrl(tr.targetURL)
+		}
+		ills := rl.ScopeLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			tr := sp.scopeTranslation(resourceURL, ill.SchemaUrl())
+			if tr == nil {
+				continue
+			}
+			if ill.SchemaUrl() != "" {
+				ill.SetSchemaUrl(tr.targetURL)
+			}
+			lrs := ill.LogRecords()
+			for k := 0; k < lrs.Len(); k++ {
+				tr.logRecord(lrs.At(k))
+			}
+		}
+	}
+	return ld, nil
+}
+
+// scopeTranslation returns the translation of the data of a scope, according to the schema URL of the
+// scope if set, or else of the resource.
+func (sp *schemaProcessor) scopeTranslation(resourceURL, scopeURL string) *translation {
+	if scopeURL != "" {
+		return sp.translator.translation(scopeURL)
+	}
+	return sp.translator.translation(resourceURL)
+}
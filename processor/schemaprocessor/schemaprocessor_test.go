@@ -0,0 +1,245 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaprocessor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+const (
+	schemaURL100 = "https://example.com/schemas/1.0.0"
+	schemaURL110 = "https://example.com/schemas/1.1.0"
+	schemaURL120 = "https://example.com/schemas/1.2.0"
+)
+
+func newTestSchemaProcessor(t *testing.T, target string) *schemaProcessor {
+	sp := newSchemaProcessor(processortest.NewNopCreateSettings(), &Config{
+		Targets:         []string{target},
+		SchemaFiles:     []string{filepath.Join("testdata", "schema.yaml")},
+		DownloadTimeout: time.Second,
+	})
+	require.NoError(t, sp.start(context.Background(), componenttest.NewNopHost()))
+	return sp
+}
+
+func newTestTraces(schemaURL, scopeSchemaURL string) ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.SetSchemaUrl(schemaURL)
+	rs.Resource().Attributes().PutStr("host", "my-host")
+	ils := rs.ScopeSpans().AppendEmpty()
+	ils.SetSchemaUrl(scopeSchemaURL)
+	span := ils.Spans().AppendEmpty()
+	span.SetName("query")
+	span.Attributes().PutStr("net.peer.name", "db")
+	span.Attributes().PutStr("db.sql", "SELECT 1")
+	event := span.Events().AppendEmpty()
+	event.SetName("error")
+	event.Attributes().PutStr("net.peer.name", "db")
+	other := ils.Spans().AppendEmpty()
+	other.SetName("other")
+	other.Attributes().PutStr("db.sql", "SELECT 1")
+	return td
+}
+
+func TestProcessTracesUpgrade(t *testing.T) {
+	sp := newTestSchemaProcessor(t, schemaURL120)
+	td, err := sp.processTraces(context.Background(), newTestTraces(schemaURL100, ""))
+	require.NoError(t, err)
+
+	rs := td.ResourceSpans().At(0)
+	assert.Equal(t, schemaURL120, rs.SchemaUrl())
+	assert.Equal(t, map[string]any{"host.name": "my-host"}, rs.Resource().Attributes().AsRaw())
+	ils := rs.ScopeSpans().At(0)
+	// The scope without schema URL is translated with the resource, and keeps no schema URL.
+	assert.Equal(t, "", ils.SchemaUrl())
+	span := ils.Spans().At(0)
+	assert.Equal(t, map[string]any{"server.address": "db", "db.statement": "SELECT 1"}, span.Attributes().AsRaw())
+	assert.Equal(t, "exception", span.Events().At(0).Name())
+	assert.Equal(t, map[string]any{"server.address": "db"}, span.Events().At(0).Attributes().AsRaw())
+	// The renames applying to other spans are not applied.
+	assert.Equal(t, map[string]any{"db.sql": "SELECT 1"}, ils.Spans().At(1).Attributes().AsRaw())
+}
+
+func TestProcessTracesDowngrade(t *testing.T) {
+	sp := newTestSchemaProcessor(t, schemaURL110)
+	td := newTestTraces(schemaURL120, schemaURL120)
+	span := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	span.Attributes().Clear()
+	span.Attributes().PutStr("server.address", "db")
+	span.Attributes().PutStr("db.statement", "SELECT 1")
+	span.Events().At(0).SetName("exception")
+	td.ResourceSpans().At(0).Resource().Attributes().Clear()
+	td.ResourceSpans().At(0).Resource().Attributes().PutStr("host.name", "my-host")
+
+	td, err := sp.processTraces(context.Background(), td)
+	require.NoError(t, err)
+	rs := td.ResourceSpans().At(0)
+	assert.Equal(t, schemaURL110, rs.SchemaUrl())
+	// The resource attribute was renamed in 1.1.0, which is kept.
+	assert.Equal(t, map[string]any{"host.name": "my-host"}, rs.Resource().Attributes().AsRaw())
+	assert.Equal(t, schemaURL110, rs.ScopeSpans().At(0).SchemaUrl())
+	span = rs.ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, map[string]any{"net.peer.name": "db", "db.sql": "SELECT 1"}, span.Attributes().AsRaw())
+	assert.Equal(t, "error", span.Events().At(0).Name())
+}
+
+func TestProcessTracesNotTranslated(t *testing.T) {
+	sp := newTestSchemaProcessor(t, schemaURL120)
+	for _, url := range []string{"", schemaURL120, "https://example.com/schemas/1.3.0", "https://opentelemetry.io/schemas/1.0.0", "invalid"} {
+		want := newTestTraces(url, "")
+		td, err := sp.processTraces(context.Background(), newTestTraces(url, ""))
+		require.NoError(t, err)
+		assert.Equal(t, want, td, url)
+	}
+}
+
+func TestProcessTracesScopeSchemaURL(t *testing.T) {
+	sp := newTestSchemaProcessor(t, schemaURL120)
+	// The scope is translated according to its own schema URL.
+	td, err := sp.processTraces(context.Background(), newTestTraces(schemaURL120, schemaURL110))
+	require.NoError(t, err)
+	rs := td.ResourceSpans().At(0)
+	assert.Equal(t, map[string]any{"host": "my-host"}, rs.Resource().Attributes().AsRaw())
+	assert.Equal(t, schemaURL120, rs.ScopeSpans().At(0).SchemaUrl())
+	span := rs.ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, map[string]any{"server.address": "db", "db.statement": "SELECT 1"}, span.Attributes().AsRaw())
+}
+
+func TestProcessMetrics(t *testing.T) {
+	sp := newTestSchemaProcessor(t, schemaURL120)
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.SetSchemaUrl(schemaURL110)
+	ilm := rm.ScopeMetrics().AppendEmpty()
+	sum := ilm.Metrics().AppendEmpty()
+	sum.SetName("http.duration")
+	sum.SetEmptySum().DataPoints().AppendEmpty().Attributes().PutInt("status", 200)
+	hist := ilm.Metrics().AppendEmpty()
+	hist.SetName("other")
+	dp := hist.SetEmptyHistogram().DataPoints().AppendEmpty()
+	dp.Attributes().PutInt("status", 200)
+	dp.Attributes().PutStr("net.peer.name", "server")
+
+	md, err := sp.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+	rm = md.ResourceMetrics().At(0)
+	assert.Equal(t, schemaURL120, rm.SchemaUrl())
+	sum = rm.ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "http.server.duration", sum.Name())
+	assert.Equal(t, map[string]any{"http.status_code": int64(200)}, sum.Sum().DataPoints().At(0).Attributes().AsRaw())
+	hist = rm.ScopeMetrics().At(0).Metrics().At(1)
+	assert.Equal(t, "other", hist.Name())
+	assert.Equal(t, map[string]any{"status": int64(200), "server.address": "server"}, hist.Histogram().DataPoints().At(0).Attributes().AsRaw())
+
+	// The metrics are translated back when downgraded.
+	sp = newTestSchemaProcessor(t, schemaURL110)
+	md, err = sp.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+	sum = md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "http.duration", sum.Name())
+	assert.Equal(t, map[string]any{"status": int64(200)}, sum.Sum().DataPoints().At(0).Attributes().AsRaw())
+}
+
+func TestProcessLogs(t *testing.T) {
+	sp := newTestSchemaProcessor(t, schemaURL110)
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.SetSchemaUrl(schemaURL100)
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.Attributes().PutStr("level", "info")
+	lr.Attributes().PutStr("net.peer.name", "server")
+
+	ld, err := sp.processLogs(context.Background(), ld)
+	require.NoError(t, err)
+	rl = ld.ResourceLogs().At(0)
+	assert.Equal(t, schemaURL110, rl.SchemaUrl())
+	// Only the changes up to the target are applied.
+	assert.Equal(t, map[string]any{"severity": "info", "net.peer.name": "server"}, rl.ScopeLogs().At(0).LogRecords().At(0).Attributes().AsRaw())
+}
+
+func TestRenameAttributesSwap(t *testing.T) {
+	attrs := pcommon.NewMap()
+	attrs.PutStr("a", "1")
+	attrs.PutStr("b", "2")
+	attrs.PutStr("c", "3")
+	renameAttributes(attrs, map[string]string{"a": "b", "b": "a"})
+	assert.Equal(t, map[string]any{"a": "2", "b": "1", "c": "3"}, attrs.AsRaw())
+}
+
+func TestStartDownload(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/schemas/1.2.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// The schema URL of the file is the one it is downloaded from.
+		_, _ = w.Write([]byte(strings.ReplaceAll(string(data), schemaURL120, "http://"+r.Host+"/schemas/1.2.0")))
+	}))
+	defer server.Close()
+
+	sp := newSchemaProcessor(processortest.NewNopCreateSettings(), &Config{
+		Targets:         []string{server.URL + "/schemas/1.2.0"},
+		DownloadTimeout: time.Second,
+	})
+	require.NoError(t, sp.start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, 1, requests)
+
+	td, err := sp.processTraces(context.Background(), newTestTraces(server.URL+"/schemas/1.0.0", ""))
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/schemas/1.2.0", td.ResourceSpans().At(0).SchemaUrl())
+	assert.Equal(t, map[string]any{"host.name": "my-host"}, td.ResourceSpans().At(0).Resource().Attributes().AsRaw())
+
+	// The local schema files are not downloaded.
+	sp = newSchemaProcessor(processortest.NewNopCreateSettings(), &Config{
+		Targets:         []string{schemaURL110},
+		SchemaFiles:     []string{filepath.Join("testdata", "schema.yaml")},
+		DownloadTimeout: time.Second,
+	})
+	assert.NoError(t, sp.start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, 1, requests)
+
+	sp = newSchemaProcessor(processortest.NewNopCreateSettings(), &Config{
+		Targets:         []string{server.URL + "/schemas/1.3.0"},
+		DownloadTimeout: time.Second,
+	})
+	assert.Error(t, sp.start(context.Background(), componenttest.NewNopHost()))
+}
+
+func TestStartInvalidSchemaFile(t *testing.T) {
+	sp := newSchemaProcessor(processortest.NewNopCreateSettings(), &Config{
+		Targets:         []string{schemaURL110},
+		SchemaFiles:     []string{filepath.Join("testdata", "missing.yaml")},
+		DownloadTimeout: time.Second,
+	})
+	assert.Error(t, sp.start(context.Background(), componenttest.NewNopHost()))
+
+	sp = newSchemaProcessor(processortest.NewNopCreateSettings(), &Config{
+		Targets:         []string{schemaURL110},
+		SchemaFiles:     []string{filepath.Join("testdata", "config.yaml")},
+		DownloadTimeout: time.Second,
+	})
+	assert.Error(t, sp.start(context.Background(), componenttest.NewNopHost()))
+}
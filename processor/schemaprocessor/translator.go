@@ -0,0 +1,248 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaprocessor // import "go.opentelemetry.io/collector/processor/schemaprocessor"
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// translator translates the telemetry of the schema families of the targets to the version of their target.
+type translator struct {
+	families map[string]*family
+
+	// translations caches the translations by schema URL.
+	translations sync.Map
+}
+
+// family holds the known versions of a schema family.
+type family struct {
+	target    version
+	targetURL string
+	// versions are the known versions of the family, sorted in ascending order.
+	versions []*versionChanges
+	// latest is the version of the newest schema file of the family.
+	latest version
+	loaded bool
+}
+
+// translation is the translation of the telemetry of a schema URL to the target of its family.
+type translation struct {
+	targetURL string
+	// steps are the changes to apply, in order.
+	steps []*versionChanges
+}
+
+func newTranslator(targets []string) (*translator, error) {
+	t := &translator{families: map[string]*family{}}
+	for _, target := range targets {
+		id, err := parseSchemaURL(target)
+		if err != nil {
+			return nil, err
+		}
+		t.families[id.family] = &family{target: id.version, targetURL: target}
+	}
+	return t, nil
+}
+
+// addSchema adds the versions of a schema file. The newest schema file of a family is kept, as it
+// describes all the previous versions too. The schema files of the other families are ignored.
+func (t *translator) addSchema(id schemaID, versions []*versionChanges) {
+	f, ok := t.families[id.family]
+	if !ok || (f.loaded && !f.latest.less(id.version)) {
+		return
+	}
+	f.versions = versions
+	f.latest = id.version
+	f.loaded = true
+}
+
+// missing returns the targets whose schema files were not added.
+func (t *translator) missing() []string {
+	var targets []string
+	for _, f := range t.families {
+		if !f.loaded || f.latest.less(f.target) {
+			targets = append(targets, f.targetURL)
+		}
+	}
+	return targets
+}
+
+// translation returns the translation of the telemetry of the given schema URL, nil if the telemetry
+// is not translated: the schema family has no target, the telemetry already has the target version,
+// or the version is newer than all the known versions.
+func (t *translator) translation(schemaURL string) *translation {
+	if schemaURL == "" {
+		return nil
+	}
+	if tr, ok := t.translations.Load(schemaURL); ok {
+		return tr.(*translation)
+	}
+	tr := t.newTranslation(schemaURL)
+	t.translations.Store(schemaURL, tr)
+	return tr
+}
+
+func (t *translator) newTranslation(schemaURL string) *translation {
+	id, err := parseSchemaURL(schemaURL)
+	if err != nil {
+		return nil
+	}
+	f, ok := t.families[id.family]
+	if !ok || id.version == f.target {
+		return nil
+	}
+	tr := &translation{targetURL: f.targetURL}
+	if id.version.less(f.target) {
+		// Upgrade through the versions after the one of the telemetry, up to the target.
+		for _, vc := range f.versions {
+			if id.version.less(vc.version) && !f.target.less(vc.version) {
+				tr.steps = append(tr.steps, vc)
+			}
+		}
+		return tr
+	}
+	if f.latest.less(id.version) {
+		return nil
+	}
+	// Downgrade through the versions after the target, up to the one of the telemetry, in reverse order.
+	for i := len(f.versions) - 1; i >= 0; i-- {
+		vc := f.versions[i]
+		if f.target.less(vc.version) && !id.version.less(vc.version) {
+			tr.steps = append(tr.steps, vc.inverse())
+		}
+	}
+	return tr
+}
+
+func (tr *translation) resource(res pcommon.Resource) {
+	for _, vc := range tr.steps {
+		renameAll(res.Attributes(), vc.all)
+		renameAll(res.Attributes(), vc.resources)
+	}
+}
+
+func (tr *translation) span(span ptrace.Span) {
+	events := span.Events()
+	for _, vc := range tr.steps {
+		renameAll(span.Attributes(), vc.all)
+		for i := 0; i < events.Len(); i++ {
+			renameAll(events.At(i).Attributes(), vc.all)
+		}
+		for _, c := range vc.spans {
+			if c.appliesTo(span.Name()) {
+				renameAttributes(span.Attributes(), c.attributes)
+			}
+		}
+		for _, c := range vc.spanEvents {
+			for i := 0; i < events.Len(); i++ {
+				event := events.At(i)
+				if to, ok := c.names[event.Name()]; ok {
+					event.SetName(to)
+					continue
+				}
+				if c.appliesTo(span.Name()) && (c.applyToEvents == nil || c.applyToEvents[event.Name()]) {
+					renameAttributes(event.Attributes(), c.attributes)
+				}
+			}
+		}
+	}
+}
+
+func (tr *translation) metric(metric pmetric.Metric) {
+	for _, vc := range tr.steps {
+		dataPointsAttributes(metric, func(attrs pcommon.Map) { renameAll(attrs, vc.all) })
+		for _, c := range vc.metrics {
+			if to, ok := c.names[metric.Name()]; ok {
+				metric.SetName(to)
+				continue
+			}
+			if c.appliesTo(metric.Name()) {
+				dataPointsAttributes(metric, func(attrs pcommon.Map) { renameAttributes(attrs, c.attributes) })
+			}
+		}
+	}
+}
+
+func (tr *translation) logRecord(lr plog.LogRecord) {
+	for _, vc := range tr.steps {
+		renameAll(lr.Attributes(), vc.all)
+		renameAll(lr.Attributes(), vc.logs)
+	}
+}
+
+// appliesTo returns whether the renames of the attributes apply to the span or metric with the given name.
+func (c change) appliesTo(name string) bool {
+	return c.applyTo == nil || c.applyTo[name]
+}
+
+func renameAll(attrs pcommon.Map, changes []change) {
+	for _, c := range changes {
+		renameAttributes(attrs, c.attributes)
+	}
+}
+
+// renameAttributes renames the attributes at once, so the renames can swap attributes. A renamed attribute
+// replaces the attribute that has its new key.
+func renameAttributes(attrs pcommon.Map, renames map[string]string) {
+	if len(renames) == 0 || attrs.Len() == 0 {
+		return
+	}
+	var moved pcommon.Map
+	found := false
+	attrs.RemoveIf(func(k string, v pcommon.Value) bool {
+		to, ok := renames[k]
+		if !ok {
+			return false
+		}
+		if !found {
+			moved = pcommon.NewMap()
+			found = true
+		}
+		v.CopyTo(moved.PutEmpty(to))
+		return true
+	})
+	if !found {
+		return
+	}
+	moved.Range(func(k string, v pcommon.Value) bool {
+		v.CopyTo(attrs.PutEmpty(k))
+		return true
+	})
+}
+
+// dataPointsAttributes calls f with the attributes of each data point of the metric.
+func dataPointsAttributes(metric pmetric.Metric, f func(pcommon.Map)) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	}
+}
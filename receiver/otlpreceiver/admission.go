@@ -0,0 +1,408 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	grpcproto "google.golang.org/grpc/encoding/proto"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/admission"
+)
+
+// errRefusedByAdmission is returned to the clients when the admission extension refuses the requests.
+// It is retryable, so the clients send the data again later.
+var errRefusedByAdmission = errors.New("data refused by the admission extension, retry later")
+
+// getAdmission returns the admission extension with the given ID.
+func getAdmission(host component.Host, admissionID component.ID) (admission.Extension, error) {
+	ext, found := host.GetExtensions()[admissionID]
+	if !found {
+		return nil, fmt.Errorf("admission extension %q not found", admissionID)
+	}
+	admissionExt, ok := ext.(admission.Extension)
+	if !ok {
+		return nil, fmt.Errorf("extension %q is not an admission extension", admissionID)
+	}
+	return admissionExt, nil
+}
+
+// admissionRetryDelay is the delay to retry the refused requests after, sent to the clients. Neither
+// the admission extension nor the limits tell when the data is admitted again.
+const admissionRetryDelay = time.Second
+
+// grpcAdmission returns the gRPC server options refusing the requests with the RESOURCE_EXHAUSTED status
+// code: the streams are refused before the request is read while the admission extension refuses the
+// data, and the messages are refused before being unmarshalled when the limits of the in-flight data are
+// reached, with the delay to retry after as a RetryInfo detail. The gRPC server does not send the details
+// of the statuses refusing the streams. Either the extension or the limiter may be nil.
+func grpcAdmission(ext admission.Extension, limiter *admissionLimiter) []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if ext != nil {
+		opts = append(opts, grpc.InTapHandle(func(ctx context.Context, _ *tap.Info) (context.Context, error) {
+			if ext.MustRefuse() {
+				return ctx, status.Error(codes.ResourceExhausted, errRefusedByAdmission.Error())
+			}
+			return ctx, nil
+		}))
+	}
+	if limiter != nil {
+		opts = append(opts,
+			grpc.ForceServerCodec(&admissionCodec{Codec: encoding.GetCodec(grpcproto.Name), limiter: limiter}),
+			grpc.StatsHandler(&admissionStatsHandler{limiter: limiter}),
+			grpc.ChainUnaryInterceptor(limiter.unaryInterceptor),
+			grpc.ChainStreamInterceptor(limiter.streamInterceptor))
+	}
+	return opts
+}
+
+// admissionStatus returns the RESOURCE_EXHAUSTED status of the refused requests, with the delay to retry
+// after as a RetryInfo detail unless the request can never be admitted.
+func admissionStatus(err error) *status.Status {
+	st := status.New(codes.ResourceExhausted, err.Error())
+	if errors.Is(err, errRequestTooLarge) {
+		return st
+	}
+	if withDetails, detailsErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(admissionRetryDelay)}); detailsErr == nil {
+		return withDetails
+	}
+	return st
+}
+
+// httpAdmission returns a handler refusing the requests, before the body is decoded, with the
+// 429 Too Many Requests status code while the admission extension refuses the data or the limits
+// of the in-flight data are reached. Either the extension or the limiter may be nil.
+func httpAdmission(ext admission.Extension, limiter *admissionLimiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if ext != nil && ext.MustRefuse() {
+			resp.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(admissionRetryDelay)))
+			errorHandler(resp, req, errRefusedByAdmission.Error(), http.StatusTooManyRequests)
+			return
+		}
+		if limiter == nil {
+			next.ServeHTTP(resp, req)
+			return
+		}
+		limiter.serveHTTP(resp, req, next)
+	})
+}
+
+var (
+	// errRefusedByAdmissionLimits is returned to the clients when the limits of the in-flight data are
+	// reached. It is retryable, so the clients send the data again later.
+	errRefusedByAdmissionLimits = errors.New("too much data in flight, retry later")
+	// errRequestTooLarge is returned to the clients when a request alone is larger than the limit of the
+	// in-flight bytes, so it can never be admitted.
+	errRequestTooLarge = errors.New("request larger than the limit of the in-flight bytes")
+)
+
+// admissionLimiter bounds the total size of the uncompressed requests being received and processed,
+// and their number, across all the protocols of the receiver.
+type admissionLimiter struct {
+	maxBytes    int64
+	maxRequests int64
+
+	bytes    atomic.Int64
+	requests atomic.Int64
+
+	// reservations are the reservations of the gRPC messages being unmarshalled, by message, until the
+	// stats handler moves them to the calls receiving the messages.
+	reservations sync.Map
+}
+
+// newAdmissionLimiter returns the limiter of the given limits, nil if there are no limits.
+func newAdmissionLimiter(cfg AdmissionLimitsConfig) *admissionLimiter {
+	if cfg.MaxInFlightBytes == 0 && cfg.MaxInFlightRequests == 0 {
+		return nil
+	}
+	return &admissionLimiter{maxBytes: cfg.MaxInFlightBytes, maxRequests: cfg.MaxInFlightRequests}
+}
+
+func (l *admissionLimiter) acquireRequest() bool {
+	if l.maxRequests == 0 {
+		return true
+	}
+	if l.requests.Add(1) > l.maxRequests {
+		l.requests.Add(-1)
+		return false
+	}
+	return true
+}
+
+func (l *admissionLimiter) releaseRequest() {
+	if l.maxRequests > 0 {
+		l.requests.Add(-1)
+	}
+}
+
+// acquireBytes returns nil if the given number of bytes is admitted, errRequestTooLarge if it is
+// larger than the limit, or errRefusedByAdmissionLimits if it does not fit in the remaining bytes.
+func (l *admissionLimiter) acquireBytes(n int64) error {
+	if l.maxBytes == 0 {
+		return nil
+	}
+	if n > l.maxBytes {
+		return errRequestTooLarge
+	}
+	if l.bytes.Add(n) > l.maxBytes {
+		l.bytes.Add(-n)
+		return errRefusedByAdmissionLimits
+	}
+	return nil
+}
+
+func (l *admissionLimiter) releaseBytes(n int64) {
+	if l.maxBytes > 0 {
+		l.bytes.Add(-n)
+	}
+}
+
+// admissionReservation is the reservation of the limits for a gRPC message, or the error refusing it.
+type admissionReservation struct {
+	size int64
+	err  error
+}
+
+// reserve reserves a request and the given number of bytes for the message before it is unmarshalled,
+// and returns whether they are reserved. The message is not unmarshalled if refused.
+func (l *admissionLimiter) reserve(msg any, size int64) bool {
+	if !l.acquireRequest() {
+		l.reservations.Store(msg, admissionReservation{err: errRefusedByAdmissionLimits})
+		return false
+	}
+	if err := l.acquireBytes(size); err != nil {
+		l.releaseRequest()
+		l.reservations.Store(msg, admissionReservation{err: err})
+		return false
+	}
+	l.reservations.Store(msg, admissionReservation{size: size})
+	return true
+}
+
+// take removes the reservation of the message.
+func (l *admissionLimiter) take(msg any) (admissionReservation, bool) {
+	r, ok := l.reservations.LoadAndDelete(msg)
+	if !ok {
+		return admissionReservation{}, false
+	}
+	return r.(admissionReservation), true
+}
+
+// release releases the limits reserved by the reservation.
+func (l *admissionLimiter) release(r admissionReservation) {
+	if r.err == nil {
+		l.releaseBytes(r.size)
+		l.releaseRequest()
+	}
+}
+
+// admissionCodec reserves the limits for the gRPC messages from their uncompressed size, before they are
+// unmarshalled, so the refused messages are never unmarshalled. The messages are refused by the
+// interceptors, since the errors of the codec are reported as internal errors.
+type admissionCodec struct {
+	encoding.Codec
+	limiter *admissionLimiter
+}
+
+func (c *admissionCodec) Unmarshal(data []byte, msg any) error {
+	if !c.limiter.reserve(msg, int64(len(data))) {
+		return nil
+	}
+	if err := c.Codec.Unmarshal(data, msg); err != nil {
+		if r, ok := c.limiter.take(msg); ok {
+			c.limiter.release(r)
+		}
+		return err
+	}
+	return nil
+}
+
+// admissionCallKey is the context key of the admissionCall of the gRPC calls.
+type admissionCallKey struct{}
+
+// admissionCall holds the reservations of the messages received by a gRPC call, released once it ends.
+type admissionCall struct {
+	mu           sync.Mutex
+	reservations map[any]admissionReservation
+}
+
+func (c *admissionCall) add(msg any, r admissionReservation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reservations[msg] = r
+}
+
+func (c *admissionCall) take(msg any) (admissionReservation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.reservations[msg]
+	delete(c.reservations, msg)
+	return r, ok
+}
+
+func (c *admissionCall) refusal(msg any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reservations[msg].err
+}
+
+// admissionStatsHandler moves the reservations of the messages, once unmarshalled, to the calls receiving
+// them, and releases them once the calls end, whether or not the interceptors are called.
+type admissionStatsHandler struct {
+	limiter *admissionLimiter
+}
+
+func (h *admissionStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, admissionCallKey{}, &admissionCall{reservations: map[any]admissionReservation{}})
+}
+
+func (h *admissionStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	call, ok := ctx.Value(admissionCallKey{}).(*admissionCall)
+	if !ok {
+		return
+	}
+	switch s := s.(type) {
+	case *stats.InPayload:
+		if r, ok := h.limiter.take(s.Payload); ok {
+			call.add(s.Payload, r)
+		}
+	case *stats.End:
+		call.mu.Lock()
+		defer call.mu.Unlock()
+		for msg, r := range call.reservations {
+			h.limiter.release(r)
+			delete(call.reservations, msg)
+		}
+	}
+}
+
+func (h *admissionStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *admissionStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// unaryInterceptor refuses the requests whose message was refused by the codec. The reservations of the
+// admitted requests are released once the calls end.
+func (l *admissionLimiter) unaryInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if call, ok := ctx.Value(admissionCallKey{}).(*admissionCall); ok {
+		if err := call.refusal(req); err != nil {
+			return nil, admissionStatus(err).Err()
+		}
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor refuses the messages of the streams refused by the codec. The reservations of the
+// admitted messages are released once received, the streams are not bounded by the limits.
+func (l *admissionLimiter) streamInterceptor(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	call, ok := ss.Context().Value(admissionCallKey{}).(*admissionCall)
+	if !ok {
+		return handler(srv, ss)
+	}
+	return handler(srv, &admissionServerStream{ServerStream: ss, limiter: l, call: call})
+}
+
+type admissionServerStream struct {
+	grpc.ServerStream
+	limiter *admissionLimiter
+	call    *admissionCall
+}
+
+func (s *admissionServerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	r, ok := s.call.take(m)
+	if !ok {
+		return nil
+	}
+	if r.err != nil {
+		return admissionStatus(r.err).Err()
+	}
+	s.limiter.release(r)
+	return nil
+}
+
+// serveHTTP reads the uncompressed body, admitting its bytes as they are read, then serves the request.
+// The requests whose Content-Length is larger than the limit are refused before the body is read.
+func (l *admissionLimiter) serveHTTP(resp http.ResponseWriter, req *http.Request, next http.Handler) {
+	if !l.acquireRequest() {
+		resp.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(admissionRetryDelay)))
+		errorHandler(resp, req, errRefusedByAdmissionLimits.Error(), http.StatusTooManyRequests)
+		return
+	}
+	defer l.releaseRequest()
+	if l.maxBytes == 0 {
+		next.ServeHTTP(resp, req)
+		return
+	}
+	if req.Header.Get("Content-Encoding") == "" && req.ContentLength > l.maxBytes {
+		errorHandler(resp, req, errRequestTooLarge.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	body, err := l.readBody(req.Body)
+	defer l.releaseBytes(int64(len(body)))
+	switch {
+	case errors.Is(err, errRequestTooLarge):
+		errorHandler(resp, req, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, errRefusedByAdmissionLimits):
+		resp.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(admissionRetryDelay)))
+		errorHandler(resp, req, err.Error(), http.StatusTooManyRequests)
+		return
+	case isRequestTooLarge(err):
+		errorHandler(resp, req, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	case err != nil:
+		errorHandler(resp, req, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	next.ServeHTTP(resp, req)
+}
+
+// readBody reads the body, admitting its bytes as they are read. The bytes of the returned body are
+// admitted, even on error, and must be released.
+func (l *admissionLimiter) readBody(r io.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(chunk)
+		if n > 0 {
+			if int64(buf.Len()+n) > l.maxBytes {
+				return buf.Bytes(), errRequestTooLarge
+			}
+			if errAcquire := l.acquireBytes(int64(n)); errAcquire != nil {
+				return buf.Bytes(), errAcquire
+			}
+			buf.Write(chunk[:n])
+		}
+		if errors.Is(err, io.EOF) {
+			return buf.Bytes(), nil
+		}
+		if err != nil {
+			return buf.Bytes(), err
+		}
+	}
+}
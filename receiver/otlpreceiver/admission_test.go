@@ -0,0 +1,376 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	grpcproto "google.golang.org/grpc/encoding/proto"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/internal/testutil"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+var admissionID = component.NewID("memory_limiter")
+
+type admissionExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	refuse atomic.Bool
+}
+
+func (ae *admissionExtension) MustRefuse() bool {
+	return ae.refuse.Load()
+}
+
+type admissionHost struct {
+	component.Host
+	ext component.Component
+}
+
+func (h *admissionHost) GetExtensions() map[component.ID]component.Component {
+	return map[component.ID]component.Component{admissionID: h.ext}
+}
+
+func TestGRPCAdmission(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.TracesSink)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	cfg.Admission = &admissionID
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, sink, nil)
+
+	ext := &admissionExtension{}
+	require.NoError(t, ocr.Start(context.Background(), &admissionHost{Host: componenttest.NewNopHost(), ext: ext}))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	td := testdata.GenerateTraces(1)
+	require.NoError(t, exportTraces(cc, td))
+	assert.Equal(t, 1, sink.SpanCount())
+
+	ext.refuse.Store(true)
+	err = exportTraces(cc, td)
+	require.Error(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, 1, sink.SpanCount())
+
+	ext.refuse.Store(false)
+	require.NoError(t, exportTraces(cc, td))
+	assert.Equal(t, 2, sink.SpanCount())
+}
+
+func TestHTTPAdmission(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	url := fmt.Sprintf("http://%s/v1/traces", addr)
+	sink := new(consumertest.TracesSink)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	cfg.Admission = &admissionID
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, sink, nil)
+
+	ext := &admissionExtension{}
+	require.NoError(t, ocr.Start(context.Background(), &admissionHost{Host: componenttest.NewNopHost(), ext: ext}))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	send := func() int {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(traceJSON))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", jsonContentType)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, send())
+	assert.Equal(t, 2, sink.SpanCount())
+
+	ext.refuse.Store(true)
+	assert.Equal(t, http.StatusTooManyRequests, send())
+	assert.Equal(t, 2, sink.SpanCount())
+
+	ext.refuse.Store(false)
+	assert.Equal(t, http.StatusOK, send())
+	assert.Equal(t, 4, sink.SpanCount())
+}
+
+func TestAdmissionInvalidExtension(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    component.Host
+		wantErr string
+	}{
+		{
+			name:    "not_found",
+			host:    componenttest.NewNopHost(),
+			wantErr: `admission extension "memory_limiter" not found`,
+		},
+		{
+			name: "not_admission",
+			host: &admissionHost{Host: componenttest.NewNopHost(), ext: &struct {
+				component.StartFunc
+				component.ShutdownFunc
+			}{}},
+			wantErr: `extension "memory_limiter" is not an admission extension`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.GRPC.NetAddr.Endpoint = testutil.GetAvailableLocalAddress(t)
+			cfg.HTTP.Endpoint = testutil.GetAvailableLocalAddress(t)
+			cfg.Admission = &admissionID
+
+			ocr := newReceiver(t, factory, cfg, otlpReceiverID, consumertest.NewNop(), nil)
+			assert.EqualError(t, ocr.Start(context.Background(), tt.host), tt.wantErr)
+		})
+	}
+}
+
+// blockingTraces returns a consumer signaling each received request on started, and blocking it
+// until release is closed.
+func blockingTraces(started chan<- struct{}, release <-chan struct{}) consumer.Traces {
+	tc, _ := consumer.NewTraces(func(context.Context, ptrace.Traces) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+	return tc
+}
+
+func TestGRPCAdmissionLimits(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	cfg.AdmissionLimits = AdmissionLimitsConfig{MaxInFlightBytes: 64 * 1024, MaxInFlightRequests: 1}
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, blockingTraces(started, release), nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	// The requests are refused while another one is in flight.
+	done := make(chan error)
+	go func() { done <- exportTraces(cc, testdata.GenerateTraces(1)) }()
+	<-started
+	err = exportTraces(cc, testdata.GenerateTraces(1))
+	assertAdmissionRetryInfo(t, err)
+	close(release)
+	require.NoError(t, <-done)
+
+	// The requests larger than the in-flight bytes are always refused, retrying does not help.
+	st := status.Convert(exportTraces(cc, testdata.GenerateTraces(1000)))
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Empty(t, st.Details())
+
+	go func() { <-started }()
+	assert.NoError(t, exportTraces(cc, testdata.GenerateTraces(1)))
+}
+
+func TestGRPCAdmissionLimitsBytes(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	td := testdata.GenerateTraces(1)
+	size, err := ptraceotlp.NewExportRequestFromTraces(td).MarshalProto()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	cfg.AdmissionLimits = AdmissionLimitsConfig{MaxInFlightBytes: int64(len(size) * 3 / 2)}
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, blockingTraces(started, release), nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	// The request not fitting in the remaining bytes is refused before being unmarshalled and processed.
+	done := make(chan error)
+	go func() { done <- exportTraces(cc, td) }()
+	<-started
+	assertAdmissionRetryInfo(t, exportTraces(cc, td))
+	close(release)
+	require.NoError(t, <-done)
+
+	// The bytes are released once the call ends.
+	go func() { <-started }()
+	assert.NoError(t, exportTraces(cc, td))
+}
+
+func assertAdmissionRetryInfo(t *testing.T, err error) {
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.Equal(t, admissionRetryDelay, retryInfo.RetryDelay.AsDuration())
+}
+
+func TestAdmissionCodec(t *testing.T) {
+	l := newAdmissionLimiter(AdmissionLimitsConfig{MaxInFlightBytes: 10, MaxInFlightRequests: 1})
+	codec := &admissionCodec{Codec: encoding.GetCodec(grpcproto.Name), limiter: l}
+	data, err := codec.Marshal(&healthpb.HealthCheckRequest{Service: "otlp"})
+	require.NoError(t, err)
+
+	msg := &healthpb.HealthCheckRequest{}
+	require.NoError(t, codec.Unmarshal(data, msg))
+	assert.Equal(t, "otlp", msg.Service)
+	assert.Equal(t, int64(1), l.requests.Load())
+	assert.Equal(t, int64(len(data)), l.bytes.Load())
+
+	// The refused messages are not unmarshalled, the refusal is kept for the interceptors.
+	refused := &healthpb.HealthCheckRequest{}
+	require.NoError(t, codec.Unmarshal(data, refused))
+	assert.Empty(t, refused.Service)
+	r, ok := l.take(refused)
+	require.True(t, ok)
+	assert.ErrorIs(t, r.err, errRefusedByAdmissionLimits)
+
+	r, ok = l.take(msg)
+	require.True(t, ok)
+	l.release(r)
+	assert.Equal(t, int64(0), l.requests.Load())
+	assert.Equal(t, int64(0), l.bytes.Load())
+
+	// The reservation of the messages failing to unmarshal is released.
+	assert.Error(t, codec.Unmarshal([]byte{0xff}, &healthpb.HealthCheckRequest{}))
+	assert.Equal(t, int64(0), l.requests.Load())
+	assert.Equal(t, int64(0), l.bytes.Load())
+}
+
+func TestHTTPAdmissionLimits(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	url := fmt.Sprintf("http://%s/v1/traces", addr)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	cfg.AdmissionLimits = AdmissionLimitsConfig{MaxInFlightBytes: int64(len(traceJSON)), MaxInFlightRequests: 1}
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, blockingTraces(started, release), nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	var retryAfter string
+	send := func(body []byte, encoding string) int {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", jsonContentType)
+		req.Header.Set("Content-Encoding", encoding)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		retryAfter = resp.Header.Get("Retry-After")
+		return resp.StatusCode
+	}
+
+	// The requests are refused while another one is in flight.
+	done := make(chan int)
+	go func() { done <- send(traceJSON, "") }()
+	<-started
+	assert.Equal(t, http.StatusTooManyRequests, send(traceJSON, ""))
+	assert.Equal(t, "1", retryAfter)
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+
+	// The requests larger than the in-flight bytes are refused, by their Content-Length or, when
+	// compressed, once their uncompressed body is read.
+	large := append(bytes.Clone(traceJSON), ' ')
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(large, ""))
+	compressed, err := compressGzip(large)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(compressed.Bytes(), "gzip"))
+
+	go func() { <-started }()
+	assert.Equal(t, http.StatusOK, send(traceJSON, ""))
+}
+
+func TestAdmissionLimiter(t *testing.T) {
+	assert.Nil(t, newAdmissionLimiter(AdmissionLimitsConfig{}))
+
+	l := newAdmissionLimiter(AdmissionLimitsConfig{MaxInFlightBytes: 10, MaxInFlightRequests: 2})
+	require.NotNil(t, l)
+	assert.ErrorIs(t, l.acquireBytes(11), errRequestTooLarge)
+	require.NoError(t, l.acquireBytes(6))
+	assert.ErrorIs(t, l.acquireBytes(5), errRefusedByAdmissionLimits)
+	require.NoError(t, l.acquireBytes(4))
+	assert.Equal(t, int64(10), l.bytes.Load())
+	l.releaseBytes(10)
+	assert.Equal(t, int64(0), l.bytes.Load())
+
+	assert.True(t, l.acquireRequest())
+	assert.True(t, l.acquireRequest())
+	assert.False(t, l.acquireRequest())
+	l.releaseRequest()
+	assert.True(t, l.acquireRequest())
+	l.releaseRequest()
+	l.releaseRequest()
+	assert.Equal(t, int64(0), l.requests.Load())
+
+	body, err := l.readBody(bytes.NewReader([]byte("0123456789")))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("0123456789"), body)
+	assert.Equal(t, int64(10), l.bytes.Load())
+	l.releaseBytes(int64(len(body)))
+
+	body, err = l.readBody(bytes.NewReader([]byte("0123456789a")))
+	assert.ErrorIs(t, err, errRequestTooLarge)
+	l.releaseBytes(int64(len(body)))
+	assert.Equal(t, int64(0), l.bytes.Load())
+}
@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// grpcBackpressure returns the gRPC server option converting the backpressure errors returned by the
+// pipeline to retryable statuses, so the clients back off before retrying: RESOURCE_EXHAUSTED when the
+// data exceeds rate limits, UNAVAILABLE when the pipeline is overloaded, with the delay to retry after,
+// if known, as a RetryInfo detail.
+func grpcBackpressure() grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		var bpErr consumererror.Backpressure
+		if err == nil || !errors.As(err, &bpErr) {
+			return resp, err
+		}
+		return resp, backpressureStatus(err, bpErr).Err()
+	})
+}
+
+// backpressureStatus returns the gRPC status of the backpressure error, keeping the status of the wrapped
+// error if any.
+func backpressureStatus(err error, bpErr consumererror.Backpressure) *status.Status {
+	st, ok := status.FromError(err)
+	if !ok {
+		code := codes.Unavailable
+		if errors.Is(err, consumererror.ErrRateLimited) {
+			code = codes.ResourceExhausted
+		}
+		st = status.New(code, err.Error())
+	}
+	if bpErr.RetryAfter() <= 0 || len(st.Details()) > 0 {
+		return st
+	}
+	if withDetails, detailsErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(bpErr.RetryAfter())}); detailsErr == nil {
+		return withDetails
+	}
+	return st
+}
@@ -8,11 +8,13 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
 const (
@@ -21,6 +23,18 @@ const (
 	protoHTTP = "protocols::http"
 )
 
+type GRPCConfig struct {
+	*configgrpc.GRPCServerSettings `mapstructure:",squash"`
+
+	// Health serves the standard gRPC health checking service, reporting the OTLP services as serving
+	// until the receiver shuts down, so the load balancers can health check the gRPC port directly.
+	Health bool `mapstructure:"health"`
+
+	// Reflection serves the gRPC server reflection service, so the tools like grpcurl can list and
+	// call the OTLP services without their protobuf definitions.
+	Reflection bool `mapstructure:"reflection"`
+}
+
 type HTTPConfig struct {
 	*confighttp.HTTPServerSettings `mapstructure:",squash"`
 
@@ -32,18 +46,108 @@ type HTTPConfig struct {
 
 	// The URL path to receive logs on. If omitted "/v1/logs" will be used.
 	LogsURLPath string `mapstructure:"logs_url_path,omitempty"`
+
+	// Encodings are the IDs of the encoding extensions decoding the requests with their content type,
+	// in addition to the OTLP protobuf and JSON encodings.
+	Encodings []component.ID `mapstructure:"encodings"`
 }
 
 // Protocols is the configuration for the supported protocols.
 type Protocols struct {
-	GRPC *configgrpc.GRPCServerSettings `mapstructure:"grpc"`
-	HTTP *HTTPConfig                    `mapstructure:"http"`
+	GRPC *GRPCConfig `mapstructure:"grpc"`
+	HTTP *HTTPConfig `mapstructure:"http"`
 }
 
 // Config defines configuration for OTLP receiver.
 type Config struct {
 	// Protocols is the configuration for the supported protocols, currently gRPC and HTTP (Proto and JSON).
 	Protocols `mapstructure:"protocols"`
+
+	// Admission if not empty, refuses the incoming requests before reading and decoding the payloads
+	// when the component specified as an admission extension, e.g. the memory limiter extension, says so.
+	Admission *component.ID `mapstructure:"admission"`
+
+	// AdmissionLimits bounds the data being received and processed by the receiver, refusing the
+	// incoming requests before decoding the payloads when too much data is in flight.
+	AdmissionLimits AdmissionLimitsConfig `mapstructure:"admission_limits"`
+
+	// RateLimits if not nil, limits the rate of the requests and items of each client, refusing the
+	// requests exceeding the limits with retryable errors hinting when to retry.
+	RateLimits *RateLimitsConfig `mapstructure:"rate_limits"`
+
+	// ClientAttribution if not nil, attributes the accepted and refused items to the clients that sent
+	// them in the additional "*_by_client" receiver metrics, bounding the number of clients reported.
+	ClientAttribution *receiverhelper.ClientAttributionSettings `mapstructure:"client_attribution"`
+
+	// AccessLog if not nil, logs one line at the info level for a sample of the requests received, with
+	// their client, signal, number of items, outcome and latency.
+	AccessLog *receiverhelper.AccessLogSettings `mapstructure:"access_log"`
+
+	// Dedup if not nil, suppresses the replays of the requests already accepted, identified by the
+	// request IDs supplied by the clients, so the data is not duplicated when the clients retry.
+	Dedup *DedupConfig `mapstructure:"dedup"`
+
+	// Drain defines how long the requests already accepted are processed for on shutdown, once the
+	// receiver stopped accepting new connections, before the requests still in flight are cut off.
+	Drain receiverhelper.DrainSettings `mapstructure:"drain"`
+}
+
+// AdmissionLimitsConfig defines the limits of the data in flight, shared by all the protocols.
+type AdmissionLimitsConfig struct {
+	// MaxInFlightBytes is the maximum total size in bytes of the uncompressed requests being received
+	// and processed. The default 0 means there is no limit.
+	MaxInFlightBytes int64 `mapstructure:"max_in_flight_bytes"`
+
+	// MaxInFlightRequests is the maximum number of requests being received and processed.
+	// The default 0 means there is no limit.
+	MaxInFlightRequests int64 `mapstructure:"max_in_flight_requests"`
+}
+
+// RateLimitsConfig defines the rate limits applying separately to each client.
+type RateLimitsConfig struct {
+	// RequestsPerSecond is the maximum rate of requests of each client. Zero means no limit.
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+
+	// RequestsBurst is the maximum number of requests that can be accepted at once above the rate.
+	// Zero means the burst is equal to RequestsPerSecond.
+	RequestsBurst int `mapstructure:"requests_burst"`
+
+	// ItemsPerSecond is the maximum rate of spans, metric data points and log records of each client.
+	// Zero means no limit.
+	ItemsPerSecond float64 `mapstructure:"items_per_second"`
+
+	// ItemsBurst is the maximum number of items that can be accepted at once above the rate.
+	// Zero means the burst is equal to ItemsPerSecond.
+	ItemsBurst int `mapstructure:"items_burst"`
+
+	// AuthAttribute if not empty, is the attribute set by the authenticator, e.g. "subject", identifying
+	// the clients. The clients without it, or all the clients when empty, are identified by their IP address.
+	AuthAttribute string `mapstructure:"auth_attribute"`
+
+	// MaxClients is the maximum number of clients with their own limits. The other clients share the
+	// same limits until the idle clients are removed. Zero means no limit.
+	MaxClients int `mapstructure:"max_clients"`
+}
+
+// DedupConfig defines how the replays of the requests are suppressed.
+type DedupConfig struct {
+	// Header is the HTTP header, or gRPC metadata key, carrying the request IDs. The requests without
+	// it are never deduplicated. If omitted "Idempotency-Key" will be used.
+	Header string `mapstructure:"header"`
+
+	// Window is how long the request IDs of the requests accepted are remembered.
+	Window time.Duration `mapstructure:"window"`
+
+	// MaxRequests is the maximum number of request IDs remembered, the oldest are forgotten first.
+	// Zero means no limit.
+	MaxRequests int `mapstructure:"max_requests"`
+
+	// StorageID if not nil, is the storage extension persisting the request IDs across restarts.
+	StorageID *component.ID `mapstructure:"storage"`
+
+	// FlushInterval is the interval between the persistence of the request IDs remembered, so they are kept
+	// even if the collector stops without shutting down. If omitted 1s will be used.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -54,6 +158,49 @@ func (cfg *Config) Validate() error {
 	if cfg.GRPC == nil && cfg.HTTP == nil {
 		return errors.New("must specify at least one protocol when using the OTLP receiver")
 	}
+	if cfg.AdmissionLimits.MaxInFlightBytes < 0 {
+		return errors.New("admission_limits::max_in_flight_bytes must not be negative")
+	}
+	if cfg.AdmissionLimits.MaxInFlightRequests < 0 {
+		return errors.New("admission_limits::max_in_flight_requests must not be negative")
+	}
+	if cfg.RateLimits != nil {
+		if err := cfg.RateLimits.validate(); err != nil {
+			return err
+		}
+	}
+	if cfg.Dedup != nil {
+		return cfg.Dedup.validate()
+	}
+	return nil
+}
+
+func (cfg *RateLimitsConfig) validate() error {
+	if cfg.RequestsPerSecond < 0 || cfg.ItemsPerSecond < 0 {
+		return errors.New("rate_limits: rate must not be negative")
+	}
+	if cfg.RequestsBurst < 0 || cfg.ItemsBurst < 0 {
+		return errors.New("rate_limits: burst must not be negative")
+	}
+	if cfg.MaxClients < 0 {
+		return errors.New("rate_limits::max_clients must not be negative")
+	}
+	if cfg.RequestsPerSecond == 0 && cfg.ItemsPerSecond == 0 {
+		return errors.New("rate_limits: at least one of requests_per_second or items_per_second must be set")
+	}
+	return nil
+}
+
+func (cfg *DedupConfig) validate() error {
+	if cfg.Window <= 0 {
+		return errors.New("dedup::window must be positive")
+	}
+	if cfg.MaxRequests < 0 {
+		return errors.New("dedup::max_requests must not be negative")
+	}
+	if cfg.FlushInterval < 0 {
+		return errors.New("dedup::flush_interval must not be negative")
+	}
 	return nil
 }
 
@@ -18,6 +18,7 @@ import (
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
 func TestUnmarshalDefaultConfig(t *testing.T) {
@@ -86,34 +87,38 @@ func TestUnmarshalConfig(t *testing.T) {
 	assert.Equal(t,
 		&Config{
 			Protocols: Protocols{
-				GRPC: &configgrpc.GRPCServerSettings{
-					NetAddr: confignet.NetAddr{
-						Endpoint:  "0.0.0.0:4317",
-						Transport: "tcp",
-					},
-					TLSSetting: &configtls.TLSServerSetting{
-						TLSSetting: configtls.TLSSetting{
-							CertFile: "test.crt",
-							KeyFile:  "test.key",
+				GRPC: &GRPCConfig{
+					GRPCServerSettings: &configgrpc.GRPCServerSettings{
+						NetAddr: confignet.NetAddr{
+							Endpoint:  "0.0.0.0:4317",
+							Transport: "tcp",
 						},
-					},
-					MaxRecvMsgSizeMiB:    32,
-					MaxConcurrentStreams: 16,
-					ReadBufferSize:       1024,
-					WriteBufferSize:      1024,
-					Keepalive: &configgrpc.KeepaliveServerConfig{
-						ServerParameters: &configgrpc.KeepaliveServerParameters{
-							MaxConnectionIdle:     11 * time.Second,
-							MaxConnectionAge:      12 * time.Second,
-							MaxConnectionAgeGrace: 13 * time.Second,
-							Time:                  30 * time.Second,
-							Timeout:               5 * time.Second,
+						TLSSetting: &configtls.TLSServerSetting{
+							TLSSetting: configtls.TLSSetting{
+								CertFile: "test.crt",
+								KeyFile:  "test.key",
+							},
 						},
-						EnforcementPolicy: &configgrpc.KeepaliveEnforcementPolicy{
-							MinTime:             10 * time.Second,
-							PermitWithoutStream: true,
+						MaxRecvMsgSizeMiB:    32,
+						MaxConcurrentStreams: 16,
+						ReadBufferSize:       1024,
+						WriteBufferSize:      1024,
+						Keepalive: &configgrpc.KeepaliveServerConfig{
+							ServerParameters: &configgrpc.KeepaliveServerParameters{
+								MaxConnectionIdle:     11 * time.Second,
+								MaxConnectionAge:      12 * time.Second,
+								MaxConnectionAgeGrace: 13 * time.Second,
+								Time:                  30 * time.Second,
+								Timeout:               5 * time.Second,
+							},
+							EnforcementPolicy: &configgrpc.KeepaliveEnforcementPolicy{
+								MinTime:             10 * time.Second,
+								PermitWithoutStream: true,
+							},
 						},
 					},
+					Health:     true,
+					Reflection: true,
 				},
 				HTTP: &HTTPConfig{
 					HTTPServerSettings: &confighttp.HTTPServerSettings{
@@ -132,8 +137,40 @@ func TestUnmarshalConfig(t *testing.T) {
 					TracesURLPath:  "/traces",
 					MetricsURLPath: "/v2/metrics",
 					LogsURLPath:    "/log/ingest",
+					Encodings:      []component.ID{component.NewID("custom_encoding")},
 				},
 			},
+			Admission: &admissionID,
+			AdmissionLimits: AdmissionLimitsConfig{
+				MaxInFlightBytes:    64 * 1024 * 1024,
+				MaxInFlightRequests: 128,
+			},
+			RateLimits: &RateLimitsConfig{
+				RequestsPerSecond: 100,
+				ItemsPerSecond:    10000,
+				ItemsBurst:        20000,
+				AuthAttribute:     "subject",
+				MaxClients:        1000,
+			},
+			ClientAttribution: &receiverhelper.ClientAttributionSettings{
+				AuthAttribute:    "subject",
+				IPv4PrefixLength: 24,
+				Allowlist:        []string{"10.0.0.0/8", "tenant-1"},
+			},
+			AccessLog: &receiverhelper.AccessLogSettings{
+				SampleEvery:         100,
+				AuthAttribute:       "subject",
+				RedactClientAddress: true,
+			},
+			Dedup: &DedupConfig{
+				Header:      "X-Request-Id",
+				Window:      5 * time.Minute,
+				MaxRequests: 100000,
+				StorageID:   &storageID,
+			},
+			Drain: receiverhelper.DrainSettings{
+				Timeout: 10 * time.Second,
+			},
 		}, cfg)
 
 }
@@ -147,22 +184,28 @@ func TestUnmarshalConfigUnix(t *testing.T) {
 	assert.Equal(t,
 		&Config{
 			Protocols: Protocols{
-				GRPC: &configgrpc.GRPCServerSettings{
-					NetAddr: confignet.NetAddr{
-						Endpoint:  "/tmp/grpc_otlp.sock",
-						Transport: "unix",
+				GRPC: &GRPCConfig{
+					GRPCServerSettings: &configgrpc.GRPCServerSettings{
+						NetAddr: confignet.NetAddr{
+							Endpoint:  "/tmp/grpc_otlp.sock",
+							Transport: "unix",
+						},
+						SocketPermissions: "0660",
+						ReadBufferSize:    512 * 1024,
 					},
-					ReadBufferSize: 512 * 1024,
 				},
 				HTTP: &HTTPConfig{
 					HTTPServerSettings: &confighttp.HTTPServerSettings{
-						Endpoint: "/tmp/http_otlp.sock",
+						Endpoint:          "/tmp/http_otlp.sock",
+						Transport:         "unix",
+						SocketPermissions: "0660",
 					},
 					TracesURLPath:  defaultTracesURLPath,
 					MetricsURLPath: defaultMetricsURLPath,
 					LogsURLPath:    defaultLogsURLPath,
 				},
 			},
+			Drain: receiverhelper.NewDefaultDrainSettings(),
 		}, cfg)
 }
 
@@ -221,6 +264,83 @@ func TestUnmarshalConfigInvalidSignalPath(t *testing.T) {
 	}
 }
 
+func TestValidateConfigAdmissionLimits(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.AdmissionLimits.MaxInFlightBytes = -1
+	assert.EqualError(t, component.ValidateConfig(cfg), "admission_limits::max_in_flight_bytes must not be negative")
+
+	cfg = factory.CreateDefaultConfig().(*Config)
+	cfg.AdmissionLimits.MaxInFlightRequests = -1
+	assert.EqualError(t, component.ValidateConfig(cfg), "admission_limits::max_in_flight_requests must not be negative")
+}
+
+func TestValidateConfigRateLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		rateLimits RateLimitsConfig
+		wantErr    string
+	}{
+		{
+			name:       "negative_rate",
+			rateLimits: RateLimitsConfig{RequestsPerSecond: -1},
+			wantErr:    "rate_limits: rate must not be negative",
+		},
+		{
+			name:       "negative_burst",
+			rateLimits: RateLimitsConfig{ItemsPerSecond: 1, ItemsBurst: -1},
+			wantErr:    "rate_limits: burst must not be negative",
+		},
+		{
+			name:       "negative_max_clients",
+			rateLimits: RateLimitsConfig{ItemsPerSecond: 1, MaxClients: -1},
+			wantErr:    "rate_limits::max_clients must not be negative",
+		},
+		{
+			name:       "no_rate",
+			rateLimits: RateLimitsConfig{RequestsBurst: 1},
+			wantErr:    "rate_limits: at least one of requests_per_second or items_per_second must be set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.RateLimits = &tt.rateLimits
+			assert.EqualError(t, component.ValidateConfig(cfg), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateConfigClientAttribution(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.ClientAttribution = &receiverhelper.ClientAttributionSettings{IPv4PrefixLength: 33}
+	assert.EqualError(t, component.ValidateConfig(cfg), "ipv4_prefix_length must be between 0 and 32")
+}
+
+func TestValidateConfigAccessLog(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.AccessLog = &receiverhelper.AccessLogSettings{SampleEvery: -1}
+	assert.EqualError(t, component.ValidateConfig(cfg), "sample_every must not be negative")
+}
+
+func TestValidateConfigDedup(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Dedup = &DedupConfig{}
+	assert.EqualError(t, component.ValidateConfig(cfg), "dedup::window must be positive")
+
+	cfg.Dedup = &DedupConfig{Window: time.Minute, MaxRequests: -1}
+	assert.EqualError(t, component.ValidateConfig(cfg), "dedup::max_requests must not be negative")
+
+	cfg.Dedup = &DedupConfig{Window: time.Minute, FlushInterval: -time.Second}
+	assert.EqualError(t, component.ValidateConfig(cfg), "dedup::flush_interval must not be negative")
+}
+
+func TestValidateConfigDrain(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Drain.Timeout = -time.Second
+	assert.EqualError(t, component.ValidateConfig(cfg), "drain timeout must not be negative")
+}
+
 func TestUnmarshalConfigEmpty(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig()
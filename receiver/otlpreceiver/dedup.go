@@ -0,0 +1,330 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// defaultDedupHeader is the default HTTP header, or gRPC metadata key, carrying the request IDs.
+	defaultDedupHeader = "Idempotency-Key"
+	// dedupStorageKey is the key of the request IDs in the storage.
+	dedupStorageKey = "request_ids"
+	// defaultDedupFlushInterval is the default interval between the persistence of the request IDs.
+	defaultDedupFlushInterval = time.Second
+)
+
+// errRequestInFlight is returned to the clients replaying a request still being processed. It is
+// retryable, so the clients retry later, when the outcome of the request is known.
+var errRequestInFlight = status.Error(codes.Unavailable, "a request with the same ID is being processed, retry later")
+
+// requestIDKey is the context key of the request IDs of the HTTP requests.
+type requestIDKey struct{}
+
+// dedupEntry is a request ID remembered until it expires.
+type dedupEntry struct {
+	Key    string `json:"key"`
+	Expiry int64  `json:"expiry"`
+}
+
+// deduplicator suppresses the replays of the requests accepted within the window, identified by the
+// request IDs supplied by the clients, so the data is not duplicated when the clients retry requests
+// that succeeded, e.g. after a network timeout. The requests without ID are never deduplicated.
+type deduplicator struct {
+	cfg    DedupConfig
+	id     component.ID
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu sync.Mutex
+	// seen are the expiries of the request IDs remembered, by signal and request ID.
+	seen map[string]time.Time
+	// order are the request IDs remembered, in the order they expire.
+	order []dedupEntry
+	// inFlight are the request IDs being processed.
+	inFlight map[string]struct{}
+	// dirty is set when a request ID is remembered, until the request IDs are persisted.
+	dirty bool
+
+	// client is nil unless the request IDs are persisted.
+	client storage.Client
+	// stopFlush stops the periodic persistence of the request IDs, flushWG waits for it to stop.
+	stopFlush chan struct{}
+	flushWG   sync.WaitGroup
+}
+
+func newDeduplicator(cfg DedupConfig, id component.ID, logger *zap.Logger) *deduplicator {
+	if cfg.Header == "" {
+		cfg.Header = defaultDedupHeader
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = defaultDedupFlushInterval
+	}
+	return &deduplicator{
+		cfg:      cfg,
+		id:       id,
+		logger:   logger,
+		now:      time.Now,
+		seen:     make(map[string]time.Time),
+		inFlight: make(map[string]struct{}),
+	}
+}
+
+// start loads the request IDs persisted by the storage extension, if any, and starts persisting them periodically.
+func (d *deduplicator) start(ctx context.Context, host component.Host) error {
+	if d.cfg.StorageID == nil {
+		return nil
+	}
+	ext, found := host.GetExtensions()[*d.cfg.StorageID]
+	if !found {
+		return fmt.Errorf("storage extension %q not found", *d.cfg.StorageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return fmt.Errorf("extension %q is not a storage extension", *d.cfg.StorageID)
+	}
+	client, err := storageExt.GetClient(ctx, component.KindReceiver, d.id, "dedup")
+	if err != nil {
+		return err
+	}
+	d.client = client
+
+	if err = d.load(ctx); err != nil {
+		return err
+	}
+	d.stopFlush = make(chan struct{})
+	d.flushWG.Add(1)
+	go d.flushPeriodically()
+	return nil
+}
+
+// load remembers the request IDs persisted and not expired yet.
+func (d *deduplicator) load(ctx context.Context) error {
+	buf, err := d.client.Get(ctx, dedupStorageKey)
+	if err != nil || buf == nil {
+		return err
+	}
+	var entries []dedupEntry
+	if err = json.Unmarshal(buf, &entries); err != nil {
+		// The request IDs are not needed to receive the data, they are forgotten.
+		d.logger.Warn("Failed to load the request IDs, replays may not be suppressed", zap.Error(err))
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	for _, e := range entries {
+		if expiry := time.Unix(0, e.Expiry); expiry.After(now) {
+			d.remember(e.Key, expiry)
+		}
+	}
+	d.dirty = false
+	return nil
+}
+
+// flushPeriodically persists the request IDs every flush interval, so at most the request IDs remembered
+// during the last interval are lost if the collector stops without shutting down, e.g. when it crashes.
+func (d *deduplicator) flushPeriodically() {
+	defer d.flushWG.Done()
+	ticker := time.NewTicker(d.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := d.flush(context.Background()); err != nil {
+				d.logger.Warn("Failed to persist the request IDs, replays may not be suppressed after a restart", zap.Error(err))
+			}
+		case <-d.stopFlush:
+			return
+		}
+	}
+}
+
+// flush persists the request IDs not expired yet, if any was remembered since they were last persisted.
+func (d *deduplicator) flush(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.dirty {
+		d.mu.Unlock()
+		return nil
+	}
+	d.expire(d.now())
+	buf, err := json.Marshal(d.order)
+	d.dirty = false
+	d.mu.Unlock()
+	if err == nil {
+		err = d.client.Set(ctx, dedupStorageKey, buf)
+	}
+	if err != nil {
+		// The request IDs are persisted again by the next flush.
+		d.mu.Lock()
+		d.dirty = true
+		d.mu.Unlock()
+	}
+	return err
+}
+
+// shutdown stops the periodic persistence and persists the request IDs not expired yet, if the storage
+// extension is set.
+func (d *deduplicator) shutdown(ctx context.Context) error {
+	if d.client == nil {
+		return nil
+	}
+	if d.stopFlush != nil {
+		close(d.stopFlush)
+		d.flushWG.Wait()
+	}
+	err := d.flush(ctx)
+	if closeErr := d.client.Close(ctx); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// requestID returns the ID supplied by the client in the HTTP header or the gRPC metadata, empty if none.
+func (d *deduplicator) requestID(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(d.cfg.Header); len(ids) > 0 {
+			return ids[0]
+		}
+	}
+	return ""
+}
+
+// httpRequestID returns a handler passing the request IDs of the HTTP requests in their context.
+func (d *deduplicator) httpRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if id := req.Header.Get(d.cfg.Header); id != "" {
+			req = req.WithContext(context.WithValue(req.Context(), requestIDKey{}, id))
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
+
+// begin returns whether the request was already accepted, or errRequestInFlight if it is being
+// processed, otherwise the request is in flight until end is called.
+func (d *deduplicator) begin(key string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expire(d.now())
+	if _, ok := d.seen[key]; ok {
+		return true, nil
+	}
+	if _, ok := d.inFlight[key]; ok {
+		return false, consumererror.NewBackpressure(errRequestInFlight, time.Second)
+	}
+	d.inFlight[key] = struct{}{}
+	return false, nil
+}
+
+// end remembers the request if it was accepted, even partially, so it is not accepted again.
+func (d *deduplicator) end(key string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.inFlight, key)
+	var partialErr consumererror.Partial
+	if err == nil || errors.As(err, &partialErr) {
+		d.remember(key, d.now().Add(d.cfg.Window))
+	}
+}
+
+// remember remembers the request ID until the expiry, forgetting the oldest request IDs above the
+// maximum. It must be called with mu held.
+func (d *deduplicator) remember(key string, expiry time.Time) {
+	if _, ok := d.seen[key]; ok {
+		return
+	}
+	d.seen[key] = expiry
+	d.dirty = true
+	d.order = append(d.order, dedupEntry{Key: key, Expiry: expiry.UnixNano()})
+	if d.cfg.MaxRequests > 0 && len(d.order) > d.cfg.MaxRequests {
+		d.forget(len(d.order) - d.cfg.MaxRequests)
+	}
+}
+
+// expire forgets the request IDs expired. It must be called with mu held.
+func (d *deduplicator) expire(now time.Time) {
+	n := 0
+	for n < len(d.order) && d.order[n].Expiry <= now.UnixNano() {
+		n++
+	}
+	d.forget(n)
+}
+
+// forget forgets the n oldest request IDs. It must be called with mu held.
+func (d *deduplicator) forget(n int) {
+	if n == 0 {
+		return
+	}
+	for _, e := range d.order[:n] {
+		delete(d.seen, e.Key)
+	}
+	d.order = append(d.order[:0], d.order[n:]...)
+}
+
+// consume calls the consume function unless the request was already accepted.
+func (d *deduplicator) consume(ctx context.Context, signal component.DataType, consume func(context.Context) error) error {
+	id := d.requestID(ctx)
+	if id == "" {
+		return consume(ctx)
+	}
+	key := string(signal) + "/" + id
+	dup, err := d.begin(key)
+	if err != nil {
+		return err
+	}
+	if dup {
+		d.logger.Debug("Suppressed the replay of a request already accepted", zap.String("request_id", id), zap.String("signal", string(signal)))
+		return nil
+	}
+	err = consume(ctx)
+	d.end(key, err)
+	return err
+}
+
+func (d *deduplicator) traces(next consumer.Traces) (consumer.Traces, error) {
+	return consumer.NewTraces(func(ctx context.Context, td ptrace.Traces) error {
+		return d.consume(ctx, component.DataTypeTraces, func(ctx context.Context) error {
+			return next.ConsumeTraces(ctx, td)
+		})
+	})
+}
+
+func (d *deduplicator) metrics(next consumer.Metrics) (consumer.Metrics, error) {
+	return consumer.NewMetrics(func(ctx context.Context, md pmetric.Metrics) error {
+		return d.consume(ctx, component.DataTypeMetrics, func(ctx context.Context) error {
+			return next.ConsumeMetrics(ctx, md)
+		})
+	})
+}
+
+func (d *deduplicator) logs(next consumer.Logs) (consumer.Logs, error) {
+	return consumer.NewLogs(func(ctx context.Context, ld plog.Logs) error {
+		return d.consume(ctx, component.DataTypeLogs, func(ctx context.Context) error {
+			return next.ConsumeLogs(ctx, ld)
+		})
+	})
+}
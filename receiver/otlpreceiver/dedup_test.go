@@ -0,0 +1,375 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/internal/testutil"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+var storageID = component.NewID("file_storage")
+
+type storageExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func (se *storageExtension) GetClient(context.Context, component.Kind, component.ID, string) (storage.Client, error) {
+	return &storageClient{ext: se}, nil
+}
+
+func (se *storageExtension) get(key string) []byte {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+	return se.data[key]
+}
+
+type storageClient struct {
+	storage.Client
+	ext *storageExtension
+}
+
+func (sc *storageClient) Get(_ context.Context, key string) ([]byte, error) {
+	return sc.ext.get(key), nil
+}
+
+func (sc *storageClient) Set(_ context.Context, key string, value []byte) error {
+	sc.ext.mu.Lock()
+	defer sc.ext.mu.Unlock()
+	sc.ext.data[key] = value
+	return nil
+}
+
+func (sc *storageClient) Close(context.Context) error {
+	return nil
+}
+
+type storageHost struct {
+	component.Host
+	ext component.Component
+}
+
+func (h *storageHost) GetExtensions() map[component.ID]component.Component {
+	return map[component.ID]component.Component{storageID: h.ext}
+}
+
+func newTestDeduplicator(cfg DedupConfig, now *time.Time) *deduplicator {
+	d := newDeduplicator(cfg, otlpReceiverID, zap.NewNop())
+	d.now = func() time.Time { return *now }
+	return d
+}
+
+func requestIDContext(id string) context.Context {
+	return context.WithValue(context.Background(), requestIDKey{}, id)
+}
+
+func TestDeduplicatorWindow(t *testing.T) {
+	now := time.Now()
+	d := newTestDeduplicator(DedupConfig{Window: time.Minute}, &now)
+	var calls int
+	consume := func(context.Context) error {
+		calls++
+		return nil
+	}
+
+	require.NoError(t, d.consume(requestIDContext("a"), component.DataTypeTraces, consume))
+	require.NoError(t, d.consume(requestIDContext("a"), component.DataTypeTraces, consume))
+	assert.Equal(t, 1, calls)
+
+	// The request IDs are specific to each signal, and the requests without ID are never deduplicated.
+	require.NoError(t, d.consume(requestIDContext("a"), component.DataTypeLogs, consume))
+	require.NoError(t, d.consume(context.Background(), component.DataTypeTraces, consume))
+	require.NoError(t, d.consume(context.Background(), component.DataTypeTraces, consume))
+	assert.Equal(t, 4, calls)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, d.consume(requestIDContext("a"), component.DataTypeTraces, consume))
+	assert.Equal(t, 5, calls)
+}
+
+func TestDeduplicatorMaxRequests(t *testing.T) {
+	now := time.Now()
+	d := newTestDeduplicator(DedupConfig{Window: time.Minute, MaxRequests: 2}, &now)
+	var calls int
+	consume := func(context.Context) error {
+		calls++
+		return nil
+	}
+
+	for _, id := range []string{"a", "b", "c", "b", "c"} {
+		require.NoError(t, d.consume(requestIDContext(id), component.DataTypeTraces, consume))
+	}
+	assert.Equal(t, 3, calls)
+	// The oldest request ID was forgotten.
+	require.NoError(t, d.consume(requestIDContext("a"), component.DataTypeTraces, consume))
+	assert.Equal(t, 4, calls)
+}
+
+func TestDeduplicatorFailedRequests(t *testing.T) {
+	now := time.Now()
+	d := newTestDeduplicator(DedupConfig{Window: time.Minute}, &now)
+	var calls int
+	fail := func(err error) func(context.Context) error {
+		return func(context.Context) error {
+			calls++
+			return err
+		}
+	}
+
+	// The requests refused are accepted again, the requests partially accepted are not.
+	errRefused := errors.New("refused")
+	assert.Equal(t, errRefused, d.consume(requestIDContext("a"), component.DataTypeTraces, fail(errRefused)))
+	require.NoError(t, d.consume(requestIDContext("a"), component.DataTypeTraces, fail(nil)))
+	assert.Equal(t, 2, calls)
+
+	errPartial := consumererror.NewPartial(errRefused, 1)
+	assert.Equal(t, errPartial, d.consume(requestIDContext("b"), component.DataTypeTraces, fail(errPartial)))
+	require.NoError(t, d.consume(requestIDContext("b"), component.DataTypeTraces, fail(nil)))
+	assert.Equal(t, 3, calls)
+}
+
+func TestDeduplicatorInFlight(t *testing.T) {
+	now := time.Now()
+	d := newTestDeduplicator(DedupConfig{Window: time.Minute}, &now)
+
+	var replayErr error
+	require.NoError(t, d.consume(requestIDContext("a"), component.DataTypeTraces, func(ctx context.Context) error {
+		replayErr = d.consume(ctx, component.DataTypeTraces, func(context.Context) error { return nil })
+		return nil
+	}))
+	assert.ErrorIs(t, replayErr, errRequestInFlight)
+	var bpErr consumererror.Backpressure
+	require.ErrorAs(t, replayErr, &bpErr)
+	assert.Equal(t, time.Second, bpErr.RetryAfter())
+}
+
+func TestDeduplicatorStorage(t *testing.T) {
+	now := time.Now()
+	ext := &storageExtension{data: map[string][]byte{}}
+	host := &storageHost{Host: componenttest.NewNopHost(), ext: ext}
+	id := storageID
+	cfg := DedupConfig{Window: time.Minute, StorageID: &id}
+	consume := func(context.Context) error { return nil }
+
+	d := newTestDeduplicator(cfg, &now)
+	require.NoError(t, d.start(context.Background(), host))
+	require.NoError(t, d.consume(requestIDContext("a"), component.DataTypeTraces, consume))
+	now = now.Add(30 * time.Second)
+	require.NoError(t, d.consume(requestIDContext("b"), component.DataTypeTraces, consume))
+	require.NoError(t, d.shutdown(context.Background()))
+
+	// The request IDs expired when restarting are forgotten.
+	now = now.Add(45 * time.Second)
+	d = newTestDeduplicator(cfg, &now)
+	require.NoError(t, d.start(context.Background(), host))
+	assert.Equal(t, []string{"traces/b"}, dedupKeys(d))
+}
+
+func TestDeduplicatorStorageWithoutShutdown(t *testing.T) {
+	now := time.Now()
+	ext := &storageExtension{data: map[string][]byte{}}
+	host := &storageHost{Host: componenttest.NewNopHost(), ext: ext}
+	id := storageID
+	cfg := DedupConfig{Window: time.Minute, StorageID: &id, FlushInterval: time.Millisecond}
+	consume := func(context.Context) error { return nil }
+
+	d := newTestDeduplicator(cfg, &now)
+	require.NoError(t, d.start(context.Background(), host))
+	require.NoError(t, d.consume(requestIDContext("a"), component.DataTypeTraces, consume))
+	assert.Eventually(t, func() bool { return ext.get(dedupStorageKey) != nil }, time.Second, time.Millisecond)
+
+	// The request IDs persisted periodically are restored, even though the previous collector did not shut down.
+	restarted := newTestDeduplicator(cfg, &now)
+	require.NoError(t, restarted.start(context.Background(), host))
+	t.Cleanup(func() {
+		require.NoError(t, restarted.shutdown(context.Background()))
+		require.NoError(t, d.shutdown(context.Background()))
+	})
+	dup, err := restarted.begin("traces/a")
+	require.NoError(t, err)
+	assert.True(t, dup)
+}
+
+func dedupKeys(d *deduplicator) []string {
+	var keys []string
+	for _, e := range d.order {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+func TestDedupInvalidStorage(t *testing.T) {
+	id := storageID
+	tests := []struct {
+		name    string
+		host    component.Host
+		wantErr string
+	}{
+		{
+			name:    "not_found",
+			host:    componenttest.NewNopHost(),
+			wantErr: `storage extension "file_storage" not found`,
+		},
+		{
+			name: "not_storage",
+			host: &storageHost{Host: componenttest.NewNopHost(), ext: &struct {
+				component.StartFunc
+				component.ShutdownFunc
+			}{}},
+			wantErr: `extension "file_storage" is not a storage extension`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDeduplicator(DedupConfig{Window: time.Minute, StorageID: &id}, otlpReceiverID, zap.NewNop())
+			assert.EqualError(t, d.start(context.Background(), tt.host), tt.wantErr)
+		})
+	}
+}
+
+func TestGRPCDedup(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.TracesSink)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	cfg.Dedup = &DedupConfig{Window: time.Minute}
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	export := func(id string) error {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), strings.ToLower(defaultDedupHeader), id)
+		return exportTracesContext(ctx, cc, testdata.GenerateTraces(1))
+	}
+	require.NoError(t, export("a"))
+	require.NoError(t, export("a"))
+	require.NoError(t, export("b"))
+	assert.Equal(t, 2, sink.SpanCount())
+}
+
+func TestGRPCDedupInFlight(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	release := make(chan struct{})
+	consumed := make(chan struct{})
+	sink := &blockingTracesSink{release: release, consumed: consumed}
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	cfg.Dedup = &DedupConfig{Window: time.Minute}
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), strings.ToLower(defaultDedupHeader), "a")
+	done := make(chan error, 1)
+	go func() {
+		done <- exportTracesContext(ctx, cc, testdata.GenerateTraces(1))
+	}()
+	<-consumed
+	err = exportTracesContext(ctx, cc, testdata.GenerateTraces(1))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	close(release)
+	require.NoError(t, <-done)
+}
+
+// blockingTracesSink blocks consuming until released.
+type blockingTracesSink struct {
+	consumertest.TracesSink
+	release  chan struct{}
+	consumed chan struct{}
+}
+
+func (s *blockingTracesSink) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	close(s.consumed)
+	<-s.release
+	return s.TracesSink.ConsumeTraces(ctx, td)
+}
+
+func exportTracesContext(ctx context.Context, cc *grpc.ClientConn, td ptrace.Traces) error {
+	_, err := ptraceotlp.NewGRPCClient(cc).Export(ctx, ptraceotlp.NewExportRequestFromTraces(td))
+	return err
+}
+
+func TestHTTPDedup(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	url := fmt.Sprintf("http://%s/v1/traces", addr)
+	sink := new(consumertest.TracesSink)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	cfg.Dedup = &DedupConfig{Header: "X-Request-Id", Window: time.Minute}
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	send := func(id string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(traceJSON))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", jsonContentType)
+		if id != "" {
+			req.Header.Set("X-Request-Id", id)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	assert.Equal(t, http.StatusOK, send("a").StatusCode)
+	assert.Equal(t, http.StatusOK, send("a").StatusCode)
+	assert.Equal(t, 2, sink.SpanCount())
+	assert.Equal(t, http.StatusOK, send("").StatusCode)
+	assert.Equal(t, http.StatusOK, send("").StatusCode)
+	assert.Equal(t, 6, sink.SpanCount())
+}
@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+)
+
+// grpcDrain returns the gRPC server option tracking the requests in flight, so they are processed
+// before the receiver shuts down, and refusing the new requests with the UNAVAILABLE status code
+// while draining.
+func grpcDrain(drainer *receiverhelper.Drainer) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !drainer.Begin() {
+			return nil, status.Error(codes.Unavailable, receiverhelper.ErrDraining.Error())
+		}
+		defer drainer.End()
+		return handler(ctx, req)
+	})
+}
+
+// httpDrain returns a handler tracking the requests in flight, so they are processed before the
+// receiver shuts down, and refusing the new requests with the 503 Service Unavailable status code
+// while draining.
+func httpDrain(drainer *receiverhelper.Drainer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !drainer.Begin() {
+			errorHandler(resp, req, receiverhelper.ErrDraining.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		defer drainer.End()
+		next.ServeHTTP(resp, req)
+	})
+}
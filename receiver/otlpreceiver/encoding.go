@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/encoding"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+// getEncodings returns the encoders of the encoding extensions with the given IDs, by the content
+// type of their payloads.
+func getEncodings(host component.Host, encodingIDs []component.ID) (map[string]encoder, error) {
+	encoders := make(map[string]encoder, len(encodingIDs))
+	for _, id := range encodingIDs {
+		ext, found := host.GetExtensions()[id]
+		if !found {
+			return nil, fmt.Errorf("encoding extension %q not found", id)
+		}
+		encodingExt, ok := ext.(encoding.Extension)
+		if !ok {
+			return nil, fmt.Errorf("extension %q is not an encoding extension", id)
+		}
+		contentType := getMimeTypeFromContentType(encodingExt.ContentType())
+		if contentType == "" {
+			return nil, fmt.Errorf("invalid content type %q of the encoding extension %q", encodingExt.ContentType(), id)
+		}
+		if _, ok := encoders[contentType]; ok || contentType == pbContentType || contentType == jsonContentType {
+			return nil, fmt.Errorf("content type %q of the encoding extension %q is already used", contentType, id)
+		}
+		encoders[contentType] = extensionEncoder{ext: encodingExt, id: id}
+	}
+	return encoders, nil
+}
+
+// extensionEncoder decodes the requests with an encoding extension. The responses are encoded with
+// the OTLP protobuf encoding.
+type extensionEncoder struct {
+	protoEncoder
+	ext encoding.Extension
+	id  component.ID
+}
+
+func (e extensionEncoder) unmarshalTracesRequest(buf []byte) (ptraceotlp.ExportRequest, error) {
+	u, ok := e.ext.(encoding.TracesUnmarshalerExtension)
+	if !ok {
+		return ptraceotlp.NewExportRequest(), fmt.Errorf("encoding extension %q does not decode traces", e.id)
+	}
+	td, err := u.UnmarshalTraces(buf)
+	if err != nil {
+		return ptraceotlp.NewExportRequest(), err
+	}
+	return ptraceotlp.NewExportRequestFromTraces(td), nil
+}
+
+func (e extensionEncoder) unmarshalMetricsRequest(buf []byte) (pmetricotlp.ExportRequest, error) {
+	u, ok := e.ext.(encoding.MetricsUnmarshalerExtension)
+	if !ok {
+		return pmetricotlp.NewExportRequest(), fmt.Errorf("encoding extension %q does not decode metrics", e.id)
+	}
+	md, err := u.UnmarshalMetrics(buf)
+	if err != nil {
+		return pmetricotlp.NewExportRequest(), err
+	}
+	return pmetricotlp.NewExportRequestFromMetrics(md), nil
+}
+
+func (e extensionEncoder) unmarshalLogsRequest(buf []byte) (plogotlp.ExportRequest, error) {
+	u, ok := e.ext.(encoding.LogsUnmarshalerExtension)
+	if !ok {
+		return plogotlp.NewExportRequest(), fmt.Errorf("encoding extension %q does not decode logs", e.id)
+	}
+	ld, err := u.UnmarshalLogs(buf)
+	if err != nil {
+		return plogotlp.NewExportRequest(), err
+	}
+	return plogotlp.NewExportRequestFromLogs(ld), nil
+}
@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/internal/testutil"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+var encodingID = component.NewID("test_encoding")
+
+// tracesEncodingExtension encodes the traces with the JSON encoding, under its own content type.
+type tracesEncodingExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	ptrace.JSONMarshaler
+	ptrace.JSONUnmarshaler
+	contentType string
+}
+
+func (e *tracesEncodingExtension) ContentType() string {
+	return e.contentType
+}
+
+type encodingHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *encodingHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func TestHTTPEncodingExtension(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	tracesSink := new(consumertest.TracesSink)
+	metricsSink := new(consumertest.MetricsSink)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.HTTP.Endpoint = addr
+	cfg.HTTP.Encodings = []component.ID{encodingID}
+	cfg.GRPC = nil
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, tracesSink, metricsSink)
+
+	ext := &tracesEncodingExtension{contentType: "application/x-test"}
+	host := &encodingHost{Host: componenttest.NewNopHost(), extensions: map[component.ID]component.Component{encodingID: ext}}
+	require.NoError(t, ocr.Start(context.Background(), host))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	send := func(path string, contentType string, body []byte) (*http.Response, []byte) {
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s%s", addr, path), bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp, respBody
+	}
+
+	td := testdata.GenerateTraces(2)
+	body, err := ext.MarshalTraces(td)
+	require.NoError(t, err)
+	resp, respBody := send(defaultTracesURLPath, "application/x-test; charset=utf-8", body)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	// The responses use the OTLP protobuf encoding.
+	assert.Equal(t, pbContentType, resp.Header.Get("Content-Type"))
+	assert.NoError(t, ptraceotlp.NewExportResponse().UnmarshalProto(respBody))
+	require.Len(t, tracesSink.AllTraces(), 1)
+	assert.Equal(t, td, tracesSink.AllTraces()[0])
+
+	// The signals not supported by the extension are refused.
+	metricsBody, err := (&pmetric.JSONMarshaler{}).MarshalMetrics(testdata.GenerateMetrics(1))
+	require.NoError(t, err)
+	resp, _ = send(defaultMetricsURLPath, "application/x-test", metricsBody)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Empty(t, metricsSink.AllMetrics())
+
+	resp, _ = send(defaultTracesURLPath, "application/x-unknown", body)
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+}
+
+func TestGetEncodings(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions map[component.ID]component.Component
+		ids        []component.ID
+		wantErr    string
+	}{
+		{
+			name:    "not_found",
+			ids:     []component.ID{encodingID},
+			wantErr: `encoding extension "test_encoding" not found`,
+		},
+		{
+			name: "not_encoding",
+			extensions: map[component.ID]component.Component{encodingID: &struct {
+				component.StartFunc
+				component.ShutdownFunc
+			}{}},
+			ids:     []component.ID{encodingID},
+			wantErr: `extension "test_encoding" is not an encoding extension`,
+		},
+		{
+			name:       "invalid_content_type",
+			extensions: map[component.ID]component.Component{encodingID: &tracesEncodingExtension{}},
+			ids:        []component.ID{encodingID},
+			wantErr:    `invalid content type "" of the encoding extension "test_encoding"`,
+		},
+		{
+			name:       "otlp_content_type",
+			extensions: map[component.ID]component.Component{encodingID: &tracesEncodingExtension{contentType: "application/json"}},
+			ids:        []component.ID{encodingID},
+			wantErr:    `content type "application/json" of the encoding extension "test_encoding" is already used`,
+		},
+		{
+			name: "duplicate_content_type",
+			extensions: map[component.ID]component.Component{
+				encodingID:                        &tracesEncodingExtension{contentType: "application/x-test"},
+				component.NewID("other_encoding"): &tracesEncodingExtension{contentType: "application/x-test"},
+			},
+			ids:     []component.ID{encodingID, component.NewID("other_encoding")},
+			wantErr: `content type "application/x-test" of the encoding extension "other_encoding" is already used`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host := &encodingHost{Host: componenttest.NewNopHost(), extensions: tt.extensions}
+			_, err := getEncodings(host, tt.ids)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
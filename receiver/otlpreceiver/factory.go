@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/internal/sharedcomponent"
 	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
 const (
@@ -40,13 +41,15 @@ func NewFactory() receiver.Factory {
 func createDefaultConfig() component.Config {
 	return &Config{
 		Protocols: Protocols{
-			GRPC: &configgrpc.GRPCServerSettings{
-				NetAddr: confignet.NetAddr{
-					Endpoint:  defaultGRPCEndpoint,
-					Transport: "tcp",
+			GRPC: &GRPCConfig{
+				GRPCServerSettings: &configgrpc.GRPCServerSettings{
+					NetAddr: confignet.NetAddr{
+						Endpoint:  defaultGRPCEndpoint,
+						Transport: "tcp",
+					},
+					// We almost write 0 bytes, so no need to tune WriteBufferSize.
+					ReadBufferSize: 512 * 1024,
 				},
-				// We almost write 0 bytes, so no need to tune WriteBufferSize.
-				ReadBufferSize: 512 * 1024,
 			},
 			HTTP: &HTTPConfig{
 				HTTPServerSettings: &confighttp.HTTPServerSettings{
@@ -57,6 +60,7 @@ func createDefaultConfig() component.Config {
 				LogsURLPath:    defaultLogsURLPath,
 			},
 		},
+		Drain: receiverhelper.NewDefaultDrainSettings(),
 	}
 }
 
@@ -79,7 +83,9 @@ func createTraces(
 		return nil, err
 	}
 
-	if err = r.Unwrap().registerTraceConsumer(nextConsumer); err != nil {
+	if err = r.Register(func(rcv *otlpReceiver) error {
+		return rcv.registerTraceConsumer(set, nextConsumer)
+	}); err != nil {
 		return nil, err
 	}
 	return r, nil
@@ -104,7 +110,9 @@ func createMetrics(
 		return nil, err
 	}
 
-	if err = r.Unwrap().registerMetricsConsumer(consumer); err != nil {
+	if err = r.Register(func(rcv *otlpReceiver) error {
+		return rcv.registerMetricsConsumer(set, consumer)
+	}); err != nil {
 		return nil, err
 	}
 	return r, nil
@@ -129,7 +137,9 @@ func createLog(
 		return nil, err
 	}
 
-	if err = r.Unwrap().registerLogsConsumer(consumer); err != nil {
+	if err = r.Register(func(rcv *otlpReceiver) error {
+		return rcv.registerLogsConsumer(set, consumer)
+	}); err != nil {
 		return nil, err
 	}
 	return r, nil
@@ -45,10 +45,12 @@ func TestCreateReceiver(t *testing.T) {
 
 func TestCreateTracesReceiver(t *testing.T) {
 	factory := NewFactory()
-	defaultGRPCSettings := &configgrpc.GRPCServerSettings{
-		NetAddr: confignet.NetAddr{
-			Endpoint:  testutil.GetAvailableLocalAddress(t),
-			Transport: "tcp",
+	defaultGRPCSettings := &GRPCConfig{
+		GRPCServerSettings: &configgrpc.GRPCServerSettings{
+			NetAddr: confignet.NetAddr{
+				Endpoint:  testutil.GetAvailableLocalAddress(t),
+				Transport: "tcp",
+			},
 		},
 	}
 	defaultHTTPSettings := &HTTPConfig{
@@ -78,10 +80,12 @@ func TestCreateTracesReceiver(t *testing.T) {
 			name: "invalid_grpc_port",
 			cfg: &Config{
 				Protocols: Protocols{
-					GRPC: &configgrpc.GRPCServerSettings{
-						NetAddr: confignet.NetAddr{
-							Endpoint:  "localhost:112233",
-							Transport: "tcp",
+					GRPC: &GRPCConfig{
+						GRPCServerSettings: &configgrpc.GRPCServerSettings{
+							NetAddr: confignet.NetAddr{
+								Endpoint:  "localhost:112233",
+								Transport: "tcp",
+							},
 						},
 					},
 					HTTP: defaultHTTPSettings,
@@ -126,10 +130,12 @@ func TestCreateTracesReceiver(t *testing.T) {
 
 func TestCreateMetricReceiver(t *testing.T) {
 	factory := NewFactory()
-	defaultGRPCSettings := &configgrpc.GRPCServerSettings{
-		NetAddr: confignet.NetAddr{
-			Endpoint:  testutil.GetAvailableLocalAddress(t),
-			Transport: "tcp",
+	defaultGRPCSettings := &GRPCConfig{
+		GRPCServerSettings: &configgrpc.GRPCServerSettings{
+			NetAddr: confignet.NetAddr{
+				Endpoint:  testutil.GetAvailableLocalAddress(t),
+				Transport: "tcp",
+			},
 		},
 	}
 	defaultHTTPSettings := &HTTPConfig{
@@ -159,10 +165,12 @@ func TestCreateMetricReceiver(t *testing.T) {
 			name: "invalid_grpc_address",
 			cfg: &Config{
 				Protocols: Protocols{
-					GRPC: &configgrpc.GRPCServerSettings{
-						NetAddr: confignet.NetAddr{
-							Endpoint:  "327.0.0.1:1122",
-							Transport: "tcp",
+					GRPC: &GRPCConfig{
+						GRPCServerSettings: &configgrpc.GRPCServerSettings{
+							NetAddr: confignet.NetAddr{
+								Endpoint:  "327.0.0.1:1122",
+								Transport: "tcp",
+							},
 						},
 					},
 					HTTP: defaultHTTPSettings,
@@ -206,10 +214,12 @@ func TestCreateMetricReceiver(t *testing.T) {
 
 func TestCreateLogReceiver(t *testing.T) {
 	factory := NewFactory()
-	defaultGRPCSettings := &configgrpc.GRPCServerSettings{
-		NetAddr: confignet.NetAddr{
-			Endpoint:  testutil.GetAvailableLocalAddress(t),
-			Transport: "tcp",
+	defaultGRPCSettings := &GRPCConfig{
+		GRPCServerSettings: &configgrpc.GRPCServerSettings{
+			NetAddr: confignet.NetAddr{
+				Endpoint:  testutil.GetAvailableLocalAddress(t),
+				Transport: "tcp",
+			},
 		},
 	}
 	defaultHTTPSettings := &HTTPConfig{
@@ -242,10 +252,12 @@ func TestCreateLogReceiver(t *testing.T) {
 			name: "invalid_grpc_address",
 			cfg: &Config{
 				Protocols: Protocols{
-					GRPC: &configgrpc.GRPCServerSettings{
-						NetAddr: confignet.NetAddr{
-							Endpoint:  "327.0.0.1:1122",
-							Transport: "tcp",
+					GRPC: &GRPCConfig{
+						GRPCServerSettings: &configgrpc.GRPCServerSettings{
+							NetAddr: confignet.NetAddr{
+								Endpoint:  "327.0.0.1:1122",
+								Transport: "tcp",
+							},
 						},
 					},
 					HTTP: defaultHTTPSettings,
@@ -316,3 +328,29 @@ func TestCreateLogReceiver(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateSharedReceiverSignalErrors(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = "327.0.0.1:1122"
+	cfg.HTTP.Endpoint = testutil.GetAvailableLocalAddress(t)
+	creationSet := receivertest.NewNopCreateSettings()
+
+	_, err := factory.CreateTracesReceiver(context.Background(), creationSet, cfg, nil)
+	require.Error(t, err)
+
+	// The failure registering another signal does not affect the signals registered.
+	tReceiver, err := factory.CreateTracesReceiver(context.Background(), creationSet, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	_, err = factory.CreateMetricsReceiver(context.Background(), creationSet, cfg, nil)
+	require.Error(t, err)
+	lReceiver, err := factory.CreateLogsReceiver(context.Background(), creationSet, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.Same(t, tReceiver, lReceiver)
+
+	// The error starting the shared receiver is returned for each signal.
+	assert.Error(t, tReceiver.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Error(t, lReceiver.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, tReceiver.Shutdown(context.Background()))
+	assert.NoError(t, lReceiver.Shutdown(context.Background()))
+}
@@ -0,0 +1,29 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// registerGRPCServices registers the health checking and server reflection services enabled by the
+// configuration on the gRPC server, once the OTLP services are registered. It returns the health
+// server reporting the OTLP services as serving, nil if the health checking service is not enabled.
+func registerGRPCServices(server *grpc.Server, cfg *GRPCConfig) *health.Server {
+	var healthServer *health.Server
+	if cfg.Health {
+		healthServer = health.NewServer()
+		for name := range server.GetServiceInfo() {
+			healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+		}
+		healthpb.RegisterHealthServer(server, healthServer)
+	}
+	if cfg.Reflection {
+		reflection.Register(server)
+	}
+	return healthServer
+}
@@ -5,8 +5,10 @@ package logs // import "go.opentelemetry.io/collector/receiver/otlpreceiver/inte
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
@@ -40,5 +42,13 @@ func (r *Receiver) Export(ctx context.Context, req plogotlp.ExportRequest) (plog
 	err := r.nextConsumer.ConsumeLogs(ctx, ld)
 	r.obsreport.EndLogsOp(ctx, dataFormatProtobuf, numSpans, err)
 
+	// A partial error is reported to the client as a partial success, so the accepted data is not sent again.
+	var partialErr consumererror.Partial
+	if errors.As(err, &partialErr) {
+		resp := plogotlp.NewExportResponse()
+		resp.PartialSuccess().SetRejectedLogRecords(int64(partialErr.Rejected()))
+		resp.PartialSuccess().SetErrorMessage(err.Error())
+		return resp, nil
+	}
 	return plogotlp.NewExportResponse(), err
 }
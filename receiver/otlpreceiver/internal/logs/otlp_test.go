@@ -16,6 +16,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
@@ -57,6 +58,16 @@ func TestExport_ErrorConsumer(t *testing.T) {
 	assert.Equal(t, plogotlp.ExportResponse{}, resp)
 }
 
+func TestExport_PartialErrorConsumer(t *testing.T) {
+	req := plogotlp.NewExportRequestFromLogs(testdata.GenerateLogs(2))
+
+	logClient := makeLogsServiceClient(t, consumertest.NewErr(consumererror.NewPartial(errors.New("my error"), 1)))
+	resp, err := logClient.Export(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.PartialSuccess().RejectedLogRecords())
+	assert.Equal(t, "my error", resp.PartialSuccess().ErrorMessage())
+}
+
 func makeLogsServiceClient(t *testing.T, lc consumer.Logs) plogotlp.GRPCClient {
 	addr := otlpReceiverOnGRPCServer(t, lc)
 	cc, err := grpc.Dial(addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
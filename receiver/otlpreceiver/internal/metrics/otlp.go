@@ -5,8 +5,10 @@ package metrics // import "go.opentelemetry.io/collector/receiver/otlpreceiver/i
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
@@ -40,5 +42,13 @@ func (r *Receiver) Export(ctx context.Context, req pmetricotlp.ExportRequest) (p
 	err := r.nextConsumer.ConsumeMetrics(ctx, md)
 	r.obsreport.EndMetricsOp(ctx, dataFormatProtobuf, dataPointCount, err)
 
+	// A partial error is reported to the client as a partial success, so the accepted data is not sent again.
+	var partialErr consumererror.Partial
+	if errors.As(err, &partialErr) {
+		resp := pmetricotlp.NewExportResponse()
+		resp.PartialSuccess().SetRejectedDataPoints(int64(partialErr.Rejected()))
+		resp.PartialSuccess().SetErrorMessage(err.Error())
+		return resp, nil
+	}
 	return pmetricotlp.NewExportResponse(), err
 }
@@ -16,6 +16,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
@@ -57,6 +58,16 @@ func TestExport_ErrorConsumer(t *testing.T) {
 	assert.Equal(t, pmetricotlp.ExportResponse{}, resp)
 }
 
+func TestExport_PartialErrorConsumer(t *testing.T) {
+	req := pmetricotlp.NewExportRequestFromMetrics(testdata.GenerateMetrics(2))
+
+	metricsClient := makeMetricsServiceClient(t, consumertest.NewErr(consumererror.NewPartial(errors.New("my error"), 1)))
+	resp, err := metricsClient.Export(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.PartialSuccess().RejectedDataPoints())
+	assert.Equal(t, "my error", resp.PartialSuccess().ErrorMessage())
+}
+
 func makeMetricsServiceClient(t *testing.T, mc consumer.Metrics) pmetricotlp.GRPCClient {
 	addr := otlpReceiverOnGRPCServer(t, mc)
 
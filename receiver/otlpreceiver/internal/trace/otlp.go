@@ -5,8 +5,10 @@ package trace // import "go.opentelemetry.io/collector/receiver/otlpreceiver/int
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
@@ -41,5 +43,13 @@ func (r *Receiver) Export(ctx context.Context, req ptraceotlp.ExportRequest) (pt
 	err := r.nextConsumer.ConsumeTraces(ctx, td)
 	r.obsreport.EndTracesOp(ctx, dataFormatProtobuf, numSpans, err)
 
+	// A partial error is reported to the client as a partial success, so the accepted data is not sent again.
+	var partialErr consumererror.Partial
+	if errors.As(err, &partialErr) {
+		resp := ptraceotlp.NewExportResponse()
+		resp.PartialSuccess().SetRejectedSpans(int64(partialErr.Rejected()))
+		resp.PartialSuccess().SetErrorMessage(err.Error())
+		return resp, nil
+	}
 	return ptraceotlp.NewExportResponse(), err
 }
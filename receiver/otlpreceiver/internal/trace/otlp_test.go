@@ -16,6 +16,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
@@ -55,6 +56,16 @@ func TestExport_ErrorConsumer(t *testing.T) {
 	assert.Equal(t, ptraceotlp.ExportResponse{}, resp)
 }
 
+func TestExport_PartialErrorConsumer(t *testing.T) {
+	req := ptraceotlp.NewExportRequestFromTraces(testdata.GenerateTraces(2))
+
+	traceClient := makeTraceServiceClient(t, consumertest.NewErr(consumererror.NewPartial(errors.New("my error"), 1)))
+	resp, err := traceClient.Export(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.PartialSuccess().RejectedSpans())
+	assert.Equal(t, "my error", resp.PartialSuccess().ErrorMessage())
+}
+
 func makeTraceServiceClient(t *testing.T, tc consumer.Traces) ptraceotlp.GRPCClient {
 	addr := otlpReceiverOnGRPCServer(t, tc)
 	cc, err := grpc.Dial(addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
@@ -11,13 +11,16 @@ import (
 	"net/http"
 	"sync"
 
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/admission"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
@@ -32,6 +35,8 @@ import (
 type otlpReceiver struct {
 	cfg        *Config
 	serverGRPC *grpc.Server
+	// healthGRPC is nil unless the gRPC health checking service is served.
+	healthGRPC *health.Server
 	httpMux    *http.ServeMux
 	serverHTTP *http.Server
 
@@ -43,6 +48,15 @@ type otlpReceiver struct {
 	obsrepGRPC *receiverhelper.ObsReport
 	obsrepHTTP *receiverhelper.ObsReport
 
+	// rateLimiter is nil when the clients are not rate limited.
+	rateLimiter *rateLimiter
+	// deduplicator is nil when the replays of the requests are not suppressed.
+	deduplicator *deduplicator
+	// drainer tracks the requests in flight, processed for a bounded time on shutdown.
+	drainer *receiverhelper.Drainer
+	// httpEncoders are the encoders of the encoding extensions by content type, set when the receiver is started.
+	httpEncoders map[string]encoder
+
 	settings *receiver.CreateSettings
 }
 
@@ -52,31 +66,52 @@ type otlpReceiver struct {
 func newOtlpReceiver(cfg *Config, set *receiver.CreateSettings) (*otlpReceiver, error) {
 	r := &otlpReceiver{
 		cfg:      cfg,
+		drainer:  receiverhelper.NewDrainer(cfg.Drain, *set),
 		settings: set,
 	}
 	if cfg.HTTP != nil {
 		r.httpMux = http.NewServeMux()
 	}
+	if cfg.RateLimits != nil {
+		r.rateLimiter = newRateLimiter(cfg.RateLimits)
+	}
+	if cfg.Dedup != nil {
+		r.deduplicator = newDeduplicator(*cfg.Dedup, set.ID, set.Logger)
+	}
 
 	var err error
-	r.obsrepGRPC, err = receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+	if r.obsrepGRPC, r.obsrepHTTP, err = r.newObsReports(*set); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// newObsReports returns the ObsReports of the gRPC and HTTP transports for the given settings. The
+// receiver shared by the pipelines of several signals reports the data of each signal with the
+// settings of its pipelines, e.g. logging with their logger.
+func (r *otlpReceiver) newObsReports(set receiver.CreateSettings) (*receiverhelper.ObsReport, *receiverhelper.ObsReport, error) {
+	obsrepGRPC, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
 		ReceiverID:             set.ID,
 		Transport:              "grpc",
-		ReceiverCreateSettings: *set,
+		ReceiverCreateSettings: set,
+		ClientAttribution:      r.cfg.ClientAttribution,
+		AccessLog:              r.cfg.AccessLog,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	r.obsrepHTTP, err = receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+	obsrepHTTP, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
 		ReceiverID:             set.ID,
 		Transport:              "http",
-		ReceiverCreateSettings: *set,
+		ReceiverCreateSettings: set,
+		ClientAttribution:      r.cfg.ClientAttribution,
+		AccessLog:              r.cfg.AccessLog,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	return r, nil
+	return obsrepGRPC, obsrepHTTP, nil
 }
 
 func (r *otlpReceiver) startGRPCServer(cfg *configgrpc.GRPCServerSettings, host component.Host) error {
@@ -116,9 +151,25 @@ func (r *otlpReceiver) startHTTPServer(cfg *confighttp.HTTPServerSettings, host
 }
 
 func (r *otlpReceiver) startProtocolServers(host component.Host) error {
+	var admissionExt admission.Extension
+	if r.cfg.Admission != nil {
+		var err error
+		if admissionExt, err = getAdmission(host, *r.cfg.Admission); err != nil {
+			return err
+		}
+	}
+
+	limiter := newAdmissionLimiter(r.cfg.AdmissionLimits)
+
 	var err error
 	if r.cfg.GRPC != nil {
-		r.serverGRPC, err = r.cfg.GRPC.ToServer(host, r.settings.TelemetrySettings)
+		// TODO: Decode the requests with the encoding extensions negotiated with the gRPC content-subtype,
+		// see the "Supported components" of the encoding extension README.
+		opts := []grpc.ServerOption{grpcDrain(r.drainer), grpcRequestSize(r.obsrepGRPC), grpcBackpressure()}
+		if admissionExt != nil || limiter != nil {
+			opts = append(opts, grpcAdmission(admissionExt, limiter)...)
+		}
+		r.serverGRPC, err = r.cfg.GRPC.ToServer(host, r.settings.TelemetrySettings, opts...)
 		if err != nil {
 			return err
 		}
@@ -135,16 +186,34 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 			plogotlp.RegisterGRPCServer(r.serverGRPC, r.logsReceiver)
 		}
 
-		err = r.startGRPCServer(r.cfg.GRPC, host)
+		r.healthGRPC = registerGRPCServices(r.serverGRPC, r.cfg.GRPC)
+
+		err = r.startGRPCServer(r.cfg.GRPC.GRPCServerSettings, host)
 		if err != nil {
 			return err
 		}
 	}
 	if r.cfg.HTTP != nil {
+		if len(r.cfg.HTTP.Encodings) > 0 {
+			if r.httpEncoders, err = getEncodings(host, r.cfg.HTTP.Encodings); err != nil {
+				return err
+			}
+		}
+		var handler http.Handler = r.httpMux
+		if admissionExt != nil || limiter != nil {
+			handler = httpAdmission(admissionExt, limiter, handler)
+		}
+		if r.cfg.HTTP.MaxRequestBodySize > 0 || (r.cfg.HTTP.Decompression != nil && r.cfg.HTTP.Decompression.MaxDecompressedSize > 0) {
+			handler = httpRequestSize(r.obsrepHTTP, handler)
+		}
+		if r.deduplicator != nil {
+			handler = r.deduplicator.httpRequestID(handler)
+		}
+		handler = httpDrain(r.drainer, handler)
 		r.serverHTTP, err = r.cfg.HTTP.ToServer(
 			host,
 			r.settings.TelemetrySettings,
-			r.httpMux,
+			handler,
 			confighttp.WithErrorHandler(errorHandler),
 		)
 		if err != nil {
@@ -162,101 +231,192 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 
 // Start runs the trace receiver on the gRPC server. Currently
 // it also enables the metrics receiver too.
-func (r *otlpReceiver) Start(_ context.Context, host component.Host) error {
+func (r *otlpReceiver) Start(ctx context.Context, host component.Host) error {
+	if r.deduplicator != nil {
+		if err := r.deduplicator.start(ctx, host); err != nil {
+			return err
+		}
+	}
 	return r.startProtocolServers(host)
 }
 
 // Shutdown is a method to turn off receiving.
 func (r *otlpReceiver) Shutdown(ctx context.Context) error {
-	var err error
-
+	// Stop accepting new connections, and let the servers process the requests already accepted
+	// while draining. The health checks report the services as not serving from now on.
+	if r.healthGRPC != nil {
+		r.healthGRPC.Shutdown()
+	}
+	stopCtx, cancel := context.WithCancel(context.Background())
+	var stopWG sync.WaitGroup
 	if r.serverHTTP != nil {
-		err = r.serverHTTP.Shutdown(ctx)
+		stopWG.Add(1)
+		go func() {
+			defer stopWG.Done()
+			// The error is the cancellation of the context when the requests are cut off.
+			_ = r.serverHTTP.Shutdown(stopCtx)
+		}()
 	}
-
+	grpcStopped := make(chan struct{})
 	if r.serverGRPC != nil {
-		r.serverGRPC.GracefulStop()
+		go func() {
+			defer close(grpcStopped)
+			r.serverGRPC.GracefulStop()
+		}()
+	} else {
+		close(grpcStopped)
+	}
+
+	cutOff := r.drainer.Drain(ctx)
+	cancel()
+	stopWG.Wait()
+
+	// Close the connections, cutting off the requests still in flight.
+	var err error
+	if r.serverHTTP != nil {
+		err = r.serverHTTP.Close()
+	}
+	if r.serverGRPC != nil && cutOff > 0 {
+		r.serverGRPC.Stop()
 	}
+	<-grpcStopped
 
 	r.shutdownWG.Wait()
+	if r.deduplicator != nil {
+		err = multierr.Append(err, r.deduplicator.shutdown(ctx))
+	}
 	return err
 }
 
-func (r *otlpReceiver) registerTraceConsumer(tc consumer.Traces) error {
+func (r *otlpReceiver) registerTraceConsumer(set receiver.CreateSettings, tc consumer.Traces) error {
 	if tc == nil {
 		return component.ErrNilNextConsumer
 	}
-	r.tracesReceiver = trace.New(tc, r.obsrepGRPC)
-	httpTracesReceiver := trace.New(tc, r.obsrepHTTP)
+	if r.rateLimiter != nil {
+		var err error
+		if tc, err = r.rateLimiter.traces(tc); err != nil {
+			return err
+		}
+	}
+	if r.deduplicator != nil {
+		var err error
+		if tc, err = r.deduplicator.traces(tc); err != nil {
+			return err
+		}
+	}
+	obsrepGRPC, obsrepHTTP, err := r.newObsReports(set)
+	if err != nil {
+		return err
+	}
+	r.tracesReceiver = trace.New(tc, obsrepGRPC)
+	httpTracesReceiver := trace.New(tc, obsrepHTTP)
 	if r.httpMux != nil {
 		r.httpMux.HandleFunc(r.cfg.HTTP.TracesURLPath, func(resp http.ResponseWriter, req *http.Request) {
 			if req.Method != http.MethodPost {
 				handleUnmatchedMethod(resp)
 				return
 			}
-			switch getMimeTypeFromContentType(req.Header.Get("Content-Type")) {
-			case pbContentType:
-				handleTraces(resp, req, httpTracesReceiver, pbEncoder)
-			case jsonContentType:
-				handleTraces(resp, req, httpTracesReceiver, jsEncoder)
-			default:
+			enc := r.httpEncoder(req.Header.Get("Content-Type"))
+			if enc == nil {
 				handleUnmatchedContentType(resp)
+				return
 			}
+			handleTraces(resp, req, httpTracesReceiver, enc)
 		})
 	}
 	return nil
 }
 
-func (r *otlpReceiver) registerMetricsConsumer(mc consumer.Metrics) error {
+func (r *otlpReceiver) registerMetricsConsumer(set receiver.CreateSettings, mc consumer.Metrics) error {
 	if mc == nil {
 		return component.ErrNilNextConsumer
 	}
-	r.metricsReceiver = metrics.New(mc, r.obsrepGRPC)
-	httpMetricsReceiver := metrics.New(mc, r.obsrepHTTP)
+	if r.rateLimiter != nil {
+		var err error
+		if mc, err = r.rateLimiter.metrics(mc); err != nil {
+			return err
+		}
+	}
+	if r.deduplicator != nil {
+		var err error
+		if mc, err = r.deduplicator.metrics(mc); err != nil {
+			return err
+		}
+	}
+	obsrepGRPC, obsrepHTTP, err := r.newObsReports(set)
+	if err != nil {
+		return err
+	}
+	r.metricsReceiver = metrics.New(mc, obsrepGRPC)
+	httpMetricsReceiver := metrics.New(mc, obsrepHTTP)
 	if r.httpMux != nil {
 		r.httpMux.HandleFunc(r.cfg.HTTP.MetricsURLPath, func(resp http.ResponseWriter, req *http.Request) {
 			if req.Method != http.MethodPost {
 				handleUnmatchedMethod(resp)
 				return
 			}
-			switch getMimeTypeFromContentType(req.Header.Get("Content-Type")) {
-			case pbContentType:
-				handleMetrics(resp, req, httpMetricsReceiver, pbEncoder)
-			case jsonContentType:
-				handleMetrics(resp, req, httpMetricsReceiver, jsEncoder)
-			default:
+			enc := r.httpEncoder(req.Header.Get("Content-Type"))
+			if enc == nil {
 				handleUnmatchedContentType(resp)
+				return
 			}
+			handleMetrics(resp, req, httpMetricsReceiver, enc)
 		})
 	}
 	return nil
 }
 
-func (r *otlpReceiver) registerLogsConsumer(lc consumer.Logs) error {
+func (r *otlpReceiver) registerLogsConsumer(set receiver.CreateSettings, lc consumer.Logs) error {
 	if lc == nil {
 		return component.ErrNilNextConsumer
 	}
-	r.logsReceiver = logs.New(lc, r.obsrepGRPC)
-	httpLogsReceiver := logs.New(lc, r.obsrepHTTP)
+	if r.rateLimiter != nil {
+		var err error
+		if lc, err = r.rateLimiter.logs(lc); err != nil {
+			return err
+		}
+	}
+	if r.deduplicator != nil {
+		var err error
+		if lc, err = r.deduplicator.logs(lc); err != nil {
+			return err
+		}
+	}
+	obsrepGRPC, obsrepHTTP, err := r.newObsReports(set)
+	if err != nil {
+		return err
+	}
+	r.logsReceiver = logs.New(lc, obsrepGRPC)
+	httpLogsReceiver := logs.New(lc, obsrepHTTP)
 	if r.httpMux != nil {
 		r.httpMux.HandleFunc(r.cfg.HTTP.LogsURLPath, func(resp http.ResponseWriter, req *http.Request) {
 			if req.Method != http.MethodPost {
 				handleUnmatchedMethod(resp)
 				return
 			}
-			switch getMimeTypeFromContentType(req.Header.Get("Content-Type")) {
-			case pbContentType:
-				handleLogs(resp, req, httpLogsReceiver, pbEncoder)
-			case jsonContentType:
-				handleLogs(resp, req, httpLogsReceiver, jsEncoder)
-			default:
+			enc := r.httpEncoder(req.Header.Get("Content-Type"))
+			if enc == nil {
 				handleUnmatchedContentType(resp)
+				return
 			}
+			handleLogs(resp, req, httpLogsReceiver, enc)
 		})
 	}
 	return nil
 }
 
+// httpEncoder returns the encoder of the content type of an HTTP request, nil if it is not supported.
+func (r *otlpReceiver) httpEncoder(contentType string) encoder {
+	switch mimeType := getMimeTypeFromContentType(contentType); mimeType {
+	case pbContentType:
+		return pbEncoder
+	case jsonContentType:
+		return jsEncoder
+	default:
+		return r.httpEncoders[mimeType]
+	}
+}
+
 func handleUnmatchedMethod(resp http.ResponseWriter) {
 	status := http.StatusMethodNotAllowed
 	writeResponse(resp, "text/plain", status, []byte(fmt.Sprintf("%v method not allowed, supported: [POST]", status)))
@@ -13,6 +13,9 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
@@ -20,6 +23,7 @@ import (
 	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -35,6 +39,7 @@ import (
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/internal/testutil"
@@ -682,6 +687,56 @@ func TestHTTPNewPortAlreadyUsed(t *testing.T) {
 	require.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
 }
 
+func TestUnixDomainSockets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	dir := t.TempDir()
+	grpcSocket := filepath.Join(dir, "grpc.sock")
+	httpSocket := filepath.Join(dir, "http.sock")
+	sink := new(consumertest.TracesSink)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr = confignet.NetAddr{Endpoint: grpcSocket, Transport: "unix"}
+	cfg.GRPC.SocketPermissions = "0660"
+	cfg.HTTP.Endpoint = httpSocket
+	cfg.HTTP.Transport = "unix"
+	cfg.HTTP.SocketPermissions = "0660"
+	r := newReceiver(t, factory, cfg, otlpReceiverID, sink, nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	for _, socket := range []string{grpcSocket, httpSocket} {
+		fi, err := os.Stat(socket)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0660), fi.Mode().Perm())
+	}
+
+	cc, err := grpc.Dial("unix://"+grpcSocket, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+	require.NoError(t, exportTraces(cc, testdata.GenerateTraces(1)))
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", httpSocket)
+			},
+		},
+	}
+	resp, err := client.Post("http://localhost"+defaultTracesURLPath, jsonContentType, bytes.NewReader(traceJSON))
+	require.NoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, 3, sink.SpanCount())
+}
+
 // TestOTLPReceiverGRPCTracesIngestTest checks that the gRPC trace receiver
 // is returning the proper response (return and metrics) when the next consumer
 // in the pipeline reports error. The test changes the responses returned by the
@@ -827,17 +882,154 @@ func TestOTLPReceiverHTTPTracesIngestTest(t *testing.T) {
 	require.NoError(t, tt.CheckReceiverTraces("http", int64(expectedReceivedBatches), int64(expectedIngestionBlockedRPCs)))
 }
 
+func TestOTLPReceiverHTTPBackpressure(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := &errOrSinkConsumer{TracesSink: new(consumertest.TracesSink)}
+
+	ocr := newHTTPReceiver(t, addr, defaultTracesURLPath, defaultMetricsURLPath, defaultLogsURLPath, sink, nil)
+	require.NotNil(t, ocr)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	tests := []struct {
+		name           string
+		err            error
+		retryAfter     time.Duration
+		wantStatusCode int
+		wantCode       codes.Code
+		wantRetryAfter string
+	}{
+		{
+			name:           "retry_after",
+			err:            errors.New("queue is full"),
+			retryAfter:     2500 * time.Millisecond,
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantCode:       codes.Unavailable,
+			wantRetryAfter: "3",
+		},
+		{
+			name:           "retry_after_below_one_second",
+			err:            errors.New("queue is full"),
+			retryAfter:     100 * time.Millisecond,
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantCode:       codes.Unavailable,
+			wantRetryAfter: "1",
+		},
+		{
+			name:           "unknown_retry_after",
+			err:            errors.New("queue is full"),
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantCode:       codes.Unavailable,
+			wantRetryAfter: "",
+		},
+		{
+			name:           "rate_limited",
+			err:            fmt.Errorf("data refused: %w", consumererror.ErrRateLimited),
+			retryAfter:     1500 * time.Millisecond,
+			wantStatusCode: http.StatusTooManyRequests,
+			wantCode:       codes.ResourceExhausted,
+			wantRetryAfter: "2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink.SetConsumeError(consumererror.NewBackpressure(tt.err, tt.retryAfter))
+
+			pbBytes, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(testdata.GenerateTraces(1))
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPost, "http://"+addr+defaultTracesURLPath, bytes.NewReader(pbBytes))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", pbContentType)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			respBytes, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			assert.Equal(t, tt.wantStatusCode, resp.StatusCode)
+			assert.Equal(t, tt.wantRetryAfter, resp.Header.Get("Retry-After"))
+			errStatus := &spb.Status{}
+			require.NoError(t, proto.Unmarshal(respBytes, errStatus))
+			assert.Equal(t, tt.wantCode, codes.Code(errStatus.Code))
+			assert.Equal(t, tt.err.Error(), errStatus.Message)
+		})
+	}
+}
+
+func TestOTLPReceiverGRPCBackpressure(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := &errOrSinkConsumer{TracesSink: new(consumertest.TracesSink)}
+
+	ocr := newGRPCReceiver(t, addr, sink, nil)
+	require.NotNil(t, ocr)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	tests := []struct {
+		name           string
+		err            error
+		retryAfter     time.Duration
+		wantCode       codes.Code
+		wantRetryDelay time.Duration
+	}{
+		{
+			name:           "retry_after",
+			err:            errors.New("queue is full"),
+			retryAfter:     2500 * time.Millisecond,
+			wantCode:       codes.Unavailable,
+			wantRetryDelay: 2500 * time.Millisecond,
+		},
+		{
+			name:     "unknown_retry_after",
+			err:      errors.New("queue is full"),
+			wantCode: codes.Unavailable,
+		},
+		{
+			name:           "rate_limited",
+			err:            fmt.Errorf("data refused: %w", consumererror.ErrRateLimited),
+			retryAfter:     1500 * time.Millisecond,
+			wantCode:       codes.ResourceExhausted,
+			wantRetryDelay: 1500 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink.SetConsumeError(consumererror.NewBackpressure(tt.err, tt.retryAfter))
+
+			_, err := ptraceotlp.NewGRPCClient(cc).Export(context.Background(), ptraceotlp.NewExportRequestFromTraces(testdata.GenerateTraces(1)))
+			st := status.Convert(err)
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.Equal(t, tt.err.Error(), st.Message())
+			var retryDelay time.Duration
+			for _, detail := range st.Details() {
+				if retryInfo, ok := detail.(*errdetails.RetryInfo); ok {
+					retryDelay = retryInfo.RetryDelay.AsDuration()
+				}
+			}
+			assert.Equal(t, tt.wantRetryDelay, retryDelay)
+		})
+	}
+}
+
 func TestGRPCInvalidTLSCredentials(t *testing.T) {
 	cfg := &Config{
 		Protocols: Protocols{
-			GRPC: &configgrpc.GRPCServerSettings{
-				NetAddr: confignet.NetAddr{
-					Endpoint:  testutil.GetAvailableLocalAddress(t),
-					Transport: "tcp",
-				},
-				TLSSetting: &configtls.TLSServerSetting{
-					TLSSetting: configtls.TLSSetting{
-						CertFile: "willfail",
+			GRPC: &GRPCConfig{
+				GRPCServerSettings: &configgrpc.GRPCServerSettings{
+					NetAddr: confignet.NetAddr{
+						Endpoint:  testutil.GetAvailableLocalAddress(t),
+						Transport: "tcp",
+					},
+					TLSSetting: &configtls.TLSServerSetting{
+						TLSSetting: configtls.TLSSetting{
+							CertFile: "willfail",
+						},
 					},
 				},
 			},
@@ -973,7 +1165,7 @@ func TestHTTPMaxRequestBodySize_OK(t *testing.T) {
 }
 
 func TestHTTPMaxRequestBodySize_TooLarge(t *testing.T) {
-	testHTTPMaxRequestBodySizeJSON(t, traceJSON, len(traceJSON)-1, 400)
+	testHTTPMaxRequestBodySizeJSON(t, traceJSON, len(traceJSON)-1, http.StatusRequestEntityTooLarge)
 }
 
 func newGRPCReceiver(t *testing.T, endpoint string, tc consumer.Traces, mc consumer.Metrics) component.Component {
@@ -1128,6 +1320,104 @@ func TestShutdown(t *testing.T) {
 	assert.EqualValues(t, sinkSpanCountAfterShutdown, nextSink.SpanCount())
 }
 
+// blockingTracesConsumer returns a consumer signaling on started when it receives traces, then blocking
+// until release is closed.
+func blockingTracesConsumer(t *testing.T, started chan<- struct{}, release <-chan struct{}) consumer.Traces {
+	tc, err := consumer.NewTraces(func(context.Context, ptrace.Traces) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+	require.NoError(t, err)
+	return tc
+}
+
+func TestShutdownDrain(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		// cutOff is true if the requests are still in flight at the end of the drain timeout.
+		cutOff bool
+	}{
+		{
+			name:    "drained",
+			timeout: 10 * time.Second,
+		},
+		{
+			name:    "cut_off",
+			timeout: 50 * time.Millisecond,
+			cutOff:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpointGrpc := testutil.GetAvailableLocalAddress(t)
+			endpointHTTP := testutil.GetAvailableLocalAddress(t)
+			started := make(chan struct{})
+			release := make(chan struct{})
+			var releaseOnce sync.Once
+			releaseFn := func() { releaseOnce.Do(func() { close(release) }) }
+			t.Cleanup(releaseFn)
+
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.GRPC.NetAddr.Endpoint = endpointGrpc
+			cfg.HTTP.Endpoint = endpointHTTP
+			cfg.Drain.Timeout = tt.timeout
+			r := newReceiver(t, factory, cfg, otlpReceiverID, blockingTracesConsumer(t, started, release), nil)
+			require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+			conn, err := grpc.Dial(endpointGrpc, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+			require.NoError(t, err)
+			defer conn.Close()
+
+			grpcErr := make(chan error, 1)
+			go func() {
+				grpcErr <- exportTraces(conn, testdata.GenerateTraces(1))
+			}()
+			<-started
+
+			traceBytes, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(testdata.GenerateTraces(1))
+			require.NoError(t, err)
+			httpStatus := make(chan int, 1)
+			go func() {
+				req := createHTTPProtobufRequest(t, fmt.Sprintf("http://%s/v1/traces", endpointHTTP), "", traceBytes)
+				resp, errHTTP := http.DefaultClient.Do(req)
+				if errHTTP != nil {
+					httpStatus <- 0
+					return
+				}
+				httpStatus <- resp.StatusCode
+				assert.NoError(t, resp.Body.Close())
+			}()
+			<-started
+
+			shutdownErr := make(chan error, 1)
+			go func() {
+				shutdownErr <- r.Shutdown(context.Background())
+			}()
+			if !tt.cutOff {
+				// The requests in flight are processed while draining.
+				select {
+				case <-shutdownErr:
+					t.Fatal("shutdown completed before the requests in flight were processed")
+				case <-time.After(50 * time.Millisecond):
+				}
+				releaseFn()
+			}
+			require.NoError(t, <-shutdownErr)
+
+			if tt.cutOff {
+				assert.Equal(t, codes.Unavailable, status.Code(<-grpcErr))
+				assert.Equal(t, 0, <-httpStatus)
+				return
+			}
+			assert.NoError(t, <-grpcErr)
+			assert.Equal(t, http.StatusOK, <-httpStatus)
+		})
+	}
+}
+
 func generateTraces(senderFn senderFunc, doneSignal chan bool) {
 	// Continuously generate spans until signaled to stop.
 loop:
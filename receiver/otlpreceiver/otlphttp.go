@@ -4,14 +4,17 @@
 package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
 
 import (
+	"errors"
 	"io"
 	"mime"
 	"net/http"
+	"strconv"
 
 	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/logs"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/metrics"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/trace"
@@ -36,7 +39,7 @@ func handleTraces(resp http.ResponseWriter, req *http.Request, tracesReceiver *t
 
 	otlpResp, err := tracesReceiver.Export(req.Context(), otlpReq)
 	if err != nil {
-		writeError(resp, encoder, err, http.StatusInternalServerError)
+		writeExportError(resp, encoder, err)
 		return
 	}
 
@@ -62,7 +65,7 @@ func handleMetrics(resp http.ResponseWriter, req *http.Request, metricsReceiver
 
 	otlpResp, err := metricsReceiver.Export(req.Context(), otlpReq)
 	if err != nil {
-		writeError(resp, encoder, err, http.StatusInternalServerError)
+		writeExportError(resp, encoder, err)
 		return
 	}
 
@@ -88,7 +91,7 @@ func handleLogs(resp http.ResponseWriter, req *http.Request, logsReceiver *logs.
 
 	otlpResp, err := logsReceiver.Export(req.Context(), otlpReq)
 	if err != nil {
-		writeError(resp, encoder, err, http.StatusInternalServerError)
+		writeExportError(resp, encoder, err)
 		return
 	}
 
@@ -102,6 +105,10 @@ func handleLogs(resp http.ResponseWriter, req *http.Request, logsReceiver *logs.
 
 func readAndCloseBody(resp http.ResponseWriter, req *http.Request, encoder encoder) ([]byte, bool) {
 	body, err := io.ReadAll(req.Body)
+	if isRequestTooLarge(err) {
+		writeError(resp, encoder, err, http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
 	if err != nil {
 		writeError(resp, encoder, err, http.StatusBadRequest)
 		return nil, false
@@ -113,6 +120,32 @@ func readAndCloseBody(resp http.ResponseWriter, req *http.Request, encoder encod
 	return body, true
 }
 
+// writeExportError writes the error returned by the pipeline, with the 429 Too Many Requests status code
+// and the Retry-After header when the client exceeds its rate limits, or the rate limits of the pipeline,
+// or the 503 Service Unavailable status code and the Retry-After header, if known, when the pipeline is
+// overloaded, so the clients back off before retrying.
+func writeExportError(w http.ResponseWriter, encoder encoder, err error) {
+	var rlErr *rateLimitError
+	if errors.As(err, &rlErr) {
+		w.Header().Set("Retry-After", strconv.Itoa(rlErr.retryAfterSeconds()))
+		writeError(w, encoder, err, http.StatusTooManyRequests)
+		return
+	}
+	var bpErr consumererror.Backpressure
+	if errors.As(err, &bpErr) {
+		if bpErr.RetryAfter() > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(bpErr.RetryAfter())))
+		}
+		statusCode := http.StatusServiceUnavailable
+		if errors.Is(err, consumererror.ErrRateLimited) {
+			statusCode = http.StatusTooManyRequests
+		}
+		writeError(w, encoder, err, statusCode)
+		return
+	}
+	writeError(w, encoder, err, http.StatusInternalServerError)
+}
+
 // writeError encodes the HTTP error inside a rpc.Status message as required by the OTLP protocol.
 func writeError(w http.ResponseWriter, encoder encoder, err error, statusCode int) {
 	s, ok := status.FromError(err)
@@ -158,6 +191,12 @@ func errorMsgToStatus(errMsg string, statusCode int) *status.Status {
 	if statusCode == http.StatusBadRequest {
 		return status.New(codes.InvalidArgument, errMsg)
 	}
+	if statusCode == http.StatusTooManyRequests || statusCode == http.StatusRequestEntityTooLarge {
+		return status.New(codes.ResourceExhausted, errMsg)
+	}
+	if statusCode == http.StatusServiceUnavailable {
+		return status.New(codes.Unavailable, errMsg)
+	}
 	return status.New(codes.Unknown, errMsg)
 }
 
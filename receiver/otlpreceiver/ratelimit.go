@@ -0,0 +1,277 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// sweepInterval is the interval between the removals of the idle clients.
+const sweepInterval = time.Minute
+
+// rateLimitError is returned to the clients exceeding their rate limits. It is retryable, with the
+// RESOURCE_EXHAUSTED gRPC status code or the 429 Too Many Requests HTTP status code, and hints the
+// clients to retry after the delay needed to be within the limits again.
+type rateLimitError struct {
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded, retry after %s", e.retryAfter)
+}
+
+// GRPCStatus returns the status of the error, with the retry delay as a RetryInfo detail.
+func (e *rateLimitError) GRPCStatus() *status.Status {
+	st := status.New(codes.ResourceExhausted, e.Error())
+	if withDetails, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(e.retryAfter)}); err == nil {
+		return withDetails
+	}
+	return st
+}
+
+// retryAfterSeconds returns the value of the Retry-After HTTP header, in seconds, at least 1.
+func (e *rateLimitError) retryAfterSeconds() int {
+	return retryAfterSeconds(e.retryAfter)
+}
+
+// retryAfterSeconds returns the delay as the value of the Retry-After HTTP header, in seconds rounded up,
+// at least 1.
+func retryAfterSeconds(delay time.Duration) int {
+	return int(math.Max(1, math.Ceil(delay.Seconds())))
+}
+
+// tokenBucket implements the token bucket algorithm. The tokens are never reserved in advance: the data
+// is refused if not enough tokens are available.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	b := float64(burst)
+	if b == 0 {
+		b = math.Ceil(rate)
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  b,
+		tokens: b,
+		last:   now,
+	}
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (tb *tokenBucket) refill(now time.Time) {
+	tb.tokens = math.Min(tb.burst, tb.tokens+now.Sub(tb.last).Seconds()*tb.rate)
+	tb.last = now
+}
+
+// wait returns the delay until n tokens are available, 0 if they are. Data larger than the burst is
+// allowed when the bucket is full, so it is not refused forever.
+func (tb *tokenBucket) wait(n float64) time.Duration {
+	missing := math.Min(n, tb.burst) - tb.tokens
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(missing / tb.rate * float64(time.Second))
+}
+
+func (tb *tokenBucket) take(n float64) {
+	tb.tokens -= math.Min(n, tb.burst)
+}
+
+func (tb *tokenBucket) full() bool {
+	return tb == nil || tb.tokens >= tb.burst
+}
+
+// clientLimiter holds the token buckets of a client.
+type clientLimiter struct {
+	requests *tokenBucket
+	items    *tokenBucket
+}
+
+// rateLimiter applies the rate limits separately to each client, across all the protocols and signals.
+type rateLimiter struct {
+	cfg *RateLimitsConfig
+
+	// now is overridable by tests.
+	now func() time.Time
+
+	// mu guards clients, overflow and lastSweep.
+	mu      sync.Mutex
+	clients map[string]*clientLimiter
+	// overflow is shared by the clients over the limit of the clients.
+	overflow  *clientLimiter
+	lastSweep time.Time
+}
+
+func newRateLimiter(cfg *RateLimitsConfig) *rateLimiter {
+	now := time.Now
+	return &rateLimiter{
+		cfg:       cfg,
+		now:       now,
+		clients:   map[string]*clientLimiter{},
+		lastSweep: now(),
+	}
+}
+
+// clientKey returns the identity of the client of the request: the configured auth attribute if set by
+// the authenticator, otherwise the IP address of the client.
+func (rl *rateLimiter) clientKey(ctx context.Context) string {
+	info := client.FromContext(ctx)
+	if rl.cfg.AuthAttribute != "" && info.Auth != nil {
+		if v := info.Auth.GetAttribute(rl.cfg.AuthAttribute); v != nil {
+			return fmt.Sprintf("auth:%v", v)
+		}
+	}
+	return "addr:" + addrHost(info.Addr)
+}
+
+// addrHost returns the host of the address, without the port that changes between the connections.
+func addrHost(addr net.Addr) string {
+	switch a := addr.(type) {
+	case nil:
+		return ""
+	case *net.TCPAddr:
+		return a.IP.String()
+	case *net.IPAddr:
+		return a.IP.String()
+	}
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+	return addr.String()
+}
+
+func (rl *rateLimiter) newClientLimiter(now time.Time) *clientLimiter {
+	cl := &clientLimiter{}
+	if rl.cfg.RequestsPerSecond > 0 {
+		cl.requests = newTokenBucket(rl.cfg.RequestsPerSecond, rl.cfg.RequestsBurst, now)
+	}
+	if rl.cfg.ItemsPerSecond > 0 {
+		cl.items = newTokenBucket(rl.cfg.ItemsPerSecond, rl.cfg.ItemsBurst, now)
+	}
+	return cl
+}
+
+// getClient returns the limiter of the client, creating it if needed. It must be called with mu held.
+func (rl *rateLimiter) getClient(key string, now time.Time) *clientLimiter {
+	if cl, ok := rl.clients[key]; ok {
+		return cl
+	}
+	atLimit := rl.cfg.MaxClients > 0 && len(rl.clients) >= rl.cfg.MaxClients
+	if atLimit && now.Sub(rl.lastSweep) >= time.Second {
+		rl.sweep(now)
+		atLimit = len(rl.clients) >= rl.cfg.MaxClients
+	}
+	if atLimit {
+		if rl.overflow == nil {
+			rl.overflow = rl.newClientLimiter(now)
+		}
+		return rl.overflow
+	}
+	cl := rl.newClientLimiter(now)
+	rl.clients[key] = cl
+	return cl
+}
+
+// sweep removes the clients whose buckets are full, which behave like new clients. It must be called
+// with mu held.
+func (rl *rateLimiter) sweep(now time.Time) {
+	for key, cl := range rl.clients {
+		if cl.requests != nil {
+			cl.requests.refill(now)
+		}
+		if cl.items != nil {
+			cl.items.refill(now)
+		}
+		if cl.requests.full() && cl.items.full() {
+			delete(rl.clients, key)
+		}
+	}
+	rl.lastSweep = now
+}
+
+// allow returns nil if a request of the client with the given number of items is within the limits,
+// taking the tokens if so, or a rateLimitError otherwise.
+func (rl *rateLimiter) allow(ctx context.Context, items int) error {
+	key := rl.clientKey(ctx)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	if now.Sub(rl.lastSweep) >= sweepInterval {
+		rl.sweep(now)
+	}
+	cl := rl.getClient(key, now)
+	var retryAfter time.Duration
+	if cl.requests != nil {
+		cl.requests.refill(now)
+		if d := cl.requests.wait(1); d > retryAfter {
+			retryAfter = d
+		}
+	}
+	if cl.items != nil {
+		cl.items.refill(now)
+		if d := cl.items.wait(float64(items)); d > retryAfter {
+			retryAfter = d
+		}
+	}
+	if retryAfter > 0 {
+		return &rateLimitError{retryAfter: retryAfter}
+	}
+	if cl.requests != nil {
+		cl.requests.take(1)
+	}
+	if cl.items != nil {
+		cl.items.take(float64(items))
+	}
+	return nil
+}
+
+func (rl *rateLimiter) traces(next consumer.Traces) (consumer.Traces, error) {
+	return consumer.NewTraces(func(ctx context.Context, td ptrace.Traces) error {
+		if err := rl.allow(ctx, td.SpanCount()); err != nil {
+			return err
+		}
+		return next.ConsumeTraces(ctx, td)
+	})
+}
+
+func (rl *rateLimiter) metrics(next consumer.Metrics) (consumer.Metrics, error) {
+	return consumer.NewMetrics(func(ctx context.Context, md pmetric.Metrics) error {
+		if err := rl.allow(ctx, md.DataPointCount()); err != nil {
+			return err
+		}
+		return next.ConsumeMetrics(ctx, md)
+	})
+}
+
+func (rl *rateLimiter) logs(next consumer.Logs) (consumer.Logs, error) {
+	return consumer.NewLogs(func(ctx context.Context, ld plog.Logs) error {
+		if err := rl.allow(ctx, ld.LogRecordCount()); err != nil {
+			return err
+		}
+		return next.ConsumeLogs(ctx, ld)
+	})
+}
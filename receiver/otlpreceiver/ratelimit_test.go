@@ -0,0 +1,202 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/internal/testutil"
+)
+
+type authData map[string]any
+
+func (a authData) GetAttribute(name string) any {
+	return a[name]
+}
+
+func (a authData) GetAttributeNames() []string {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	return names
+}
+
+func clientContext(ip string, auth client.AuthData) context.Context {
+	return client.NewContext(context.Background(), client.Info{
+		Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 12345},
+		Auth: auth,
+	})
+}
+
+func newTestRateLimiter(cfg *RateLimitsConfig, now *time.Time) *rateLimiter {
+	rl := newRateLimiter(cfg)
+	rl.now = func() time.Time { return *now }
+	rl.lastSweep = *now
+	return rl
+}
+
+func TestRateLimiterRequests(t *testing.T) {
+	now := time.Now()
+	rl := newTestRateLimiter(&RateLimitsConfig{RequestsPerSecond: 2}, &now)
+	ctx := clientContext("10.0.0.1", nil)
+
+	require.NoError(t, rl.allow(ctx, 1000))
+	require.NoError(t, rl.allow(ctx, 1000))
+	err := rl.allow(ctx, 1000)
+	var rlErr *rateLimitError
+	require.ErrorAs(t, err, &rlErr)
+	assert.Equal(t, 500*time.Millisecond, rlErr.retryAfter)
+	assert.Equal(t, 1, rlErr.retryAfterSeconds())
+
+	// The other clients have their own limits, whatever their port.
+	require.NoError(t, rl.allow(clientContext("10.0.0.2", nil), 1))
+
+	now = now.Add(500 * time.Millisecond)
+	require.NoError(t, rl.allow(ctx, 1))
+	assert.Error(t, rl.allow(ctx, 1))
+}
+
+func TestRateLimiterItems(t *testing.T) {
+	now := time.Now()
+	rl := newTestRateLimiter(&RateLimitsConfig{ItemsPerSecond: 100, ItemsBurst: 200}, &now)
+	ctx := clientContext("10.0.0.1", nil)
+
+	require.NoError(t, rl.allow(ctx, 150))
+	err := rl.allow(ctx, 100)
+	var rlErr *rateLimitError
+	require.ErrorAs(t, err, &rlErr)
+	assert.Equal(t, 500*time.Millisecond, rlErr.retryAfter)
+
+	// A refused request takes no tokens.
+	require.NoError(t, rl.allow(ctx, 50))
+
+	// Requests larger than the burst are accepted when the bucket is full.
+	now = now.Add(2 * time.Second)
+	require.NoError(t, rl.allow(ctx, 1000))
+	err = rl.allow(ctx, 1000)
+	require.ErrorAs(t, err, &rlErr)
+	assert.Equal(t, 2*time.Second, rlErr.retryAfter)
+	assert.Equal(t, 2, rlErr.retryAfterSeconds())
+}
+
+func TestRateLimiterAuthAttribute(t *testing.T) {
+	now := time.Now()
+	rl := newTestRateLimiter(&RateLimitsConfig{RequestsPerSecond: 1, AuthAttribute: "subject"}, &now)
+
+	// The clients are identified by the auth attribute, whatever their address.
+	require.NoError(t, rl.allow(clientContext("10.0.0.1", authData{"subject": "tenant-1"}), 1))
+	assert.Error(t, rl.allow(clientContext("10.0.0.2", authData{"subject": "tenant-1"}), 1))
+	require.NoError(t, rl.allow(clientContext("10.0.0.2", authData{"subject": "tenant-2"}), 1))
+
+	// The clients without the auth attribute are identified by their address.
+	require.NoError(t, rl.allow(clientContext("10.0.0.1", authData{}), 1))
+	assert.Error(t, rl.allow(clientContext("10.0.0.1", nil), 1))
+}
+
+func TestRateLimiterMaxClients(t *testing.T) {
+	now := time.Now()
+	rl := newTestRateLimiter(&RateLimitsConfig{RequestsPerSecond: 1, MaxClients: 2}, &now)
+
+	require.NoError(t, rl.allow(clientContext("10.0.0.1", nil), 1))
+	require.NoError(t, rl.allow(clientContext("10.0.0.2", nil), 1))
+	// The clients over the limit share the same limits.
+	require.NoError(t, rl.allow(clientContext("10.0.0.3", nil), 1))
+	assert.Error(t, rl.allow(clientContext("10.0.0.4", nil), 1))
+	assert.Len(t, rl.clients, 2)
+
+	// The idle clients are removed, making room for the new ones.
+	now = now.Add(time.Second)
+	require.NoError(t, rl.allow(clientContext("10.0.0.4", nil), 1))
+	assert.Len(t, rl.clients, 1)
+	assert.Contains(t, rl.clients, "addr:10.0.0.4")
+
+	// The idle clients are also removed periodically.
+	now = now.Add(sweepInterval)
+	require.NoError(t, rl.allow(clientContext("10.0.0.5", nil), 1))
+	assert.Len(t, rl.clients, 1)
+	assert.Contains(t, rl.clients, "addr:10.0.0.5")
+}
+
+func TestGRPCRateLimits(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.TracesSink)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	cfg.RateLimits = &RateLimitsConfig{ItemsPerSecond: 0.1, ItemsBurst: 1}
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	require.NoError(t, exportTraces(cc, testdata.GenerateTraces(1)))
+	err = exportTraces(cc, testdata.GenerateTraces(1))
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.Greater(t, retryInfo.RetryDelay.AsDuration(), 9*time.Second)
+	assert.Equal(t, 1, sink.SpanCount())
+}
+
+func TestHTTPRateLimits(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	url := fmt.Sprintf("http://%s/v1/traces", addr)
+	sink := new(consumertest.TracesSink)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	cfg.RateLimits = &RateLimitsConfig{RequestsPerSecond: 0.1, RequestsBurst: 1}
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	send := func() *http.Response {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(traceJSON))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", jsonContentType)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	assert.Equal(t, http.StatusOK, send().StatusCode)
+	resp := send()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "10", resp.Header.Get("Retry-After"))
+	assert.Equal(t, 2, sink.SpanCount())
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+)
+
+// isRequestTooLarge returns whether the error is returned reading a request body larger than the
+// max_request_body_size setting, or than the decompression max_decompressed_size setting once decompressed.
+func isRequestTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// tooLargeBody is a request body recording whether it is larger than the size limits.
+type tooLargeBody struct {
+	io.ReadCloser
+	tooLarge bool
+}
+
+func (b *tooLargeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if isRequestTooLarge(err) {
+		b.tooLarge = true
+	}
+	return n, err
+}
+
+// httpRequestSize returns a handler recording the requests refused because their body is larger than
+// the size limits. The requests are refused by the handlers reading the body, with
+// the 413 Request Entity Too Large status code.
+func httpRequestSize(obsrep *receiverhelper.ObsReport, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		body := &tooLargeBody{ReadCloser: req.Body}
+		req.Body = body
+		next.ServeHTTP(resp, req)
+		if body.tooLarge {
+			obsrep.RecordRefusedRequest(req.Context(), receiverhelper.RefusalReasonTooLarge)
+		}
+	})
+}
+
+// grpcRequestSize returns the gRPC server option recording the requests refused because their message
+// is larger than the max_recv_msg_size_mib setting. The gRPC server refuses them with the
+// RESOURCE_EXHAUSTED status code before calling the interceptors, so they are recorded once ended.
+func grpcRequestSize(obsrep *receiverhelper.ObsReport) grpc.ServerOption {
+	return grpc.StatsHandler(&requestSizeStatsHandler{obsrep: obsrep})
+}
+
+type requestSizeStatsHandler struct {
+	obsrep *receiverhelper.ObsReport
+}
+
+func (h *requestSizeStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *requestSizeStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	end, ok := s.(*stats.End)
+	if !ok || end.Error == nil {
+		return
+	}
+	// The gRPC server does not expose a dedicated error, only its message tells the message was too large.
+	if st := status.Convert(end.Error); st.Code() == codes.ResourceExhausted && strings.Contains(st.Message(), "larger than max") {
+		h.obsrep.RecordRefusedRequest(ctx, receiverhelper.RefusalReasonTooLarge)
+	}
+}
+
+func (h *requestSizeStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *requestSizeStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpreceiver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/internal/testutil"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+)
+
+// generateLargeTraces returns traces whose encoding is larger than the given size.
+func generateLargeTraces(size int) ptrace.Traces {
+	td := testdata.GenerateTraces(1)
+	td.ResourceSpans().At(0).Resource().Attributes().PutStr("large", strings.Repeat("x", size))
+	return td
+}
+
+func TestHTTPMaxRequestBodySizeRefusedRequests(t *testing.T) {
+	tt, err := obsreporttest.SetupTelemetry(otlpReceiverID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	url := fmt.Sprintf("http://%s/v1/traces", addr)
+	sink := new(consumertest.TracesSink)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.HTTP.Endpoint = addr
+	cfg.HTTP.MaxRequestBodySize = 1024
+	cfg.GRPC = nil
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	send := func(td ptrace.Traces) (int, *spb.Status) {
+		body, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", pbContentType)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		respBytes, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		errStatus := &spb.Status{}
+		require.NoError(t, proto.Unmarshal(respBytes, errStatus))
+		return resp.StatusCode, errStatus
+	}
+
+	code, _ := send(testdata.GenerateTraces(1))
+	assert.Equal(t, http.StatusOK, code)
+	code, errStatus := send(generateLargeTraces(2048))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+	assert.Equal(t, codes.ResourceExhausted, codes.Code(errStatus.Code))
+	assert.Equal(t, 1, sink.SpanCount())
+	require.NoError(t, tt.CheckReceiverRefusedRequests("http", receiverhelper.RefusalReasonTooLarge, 1))
+}
+
+func TestHTTPMaxDecompressedSizeRefusedRequests(t *testing.T) {
+	tt, err := obsreporttest.SetupTelemetry(otlpReceiverID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	url := fmt.Sprintf("http://%s/v1/traces", addr)
+	sink := new(consumertest.TracesSink)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.HTTP.Endpoint = addr
+	cfg.HTTP.MaxRequestBodySize = 1024
+	cfg.HTTP.Decompression = &confighttp.DecompressionSettings{MaxDecompressedSize: 1024}
+	cfg.GRPC = nil
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	// The compressed body is within the max_request_body_size, not once decompressed.
+	body, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(generateLargeTraces(64 * 1024))
+	require.NoError(t, err)
+	compressed, err := compressZstd(body)
+	require.NoError(t, err)
+	require.Less(t, compressed.Len(), 1024)
+	req, err := http.NewRequest(http.MethodPost, url, compressed)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", pbContentType)
+	req.Header.Set("Content-Encoding", "zstd")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	assert.Equal(t, 0, sink.SpanCount())
+	require.NoError(t, tt.CheckReceiverRefusedRequests("http", receiverhelper.RefusalReasonTooLarge, 1))
+}
+
+func TestGRPCMaxRecvMsgSizeRefusedRequests(t *testing.T) {
+	tt, err := obsreporttest.SetupTelemetry(otlpReceiverID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.TracesSink)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.GRPC.MaxRecvMsgSizeMiB = 1
+	cfg.HTTP = nil
+	ocr := newReceiver(t, factory, cfg, otlpReceiverID, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	require.NoError(t, exportTraces(cc, testdata.GenerateTraces(1)))
+	err = exportTraces(cc, generateLargeTraces(2*1024*1024))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, 1, sink.SpanCount())
+	require.NoError(t, tt.CheckReceiverRefusedRequests("grpc", receiverhelper.RefusalReasonTooLarge, 1))
+}
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receiverhelper // import "go.opentelemetry.io/collector/receiver/receiverhelper"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+const (
+	// redactedIPv4PrefixLength and redactedIPv6PrefixLength are the lengths of the netblocks logged
+	// instead of the IP addresses of the clients when redacted.
+	redactedIPv4PrefixLength = 24
+	redactedIPv6PrefixLength = 48
+
+	outcomeAccepted = "accepted"
+	outcomePartial  = "partially_accepted"
+	outcomeRefused  = "refused"
+)
+
+// AccessLogSettings defines the access log of the receiver: one line logged at the info level for a
+// sample of the requests received, with the client, the signal, the number of items, the outcome and
+// the latency of the request, giving visibility on the ingest without the debug logs.
+type AccessLogSettings struct {
+	// SampleEvery is the number of requests received per request logged, e.g. 100 to log one request
+	// out of 100. The default 0 logs every request.
+	SampleEvery int `mapstructure:"sample_every"`
+
+	// AuthAttribute if not empty, is the attribute set by the authenticator, e.g. "subject", logged to
+	// identify the clients in addition to their address.
+	AuthAttribute string `mapstructure:"auth_attribute"`
+
+	// RedactClientAddress logs the netblocks of the IP addresses of the clients, /24 for IPv4 and /48
+	// for IPv6, instead of the IP addresses.
+	RedactClientAddress bool `mapstructure:"redact_client_address"`
+}
+
+// Validate checks the access log settings are valid.
+func (als *AccessLogSettings) Validate() error {
+	if als.SampleEvery < 0 {
+		return errors.New("sample_every must not be negative")
+	}
+	return nil
+}
+
+// startTimeKey is the context key of the time the operations started, set when the access log is enabled.
+type startTimeKey struct{}
+
+// accessLogger logs a sample of the operations of a receiver.
+type accessLogger struct {
+	cfg       AccessLogSettings
+	logger    *zap.Logger
+	transport string
+	requests  atomic.Uint64
+}
+
+func newAccessLogger(cfg AccessLogSettings, logger *zap.Logger, transport string) *accessLogger {
+	if cfg.SampleEvery == 0 {
+		cfg.SampleEvery = 1
+	}
+	return &accessLogger{cfg: cfg, logger: logger, transport: transport}
+}
+
+// start returns the context of an operation starting, with its start time.
+func (al *accessLogger) start(ctx context.Context) context.Context {
+	return context.WithValue(ctx, startTimeKey{}, time.Now())
+}
+
+// log logs the operation ending if it is sampled.
+func (al *accessLogger) log(ctx context.Context, dataType component.DataType, format string, numAccepted, numRefused int, err error) {
+	if (al.requests.Add(1)-1)%uint64(al.cfg.SampleEvery) != 0 {
+		return
+	}
+
+	outcome := outcomeAccepted
+	switch {
+	case err != nil && numAccepted > 0:
+		outcome = outcomePartial
+	case err != nil:
+		outcome = outcomeRefused
+	}
+	fields := []zap.Field{
+		zap.String("client", al.clientAddress(ctx)),
+		zap.String("signal", string(dataType)),
+		zap.String("transport", al.transport),
+		zap.String("format", format),
+		zap.Int("items", numAccepted+numRefused),
+		zap.String("outcome", outcome),
+	}
+	if start, ok := ctx.Value(startTimeKey{}).(time.Time); ok {
+		fields = append(fields, zap.Duration("latency", time.Since(start)))
+	}
+	if al.cfg.AuthAttribute != "" {
+		if auth := client.FromContext(ctx).Auth; auth != nil {
+			if v := auth.GetAttribute(al.cfg.AuthAttribute); v != nil {
+				fields = append(fields, zap.String(al.cfg.AuthAttribute, fmt.Sprint(v)))
+			}
+		}
+	}
+	if err != nil {
+		var partialErr consumererror.Partial
+		if !errors.As(err, &partialErr) || outcome == outcomeRefused {
+			fields = append(fields, zap.Error(err))
+		} else {
+			fields = append(fields, zap.NamedError("partial_error", err))
+		}
+	}
+	if al.cfg.SampleEvery > 1 {
+		fields = append(fields, zap.Int("sample_every", al.cfg.SampleEvery))
+	}
+	al.logger.Info("Request received", fields...)
+}
+
+// clientAddress returns the IP address of the client, or its netblock when redacted, "unknown" if none.
+func (al *accessLogger) clientAddress(ctx context.Context) string {
+	addr, ok := addrIP(client.FromContext(ctx).Addr)
+	if !ok {
+		return unknownClient
+	}
+	if !al.cfg.RedactClientAddress {
+		return addr.String()
+	}
+	bits := redactedIPv6PrefixLength
+	if addr.Is4() {
+		bits = redactedIPv4PrefixLength
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return unknownClient
+	}
+	return prefix.String()
+}
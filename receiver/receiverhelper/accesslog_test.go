@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receiverhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+func newAccessLogObsReport(t *testing.T, cfg AccessLogSettings) (*ObsReport, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	set := receiver.CreateSettings{ID: receiverID, TelemetrySettings: componenttest.NewNopTelemetrySettings(), BuildInfo: component.NewDefaultBuildInfo()}
+	set.Logger = zap.New(core)
+	rec, err := NewObsReport(ObsReportSettings{
+		ReceiverID:             receiverID,
+		Transport:              transport,
+		ReceiverCreateSettings: set,
+		AccessLog:              &cfg,
+	})
+	require.NoError(t, err)
+	return rec, logs
+}
+
+func TestAccessLog(t *testing.T) {
+	rec, logs := newAccessLogObsReport(t, AccessLogSettings{AuthAttribute: "subject"})
+
+	ctx := rec.StartTracesOp(clientContext("10.0.1.1", authData{"subject": "tenant-1"}))
+	rec.EndTracesOp(ctx, format, 7, nil)
+	ctx = rec.StartMetricsOp(clientContext("10.0.1.1", nil))
+	rec.EndMetricsOp(ctx, format, 3, errors.New("queue full"))
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Request received", entries[0].Message)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "10.0.1.1", fields["client"])
+	assert.Equal(t, "traces", fields["signal"])
+	assert.Equal(t, transport, fields["transport"])
+	assert.Equal(t, format, fields["format"])
+	assert.Equal(t, int64(7), fields["items"])
+	assert.Equal(t, "accepted", fields["outcome"])
+	assert.Contains(t, fields, "latency")
+	assert.Equal(t, "tenant-1", fields["subject"])
+
+	fields = entries[1].ContextMap()
+	assert.Equal(t, "metrics", fields["signal"])
+	assert.Equal(t, "refused", fields["outcome"])
+	assert.Equal(t, "queue full", fields["error"])
+	assert.NotContains(t, fields, "subject")
+}
+
+func TestAccessLogPartiallyAccepted(t *testing.T) {
+	rec, logs := newAccessLogObsReport(t, AccessLogSettings{})
+
+	ctx := rec.StartLogsOp(context.Background())
+	rec.EndLogsOp(ctx, format, 10, consumererror.NewPartial(errors.New("invalid records"), 4))
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "unknown", fields["client"])
+	assert.Equal(t, int64(10), fields["items"])
+	assert.Equal(t, "partially_accepted", fields["outcome"])
+	assert.Equal(t, "invalid records", fields["partial_error"])
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	rec, logs := newAccessLogObsReport(t, AccessLogSettings{SampleEvery: 3})
+
+	for i := 0; i < 7; i++ {
+		ctx := rec.StartTracesOp(context.Background())
+		rec.EndTracesOp(ctx, format, 1, nil)
+	}
+
+	entries := logs.All()
+	require.Len(t, entries, 3)
+	assert.Equal(t, int64(3), entries[0].ContextMap()["sample_every"])
+}
+
+func TestAccessLogRedactClientAddress(t *testing.T) {
+	rec, logs := newAccessLogObsReport(t, AccessLogSettings{RedactClientAddress: true})
+
+	for _, ip := range []string{"10.0.1.1", "::ffff:10.0.1.1", "2001:db8:1:2::1"} {
+		ctx := rec.StartTracesOp(clientContext(ip, nil))
+		rec.EndTracesOp(ctx, format, 1, nil)
+	}
+
+	var clients []any
+	for _, entry := range logs.All() {
+		clients = append(clients, entry.ContextMap()["client"])
+	}
+	assert.Equal(t, []any{"10.0.1.0/24", "10.0.1.0/24", "2001:db8:1::/48"}, clients)
+}
+
+func TestAccessLogSettingsValidate(t *testing.T) {
+	assert.NoError(t, (&AccessLogSettings{}).Validate())
+	assert.EqualError(t, (&AccessLogSettings{SampleEvery: -1}).Validate(), "sample_every must not be negative")
+}
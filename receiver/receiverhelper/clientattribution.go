@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receiverhelper // import "go.opentelemetry.io/collector/receiver/receiverhelper"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+
+	"go.opentelemetry.io/collector/client"
+)
+
+const (
+	// defaultMaxClients is the default maximum number of clients reported individually.
+	defaultMaxClients = 100
+
+	// otherClient identifies the clients that are not reported individually.
+	otherClient = "other"
+	// unknownClient identifies the clients without auth attribute nor IP address, e.g. connected
+	// through a Unix domain socket.
+	unknownClient = "unknown"
+)
+
+// ClientAttributionSettings defines how the accepted and refused items are attributed to the clients
+// that sent them, with the "client" attribute of the additional "*_by_client" receiver metrics.
+// The number of clients reported individually is bounded, the others are reported as "other".
+type ClientAttributionSettings struct {
+	// AuthAttribute if not empty, is the attribute set by the authenticator, e.g. "subject", identifying
+	// the clients. The clients without it, or all the clients when empty, are identified by their IP address.
+	AuthAttribute string `mapstructure:"auth_attribute"`
+
+	// IPv4PrefixLength is the length of the netblocks the IPv4 clients are grouped by, e.g. 24 to report
+	// the clients in 10.0.0.0/24 together. The default 0 means each IP address is reported separately.
+	IPv4PrefixLength int `mapstructure:"ipv4_prefix_length"`
+
+	// IPv6PrefixLength is the length of the netblocks the IPv6 clients are grouped by, e.g. 64.
+	// The default 0 means each IP address is reported separately.
+	IPv6PrefixLength int `mapstructure:"ipv6_prefix_length"`
+
+	// Allowlist if not empty, is the list of the clients reported individually: netblocks in the CIDR
+	// notation, IP addresses, or values of the auth attribute. All the other clients are reported as "other".
+	Allowlist []string `mapstructure:"allowlist"`
+
+	// MaxClients is the maximum number of clients reported individually when the allowlist is empty.
+	// The clients seen once the limit is reached are reported as "other". The default 0 means 100.
+	MaxClients int `mapstructure:"max_clients"`
+}
+
+// Validate checks the client attribution settings are valid.
+func (cas *ClientAttributionSettings) Validate() error {
+	if cas.IPv4PrefixLength < 0 || cas.IPv4PrefixLength > 32 {
+		return errors.New("ipv4_prefix_length must be between 0 and 32")
+	}
+	if cas.IPv6PrefixLength < 0 || cas.IPv6PrefixLength > 128 {
+		return errors.New("ipv6_prefix_length must be between 0 and 128")
+	}
+	if cas.MaxClients < 0 {
+		return errors.New("max_clients must not be negative")
+	}
+	return nil
+}
+
+// clientAttributor returns the client identities reported by the metrics.
+type clientAttributor struct {
+	cfg ClientAttributionSettings
+
+	// allowedNets and allowedValues are the netblocks and auth attribute values of the allowlist.
+	allowedNets   []netip.Prefix
+	allowedValues map[string]struct{}
+
+	maxClients int
+	// mu guards seen, the clients reported individually so far when the allowlist is empty.
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+func newClientAttributor(cfg ClientAttributionSettings) *clientAttributor {
+	ca := &clientAttributor{
+		cfg:           cfg,
+		allowedValues: map[string]struct{}{},
+		maxClients:    cfg.MaxClients,
+		seen:          map[string]struct{}{},
+	}
+	if ca.maxClients == 0 {
+		ca.maxClients = defaultMaxClients
+	}
+	for _, entry := range cfg.Allowlist {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			ca.allowedNets = append(ca.allowedNets, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			ca.allowedNets = append(ca.allowedNets, netip.PrefixFrom(addr, addr.BitLen()))
+		} else {
+			ca.allowedValues[entry] = struct{}{}
+		}
+	}
+	return ca
+}
+
+// client returns the identity of the client of the operation.
+func (ca *clientAttributor) client(ctx context.Context) string {
+	info := client.FromContext(ctx)
+	if ca.cfg.AuthAttribute != "" && info.Auth != nil {
+		if v := info.Auth.GetAttribute(ca.cfg.AuthAttribute); v != nil {
+			value := fmt.Sprintf("%v", v)
+			if len(ca.cfg.Allowlist) == 0 {
+				return ca.limit(value)
+			}
+			if _, ok := ca.allowedValues[value]; ok {
+				return value
+			}
+			return otherClient
+		}
+	}
+
+	addr, ok := addrIP(info.Addr)
+	if !ok {
+		return unknownClient
+	}
+	if len(ca.cfg.Allowlist) > 0 {
+		for _, prefix := range ca.allowedNets {
+			if prefix.Contains(addr) {
+				return prefix.String()
+			}
+		}
+		return otherClient
+	}
+	bits := ca.cfg.IPv4PrefixLength
+	if addr.Is6() {
+		bits = ca.cfg.IPv6PrefixLength
+	}
+	if bits == 0 {
+		bits = addr.BitLen()
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return unknownClient
+	}
+	return ca.limit(prefix.String())
+}
+
+// limit returns the identity of the client if it is reported individually, or "other" once the maximum
+// number of clients is reached.
+func (ca *clientAttributor) limit(id string) string {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	if _, ok := ca.seen[id]; ok {
+		return id
+	}
+	if len(ca.seen) >= ca.maxClients {
+		return otherClient
+	}
+	ca.seen[id] = struct{}{}
+	return id
+}
+
+// addrIP returns the IP address of the client address, if any.
+func addrIP(addr net.Addr) (netip.Addr, bool) {
+	var ip net.IP
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		ip = a.IP
+	case *net.UDPAddr:
+		ip = a.IP
+	case *net.IPAddr:
+		ip = a.IP
+	default:
+		return netip.Addr{}, false
+	}
+	ipAddr, ok := netip.AddrFromSlice(ip)
+	return ipAddr.Unmap(), ok
+}
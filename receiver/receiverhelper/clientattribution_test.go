@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receiverhelper
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/client"
+)
+
+type authData map[string]any
+
+func (a authData) GetAttribute(name string) any {
+	return a[name]
+}
+
+func (a authData) GetAttributeNames() []string {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	return names
+}
+
+func clientContext(ip string, auth client.AuthData) context.Context {
+	return client.NewContext(context.Background(), client.Info{
+		Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 12345},
+		Auth: auth,
+	})
+}
+
+func TestClientAttributor(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings ClientAttributionSettings
+		ctx      context.Context
+		want     string
+	}{
+		{
+			name: "ipv4",
+			ctx:  clientContext("10.0.0.1", nil),
+			want: "10.0.0.1/32",
+		},
+		{
+			name: "ipv4_mapped",
+			ctx:  clientContext("::ffff:10.0.0.1", nil),
+			want: "10.0.0.1/32",
+		},
+		{
+			name:     "ipv4_netblock",
+			settings: ClientAttributionSettings{IPv4PrefixLength: 16},
+			ctx:      clientContext("10.0.1.1", nil),
+			want:     "10.0.0.0/16",
+		},
+		{
+			name:     "ipv6_netblock",
+			settings: ClientAttributionSettings{IPv4PrefixLength: 16, IPv6PrefixLength: 64},
+			ctx:      clientContext("2001:db8::1", nil),
+			want:     "2001:db8::/64",
+		},
+		{
+			name: "no_address",
+			ctx:  context.Background(),
+			want: "unknown",
+		},
+		{
+			name:     "auth_attribute",
+			settings: ClientAttributionSettings{AuthAttribute: "subject"},
+			ctx:      clientContext("10.0.0.1", authData{"subject": "tenant-1"}),
+			want:     "tenant-1",
+		},
+		{
+			name:     "auth_attribute_missing",
+			settings: ClientAttributionSettings{AuthAttribute: "subject"},
+			ctx:      clientContext("10.0.0.1", authData{}),
+			want:     "10.0.0.1/32",
+		},
+		{
+			name:     "allowlist_netblock",
+			settings: ClientAttributionSettings{Allowlist: []string{"10.0.0.0/8", "192.168.0.1"}},
+			ctx:      clientContext("10.1.2.3", nil),
+			want:     "10.0.0.0/8",
+		},
+		{
+			name:     "allowlist_address",
+			settings: ClientAttributionSettings{Allowlist: []string{"10.0.0.0/8", "192.168.0.1"}},
+			ctx:      clientContext("192.168.0.1", nil),
+			want:     "192.168.0.1/32",
+		},
+		{
+			name:     "allowlist_other_address",
+			settings: ClientAttributionSettings{Allowlist: []string{"10.0.0.0/8", "192.168.0.1"}},
+			ctx:      clientContext("192.168.0.2", nil),
+			want:     "other",
+		},
+		{
+			name:     "allowlist_auth_attribute",
+			settings: ClientAttributionSettings{AuthAttribute: "subject", Allowlist: []string{"tenant-1"}},
+			ctx:      clientContext("10.0.0.1", authData{"subject": "tenant-1"}),
+			want:     "tenant-1",
+		},
+		{
+			name:     "allowlist_other_auth_attribute",
+			settings: ClientAttributionSettings{AuthAttribute: "subject", Allowlist: []string{"tenant-1", "10.0.0.0/8"}},
+			ctx:      clientContext("10.0.0.1", authData{"subject": "tenant-2"}),
+			want:     "other",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newClientAttributor(tt.settings).client(tt.ctx))
+		})
+	}
+}
+
+func TestClientAttributorMaxClients(t *testing.T) {
+	ca := newClientAttributor(ClientAttributionSettings{MaxClients: 2})
+
+	assert.Equal(t, "10.0.0.1/32", ca.client(clientContext("10.0.0.1", nil)))
+	assert.Equal(t, "10.0.0.2/32", ca.client(clientContext("10.0.0.2", nil)))
+	assert.Equal(t, "other", ca.client(clientContext("10.0.0.3", nil)))
+	// The clients already reported are still reported individually.
+	assert.Equal(t, "10.0.0.1/32", ca.client(clientContext("10.0.0.1", nil)))
+}
+
+func TestClientAttributionSettingsValidate(t *testing.T) {
+	assert.NoError(t, (&ClientAttributionSettings{IPv4PrefixLength: 24, IPv6PrefixLength: 64}).Validate())
+	assert.EqualError(t, (&ClientAttributionSettings{IPv4PrefixLength: 33}).Validate(), "ipv4_prefix_length must be between 0 and 32")
+	assert.EqualError(t, (&ClientAttributionSettings{IPv6PrefixLength: -1}).Validate(), "ipv6_prefix_length must be between 0 and 128")
+	assert.EqualError(t, (&ClientAttributionSettings{MaxClients: -1}).Validate(), "max_clients must not be negative")
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receiverhelper // import "go.opentelemetry.io/collector/receiver/receiverhelper"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/receiver"
+)
+
+// defaultDrainTimeout is the default maximum time the accepted requests are processed for on shutdown.
+const defaultDrainTimeout = 5 * time.Second
+
+// ErrDraining is returned for the requests refused because the receiver is shutting down. It is
+// retryable: the clients can send the data again to another instance or once the receiver restarted.
+var ErrDraining = errors.New("the receiver is shutting down")
+
+// DrainSettings defines how a receiver drains the requests it accepted when it shuts down.
+type DrainSettings struct {
+	// Timeout is the maximum time the accepted requests are processed for once the receiver stopped
+	// accepting new requests. The requests still in flight after it are cut off. Zero means the
+	// requests in flight are cut off immediately.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// NewDefaultDrainSettings returns the default settings for DrainSettings.
+func NewDefaultDrainSettings() DrainSettings {
+	return DrainSettings{
+		Timeout: defaultDrainTimeout,
+	}
+}
+
+// Validate checks the drain settings are valid.
+func (ds *DrainSettings) Validate() error {
+	if ds.Timeout < 0 {
+		return errors.New("drain timeout must not be negative")
+	}
+	return nil
+}
+
+// Drainer tracks the requests accepted by a receiver, so they are processed before the receiver closes
+// its connections on shutdown. The receivers call Begin and End around the processing of each request,
+// and Drain on shutdown once they stopped accepting new connections.
+type Drainer struct {
+	timeout time.Duration
+	logger  *zap.Logger
+
+	// mu guards inFlight and draining.
+	mu       sync.Mutex
+	inFlight int
+	draining bool
+	// idle is closed once draining and no request is in flight.
+	idle chan struct{}
+}
+
+// NewDrainer creates a new Drainer.
+func NewDrainer(cfg DrainSettings, set receiver.CreateSettings) *Drainer {
+	return &Drainer{
+		timeout: cfg.Timeout,
+		logger:  set.Logger,
+		idle:    make(chan struct{}),
+	}
+}
+
+// Begin is called when the receiver starts processing a request. It returns false once the receiver is
+// draining, in which case the request must be refused with ErrDraining. Otherwise, End must be called
+// once the request is processed.
+func (d *Drainer) Begin() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	d.inFlight++
+	return true
+}
+
+// End is called when the receiver is done processing a request for which Begin returned true.
+func (d *Drainer) End() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inFlight--
+	if d.draining && d.inFlight == 0 {
+		close(d.idle)
+	}
+}
+
+// Drain refuses the new requests and waits for the requests in flight to be processed, for at most the
+// drain timeout or until the context is done. It returns the number of requests still in flight, which
+// the receiver cuts off when closing its connections, and reports them in the logs.
+func (d *Drainer) Drain(ctx context.Context) int {
+	d.mu.Lock()
+	if !d.draining {
+		d.draining = true
+		if d.inFlight == 0 {
+			close(d.idle)
+		}
+	}
+	d.mu.Unlock()
+
+	timer := time.NewTimer(d.timeout)
+	defer timer.Stop()
+	select {
+	case <-d.idle:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	d.mu.Lock()
+	cutOff := d.inFlight
+	d.mu.Unlock()
+	if cutOff > 0 {
+		d.logger.Warn("Requests cut off while draining the receiver",
+			zap.Int("requests", cutOff),
+			zap.Duration("drain_timeout", d.timeout))
+	}
+	return cutOff
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receiverhelper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+func newTestDrainer(timeout time.Duration) (*Drainer, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.WarnLevel)
+	set := receiver.CreateSettings{TelemetrySettings: componenttest.NewNopTelemetrySettings()}
+	set.Logger = zap.New(core)
+	return NewDrainer(DrainSettings{Timeout: timeout}, set), logs
+}
+
+func TestDrainerIdle(t *testing.T) {
+	d, logs := newTestDrainer(time.Hour)
+	require.True(t, d.Begin())
+	d.End()
+
+	assert.Equal(t, 0, d.Drain(context.Background()))
+	assert.Equal(t, 0, logs.Len())
+	// The new requests are refused once draining.
+	assert.False(t, d.Begin())
+	// Draining again returns immediately.
+	assert.Equal(t, 0, d.Drain(context.Background()))
+}
+
+func TestDrainerWaitsForRequests(t *testing.T) {
+	d, logs := newTestDrainer(time.Hour)
+	require.True(t, d.Begin())
+	require.True(t, d.Begin())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		d.End()
+		d.End()
+	}()
+	assert.Equal(t, 0, d.Drain(context.Background()))
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestDrainerTimeout(t *testing.T) {
+	d, logs := newTestDrainer(10 * time.Millisecond)
+	require.True(t, d.Begin())
+	require.True(t, d.Begin())
+	d.End()
+
+	assert.Equal(t, 1, d.Drain(context.Background()))
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, int64(1), logs.All()[0].ContextMap()["requests"])
+
+	// The requests cut off can still end.
+	d.End()
+}
+
+func TestDrainerContextDone(t *testing.T) {
+	d, _ := newTestDrainer(time.Hour)
+	require.True(t, d.Begin())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, 1, d.Drain(ctx))
+}
+
+func TestDrainSettingsValidate(t *testing.T) {
+	cfg := NewDefaultDrainSettings()
+	assert.NoError(t, cfg.Validate())
+	cfg.Timeout = 0
+	assert.NoError(t, cfg.Validate())
+	cfg.Timeout = -time.Second
+	assert.EqualError(t, cfg.Validate(), "drain timeout must not be negative")
+}
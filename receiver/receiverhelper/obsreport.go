@@ -5,6 +5,7 @@ package receiverhelper // import "go.opentelemetry.io/collector/receiver/receive
 
 import (
 	"context"
+	"errors"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
@@ -17,6 +18,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/internal/obsreportconfig"
 	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
 	"go.opentelemetry.io/collector/receiver"
@@ -26,6 +28,10 @@ const (
 	receiverScope = obsmetrics.Scope + obsmetrics.NameSep + obsmetrics.ReceiverKey
 )
 
+// RefusalReasonTooLarge is the reason of the requests refused because they are larger than the
+// configured limit.
+const RefusalReasonTooLarge = "too_large"
+
 // ObsReport is a helper to add observability to a receiver.
 type ObsReport struct {
 	level          configtelemetry.Level
@@ -46,6 +52,20 @@ type ObsReport struct {
 	refusedMetricPointsCounter  metric.Int64Counter
 	acceptedLogRecordsCounter   metric.Int64Counter
 	refusedLogRecordsCounter    metric.Int64Counter
+
+	// clients is nil unless the items are attributed to the clients.
+	clients                             *clientAttributor
+	acceptedSpansByClientCounter        metric.Int64Counter
+	refusedSpansByClientCounter         metric.Int64Counter
+	acceptedMetricPointsByClientCounter metric.Int64Counter
+	refusedMetricPointsByClientCounter  metric.Int64Counter
+	acceptedLogRecordsByClientCounter   metric.Int64Counter
+	refusedLogRecordsByClientCounter    metric.Int64Counter
+
+	refusedRequestsCounter metric.Int64Counter
+
+	// accessLog is nil unless the operations are logged.
+	accessLog *accessLogger
 }
 
 // ObsReportSettings are settings for creating an ObsReport.
@@ -59,6 +79,11 @@ type ObsReportSettings struct {
 	// operations without a corresponding new context per operation.
 	LongLivedCtx           bool
 	ReceiverCreateSettings receiver.CreateSettings
+	// ClientAttribution if not nil, attributes the accepted and refused items to the clients that
+	// sent them, in the additional "*_by_client" metrics.
+	ClientAttribution *ClientAttributionSettings
+	// AccessLog if not nil, logs a sample of the operations with their client, items, outcome and latency.
+	AccessLog *AccessLogSettings
 }
 
 // NewObsReport creates a new ObsReport.
@@ -86,6 +111,12 @@ func newReceiver(cfg ObsReportSettings, useOtel bool) (*ObsReport, error) {
 			attribute.String(obsmetrics.TransportKey, cfg.Transport),
 		},
 	}
+	if cfg.ClientAttribution != nil {
+		rec.clients = newClientAttributor(*cfg.ClientAttribution)
+	}
+	if cfg.AccessLog != nil {
+		rec.accessLog = newAccessLogger(*cfg.AccessLog, rec.logger, cfg.Transport)
+	}
 
 	if err := rec.createOtelMetrics(); err != nil {
 		return nil, err
@@ -143,6 +174,59 @@ func (rec *ObsReport) createOtelMetrics() error {
 	)
 	errors = multierr.Append(errors, err)
 
+	rec.refusedRequestsCounter, err = rec.meter.Int64Counter(
+		obsmetrics.ReceiverPrefix+obsmetrics.RefusedRequestsKey,
+		metric.WithDescription("Number of requests refused before their data was decoded, by reason."),
+		metric.WithUnit("1"),
+	)
+	errors = multierr.Append(errors, err)
+
+	if rec.clients == nil {
+		return errors
+	}
+
+	rec.acceptedSpansByClientCounter, err = rec.meter.Int64Counter(
+		obsmetrics.ReceiverPrefix+obsmetrics.AcceptedSpansKey+obsmetrics.ByClientSuffix,
+		metric.WithDescription("Number of spans successfully pushed into the pipeline, by client."),
+		metric.WithUnit("1"),
+	)
+	errors = multierr.Append(errors, err)
+
+	rec.refusedSpansByClientCounter, err = rec.meter.Int64Counter(
+		obsmetrics.ReceiverPrefix+obsmetrics.RefusedSpansKey+obsmetrics.ByClientSuffix,
+		metric.WithDescription("Number of spans that could not be pushed into the pipeline, by client."),
+		metric.WithUnit("1"),
+	)
+	errors = multierr.Append(errors, err)
+
+	rec.acceptedMetricPointsByClientCounter, err = rec.meter.Int64Counter(
+		obsmetrics.ReceiverPrefix+obsmetrics.AcceptedMetricPointsKey+obsmetrics.ByClientSuffix,
+		metric.WithDescription("Number of metric points successfully pushed into the pipeline, by client."),
+		metric.WithUnit("1"),
+	)
+	errors = multierr.Append(errors, err)
+
+	rec.refusedMetricPointsByClientCounter, err = rec.meter.Int64Counter(
+		obsmetrics.ReceiverPrefix+obsmetrics.RefusedMetricPointsKey+obsmetrics.ByClientSuffix,
+		metric.WithDescription("Number of metric points that could not be pushed into the pipeline, by client."),
+		metric.WithUnit("1"),
+	)
+	errors = multierr.Append(errors, err)
+
+	rec.acceptedLogRecordsByClientCounter, err = rec.meter.Int64Counter(
+		obsmetrics.ReceiverPrefix+obsmetrics.AcceptedLogRecordsKey+obsmetrics.ByClientSuffix,
+		metric.WithDescription("Number of log records successfully pushed into the pipeline, by client."),
+		metric.WithUnit("1"),
+	)
+	errors = multierr.Append(errors, err)
+
+	rec.refusedLogRecordsByClientCounter, err = rec.meter.Int64Counter(
+		obsmetrics.ReceiverPrefix+obsmetrics.RefusedLogRecordsKey+obsmetrics.ByClientSuffix,
+		metric.WithDescription("Number of log records that could not be pushed into the pipeline, by client."),
+		metric.WithUnit("1"),
+	)
+	errors = multierr.Append(errors, err)
+
 	return errors
 }
 
@@ -200,6 +284,26 @@ func (rec *ObsReport) EndMetricsOp(
 	rec.endOp(receiverCtx, format, numReceivedPoints, err, component.DataTypeMetrics)
 }
 
+// RecordRefusedRequest records a request refused before its data was decoded, so the number of
+// items it contained is unknown, for the given reason, e.g. RefusalReasonTooLarge. The refused
+// items of the requests decoded are recorded by the End*Op functions instead.
+func (rec *ObsReport) RecordRefusedRequest(ctx context.Context, reason string) {
+	if rec.level == configtelemetry.LevelNone {
+		return
+	}
+	if rec.useOtelForMetrics {
+		attrs := make([]attribute.KeyValue, 0, len(rec.otelAttrs)+1)
+		attrs = append(attrs, rec.otelAttrs...)
+		attrs = append(attrs, attribute.String(obsmetrics.RefusalReasonKey, reason))
+		rec.refusedRequestsCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
+		return
+	}
+	mutators := make([]tag.Mutator, 0, len(rec.mutators)+1)
+	mutators = append(mutators, rec.mutators...)
+	mutators = append(mutators, tag.Upsert(obsmetrics.TagKeyRefusalReason, reason, tag.WithTTL(tag.TTLNoPropagation)))
+	_ = stats.RecordWithTags(ctx, mutators, obsmetrics.ReceiverRefusedRequests.M(1))
+}
+
 // startOp creates the span used to trace the operation. Returning
 // the updated context with the created span.
 func (rec *ObsReport) startOp(receiverCtx context.Context, operationSuffix string) context.Context {
@@ -222,6 +326,9 @@ func (rec *ObsReport) startOp(receiverCtx context.Context, operationSuffix strin
 	if rec.transport != "" {
 		span.SetAttributes(attribute.String(obsmetrics.TransportKey, rec.transport))
 	}
+	if rec.accessLog != nil {
+		ctx = rec.accessLog.start(ctx)
+	}
 	return ctx
 }
 
@@ -238,6 +345,12 @@ func (rec *ObsReport) endOp(
 	if err != nil {
 		numAccepted = 0
 		numRefused = numReceivedItems
+		// Only the rejected items of a partial error are refused.
+		var partialErr consumererror.Partial
+		if errors.As(err, &partialErr) && partialErr.Rejected() < numReceivedItems {
+			numRefused = partialErr.Rejected()
+			numAccepted = numReceivedItems - numRefused
+		}
 	}
 
 	span := trace.SpanFromContext(receiverCtx)
@@ -245,6 +358,9 @@ func (rec *ObsReport) endOp(
 	if rec.level != configtelemetry.LevelNone {
 		rec.recordMetrics(receiverCtx, dataType, numAccepted, numRefused)
 	}
+	if rec.accessLog != nil {
+		rec.accessLog.log(receiverCtx, dataType, format, numAccepted, numRefused, err)
+	}
 
 	// end span according to errors
 	if span.IsRecording() {
@@ -279,6 +395,15 @@ func (rec *ObsReport) recordMetrics(receiverCtx context.Context, dataType compon
 	} else {
 		rec.recordWithOC(receiverCtx, dataType, numAccepted, numRefused)
 	}
+	if rec.clients == nil {
+		return
+	}
+	clientID := rec.clients.client(receiverCtx)
+	if rec.useOtelForMetrics {
+		rec.recordByClientWithOtel(receiverCtx, dataType, clientID, numAccepted, numRefused)
+	} else {
+		rec.recordByClientWithOC(receiverCtx, dataType, clientID, numAccepted, numRefused)
+	}
 }
 
 func (rec *ObsReport) recordWithOtel(receiverCtx context.Context, dataType component.DataType, numAccepted, numRefused int) {
@@ -318,3 +443,45 @@ func (rec *ObsReport) recordWithOC(receiverCtx context.Context, dataType compone
 		acceptedMeasure.M(int64(numAccepted)),
 		refusedMeasure.M(int64(numRefused)))
 }
+
+func (rec *ObsReport) recordByClientWithOtel(receiverCtx context.Context, dataType component.DataType, clientID string, numAccepted, numRefused int) {
+	var acceptedMeasure, refusedMeasure metric.Int64Counter
+	switch dataType {
+	case component.DataTypeTraces:
+		acceptedMeasure = rec.acceptedSpansByClientCounter
+		refusedMeasure = rec.refusedSpansByClientCounter
+	case component.DataTypeMetrics:
+		acceptedMeasure = rec.acceptedMetricPointsByClientCounter
+		refusedMeasure = rec.refusedMetricPointsByClientCounter
+	case component.DataTypeLogs:
+		acceptedMeasure = rec.acceptedLogRecordsByClientCounter
+		refusedMeasure = rec.refusedLogRecordsByClientCounter
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(rec.otelAttrs)+1)
+	attrs = append(attrs, rec.otelAttrs...)
+	attrs = append(attrs, attribute.String(obsmetrics.ClientKey, clientID))
+	acceptedMeasure.Add(receiverCtx, int64(numAccepted), metric.WithAttributes(attrs...))
+	refusedMeasure.Add(receiverCtx, int64(numRefused), metric.WithAttributes(attrs...))
+}
+
+func (rec *ObsReport) recordByClientWithOC(receiverCtx context.Context, dataType component.DataType, clientID string, numAccepted, numRefused int) {
+	var acceptedMeasure, refusedMeasure *stats.Int64Measure
+	switch dataType {
+	case component.DataTypeTraces:
+		acceptedMeasure = obsmetrics.ReceiverAcceptedSpansByClient
+		refusedMeasure = obsmetrics.ReceiverRefusedSpansByClient
+	case component.DataTypeMetrics:
+		acceptedMeasure = obsmetrics.ReceiverAcceptedMetricPointsByClient
+		refusedMeasure = obsmetrics.ReceiverRefusedMetricPointsByClient
+	case component.DataTypeLogs:
+		acceptedMeasure = obsmetrics.ReceiverAcceptedLogRecordsByClient
+		refusedMeasure = obsmetrics.ReceiverRefusedLogRecordsByClient
+	}
+
+	_ = stats.RecordWithTags(
+		receiverCtx,
+		[]tag.Mutator{tag.Upsert(obsmetrics.TagKeyClient, clientID, tag.WithTTL(tag.TTLNoPropagation))},
+		acceptedMeasure.M(int64(numAccepted)),
+		refusedMeasure.M(int64(numRefused)))
+}
@@ -14,6 +14,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/featuregate"
 	"go.opentelemetry.io/collector/internal/obsreportconfig"
 	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
@@ -84,6 +85,70 @@ func TestReceiveTraceDataOp(t *testing.T) {
 	})
 }
 
+func TestReceiveTraceDataOpPartial(t *testing.T) {
+	testTelemetry(t, receiverID, func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool) {
+		rec, err := newReceiver(ObsReportSettings{
+			ReceiverID:             receiverID,
+			Transport:              transport,
+			ReceiverCreateSettings: receiver.CreateSettings{ID: receiverID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+		}, useOtel)
+		require.NoError(t, err)
+		ctx := rec.StartTracesOp(context.Background())
+		rec.EndTracesOp(ctx, format, 13, consumererror.NewPartial(errFake, 3))
+
+		spans := tt.SpanRecorder.Ended()
+		require.Len(t, spans, 1)
+		require.Contains(t, spans[0].Attributes(), attribute.KeyValue{Key: obsmetrics.AcceptedSpansKey, Value: attribute.Int64Value(10)})
+		require.Contains(t, spans[0].Attributes(), attribute.KeyValue{Key: obsmetrics.RefusedSpansKey, Value: attribute.Int64Value(3)})
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		require.NoError(t, tt.CheckReceiverTraces(transport, 10, 3))
+	})
+}
+
+func TestReceiveOpByClient(t *testing.T) {
+	testTelemetry(t, receiverID, func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool) {
+		rec, err := newReceiver(ObsReportSettings{
+			ReceiverID:             receiverID,
+			Transport:              transport,
+			ReceiverCreateSettings: receiver.CreateSettings{ID: receiverID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+			ClientAttribution:      &ClientAttributionSettings{IPv4PrefixLength: 24},
+		}, useOtel)
+		require.NoError(t, err)
+
+		ctx := rec.StartTracesOp(clientContext("10.0.0.1", nil))
+		rec.EndTracesOp(ctx, format, 13, nil)
+		ctx = rec.StartTracesOp(clientContext("10.0.0.2", nil))
+		rec.EndTracesOp(ctx, format, 5, errFake)
+		ctx = rec.StartLogsOp(clientContext("10.0.0.1", nil))
+		rec.EndLogsOp(ctx, format, 7, nil)
+		ctx = rec.StartMetricsOp(context.Background())
+		rec.EndMetricsOp(ctx, format, 3, nil)
+
+		require.NoError(t, tt.CheckReceiverTraces(transport, 13, 5))
+		require.NoError(t, tt.CheckReceiverTracesByClient(transport, "10.0.0.0/24", 13, 5))
+		require.NoError(t, tt.CheckReceiverLogsByClient(transport, "10.0.0.0/24", 7, 0))
+		require.NoError(t, tt.CheckReceiverMetricsByClient(transport, "unknown", 3, 0))
+	})
+}
+
+func TestRecordRefusedRequest(t *testing.T) {
+	testTelemetry(t, receiverID, func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool) {
+		rec, err := newReceiver(ObsReportSettings{
+			ReceiverID:             receiverID,
+			Transport:              transport,
+			ReceiverCreateSettings: receiver.CreateSettings{ID: receiverID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+		}, useOtel)
+		require.NoError(t, err)
+
+		rec.RecordRefusedRequest(context.Background(), RefusalReasonTooLarge)
+		rec.RecordRefusedRequest(context.Background(), RefusalReasonTooLarge)
+		rec.RecordRefusedRequest(context.Background(), "other")
+
+		require.NoError(t, tt.CheckReceiverRefusedRequests(transport, RefusalReasonTooLarge, 2))
+		require.NoError(t, tt.CheckReceiverRefusedRequests(transport, "other", 1))
+	})
+}
+
 func TestReceiveLogsOp(t *testing.T) {
 	testTelemetry(t, receiverID, func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool) {
 		parentCtx, parentSpan := tt.TracerProvider.Tracer("test").Start(context.Background(), t.Name())
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receiverhelper // import "go.opentelemetry.io/collector/receiver/receiverhelper"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+// errConnectionClosed is logged when the consume function returns without error before shutdown.
+var errConnectionClosed = errors.New("connection closed by the source")
+
+// RestartSettings defines how a receive loop restarts after failures: it reconnects after a backoff
+// interval growing after each consecutive failure, reset once connected again.
+type RestartSettings struct {
+	// InitialInterval is the backoff interval after the first failure.
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	// MaxInterval is the upper bound on the backoff interval.
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+	// Multiplier multiplies the backoff interval after each consecutive failure.
+	Multiplier float64 `mapstructure:"multiplier"`
+}
+
+// NewDefaultRestartSettings returns the default settings for RestartSettings.
+func NewDefaultRestartSettings() RestartSettings {
+	return RestartSettings{
+		InitialInterval: time.Second,
+		MaxInterval:     30 * time.Second,
+		Multiplier:      2,
+	}
+}
+
+// Validate checks the restart settings are valid.
+func (rs *RestartSettings) Validate() error {
+	if rs.InitialInterval <= 0 {
+		return errors.New("initial_interval must be positive")
+	}
+	if rs.MaxInterval < rs.InitialInterval {
+		return errors.New("max_interval must not be less than initial_interval")
+	}
+	if rs.Multiplier < 1 {
+		return errors.New("multiplier must be at least 1")
+	}
+	return nil
+}
+
+// ConnectFunc connects to the source of the data of a pull receiver, e.g. a broker, and returns the
+// function consuming the data from the connection.
+type ConnectFunc func(ctx context.Context) (ConsumeFunc, error)
+
+// ConsumeFunc consumes the data from a connection, passing it to the next consumer, until the context
+// is done or the connection fails. It releases the connection before returning.
+type ConsumeFunc func(ctx context.Context) error
+
+// ReceiveLoop manages the background receive loop of a long-running pull receiver: it connects to the
+// source, consumes from it, and reconnects with backoff on failure until the receiver is shut down.
+// The loop stops, reporting a fatal error to the host, when connecting or consuming fails with a
+// permanent error, see consumererror.NewPermanent.
+//
+// ReceiveLoop implements component.Component, so the receivers can embed it or call its Start and
+// Shutdown functions from their own.
+type ReceiveLoop struct {
+	restart RestartSettings
+	connect ConnectFunc
+	logger  *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ component.Component = (*ReceiveLoop)(nil)
+
+// NewReceiveLoop creates a new ReceiveLoop, connecting to the source with the given function.
+func NewReceiveLoop(cfg RestartSettings, set receiver.CreateSettings, connect ConnectFunc) *ReceiveLoop {
+	return &ReceiveLoop{
+		restart: cfg,
+		connect: connect,
+		logger:  set.Logger,
+	}
+}
+
+// Start starts the receive loop in the background.
+func (l *ReceiveLoop) Start(_ context.Context, host component.Host) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+	l.done = make(chan struct{})
+	go l.run(ctx, host)
+	return nil
+}
+
+// Shutdown stops the receive loop, and waits for the consume function to return or the context to be done.
+func (l *ReceiveLoop) Shutdown(ctx context.Context) error {
+	if l.cancel == nil {
+		return nil
+	}
+	l.cancel()
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *ReceiveLoop) run(ctx context.Context, host component.Host) {
+	defer close(l.done)
+	var interval time.Duration
+	for {
+		consume, err := l.connect(ctx)
+		if err == nil {
+			// The backoff is reset once connected again.
+			interval = 0
+			if err = consume(ctx); err == nil {
+				err = errConnectionClosed
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if consumererror.IsPermanent(err) {
+			l.logger.Error("Receive loop failed permanently, not restarting", zap.Error(err))
+			host.ReportFatalError(err)
+			return
+		}
+
+		interval = l.nextInterval(interval)
+		l.logger.Warn("Receive loop failed, restarting",
+			zap.Error(err),
+			zap.Duration("interval", interval))
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// nextInterval returns the backoff interval following the given one, the initial interval after the
+// first failure.
+func (l *ReceiveLoop) nextInterval(interval time.Duration) time.Duration {
+	if interval == 0 {
+		return l.restart.InitialInterval
+	}
+	next := time.Duration(float64(interval) * l.restart.Multiplier)
+	if next > l.restart.MaxInterval {
+		return l.restart.MaxInterval
+	}
+	return next
+}
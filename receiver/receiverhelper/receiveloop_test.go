@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package receiverhelper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+// fatalErrorHost records the fatal errors reported by the components.
+type fatalErrorHost struct {
+	component.Host
+	errs chan error
+}
+
+func (h *fatalErrorHost) ReportFatalError(err error) {
+	h.errs <- err
+}
+
+func newFatalErrorHost() *fatalErrorHost {
+	return &fatalErrorHost{Host: componenttest.NewNopHost(), errs: make(chan error, 1)}
+}
+
+var testRestartSettings = RestartSettings{
+	InitialInterval: time.Millisecond,
+	MaxInterval:     4 * time.Millisecond,
+	Multiplier:      2,
+}
+
+func TestReceiveLoopReconnects(t *testing.T) {
+	var connects atomic.Int64
+	consumed := make(chan struct{})
+	connect := func(context.Context) (ConsumeFunc, error) {
+		switch connects.Add(1) {
+		case 1:
+			return nil, errors.New("connection refused")
+		case 2:
+			// The connection fails while consuming.
+			return func(context.Context) error { return errors.New("connection reset") }, nil
+		case 3:
+			// The connection is closed by the source.
+			return func(context.Context) error { return nil }, nil
+		}
+		return func(ctx context.Context) error {
+			close(consumed)
+			<-ctx.Done()
+			return ctx.Err()
+		}, nil
+	}
+
+	l := NewReceiveLoop(testRestartSettings, receiver.CreateSettings{TelemetrySettings: componenttest.NewNopTelemetrySettings()}, connect)
+	host := newFatalErrorHost()
+	require.NoError(t, l.Start(context.Background(), host))
+	<-consumed
+	require.NoError(t, l.Shutdown(context.Background()))
+	assert.Equal(t, int64(4), connects.Load())
+	assert.Empty(t, host.errs)
+}
+
+func TestReceiveLoopPermanentError(t *testing.T) {
+	errPermanent := consumererror.NewPermanent(errors.New("authentication failed"))
+	var connects atomic.Int64
+	connect := func(context.Context) (ConsumeFunc, error) {
+		connects.Add(1)
+		return nil, errPermanent
+	}
+
+	l := NewReceiveLoop(testRestartSettings, receiver.CreateSettings{TelemetrySettings: componenttest.NewNopTelemetrySettings()}, connect)
+	host := newFatalErrorHost()
+	require.NoError(t, l.Start(context.Background(), host))
+	assert.Equal(t, errPermanent, <-host.errs)
+	require.NoError(t, l.Shutdown(context.Background()))
+	assert.Equal(t, int64(1), connects.Load())
+}
+
+func TestReceiveLoopShutdownTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	consumed := make(chan struct{})
+	connect := func(context.Context) (ConsumeFunc, error) {
+		return func(context.Context) error {
+			// The consume function does not return on shutdown.
+			close(consumed)
+			<-release
+			return nil
+		}, nil
+	}
+
+	l := NewReceiveLoop(testRestartSettings, receiver.CreateSettings{TelemetrySettings: componenttest.NewNopTelemetrySettings()}, connect)
+	require.NoError(t, l.Start(context.Background(), componenttest.NewNopHost()))
+	<-consumed
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Shutdown(ctx), context.DeadlineExceeded)
+}
+
+func TestReceiveLoopShutdownNotStarted(t *testing.T) {
+	l := NewReceiveLoop(testRestartSettings, receiver.CreateSettings{TelemetrySettings: componenttest.NewNopTelemetrySettings()}, nil)
+	assert.NoError(t, l.Shutdown(context.Background()))
+}
+
+func TestReceiveLoopNextInterval(t *testing.T) {
+	l := NewReceiveLoop(testRestartSettings, receiver.CreateSettings{TelemetrySettings: componenttest.NewNopTelemetrySettings()}, nil)
+	var intervals []time.Duration
+	var interval time.Duration
+	for i := 0; i < 4; i++ {
+		interval = l.nextInterval(interval)
+		intervals = append(intervals, interval)
+	}
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond}, intervals)
+}
+
+func TestRestartSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*RestartSettings)
+		wantErr string
+	}{
+		{
+			name:   "default",
+			modify: func(*RestartSettings) {},
+		},
+		{
+			name:    "initial_interval",
+			modify:  func(rs *RestartSettings) { rs.InitialInterval = 0 },
+			wantErr: "initial_interval must be positive",
+		},
+		{
+			name:    "max_interval",
+			modify:  func(rs *RestartSettings) { rs.MaxInterval = rs.InitialInterval / 2 },
+			wantErr: "max_interval must not be less than initial_interval",
+		},
+		{
+			name:    "multiplier",
+			modify:  func(rs *RestartSettings) { rs.Multiplier = 0.5 },
+			wantErr: "multiplier must be at least 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultRestartSettings()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
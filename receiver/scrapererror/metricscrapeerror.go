@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package scrapererror // import "go.opentelemetry.io/collector/receiver/scrapererror"
+
+import (
+	"errors"
+	"io/fs"
+	"strconv"
+
+	"go.uber.org/multierr"
+)
+
+// Reason is the reason why a metric failed to be scraped.
+type Reason string
+
+const (
+	// ReasonUnknown is the reason of the failures not matching any other reason.
+	ReasonUnknown Reason = "unknown"
+	// ReasonPermission is the reason of the failures caused by the scraper not being allowed to read
+	// the data source, e.g. when the collector is not running as root.
+	ReasonPermission Reason = "permission"
+	// ReasonMissingSource is the reason of the failures caused by a missing data source, e.g. a file
+	// or a device that does not exist on the host.
+	ReasonMissingSource Reason = "missing_source"
+	// ReasonParse is the reason of the failures caused by data that could not be parsed.
+	ReasonParse Reason = "parse"
+)
+
+// MetricScrapeError is an error to represent that a metric failed to be scraped, and why.
+type MetricScrapeError struct {
+	error
+	// Metric is the name of the metric that failed to be scraped.
+	Metric string
+	// Reason is the reason why the metric failed to be scraped.
+	Reason Reason
+	// Failed is the number of data points of the metric that failed to be scraped.
+	Failed int
+}
+
+// ReasonOf returns the reason of the error, from the standard errors it wraps: ReasonPermission for
+// fs.ErrPermission, ReasonMissingSource for fs.ErrNotExist, ReasonParse for the strconv errors, and
+// ReasonUnknown otherwise.
+func ReasonOf(err error) Reason {
+	var numErr *strconv.NumError
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return ReasonPermission
+	case errors.Is(err, fs.ErrNotExist):
+		return ReasonMissingSource
+	case errors.As(err, &numErr):
+		return ReasonParse
+	}
+	return ReasonUnknown
+}
+
+// MetricErrors returns the MetricScrapeErrors of an error combined by ScrapeErrors, reporting which
+// metrics failed to be scraped and why, or nil if none.
+func MetricErrors(err error) []MetricScrapeError {
+	var partialErr PartialScrapeError
+	if errors.As(err, &partialErr) {
+		err = partialErr.error
+	}
+	var metricErrs []MetricScrapeError
+	for _, e := range multierr.Errors(err) {
+		var metricErr MetricScrapeError
+		if errors.As(e, &metricErr) {
+			metricErrs = append(metricErrs, metricErr)
+		}
+	}
+	return metricErrs
+}
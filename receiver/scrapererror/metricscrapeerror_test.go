@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package scrapererror
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReasonOf(t *testing.T) {
+	_, errNotExist := os.Open("/not/existing/file")
+	_, errParse := strconv.ParseInt("not a number", 10, 64)
+
+	tests := []struct {
+		name string
+		err  error
+		want Reason
+	}{
+		{
+			name: "permission",
+			err:  fmt.Errorf("failed to read the device: %w", fs.ErrPermission),
+			want: ReasonPermission,
+		},
+		{
+			name: "missing_source",
+			err:  errNotExist,
+			want: ReasonMissingSource,
+		},
+		{
+			name: "parse",
+			err:  fmt.Errorf("failed to parse the value: %w", errParse),
+			want: ReasonParse,
+		},
+		{
+			name: "unknown",
+			err:  errors.New("failed"),
+			want: ReasonUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ReasonOf(tt.err))
+		})
+	}
+}
+
+func TestMetricErrors(t *testing.T) {
+	assert.Nil(t, MetricErrors(nil))
+	assert.Nil(t, MetricErrors(errors.New("failed")))
+	assert.Nil(t, MetricErrors(NewPartialScrapeError(errors.New("failed"), 1)))
+
+	metricErr := MetricScrapeError{error: errors.New("failed"), Metric: "system.disk.io", Reason: ReasonPermission, Failed: 1}
+	var errs ScrapeErrors
+	errs.AddMetric("system.disk.io", ReasonPermission, 1, errors.New("failed"))
+	assert.Equal(t, []MetricScrapeError{metricErr}, MetricErrors(errs.Combine()))
+	assert.Equal(t, []MetricScrapeError{metricErr}, MetricErrors(fmt.Errorf("scrape: %w", errs.Combine())))
+}
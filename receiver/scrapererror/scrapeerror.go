@@ -7,7 +7,8 @@ import (
 	"go.uber.org/multierr"
 )
 
-// ScrapeErrors contains multiple PartialScrapeErrors and can also contain generic errors.
+// ScrapeErrors contains multiple PartialScrapeErrors and MetricScrapeErrors, and can also contain
+// generic errors.
 type ScrapeErrors struct {
 	errs              []error
 	failedScrapeCount int
@@ -19,17 +20,30 @@ func (s *ScrapeErrors) AddPartial(failed int, err error) {
 	s.failedScrapeCount += failed
 }
 
+// AddMetric adds a MetricScrapeError for the metric that failed to be scraped, with the provided
+// reason, failed count and error.
+func (s *ScrapeErrors) AddMetric(metric string, reason Reason, failed int, err error) {
+	s.errs = append(s.errs, MetricScrapeError{
+		error:  err,
+		Metric: metric,
+		Reason: reason,
+		Failed: failed,
+	})
+	s.failedScrapeCount += failed
+}
+
 // Add adds a regular error.
 func (s *ScrapeErrors) Add(err error) {
 	s.errs = append(s.errs, err)
 }
 
 // Combine converts a slice of errors into one error.
-// It will return a PartialScrapeError if at least one error in the slice is a PartialScrapeError.
+// It will return a PartialScrapeError if at least one error in the slice is a PartialScrapeError
+// or a MetricScrapeError. The MetricScrapeErrors are returned by MetricErrors.
 func (s *ScrapeErrors) Combine() error {
 	partialScrapeErr := false
 	for _, err := range s.errs {
-		if IsPartialScrapeError(err) {
+		if _, ok := err.(MetricScrapeError); ok || IsPartialScrapeError(err) {
 			partialScrapeErr = true
 		}
 	}
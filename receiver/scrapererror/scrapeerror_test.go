@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestScrapeErrorsAddPartial(t *testing.T) {
@@ -36,6 +37,27 @@ func TestScrapeErrorsAdd(t *testing.T) {
 	assert.Equal(t, expected, errs.errs)
 }
 
+func TestScrapeErrorsAddMetric(t *testing.T) {
+	err1 := errors.New("err 1")
+	err2 := errors.New("err 2")
+	expected := []MetricScrapeError{
+		{error: err1, Metric: "system.disk.io", Reason: ReasonPermission, Failed: 2},
+		{error: err2, Metric: "system.disk.operations", Reason: ReasonParse, Failed: 1},
+	}
+
+	var errs ScrapeErrors
+	errs.AddMetric("system.disk.io", ReasonPermission, 2, err1)
+	errs.AddMetric("system.disk.operations", ReasonParse, 1, err2)
+	errs.Add(errors.New("err 3"))
+
+	err := errs.Combine()
+	assert.EqualError(t, err, "err 1; err 2; err 3")
+	var partialScrapeErr PartialScrapeError
+	require.ErrorAs(t, err, &partialScrapeErr)
+	assert.Equal(t, 3, partialScrapeErr.Failed)
+	assert.Equal(t, expected, MetricErrors(err))
+}
+
 func TestScrapeErrorsCombine(t *testing.T) {
 	testCases := []struct {
 		errs                func() ScrapeErrors
@@ -6,6 +6,8 @@ package scraperhelper // import "go.opentelemetry.io/collector/receiver/scraperh
 import (
 	"context"
 	"errors"
+	"sync/atomic"
+	"time"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
@@ -42,6 +44,13 @@ type ObsReport struct {
 	otelAttrs            []attribute.KeyValue
 	scrapedMetricsPoints metric.Int64Counter
 	erroredMetricsPoints metric.Int64Counter
+	timedOutScrapes      metric.Int64Counter
+	backoffIntervalGauge metric.Int64ObservableGauge
+	// erroredMetricsPointsByMetric attributes the errored metric points to the metrics that failed and why.
+	erroredMetricsPointsByMetric metric.Int64Counter
+
+	// backoffInterval is the current backoff interval of the scraper, in milliseconds.
+	backoffInterval atomic.Int64
 }
 
 // ObsReportSettings are settings for creating an ObsReport.
@@ -103,6 +112,31 @@ func (s *ObsReport) createOtelMetrics(cfg ObsReportSettings) error {
 	)
 	errors = multierr.Append(errors, err)
 
+	s.erroredMetricsPointsByMetric, err = meter.Int64Counter(
+		obsmetrics.ScraperPrefix+obsmetrics.ErroredMetricPointsKey+obsmetrics.ByMetricSuffix,
+		metric.WithDescription("Number of metric points that were unable to be scraped, by metric and reason."),
+		metric.WithUnit("1"),
+	)
+	errors = multierr.Append(errors, err)
+
+	s.timedOutScrapes, err = meter.Int64Counter(
+		obsmetrics.ScraperPrefix+obsmetrics.TimedOutScrapesKey,
+		metric.WithDescription("Number of scrapes that exceeded the scrape timeout."),
+		metric.WithUnit("1"),
+	)
+	errors = multierr.Append(errors, err)
+
+	s.backoffIntervalGauge, err = meter.Int64ObservableGauge(
+		obsmetrics.ScraperPrefix+obsmetrics.BackoffIntervalKey,
+		metric.WithDescription("Current backoff interval of the scraper after failed scrapes, 0 when not backing off."),
+		metric.WithUnit("ms"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(s.backoffInterval.Load(), metric.WithAttributes(s.otelAttrs...))
+			return nil
+		}),
+	)
+	errors = multierr.Append(errors, err)
+
 	return errors
 }
 
@@ -118,17 +152,21 @@ func (s *ObsReport) StartMetricsOp(ctx context.Context) context.Context {
 }
 
 // EndMetricsOp completes the scrape operation that was started with
-// StartMetricsOp.
+// StartMetricsOp. A scrape failing with context.DeadlineExceeded is counted
+// as timed out. The metrics of a scrapererror.PartialScrapeError that failed to
+// be scraped are also counted by metric and reason.
 func (s *ObsReport) EndMetricsOp(
 	scraperCtx context.Context,
 	numScrapedMetrics int,
 	err error,
 ) {
 	numErroredMetrics := 0
+	var metricErrs []scrapererror.MetricScrapeError
 	if err != nil {
 		var partialErr scrapererror.PartialScrapeError
 		if errors.As(err, &partialErr) {
 			numErroredMetrics = partialErr.Failed
+			metricErrs = scrapererror.MetricErrors(partialErr)
 		} else {
 			numErroredMetrics = numScrapedMetrics
 			numScrapedMetrics = 0
@@ -139,6 +177,10 @@ func (s *ObsReport) EndMetricsOp(
 
 	if s.level != configtelemetry.LevelNone {
 		s.recordMetrics(scraperCtx, numScrapedMetrics, numErroredMetrics)
+		s.recordMetricErrors(scraperCtx, metricErrs)
+		if errors.Is(err, context.DeadlineExceeded) {
+			s.recordTimedOutScrape(scraperCtx)
+		}
 	}
 
 	// end span according to errors
@@ -168,3 +210,42 @@ func (s *ObsReport) recordMetrics(scraperCtx context.Context, numScrapedMetrics,
 			obsmetrics.ScraperErroredMetricPoints.M(int64(numErroredMetrics)))
 	}
 }
+
+func (s *ObsReport) recordMetricErrors(scraperCtx context.Context, metricErrs []scrapererror.MetricScrapeError) {
+	for _, metricErr := range metricErrs {
+		if s.useOtelForMetrics {
+			attrs := make([]attribute.KeyValue, 0, len(s.otelAttrs)+2)
+			attrs = append(attrs, s.otelAttrs...)
+			attrs = append(attrs,
+				attribute.String(obsmetrics.ScrapedMetricKey, metricErr.Metric),
+				attribute.String(obsmetrics.ScrapeErrorReasonKey, string(metricErr.Reason)))
+			s.erroredMetricsPointsByMetric.Add(scraperCtx, int64(metricErr.Failed), metric.WithAttributes(attrs...))
+		} else { // OC for metrics
+			_ = stats.RecordWithTags(
+				scraperCtx,
+				[]tag.Mutator{
+					tag.Upsert(obsmetrics.TagKeyScrapedMetric, metricErr.Metric, tag.WithTTL(tag.TTLNoPropagation)),
+					tag.Upsert(obsmetrics.TagKeyScrapeErrorReason, string(metricErr.Reason), tag.WithTTL(tag.TTLNoPropagation)),
+				},
+				obsmetrics.ScraperErroredMetricPointsByMetric.M(int64(metricErr.Failed)))
+		}
+	}
+}
+
+func (s *ObsReport) recordTimedOutScrape(scraperCtx context.Context) {
+	if s.useOtelForMetrics {
+		s.timedOutScrapes.Add(scraperCtx, 1, metric.WithAttributes(s.otelAttrs...))
+	} else { // OC for metrics
+		stats.Record(scraperCtx, obsmetrics.ScraperTimedOutScrapes.M(1))
+	}
+}
+
+// recordBackoffInterval records the current backoff interval of the scraper.
+func (s *ObsReport) recordBackoffInterval(interval time.Duration) {
+	s.backoffInterval.Store(interval.Milliseconds())
+	if s.level == configtelemetry.LevelNone || s.useOtelForMetrics {
+		return
+	}
+	ctx, _ := tag.New(context.Background(), s.mutators...)
+	stats.Record(ctx, obsmetrics.ScraperBackoffInterval.M(interval.Milliseconds()))
+}
@@ -6,7 +6,9 @@ package scraperhelper
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -92,6 +94,66 @@ func TestScrapeMetricsDataOp(t *testing.T) {
 	})
 }
 
+func TestScrapeMetricsDataOpTimedOut(t *testing.T) {
+	testTelemetry(t, receiverID, func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool) {
+		scrp, err := newScraper(ObsReportSettings{
+			ReceiverID:             receiverID,
+			Scraper:                scraperID,
+			ReceiverCreateSettings: receiver.CreateSettings{ID: receiverID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+		}, useOtel)
+		require.NoError(t, err)
+
+		for _, err := range []error{fmt.Errorf("timed out: %w", context.DeadlineExceeded), errFake, context.DeadlineExceeded} {
+			ctx := scrp.StartMetricsOp(context.Background())
+			scrp.EndMetricsOp(ctx, 7, err)
+		}
+
+		require.NoError(t, obsreporttest.CheckScraperMetrics(tt, receiverID, scraperID, 0, 21))
+		require.NoError(t, obsreporttest.CheckScraperTimedOutScrapes(tt, receiverID, scraperID, 2))
+	})
+}
+
+func TestScrapeMetricsDataOpByMetric(t *testing.T) {
+	testTelemetry(t, receiverID, func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool) {
+		scrp, err := newScraper(ObsReportSettings{
+			ReceiverID:             receiverID,
+			Scraper:                scraperID,
+			ReceiverCreateSettings: receiver.CreateSettings{ID: receiverID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+		}, useOtel)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			var errs scrapererror.ScrapeErrors
+			errs.AddMetric("system.disk.io", scrapererror.ReasonPermission, 2, errFake)
+			errs.AddMetric("system.disk.operations", scrapererror.ReasonParse, 1, errFake)
+			errs.AddPartial(3, errFake)
+			ctx := scrp.StartMetricsOp(context.Background())
+			scrp.EndMetricsOp(ctx, 10, errs.Combine())
+		}
+
+		// The errored metric points are still counted in total.
+		require.NoError(t, obsreporttest.CheckScraperMetrics(tt, receiverID, scraperID, 20, 12))
+		require.NoError(t, obsreporttest.CheckScraperErroredMetricsByMetric(tt, receiverID, scraperID, "system.disk.io", "permission", 4))
+		require.NoError(t, obsreporttest.CheckScraperErroredMetricsByMetric(tt, receiverID, scraperID, "system.disk.operations", "parse", 2))
+	})
+}
+
+func TestRecordBackoffInterval(t *testing.T) {
+	testTelemetry(t, receiverID, func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool) {
+		scrp, err := newScraper(ObsReportSettings{
+			ReceiverID:             receiverID,
+			Scraper:                scraperID,
+			ReceiverCreateSettings: receiver.CreateSettings{ID: receiverID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+		}, useOtel)
+		require.NoError(t, err)
+
+		scrp.recordBackoffInterval(time.Minute)
+		require.NoError(t, obsreporttest.CheckScraperBackoffInterval(tt, receiverID, scraperID, time.Minute.Milliseconds()))
+		scrp.recordBackoffInterval(0)
+		require.NoError(t, obsreporttest.CheckScraperBackoffInterval(tt, receiverID, scraperID, 0))
+	})
+}
+
 func testTelemetry(t *testing.T, id component.ID, testFunc func(t *testing.T, tt obsreporttest.TestTelemetry, useOtel bool)) {
 	t.Run("WithOC", func(t *testing.T) {
 		tt, err := obsreporttest.SetupTelemetry(id)
@@ -6,6 +6,12 @@ package scraperhelper // import "go.opentelemetry.io/collector/receiver/scraperh
 import (
 	"context"
 	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/multierr"
@@ -19,6 +25,19 @@ import (
 	"go.opentelemetry.io/collector/receiver/scrapererror"
 )
 
+// errNotScraping is returned by the on-demand scrapes when the controller is not started or stopped.
+var errNotScraping = errors.New("the scraper controller is not scraping")
+
+// ScrapeTrigger is implemented by the receivers created by NewScraperControllerReceiver, to trigger
+// a scrape on demand, out of the collection interval, e.g. to debug the scrapers or to collect the
+// metrics on an event.
+type ScrapeTrigger interface {
+	// TriggerScrape scrapes all the scrapers immediately, passes the scraped metrics to the next
+	// consumer, and returns them along with the scrape errors. The on-demand scrapes are serialized
+	// with the scheduled ones, and are not jittered.
+	TriggerScrape(ctx context.Context) (pmetric.Metrics, error)
+}
+
 // ScraperControllerOption apply changes to internal options.
 type ScraperControllerOption func(*controller)
 
@@ -47,13 +66,33 @@ type controller struct {
 	logger             *zap.Logger
 	collectionInterval time.Duration
 	initialDelay       time.Duration
+	alignWithInterval  bool
+	collectOnStart     bool
 	timeout            time.Duration
-	nextConsumer       consumer.Metrics
+	initialJitter      time.Duration
+	jitter             time.Duration
+	jitterSeed         int64
+	backoff            BackoffSettings
+	// maxConcurrentScrapers is the maximum number of scrapers scraped
+	// concurrently, they are scraped sequentially when less than 2.
+	maxConcurrentScrapers int
+	nextConsumer          consumer.Metrics
 
 	scrapers    []Scraper
 	obsScrapers []*ObsReport
+	// jitterRands are the random sources of the delays of the scrapers, set
+	// when the scrapes are jittered.
+	jitterRands []*rand.Rand
+	// scraping tells, by scraper, whether a scrape exceeding the timeout is
+	// still running.
+	scraping []atomic.Bool
+	// backoffs are the backoff states of the scrapers, set when the backoff
+	// is enabled.
+	backoffs []scraperBackoff
 
 	tickerCh <-chan time.Time
+	// triggers receives the on-demand scrapes, handled by the scraping goroutine.
+	triggers chan chan scrapeResult
 
 	initialized bool
 	done        chan struct{}
@@ -88,22 +127,31 @@ func NewScraperControllerReceiver(
 	}
 
 	sc := &controller{
-		id:                 set.ID,
-		logger:             set.Logger,
-		collectionInterval: cfg.CollectionInterval,
-		initialDelay:       cfg.InitialDelay,
-		timeout:            cfg.Timeout,
-		nextConsumer:       nextConsumer,
-		done:               make(chan struct{}),
-		terminated:         make(chan struct{}),
-		obsrecv:            obsrecv,
-		recvSettings:       set,
+		id:                    set.ID,
+		logger:                set.Logger,
+		collectionInterval:    cfg.CollectionInterval,
+		initialDelay:          cfg.InitialDelay,
+		alignWithInterval:     cfg.AlignWithInterval,
+		collectOnStart:        cfg.CollectOnStart,
+		timeout:               cfg.Timeout,
+		initialJitter:         cfg.InitialJitter,
+		jitter:                cfg.Jitter,
+		jitterSeed:            cfg.JitterSeed,
+		backoff:               cfg.Backoff,
+		maxConcurrentScrapers: cfg.MaxConcurrentScrapers,
+		nextConsumer:          nextConsumer,
+		triggers:              make(chan chan scrapeResult),
+		done:                  make(chan struct{}),
+		terminated:            make(chan struct{}),
+		obsrecv:               obsrecv,
+		recvSettings:          set,
 	}
 
 	for _, op := range options {
 		op(sc)
 	}
 
+	sc.scraping = make([]atomic.Bool, len(sc.scrapers))
 	sc.obsScrapers = make([]*ObsReport, len(sc.scrapers))
 	for i, scraper := range sc.scrapers {
 		scrp, err := NewObsReport(ObsReportSettings{
@@ -119,9 +167,34 @@ func NewScraperControllerReceiver(
 		}
 	}
 
+	if sc.backoff.Enabled {
+		sc.backoffs = make([]scraperBackoff, len(sc.scrapers))
+	}
+
+	if sc.initialJitter > 0 || sc.jitter > 0 {
+		sc.jitterRands = make([]*rand.Rand, len(sc.scrapers))
+		for i, scraper := range sc.scrapers {
+			sc.jitterRands[i] = rand.New(rand.NewSource(jitterSeed(sc.jitterSeed, sc.id, scraper.ID())))
+		}
+	}
+
 	return sc, nil
 }
 
+var _ ScrapeTrigger = (*controller)(nil)
+
+// jitterSeed returns the seed of the delays of a scraper. A configured seed is
+// combined with the IDs of the receiver and of the scraper, so each scraper has
+// its own deterministic delays.
+func jitterSeed(seed int64, receiverID, scraperID component.ID) int64 {
+	if seed == 0 {
+		return rand.Int63()
+	}
+	h := fnv.New64a()
+	_, _ = fmt.Fprint(h, seed, receiverID, scraperID)
+	return int64(h.Sum64())
+}
+
 // Start the receiver, invoked during service start.
 func (sc *controller) Start(ctx context.Context, host component.Host) error {
 	for _, scraper := range sc.scrapers {
@@ -156,8 +229,17 @@ func (sc *controller) Shutdown(ctx context.Context) error {
 // collection interval.
 func (sc *controller) startScraping() {
 	go func() {
-		if sc.initialDelay > 0 {
-			<-time.After(sc.initialDelay)
+		if sc.collectOnStart {
+			// The first scrape happens at start, without waiting for the
+			// initial delay nor the initial jitter.
+			if !sc.scrapeMetricsAndReport(0) {
+				sc.terminated <- struct{}{}
+				return
+			}
+		}
+		if !sc.waitUntil(sc.firstScrapeTime(time.Now())) {
+			sc.terminated <- struct{}{}
+			return
 		}
 
 		if sc.tickerCh == nil {
@@ -169,11 +251,31 @@ func (sc *controller) startScraping() {
 		// Call scrape method on initialision to ensure
 		// that scrapers start from when the component starts
 		// instead of waiting for the full duration to start.
-		sc.scrapeMetricsAndReport()
+		// When already scraped on start, the scrapes continue at the
+		// next aligned instant if configured, or at the next tick.
+		if !sc.collectOnStart {
+			if !sc.scrapeMetricsAndReport(sc.initialJitter) {
+				sc.terminated <- struct{}{}
+				return
+			}
+		} else if sc.alignWithInterval {
+			if !sc.scrapeMetricsAndReport(sc.jitter) {
+				sc.terminated <- struct{}{}
+				return
+			}
+		}
 		for {
 			select {
 			case <-sc.tickerCh:
-				sc.scrapeMetricsAndReport()
+				if !sc.scrapeMetricsAndReport(sc.jitter) {
+					sc.terminated <- struct{}{}
+					return
+				}
+			case resultCh := <-sc.triggers:
+				if !sc.scrapeOnDemand(resultCh) {
+					sc.terminated <- struct{}{}
+					return
+				}
 			case <-sc.done:
 				sc.terminated <- struct{}{}
 				return
@@ -182,35 +284,296 @@ func (sc *controller) startScraping() {
 	}()
 }
 
+// firstScrapeTime returns the time of the first scrape of a controller started
+// at the given time, after the initial delay and aligned with the collection
+// interval if configured. When the controller scraped on start, the initial
+// delay is skipped and the scrapes continue at the next aligned instant after
+// the given time, if configured, or at the given time otherwise.
+func (sc *controller) firstScrapeTime(start time.Time) time.Time {
+	if sc.initialDelay > 0 && !sc.collectOnStart {
+		start = start.Add(sc.initialDelay)
+	}
+	if !sc.alignWithInterval {
+		return start
+	}
+	aligned := start.Truncate(sc.collectionInterval)
+	if aligned.Before(start) || (sc.collectOnStart && aligned.Equal(start)) {
+		aligned = aligned.Add(sc.collectionInterval)
+	}
+	return aligned
+}
+
+// scrapeResult is the result of an on-demand scrape.
+type scrapeResult struct {
+	md  pmetric.Metrics
+	err error
+}
+
+// TriggerScrape implements ScrapeTrigger.
+func (sc *controller) TriggerScrape(ctx context.Context) (pmetric.Metrics, error) {
+	if !sc.initialized {
+		return pmetric.NewMetrics(), errNotScraping
+	}
+	// Buffered, so the scraping goroutine does not block when the caller gave up.
+	resultCh := make(chan scrapeResult, 1)
+	select {
+	case sc.triggers <- resultCh:
+	case <-sc.done:
+		return pmetric.NewMetrics(), errNotScraping
+	case <-ctx.Done():
+		return pmetric.NewMetrics(), ctx.Err()
+	}
+	select {
+	case r := <-resultCh:
+		return r.md, r.err
+	case <-ctx.Done():
+		return pmetric.NewMetrics(), ctx.Err()
+	}
+}
+
 // scrapeMetricsAndReport calls the Scrape function for each of the configured
 // Scrapers, records observability information, and passes the scraped metrics
-// to the next component.
-func (sc *controller) scrapeMetricsAndReport() {
-	ctx, done := withScrapeContext(sc.timeout)
-	defer done()
+// to the next component. It returns false if the controller was stopped while
+// waiting for a scraper.
+func (sc *controller) scrapeMetricsAndReport(maxJitter time.Duration) bool {
+	md, err := sc.scrapeMetrics(maxJitter)
+	if errors.Is(err, errNotScraping) {
+		return false
+	}
+	_ = sc.report(md)
+	return true
+}
 
-	metrics := pmetric.NewMetrics()
+// scrapeOnDemand scrapes all the scrapers without jitter, passes the scraped
+// metrics to the next component, and sends them along with the errors to the
+// caller of TriggerScrape. It returns false if the controller was stopped while
+// waiting for a scraper.
+func (sc *controller) scrapeOnDemand(resultCh chan<- scrapeResult) bool {
+	md, err := sc.scrapeMetrics(0)
+	if errors.Is(err, errNotScraping) {
+		resultCh <- scrapeResult{md: pmetric.NewMetrics(), err: err}
+		return false
+	}
+	// The next consumer may mutate the metrics, they are copied for the caller.
+	result := scrapeResult{md: pmetric.NewMetrics(), err: err}
+	md.CopyTo(result.md)
+	result.err = multierr.Append(result.err, sc.report(md))
+	resultCh <- result
+	return true
+}
 
-	for i, scraper := range sc.scrapers {
-		scrp := sc.obsScrapers[i]
-		ctx = scrp.StartMetricsOp(ctx)
-		md, err := scraper.Scrape(ctx)
+// scrapeMetrics calls the Scrape function for each of the configured Scrapers,
+// records observability information, and returns the scraped metrics along with
+// the scrape errors. Each scraper is delayed by a random jitter up to maxJitter,
+// and up to maxConcurrentScrapers scrapers are scraped concurrently. It returns
+// errNotScraping if the controller was stopped while waiting for a scraper.
+func (sc *controller) scrapeMetrics(maxJitter time.Duration) (pmetric.Metrics, error) {
+	order, delays := sc.jitterDelays(maxJitter)
+	results := make([]pmetric.Metrics, len(sc.scrapers))
+	errs := make([]error, len(sc.scrapers))
+
+	var sem chan struct{}
+	if sc.maxConcurrentScrapers > 1 {
+		sem = make(chan struct{}, sc.maxConcurrentScrapers)
+	}
+	var wg sync.WaitGroup
+	// The scrapes in progress complete before returning, even when stopped.
+	defer wg.Wait()
+
+	start := time.Now()
+	for _, i := range order {
+		if delays != nil && !sc.waitUntil(start.Add(delays[i])) {
+			return pmetric.Metrics{}, errNotScraping
+		}
+		if sem == nil {
+			results[i], errs[i] = sc.collect(i)
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-sc.done:
+			return pmetric.Metrics{}, errNotScraping
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i], errs[i] = sc.collect(i)
+		}(i)
+	}
+	wg.Wait()
 
-		if err != nil {
-			sc.logger.Error("Error scraping metrics", zap.Error(err), zap.Stringer("scraper", scraper.ID()))
-			if !scrapererror.IsPartialScrapeError(err) {
-				scrp.EndMetricsOp(ctx, 0, err)
-				continue
-			}
+	metrics := pmetric.NewMetrics()
+	for _, md := range results {
+		if md != (pmetric.Metrics{}) {
+			md.ResourceMetrics().MoveAndAppendTo(metrics.ResourceMetrics())
 		}
-		scrp.EndMetricsOp(ctx, md.MetricCount(), err)
-		md.ResourceMetrics().MoveAndAppendTo(metrics.ResourceMetrics())
 	}
+	return metrics, multierr.Combine(errs...)
+}
 
+// report passes the scraped metrics to the next component, and records
+// observability information.
+func (sc *controller) report(metrics pmetric.Metrics) error {
 	dataPointCount := metrics.DataPointCount()
-	ctx = sc.obsrecv.StartMetricsOp(ctx)
+	ctx := sc.obsrecv.StartMetricsOp(context.Background())
 	err := sc.nextConsumer.ConsumeMetrics(ctx, metrics)
 	sc.obsrecv.EndMetricsOp(ctx, "", dataPointCount, err)
+	return err
+}
+
+// collect scrapes a scraper unless it is backing off or its previous scrape
+// is still running, and returns the scraped metrics, zero if none, and the
+// scrape error.
+func (sc *controller) collect(i int) (pmetric.Metrics, error) {
+	if sc.backoffs != nil && sc.backoffs[i].skipped > 0 {
+		sc.backoffs[i].skipped--
+		return pmetric.Metrics{}, nil
+	}
+	if sc.scraping[i].Load() {
+		sc.logger.Warn("Skipping scrape, the previous scrape exceeded the timeout and is still running", zap.Stringer("scraper", sc.scrapers[i].ID()))
+		return pmetric.Metrics{}, nil
+	}
+	md, err := sc.scrapeAndReport(i)
+	failed := err != nil && !scrapererror.IsPartialScrapeError(err)
+	if sc.backoffs != nil {
+		sc.updateBackoff(i, failed)
+	}
+	if failed {
+		return pmetric.Metrics{}, err
+	}
+	return md, err
+}
+
+// scrapeAndReport calls the Scrape function of a scraper, with the scrape
+// timeout as context deadline, and records observability information.
+func (sc *controller) scrapeAndReport(i int) (pmetric.Metrics, error) {
+	scraper := sc.scrapers[i]
+	scrp := sc.obsScrapers[i]
+	ctx, done := withScrapeContext(sc.timeout)
+	defer done()
+
+	ctx = scrp.StartMetricsOp(ctx)
+	md, err := sc.scrape(ctx, i)
+	if err != nil {
+		sc.logger.Error("Error scraping metrics", zap.Error(err), zap.Stringer("scraper", scraper.ID()))
+		if !scrapererror.IsPartialScrapeError(err) {
+			scrp.EndMetricsOp(ctx, 0, err)
+			return md, err
+		}
+	}
+	scrp.EndMetricsOp(ctx, md.MetricCount(), err)
+	return md, err
+}
+
+// scrape calls the Scrape function of a scraper. When the scrapes have a timeout,
+// a scraper not returning by the deadline is abandoned, so it cannot delay the
+// other scrapers, and it is skipped until it returns.
+func (sc *controller) scrape(ctx context.Context, i int) (pmetric.Metrics, error) {
+	scraper := sc.scrapers[i]
+	if sc.timeout <= 0 {
+		return scraper.Scrape(ctx)
+	}
+
+	type result struct {
+		md  pmetric.Metrics
+		err error
+	}
+	resultCh := make(chan result, 1)
+	sc.scraping[i].Store(true)
+	go func() {
+		md, err := scraper.Scrape(ctx)
+		sc.scraping[i].Store(false)
+		resultCh <- result{md: md, err: err}
+	}()
+
+	select {
+	case r := <-resultCh:
+		return r.md, r.err
+	case <-ctx.Done():
+		select {
+		case r := <-resultCh:
+			// The scraper returned at the deadline.
+			return r.md, r.err
+		default:
+		}
+		return pmetric.NewMetrics(), fmt.Errorf("scrape exceeded the timeout of %v: %w", sc.timeout, ctx.Err())
+	}
+}
+
+// scraperBackoff is the backoff state of a scraper.
+type scraperBackoff struct {
+	// interval is the current backoff interval, 0 when not backing off.
+	interval time.Duration
+	// skipped is the number of scrapes left to skip.
+	skipped int
+}
+
+// updateBackoff updates the backoff of a scraper after a scrape. After a failed
+// scrape the backoff interval grows and the next scrapes are skipped until it
+// elapsed, the following scrape probing the recovery of the scraper. The backoff
+// stops after a successful scrape.
+func (sc *controller) updateBackoff(i int, failed bool) {
+	b := &sc.backoffs[i]
+	if !failed {
+		if b.interval > 0 {
+			sc.logger.Info("Scrape succeeded, stopping the backoff", zap.Stringer("scraper", sc.scrapers[i].ID()))
+			b.interval = 0
+			sc.obsScrapers[i].recordBackoffInterval(0)
+		}
+		return
+	}
+
+	if b.interval == 0 {
+		b.interval = sc.backoff.InitialInterval
+	} else {
+		b.interval = time.Duration(float64(b.interval) * sc.backoff.Multiplier)
+	}
+	if b.interval > sc.backoff.MaxInterval {
+		b.interval = sc.backoff.MaxInterval
+	}
+	// The scrape following the interval, rounded up to a multiple of the
+	// collection interval, probes the recovery.
+	b.skipped = int((b.interval+sc.collectionInterval-1)/sc.collectionInterval) - 1
+	sc.logger.Warn("Scrape failed, backing off", zap.Stringer("scraper", sc.scrapers[i].ID()), zap.Duration("interval", b.interval))
+	sc.obsScrapers[i].recordBackoffInterval(b.interval)
+}
+
+// jitterDelays returns the order of the scrapers and their random delays, sorted
+// by delay. The delays are nil when the scrapes are not jittered.
+func (sc *controller) jitterDelays(maxJitter time.Duration) ([]int, []time.Duration) {
+	order := make([]int, len(sc.scrapers))
+	for i := range order {
+		order[i] = i
+	}
+	if maxJitter <= 0 || sc.jitterRands == nil {
+		return order, nil
+	}
+	delays := make([]time.Duration, len(sc.scrapers))
+	for i, r := range sc.jitterRands {
+		delays[i] = time.Duration(r.Int63n(int64(maxJitter)))
+	}
+	sort.SliceStable(order, func(a, b int) bool { return delays[order[a]] < delays[order[b]] })
+	return order, delays
+}
+
+// waitUntil waits until the given time, and returns false if the controller
+// was stopped meanwhile.
+func (sc *controller) waitUntil(t time.Time) bool {
+	d := time.Until(t)
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-sc.done:
+		return false
+	}
 }
 
 // stopScraping stops the ticker
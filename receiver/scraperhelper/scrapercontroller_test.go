@@ -6,6 +6,9 @@ package scraperhelper
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -437,3 +440,460 @@ func TestScrapeControllerInitialDelay(t *testing.T) {
 
 	assert.NoError(t, r.Shutdown(context.Background()), "Must not error closing down")
 }
+
+func TestScrapeControllerShutdownDuringInitialDelay(t *testing.T) {
+	tsm := &testScrapeMetrics{ch: make(chan int, 1)}
+	scp, err := NewScraper("", tsm.scrape)
+	require.NoError(t, err, "Must not error when creating scraper")
+
+	r, err := NewScraperControllerReceiver(
+		&ScraperControllerSettings{
+			CollectionInterval: time.Hour,
+			InitialDelay:       time.Hour,
+		},
+		receivertest.NewNopCreateSettings(),
+		new(consumertest.MetricsSink),
+		AddScraper(scp),
+	)
+	require.NoError(t, err, "Must not error when creating receiver")
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()), "Must not error when starting")
+	assert.NoError(t, r.Shutdown(context.Background()), "Must not error closing down")
+	assert.Equal(t, 0, tsm.timesScrapeCalled, "Must not have scraped while waiting for the initial delay")
+}
+
+func TestScrapeControllerCollectOnStart(t *testing.T) {
+	tsm := &testScrapeMetrics{ch: make(chan int, 1)}
+	scp, err := NewScraper("", tsm.scrape)
+	require.NoError(t, err, "Must not error when creating scraper")
+
+	sink := new(consumertest.MetricsSink)
+	r, err := NewScraperControllerReceiver(
+		&ScraperControllerSettings{
+			CollectionInterval: time.Hour,
+			InitialDelay:       time.Hour,
+			InitialJitter:      time.Hour,
+			CollectOnStart:     true,
+		},
+		receivertest.NewNopCreateSettings(),
+		sink,
+		AddScraper(scp),
+	)
+	require.NoError(t, err, "Must not error when creating receiver")
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()), "Must not error when starting")
+	assert.Equal(t, 1, <-tsm.ch, "Must have scraped on start")
+	assert.Eventually(t, func() bool { return sink.DataPointCount() == 1 }, time.Second, time.Millisecond)
+	assert.NoError(t, r.Shutdown(context.Background()), "Must not error closing down")
+	assert.Equal(t, 1, tsm.timesScrapeCalled, "Must have scraped once")
+}
+
+func TestScrapeControllerFirstScrapeTime(t *testing.T) {
+	start := time.Date(2023, 10, 1, 12, 0, 10, 0, time.UTC)
+	tests := []struct {
+		name string
+		cfg  ScraperControllerSettings
+		want time.Time
+	}{
+		{
+			name: "immediately",
+			cfg:  ScraperControllerSettings{CollectionInterval: 30 * time.Second},
+			want: start,
+		},
+		{
+			name: "initial_delay",
+			cfg:  ScraperControllerSettings{CollectionInterval: 30 * time.Second, InitialDelay: 5 * time.Second},
+			want: start.Add(5 * time.Second),
+		},
+		{
+			name: "aligned",
+			cfg:  ScraperControllerSettings{CollectionInterval: 30 * time.Second, AlignWithInterval: true},
+			want: time.Date(2023, 10, 1, 12, 0, 30, 0, time.UTC),
+		},
+		{
+			name: "aligned_after_initial_delay",
+			cfg:  ScraperControllerSettings{CollectionInterval: 30 * time.Second, InitialDelay: 25 * time.Second, AlignWithInterval: true},
+			want: time.Date(2023, 10, 1, 12, 1, 0, 0, time.UTC),
+		},
+		{
+			name: "already_aligned",
+			cfg:  ScraperControllerSettings{CollectionInterval: 10 * time.Second, AlignWithInterval: true},
+			want: start,
+		},
+		{
+			name: "collect_on_start",
+			cfg:  ScraperControllerSettings{CollectionInterval: 30 * time.Second, InitialDelay: 5 * time.Second, CollectOnStart: true},
+			want: start,
+		},
+		{
+			name: "collect_on_start_aligned",
+			cfg:  ScraperControllerSettings{CollectionInterval: 30 * time.Second, InitialDelay: 25 * time.Second, AlignWithInterval: true, CollectOnStart: true},
+			want: time.Date(2023, 10, 1, 12, 0, 30, 0, time.UTC),
+		},
+		{
+			name: "collect_on_start_already_aligned",
+			cfg:  ScraperControllerSettings{CollectionInterval: 10 * time.Second, AlignWithInterval: true, CollectOnStart: true},
+			want: start.Add(10 * time.Second),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewScraperControllerReceiver(&tt.cfg, receivertest.NewNopCreateSettings(), new(consumertest.MetricsSink))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, r.(*controller).firstScrapeTime(start))
+		})
+	}
+}
+
+func TestScrapeControllerJitterDelays(t *testing.T) {
+	newController := func(seed int64) *controller {
+		var options []ScraperControllerOption
+		for _, name := range []string{"a", "b", "c"} {
+			scp, err := NewScraper(name, func(context.Context) (pmetric.Metrics, error) {
+				return pmetric.NewMetrics(), nil
+			})
+			require.NoError(t, err)
+			options = append(options, AddScraper(scp))
+		}
+		r, err := NewScraperControllerReceiver(
+			&ScraperControllerSettings{CollectionInterval: time.Minute, Jitter: 10 * time.Second, JitterSeed: seed},
+			receivertest.NewNopCreateSettings(),
+			new(consumertest.MetricsSink),
+			options...,
+		)
+		require.NoError(t, err)
+		return r.(*controller)
+	}
+
+	sc := newController(42)
+	order, delays := sc.jitterDelays(10 * time.Second)
+	require.Len(t, delays, 3)
+	for i := 1; i < len(order); i++ {
+		assert.LessOrEqual(t, delays[order[i-1]], delays[order[i]], "Must be sorted by delay")
+	}
+	for _, d := range delays {
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.Less(t, d, 10*time.Second)
+	}
+	// The scrapers have their own delays.
+	assert.False(t, delays[0] == delays[1] && delays[1] == delays[2])
+
+	// The same seed gives the same delays, another seed other delays.
+	_, same := newController(42).jitterDelays(10 * time.Second)
+	assert.Equal(t, delays, same)
+	_, other := newController(43).jitterDelays(10 * time.Second)
+	assert.NotEqual(t, delays, other)
+
+	order, delays = sc.jitterDelays(0)
+	assert.Equal(t, []int{0, 1, 2}, order)
+	assert.Nil(t, delays)
+}
+
+func TestScrapeControllerInitialJitter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("This requires real time to pass, skipping")
+		return
+	}
+
+	t.Parallel()
+
+	elapsed := make(chan time.Time, 1)
+	scp, err := NewScraper("timed", func(ctx context.Context) (pmetric.Metrics, error) {
+		elapsed <- time.Now()
+		return pmetric.NewMetrics(), nil
+	})
+	require.NoError(t, err, "Must not error when creating scraper")
+
+	set := receivertest.NewNopCreateSettings()
+	r, err := NewScraperControllerReceiver(
+		&ScraperControllerSettings{
+			CollectionInterval: time.Hour,
+			InitialJitter:      300 * time.Millisecond,
+			JitterSeed:         1,
+		},
+		set,
+		new(consumertest.MetricsSink),
+		AddScraper(scp),
+	)
+	require.NoError(t, err, "Must not error when creating receiver")
+	delay := time.Duration(rand.New(rand.NewSource(jitterSeed(1, set.ID, scp.ID()))).Int63n(int64(300 * time.Millisecond)))
+
+	t0 := time.Now()
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()), "Must not error when starting")
+	t1 := <-elapsed
+
+	assert.GreaterOrEqual(t, t1.Sub(t0), delay, "Must have waited for the initial jitter")
+
+	assert.NoError(t, r.Shutdown(context.Background()), "Must not error closing down")
+}
+
+func TestScrapeControllerShutdownDuringJitter(t *testing.T) {
+	tsm := &testScrapeMetrics{ch: make(chan int, 1)}
+	scp, err := NewScraper("", tsm.scrape)
+	require.NoError(t, err, "Must not error when creating scraper")
+
+	r, err := NewScraperControllerReceiver(
+		&ScraperControllerSettings{
+			CollectionInterval: time.Hour,
+			InitialJitter:      time.Hour,
+			JitterSeed:         1,
+		},
+		receivertest.NewNopCreateSettings(),
+		new(consumertest.MetricsSink),
+		AddScraper(scp),
+	)
+	require.NoError(t, err, "Must not error when creating receiver")
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()), "Must not error when starting")
+	assert.NoError(t, r.Shutdown(context.Background()), "Must not error closing down")
+	assert.Equal(t, 0, tsm.timesScrapeCalled, "Must not have scraped while waiting for the jitter")
+}
+
+func TestScrapeControllerTimeout(t *testing.T) {
+	receiverID := component.NewID("receiver")
+	tt, err := obsreporttest.SetupTelemetry(receiverID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	release := make(chan struct{})
+	stuckCalls := make(chan struct{}, 10)
+	stuck, err := NewScraper("stuck", func(context.Context) (pmetric.Metrics, error) {
+		stuckCalls <- struct{}{}
+		// Ignores the context deadline.
+		<-release
+		return pmetric.NewMetrics(), nil
+	})
+	require.NoError(t, err)
+	tsm := &testScrapeMetrics{ch: make(chan int, 10)}
+	scp, err := NewScraper("scraper", tsm.scrape)
+	require.NoError(t, err)
+
+	sink := new(consumertest.MetricsSink)
+	tickerCh := make(chan time.Time)
+	r, err := NewScraperControllerReceiver(
+		&ScraperControllerSettings{CollectionInterval: time.Hour, Timeout: 50 * time.Millisecond},
+		receiver.CreateSettings{ID: receiverID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+		sink,
+		AddScraper(stuck),
+		AddScraper(scp),
+		WithTickerChannel(tickerCh),
+	)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The stuck scraper does not delay the other scraper.
+	assert.Equal(t, 1, <-tsm.ch)
+	assert.Eventually(t, func() bool { return len(sink.AllMetrics()) == 1 }, time.Second, 10*time.Millisecond)
+
+	// The stuck scraper is skipped while its scrape is still running.
+	tickerCh <- time.Now()
+	assert.Equal(t, 2, <-tsm.ch)
+	assert.Eventually(t, func() bool { return len(sink.AllMetrics()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Len(t, stuckCalls, 1)
+
+	// The stuck scraper is scraped again once its scrape returned.
+	close(release)
+	assert.Eventually(t, func() bool { return !r.(*controller).scraping[0].Load() }, time.Second, 10*time.Millisecond)
+	tickerCh <- time.Now()
+	assert.Equal(t, 3, <-tsm.ch)
+	assert.Len(t, stuckCalls, 2)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	require.NoError(t, obsreporttest.CheckScraperTimedOutScrapes(tt, receiverID, component.NewID("stuck"), 1))
+}
+
+func TestScrapeControllerBackoff(t *testing.T) {
+	receiverID := component.NewID("receiver")
+	tt, err := obsreporttest.SetupTelemetry(receiverID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	var down atomic.Bool
+	down.Store(true)
+	var scrapes atomic.Int32
+	scp, err := NewScraper("scraper", func(context.Context) (pmetric.Metrics, error) {
+		scrapes.Add(1)
+		if down.Load() {
+			return pmetric.NewMetrics(), errors.New("target is down")
+		}
+		return pmetric.NewMetrics(), nil
+	})
+	require.NoError(t, err)
+
+	sink := new(consumertest.MetricsSink)
+	tickerCh := make(chan time.Time)
+	r, err := NewScraperControllerReceiver(
+		&ScraperControllerSettings{
+			CollectionInterval: time.Minute,
+			Backoff: BackoffSettings{
+				Enabled:         true,
+				InitialInterval: 2 * time.Minute,
+				MaxInterval:     4 * time.Minute,
+				Multiplier:      3,
+			},
+		},
+		receiver.CreateSettings{ID: receiverID, TelemetrySettings: tt.TelemetrySettings, BuildInfo: component.NewDefaultBuildInfo()},
+		sink,
+		AddScraper(scp),
+		WithTickerChannel(tickerCh),
+	)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	cycles := 1
+	waitCycle := func() {
+		assert.Eventually(t, func() bool { return len(sink.AllMetrics()) == cycles }, time.Second, time.Millisecond)
+	}
+	tick := func() {
+		tickerCh <- time.Now()
+		cycles++
+		waitCycle()
+	}
+
+	// The first failed scrape backs off for 2 minutes, skipping the next scrape.
+	waitCycle()
+	require.NoError(t, obsreporttest.CheckScraperBackoffInterval(tt, receiverID, scp.ID(), 2*time.Minute.Milliseconds()))
+	tick()
+	assert.EqualValues(t, 1, scrapes.Load())
+
+	// The probe fails too and backs off for the max interval, skipping 3 scrapes.
+	tick()
+	assert.EqualValues(t, 2, scrapes.Load())
+	require.NoError(t, obsreporttest.CheckScraperBackoffInterval(tt, receiverID, scp.ID(), 4*time.Minute.Milliseconds()))
+	down.Store(false)
+	tick()
+	tick()
+	tick()
+	assert.EqualValues(t, 2, scrapes.Load())
+
+	// The probe succeeds and stops the backoff.
+	tick()
+	assert.EqualValues(t, 3, scrapes.Load())
+	require.NoError(t, obsreporttest.CheckScraperBackoffInterval(tt, receiverID, scp.ID(), 0))
+	tick()
+	assert.EqualValues(t, 4, scrapes.Load())
+
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestScrapeControllerMaxConcurrentScrapers(t *testing.T) {
+	for _, maxConcurrentScrapers := range []int{0, 1, 2, 4} {
+		maxConcurrentScrapers := maxConcurrentScrapers
+		t.Run(fmt.Sprint(maxConcurrentScrapers), func(t *testing.T) {
+			var running, maxRunning atomic.Int32
+			var options []ScraperControllerOption
+			for i := 0; i < 4; i++ {
+				i := i
+				scp, err := NewScraper(fmt.Sprint("scraper", i), func(context.Context) (pmetric.Metrics, error) {
+					n := running.Add(1)
+					defer running.Add(-1)
+					for {
+						m := maxRunning.Load()
+						if n <= m || maxRunning.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					time.Sleep(20 * time.Millisecond)
+					md := pmetric.NewMetrics()
+					md.ResourceMetrics().AppendEmpty().Resource().Attributes().PutInt("scraper", int64(i))
+					return md, nil
+				})
+				require.NoError(t, err)
+				options = append(options, AddScraper(scp))
+			}
+
+			sink := new(consumertest.MetricsSink)
+			r, err := NewScraperControllerReceiver(
+				&ScraperControllerSettings{CollectionInterval: time.Hour, MaxConcurrentScrapers: maxConcurrentScrapers},
+				receivertest.NewNopCreateSettings(),
+				sink,
+				options...,
+			)
+			require.NoError(t, err)
+			require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+			assert.Eventually(t, func() bool { return len(sink.AllMetrics()) == 1 }, time.Second, time.Millisecond)
+			require.NoError(t, r.Shutdown(context.Background()))
+
+			want := int32(maxConcurrentScrapers)
+			if want < 1 {
+				want = 1
+			}
+			assert.Equal(t, want, maxRunning.Load())
+
+			// The metrics are in the order of the scrapers.
+			rms := sink.AllMetrics()[0].ResourceMetrics()
+			require.Equal(t, 4, rms.Len())
+			for i := 0; i < rms.Len(); i++ {
+				v, _ := rms.At(i).Resource().Attributes().Get("scraper")
+				assert.EqualValues(t, i, v.Int())
+			}
+		})
+	}
+}
+
+func TestScrapeControllerTriggerScrape(t *testing.T) {
+	scrapeErr := errors.New("err1")
+	tsm := &testScrapeMetrics{ch: make(chan int, 10)}
+	scp, err := NewScraper("scraper", tsm.scrape)
+	require.NoError(t, err)
+	failing, err := NewScraper("failing", func(context.Context) (pmetric.Metrics, error) {
+		return pmetric.NewMetrics(), scrapeErr
+	})
+	require.NoError(t, err)
+
+	sink := new(consumertest.MetricsSink)
+	r, err := NewScraperControllerReceiver(
+		&ScraperControllerSettings{CollectionInterval: time.Hour},
+		receivertest.NewNopCreateSettings(),
+		sink,
+		AddScraper(scp),
+		AddScraper(failing),
+	)
+	require.NoError(t, err)
+	trigger, ok := r.(ScrapeTrigger)
+	require.True(t, ok)
+
+	_, err = trigger.TriggerScrape(context.Background())
+	assert.ErrorIs(t, err, errNotScraping)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	md, err := trigger.TriggerScrape(context.Background())
+	assert.ErrorIs(t, err, scrapeErr)
+	assert.Equal(t, 1, md.DataPointCount())
+	// The initial scrape and the on-demand one.
+	assert.Equal(t, 2, tsm.timesScrapeCalled)
+	require.Len(t, sink.AllMetrics(), 2)
+	assert.Equal(t, md, sink.AllMetrics()[1])
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	_, err = trigger.TriggerScrape(context.Background())
+	assert.ErrorIs(t, err, errNotScraping)
+}
+
+func TestScrapeControllerTriggerScrapeContextDone(t *testing.T) {
+	scraping := make(chan struct{})
+	release := make(chan struct{})
+	scp, err := NewScraper("scraper", func(context.Context) (pmetric.Metrics, error) {
+		close(scraping)
+		<-release
+		return pmetric.NewMetrics(), nil
+	})
+	require.NoError(t, err)
+	r, err := NewScraperControllerReceiver(
+		&ScraperControllerSettings{CollectionInterval: time.Hour},
+		receivertest.NewNopCreateSettings(),
+		new(consumertest.MetricsSink),
+		AddScraper(scp),
+	)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The on-demand scrape waits for the initial scrape.
+	<-scraping
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = r.(ScrapeTrigger).TriggerScrape(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	close(release)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
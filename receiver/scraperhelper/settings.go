@@ -14,7 +14,11 @@ import (
 )
 
 var (
-	errNonPositiveInterval = errors.New("requires positive value")
+	errNonPositiveInterval  = errors.New("requires positive value")
+	errJitterTooLarge       = errors.New(`must be less than "collection_interval"`)
+	errMaxIntervalTooSmall  = errors.New(`must be greater than or equal to "initial_interval"`)
+	errMultiplierOutOfRange = errors.New("must be greater than or equal to 1")
+	errNegativeValue        = errors.New("must not be negative")
 )
 
 // ScraperControllerSettings defines common settings for a scraper controller
@@ -28,8 +32,60 @@ type ScraperControllerSettings struct {
 	// InitialDelay sets the initial start delay for the scraper,
 	// any non positive value is assumed to be immediately.
 	InitialDelay time.Duration `mapstructure:"initial_delay"`
-	// Timeout is an optional value used to set scraper's context deadline.
+	// AlignWithInterval defers the first scrape, after the initial delay, to
+	// the next multiple of the collection interval, e.g. :00 and :30 with a
+	// 30s interval, so the scrapes of all the collectors happen at the same
+	// instants and their timestamps are comparable.
+	AlignWithInterval bool `mapstructure:"align_with_interval"`
+	// CollectOnStart scrapes immediately when the controller starts, without
+	// waiting for the initial delay, so short-lived collectors and the tests
+	// of configurations get metrics right away. The next scrapes happen every
+	// collection interval after, aligned with it if configured.
+	CollectOnStart bool `mapstructure:"collect_on_start"`
+	// Timeout is an optional value used to set the context deadline of each
+	// scrape of each scraper. A scrape exceeding it is abandoned and counted
+	// as timed out, so it cannot delay the other scrapers, and the scraper is
+	// skipped until its scrape returns.
 	Timeout time.Duration `mapstructure:"timeout"`
+	// InitialJitter sets the maximum random delay added to the initial delay
+	// of each scraper, so the first scrapes of many collectors started at once
+	// are spread over time.
+	InitialJitter time.Duration `mapstructure:"initial_jitter"`
+	// Jitter sets the maximum random delay of each scrape of each scraper
+	// after the collection interval elapsed, so the scrapes of many collectors
+	// sharing targets don't happen at the same instant. It must be less than
+	// the collection interval.
+	Jitter time.Duration `mapstructure:"jitter"`
+	// JitterSeed seeds the random delays of the scrapers when not zero, so they
+	// are deterministic for a given receiver and scraper. Otherwise the delays
+	// are seeded randomly.
+	JitterSeed int64 `mapstructure:"jitter_seed"`
+	// Backoff sets the backoff of each scraper after failed scrapes.
+	Backoff BackoffSettings `mapstructure:"backoff"`
+	// MaxConcurrentScrapers sets the maximum number of scrapers scraped
+	// concurrently at each collection interval, so the scrapes of receivers
+	// with many scrapers don't stretch past the interval. Each scraper is
+	// still scraped once per collection interval. The scrapers are scraped
+	// sequentially when 0 or 1.
+	MaxConcurrentScrapers int `mapstructure:"max_concurrent_scrapers"`
+}
+
+// BackoffSettings defines the backoff of a scraper after failed scrapes, for
+// instance when its target is down. The scrapes of the scraper are skipped
+// until the backoff interval elapsed, rounded up to a multiple of the
+// collection interval, then the next scrape probes whether the target
+// recovered. The backoff interval grows after each consecutive failed scrape,
+// and the backoff stops after a successful one.
+type BackoffSettings struct {
+	// Enabled enables the backoff of the scrapers.
+	Enabled bool `mapstructure:"enabled"`
+	// InitialInterval is the backoff interval after the first failed scrape.
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	// MaxInterval is the upper bound on the backoff interval.
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+	// Multiplier multiplies the backoff interval after each consecutive
+	// failed scrape.
+	Multiplier float64 `mapstructure:"multiplier"`
 }
 
 // NewDefaultScraperControllerSettings returns default scraper controller
@@ -39,6 +95,12 @@ func NewDefaultScraperControllerSettings(component.Type) ScraperControllerSettin
 		CollectionInterval: time.Minute,
 		InitialDelay:       time.Second,
 		Timeout:            0,
+		Backoff: BackoffSettings{
+			Enabled:         false,
+			InitialInterval: 2 * time.Minute,
+			MaxInterval:     30 * time.Minute,
+			Multiplier:      2,
+		},
 	}
 }
 
@@ -49,5 +111,34 @@ func (set *ScraperControllerSettings) Validate() (errs error) {
 	if set.Timeout < 0 {
 		errs = multierr.Append(errs, fmt.Errorf(`"timeout": %w`, errNonPositiveInterval))
 	}
+	if set.InitialJitter < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"initial_jitter": %w`, errNonPositiveInterval))
+	}
+	if set.Jitter < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"jitter": %w`, errNonPositiveInterval))
+	} else if set.CollectionInterval > 0 && set.Jitter >= set.CollectionInterval {
+		errs = multierr.Append(errs, fmt.Errorf(`"jitter": %w`, errJitterTooLarge))
+	}
+	if set.MaxConcurrentScrapers < 0 {
+		errs = multierr.Append(errs, fmt.Errorf(`"max_concurrent_scrapers": %w`, errNegativeValue))
+	}
+	if set.Backoff.Enabled {
+		if err := set.Backoff.validate(); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf(`"backoff": %w`, err))
+		}
+	}
 	return errs
 }
+
+func (set *BackoffSettings) validate() error {
+	if set.InitialInterval <= 0 {
+		return fmt.Errorf(`"initial_interval": %w`, errNonPositiveInterval)
+	}
+	if set.MaxInterval < set.InitialInterval {
+		return fmt.Errorf(`"max_interval": %w`, errMaxIntervalTooSmall)
+	}
+	if set.Multiplier < 1 {
+		return fmt.Errorf(`"multiplier": %w`, errMultiplierOutOfRange)
+	}
+	return nil
+}
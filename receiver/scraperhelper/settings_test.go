@@ -36,6 +36,73 @@ func TestScrapeControllerSettings(t *testing.T) {
 			},
 			errVal: `"timeout": requires positive value`,
 		},
+		{
+			name: "invalid jitters",
+			set: ScraperControllerSettings{
+				CollectionInterval: time.Minute,
+				InitialJitter:      -1 * time.Minute,
+				Jitter:             -1 * time.Minute,
+			},
+			errVal: `"initial_jitter": requires positive value; "jitter": requires positive value`,
+		},
+		{
+			name: "jitter not less than collection interval",
+			set: ScraperControllerSettings{
+				CollectionInterval: time.Minute,
+				Jitter:             time.Minute,
+			},
+			errVal: `"jitter": must be less than "collection_interval"`,
+		},
+		{
+			name: "negative max concurrent scrapers",
+			set: ScraperControllerSettings{
+				CollectionInterval:    time.Minute,
+				MaxConcurrentScrapers: -1,
+			},
+			errVal: `"max_concurrent_scrapers": must not be negative`,
+		},
+		{
+			name: "invalid backoff",
+			set: ScraperControllerSettings{
+				CollectionInterval: time.Minute,
+				Backoff:            BackoffSettings{Enabled: true, MaxInterval: time.Minute, Multiplier: 2},
+			},
+			errVal: `"backoff": "initial_interval": requires positive value`,
+		},
+		{
+			name: "backoff max interval less than initial interval",
+			set: ScraperControllerSettings{
+				CollectionInterval: time.Minute,
+				Backoff:            BackoffSettings{Enabled: true, InitialInterval: time.Hour, MaxInterval: time.Minute, Multiplier: 2},
+			},
+			errVal: `"backoff": "max_interval": must be greater than or equal to "initial_interval"`,
+		},
+		{
+			name: "backoff multiplier less than one",
+			set: ScraperControllerSettings{
+				CollectionInterval: time.Minute,
+				Backoff:            BackoffSettings{Enabled: true, InitialInterval: time.Minute, MaxInterval: time.Hour, Multiplier: 0.5},
+			},
+			errVal: `"backoff": "multiplier": must be greater than or equal to 1`,
+		},
+		{
+			name: "disabled backoff",
+			set: ScraperControllerSettings{
+				CollectionInterval: time.Minute,
+				Backoff:            BackoffSettings{Enabled: false},
+			},
+			errVal: "",
+		},
+		{
+			name: "valid jitters",
+			set: ScraperControllerSettings{
+				CollectionInterval: time.Minute,
+				InitialJitter:      time.Hour,
+				Jitter:             30 * time.Second,
+				JitterSeed:         1,
+			},
+			errVal: "",
+		},
 	} {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
@@ -95,8 +95,8 @@ func (bes *Extensions) NotifyConfig(ctx context.Context, conf *confmap.Conf) err
 	for _, extID := range bes.extensionIDs {
 		ext := bes.extMap[extID]
 		if cw, ok := ext.(extension.ConfigWatcher); ok {
-			clonedConf := confmap.NewFromStringMap(conf.ToStringMap())
-			errs = multierr.Append(errs, cw.NotifyConfig(ctx, clonedConf))
+			// The secrets retrieved by the config providers are redacted from the configuration notified.
+			errs = multierr.Append(errs, cw.NotifyConfig(ctx, conf.Redacted()))
 		}
 	}
 	return errs
@@ -79,6 +79,7 @@ require (
 	github.com/tklauser/go-sysconf v0.3.12 // indirect
 	github.com/tklauser/numcpus v0.6.1 // indirect
 	github.com/yusufpapurcu/wmi v1.2.3 // indirect
+	go.opentelemetry.io/collector/config/configopaque v0.88.0 // indirect
 	go.opentelemetry.io/contrib/zpages v0.45.0 // indirect
 	go.opentelemetry.io/otel/exporters/otlp/otlptrace v1.20.0 // indirect
 	go.opentelemetry.io/proto/otlp v1.0.0 // indirect
@@ -111,6 +112,8 @@ replace go.opentelemetry.io/collector/confmap => ../confmap
 
 replace go.opentelemetry.io/collector/config/configtelemetry => ../config/configtelemetry
 
+replace go.opentelemetry.io/collector/config/configopaque => ../config/configopaque
+
 replace go.opentelemetry.io/collector/processor => ../processor
 
 replace go.opentelemetry.io/collector/consumer => ../consumer
@@ -22,6 +22,7 @@ import (
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/service/internal/capabilityconsumer"
+	"go.opentelemetry.io/collector/service/internal/components"
 	"go.opentelemetry.io/collector/service/internal/servicetelemetry"
 	"go.opentelemetry.io/collector/service/pipelines"
 )
@@ -96,6 +97,10 @@ func (g *Graph) createNodes(set Settings) error {
 
 		pipe.capabilitiesNode = newCapabilitiesNode(pipelineID)
 
+		if err := checkProcessorsOrder(set, pipelineID, pipelineCfg.Processors); err != nil {
+			return err
+		}
+
 		for _, procID := range pipelineCfg.Processors {
 			procNode := g.createProcessor(pipelineID, procID)
 			pipe.processors = append(pipe.processors, procNode)
@@ -174,6 +179,37 @@ func (g *Graph) createNodes(set Settings) error {
 	return nil
 }
 
+// checkProcessorsOrder checks that the processors of a pipeline are placed according to the ordering
+// constraints of their factories. It fails for the required constraints and logs a warning for the others.
+func checkProcessorsOrder(set Settings, pipelineID component.ID, procIDs []component.ID) error {
+	for i, procID := range procIDs {
+		// A missing factory is reported when the processor is built.
+		f, ok := set.ProcessorBuilder.Factory(procID.Type()).(processor.Factory)
+		if !ok {
+			continue
+		}
+		constraint := f.OrderingConstraint()
+		switch constraint.Position {
+		case processor.PositionFirst:
+			if i == 0 {
+				continue
+			}
+		case processor.PositionLast:
+			if i == len(procIDs)-1 {
+				continue
+			}
+		default:
+			continue
+		}
+		if constraint.Required {
+			return fmt.Errorf("processor %q must be the %s processor in pipeline %q", procID, constraint.Position, pipelineID)
+		}
+		components.ProcessorLogger(set.Telemetry.Logger, procID, pipelineID).Warn(
+			fmt.Sprintf("The processor should be the %s processor in the pipeline", constraint.Position))
+	}
+	return nil
+}
+
 func (g *Graph) createReceiver(pipelineID, recvID component.ID) *receiverNode {
 	rcvrNode := newReceiverNode(pipelineID.Type(), recvID)
 	if node := g.componentGraph.Node(rcvrNode.ID()); node != nil {
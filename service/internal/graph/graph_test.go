@@ -12,6 +12,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 	"gonum.org/v1/gonum/graph/simple"
 
 	"go.opentelemetry.io/collector/component"
@@ -1995,6 +1997,91 @@ func TestGraphBuildErrors(t *testing.T) {
 	}
 }
 
+func TestGraphProcessorsOrder(t *testing.T) {
+	nopReceiverFactory := receivertest.NewNopFactory()
+	nopProcessorFactory := processortest.NewNopFactory()
+	nopExporterFactory := exportertest.NewNopFactory()
+	createTraces := func(ctx context.Context, set processor.CreateSettings, cfg component.Config, next consumer.Traces) (processor.Traces, error) {
+		return nopProcessorFactory.CreateTracesProcessor(ctx, set, cfg, next)
+	}
+	firstProcessorFactory := processor.NewFactory("first", nopProcessorFactory.CreateDefaultConfig,
+		processor.WithTraces(createTraces, component.StabilityLevelStable),
+		processor.WithOrderingConstraint(processor.OrderingConstraint{Position: processor.PositionFirst, Required: true}))
+	lastProcessorFactory := processor.NewFactory("last", nopProcessorFactory.CreateDefaultConfig,
+		processor.WithTraces(createTraces, component.StabilityLevelStable),
+		processor.WithOrderingConstraint(processor.OrderingConstraint{Position: processor.PositionLast}))
+
+	tests := []struct {
+		name        string
+		processors  []component.ID
+		expectedErr string
+		warnings    int
+	}{
+		{
+			name:       "respected",
+			processors: []component.ID{component.NewID("first"), component.NewID("nop"), component.NewID("last")},
+		},
+		{
+			name:        "required_violated",
+			processors:  []component.ID{component.NewID("nop"), component.NewID("first")},
+			expectedErr: `processor "first" must be the first processor in pipeline "traces"`,
+		},
+		{
+			name:       "warning",
+			processors: []component.ID{component.NewID("last"), component.NewID("nop")},
+			warnings:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			core, logs := observer.New(zap.WarnLevel)
+			telemetry := servicetelemetry.NewNopTelemetrySettings()
+			telemetry.Logger = zap.New(core)
+			set := Settings{
+				BuildInfo: component.NewDefaultBuildInfo(),
+				Telemetry: telemetry,
+				ReceiverBuilder: receiver.NewBuilder(
+					map[component.ID]component.Config{component.NewID("nop"): nopReceiverFactory.CreateDefaultConfig()},
+					map[component.Type]receiver.Factory{nopReceiverFactory.Type(): nopReceiverFactory}),
+				ProcessorBuilder: processor.NewBuilder(
+					map[component.ID]component.Config{
+						component.NewID("nop"):   nopProcessorFactory.CreateDefaultConfig(),
+						component.NewID("first"): firstProcessorFactory.CreateDefaultConfig(),
+						component.NewID("last"):  lastProcessorFactory.CreateDefaultConfig(),
+					},
+					map[component.Type]processor.Factory{
+						nopProcessorFactory.Type():   nopProcessorFactory,
+						firstProcessorFactory.Type(): firstProcessorFactory,
+						lastProcessorFactory.Type():  lastProcessorFactory,
+					}),
+				ExporterBuilder: exporter.NewBuilder(
+					map[component.ID]component.Config{component.NewID("nop"): nopExporterFactory.CreateDefaultConfig()},
+					map[component.Type]exporter.Factory{nopExporterFactory.Type(): nopExporterFactory}),
+				ConnectorBuilder: connector.NewBuilder(map[component.ID]component.Config{}, map[component.Type]connector.Factory{}),
+				PipelineConfigs: pipelines.Config{
+					component.NewID("traces"): {
+						Receivers:  []component.ID{component.NewID("nop")},
+						Processors: test.processors,
+						Exporters:  []component.ID{component.NewID("nop")},
+					},
+				},
+			}
+			_, err := Build(context.Background(), set)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			warnings := logs.FilterMessage("The processor should be the last processor in the pipeline").All()
+			require.Len(t, warnings, test.warnings)
+			for _, warning := range warnings {
+				assert.Equal(t, "last", warning.ContextMap()["name"])
+			}
+		})
+	}
+}
+
 // This includes all tests from the previous implmentation, plus a new one
 // relevant only to the new graph-based implementation.
 func TestGraphFailToStartAndShutdown(t *testing.T) {
@@ -4,18 +4,28 @@
 package graph // import "go.opentelemetry.io/collector/service/internal/graph"
 
 import (
+	"context"
 	"net/http"
 	"sort"
+	"strconv"
+	"time"
 
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
 	"go.opentelemetry.io/collector/service/internal/zpages"
 )
 
 const (
 
 	// URL Params
-	zPipelineName  = "pipelinenamez"
-	zComponentName = "componentnamez"
-	zComponentKind = "componentkindz"
+	zPipelineName       = "pipelinenamez"
+	zComponentName      = "componentnamez"
+	zComponentKind      = "componentkindz"
+	zScrapeReceiverName = "zscrapereceivername"
+
+	// zScrapeTimeout is the timeout of the scrapes triggered from the zpages.
+	zScrapeTimeout = 30 * time.Second
 )
 
 func (g *Graph) HandleZPages(w http.ResponseWriter, r *http.Request) {
@@ -79,3 +89,84 @@ func (g *Graph) HandleZPages(w http.ResponseWriter, r *http.Request) {
 	}
 	zpages.WriteHTMLPageFooter(w)
 }
+
+// HandleScrapeZPages lists the receivers able to scrape on demand, see scraperhelper.ScrapeTrigger,
+// and shows the metrics scraped by the receiver given in the query.
+func (g *Graph) HandleScrapeZPages(w http.ResponseWriter, r *http.Request) {
+	receiverName := r.URL.Query().Get(zScrapeReceiverName)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	zpages.WriteHTMLPageHeader(w, zpages.HeaderData{Title: "Scrapers"})
+
+	triggers := g.scrapeTriggers()
+	sumData := zpages.SummaryScrapersTableData{}
+	sumData.Rows = make([]zpages.SummaryScrapersTableRowData, 0, len(triggers))
+	for id := range triggers {
+		sumData.Rows = append(sumData.Rows, zpages.SummaryScrapersTableRowData{FullName: id.String()})
+	}
+	sort.Slice(sumData.Rows, func(i, j int) bool {
+		return sumData.Rows[i].FullName < sumData.Rows[j].FullName
+	})
+	zpages.WriteHTMLScrapersSummaryTable(w, sumData)
+
+	if receiverName != "" {
+		zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+			Name: "receiver: " + receiverName,
+		})
+		writeScrapeResult(r.Context(), w, triggers, receiverName)
+	}
+	zpages.WriteHTMLPageFooter(w)
+}
+
+// scrapeTriggers returns the metrics receivers able to scrape on demand, by ID.
+func (g *Graph) scrapeTriggers() map[component.ID]scraperhelper.ScrapeTrigger {
+	triggers := make(map[component.ID]scraperhelper.ScrapeTrigger)
+	for _, pg := range g.pipelines {
+		for _, recvNode := range pg.receivers {
+			// Skip connectors, they do not scrape.
+			if recvNode, ok := g.componentGraph.Node(recvNode.ID()).(*receiverNode); ok && recvNode.pipelineType == component.DataTypeMetrics {
+				if trigger, ok := recvNode.Component.(scraperhelper.ScrapeTrigger); ok {
+					triggers[recvNode.componentID] = trigger
+				}
+			}
+		}
+	}
+	return triggers
+}
+
+// writeScrapeResult triggers a scrape of the receiver, and writes the scraped metrics.
+func writeScrapeResult(ctx context.Context, w http.ResponseWriter, triggers map[component.ID]scraperhelper.ScrapeTrigger, receiverName string) {
+	var trigger scraperhelper.ScrapeTrigger
+	for id, t := range triggers {
+		if id.String() == receiverName {
+			trigger = t
+		}
+	}
+	if trigger == nil {
+		zpages.WriteHTMLPropertiesTable(w, zpages.PropertiesTableData{Name: "Scrape", Properties: [][2]string{
+			{"Error", "the receiver does not scrape on demand"},
+		}})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, zScrapeTimeout)
+	defer cancel()
+	start := time.Now()
+	md, err := trigger.TriggerScrape(ctx)
+	props := [][2]string{
+		{"Duration", time.Since(start).String()},
+		{"Metrics", strconv.Itoa(md.MetricCount())},
+		{"DataPoints", strconv.Itoa(md.DataPointCount())},
+	}
+	if err != nil {
+		props = append(props, [2]string{"Error", err.Error()})
+	}
+	zpages.WriteHTMLPropertiesTable(w, zpages.PropertiesTableData{Name: "Scrape", Properties: props})
+
+	jsonMetrics, err := (&pmetric.JSONMarshaler{}).MarshalMetrics(md)
+	if err != nil {
+		zpages.WriteHTMLScrapeResult(w, zpages.ScrapeResultData{Name: "Metrics", Metrics: err.Error()})
+		return
+	}
+	zpages.WriteHTMLScrapeResult(w, zpages.ScrapeResultData{Name: "Metrics", Metrics: string(jsonMetrics)})
+}
@@ -129,6 +129,7 @@ func InitOpenTelemetry(res *resource.Resource, options []sdkmetric.Option, disab
 	opts := []sdkmetric.Option{
 		sdkmetric.WithResource(res),
 		sdkmetric.WithView(batchViews(disableHighCardinality)...),
+		sdkmetric.WithView(processorViews()...),
 	}
 
 	opts = append(opts, options...)
@@ -152,6 +153,19 @@ func InitPrometheusServer(registry *prometheus.Registry, address string, asyncEr
 	return server
 }
 
+// processorViews sets the buckets of the processor durations, as the processing of a batch usually takes less than a millisecond.
+func processorViews() []sdkmetric.View {
+	boundaries := []float64{0, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+	var views []sdkmetric.View
+	for _, name := range []string{"processor/processing_duration", "processor/await_duration"} {
+		views = append(views, sdkmetric.NewView(
+			sdkmetric.Instrument{Name: name},
+			sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{Boundaries: boundaries}},
+		))
+	}
+	return views
+}
+
 func batchViews(disableHighCardinality bool) []sdkmetric.View {
 	views := []sdkmetric.View{
 		sdkmetric.NewView(
@@ -168,6 +182,12 @@ func batchViews(disableHighCardinality bool) []sdkmetric.View {
 					1000_000, 2000_000, 3000_000, 4000_000, 5000_000, 6000_000, 7000_000, 8000_000, 9000_000},
 			}},
 		),
+		sdkmetric.NewView(
+			sdkmetric.Instrument{Name: processorhelper.BuildCustomMetricName("batch", "batch_fill_ratio")},
+			sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{
+				Boundaries: []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1, 1.5, 2, 5, 10},
+			}},
+		),
 	}
 	if disableHighCardinality {
 		views = append(views, sdkmetric.NewView(sdkmetric.Instrument{
@@ -41,6 +41,14 @@ var (
 	propertiesTableBytes    []byte
 	propertiesTableTemplate = parseTemplate("properties_table", propertiesTableBytes)
 
+	//go:embed templates/scrapers_table.html
+	scrapersTableBytes    []byte
+	scrapersTableTemplate = parseTemplate("scrapers_table", scrapersTableBytes)
+
+	//go:embed templates/scrape_result.html
+	scrapeResultBytes    []byte
+	scrapeResultTemplate = parseTemplate("scrape_result", scrapeResultBytes)
+
 	//go:embed templates/features_table.html
 	featuresTableBytes    []byte
 	featuresTableTemplate = parseTemplate("features_table", featuresTableBytes)
@@ -104,6 +112,38 @@ func WriteHTMLPipelinesSummaryTable(w io.Writer, spd SummaryPipelinesTableData)
 	}
 }
 
+// SummaryScrapersTableData contains data for the table of the receivers scraping on demand.
+type SummaryScrapersTableData struct {
+	Rows []SummaryScrapersTableRowData
+}
+
+// SummaryScrapersTableRowData contains data for one row in the table of the receivers scraping on demand.
+type SummaryScrapersTableRowData struct {
+	FullName string
+}
+
+// WriteHTMLScrapersSummaryTable writes the table of the receivers scraping on demand.
+// It does not write the header or footer.
+func WriteHTMLScrapersSummaryTable(w io.Writer, ssd SummaryScrapersTableData) {
+	if err := scrapersTableTemplate.Execute(w, ssd); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
+
+// ScrapeResultData contains data for the scrape result template.
+type ScrapeResultData struct {
+	Name string
+	// Metrics are the scraped metrics, serialized.
+	Metrics string
+}
+
+// WriteHTMLScrapeResult writes the metrics scraped on demand.
+func WriteHTMLScrapeResult(w io.Writer, srd ScrapeResultData) {
+	if err := scrapeResultTemplate.Execute(w, srd); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
+
 // ComponentHeaderData contains data for component header template.
 type ComponentHeaderData struct {
 	Name              string
@@ -66,6 +66,16 @@ func TestNoCrash(t *testing.T) {
 			}},
 		})
 	})
+	assert.NotPanics(t, func() {
+		WriteHTMLScrapersSummaryTable(buf, SummaryScrapersTableData{
+			Rows: []SummaryScrapersTableRowData{{
+				FullName: "hostmetrics",
+			}},
+		})
+	})
+	assert.NotPanics(t, func() {
+		WriteHTMLScrapeResult(buf, ScrapeResultData{Name: "Metrics", Metrics: "{}"})
+	})
 	assert.NotPanics(t, func() {
 		WriteHTMLPropertiesTable(buf, PropertiesTableData{Name: "Bar", Properties: [][2]string{{"key", "value"}}})
 	})
@@ -138,6 +138,35 @@ func New(ctx context.Context, set Settings, cfg Config) (*Service, error) {
 	return srv, nil
 }
 
+// Validate checks the extensions and pipelines of the service can be built from the configuration: the
+// components are created, without being started, and the graph of the pipelines is checked, e.g. for
+// cycles or components not supporting the data types of their pipelines.
+func Validate(ctx context.Context, set Settings, cfg Config) error {
+	tel := servicetelemetry.NewNopTelemetrySettings()
+	extensionsSettings := extensions.Settings{
+		Telemetry:  tel,
+		BuildInfo:  set.BuildInfo,
+		Extensions: set.Extensions,
+	}
+	if _, err := extensions.New(ctx, extensionsSettings, cfg.Extensions); err != nil {
+		return fmt.Errorf("failed to build extensions: %w", err)
+	}
+
+	pSet := graph.Settings{
+		Telemetry:        tel,
+		BuildInfo:        set.BuildInfo,
+		ReceiverBuilder:  set.Receivers,
+		ProcessorBuilder: set.Processors,
+		ExporterBuilder:  set.Exporters,
+		ConnectorBuilder: set.Connectors,
+		PipelineConfigs:  cfg.Pipelines,
+	}
+	if _, err := graph.Build(ctx, pSet); err != nil {
+		return fmt.Errorf("failed to build pipelines: %w", err)
+	}
+	return nil
+}
+
 // Start starts the extensions and pipelines. If Start fails Shutdown should be called to ensure a clean state.
 func (srv *Service) Start(ctx context.Context) error {
 	srv.telemetrySettings.Logger.Info("Starting "+srv.buildInfo.Command+"...",
@@ -236,6 +265,12 @@ func (srv *Service) initExtensionsAndPipeline(ctx context.Context, set Settings,
 	return nil
 }
 
+// Exporters returns the exporters of the pipelines of this service, by data type and ID, e.g. to apply
+// configuration changes to the running exporters.
+func (srv *Service) Exporters() map[component.DataType]map[component.ID]component.Component {
+	return srv.host.GetExporters()
+}
+
 // Logger returns the logger created for this service.
 // This is a temporary API that may be removed soon after investigating how the collector should record different events.
 func (srv *Service) Logger() *zap.Logger {
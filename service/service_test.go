@@ -222,6 +222,7 @@ func TestServiceGetExporters(t *testing.T) {
 	})
 
 	expMap := srv.host.GetExporters()
+	assert.Equal(t, expMap, srv.Exporters())
 	assert.Len(t, expMap, 3)
 	assert.Len(t, expMap[component.DataTypeTraces], 1)
 	assert.Contains(t, expMap[component.DataTypeTraces], component.NewID("nop"))
@@ -246,6 +247,18 @@ func TestServiceTelemetryCleanupOnError(t *testing.T) {
 	assert.NoError(t, srv.Shutdown(context.Background()))
 }
 
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(context.Background(), newNopSettings(), newNopConfig()))
+
+	invalidCfg := newNopConfig()
+	invalidCfg.Pipelines[component.NewID("traces")].Processors[0] = component.NewID("invalid")
+	assert.ErrorContains(t, Validate(context.Background(), newNopSettings(), invalidCfg), "failed to build pipelines")
+
+	invalidCfg = newNopConfig()
+	invalidCfg.Extensions = extensions.Config{component.NewID("invalid")}
+	assert.ErrorContains(t, Validate(context.Background(), newNopSettings(), invalidCfg), "failed to build extensions")
+}
+
 func TestServiceTelemetryWithOpenCensusMetrics(t *testing.T) {
 	for _, tc := range ownMetricsTestCases() {
 		t.Run(tc.name, func(t *testing.T) {
@@ -507,6 +520,8 @@ func assertZPages(t *testing.T, zpagesAddr string) {
 		"/debug/pipelinez",
 		"/debug/servicez",
 		"/debug/extensionz",
+		"/debug/scrapez",
+		"/debug/scrapez?zscrapereceivername=nop",
 	}
 
 	testZPagePathFn := func(t *testing.T, path string) {
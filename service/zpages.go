@@ -20,6 +20,7 @@ const (
 	zPipelinePath  = "pipelinez"
 	zExtensionPath = "extensionz"
 	zFeaturePath   = "featurez"
+	zScrapePath    = "scrapez"
 )
 
 var (
@@ -42,6 +43,7 @@ func (host *serviceHost) RegisterZPages(mux *http.ServeMux, pathPrefix string) {
 	mux.HandleFunc(path.Join(pathPrefix, zPipelinePath), host.pipelines.HandleZPages)
 	mux.HandleFunc(path.Join(pathPrefix, zExtensionPath), host.serviceExtensions.HandleZPages)
 	mux.HandleFunc(path.Join(pathPrefix, zFeaturePath), handleFeaturezRequest)
+	mux.HandleFunc(path.Join(pathPrefix, zScrapePath), host.pipelines.HandleScrapeZPages)
 }
 
 func (host *serviceHost) zPagesRequest(w http.ResponseWriter, _ *http.Request) {
@@ -64,6 +66,11 @@ func (host *serviceHost) zPagesRequest(w http.ResponseWriter, _ *http.Request) {
 		ComponentEndpoint: zFeaturePath,
 		Link:              true,
 	})
+	zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+		Name:              "Scrapers",
+		ComponentEndpoint: zScrapePath,
+		Link:              true,
+	})
 	zpages.WriteHTMLPageFooter(w)
 }
 